@@ -31,7 +31,48 @@ ALTER TABLE email_link_sign_ins
 
 CREATE TABLE IF NOT EXISTS sign_ins_per_ip (
        login_session_number  BIGINT DEFAULT 0 NOT NULL,
-       login_attempts        BIGINT DEFAULT 0 NOT NULL CONSTRAINT sign_ins_per_ip_login_attempts_count CHECK (login_attempts <= 10),
+       login_attempts        BIGINT DEFAULT 0 NOT NULL,
        ip                    TEXT NOT NULL,
        PRIMARY KEY (login_session_number, ip)
-);
\ No newline at end of file
+);
+-- The limits are configurable, per throttle, so they're enforced when upserting the attempts.
+ALTER TABLE sign_ins_per_ip DROP CONSTRAINT IF EXISTS sign_ins_per_ip_login_attempts_count;
+ALTER TABLE email_link_sign_ins
+    ADD COLUMN IF NOT EXISTS step_up_required_at timestamp,
+    ADD COLUMN IF NOT EXISTS step_up_completed_at timestamp;
+
+ALTER TABLE email_link_sign_ins
+    ADD COLUMN IF NOT EXISTS device_fingerprint_bound_at timestamp,
+    ADD COLUMN IF NOT EXISTS device_fingerprint TEXT;
+
+CREATE TABLE IF NOT EXISTS sign_in_unblock_audit (
+       unblocked_at     timestamp NOT NULL,
+       admin_user_id    TEXT NOT NULL,
+       email            TEXT NOT NULL DEFAULT '',
+       device_unique_id TEXT NOT NULL DEFAULT '',
+       ip               TEXT NOT NULL DEFAULT '',
+       reason           TEXT NOT NULL);
+CREATE INDEX IF NOT EXISTS sign_in_unblock_audit_unblocked_at_ix ON sign_in_unblock_audit (unblocked_at);
+
+ALTER TABLE email_link_sign_ins
+    ADD COLUMN IF NOT EXISTS confirmation_code_expires_at timestamp;
+
+CREATE TABLE IF NOT EXISTS account_metadata_claims_sync (
+       synced_at        timestamp,
+       retry_at         timestamp,
+       attempts         BIGINT DEFAULT 0 NOT NULL,
+       user_id          TEXT PRIMARY KEY,
+       synced_metadata  JSONB);
+
+CREATE TABLE IF NOT EXISTS firebase_migrations (
+       checked_at       timestamp NOT NULL,
+       migrated_at      timestamp,
+       user_id          TEXT PRIMARY KEY,
+       firebase_id      TEXT,
+       device_unique_id TEXT,
+       source           TEXT NOT NULL);
+
+CREATE TABLE IF NOT EXISTS used_login_sessions (
+       expires_at timestamp NOT NULL,
+       jti        TEXT PRIMARY KEY);
+CREATE INDEX IF NOT EXISTS used_login_sessions_expires_at_ix ON used_login_sessions (expires_at);
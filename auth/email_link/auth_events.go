@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// sendAuthEvent is best effort: the events are only for analytics, so failing to send them must never fail the login flow.
+func (c *client) sendAuthEvent(ctx context.Context, eventType users.AuthEventType, id *loginID, userID, clientIP string) {
+	event := &users.AuthEvent{
+		CreatedAt:      time.Now(),
+		Type:           eventType,
+		UserID:         userID,
+		Email:          id.Email,
+		DeviceUniqueID: id.DeviceUniqueID,
+		ClientIP:       clientIP,
+	}
+	if err := c.userModifier.SendAuthEvent(ctx, event); err != nil {
+		log.Error(errors.Wrapf(err, "failed to send auth event %#v", event))
+	}
+}
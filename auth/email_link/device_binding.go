@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func ContextWithDeviceFingerprint(ctx context.Context, fingerprint string) context.Context {
+	if fingerprint == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, deviceFingerprintCtxValueKey, fingerprint) //nolint:revive,staticcheck // Not an issue.
+}
+
+func deviceFingerprint(ctx context.Context) (fingerprint string) {
+	fingerprint, _ = ctx.Value(deviceFingerprintCtxValueKey).(string) //nolint:errcheck // Not needed.
+
+	return
+}
+
+// verifyDeviceFingerprint checks the fingerprint presented by the device against the one the tokens are bound to.
+// Nothing is bound yet (older sessions, or sign ins started without a fingerprint) means any fingerprint is accepted, and it gets bound.
+// In lenient mode, devices that don't present any fingerprint are accepted as well, in strict mode they're not.
+func (c *client) verifyDeviceFingerprint(bound, presented string) error {
+	if c.cfg.DeviceBinding.Mode == disabledDeviceBindingMode || bound == "" || bound == presented {
+		return nil
+	}
+	if presented == "" && c.cfg.DeviceBinding.Mode == lenientDeviceBindingMode {
+		return nil
+	}
+
+	return errors.Wrapf(ErrDeviceFingerprintMismatch, "presented device fingerprint `%v` does not match the bound one", presented)
+}
+
+func (c *client) boundDeviceFingerprint(fingerprint string) string {
+	if c.cfg.DeviceBinding.Mode == disabledDeviceBindingMode {
+		return ""
+	}
+
+	return fingerprint
+}
+
+// bindDeviceFingerprint binds the tokens to the presented fingerprint, on their first refresh, if they're not bound yet.
+func (c *client) bindDeviceFingerprint(ctx context.Context, id *loginID, bound string, now *time.Time) error {
+	presented := c.boundDeviceFingerprint(deviceFingerprint(ctx))
+	if bound != "" || presented == "" {
+		return nil
+	}
+	sql := `UPDATE email_link_sign_ins
+				SET device_fingerprint = $3,
+					device_fingerprint_bound_at = $4
+			WHERE email = $1
+				  AND device_unique_id = $2
+				  AND device_fingerprint IS NULL`
+	_, err := storage.Exec(ctx, c.db, sql, id.Email, id.DeviceUniqueID, presented, now.Time)
+
+	return errors.Wrapf(err, "failed to bind device fingerprint for id:%#v", id)
+}
@@ -34,7 +34,7 @@ func (c *client) handleEmailModification(ctx context.Context, els *emailLinkSign
 				}
 
 				return multierror.Append( //nolint:wrapcheck // .
-					errors.Wrapf(c.resetEmailModification(ctx, usr.ID, oldEmailVal), "[reset] resetEmailModification failed for email:%v", oldEmailVal),
+					errors.Wrapf(c.resetEmailModification(ctx, usr.ID, oldEmailVal), "[reset] resetEmailModification failed for email:%v", users.RedactedEmail(oldEmailVal)),
 					errors.Wrapf(fErr, "failed to change email in firebase to:%v fbUserID:%v", newEmail, firebaseID),
 				).ErrorOrNil()
 			}
@@ -42,13 +42,13 @@ func (c *client) handleEmailModification(ctx context.Context, els *emailLinkSign
 	}
 	if notifyEmail != "" {
 		resetEmailOTP, now := generateOTP(), time.Now()
-		resetConfirmationCode := generateConfirmationCode()
+		resetConfirmationCode := c.generateConfirmationCode()
 		uErr := c.upsertEmailLinkSignIn(ctx, oldEmail, els.DeviceUniqueID, resetEmailOTP, resetConfirmationCode, now)
 		if uErr != nil {
 			return multierror.Append( //nolint:wrapcheck // .
-				errors.Wrapf(c.resetEmailModification(ctx, usr.ID, oldEmail), "[reset] resetEmailModification failed for email:%v", oldEmail),
-				errors.Wrapf(c.resetFirebaseEmailModification(ctx, els.Metadata, oldEmail), "[reset] updateEmail in firebase failed for email:%v", oldEmail),
-				errors.Wrapf(uErr, "failed to store/update email confirmation for email:%v", oldEmail),
+				errors.Wrapf(c.resetEmailModification(ctx, usr.ID, oldEmail), "[reset] resetEmailModification failed for email:%v", users.RedactedEmail(oldEmail)),
+				errors.Wrapf(c.resetFirebaseEmailModification(ctx, els.Metadata, oldEmail), "[reset] updateEmail in firebase failed for email:%v", users.RedactedEmail(oldEmail)),
+				errors.Wrapf(uErr, "failed to store/update email confirmation for email:%v", users.RedactedEmail(oldEmail)),
 			).ErrorOrNil()
 		}
 		resetEmailPayload, rErr := c.generateMagicLinkPayload(
@@ -56,17 +56,17 @@ func (c *client) handleEmailModification(ctx context.Context, els *emailLinkSign
 			newEmail, "", resetEmailOTP, now)
 		if rErr != nil {
 			return multierror.Append( //nolint:wrapcheck // .
-				errors.Wrapf(c.resetEmailModification(ctx, usr.ID, oldEmail), "[reset] resetEmailModification failed for email:%v", oldEmail),
-				errors.Wrapf(c.resetFirebaseEmailModification(ctx, els.Metadata, oldEmail), "[reset] updateEmail in firebase failed for email:%v", oldEmail),
-				errors.Wrapf(rErr, "can't generate link payload for email: %v", oldEmail),
+				errors.Wrapf(c.resetEmailModification(ctx, usr.ID, oldEmail), "[reset] resetEmailModification failed for email:%v", users.RedactedEmail(oldEmail)),
+				errors.Wrapf(c.resetFirebaseEmailModification(ctx, els.Metadata, oldEmail), "[reset] updateEmail in firebase failed for email:%v", users.RedactedEmail(oldEmail)),
+				errors.Wrapf(rErr, "can't generate link payload for email: %v", users.RedactedEmail(oldEmail)),
 			).ErrorOrNil()
 		}
 		authLink := c.getResetAuthLink(resetEmailPayload, els.Language, resetConfirmationCode)
 		if sErr := c.sendNotifyEmailChanged(ctx, notifyEmail, newEmail, authLink, els.Language); sErr != nil {
 			return multierror.Append( //nolint:wrapcheck // .
-				errors.Wrapf(c.resetEmailModification(ctx, usr.ID, oldEmail), "[reset] resetEmailModification failed for email:%v", oldEmail),
-				errors.Wrapf(c.resetFirebaseEmailModification(ctx, els.Metadata, oldEmail), "[reset] updateEmail in firebase failed for email:%v", oldEmail),
-				errors.Wrapf(sErr, "failed to send notification email about email change for userID %v email %v", els.UserID, oldEmail),
+				errors.Wrapf(c.resetEmailModification(ctx, usr.ID, oldEmail), "[reset] resetEmailModification failed for email:%v", users.RedactedEmail(oldEmail)),
+				errors.Wrapf(c.resetFirebaseEmailModification(ctx, els.Metadata, oldEmail), "[reset] updateEmail in firebase failed for email:%v", users.RedactedEmail(oldEmail)),
+				errors.Wrapf(sErr, "failed to send notification email about email change for userID %v email %v", els.UserID, users.RedactedEmail(oldEmail)),
 			).ErrorOrNil()
 		}
 	}
@@ -130,5 +130,5 @@ func (c *client) sendNotifyEmailChanged(ctx context.Context, notifyEmail, newEma
 	}, email.Participant{
 		Name:  "",
 		Email: notifyEmail,
-	}), "failed to send notify email changed for user with email:%v", notifyEmail)
+	}), "failed to send notify email changed for user with email:%v", users.RedactedEmail(notifyEmail))
 }
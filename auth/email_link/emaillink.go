@@ -14,6 +14,7 @@ import (
 	stdlibtime "time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
 	"github.com/ice-blockchain/wintr/auth"
@@ -31,7 +32,7 @@ func init() {
 
 func NewClient(ctx context.Context, userModifier UserModifier, authClient auth.Client) Client {
 	cfg := loadConfiguration()
-	cfg.validate()
+	log.Panic(cfg.validate()) //nolint:revive // That's intended.
 	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
 
 	cl := &client{
@@ -45,19 +46,33 @@ func NewClient(ctx context.Context, userModifier UserModifier, authClient auth.C
 		cl.emailClient = email.New(applicationYamlKey)
 	}
 	go cl.startOldLoginAttemptsCleaner(ctx)
+	if cfg.MetadataClaimsSync.Interval > 0 {
+		go cl.startMetadataClaimsSyncer(ctx)
+	}
+	if cfg.FirebaseMigration.Interval > 0 {
+		go cl.startFirebaseMigrator(ctx)
+	}
 
 	return cl
 }
 
 func NewROClient(ctx context.Context) IceUserIDClient {
+	cfg := loadConfiguration()
+	log.Panic(cfg.validate()) //nolint:revive // That's intended.
 	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
 
 	return &client{
 		shutdown: db.Close,
 		db:       db,
+		cfg:      cfg,
 	}
 }
 
+// ValidateConfig reports, at once, all the problems of the config needed by NewClient.
+func ValidateConfig() error {
+	return loadConfiguration().validate()
+}
+
 func (c *client) Close() error {
 	return errors.Wrap(c.shutdown(), "closing auth/emaillink repository failed")
 }
@@ -107,28 +122,126 @@ func loadLoginSessionConfiguration(cfg *config) {
 	}
 }
 
-func (cfg *config) validate() {
+// validate reports all the problems at once. It also applies the defaults, so it must be called before using the config.
+func (cfg *config) validate() error {
+	var mErr *multierror.Error
 	if cfg.EmailValidation.JwtSecret == "" {
-		log.Panic(errors.New("no email jwt secret provided"))
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.emailValidation.jwtSecret` (or the EMAIL_JWT_SECRET/JWT_SECRET env variables) is missing", applicationYamlKey))
 	}
-	if cfg.LoginSession.JwtSecret == "" {
-		log.Panic(errors.New("no login session jwt secret provided"))
+	if cfg.LoginSession.JwtSecret == "" && len(cfg.LoginSession.SigningKeys) == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.loginSession.jwtSecret` (or the LOGIN_JWT_SECRET/JWT_SECRET env variables) or `%v.loginSession.signingKeys` is missing", applicationYamlKey, applicationYamlKey)) //nolint:lll // .
 	}
+	mErr = multierror.Append(mErr, cfg.validateSigningKeys()...)
 	if cfg.EmailValidation.AuthLink == "" {
-		log.Panic("no auth link provided")
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.emailValidation.authLink` is missing", applicationYamlKey))
 	}
 	if cfg.FromEmailAddress == "" {
-		log.Panic("no from email address provided")
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.fromEmailAddress` is missing", applicationYamlKey))
 	}
 	if cfg.FromEmailName == "" {
-		log.Panic("no from email name provided")
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.fromEmailName` is missing", applicationYamlKey))
 	}
 	if cfg.EmailValidation.ExpirationTime == 0 {
-		log.Panic("no expiration time provided for email validation")
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.emailValidation.expirationTime` is missing", applicationYamlKey))
 	}
 	if cfg.ConfirmationCode.MaxWrongAttemptsCount == 0 {
-		log.Panic("no max wrong attempts count provided for confirmation code")
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.confirmationCode.maxWrongAttemptsCount` is missing", applicationYamlKey))
+	}
+	mErr = multierror.Append(mErr, cfg.validateConfirmationCode()...)
+	mErr = multierror.Append(mErr, cfg.validateSignInThrottling()...)
+	if cfg.MetadataClaimsSync.Interval < 0 || cfg.MetadataClaimsSync.MaxBackoff < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.metadataClaimsSync.interval` and `%v.metadataClaimsSync.maxBackoff` can't be negative", applicationYamlKey, applicationYamlKey)) //nolint:lll // .
+	}
+	if cfg.MetadataClaimsSync.MaxBackoff < cfg.MetadataClaimsSync.Interval {
+		cfg.MetadataClaimsSync.MaxBackoff = cfg.MetadataClaimsSync.Interval
+	}
+	if cfg.MetadataClaimsSync.BatchSize == 0 {
+		cfg.MetadataClaimsSync.BatchSize = defaultMetadataClaimsSyncBatchSize
+	}
+	if cfg.FirebaseMigration.Interval < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.firebaseMigration.interval` can't be negative", applicationYamlKey))
+	}
+	if cfg.FirebaseMigration.BatchSize == 0 {
+		cfg.FirebaseMigration.BatchSize = defaultFirebaseMigrationBatchSize
+	}
+	switch cfg.DeviceBinding.Mode {
+	case "":
+		cfg.DeviceBinding.Mode = disabledDeviceBindingMode
+	case disabledDeviceBindingMode, lenientDeviceBindingMode, strictDeviceBindingMode:
+	default:
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.deviceBinding.mode` is invalid: `%v`", applicationYamlKey, cfg.DeviceBinding.Mode))
+	}
+	if cfg.SignInStepUp.Enabled && cfg.SignInStepUp.LivenessVerificationURL == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.signInStepUp.livenessVerificationUrl` is missing, but sign in step up is enabled", applicationYamlKey))
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+}
+
+// validateConfirmationCode applies the presets of the confirmation code mode, for whatever is not explicitly configured.
+func (cfg *config) validateConfirmationCode() []error {
+	var errs []error
+	code := &cfg.ConfirmationCode
+	switch code.Mode {
+	case "", defaultConfirmationCodeMode:
+		code.Mode = defaultConfirmationCodeMode
+		if code.Length == 0 {
+			code.Length = defaultConfirmationCodeLength
+		}
+	case professionalConfirmationCodeMode:
+		if code.Length == 0 {
+			code.Length = professionalConfirmationCodeLength
+		}
+	default:
+		errs = append(errs, errors.Errorf("`%v.confirmationCode.mode` is invalid: `%v`", applicationYamlKey, code.Mode))
+	}
+	if code.Alphabet == "" {
+		code.Alphabet = digitsConfirmationCodeAlphabet
+	}
+	if _, found := confirmationCodeCharacters[code.Alphabet]; !found {
+		errs = append(errs, errors.Errorf("`%v.confirmationCode.alphabet` is invalid: `%v`", applicationYamlKey, code.Alphabet))
+	}
+	if code.Mode == defaultConfirmationCodeMode || code.Mode == professionalConfirmationCodeMode {
+		if code.Length < minConfirmationCodeLength || code.Length > maxConfirmationCodeLength {
+			errs = append(errs, errors.Errorf("`%v.confirmationCode.length` must be between %v and %v", applicationYamlKey, minConfirmationCodeLength, maxConfirmationCodeLength))
+		}
+	}
+	if code.TTL == 0 || code.TTL > cfg.EmailValidation.ExpirationTime {
+		code.TTL = cfg.EmailValidation.ExpirationTime
+	}
+
+	return errs
+}
+
+// validateSignInThrottling defaults the per IP throttle to what used to be hardcoded and validates the windows of the enabled throttles.
+func (cfg *config) validateSignInThrottling() []error {
+	if cfg.EmailValidation.SameIPRateCheckPeriod == 0 {
+		cfg.EmailValidation.SameIPRateCheckPeriod = defaultSameIPRateCheckPeriod
+	}
+	if cfg.EmailValidation.MaxRequestsFromIP == 0 {
+		cfg.EmailValidation.MaxRequestsFromIP = defaultMaxRequestsFromIP
+	}
+	var errs []error
+	if cfg.EmailValidation.SameIPRateCheckPeriod < stdlibtime.Second {
+		errs = append(errs, errors.Errorf("`%v.emailValidation.sameIpRateCheckPeriod` must be at least 1s", applicationYamlKey))
+	}
+	if cfg.EmailValidation.MaxRequestsFromIP < 0 {
+		errs = append(errs, errors.Errorf("`%v.emailValidation.maxRequestsFromIP` can't be negative", applicationYamlKey))
+	}
+	for name, throttle := range map[string]*signInThrottle{
+		"perEmail":  &cfg.SignInThrottling.PerEmail,
+		"perDevice": &cfg.SignInThrottling.PerDevice,
+		"global":    &cfg.SignInThrottling.Global,
+	} {
+		if throttle.MaxAttempts < 0 {
+			errs = append(errs, errors.Errorf("`%v.signInThrottling.%v.maxAttempts` can't be negative", applicationYamlKey, name))
+		}
+		if throttle.MaxAttempts > 0 && throttle.Window < stdlibtime.Second {
+			errs = append(errs, errors.Errorf("`%v.signInThrottling.%v.window` must be at least 1s, if it's enabled", applicationYamlKey, name))
+		}
 	}
+
+	return errs
 }
 
 func (t *emailTemplate) getSubject(data any) string {
@@ -201,15 +314,22 @@ func loadEmailMagicLinkTranslationTemplates() { //nolint:funlen,gocognit,revive
 }
 
 func parseJwtToken(jwtToken, secret string, res jwt.Claims) error {
-	if _, err := jwt.ParseWithClaims(jwtToken, res, func(token *jwt.Token) (any, error) {
+	return parseJwtTokenWithKey(jwtToken, res, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || token.Method.Alg() != jwt.SigningMethodHS256.Name {
 			return nil, errors.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
+
+		return []byte(secret), nil
+	})
+}
+
+func parseJwtTokenWithKey(jwtToken string, res jwt.Claims, keyFunc jwt.Keyfunc) error {
+	if _, err := jwt.ParseWithClaims(jwtToken, res, func(token *jwt.Token) (any, error) {
 		if iss, err := token.Claims.GetIssuer(); err != nil || iss != jwtIssuer {
 			return nil, errors.Wrapf(ErrInvalidToken, "invalid issuer:%v", iss)
 		}
 
-		return []byte(secret), nil
+		return keyFunc(token)
 	}); err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) || errors.Is(err, jwt.ErrTokenNotValidYet) {
 			return errors.Wrapf(ErrExpiredToken, "expired or not valid yet token:%v", jwtToken)
@@ -225,13 +345,16 @@ func (c *client) deleteOldLoginAttempts(ctx context.Context) error {
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "[deleteOldLoginAttempts] unexpected deadline")
 	}
-	prevDaySessionNumber := time.Now().Add(-24*stdlibtime.Hour).Unix() / int64(sameIPCheckRate.Seconds())
-	sql := `DELETE FROM sign_ins_per_ip WHERE login_session_number < $1`
-	if _, err := storage.Exec(ctx, c.db, sql, prevDaySessionNumber); err != nil {
+	prevDaySessionNumber := time.Now().Add(-24*stdlibtime.Hour).Unix() / int64(c.cfg.EmailValidation.SameIPRateCheckPeriod.Seconds())
+	sql := `DELETE FROM sign_ins_per_ip WHERE login_session_number < $1 AND strpos(ip, $2) = 0`
+	if _, err := storage.Exec(ctx, c.db, sql, prevDaySessionNumber, signInThrottleKeySeparator); err != nil {
 		return errors.Wrap(err, "failed to delete old data from sign_ins_per_ip")
 	}
+	if _, err := storage.Exec(ctx, c.db, `DELETE FROM used_login_sessions WHERE expires_at < $1`, time.Now().Time); err != nil {
+		return errors.Wrap(err, "failed to delete expired used_login_sessions")
+	}
 
-	return nil
+	return errors.Wrap(c.deleteOldSignInThrottleAttempts(ctx), "failed to deleteOldSignInThrottleAttempts")
 }
 
 func (c *client) startOldLoginAttemptsCleaner(ctx context.Context) {
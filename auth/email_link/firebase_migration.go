@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+	stdlibtime "time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/auth"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (c *client) GetFirebaseMigration(ctx context.Context, userID string) (*FirebaseMigration, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT m.migrated_at,
+				   u.id 														  AS user_id,
+				   COALESCE(m.firebase_id, md.metadata ->> $2, '') 				  AS firebase_id,
+				   COALESCE(md.metadata ->> $3, '') 							  AS registered_with_provider,
+				   (CASE WHEN starts_with(u.id, $4) THEN $5
+				   		 WHEN m.migrated_at IS NOT NULL THEN m.source
+				   		 ELSE '' END) 											  AS source,
+				   (CASE WHEN m.migrated_at IS NOT NULL THEN COALESCE(m.device_unique_id, '') ELSE '' END) AS device_unique_id,
+				   (starts_with(u.id, $4) OR m.migrated_at IS NOT NULL) 		  AS migrated
+			FROM users u
+				LEFT JOIN firebase_migrations m ON m.user_id = u.id
+				LEFT JOIN account_metadata md ON md.user_id = u.id
+			WHERE u.id = $1`
+	res, err := storage.Get[FirebaseMigration](ctx, c.db, sql,
+		userID, auth.FirebaseIDClaim, auth.RegisteredWithProviderClaim, iceIDPrefix, nativeFirebaseMigrationSource)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return nil, errors.Wrapf(ErrUserNotFound, "user %v not found", userID)
+		}
+
+		return nil, errors.Wrapf(err, "failed to get the firebase migration of userID:%v", userID)
+	}
+
+	return res, nil
+}
+
+func (c *client) startFirebaseMigrator(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(c.cfg.FirebaseMigration.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 5 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(c.migrateFirebaseUsers(reqCtx), "failed to migrateFirebaseUsers"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// migrateFirebaseUsers migrates a batch of the users, not registered with ice, that weren't checked yet.
+// Every one of them is checked only once, whether it's migrated or not; the ones that aren't can still be migrated by signing in.
+func (c *client) migrateFirebaseUsers(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sql := `SELECT u.id,
+				   u.email
+			FROM users u
+				LEFT JOIN firebase_migrations m ON m.user_id = u.id
+			WHERE m.user_id IS NULL
+				  AND NOT starts_with(u.id, $1)
+			LIMIT $2`
+	candidates, err := storage.Select[firebaseMigrationCandidate](ctx, c.db, sql, iceIDPrefix, c.cfg.FirebaseMigration.BatchSize)
+	if err != nil {
+		return errors.Wrap(err, "failed to select the users to migrate from firebase")
+	}
+	var mErr *multierror.Error
+	for _, candidate := range candidates {
+		candidate.Email = c.openEmail(candidate.Email)
+		mErr = multierror.Append(mErr, errors.Wrapf(c.migrateFirebaseUser(ctx, candidate), "failed to migrate userID:%v", candidate.ID))
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // Not needed.
+}
+
+// migrateFirebaseUser migrates the user, if it's found in Firebase by its email, like signing in with an email link would,
+// setting the claims that bind its Firebase and ice IDs, without overriding the ones it already has.
+func (c *client) migrateFirebaseUser(ctx context.Context, candidate *firebaseMigrationCandidate) error {
+	var firebaseID string
+	// The users without an email have their ID in its place.
+	if candidate.Email != "" && candidate.Email != candidate.ID {
+		var err error
+		if firebaseID, err = c.authClient.GetUserUIDByEmail(ctx, candidate.Email); err != nil {
+			return errors.Wrapf(err, "failed to get the firebase user of email:%v", users.RedactedEmail(candidate.Email))
+		}
+	}
+	now := time.Now()
+	if firebaseID != candidate.ID {
+		sql := `INSERT INTO firebase_migrations(checked_at, user_id, source) VALUES ($1, $2, $3) ON CONFLICT(user_id) DO NOTHING`
+		_, err := storage.Exec(ctx, c.db, sql, now.Time, candidate.ID, batchFirebaseMigrationSource)
+
+		return errors.Wrapf(err, "failed to mark userID:%v as checked", candidate.ID)
+	}
+	md := users.JSON(map[string]any{
+		auth.IceIDClaim:                  candidate.ID,
+		auth.FirebaseIDClaim:             firebaseID,
+		auth.RegisteredWithProviderClaim: auth.ProviderFirebase,
+	})
+	sql := `with metadata_update as (
+				INSERT INTO account_metadata(user_id, metadata)
+				VALUES ($2, $4::jsonb) ON CONFLICT(user_id) DO UPDATE
+					SET metadata = EXCLUDED.metadata || account_metadata.metadata
+			)
+			INSERT INTO firebase_migrations(checked_at, migrated_at, user_id, firebase_id, source)
+			VALUES ($1, $1, $2, $3, $5) ON CONFLICT(user_id) DO NOTHING`
+	_, err := storage.Exec(ctx, c.db, sql, now.Time, candidate.ID, firebaseID, md, batchFirebaseMigrationSource)
+
+	return errors.Wrapf(err, "failed to migrate userID:%v", candidate.ID)
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/auth"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (c *client) SignInAsGuest(ctx context.Context, deviceUniqueID, clientIP string) (*Tokens, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	if !c.cfg.GuestAccounts.Enabled {
+		return nil, ErrGuestAccountsDisabled
+	}
+	userID := users.GuestUserIDPrefix + uuid.NewString()
+	// Guests have no email, so their sign ins are keyed by their ID instead, like the emails of the accounts without one.
+	id := loginID{Email: userID, DeviceUniqueID: deviceUniqueID}
+	md := users.JSON(map[string]any{auth.IceIDClaim: userID, "role": GuestRole})
+	now := time.Now()
+	sql := `WITH metadata_insert AS (
+				INSERT INTO account_metadata (user_id, metadata) VALUES ($3, $5::jsonb)
+			)
+			INSERT INTO email_link_sign_ins (created_at, token_issued_at, issued_token_seq, previously_issued_token_seq, email, otp, user_id, device_unique_id)
+			VALUES ($1, $1, 1, 1, $2, $3, $3, $4)`
+	if _, err := storage.Exec(ctx, c.db, sql, now.Time, id.Email, userID, id.DeviceUniqueID, md); err != nil {
+		return nil, errors.Wrapf(err, "failed to insert the guest sign in for:%#v", id)
+	}
+	usr, err := c.getUserByIDOrPk(ctx, userID, &id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get guest user by userID:%v", userID)
+	}
+	tokens, err := c.generateTokens(now, usr, usr.IssuedTokenSeq)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't generate tokens for guest userID:%v", userID)
+	}
+	c.sendAuthEvent(ctx, users.GuestSignedInAuthEventType, &id, userID, clientIP)
+
+	return tokens, nil
+}
+
+func (c *client) GuestUserID(ctx context.Context, guestToken string) (string, error) {
+	if ctx.Err() != nil {
+		return "", errors.Wrap(ctx.Err(), "context failed")
+	}
+	token, err := c.authClient.ParseToken(guestToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrExpiredToken) {
+			return "", errors.Wrap(ErrExpiredToken, "guest token expired")
+		}
+
+		return "", errors.Wrapf(ErrInvalidToken, "failed to verify guest token: %v", err)
+	}
+	if token.Role != GuestRole || !strings.HasPrefix(token.Subject, users.GuestUserIDPrefix) {
+		return "", errors.Wrapf(ErrInvalidToken, "token of userID:%v is not a guest one", token.Subject)
+	}
+
+	return token.Subject, nil
+}
@@ -29,11 +29,16 @@ func (c *client) SendSignInLinkToEmail(ctx context.Context, emailValue, deviceUn
 	}
 	id := loginID{emailValue, deviceUniqueID}
 	now := time.Now()
-	loginSessionNumber := now.Time.Unix() / int64(sameIPCheckRate.Seconds())
+	loginSessionNumber := now.Time.Unix() / int64(c.cfg.EmailValidation.SameIPRateCheckPeriod.Seconds())
+	oldEmail := users.ConfirmedEmail(ctx)
+	if oldEmail == "" && userIDForPhoneNumberToEmailMigration(ctx) == "" {
+		if id.Email, err = c.primaryEmail(ctx, emailValue); err != nil {
+			return "", errors.Wrapf(err, "can't resolve the primary email for:%v", emailValue)
+		}
+	}
 	if vErr := c.validateEmailSignIn(ctx, &id); vErr != nil {
 		return "", errors.Wrapf(vErr, "can't validate email sign in for:%#v", id)
 	}
-	oldEmail := users.ConfirmedEmail(ctx)
 	if oldEmail != "" {
 		loginSessionNumber = 0
 		clientIP = "" //nolint:revive // .
@@ -42,15 +47,16 @@ func (c *client) SendSignInLinkToEmail(ctx context.Context, emailValue, deviceUn
 			return "", errors.Wrapf(vErr, "can't validate modification email for:%#v", oldID)
 		}
 	}
+	c.sendAuthEvent(ctx, users.LoginRequestedAuthEventType, &id, "", clientIP)
 	otp := generateOTP()
-	confirmationCode := generateConfirmationCode()
-	loginSession, err = c.generateLoginSession(&id, confirmationCode, clientIP, loginSessionNumber)
+	confirmationCode := c.generateConfirmationCode()
+	loginSession, err = c.generateLoginSession(&id, confirmationCode, clientIP, deviceFingerprint(ctx), loginSessionNumber)
 	if err != nil {
 		return "", errors.Wrap(err, "can't call generateLoginSession")
 	}
-	if loginSessionNumber > 0 && clientIP != "" && userIDForPhoneNumberToEmailMigration(ctx) == "" {
-		if ipErr := c.upsertIPLoginAttempt(ctx, &id, clientIP, loginSessionNumber); ipErr != nil {
-			return "", errors.Wrapf(ipErr, "failed increment login attempts for IP:%v (session num %v)", clientIP, loginSessionNumber)
+	if loginSessionNumber > 0 && userIDForPhoneNumberToEmailMigration(ctx) == "" {
+		if tErr := c.throttleSignIn(ctx, &id, clientIP, now); tErr != nil {
+			return "", errors.Wrapf(tErr, "failed to throttle sign in for IP:%v (session num %v)", clientIP, loginSessionNumber)
 		}
 	}
 	if uErr := c.upsertEmailLinkSignIn(ctx, id.Email, id.DeviceUniqueID, otp, confirmationCode, now); uErr != nil {
@@ -78,12 +84,13 @@ func (c *client) SendSignInLinkToEmail(ctx context.Context, emailValue, deviceUn
 			errors.Wrapf(pErr, "can't generate magic link payload for id: %#v", id),
 		).ErrorOrNil()
 	}
-	if sErr := c.sendMagicLink(ctx, &id, oldEmail, payload, language); sErr != nil {
+	if sErr := c.sendMagicLink(ctx, &id, emailValue, oldEmail, payload, language); sErr != nil {
 		return "", multierror.Append( //nolint:wrapcheck // .
 			errors.Wrapf(c.decrementIPLoginAttempts(ctx, clientIP, loginSessionNumber), "[rollback] failed to rollback login attempts for ip"),
 			errors.Wrapf(sErr, "can't send magic link for id:%#v", id),
 		).ErrorOrNil()
 	}
+	c.sendAuthEvent(ctx, users.CodeSentAuthEventType, &id, "", clientIP)
 
 	return loginSession, nil
 }
@@ -96,7 +103,7 @@ func (c *client) restoreOldLoginSession(ctx context.Context, id *loginID, client
 			errors.Wrapf(dErr, "can't get email link sign in information by:%#v", id),
 		).ErrorOrNil()
 	}
-	oldLoginSession, dErr := c.generateLoginSession(id, existingSignIn.ConfirmationCode, clientIP, loginSessionNumber)
+	oldLoginSession, dErr := c.generateLoginSession(id, existingSignIn.ConfirmationCode, clientIP, deviceFingerprint(ctx), loginSessionNumber)
 	if dErr != nil {
 		return "", multierror.Append( //nolint:wrapcheck // .
 			errors.Wrapf(c.decrementIPLoginAttempts(ctx, clientIP, loginSessionNumber), "[rollback] failed to rollback login attempts for ip"),
@@ -143,7 +150,7 @@ func (c *client) decrementIPLoginAttempts(ctx context.Context, ip string, loginS
 func (c *client) validateEmailModification(ctx context.Context, newEmail string, oldID *loginID) error {
 	if iErr := c.isUserExist(ctx, newEmail); !storage.IsErr(iErr, storage.ErrNotFound) {
 		if iErr != nil {
-			return errors.Wrapf(iErr, "can't check if user exists for email:%v", newEmail)
+			return errors.Wrapf(iErr, "can't check if user exists for email:%v", users.RedactedEmail(newEmail))
 		}
 
 		return errors.Wrapf(terror.New(ErrUserDuplicate, map[string]any{"field": "email"}), "user with such email already exists:%v", newEmail)
@@ -164,7 +171,8 @@ func (c *client) validateEmailModification(ctx context.Context, newEmail string,
 	return nil
 }
 
-func (c *client) sendMagicLink(ctx context.Context, id *loginID, oldEmail, payload, language string) error {
+// sendMagicLink sends the link to toEmail, which differs from the email of the id when signing in with a secondary email.
+func (c *client) sendMagicLink(ctx context.Context, id *loginID, toEmail, oldEmail, payload, language string) error { //nolint:revive // .
 	authLink := c.getAuthLink(payload, language)
 	var emailType string
 	if oldEmail != "" {
@@ -172,23 +180,23 @@ func (c *client) sendMagicLink(ctx context.Context, id *loginID, oldEmail, paylo
 	} else {
 		emailType = signInEmailType
 	}
+	data := struct {
+		Email string
+		Link  string
+	}{
+		Email: toEmail,
+		Link:  authLink,
+	}
 
-	return errors.Wrapf(c.sendEmailWithType(ctx, emailType, id.Email, language, authLink), "failed to send validation email for id:%#v", id)
+	return errors.Wrapf(c.sendEmailWithType(ctx, emailType, toEmail, language, data), "failed to send validation email for id:%#v", id)
 }
 
-func (c *client) sendEmailWithType(ctx context.Context, emailType, toEmail, language, link string) error {
+func (c *client) sendEmailWithType(ctx context.Context, emailType, toEmail, language string, data any) error {
 	var tmpl *emailTemplate
 	tmpl, ok := allEmailLinkTemplates[emailType][language]
 	if !ok {
 		tmpl = allEmailLinkTemplates[emailType][defaultLanguage]
 	}
-	data := struct {
-		Email string
-		Link  string
-	}{
-		Email: toEmail,
-		Link:  link,
-	}
 
 	return errors.Wrapf(c.emailClient.Send(ctx, &email.Parcel{
 		Body: &email.Body{
@@ -203,13 +211,16 @@ func (c *client) sendEmailWithType(ctx context.Context, emailType, toEmail, lang
 	}, email.Participant{
 		Name:  "",
 		Email: toEmail,
-	}), "failed to send email with type:%v for user with email:%v", emailType, toEmail)
+	}), "failed to send email with type:%v for user with email:%v", emailType, users.RedactedEmail(toEmail))
 }
 
 //nolint:revive,lll // .
 func (c *client) upsertEmailLinkSignIn(ctx context.Context, toEmail, deviceUniqueID, otp, code string, now *time.Time) error {
 	confirmationCodeWrongAttempts := 0
-	params := []any{now.Time, toEmail, deviceUniqueID, otp, code, confirmationCodeWrongAttempts, userIDForPhoneNumberToEmailMigration(ctx)}
+	params := []any{
+		now.Time, toEmail, deviceUniqueID, otp, code, confirmationCodeWrongAttempts, userIDForPhoneNumberToEmailMigration(ctx),
+		now.Add(c.cfg.ConfirmationCode.TTL),
+	}
 	sql := fmt.Sprintf(`INSERT INTO email_link_sign_ins (
 							created_at,
 							email,
@@ -217,16 +228,20 @@ func (c *client) upsertEmailLinkSignIn(ctx context.Context, toEmail, deviceUniqu
 							otp,
 							confirmation_code,
 							confirmation_code_wrong_attempts_count,
-							phone_number_to_email_migration_user_id)
-						VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7,''))
+							phone_number_to_email_migration_user_id,
+							confirmation_code_expires_at)
+						VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7,''), $8)
 						ON CONFLICT (email, device_unique_id) DO UPDATE 
 							SET otp           				     	   = EXCLUDED.otp, 
 								created_at    				     	   = EXCLUDED.created_at,
 								confirmation_code 		          	   = EXCLUDED.confirmation_code,
+								confirmation_code_expires_at 		   = EXCLUDED.confirmation_code_expires_at,
 								confirmation_code_wrong_attempts_count = EXCLUDED.confirmation_code_wrong_attempts_count,
 								phone_number_to_email_migration_user_id = COALESCE(NULLIF(EXCLUDED.phone_number_to_email_migration_user_id,''),email_link_sign_ins.phone_number_to_email_migration_user_id),
 						        email_confirmed_at                     = null,
-						        user_id                                = null
+						        user_id                                = null,
+						        step_up_required_at                    = null,
+						        step_up_completed_at                   = null
 						WHERE   (extract(epoch from email_link_sign_ins.created_at)::bigint/%[1]v)  != (extract(epoch from EXCLUDED.created_at::timestamp)::bigint/%[1]v)
 						   AND (email_link_sign_ins.otp                                             != EXCLUDED.otp
 						   OR   email_link_sign_ins.confirmation_code 		          	            != EXCLUDED.confirmation_code
@@ -234,29 +249,10 @@ func (c *client) upsertEmailLinkSignIn(ctx context.Context, toEmail, deviceUniqu
 		uint64(duplicatedSignInRequestsInLessThan/stdlibtime.Second))
 	rowsInserted, err := storage.Exec(ctx, c.db, sql, params...)
 	if rowsInserted == 0 && err == nil {
-		err = errors.Wrapf(ErrUserDuplicate, "duplicated signIn request for email %v,device %v", toEmail, deviceUniqueID)
+		err = errors.Wrapf(ErrUserDuplicate, "duplicated signIn request for email %v,device %v", users.RedactedEmail(toEmail), deviceUniqueID)
 	}
 
-	return errors.Wrapf(err, "failed to insert/update email link sign ins record for email:%v", toEmail)
-}
-
-func (c *client) upsertIPLoginAttempt(ctx context.Context, id *loginID, clientIP string, loginSessionNumber int64) error {
-	sql := `INSERT INTO sign_ins_per_ip (ip, login_session_number, login_attempts)
-					VALUES ($1, $2, 1)
-	ON CONFLICT (login_session_number, ip) DO UPDATE
-		SET login_attempts = sign_ins_per_ip.login_attempts + 1`
-	_, err := storage.Exec(ctx, c.db, sql, clientIP, loginSessionNumber)
-	if err != nil {
-		if storage.IsErr(err, storage.ErrCheckFailed) {
-			err = errors.Wrapf(ErrTooManyAttempts, "user %#v is blocked due to a lot of requests from IP %v", id, clientIP)
-
-			return terror.New(err, map[string]any{"ip": clientIP})
-		}
-
-		return errors.Wrapf(err, "failed to increment login attempts from IP %v", clientIP)
-	}
-
-	return nil
+	return errors.Wrapf(err, "failed to insert/update email link sign ins record for email:%v", users.RedactedEmail(toEmail))
 }
 
 func (c *client) generateMagicLinkPayload(id *loginID, oldEmail, notifyEmail, otp string, now *time.Time) (string, error) {
@@ -286,9 +282,10 @@ func (c *client) getAuthLink(token, language string) string {
 	return fmt.Sprintf("%s?token=%s&lang=%s", c.cfg.EmailValidation.AuthLink, token, language)
 }
 
-func (c *client) generateLoginSession(id *loginID, confirmationCode, clientIP string, loginSessionNumber int64) (string, error) {
+//nolint:revive // .
+func (c *client) generateLoginSession(id *loginID, confirmationCode, clientIP, fingerprint string, loginSessionNumber int64) (string, error) {
 	now := time.Now()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, loginFlowToken{
+	payload, err := c.signLoginSession(now, &loginFlowToken{
 		RegisteredClaims: &jwt.RegisteredClaims{
 			Issuer:    jwtIssuer,
 			Subject:   id.Email,
@@ -296,13 +293,14 @@ func (c *client) generateLoginSession(id *loginID, confirmationCode, clientIP st
 			ExpiresAt: jwt.NewNumericDate(now.Add(c.cfg.EmailValidation.ExpirationTime)),
 			NotBefore: jwt.NewNumericDate(*now.Time),
 			IssuedAt:  jwt.NewNumericDate(*now.Time),
+			ID:        uuid.NewString(),
 		},
 		DeviceUniqueID:     id.DeviceUniqueID,
 		ConfirmationCode:   confirmationCode,
 		LoginSessionNumber: loginSessionNumber,
 		ClientIP:           clientIP,
+		DeviceFingerprint:  fingerprint,
 	})
-	payload, err := token.SignedString([]byte(c.cfg.LoginSession.JwtSecret))
 	if err != nil {
 		return "", errors.Wrapf(err, "can't generate login flow for id:%#v,now:%v", id, now)
 	}
@@ -314,9 +312,14 @@ func generateOTP() string {
 	return uuid.NewString()
 }
 
-func generateConfirmationCode() string {
-	result, err := rand.Int(rand.Reader, big.NewInt(999)) //nolint:gomnd // It's max value.
-	log.Panic(err, "random wrong")
+func (c *client) generateConfirmationCode() string {
+	characters := confirmationCodeCharacters[c.cfg.ConfirmationCode.Alphabet]
+	code := make([]byte, c.cfg.ConfirmationCode.Length)
+	for ix := range code {
+		result, err := rand.Int(rand.Reader, big.NewInt(int64(len(characters))))
+		log.Panic(err, "random wrong")
+		code[ix] = characters[result.Int64()]
+	}
 
-	return fmt.Sprintf("%03d", result.Int64()+1)
+	return string(code)
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/ice-blockchain/eskimo/users"
 	"github.com/ice-blockchain/wintr/auth"
 	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/terror"
 	"github.com/ice-blockchain/wintr/time"
 )
 
@@ -28,10 +29,10 @@ func (c *client) SignIn(ctx context.Context, emailLinkPayload, confirmationCode
 	els, err := c.getEmailLinkSignInByPk(ctx, &id, token.OldEmail)
 	if err != nil {
 		if storage.IsErr(err, storage.ErrNotFound) {
-			return errors.Wrapf(ErrNoConfirmationRequired, "[getEmailLinkSignInByPk] no pending confirmation for email:%v", email)
+			return errors.Wrapf(ErrNoConfirmationRequired, "[getEmailLinkSignInByPk] no pending confirmation for email:%v", users.RedactedEmail(email))
 		}
 
-		return errors.Wrapf(err, "failed to get user info by email:%v(old email:%v)", email, token.OldEmail)
+		return errors.Wrapf(err, "failed to get user info by email:%v(old email:%v)", users.RedactedEmail(email), users.RedactedEmail(token.OldEmail))
 	}
 	if vErr := c.verifySignIn(ctx, els, &id, emailLinkPayload, confirmationCode, token.OTP); vErr != nil {
 		return errors.Wrapf(vErr, "can't verify sign in for id:%#v", id)
@@ -49,23 +50,28 @@ func (c *client) SignIn(ctx context.Context, emailLinkPayload, confirmationCode
 		if token.OldEmail != "" {
 			mErr = multierror.Append(mErr,
 				errors.Wrapf(c.resetEmailModification(ctx, *els.UserID, token.OldEmail),
-					"[reset] resetEmailModification failed for email:%v", token.OldEmail),
+					"[reset] resetEmailModification failed for email:%v", users.RedactedEmail(token.OldEmail)),
 				errors.Wrapf(c.resetFirebaseEmailModification(ctx, els.Metadata, token.OldEmail),
-					"[reset] resetEmailModification failed for email:%v", token.OldEmail),
+					"[reset] resetEmailModification failed for email:%v", users.RedactedEmail(token.OldEmail)),
 			)
 		}
-		mErr = multierror.Append(mErr, errors.Wrapf(fErr, "can't finish auth process for userID:%v,email:%v,otp:%v", els.UserID, email, token.OTP))
+		mErr = multierror.Append(mErr, errors.Wrapf(fErr, "can't finish auth process for userID:%v,email:%v,otp:%v", els.UserID, users.RedactedEmail(email), token.OTP))
 
 		return mErr.ErrorOrNil() //nolint:wrapcheck // .
 	}
+	c.sendAuthEvent(ctx, users.CodeVerifiedAuthEventType, &id, *els.UserID, "")
 
 	return nil
 }
 
-//nolint:revive // .
+//nolint:revive,funlen // .
 func (c *client) verifySignIn(ctx context.Context, els *emailLinkSignIn, id *loginID, emailLinkPayload, confirmationCode, tokenOTP string) error {
 	if els.OTP == *els.UserID || els.OTP != tokenOTP {
-		return errors.Wrapf(ErrNoConfirmationRequired, "no pending confirmation for email:%v", id.Email)
+		return errors.Wrapf(ErrNoConfirmationRequired, "no pending confirmation for email:%v", users.RedactedEmail(id.Email))
+	}
+	// The expiration is stored per sign in, so that changing the TTL doesn't affect the sign ins that are already in progress.
+	if els.ConfirmationCodeExpiresAt != nil && time.Now().After(*els.ConfirmationCodeExpiresAt.Time) {
+		return errors.Wrapf(ErrConfirmationCodeExpired, "confirmation code expired at %v for id:%#v", els.ConfirmationCodeExpiresAt, id)
 	}
 	var shouldBeBlocked bool
 	var mErr *multierror.Error
@@ -76,7 +82,9 @@ func (c *client) verifySignIn(ctx context.Context, els *emailLinkSignIn, id *log
 			shouldBeBlocked = true
 		}
 		if !shouldBeBlocked {
-			return errors.Wrapf(ErrConfirmationCodeAttemptsExceeded, "confirmation code wrong attempts count exceeded for id:%#v", id)
+			err := errors.Wrapf(ErrConfirmationCodeAttemptsExceeded, "confirmation code wrong attempts count exceeded for id:%#v", id)
+
+			return terror.New(err, confirmationCodeAttemptsData(0, els.BlockedUntil))
 		}
 		mErr = multierror.Append(mErr, errors.Wrapf(ErrConfirmationCodeAttemptsExceeded, "confirmation code wrong attempts count exceeded for id:%#v", id))
 	}
@@ -84,25 +92,43 @@ func (c *client) verifySignIn(ctx context.Context, els *emailLinkSignIn, id *log
 		if els.ConfirmationCodeWrongAttemptsCount+1 >= c.cfg.ConfirmationCode.MaxWrongAttemptsCount {
 			shouldBeBlocked = true
 		}
-		if iErr := c.increaseWrongConfirmationCodeAttemptsCount(ctx, id, shouldBeBlocked); iErr != nil {
+		var blockedUntil *time.Time
+		if shouldBeBlocked {
+			blockedUntil = time.New(time.Now().Add(c.cfg.EmailValidation.BlockDuration))
+		}
+		c.sendAuthEvent(ctx, users.CodeFailedAuthEventType, id, *els.UserID, "")
+		if iErr := c.increaseWrongConfirmationCodeAttemptsCount(ctx, id, blockedUntil); iErr != nil {
 			mErr = multierror.Append(mErr, errors.Wrapf(iErr,
-				"can't increment wrong confirmation code attempts count for email:%v,deviceUniqueID:%v", id.Email, id.DeviceUniqueID))
+				"can't increment wrong confirmation code attempts count for email:%v,deviceUniqueID:%v", users.RedactedEmail(id.Email), id.DeviceUniqueID))
+		} else if shouldBeBlocked {
+			c.sendAuthEvent(ctx, users.SessionBlockedAuthEventType, id, *els.UserID, "")
 		}
 		mErr = multierror.Append(mErr, errors.Wrapf(ErrConfirmationCodeWrong, "wrong confirmation code:%v for linkPayload:%v", confirmationCode, emailLinkPayload))
+		remainingAttempts := c.cfg.ConfirmationCode.MaxWrongAttemptsCount - (els.ConfirmationCodeWrongAttemptsCount + 1)
 
-		return mErr.ErrorOrNil() //nolint:wrapcheck // Not needed.
+		return terror.New(mErr.ErrorOrNil(), confirmationCodeAttemptsData(remainingAttempts, blockedUntil))
 	}
 
 	return nil
 }
 
-//nolint:revive // Not to create duplicated function with/without bool flag.
-func (c *client) increaseWrongConfirmationCodeAttemptsCount(ctx context.Context, id *loginID, shouldBeBlocked bool) error {
+// confirmationCodeAttemptsData is the data returned to the clients with the confirmation code errors,
+// so that they can display how many attempts are left or, once blocked, until when a new sign in can't be started.
+func confirmationCodeAttemptsData(remainingAttempts int64, blockedUntil *time.Time) map[string]any {
+	data := map[string]any{"remainingAttempts": max(remainingAttempts, 0)}
+	if blockedUntil != nil {
+		data["blockedUntil"] = blockedUntil
+	}
+
+	return data
+}
+
+func (c *client) increaseWrongConfirmationCodeAttemptsCount(ctx context.Context, id *loginID, blockedUntil *time.Time) error {
 	params := []any{id.Email, id.DeviceUniqueID}
 	var blockSQL string
-	if shouldBeBlocked {
+	if blockedUntil != nil {
 		blockSQL = ",blocked_until = $3"
-		params = append(params, time.Now().Add(c.cfg.EmailValidation.BlockDuration))
+		params = append(params, blockedUntil.Time)
 	}
 	sql := fmt.Sprintf(`UPDATE email_link_sign_ins
 				SET confirmation_code_wrong_attempts_count = confirmation_code_wrong_attempts_count + 1
@@ -140,13 +166,32 @@ func (c *client) finishAuthProcess(
 		return errors.Wrapf(err, "failed to merge %#v and %v:%v", md, auth.IceIDClaim, userID)
 	}
 	params := []any{id.Email, time.Now().Time, userID, otp, id.DeviceUniqueID, issuedTokenSeq, mdToUpdate}
+	var firebaseMigration string
+	if !strings.HasPrefix(userID, iceIDPrefix) {
+		// Whoever isn't registered with ice is registered with Firebase, so signing in with an email link migrates them to an ice ID.
+		firebaseID, _ := mdToUpdate[auth.FirebaseIDClaim].(string) //nolint:errcheck,revive // Not needed.
+		if firebaseID == "" {
+			firebaseID = userID
+		}
+		firebaseMigration = `, firebase_migration as (
+				INSERT INTO firebase_migrations(checked_at, migrated_at, user_id, firebase_id, device_unique_id, source)
+				VALUES ($2, $2, $3, $8, $5, $9) ON CONFLICT(user_id) DO UPDATE
+					SET checked_at = EXCLUDED.checked_at,
+						migrated_at = EXCLUDED.migrated_at,
+						firebase_id = EXCLUDED.firebase_id,
+						device_unique_id = EXCLUDED.device_unique_id,
+						source = EXCLUDED.source
+				WHERE firebase_migrations.migrated_at IS NULL
+			)`
+		params = append(params, firebaseID, signInFirebaseMigrationSource)
+	}
 	sql := fmt.Sprintf(`
 			with metadata_update as (
 				INSERT INTO account_metadata(user_id, metadata)
 				VALUES ($3, $7::jsonb) ON CONFLICT(user_id) DO UPDATE
 					SET metadata = EXCLUDED.metadata
 				WHERE account_metadata.metadata != EXCLUDED.metadata
-			) 
+			) %[2]v
 			UPDATE email_link_sign_ins
 				SET token_issued_at = $2,
 					user_id = $3,
@@ -159,7 +204,7 @@ func (c *client) finishAuthProcess(
 				  AND otp = $4
 				  AND device_unique_id = $5
 				  AND issued_token_seq = $6
-			`, emailConfirmedAt)
+			`, emailConfirmedAt, firebaseMigration)
 
 	rowsUpdated, err := storage.Exec(ctx, c.db, sql, params...)
 	if err != nil {
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (c *client) GetSignInLockouts(ctx context.Context, limit, offset uint64) ([]*SignInLockout, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT created_at,
+				   blocked_until,
+				   user_id,
+				   email,
+				   device_unique_id,
+				   confirmation_code_wrong_attempts_count
+			FROM email_link_sign_ins
+			WHERE blocked_until > $1
+			   OR confirmation_code_wrong_attempts_count > 0
+			ORDER BY blocked_until DESC NULLS LAST, confirmation_code_wrong_attempts_count DESC, created_at DESC
+			LIMIT $2 OFFSET $3`
+	res, err := storage.Select[SignInLockout](ctx, c.db, sql, time.Now().Time, limit, offset)
+
+	return res, errors.Wrap(err, "failed to select sign in lockouts")
+}
+
+func (c *client) GetSignInAttemptsPerIP(ctx context.Context, minAttempts, limit, offset uint64) ([]*IPSignInAttempts, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT (to_timestamp(login_session_number * $1) AT TIME ZONE 'UTC') AS window_started_at,
+				   ip,
+				   login_attempts,
+				   $2::bigint 												 AS max_attempts
+			FROM sign_ins_per_ip
+			WHERE login_attempts >= $3
+				  AND strpos(ip, $6) = 0
+			ORDER BY login_session_number DESC, login_attempts DESC
+			LIMIT $4 OFFSET $5`
+	res, err := storage.Select[IPSignInAttempts](ctx, c.db, sql,
+		int64(c.cfg.EmailValidation.SameIPRateCheckPeriod.Seconds()), c.cfg.EmailValidation.MaxRequestsFromIP, minAttempts, limit, offset,
+		signInThrottleKeySeparator)
+
+	return res, errors.Wrapf(err, "failed to select sign in attempts per ip for minAttempts:%v", minAttempts)
+}
+
+func (c *client) GetSignInStatistics(ctx context.Context) (*SignInStatistics, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	now := time.Now()
+	global := &c.cfg.SignInThrottling.Global
+	var windowNumber int64
+	if global.MaxAttempts > 0 {
+		windowNumber = global.windowNumber(now)
+	}
+	sql := `SELECT (SELECT count(1) FROM email_link_sign_ins WHERE blocked_until > $1) AS blocked_sign_ins,
+				   ($4 > 0 AND COALESCE((SELECT login_attempts >= $4
+										 FROM sign_ins_per_ip
+										 WHERE ip = $2 AND login_session_number = $3), false)) AS sign_in_links_throttled`
+	res, err := storage.Get[SignInStatistics](ctx, c.db, sql,
+		now.Time, globalSignInThrottle+signInThrottleKeySeparator, windowNumber, global.MaxAttempts)
+
+	return res, errors.Wrap(err, "failed to get the sign in statistics")
+}
+
+// UnblockSignIn lifts the lockout of a login identity (email+device) and/or resets the login attempts of an IP.
+// Every unblock is audited, in the same transaction, so there's no unblock without a trace.
+func (c *client) UnblockSignIn(ctx context.Context, unblock *SignInUnblock) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	if unblock.Email == "" && unblock.IP == "" {
+		return errors.Wrap(ErrUserNotFound, "nothing to unblock")
+	}
+	unblock.UnblockedAt = time.Now()
+
+	return errors.Wrapf(storage.DoInTransaction(ctx, c.db, func(conn storage.QueryExecer) error {
+		if unblock.Email != "" {
+			sql := `UPDATE email_link_sign_ins
+						SET blocked_until = NULL,
+							confirmation_code_wrong_attempts_count = 0
+					WHERE email = $1
+						  AND device_unique_id = $2`
+			if updatedRows, err := storage.Exec(ctx, conn, sql, unblock.Email, unblock.DeviceUniqueID); err != nil {
+				return errors.Wrapf(err, "failed to unblock sign in for email:%v,deviceUniqueID:%v", users.RedactedEmail(unblock.Email), unblock.DeviceUniqueID)
+			} else if updatedRows == 0 {
+				return errors.Wrapf(ErrUserNotFound, "no sign in for email:%v,deviceUniqueID:%v", users.RedactedEmail(unblock.Email), unblock.DeviceUniqueID)
+			}
+		}
+		if unblock.IP != "" {
+			if _, err := storage.Exec(ctx, conn, `UPDATE sign_ins_per_ip SET login_attempts = 0 WHERE ip = $1`, unblock.IP); err != nil {
+				return errors.Wrapf(err, "failed to reset login attempts for ip:%v", unblock.IP)
+			}
+		}
+		sql := `INSERT INTO sign_in_unblock_audit (unblocked_at, admin_user_id, email, device_unique_id, ip, reason) VALUES ($1, $2, $3, $4, $5, $6)`
+		_, err := storage.Exec(ctx, conn, sql,
+			unblock.UnblockedAt.Time, unblock.AdminUserID, unblock.Email, unblock.DeviceUniqueID, unblock.IP, unblock.Reason)
+
+		return errors.Wrapf(err, "failed to insert sign in unblock audit for %#v", unblock)
+	}), "failed to unblock sign in for %#v", unblock)
+}
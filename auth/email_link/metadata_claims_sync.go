@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+	"maps"
+	stdlibtime "time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/auth"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (c *client) startMetadataClaimsSyncer(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(c.cfg.MetadataClaimsSync.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 5 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(c.syncMetadataClaims(reqCtx), "failed to syncMetadataClaims"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// syncMetadataClaims pushes the account metadata of the Firebase users to their custom claims, if it drifted from what was last pushed.
+// The drift is detected by comparing it with the last synced metadata, so the changes are picked up whoever made them, i.e. account merges.
+// The failed syncs are retried with an exponential backoff. Replicas might push the same metadata concurrently, which is harmless.
+func (c *client) syncMetadataClaims(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	now := time.Now()
+	sql := `SELECT md.user_id,
+				   md.metadata,
+				   COALESCE(s.attempts, 0) AS attempts
+			FROM account_metadata md
+				LEFT JOIN account_metadata_claims_sync s ON s.user_id = md.user_id
+			WHERE COALESCE(md.metadata ->> $1, '') != ''
+				  AND s.synced_metadata IS DISTINCT FROM md.metadata
+				  AND (s.retry_at IS NULL OR s.retry_at <= $2)
+			ORDER BY s.retry_at NULLS FIRST
+			LIMIT $3`
+	drifted, err := storage.Select[metadataClaims](ctx, c.db, sql, auth.FirebaseIDClaim, now.Time, c.cfg.MetadataClaimsSync.BatchSize)
+	if err != nil {
+		return errors.Wrap(err, "failed to select the account metadata drifted from the custom claims")
+	}
+	var mErr *multierror.Error
+	for _, md := range drifted {
+		if sErr := c.syncMetadataClaimsOf(ctx, md, now); sErr != nil {
+			mErr = multierror.Append(mErr, errors.Wrapf(c.retryMetadataClaimsSync(ctx, md, now), "failed to schedule the retry for userID:%v", md.UserID),
+				errors.Wrapf(sErr, "failed to sync the custom claims of userID:%v (attempt %v)", md.UserID, md.Attempts+1))
+		}
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // Not needed.
+}
+
+func (c *client) syncMetadataClaimsOf(ctx context.Context, md *metadataClaims, now *time.Time) error {
+	firebaseID, _ := (*md.Metadata)[auth.FirebaseIDClaim].(string) //nolint:errcheck,revive // It's selected only if it's a non empty string.
+	// UpdateCustomClaims merges the current claims into the provided ones, so they're copied, not to store them as synced.
+	if err := c.authClient.UpdateCustomClaims(ctx, firebaseID, maps.Clone(*md.Metadata)); err != nil && !errors.Is(err, auth.ErrUserNotFound) {
+		return errors.Wrapf(err, "failed to update the custom claims of firebaseID:%v", firebaseID)
+	}
+	sql := `INSERT INTO account_metadata_claims_sync (user_id, synced_metadata, synced_at, attempts, retry_at)
+				VALUES ($1, $2, $3, 0, NULL)
+			ON CONFLICT (user_id) DO UPDATE
+				SET synced_metadata = EXCLUDED.synced_metadata,
+					synced_at = EXCLUDED.synced_at,
+					attempts = 0,
+					retry_at = NULL`
+	_, err := storage.Exec(ctx, c.db, sql, md.UserID, md.Metadata, now.Time)
+
+	return errors.Wrapf(err, "failed to mark the custom claims of userID:%v as synced", md.UserID)
+}
+
+func (c *client) retryMetadataClaimsSync(ctx context.Context, md *metadataClaims, now *time.Time) error {
+	const maxBackoffExponent = 20
+	backoff := c.cfg.MetadataClaimsSync.MaxBackoff
+	if md.Attempts < maxBackoffExponent {
+		backoff = min(c.cfg.MetadataClaimsSync.Interval<<md.Attempts, backoff)
+	}
+	sql := `INSERT INTO account_metadata_claims_sync (user_id, attempts, retry_at)
+				VALUES ($1, 1, $2)
+			ON CONFLICT (user_id) DO UPDATE
+				SET attempts = account_metadata_claims_sync.attempts + 1,
+					retry_at = EXCLUDED.retry_at`
+	_, err := storage.Exec(ctx, c.db, sql, md.UserID, now.Add(backoff))
+
+	return errors.Wrapf(err, "failed to update the custom claims sync retry of userID:%v", md.UserID)
+}
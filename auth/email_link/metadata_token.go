@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/auth"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (c *client) ParseMetadataToken(metadataToken string) (*MetadataToken, error) {
+	token, err := c.authClient.ParseToken(metadataToken)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidMetadataToken, "failed to verify metadata token: %v", err)
+	}
+	if token.RegisteredClaims == nil || token.Issuer != metadataJwtIssuer || token.IssuedAt == nil || token.Subject == "" {
+		return nil, errors.Wrap(ErrInvalidMetadataToken, "not a metadata token")
+	}
+	md := &MetadataToken{IssuedAt: time.New(token.IssuedAt.Time), UserID: token.Subject}
+	age := time.Now().Sub(*md.IssuedAt.Time)
+	if c.cfg.MetadataToken.MaxAge > 0 && age > c.cfg.MetadataToken.MaxAge {
+		return md, errors.Wrapf(ErrExpiredMetadataToken, "metadata token for userID:%v was issued at %v", md.UserID, md.IssuedAt)
+	}
+	md.RefreshRequired = c.cfg.MetadataToken.RefreshAfter > 0 && age > c.cfg.MetadataToken.RefreshAfter
+
+	return md, nil
+}
+
+// RefreshMetadata accepts expired tokens as well, because that's the whole point of refreshing them.
+func (c *client) RefreshMetadata(ctx context.Context, userID, email, metadataToken string) (*Metadata, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "refresh metadata failed because context failed")
+	}
+	md, err := c.ParseMetadataToken(metadataToken)
+	if err != nil && !errors.Is(err, ErrExpiredMetadataToken) {
+		return nil, err
+	}
+	if _, err = c.authClient.ModifyTokenWithMetadata(&auth.Token{UserID: userID}, metadataToken); err != nil {
+		return nil, errors.Wrapf(ErrMetadataTokenMismatch, "metadata token of userID:%v is not owned by userID:%v: %v", md.UserID, userID, err)
+	}
+	refreshed, _, err := c.Metadata(ctx, md.UserID, email)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to re-issue metadata token for userID:%v", md.UserID)
+	}
+
+	return &Metadata{UserID: md.UserID, Metadata: refreshed}, nil
+}
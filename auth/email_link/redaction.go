@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"fmt"
+
+	"github.com/ice-blockchain/eskimo/users"
+)
+
+func (l SignInLockout) Format(state fmt.State, verb rune) { //nolint:gocritic // It has to be a value receiver, for the values to use it.
+	users.FormatRedacted(state, verb, l)
+}
+
+func (e UserEmail) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	users.FormatRedacted(state, verb, e)
+}
+
+func (u SignInUnblock) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	users.FormatRedacted(state, verb, u)
+}
+
+func (id loginID) Format(state fmt.State, verb rune) {
+	users.FormatRedacted(state, verb, id)
+}
+
+func (els emailLinkSignIn) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	users.FormatRedacted(state, verb, els)
+}
+
+func (md metadata) Format(state fmt.State, verb rune) {
+	users.FormatRedacted(state, verb, md)
+}
+
+func (se secondaryEmail) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	users.FormatRedacted(state, verb, se)
+}
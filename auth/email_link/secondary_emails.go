@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/terror"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (c *client) GetEmails(ctx context.Context, userID string) ([]*UserEmail, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT NULL::timestamp 	 	  AS created_at,
+				   email,
+				   true 			  	  AS primary,
+				   true 			  	  AS confirmed
+			FROM users
+			WHERE id = $1
+			  AND email != id
+			UNION ALL
+			(SELECT created_at,
+				    email,
+				    false 				  AS primary,
+				    confirmed_at IS NOT NULL AS confirmed
+			 FROM secondary_emails
+			 WHERE user_id = $1
+			 ORDER BY created_at)`
+	res, err := storage.Select[UserEmail](ctx, c.db, sql, userID)
+	for _, userEmail := range res {
+		if userEmail.Primary {
+			userEmail.Email = c.openEmail(userEmail.Email)
+		}
+	}
+
+	return res, errors.Wrapf(err, "failed to select emails for userID:%v", userID)
+}
+
+func (c *client) AddEmail(ctx context.Context, userID, emailValue, language string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	primary, err := c.getPrimaryEmail(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the primary email of userID:%v", userID)
+	}
+	if primary == "" || primary == userID {
+		return errors.Wrapf(ErrPrimaryEmailRequired, "userID:%v has no primary email", userID)
+	}
+	if iErr := c.isUserExist(ctx, emailValue); !storage.IsErr(iErr, storage.ErrNotFound) {
+		if iErr != nil {
+			return errors.Wrapf(iErr, "can't check if user exists for email:%v", users.RedactedEmail(emailValue))
+		}
+
+		return errors.Wrapf(terror.New(ErrUserDuplicate, map[string]any{"field": "email"}), "user with such email already exists:%v", emailValue)
+	}
+	now, confirmationCode := time.Now(), c.generateConfirmationCode()
+	sql := `INSERT INTO secondary_emails (created_at, confirmation_code_expires_at, user_id, email, confirmation_code)
+				SELECT $1, $2, $3, $4, $5
+				WHERE (SELECT count(1) FROM secondary_emails WHERE user_id = $3 AND email != $4) < $6
+			ON CONFLICT (email, user_id) DO UPDATE
+				SET created_at 							   = EXCLUDED.created_at,
+					confirmation_code_expires_at 		   = EXCLUDED.confirmation_code_expires_at,
+					confirmation_code 					   = EXCLUDED.confirmation_code,
+					confirmation_code_wrong_attempts_count = 0
+			WHERE secondary_emails.confirmed_at IS NULL`
+	upserted, err := storage.Exec(ctx, c.db, sql,
+		now.Time, now.Add(c.cfg.ConfirmationCode.TTL), userID, emailValue, confirmationCode, maxSecondaryEmailsPerUser)
+	if err != nil {
+		return errors.Wrapf(err, "failed to upsert secondary email:%v for userID:%v", users.RedactedEmail(emailValue), userID)
+	}
+	if upserted == 0 {
+		return errors.Wrapf(ErrTooManyEmails, "userID:%v already has %v secondary emails", userID, maxSecondaryEmailsPerUser)
+	}
+	data := struct {
+		Email            string
+		ConfirmationCode string
+	}{
+		Email:            emailValue,
+		ConfirmationCode: confirmationCode,
+	}
+
+	return errors.Wrapf(c.sendEmailWithType(ctx, confirmEmailType, emailValue, language, data),
+		"failed to send the confirmation code of secondary email:%v for userID:%v", users.RedactedEmail(emailValue), userID)
+}
+
+//nolint:funlen // .
+func (c *client) ConfirmEmail(ctx context.Context, userID, emailValue, confirmationCode string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT * FROM secondary_emails WHERE user_id = $1 AND email = $2`
+	se, err := storage.Get[secondaryEmail](ctx, c.db, sql, userID, emailValue)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return errors.Wrapf(ErrNoConfirmationRequired, "no pending confirmation for secondary email:%v of userID:%v", users.RedactedEmail(emailValue), userID)
+		}
+
+		return errors.Wrapf(err, "failed to get secondary email:%v of userID:%v", users.RedactedEmail(emailValue), userID)
+	}
+	if se.ConfirmedAt != nil || se.ConfirmationCode == nil {
+		return errors.Wrapf(ErrNoConfirmationRequired, "secondary email:%v of userID:%v is already confirmed", users.RedactedEmail(emailValue), userID)
+	}
+	if se.ConfirmationCodeExpiresAt != nil && time.Now().After(*se.ConfirmationCodeExpiresAt.Time) {
+		return errors.Wrapf(ErrConfirmationCodeExpired, "confirmation code expired at %v for secondary email:%v", se.ConfirmationCodeExpiresAt, users.RedactedEmail(emailValue))
+	}
+	if se.ConfirmationCodeWrongAttemptsCount >= c.cfg.ConfirmationCode.MaxWrongAttemptsCount {
+		return errors.Wrapf(ErrConfirmationCodeAttemptsExceeded, "confirmation code wrong attempts count exceeded for secondary email:%v", users.RedactedEmail(emailValue))
+	}
+	if *se.ConfirmationCode != confirmationCode {
+		sql = `UPDATE secondary_emails
+					SET confirmation_code_wrong_attempts_count = confirmation_code_wrong_attempts_count + 1
+			   WHERE user_id = $1
+					 AND email = $2`
+		_, uErr := storage.Exec(ctx, c.db, sql, userID, emailValue)
+
+		return multierror.Append( //nolint:wrapcheck // .
+			errors.Wrapf(uErr, "can't increment wrong confirmation code attempts count for secondary email:%v", users.RedactedEmail(emailValue)),
+			errors.Wrapf(ErrConfirmationCodeWrong, "wrong confirmation code:%v for secondary email:%v", confirmationCode, users.RedactedEmail(emailValue)),
+		).ErrorOrNil()
+	}
+	if iErr := c.isUserExist(ctx, emailValue); !storage.IsErr(iErr, storage.ErrNotFound) {
+		if iErr != nil {
+			return errors.Wrapf(iErr, "can't check if user exists for email:%v", users.RedactedEmail(emailValue))
+		}
+
+		return errors.Wrapf(terror.New(ErrUserDuplicate, map[string]any{"field": "email"}), "user with such email already exists:%v", emailValue)
+	}
+	sql = `UPDATE secondary_emails
+				SET confirmed_at = $3,
+					confirmation_code = NULL
+		   WHERE user_id = $1
+				 AND email = $2
+				 AND confirmation_code = $4`
+	if updated, uErr := storage.Exec(ctx, c.db, sql, userID, emailValue, time.Now().Time, confirmationCode); uErr != nil {
+		if storage.IsErr(uErr, storage.ErrDuplicate) {
+			return errors.Wrapf(terror.New(ErrUserDuplicate, map[string]any{"field": "email"}), "secondary email:%v was confirmed by another user", users.RedactedEmail(emailValue))
+		}
+
+		return errors.Wrapf(uErr, "failed to confirm secondary email:%v of userID:%v", users.RedactedEmail(emailValue), userID)
+	} else if updated == 0 {
+		return errors.Wrapf(ErrNoConfirmationRequired, "secondary email:%v of userID:%v was confirmed concurrently", users.RedactedEmail(emailValue), userID)
+	}
+
+	return nil
+}
+
+// SetPrimaryEmail changes the email of the user, which becomes a secondary one, and moves the sign ins to the new primary email,
+// because they're keyed by it.
+func (c *client) SetPrimaryEmail(ctx context.Context, userID, emailValue string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	secondary, err := c.isSecondaryEmail(ctx, userID, emailValue)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if email:%v is a secondary email of userID:%v", users.RedactedEmail(emailValue), userID)
+	}
+	if !secondary {
+		return errors.Wrapf(ErrUserNotFound, "email:%v is not a confirmed secondary email of userID:%v", users.RedactedEmail(emailValue), userID)
+	}
+	oldEmail, err := c.getPrimaryEmail(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the primary email of userID:%v", userID)
+	}
+	md, err := storage.Get[metadata](ctx, c.db, `SELECT * FROM account_metadata WHERE user_id = $1`, userID)
+	if err != nil && !storage.IsErr(err, storage.ErrNotFound) {
+		return errors.Wrapf(err, "failed to get account metadata for userID %v", userID)
+	}
+	if md == nil {
+		md = new(metadata)
+	}
+	usr := new(users.User)
+	usr.ID = userID
+	usr.Email = emailValue
+	if err = c.userModifier.ModifyUser(users.ConfirmedEmailContext(ctx, emailValue), usr, nil); err != nil {
+		return errors.Wrapf(err, "failed to modify user %v with primary email modification", userID)
+	}
+	if fErr := c.resetFirebaseEmailModification(ctx, md.Metadata, emailValue); fErr != nil {
+		return multierror.Append( //nolint:wrapcheck // .
+			errors.Wrapf(c.resetEmailModification(ctx, userID, oldEmail), "[reset] resetEmailModification failed for email:%v", users.RedactedEmail(oldEmail)),
+			errors.Wrapf(fErr, "failed to change email in firebase to:%v", emailValue),
+		).ErrorOrNil()
+	}
+	if sErr := c.swapPrimaryEmail(ctx, userID, oldEmail, emailValue); sErr != nil {
+		return multierror.Append( //nolint:wrapcheck // .
+			errors.Wrapf(c.resetEmailModification(ctx, userID, oldEmail), "[reset] resetEmailModification failed for email:%v", users.RedactedEmail(oldEmail)),
+			errors.Wrapf(c.resetFirebaseEmailModification(ctx, md.Metadata, oldEmail), "[reset] updateEmail in firebase failed for email:%v", users.RedactedEmail(oldEmail)),
+			errors.Wrapf(sErr, "failed to swap primary email:%v with:%v for userID:%v", users.RedactedEmail(oldEmail), users.RedactedEmail(emailValue), userID),
+		).ErrorOrNil()
+	}
+
+	return nil
+}
+
+func (c *client) swapPrimaryEmail(ctx context.Context, userID, oldEmail, newEmail string) error {
+	return errors.Wrapf(storage.DoInTransaction(ctx, c.db, func(conn storage.QueryExecer) error {
+		if _, err := storage.Exec(ctx, conn, `DELETE FROM secondary_emails WHERE user_id = $1 AND email = $2`, userID, newEmail); err != nil {
+			return errors.Wrapf(err, "failed to delete secondary email:%v", users.RedactedEmail(newEmail))
+		}
+		if oldEmail != "" && oldEmail != userID {
+			sql := `INSERT INTO secondary_emails (created_at, confirmed_at, user_id, email) VALUES ($1, $1, $2, $3)`
+			if _, err := storage.Exec(ctx, conn, sql, time.Now().Time, userID, oldEmail); err != nil {
+				return errors.Wrapf(err, "failed to insert the previous primary email:%v as secondary", users.RedactedEmail(oldEmail))
+			}
+		}
+		// Whatever sign ins were started with the new primary email, before it was confirmed, are stale now.
+		if _, err := storage.Exec(ctx, conn, `DELETE FROM email_link_sign_ins WHERE email = $1`, newEmail); err != nil {
+			return errors.Wrapf(err, "failed to delete stale sign ins for email:%v", users.RedactedEmail(newEmail))
+		}
+		sql := `UPDATE email_link_sign_ins SET email = $3 WHERE user_id = $1 AND email = $2`
+		_, err := storage.Exec(ctx, conn, sql, userID, oldEmail, newEmail)
+
+		return errors.Wrapf(err, "failed to move sign ins from email:%v to:%v", users.RedactedEmail(oldEmail), users.RedactedEmail(newEmail))
+	}), "failed to swap primary email for userID:%v", userID)
+}
+
+func (c *client) RemoveEmail(ctx context.Context, userID, emailValue string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `DELETE FROM secondary_emails WHERE user_id = $1 AND email = $2`
+	if deleted, err := storage.Exec(ctx, c.db, sql, userID, emailValue); err != nil {
+		return errors.Wrapf(err, "failed to delete secondary email:%v of userID:%v", users.RedactedEmail(emailValue), userID)
+	} else if deleted == 0 {
+		return errors.Wrapf(ErrUserNotFound, "email:%v is not a secondary email of userID:%v", users.RedactedEmail(emailValue), userID)
+	}
+
+	return nil
+}
+
+func (c *client) getPrimaryEmail(ctx context.Context, userID string) (string, error) {
+	usr, err := storage.Get[struct {
+		Email string
+	}](ctx, c.db, `SELECT email FROM users WHERE id = $1`, userID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get email of userID:%v", userID)
+	}
+
+	return c.openEmail(usr.Email), nil
+}
+
+// primaryEmail resolves a confirmed secondary email to the primary email of its user, because sign ins are keyed by the primary one.
+// Any other email is returned as is.
+func (c *client) primaryEmail(ctx context.Context, emailValue string) (string, error) {
+	sql := `SELECT u.email
+			FROM secondary_emails se
+				JOIN users u ON u.id = se.user_id
+			WHERE se.email = $1
+				  AND se.confirmed_at IS NOT NULL
+				  AND u.email != u.id`
+	usr, err := storage.Get[struct {
+		Email string
+	}](ctx, c.db, sql, emailValue)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return emailValue, nil
+		}
+
+		return "", errors.Wrapf(err, "failed to get the primary email for secondary email:%v", users.RedactedEmail(emailValue))
+	}
+
+	return c.openEmail(usr.Email), nil
+}
+
+func (c *client) isSecondaryEmail(ctx context.Context, userID, emailValue string) (bool, error) {
+	sql := `SELECT * FROM secondary_emails WHERE user_id = $1 AND email = $2 AND confirmed_at IS NOT NULL`
+	if _, err := storage.Get[secondaryEmail](ctx, c.db, sql, userID, emailValue); err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, errors.Wrapf(err, "failed to get secondary email:%v of userID:%v", users.RedactedEmail(emailValue), userID)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// checkSignInStepUp holds back the tokens of a risky sign in (an existing user, on a device never used before,
+// from a country never seen before) until the user passes a quick face liveness re-check.
+func (c *client) checkSignInStepUp(ctx context.Context, id *loginID, els *emailLinkSignIn, confirmationCode, clientIP string) error {
+	if !c.cfg.SignInStepUp.Enabled || els.StepUpCompletedAt != nil {
+		return nil
+	}
+	if els.StepUpRequiredAt == nil {
+		risky, err := c.isRiskySignIn(ctx, id, *els.UserID, clientIP)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check if sign in is risky for id:%#v", id)
+		}
+		if !risky {
+			return nil
+		}
+		sql := `UPDATE email_link_sign_ins
+					SET step_up_required_at = $4
+				WHERE email = $1
+					  AND device_unique_id = $2
+					  AND confirmation_code = $3
+					  AND step_up_required_at IS NULL`
+		if _, err = storage.Exec(ctx, c.db, sql, id.Email, id.DeviceUniqueID, confirmationCode, time.Now().Time); err != nil {
+			return errors.Wrapf(err, "failed to mark sign in step up as required for id:%#v", id)
+		}
+	}
+
+	return errors.Wrapf(ErrSignInStepUpRequired, "face liveness re-check required for id:%#v", id)
+}
+
+func (c *client) isRiskySignIn(ctx context.Context, id *loginID, userID, clientIP string) (bool, error) {
+	if clientIP == "" {
+		return false, nil
+	}
+	sql := `SELECT EXISTS(SELECT 1 FROM device_metadata WHERE user_id = u.id AND device_unique_id = $2) AS known_device,
+				   upper(u.country) 																   AS country,
+				   COALESCE((SELECT array_agg(DISTINCT upper(country_short))
+							 FROM device_metadata
+							 WHERE user_id = u.id
+							   AND COALESCE(country_short, '') != ''), '{}') 						   AS known_countries
+			FROM users u
+			WHERE u.id = $1`
+	risk, err := storage.Get[signInRisk](ctx, c.db, sql, userID, id.DeviceUniqueID)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) { // It's a brand new user, so there's nothing to compare with.
+			return false, nil
+		}
+
+		return false, errors.Wrapf(err, "failed to get sign in risk signals for userID:%v", userID)
+	}
+	if risk.KnownDevice {
+		return false, nil
+	}
+	location := c.userModifier.GetDeviceMetadataLocation(ctx, &users.DeviceID{UserID: userID, DeviceUniqueID: id.DeviceUniqueID}, net.ParseIP(clientIP))
+	if location.Country == "" {
+		return false, nil
+	}
+	country := strings.ToUpper(location.Country)
+
+	return country != risk.Country && !slices.Contains(risk.KnownCountries, country), nil
+}
+
+func (c *client) CompleteSignInStepUp(ctx context.Context, loginSession, livenessSessionID string) error {
+	var token loginFlowToken
+	if err := c.parseLoginSession(loginSession, &token); err != nil {
+		return errors.Wrapf(err, "can't parse login session:%v", loginSession)
+	}
+	id := loginID{Email: token.Subject, DeviceUniqueID: token.DeviceUniqueID}
+	els, err := c.getConfirmedEmailLinkSignIn(ctx, &id, token.ConfirmationCode)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return errors.Wrapf(ErrNoPendingLoginSession, "no pending login session:%v,id:%#v", loginSession, id)
+		}
+
+		return errors.Wrapf(err, "failed to get confirmed email link sign in for loginSession:%v,id:%#v", loginSession, id)
+	}
+	if els.UserID == nil || els.StepUpRequiredAt == nil || els.StepUpCompletedAt != nil {
+		return errors.Wrapf(ErrNoPendingSignInStepUp, "no pending sign in step up for id:%#v", id)
+	}
+	passed, err := c.verifyLiveness(ctx, *els.UserID, livenessSessionID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to verify liveness session:%v for userID:%v", livenessSessionID, *els.UserID)
+	}
+	if !passed {
+		return errors.Wrapf(ErrSignInStepUpFailed, "liveness session:%v not passed for userID:%v", livenessSessionID, *els.UserID)
+	}
+	sql := `UPDATE email_link_sign_ins
+				SET step_up_completed_at = $4
+			WHERE email = $1
+				  AND device_unique_id = $2
+				  AND confirmation_code = $3
+				  AND step_up_required_at IS NOT NULL
+				  AND step_up_completed_at IS NULL`
+	rowsUpdated, err := storage.Exec(ctx, c.db, sql, id.Email, id.DeviceUniqueID, token.ConfirmationCode, time.Now().Time)
+	if err != nil {
+		return errors.Wrapf(err, "failed to complete sign in step up for id:%#v", id)
+	}
+	if rowsUpdated == 0 {
+		return errors.Wrapf(ErrNoPendingSignInStepUp, "[CompleteSignInStepUp] no records were updated: race condition")
+	}
+
+	return nil
+}
+
+//nolint:gomnd // Specific config.
+func (c *client) verifyLiveness(ctx context.Context, userID, livenessSessionID string) (passed bool, err error) {
+	var result struct {
+		Passed bool `json:"passed"`
+	}
+	resp, err := req.
+		SetContext(ctx).
+		SetRetryCount(10).
+		SetRetryBackoffInterval(10*stdlibtime.Millisecond, 1*stdlibtime.Second).
+		SetRetryHook(func(resp *req.Response, err error) {
+			if err != nil {
+				log.Error(errors.Wrap(err, "failed to verify liveness session for user, retrying... "))
+			} else {
+				log.Error(errors.Errorf("failed to verify liveness session for user with status code:%v, retrying... ", resp.GetStatusCode()))
+			}
+		}).
+		SetRetryCondition(func(resp *req.Response, err error) bool {
+			return err != nil || resp.GetStatusCode() >= http.StatusInternalServerError
+		}).
+		AddQueryParam("caller", "eskimo-hut").
+		SetBodyJsonMarshal(&struct {
+			UserID    string `json:"userId"`
+			SessionID string `json:"sessionId"`
+		}{UserID: userID, SessionID: livenessSessionID}).
+		Post(c.cfg.SignInStepUp.LivenessVerificationURL)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to verify liveness session:%v for userID:%v", livenessSessionID, userID)
+	}
+	switch statusCode := resp.GetStatusCode(); statusCode {
+	case http.StatusOK:
+		if err = resp.UnmarshalJson(&result); err != nil {
+			return false, errors.Wrapf(err, "failed to decode liveness verification result for userID:%v", userID)
+		}
+
+		return result.Passed, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, errors.Errorf("[%v]failed to verify liveness session:%v for userID:%v", statusCode, livenessSessionID, userID)
+	}
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+	"math"
+	stdlibtime "time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/terror"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+type (
+	signInThrottleAttempt struct {
+		*signInThrottle
+		Name string
+		Key  string
+	}
+)
+
+// signInThrottleAttempts are the throttles that count a sign in link request of the id, the global circuit first,
+// so that the other ones aren't used up while it's open.
+func (c *client) signInThrottleAttempts(id *loginID, clientIP string) []*signInThrottleAttempt {
+	return []*signInThrottleAttempt{
+		{
+			signInThrottle: &c.cfg.SignInThrottling.Global,
+			Name:           globalSignInThrottle,
+			Key:            globalSignInThrottle + signInThrottleKeySeparator,
+		},
+		{
+			signInThrottle: &signInThrottle{Window: c.cfg.EmailValidation.SameIPRateCheckPeriod, MaxAttempts: c.cfg.EmailValidation.MaxRequestsFromIP},
+			Name:           ipSignInThrottle,
+			Key:            clientIP,
+		},
+		{
+			signInThrottle: &c.cfg.SignInThrottling.PerEmail,
+			Name:           emailSignInThrottle,
+			Key:            emailSignInThrottle + signInThrottleKeySeparator + id.Email,
+		},
+		{
+			signInThrottle: &c.cfg.SignInThrottling.PerDevice,
+			Name:           deviceSignInThrottle,
+			Key:            deviceSignInThrottle + signInThrottleKeySeparator + id.DeviceUniqueID,
+		},
+	}
+}
+
+// throttleSignIn counts the sign in link request in every enabled throttle and rejects it as soon as one of them is exhausted.
+// Only the per IP attempts are rolled back if the request fails afterwards, the other throttles count every request.
+func (c *client) throttleSignIn(ctx context.Context, id *loginID, clientIP string, now *time.Time) error {
+	for _, attempt := range c.signInThrottleAttempts(id, clientIP) {
+		if attempt.MaxAttempts == 0 || attempt.Key == "" {
+			continue
+		}
+		windowNumber := attempt.windowNumber(now)
+		sql := `INSERT INTO sign_ins_per_ip (ip, login_session_number, login_attempts)
+					VALUES ($1, $2, 1)
+				ON CONFLICT (login_session_number, ip) DO UPDATE
+					SET login_attempts = sign_ins_per_ip.login_attempts + 1
+				WHERE sign_ins_per_ip.login_attempts < $3`
+		rowsUpserted, err := storage.Exec(ctx, c.db, sql, attempt.Key, windowNumber, attempt.MaxAttempts)
+		if err != nil {
+			return errors.Wrapf(err, "failed to increment the %v sign in attempts for %#v", attempt.Name, id)
+		}
+		if rowsUpserted == 0 {
+			windowEnd := stdlibtime.Unix((windowNumber+1)*int64(attempt.Window.Seconds()), 0)
+			data := map[string]any{
+				"throttle":          attempt.Name,
+				"retryAfterSeconds": uint64(math.Ceil(windowEnd.Sub(*now.Time).Seconds())),
+			}
+			if attempt.Name == ipSignInThrottle {
+				data["ip"] = clientIP
+			}
+			err = errors.Wrapf(ErrTooManyAttempts, "sign in of %#v is throttled by the %v throttle (ip:%v)", id, attempt.Name, clientIP)
+
+			return terror.New(err, data)
+		}
+	}
+
+	return nil
+}
+
+func (t *signInThrottle) windowNumber(now *time.Time) int64 {
+	return now.Unix() / int64(t.Window.Seconds())
+}
+
+// deleteOldSignInThrottleAttempts deletes the attempts of the throttles, other than the per IP one, which aren't of their current window.
+func (c *client) deleteOldSignInThrottleAttempts(ctx context.Context) error {
+	now := time.Now()
+	var mErr *multierror.Error
+	for _, attempt := range c.signInThrottleAttempts(new(loginID), "") {
+		if attempt.MaxAttempts == 0 || attempt.Name == ipSignInThrottle {
+			continue
+		}
+		sql := `DELETE FROM sign_ins_per_ip WHERE starts_with(ip, $1) AND login_session_number != $2`
+		if _, err := storage.Exec(ctx, c.db, sql, attempt.Name+signInThrottleKeySeparator, attempt.windowNumber(now)); err != nil {
+			mErr = multierror.Append(mErr, errors.Wrapf(err, "failed to delete the old %v sign in attempts", attempt.Name))
+		}
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // Not needed.
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	stdlibtime "time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/auth"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// validateSigningKeys parses the login session signing keys. The rotation schedule is given by their `activeFrom`:
+// new login sessions are always signed with the most recently activated key, while all the keys that are not expired yet
+// are published and accepted, so a key should expire at least `emailValidation.expirationTime` after its successor is activated.
+func (cfg *config) validateSigningKeys() []error {
+	var errs []error
+	kids := make(map[string]struct{}, len(cfg.LoginSession.SigningKeys))
+	for ix, key := range cfg.LoginSession.SigningKeys {
+		if key.KID == "" {
+			errs = append(errs, errors.Errorf("`%v.loginSession.signingKeys[%v].kid` is missing", applicationYamlKey, ix))
+		}
+		if _, found := kids[key.KID]; found && key.KID != "" {
+			errs = append(errs, errors.Errorf("`%v.loginSession.signingKeys[%v].kid` `%v` is duplicated", applicationYamlKey, ix, key.KID))
+		}
+		kids[key.KID] = struct{}{}
+		seed, err := base64.StdEncoding.DecodeString(key.PrivateKey)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			errs = append(errs, errors.Errorf("`%v.loginSession.signingKeys[%v].privateKey` is invalid, a base64 encoded ed25519 seed is expected", applicationYamlKey, ix))
+		} else {
+			key.private = ed25519.NewKeyFromSeed(seed)
+		}
+		if key.activeFrom, err = stdlibtime.Parse(stdlibtime.RFC3339, key.ActiveFrom); err != nil {
+			errs = append(errs, errors.Wrapf(err, "`%v.loginSession.signingKeys[%v].activeFrom` is invalid", applicationYamlKey, ix))
+		}
+		if key.ExpiresAt == "" {
+			continue
+		}
+		if key.expiresAt, err = stdlibtime.Parse(stdlibtime.RFC3339, key.ExpiresAt); err != nil {
+			errs = append(errs, errors.Wrapf(err, "`%v.loginSession.signingKeys[%v].expiresAt` is invalid", applicationYamlKey, ix))
+		} else if !key.activeFrom.IsZero() && !key.expiresAt.After(key.activeFrom) {
+			errs = append(errs, errors.Errorf("`%v.loginSession.signingKeys[%v]` expires before being activated", applicationYamlKey, ix))
+		}
+	}
+
+	return errs
+}
+
+func (k *signingKey) expired(now *time.Time) bool {
+	return !k.expiresAt.IsZero() && !now.Before(k.expiresAt)
+}
+
+// currentSigningKey returns nil if there's no key active yet, in which case the login sessions are signed with the shared secret.
+func (c *client) currentSigningKey(now *time.Time) *signingKey {
+	var current *signingKey
+	for _, key := range c.cfg.LoginSession.SigningKeys {
+		if key.activeFrom.After(*now.Time) || key.expired(now) {
+			continue
+		}
+		if current == nil || key.activeFrom.After(current.activeFrom) {
+			current = key
+		}
+	}
+
+	return current
+}
+
+func (k *signingKey) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = k.KID
+	payload, err := token.SignedString(k.private)
+
+	return payload, errors.Wrapf(err, "failed to sign with key `%v`", k.KID)
+}
+
+func (c *client) signLoginSession(now *time.Time, claims *loginFlowToken) (string, error) {
+	if key := c.currentSigningKey(now); key != nil {
+		payload, err := key.sign(claims)
+
+		return payload, errors.Wrap(err, "failed to sign login session")
+	}
+	payload, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(c.cfg.LoginSession.JwtSecret))
+
+	return payload, errors.Wrap(err, "failed to sign login session with the shared secret")
+}
+
+// signAccessToken re-signs the access token generated by the auth client with the current key, so that it can be verified with the JWKS.
+// It's left signed with the shared secret if that's not enabled or there's no key active yet.
+func (c *client) signAccessToken(now *time.Time, accessToken string) (string, error) {
+	key := c.currentSigningKey(now)
+	if !c.cfg.LoginSession.SignAccessTokens || key == nil {
+		return accessToken, nil
+	}
+	claims := new(auth.IceToken)
+	if _, _, err := jwt.NewParser().ParseUnverified(accessToken, claims); err != nil {
+		return "", errors.Wrap(err, "failed to parse the generated access token")
+	}
+	payload, err := key.sign(claims)
+
+	return payload, errors.Wrap(err, "failed to sign access token")
+}
+
+// parseLoginSession accepts the login sessions signed with any of the published keys,
+// and, for the ones issued before the keys were configured, with the shared secret, if it's still configured.
+func (c *client) parseLoginSession(loginSession string, res *loginFlowToken) error {
+	return parseJwtTokenWithKey(loginSession, res, func(token *jwt.Token) (any, error) {
+		kid, hasKID := token.Header["kid"].(string)
+		if !hasKID {
+			if c.cfg.LoginSession.JwtSecret == "" {
+				return nil, errors.Wrap(ErrInvalidToken, "no kid provided")
+			}
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || token.Method.Alg() != jwt.SigningMethodHS256.Name {
+				return nil, errors.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+
+			return []byte(c.cfg.LoginSession.JwtSecret), nil
+		}
+		if token.Method.Alg() != jwt.SigningMethodEdDSA.Alg() {
+			return nil, errors.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		now := time.Now()
+		for _, key := range c.cfg.LoginSession.SigningKeys {
+			if key.KID == kid && !key.expired(now) {
+				return key.private.Public(), nil
+			}
+		}
+
+		return nil, errors.Wrapf(ErrInvalidToken, "unknown or expired kid:%v", kid)
+	})
+}
+
+// JSONWebKeySet publishes all the keys that are not expired, including the ones that are not active yet,
+// so that the other services can cache them before they start being used.
+func (c *client) JSONWebKeySet() *JSONWebKeySet {
+	now := time.Now()
+	jwks := &JSONWebKeySet{Keys: make([]*JSONWebKey, 0, len(c.cfg.LoginSession.SigningKeys))}
+	for _, key := range c.cfg.LoginSession.SigningKeys {
+		if key.expired(now) {
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, &JSONWebKey{
+			KeyType:   "OKP",
+			Curve:     "Ed25519",
+			Use:       "sig",
+			Algorithm: jwt.SigningMethodEdDSA.Alg(),
+			KeyID:     key.KID,
+			X:         base64.RawURLEncoding.EncodeToString(key.private.Public().(ed25519.PublicKey)), //nolint:forcetypeassert // It's always ed25519.
+		})
+	}
+
+	return jwks
+}
@@ -9,11 +9,12 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
 )
 
 func (c *client) Status(ctx context.Context, loginSession string) (tokens *Tokens, emailConfirmed bool, err error) {
 	var token loginFlowToken
-	if err = parseJwtToken(loginSession, c.cfg.LoginSession.JwtSecret, &token); err != nil {
+	if err = c.parseLoginSession(loginSession, &token); err != nil {
 		return nil, false, errors.Wrapf(err, "can't parse login session:%v", loginSession)
 	}
 	id := loginID{Email: token.Subject, DeviceUniqueID: token.DeviceUniqueID}
@@ -31,11 +32,20 @@ func (c *client) Status(ctx context.Context, loginSession string) (tokens *Token
 	if els.ConfirmationCode == *els.UserID {
 		return nil, false, errors.Wrapf(ErrNoPendingLoginSession, "tokens already provided for id:%#v", id)
 	}
+	if fErr := c.verifyDeviceFingerprint(token.DeviceFingerprint, deviceFingerprint(ctx)); fErr != nil {
+		return nil, false, errors.Wrapf(fErr, "login session is bound to another device for id:%#v", id)
+	}
+	if sErr := c.checkSignInStepUp(ctx, &id, els, token.ConfirmationCode, token.ClientIP); sErr != nil {
+		return nil, false, errors.Wrapf(sErr, "sign in step up check failed for id:%#v", id)
+	}
+	if uErr := c.useLoginSession(ctx, &token); uErr != nil {
+		return nil, false, errors.Wrapf(uErr, "can't use login session for id:%#v", id)
+	}
 	tokens, err = c.generateTokens(els.TokenIssuedAt, els, els.IssuedTokenSeq)
 	if err != nil {
 		return nil, false, errors.Wrapf(err, "can't generate tokens for id:%#v", id)
 	}
-	if rErr := c.resetLoginSession(ctx, &id, els, token.ConfirmationCode, token.ClientIP, token.LoginSessionNumber); rErr != nil {
+	if rErr := c.resetLoginSession(ctx, &id, els, &token); rErr != nil {
 		return nil, false, errors.Wrapf(rErr, "can't reset login session for id:%#v", id)
 	}
 	emailConfirmed = els.EmailConfirmedAt != nil
@@ -43,23 +53,39 @@ func (c *client) Status(ctx context.Context, loginSession string) (tokens *Token
 	return tokens, emailConfirmed, nil
 }
 
-//nolint:revive // .
-func (c *client) resetLoginSession(
-	ctx context.Context, id *loginID, els *emailLinkSignIn,
-	prevConfirmationCode, clientIP string, loginSessionNumber int64,
-) error {
+// useLoginSession makes sure the tokens are provided only once per login session, by its jti, even if it's replayed concurrently.
+func (c *client) useLoginSession(ctx context.Context, token *loginFlowToken) error {
+	if token.ID == "" || token.ExpiresAt == nil {
+		return errors.Wrap(ErrInvalidToken, "no jti or expiration provided")
+	}
+	sql := `INSERT INTO used_login_sessions (jti, expires_at) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	rowsInserted, err := storage.Exec(ctx, c.db, sql, token.ID, token.ExpiresAt.Time)
+	if err != nil {
+		return errors.Wrapf(err, "failed to insert used login session jti:%v", token.ID)
+	}
+	if rowsInserted == 0 {
+		return errors.Wrapf(ErrNoPendingLoginSession, "login session jti:%v already used", token.ID)
+	}
+
+	return nil
+}
+
+// resetLoginSession also binds the issued tokens to the device fingerprint supplied when the sign in was started, if any.
+func (c *client) resetLoginSession(ctx context.Context, id *loginID, els *emailLinkSignIn, token *loginFlowToken) error {
 	decrementIPAttempts := ""
-	params := []any{els.UserID, id.Email, id.DeviceUniqueID, els.OTP, prevConfirmationCode, els.IssuedTokenSeq}
-	if clientIP != "" && loginSessionNumber > 0 {
+	params := []any{els.UserID, id.Email, id.DeviceUniqueID, els.OTP, token.ConfirmationCode, els.IssuedTokenSeq, c.boundDeviceFingerprint(token.DeviceFingerprint), time.Now().Time}
+	if token.ClientIP != "" && token.LoginSessionNumber > 0 {
 		decrementIPAttempts = `with decrement_ip_login_attempts as (
 				UPDATE sign_ins_per_ip SET
 					login_attempts = GREATEST(sign_ins_per_ip.login_attempts - 1, 0)
-				WHERE ip = $7 AND login_session_number = $8
+				WHERE ip = $9 AND login_session_number = $10
 			)`
-		params = append(params, clientIP, loginSessionNumber)
+		params = append(params, token.ClientIP, token.LoginSessionNumber)
 	}
 	sql := fmt.Sprintf(`%v UPDATE email_link_sign_ins
-				   	  SET confirmation_code = $1
+				   	  SET confirmation_code = $1,
+				   	  	  device_fingerprint = NULLIF($7, ''),
+				   	  	  device_fingerprint_bound_at = (CASE WHEN NULLIF($7, '') IS NULL THEN NULL ELSE $8::timestamp END)
 				WHERE email = $2
 					  AND device_unique_id = $3
 					  AND otp = $4
@@ -67,5 +93,5 @@ func (c *client) resetLoginSession(
 					  AND issued_token_seq = $6`, decrementIPAttempts)
 	_, err := storage.Exec(ctx, c.db, sql, params...)
 
-	return errors.Wrapf(err, "failed to reset login session by id:%#v and confirmationCode:%v", id, prevConfirmationCode)
+	return errors.Wrapf(err, "failed to reset login session by id:%#v and confirmationCode:%v", id, token.ConfirmationCode)
 }
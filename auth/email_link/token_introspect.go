@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// IntrospectToken tells whether the access token is still active: it's valid, not expired and its sequence wasn't revoked.
+// Refreshing the tokens revokes the sequences before the previous one and signing in again, on the same device, revokes all of them,
+// which is why it can't be checked offline. The invalid tokens are just inactive, not errors, so that nothing is disclosed about them.
+func (c *client) IntrospectToken(ctx context.Context, accessToken string) (*TokenIntrospection, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	token, err := c.authClient.ParseToken(accessToken)
+	if err != nil {
+		return &TokenIntrospection{Active: false}, nil //nolint:nilerr // Expired or not, it can't be used.
+	}
+	if token.Issuer != accessTokenIssuer {
+		return &TokenIntrospection{Active: false}, nil
+	}
+	email, err := c.primaryEmail(ctx, token.Email)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve the primary email for email:%v", users.RedactedEmail(token.Email))
+	}
+	sql := `SELECT issued_token_seq, previously_issued_token_seq
+			FROM email_link_sign_ins
+			WHERE email = $1
+			  AND device_unique_id = $2
+			  AND user_id = $3`
+	signIn, err := storage.Get[struct {
+		IssuedTokenSeq           int64
+		PreviouslyIssuedTokenSeq int64
+	}](ctx, c.db, sql, email, token.DeviceUniqueID, token.Subject)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return &TokenIntrospection{Active: false}, nil
+		}
+
+		return nil, errors.Wrapf(err, "failed to get the email link sign in of userID:%v", token.Subject)
+	}
+	if token.Seq < signIn.PreviouslyIssuedTokenSeq || token.Seq > signIn.IssuedTokenSeq {
+		return &TokenIntrospection{Active: false}, nil
+	}
+	res := &TokenIntrospection{
+		UserID:         token.Subject,
+		Role:           token.Role,
+		DeviceUniqueID: token.DeviceUniqueID,
+		Seq:            token.Seq,
+		Active:         true,
+	}
+	if token.IssuedAt != nil {
+		res.IssuedAt = time.New(token.IssuedAt.Time)
+	}
+	if token.ExpiresAt != nil {
+		res.ExpiresAt = time.New(token.ExpiresAt.Time)
+	}
+
+	return res, nil
+}
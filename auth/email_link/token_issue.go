@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// IssueTokens signs in the user on the device without an email link, because it was already authenticated by other means (i.e. a passkey).
+// The tokens are tracked in the sign ins of the primary email, like the email link ones, so they can be refreshed the same way.
+func (c *client) IssueTokens(ctx context.Context, userID, deviceUniqueID string, authEventType users.AuthEventType) (*Tokens, error) {
+	return c.issueTokens(ctx, userID, deviceUniqueID, authEventType, false)
+}
+
+// IssueTokensWithoutEmail is like IssueTokens, but the user doesn't need an email.
+// The sign ins of the users without one are keyed by their ID instead, like the guests' ones.
+func (c *client) IssueTokensWithoutEmail(ctx context.Context, userID, deviceUniqueID string, authEventType users.AuthEventType) (*Tokens, error) {
+	return c.issueTokens(ctx, userID, deviceUniqueID, authEventType, true)
+}
+
+//nolint:revive // .
+func (c *client) issueTokens(ctx context.Context, userID, deviceUniqueID string, authEventType users.AuthEventType, withoutEmail bool) (*Tokens, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	primaryEmail, err := c.getPrimaryEmail(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the primary email of userID:%v", userID)
+	}
+	if primaryEmail == "" || primaryEmail == userID {
+		if !withoutEmail {
+			return nil, errors.Wrapf(ErrPrimaryEmailRequired, "userID:%v has no email to sign in with", userID)
+		}
+		primaryEmail = userID
+	}
+	id := loginID{Email: primaryEmail, DeviceUniqueID: deviceUniqueID}
+	now := time.Now()
+	tokenSeq, err := c.upsertIssuedTokenSeq(ctx, &id, userID, now)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return nil, errors.Wrapf(ErrUserBlocked, "sign ins are blocked for:%#v", id)
+		}
+
+		return nil, errors.Wrapf(err, "failed to issue a token seq for:%#v", id)
+	}
+	usr, err := c.getUserByIDOrPk(ctx, userID, &id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get user by userID:%v", userID)
+	}
+	tokens, err := c.generateTokens(now, usr, tokenSeq)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't generate tokens for userID:%v, email:%v", userID, users.RedactedEmail(id.Email))
+	}
+	c.sendAuthEvent(ctx, authEventType, &id, userID, "")
+
+	return tokens, nil
+}
+
+func (c *client) upsertIssuedTokenSeq(ctx context.Context, id *loginID, userID string, now *time.Time) (int64, error) {
+	sql := `INSERT INTO email_link_sign_ins (created_at, token_issued_at, email_confirmed_at, issued_token_seq, previously_issued_token_seq,
+											 email, otp, user_id, device_unique_id)
+			VALUES ($1, $1, $1, 1, 1, $2, $3, $3, $4)
+			ON CONFLICT (email, device_unique_id) DO UPDATE
+				SET token_issued_at = EXCLUDED.token_issued_at,
+					user_id = EXCLUDED.user_id,
+					otp = EXCLUDED.otp,
+					confirmation_code = NULL,
+					issued_token_seq = COALESCE(email_link_sign_ins.issued_token_seq, 0) + 1,
+					previously_issued_token_seq = COALESCE(email_link_sign_ins.issued_token_seq, 0) + 1
+			WHERE email_link_sign_ins.blocked_until IS NULL OR email_link_sign_ins.blocked_until < $1
+			RETURNING issued_token_seq`
+	res, err := storage.ExecOne[struct {
+		IssuedTokenSeq int64
+	}](ctx, c.db, sql, now.Time, id.Email, userID, id.DeviceUniqueID)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to upsert email link sign ins for:%#v", id)
+	}
+
+	return res.IssuedTokenSeq, nil
+}
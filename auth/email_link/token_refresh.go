@@ -7,6 +7,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/ice-blockchain/eskimo/users"
 	"github.com/ice-blockchain/wintr/auth"
 	"github.com/ice-blockchain/wintr/connectors/storage/v2"
 	"github.com/ice-blockchain/wintr/time"
@@ -26,30 +27,52 @@ func (c *client) RegenerateTokens(ctx context.Context, previousRefreshToken stri
 		return nil, errors.Wrapf(ErrInvalidToken, "failed to verify token:%v (token:%v)", err.Error(), previousRefreshToken)
 	}
 	id := loginID{Email: token.Email, DeviceUniqueID: token.DeviceUniqueID}
+	// The token might still have the previous primary email, but the sign ins moved to the current one.
+	if id.Email, err = c.primaryEmail(ctx, token.Email); err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve the primary email for email:%v", users.RedactedEmail(token.Email))
+	}
 	usr, err := c.getUserByIDOrPk(ctx, token.Subject, &id)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
-			return nil, errors.Wrapf(ErrUserNotFound, "user with userID:%v or email:%v not found", token.Subject, token.Email)
+			return nil, errors.Wrapf(ErrUserNotFound, "user with userID:%v or email:%v not found", token.Subject, users.RedactedEmail(token.Email))
 		}
 
 		return nil, errors.Wrapf(err, "failed to get user by userID:%v", token.Subject)
 	}
-	if usr.Email != token.Email || usr.DeviceUniqueID != token.DeviceUniqueID {
+	if usr.Email != id.Email || usr.DeviceUniqueID != token.DeviceUniqueID {
 		return nil, errors.Wrapf(ErrUserDataMismatch,
-			"user's email:%v does not match token's email:%v or deviceID:%v (userID %v)", usr.Email, token.Email, token.DeviceUniqueID, token.Subject)
+			"user's email:%v does not match token's email:%v or deviceID:%v (userID %v)", users.RedactedEmail(usr.Email), users.RedactedEmail(token.Email), token.DeviceUniqueID, token.Subject)
+	}
+	var boundFingerprint string
+	if usr.DeviceFingerprint != nil {
+		boundFingerprint = *usr.DeviceFingerprint
+	}
+	if fErr := c.verifyDeviceFingerprint(boundFingerprint, deviceFingerprint(ctx)); fErr != nil {
+		return nil, errors.Wrapf(fErr, "refresh token is bound to another device (userID %v)", token.Subject)
+	}
+	deviceID := &users.DeviceID{UserID: token.Subject, DeviceUniqueID: token.DeviceUniqueID}
+	if aErr := c.userModifier.CheckDeviceAttestation(ctx, deviceID, users.AuthDeviceAttestationPurpose); aErr != nil {
+		return nil, errors.Wrapf(aErr, "device attestation check failed (userID %v)", token.Subject)
 	}
 	now := time.Now()
+	if bErr := c.bindDeviceFingerprint(ctx, &id, boundFingerprint, now); bErr != nil {
+		return nil, errors.Wrapf(bErr, "failed to bind device fingerprint (userID %v)", token.Subject)
+	}
 	refreshTokenSeq, err := c.incrementRefreshTokenSeq(ctx, &id, token.Subject, token.Seq, now)
 	if err != nil {
 		if storage.IsErr(err, storage.ErrNotFound) {
 			return nil, errors.Wrapf(ErrInvalidToken, "refreshToken with wrong sequence:%v provided (userID:%v)", token.Seq, token.Subject)
 		}
 
-		return nil, errors.Wrapf(err, "failed to update email link sign ins for email:%v", token.Email)
+		return nil, errors.Wrapf(err, "failed to update email link sign ins for email:%v", users.RedactedEmail(token.Email))
 	}
 	tokens, err = c.generateTokens(now, usr, refreshTokenSeq)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't generate tokens for userID:%v, email:%v", token.Subject, users.RedactedEmail(token.Email))
+	}
+	c.sendAuthEvent(ctx, users.TokensRefreshedAuthEventType, &id, token.Subject, "")
 
-	return tokens, errors.Wrapf(err, "can't generate tokens for userID:%v, email:%v", token.Subject, token.Email)
+	return tokens, nil
 }
 
 func (c *client) incrementRefreshTokenSeq(
@@ -94,6 +117,9 @@ func (c *client) generateTokens(now *time.Time, els *emailLinkSignIn, seq int64)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to generate tokens for user:%#v", els)
 	}
+	if accessToken, err = c.signAccessToken(now, accessToken); err != nil {
+		return nil, errors.Wrapf(err, "failed to sign access token for user:%#v", els)
+	}
 
 	return &Tokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
 }
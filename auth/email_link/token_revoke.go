@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package emaillinkiceauth
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+// LogoutEverywhere revokes all the tokens of the user, on all of its devices, by bumping the token sequences of all of its sign ins
+// past the ones issued, the previous ones included, so that they can't be refreshed and are not active anymore.
+// The login flows that are in progress are invalidated as well, since they're bound to the sequence they started with.
+func (c *client) LogoutEverywhere(ctx context.Context, userID, clientIP string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `UPDATE email_link_sign_ins
+			SET issued_token_seq = COALESCE(issued_token_seq, 0) + 1,
+				previously_issued_token_seq = COALESCE(issued_token_seq, 0) + 1
+			WHERE user_id = $1
+			RETURNING email, device_unique_id`
+	signIns, err := storage.ExecMany[loginID](ctx, c.db, sql, userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to revoke the token sequences of userID:%v", userID)
+	}
+	for _, id := range signIns {
+		c.sendAuthEvent(ctx, users.LoggedOutEverywhereAuthEventType, id, userID, clientIP)
+	}
+
+	return nil
+}
@@ -19,7 +19,7 @@ import (
 func (c *client) getEmailLinkSignInByPk(ctx context.Context, id *loginID, oldEmail string) (*emailLinkSignIn, error) {
 	userID, err := c.findOrGenerateUserID(ctx, id.Email, oldEmail)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to fetch or generate userID for email:%v", id.Email)
+		return nil, errors.Wrapf(err, "failed to fetch or generate userID for email:%v", users.RedactedEmail(id.Email))
 	}
 	usr, err := c.getUserByIDOrPk(ctx, userID, id)
 	if err != nil {
@@ -49,34 +49,46 @@ func (c *client) getUserIDFromEmail(ctx context.Context, searchEmail, idIfNotFou
 	sql := `SELECT id FROM (
 				SELECT users.id, 1 as idx
 					FROM users 
+						WHERE email = $1 OR email_index = $3
+				UNION ALL
+				(SELECT user_id AS id, 2 as idx
+					FROM secondary_emails
 						WHERE email = $1
+							  AND confirmed_at IS NOT NULL)
 				UNION ALL
-				(SELECT COALESCE(user_id, phone_number_to_email_migration_user_id, $2) AS id, 2 as idx
+				(SELECT COALESCE(user_id, phone_number_to_email_migration_user_id, $2) AS id, 3 as idx
 					FROM email_link_sign_ins
 						WHERE email = $1)
 			) t ORDER BY idx LIMIT 1`
-	ids, err := storage.Select[dbUserID](ctx, c.db, sql, searchEmail, idIfNotFound)
+	ids, err := storage.Select[dbUserID](ctx, c.db, sql, searchEmail, idIfNotFound, c.userModifier.EmailIndex(searchEmail))
 	if err != nil || len(ids) == 0 {
 		if storage.IsErr(err, storage.ErrNotFound) || (err == nil && len(ids) == 0) {
 			return idIfNotFound, nil
 		}
 
-		return "", errors.Wrapf(err, "failed to find user by email:%v", searchEmail)
+		return "", errors.Wrapf(err, "failed to find user by email:%v", users.RedactedEmail(searchEmail))
 	}
 
 	return ids[0].ID, nil
 }
 
+// isUserExist checks both the primary and the confirmed secondary emails, because an email can belong to only one user.
 func (c *client) isUserExist(ctx context.Context, email string) error {
 	type dbUser struct {
 		ID string
 	}
 	sql := `SELECT id 
 				FROM users 
-					WHERE email = $1`
-	_, err := storage.Get[dbUser](ctx, c.db, sql, email)
+					WHERE email = $1 OR email_index = $2
+			UNION ALL
+			SELECT user_id AS id
+				FROM secondary_emails
+					WHERE email = $1
+						  AND confirmed_at IS NOT NULL
+			LIMIT 1`
+	_, err := storage.Get[dbUser](ctx, c.db, sql, email, c.userModifier.EmailIndex(email))
 
-	return errors.Wrapf(err, "failed to find user by email:%v", email)
+	return errors.Wrapf(err, "failed to find user by email:%v", users.RedactedEmail(email))
 }
 
 //nolint:funlen // .
@@ -99,7 +111,9 @@ func (c *client) getUserByIDOrPk(ctx context.Context, userID string, id *loginID
 					device_unique_id,
 					language,
 		    		hash_code,
-		    		metadata
+		    		metadata,
+		    		device_fingerprint,
+		    		confirmation_code_expires_at
 		FROM (
 			WITH emails AS (
 				SELECT
@@ -118,6 +132,8 @@ func (c *client) getUserByIDOrPk(ctx context.Context, userID string, id *loginID
 					'en' 											   AS language,
 					COALESCE((account_metadata.metadata -> 'hash_code')::BIGINT,0) AS hash_code,
 					account_metadata.metadata,
+					device_fingerprint,
+					confirmation_code_expires_at,
 					2                                                  AS idx
 				FROM email_link_sign_ins
 				LEFT JOIN account_metadata ON account_metadata.user_id = $1
@@ -139,6 +155,8 @@ func (c *client) getUserByIDOrPk(ctx context.Context, userID string, id *loginID
 					u.language			    				 	  	   AS language,
 					u.hash_code,
 					account_metadata.metadata    				 	   AS metadata,
+					emails.device_fingerprint 					 	   AS device_fingerprint,
+					emails.confirmation_code_expires_at 		 	   AS confirmation_code_expires_at,
 					1 												   AS idx
 				FROM users u
 				LEFT JOIN emails ON emails.email = $2 and u.id = emails.user_id
@@ -150,6 +168,7 @@ func (c *client) getUserByIDOrPk(ctx context.Context, userID string, id *loginID
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get user by pk:%#v)", id)
 	}
+	usr.Email = c.openEmail(usr.Email)
 
 	return usr, nil
 }
@@ -203,7 +222,7 @@ func (c *client) IceUserID(ctx context.Context, email string) (string, error) {
 	}
 	userID, err := c.getUserIDFromEmail(ctx, email, "")
 	if err != nil {
-		return "", errors.Wrapf(err, "failed to fetch userID by email:%v", email)
+		return "", errors.Wrapf(err, "failed to fetch userID by email:%v", users.RedactedEmail(email))
 	}
 	if strings.HasPrefix(userID, iceIDPrefix) {
 		return userID, nil
@@ -267,9 +286,17 @@ func (c *client) Metadata(ctx context.Context, userID, tokenEmail string) (strin
 		return "", nil, errors.Wrapf(err, "failed to get user metadata %v", userID)
 	}
 	if md.Email != nil {
+		*md.Email = c.openEmail(*md.Email)
 		emailEmpty := *md.Email == "" || *md.Email == *md.UserID
 		if tokenEmail != "" && !emailEmpty && !strings.EqualFold(tokenEmail, *md.Email) { //nolint:gosec // .
-			return "", nil, terror.New(ErrUserDataMismatch, map[string]any{"email": *md.Email})
+			// The token might still have the previous primary email, which is now a secondary one.
+			secondary, sErr := c.isSecondaryEmail(ctx, userID, tokenEmail)
+			if sErr != nil {
+				return "", nil, errors.Wrapf(sErr, "failed to check if email:%v is a secondary email of userID:%v", users.RedactedEmail(tokenEmail), userID)
+			}
+			if !secondary {
+				return "", nil, terror.New(ErrUserDataMismatch, map[string]any{"email": *md.Email})
+			}
 		}
 	}
 	encoded := ""
@@ -281,3 +308,11 @@ func (c *client) Metadata(ctx context.Context, userID, tokenEmail string) (strin
 
 	return encoded, md.Metadata, nil
 }
+
+// openEmail decrypts the primary email, as it's read from the users table, because it's encrypted there, unlike in the tables of this package.
+func (c *client) openEmail(email string) string {
+	info := users.SensitiveUserInformation{Email: email}
+	c.userModifier.OpenSensitiveUserInformation(&info)
+
+	return info.Email
+}
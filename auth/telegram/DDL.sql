@@ -0,0 +1,8 @@
+-- SPDX-License-Identifier: ice License 1.0
+
+CREATE TABLE IF NOT EXISTS telegram_users (
+           created_at                             timestamp NOT NULL,
+           last_signed_in_at                      timestamp NOT NULL,
+           telegram_id                            BIGINT NOT NULL PRIMARY KEY,
+           user_id                                TEXT NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+           telegram_username                      TEXT NOT NULL DEFAULT '');
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package telegram
+
+import (
+	"context"
+	_ "embed"
+	"io"
+	"net"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+// Public API.
+
+type (
+	TokenIssuer interface {
+		IssueTokensWithoutEmail(ctx context.Context, userID, deviceUniqueID string, authEventType users.AuthEventType) (*emaillink.Tokens, error)
+	}
+	UserCreator interface {
+		CreateUser(ctx context.Context, usr *users.User, clientIP net.IP) error
+		DeleteUser(ctx context.Context, userID users.UserID) error
+	}
+	Client interface {
+		io.Closer
+		// SignIn verifies the `initData` of the Telegram Mini App and issues the same tokens as the email link sign in, for the ice user of the Telegram one.
+		// The ice user is created on the first sign in, referred by the user (ID or username) in the `start_param`, if any.
+		SignIn(ctx context.Context, initData, deviceUniqueID string, clientIP net.IP) (*emaillink.Tokens, error)
+	}
+)
+
+var (
+	ErrInvalidInitData = errors.New("invalid init data")
+	ErrExpiredInitData = errors.New("expired init data")
+)
+
+// Private API.
+
+const (
+	applicationYamlKey = "auth/telegram"
+	botTokenEnv        = "TELEGRAM_BOT_TOKEN" //nolint:gosec // It's just the name.
+
+	// As per https://core.telegram.org/bots/webapps#validating-data-received-via-the-mini-app.
+	initDataSecretKey = "WebAppData"
+	hashField         = "hash"
+	authDateField     = "auth_date"
+	userField         = "user"
+	startParamField   = "start_param"
+)
+
+type (
+	client struct {
+		db          *storage.DB
+		cfg         *config
+		tokenIssuer TokenIssuer
+		userCreator UserCreator
+	}
+	config struct {
+		// BotToken is the token of the bot of the Mini App. It's taken from the env, if it's not set.
+		BotToken string `yaml:"botToken" mapstructure:"botToken"`
+		// InitDataTTL is how long the init data is accepted for, after Telegram issued it.
+		InitDataTTL stdlibtime.Duration `yaml:"initDataTTL" mapstructure:"initDataTTL"`
+		Enabled     bool                `yaml:"enabled"`
+	}
+	initData struct {
+		AuthDate   stdlibtime.Time
+		User       *telegramUser
+		StartParam string
+	}
+	telegramUser struct {
+		FirstName    string `json:"first_name"`    //nolint:tagliatelle // As per the Telegram API.
+		LastName     string `json:"last_name"`     //nolint:tagliatelle // As per the Telegram API.
+		Username     string `json:"username"`      //nolint:tagliatelle // As per the Telegram API.
+		LanguageCode string `json:"language_code"` //nolint:tagliatelle // As per the Telegram API.
+		ID           int64  `json:"id"`
+	}
+)
+
+// .
+var (
+	//go:embed DDL.sql
+	ddl string
+)
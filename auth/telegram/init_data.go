@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package telegram
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+)
+
+// parseInitData verifies the signature of the init data with the bot token and parses it.
+// It's accepted only for initDataTTL after Telegram issued it, so that a leaked one can't be replayed forever.
+func (cfg *config) parseInitData(rawInitData string, now stdlibtime.Time) (*initData, error) {
+	values, err := url.ParseQuery(rawInitData)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidInitData, "failed to parse init data: %v", err)
+	}
+	if err = verifyInitDataHash(cfg.BotToken, values); err != nil {
+		return nil, err
+	}
+	authDate, err := strconv.ParseInt(values.Get(authDateField), 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidInitData, "invalid %v %q", authDateField, values.Get(authDateField))
+	}
+	data := &initData{AuthDate: stdlibtime.Unix(authDate, 0), StartParam: values.Get(startParamField)}
+	if now.Sub(data.AuthDate) > cfg.InitDataTTL {
+		return nil, errors.Wrapf(ErrExpiredInitData, "init data was issued at %v", data.AuthDate)
+	}
+	if err = json.Unmarshal([]byte(values.Get(userField)), &data.User); err != nil || data.User == nil || data.User.ID == 0 {
+		return nil, errors.Wrapf(ErrInvalidInitData, "invalid %v %q", userField, values.Get(userField))
+	}
+
+	return data, nil
+}
+
+// verifyInitDataHash checks the hash of the init data, which is the HMAC-SHA256 of its data-check-string,
+// i.e. all the other fields sorted by key, as `key=value` lines, keyed with the HMAC-SHA256 of the bot token keyed with `WebAppData`.
+func verifyInitDataHash(botToken string, values url.Values) error {
+	hash, err := hex.DecodeString(values.Get(hashField))
+	if err != nil || len(hash) != sha256.Size {
+		return errors.Wrapf(ErrInvalidInitData, "invalid %v %q", hashField, values.Get(hashField))
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if key != hashField {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	fields := make([]string, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, key+"="+values.Get(key))
+	}
+	if !hmac.Equal(hash, hmacSHA256(hmacSHA256([]byte(initDataSecretKey), []byte(botToken)), []byte(strings.Join(fields, "\n")))) {
+		return errors.Wrap(ErrInvalidInitData, "hash mismatch")
+	}
+
+	return nil
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
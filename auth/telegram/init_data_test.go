@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package telegram
+
+import (
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"testing"
+	stdlibtime "time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBotToken = "123456:ABC-DEF1234ghIkl-zyx57W2v1u123ew11"
+
+func signedInitData(t *testing.T, botToken string, authDate stdlibtime.Time, user string) url.Values {
+	t.Helper()
+	values := url.Values{
+		authDateField:   {strconv.FormatInt(authDate.Unix(), 10)},
+		"query_id":      {"AAHdF6IQAAAAAN0XohDhrOrc"},
+		startParamField: {"jdoe"},
+		userField:       {user},
+	}
+	dataCheckString := authDateField + "=" + values.Get(authDateField) + "\nquery_id=" + values.Get("query_id") +
+		"\n" + startParamField + "=jdoe\n" + userField + "=" + user
+	values.Set(hashField, hex.EncodeToString(hmacSHA256(hmacSHA256([]byte(initDataSecretKey), []byte(botToken)), []byte(dataCheckString))))
+
+	return values
+}
+
+func TestParseInitData(t *testing.T) {
+	t.Parallel()
+	now := stdlibtime.Now()
+	cfg := &config{BotToken: testBotToken, InitDataTTL: stdlibtime.Hour}
+	user := `{"id":279058397,"first_name":"John","last_name":"Doe","username":"jdoe","language_code":"en-US"}`
+
+	data, err := cfg.parseInitData(signedInitData(t, testBotToken, now, user).Encode(), now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Unix(), data.AuthDate.Unix())
+	assert.Equal(t, "jdoe", data.StartParam)
+	assert.Equal(t, &telegramUser{ID: 279058397, FirstName: "John", LastName: "Doe", Username: "jdoe", LanguageCode: "en-US"}, data.User)
+
+	_, err = cfg.parseInitData(signedInitData(t, "654321:other", now, user).Encode(), now)
+	require.ErrorIs(t, err, ErrInvalidInitData)
+
+	tampered := signedInitData(t, testBotToken, now, user)
+	tampered.Set(startParamField, "someone_else")
+	_, err = cfg.parseInitData(tampered.Encode(), now)
+	require.ErrorIs(t, err, ErrInvalidInitData)
+
+	_, err = cfg.parseInitData(signedInitData(t, testBotToken, now.Add(-2*stdlibtime.Hour), user).Encode(), now)
+	require.ErrorIs(t, err, ErrExpiredInitData)
+
+	_, err = cfg.parseInitData(signedInitData(t, testBotToken, now, `{"first_name":"John"}`).Encode(), now)
+	require.ErrorIs(t, err, ErrInvalidInitData)
+}
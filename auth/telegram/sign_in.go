@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package telegram
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/auth"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (c *client) SignIn(ctx context.Context, rawInitData, deviceUniqueID string, clientIP net.IP) (*emaillink.Tokens, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	now := time.Now()
+	data, err := c.cfg.parseInitData(rawInitData, *now.Time)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify the init data")
+	}
+	userID, err := c.signInTelegramUser(ctx, data, now)
+	if storage.IsErr(err, storage.ErrNotFound) {
+		userID, err = c.createTelegramUser(ctx, data, clientIP, now)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the ice user of telegramID:%v", data.User.ID)
+	}
+	tokens, err := c.tokenIssuer.IssueTokensWithoutEmail(ctx, userID, deviceUniqueID, users.TelegramVerifiedAuthEventType)
+
+	return tokens, errors.Wrapf(err, "failed to issue tokens for userID:%v", userID)
+}
+
+func (c *client) signInTelegramUser(ctx context.Context, data *initData, now *time.Time) (string, error) {
+	sql := `UPDATE telegram_users
+			SET last_signed_in_at = $2,
+				telegram_username = $3
+			WHERE telegram_id = $1
+			RETURNING user_id`
+	res, err := storage.ExecOne[struct {
+		UserID string
+	}](ctx, c.db, sql, data.User.ID, now.Time, data.User.Username)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to update the sign in of telegramID:%v", data.User.ID)
+	}
+
+	return res.UserID, nil
+}
+
+// createTelegramUser creates the ice user of the Telegram one, on its first sign in, and binds them.
+// If a concurrent first sign in binds it first, the created user is deleted and the bound one is used instead.
+func (c *client) createTelegramUser(ctx context.Context, data *initData, clientIP net.IP, now *time.Time) (string, error) {
+	usr := new(users.User) // Its ID is generated by CreateUser.
+	usr.ReferredBy = c.referrer(ctx, data.StartParam)
+	usr.Language, _, _ = strings.Cut(strings.ToLower(data.User.LanguageCode), "-")
+	if data.User.FirstName != "" {
+		usr.FirstName = &data.User.FirstName
+	}
+	if data.User.LastName != "" {
+		usr.LastName = &data.User.LastName
+	}
+	err := c.userCreator.CreateUser(ctx, usr, clientIP)
+	if errors.Is(err, users.ErrProfanity) {
+		// The names are just a convenience, the user can set them later.
+		usr.FirstName, usr.LastName = nil, nil
+		err = c.userCreator.CreateUser(ctx, usr, clientIP)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create the user of telegramID:%v", data.User.ID)
+	}
+	md := users.JSON(map[string]any{auth.IceIDClaim: usr.ID, auth.RegisteredWithProviderClaim: auth.ProviderIce})
+	sql := `WITH metadata_insert AS (
+				INSERT INTO account_metadata (user_id, metadata) VALUES ($3, $5::jsonb)
+				ON CONFLICT (user_id) DO NOTHING
+			)
+			INSERT INTO telegram_users (created_at, last_signed_in_at, telegram_id, user_id, telegram_username)
+			VALUES ($1, $1, $2, $3, $4)
+			ON CONFLICT (telegram_id) DO NOTHING`
+	rowsInserted, err := storage.Exec(ctx, c.db, sql, now.Time, data.User.ID, usr.ID, data.User.Username, md)
+	if err == nil && rowsInserted == 1 {
+		return usr.ID, nil
+	}
+	log.Error(errors.Wrapf(c.userCreator.DeleteUser(ctx, usr.ID), "failed to delete the unbound userID:%v of telegramID:%v", usr.ID, data.User.ID))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to bind userID:%v to telegramID:%v", usr.ID, data.User.ID)
+	}
+
+	return c.signInTelegramUser(ctx, data, now)
+}
+
+// referrer is the user, by ID or username, in the start parameter of the Mini App, if it exists. The user is created without one otherwise.
+func (c *client) referrer(ctx context.Context, startParam string) string {
+	if startParam == "" {
+		return ""
+	}
+	res, err := storage.Get[struct {
+		ID string
+	}](ctx, c.db, `SELECT id FROM users WHERE id = $1 OR username = $1 LIMIT 1`, startParam)
+	if err != nil {
+		if !storage.IsErr(err, storage.ErrNotFound) {
+			log.Error(errors.Wrapf(err, "failed to get the referrer %q", startParam))
+		}
+
+		return ""
+	}
+
+	return res.ID
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package telegram
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// New returns nil if the Telegram sign in is disabled.
+func New(ctx context.Context, tokenIssuer TokenIssuer, userCreator UserCreator) Client {
+	cfg := loadConfiguration()
+	if !cfg.Enabled {
+		return nil
+	}
+	log.Panic(cfg.validate()) //nolint:revive // That's intended.
+	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
+
+	return &client{
+		db:          db,
+		cfg:         cfg,
+		tokenIssuer: tokenIssuer,
+		userCreator: userCreator,
+	}
+}
+
+// ValidateConfig reports, at once, all the problems of the config needed by New.
+func ValidateConfig() error {
+	if cfg := loadConfiguration(); cfg.Enabled {
+		return cfg.validate()
+	}
+
+	return nil
+}
+
+func (c *client) Close() error {
+	return errors.Wrap(c.db.Close(), "closing auth/telegram repository failed")
+}
+
+func loadConfiguration() *config {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
+	if cfg.BotToken == "" {
+		cfg.BotToken = loadFromEnv(applicationYamlKey, botTokenEnv)
+	}
+
+	return &cfg
+}
+
+func (cfg *config) validate() error {
+	var mErr *multierror.Error
+	if cfg.BotToken == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.botToken` is missing, neither is the %v env variable set", applicationYamlKey, botTokenEnv))
+	}
+	if cfg.InitDataTTL <= 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.initDataTTL` must be positive", applicationYamlKey))
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // Not needed.
+}
+
+func loadFromEnv(applicationYAMLKey, env string) string {
+	module := strings.ToUpper(strings.ReplaceAll(strings.ReplaceAll(applicationYAMLKey, "-", "_"), "/", "_"))
+	if val := os.Getenv(module + "_" + env); val != "" {
+		return val
+	}
+
+	return os.Getenv(env)
+}
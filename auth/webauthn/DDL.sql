@@ -0,0 +1,20 @@
+-- SPDX-License-Identifier: ice License 1.0
+
+CREATE TABLE IF NOT EXISTS passkeys (
+           created_at                             timestamp NOT NULL,
+           last_used_at                           timestamp,
+           sign_count                             BIGINT DEFAULT 0 NOT NULL,
+           user_id                                TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+           device_unique_id                       TEXT NOT NULL,
+           credential_id                          TEXT NOT NULL UNIQUE,
+           public_key                             BYTEA NOT NULL,
+           primary key(user_id, device_unique_id))
+           WITH (FILLFACTOR = 70);
+
+CREATE TABLE IF NOT EXISTS passkey_challenges (
+           expires_at                             timestamp NOT NULL,
+           ceremony                               TEXT NOT NULL,
+           user_id                                TEXT NOT NULL DEFAULT '',
+           device_unique_id                       TEXT NOT NULL,
+           challenge                              TEXT NOT NULL PRIMARY KEY);
+CREATE INDEX IF NOT EXISTS passkey_challenges_expires_at_ix ON passkey_challenges (expires_at);
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/pkg/errors"
+	"github.com/ugorji/go/codec"
+)
+
+type (
+	// coseKey is the credential public key, as per https://www.rfc-editor.org/rfc/rfc8152#section-7.
+	coseKey struct {
+		key       crypto.PublicKey
+		algorithm int64
+	}
+)
+
+const (
+	rpIDHashLength       = 32
+	flagsLength          = 1
+	signCountLength      = 4
+	aaguidLength         = 16
+	credentialIDLenBytes = 2
+
+	coseKeyTypeLabel  = 1
+	coseAlgLabel      = 3
+	coseCurveLabel    = -1
+	coseXLabel        = -2
+	coseYLabel        = -3
+	coseRSAModulus    = -1
+	coseRSAExponent   = -2
+	coseOKPKeyType    = 1
+	coseEC2KeyType    = 2
+	coseRSAKeyType    = 3
+	coseP256Curve     = 1
+	coseEd25519Curve  = 6
+	p256CoordinateLen = 32
+)
+
+//nolint:gochecknoglobals // It's stateless.
+var cborHandle = new(codec.CborHandle)
+
+func parseAttestationObject(raw []byte) (*authenticatorData, error) {
+	var att attestationObject
+	if err := codec.NewDecoderBytes(raw, cborHandle).Decode(&att); err != nil {
+		return nil, errors.Wrapf(ErrInvalidCredential, "invalid attestationObject: %v", err)
+	}
+
+	return parseAuthenticatorData(att.AuthData)
+}
+
+// parseAuthenticatorData parses the layout described in https://www.w3.org/TR/webauthn-2/#sctn-authenticator-data.
+func parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	const headerLength = rpIDHashLength + flagsLength + signCountLength
+	if len(raw) < headerLength {
+		return nil, errors.Wrapf(ErrInvalidCredential, "authenticatorData is too short: %v bytes", len(raw))
+	}
+	ad := &authenticatorData{
+		rpIDHash:  raw[:rpIDHashLength],
+		flags:     raw[rpIDHashLength],
+		signCount: binary.BigEndian.Uint32(raw[rpIDHashLength+flagsLength : headerLength]),
+	}
+	if ad.flags&attestedCredentialDataFlag == 0 {
+		return ad, nil
+	}
+	rest := raw[headerLength:]
+	if len(rest) < aaguidLength+credentialIDLenBytes {
+		return nil, errors.Wrap(ErrInvalidCredential, "attested credential data is too short")
+	}
+	credentialIDLength := int(binary.BigEndian.Uint16(rest[aaguidLength : aaguidLength+credentialIDLenBytes]))
+	rest = rest[aaguidLength+credentialIDLenBytes:]
+	if len(rest) < credentialIDLength {
+		return nil, errors.Wrap(ErrInvalidCredential, "credential id is too short")
+	}
+	ad.credentialID, rest = rest[:credentialIDLength], rest[credentialIDLength:]
+	key, read, err := parseCOSEKey(rest)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid credential public key")
+	}
+	ad.publicKey, ad.rawPublicKey = key, rest[:read]
+
+	return ad, nil
+}
+
+// parseCOSEKey returns the key and how many bytes it took, because in the authenticator data it can be followed by extensions.
+func parseCOSEKey(raw []byte) (*coseKey, int, error) {
+	var fields map[int]any
+	dec := codec.NewDecoderBytes(raw, cborHandle)
+	if err := dec.Decode(&fields); err != nil {
+		return nil, 0, errors.Wrapf(ErrInvalidCredential, "invalid COSE key: %v", err)
+	}
+	keyType, algorithm, curve := coseInt(fields[coseKeyTypeLabel]), coseInt(fields[coseAlgLabel]), coseInt(fields[coseCurveLabel])
+	var (
+		key crypto.PublicKey
+		err error
+	)
+	switch {
+	case keyType == coseEC2KeyType && algorithm == es256Algorithm && curve == coseP256Curve:
+		key, err = ecdsaKey(coseBytes(fields[coseXLabel]), coseBytes(fields[coseYLabel]))
+	case keyType == coseOKPKeyType && algorithm == edDSAAlgorithm && curve == coseEd25519Curve:
+		if x := coseBytes(fields[coseXLabel]); len(x) == ed25519.PublicKeySize {
+			key = ed25519.PublicKey(x)
+		} else {
+			err = errors.Wrapf(ErrInvalidCredential, "invalid Ed25519 key length %v", len(x))
+		}
+	case keyType == coseRSAKeyType && algorithm == rs256Algorithm:
+		key, err = rsaKey(coseBytes(fields[coseRSAModulus]), coseBytes(fields[coseRSAExponent]))
+	default:
+		err = errors.Wrapf(ErrInvalidCredential, "unsupported COSE key type %v, algorithm %v, curve %v", keyType, algorithm, curve)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &coseKey{key: key, algorithm: algorithm}, dec.NumBytesRead(), nil
+}
+
+func ecdsaKey(x, y []byte) (*ecdsa.PublicKey, error) {
+	if len(x) != p256CoordinateLen || len(y) != p256CoordinateLen {
+		return nil, errors.Wrap(ErrInvalidCredential, "invalid P-256 coordinates length")
+	}
+	uncompressed := append(append([]byte{0x04}, x...), y...) //nolint:gocritic // It's a new slice.
+	if _, err := ecdh.P256().NewPublicKey(uncompressed); err != nil {
+		return nil, errors.Wrapf(ErrInvalidCredential, "invalid P-256 point: %v", err)
+	}
+
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
+
+func rsaKey(modulus, exponent []byte) (*rsa.PublicKey, error) {
+	const minModulusBits, maxExponentBytes = 2048, 4
+	n, e := new(big.Int).SetBytes(modulus), new(big.Int).SetBytes(exponent)
+	if n.BitLen() < minModulusBits || len(exponent) == 0 || len(exponent) > maxExponentBytes || e.Int64() < 3 {
+		return nil, errors.Wrap(ErrInvalidCredential, "invalid RSA key")
+	}
+
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+// verify checks the assertion signature, over the authenticator data followed by the hash of the clientDataJSON.
+func (k *coseKey) verify(rawAuthenticatorData, rawClientData, signature []byte) error {
+	clientDataHash := sha256.Sum256(rawClientData)
+	signed := make([]byte, 0, len(rawAuthenticatorData)+len(clientDataHash))
+	signed = append(append(signed, rawAuthenticatorData...), clientDataHash[:]...)
+	digest := sha256.Sum256(signed)
+	var valid bool
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, signed, signature)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	}
+	if !valid {
+		return errors.Wrapf(ErrInvalidCredential, "invalid signature for algorithm %v", k.algorithm)
+	}
+
+	return nil
+}
+
+func coseInt(value any) int64 {
+	switch val := value.(type) {
+	case int64:
+		return val
+	case uint64:
+		return int64(val) //nolint:gosec // Overflows don't match any supported label anyway.
+	default:
+		return 0
+	}
+}
+
+func coseBytes(value any) []byte {
+	val, _ := value.([]byte) //nolint:errcheck // Missing or invalid values are rejected by their length.
+
+	return val
+}
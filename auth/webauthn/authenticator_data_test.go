@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+func TestParseAttestationObjectAndVerify(t *testing.T) {
+	t.Parallel()
+	es256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	for name, tc := range map[string]struct {
+		signer crypto.Signer
+		key    map[int]any
+		hash   bool
+	}{
+		"ES256": {
+			signer: es256,
+			key:    map[int]any{coseKeyTypeLabel: coseEC2KeyType, coseAlgLabel: es256Algorithm, coseCurveLabel: coseP256Curve, coseXLabel: es256.X.FillBytes(make([]byte, p256CoordinateLen)), coseYLabel: es256.Y.FillBytes(make([]byte, p256CoordinateLen))}, //nolint:lll // .
+			hash:   true,
+		},
+		"EdDSA": {
+			signer: edPriv,
+			key:    map[int]any{coseKeyTypeLabel: coseOKPKeyType, coseAlgLabel: edDSAAlgorithm, coseCurveLabel: coseEd25519Curve, coseXLabel: []byte(edPub)},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			credentialID := []byte("credential-" + name)
+			rawAuthData := authData(t, "ice.io", userPresentFlag|attestedCredentialDataFlag, 1, credentialID, tc.key)
+			ad, err := parseAttestationObject(encode(t, map[string]any{"fmt": "none", "attStmt": map[string]any{}, "authData": rawAuthData}))
+			require.NoError(t, err)
+			assert.Equal(t, credentialID, ad.credentialID)
+			assert.EqualValues(t, 1, ad.signCount)
+			rpIDHash := sha256.Sum256([]byte("ice.io"))
+			assert.Equal(t, rpIDHash[:], ad.rpIDHash)
+			stored, read, err := parseCOSEKey(ad.rawPublicKey)
+			require.NoError(t, err)
+			assert.Equal(t, len(ad.rawPublicKey), read)
+
+			assertionAuthData := authData(t, "ice.io", userPresentFlag, 2, nil, nil)
+			clientDataJSON := []byte(`{"type":"webauthn.get","challenge":"abc","origin":"https://ice.io"}`)
+			clientDataHash := sha256.Sum256(clientDataJSON)
+			signed := append(append([]byte{}, assertionAuthData...), clientDataHash[:]...) //nolint:gocritic // It's a new slice.
+			digest, opts := signed, crypto.SignerOpts(crypto.Hash(0))
+			if tc.hash {
+				sum := sha256.Sum256(signed)
+				digest, opts = sum[:], crypto.SHA256
+			}
+			signature, err := tc.signer.Sign(rand.Reader, digest, opts)
+			require.NoError(t, err)
+			require.NoError(t, stored.verify(assertionAuthData, clientDataJSON, signature))
+			require.ErrorIs(t, stored.verify(assertionAuthData, []byte(`{"type":"webauthn.get"}`), signature), ErrInvalidCredential)
+		})
+	}
+}
+
+func TestParseAuthenticatorDataInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := parseAuthenticatorData(make([]byte, 36))
+	require.ErrorIs(t, err, ErrInvalidCredential)
+
+	truncated := authData(t, "ice.io", userPresentFlag|attestedCredentialDataFlag, 0, []byte("id"), nil)
+	_, err = parseAuthenticatorData(truncated[:len(truncated)-1])
+	require.ErrorIs(t, err, ErrInvalidCredential)
+
+	unsupported := authData(t, "ice.io", userPresentFlag|attestedCredentialDataFlag, 0, []byte("id"), map[int]any{coseKeyTypeLabel: coseEC2KeyType, coseAlgLabel: -36}) //nolint:lll // .
+	_, err = parseAuthenticatorData(unsupported)
+	require.ErrorIs(t, err, ErrInvalidCredential)
+}
+
+func authData(tb testing.TB, rpID string, flags byte, signCount uint32, credentialID []byte, key map[int]any) []byte {
+	tb.Helper()
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	res := append(rpIDHash[:], flags) //nolint:gocritic // It's a new slice.
+	res = binary.BigEndian.AppendUint32(res, signCount)
+	if flags&attestedCredentialDataFlag == 0 {
+		return res
+	}
+	res = append(res, make([]byte, aaguidLength)...)
+	res = binary.BigEndian.AppendUint16(res, uint16(len(credentialID))) //nolint:gosec // It's short.
+	res = append(res, credentialID...)
+	if key != nil {
+		res = append(res, encode(tb, key)...)
+	}
+
+	return res
+}
+
+func encode(tb testing.TB, value any) []byte {
+	tb.Helper()
+	var res []byte
+	require.NoError(tb, codec.NewEncoderBytes(&res, cborHandle).Encode(value))
+
+	return res
+}
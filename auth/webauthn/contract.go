@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package webauthn
+
+import (
+	"context"
+	_ "embed"
+	"io"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// Public API.
+
+type (
+	TokenIssuer interface {
+		IssueTokens(ctx context.Context, userID, deviceUniqueID string, authEventType users.AuthEventType) (*emaillink.Tokens, error)
+	}
+	Client interface {
+		io.Closer
+		// StartRegistration returns the options for `navigator.credentials.create()`, to register a passkey for the device of the user.
+		StartRegistration(ctx context.Context, userID, deviceUniqueID, userName string) (*CreationOptions, error)
+		// FinishRegistration verifies the new credential against the challenge of StartRegistration and stores it.
+		// Registering a passkey again on the same device replaces the previous one.
+		FinishRegistration(ctx context.Context, userID, deviceUniqueID string, credential *RegistrationCredential) (*Passkey, error)
+		GetPasskeys(ctx context.Context, userID string) ([]*Passkey, error)
+		DeletePasskey(ctx context.Context, userID, credentialID string) error
+		// StartSignIn returns the options for `navigator.credentials.get()`. Passkeys are discoverable, so the user doesn't have to be known yet.
+		StartSignIn(ctx context.Context, deviceUniqueID string) (*RequestOptions, error)
+		// SignIn verifies the assertion against the challenge of StartSignIn and issues the same tokens as the email link sign in.
+		SignIn(ctx context.Context, deviceUniqueID string, credential *AssertionCredential) (*emaillink.Tokens, error)
+	}
+	Passkey struct {
+		CreatedAt      *time.Time `json:"createdAt" example:"2022-01-03T16:20:52.156534Z"`
+		LastUsedAt     *time.Time `json:"lastUsedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		UserID         string     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		DeviceUniqueID string     `json:"deviceUniqueId" example:"6FB988F3-36F4-433D-9C7C-555887E57EB2" db:"device_unique_id"`
+		CredentialID   string     `json:"credentialId" example:"KEbWNCc7NgaYnUyrNeFGX9_3Y-8oJ3KwzjnaiD1d1LU" db:"credential_id"`
+		PublicKey      []byte     `json:"-" db:"public_key"`
+		SignCount      int64      `json:"-" db:"sign_count"`
+	}
+	CreationOptions struct {
+		RelyingParty           *RelyingParty           `json:"rp"`
+		User                   *UserEntity             `json:"user"`
+		AuthenticatorSelection *AuthenticatorSelection `json:"authenticatorSelection"`
+		Challenge              string                  `json:"challenge" example:"P1c8bVtCzAyQWsU2qQJ0Hl-0jcPvHszOjPPbwBYCJ7E"`
+		Attestation            string                  `json:"attestation" example:"none"`
+		PubKeyCredParams       []*CredentialParameter  `json:"pubKeyCredParams"`
+		ExcludeCredentials     []*CredentialDescriptor `json:"excludeCredentials"`
+		// Timeout is in milliseconds.
+		Timeout int64 `json:"timeout" example:"300000"`
+	}
+	RequestOptions struct {
+		Challenge        string `json:"challenge" example:"P1c8bVtCzAyQWsU2qQJ0Hl-0jcPvHszOjPPbwBYCJ7E"`
+		RelyingPartyID   string `json:"rpId" example:"ice.io"` //nolint:tagliatelle // As per the WebAuthn spec.
+		UserVerification string `json:"userVerification" example:"preferred" enums:"required,preferred"`
+		// Timeout is in milliseconds.
+		Timeout int64 `json:"timeout" example:"300000"`
+	}
+	RelyingParty struct {
+		ID   string `json:"id" example:"ice.io"`
+		Name string `json:"name" example:"ice"`
+	}
+	UserEntity struct {
+		// ID is the base64url encoded userID, the same one returned as `userHandle` by the assertions.
+		ID          string `json:"id" example:"ZGlkOmV0aHI6MHg0QjczQzU4MzcwQUVmY0VmODZBNjAyMWFmQ0RlNTY3MzUxMTM3NkIy"`
+		Name        string `json:"name" example:"someone1@example.com"`
+		DisplayName string `json:"displayName" example:"someone1@example.com"`
+	}
+	CredentialParameter struct {
+		Type      string `json:"type" example:"public-key"`
+		Algorithm int64  `json:"alg" example:"-7"`
+	}
+	CredentialDescriptor struct {
+		Type string `json:"type" example:"public-key"`
+		ID   string `json:"id" example:"KEbWNCc7NgaYnUyrNeFGX9_3Y-8oJ3KwzjnaiD1d1LU"`
+	}
+	AuthenticatorSelection struct {
+		ResidentKey      string `json:"residentKey" example:"required"`
+		UserVerification string `json:"userVerification" example:"preferred" enums:"required,preferred"`
+	}
+	// RegistrationCredential is the `PublicKeyCredential` returned by `navigator.credentials.create()`, with the binary fields base64url encoded.
+	RegistrationCredential struct {
+		ID       string `json:"id" example:"KEbWNCc7NgaYnUyrNeFGX9_3Y-8oJ3KwzjnaiD1d1LU"`
+		Type     string `json:"type" example:"public-key"`
+		Response struct {
+			ClientDataJSON    string `json:"clientDataJSON"` //nolint:tagliatelle // As per the WebAuthn spec.
+			AttestationObject string `json:"attestationObject"`
+		} `json:"response"`
+	}
+	// AssertionCredential is the `PublicKeyCredential` returned by `navigator.credentials.get()`, with the binary fields base64url encoded.
+	AssertionCredential struct {
+		ID       string `json:"id" example:"KEbWNCc7NgaYnUyrNeFGX9_3Y-8oJ3KwzjnaiD1d1LU"`
+		Type     string `json:"type" example:"public-key"`
+		Response struct {
+			ClientDataJSON    string `json:"clientDataJSON"` //nolint:tagliatelle // As per the WebAuthn spec.
+			AuthenticatorData string `json:"authenticatorData"`
+			Signature         string `json:"signature"`
+			UserHandle        string `json:"userHandle,omitempty"`
+		} `json:"response"`
+	}
+)
+
+var (
+	ErrChallengeNotFound = errors.New("challenge not found or expired")
+	ErrInvalidCredential = errors.New("invalid credential")
+	ErrPasskeyNotFound   = storage.ErrNotFound
+	ErrPasskeyDuplicate  = errors.New("passkey already registered")
+	ErrUserNotFound      = storage.ErrRelationNotFound
+)
+
+// Private API.
+
+const (
+	applicationYamlKey = "auth/webauthn"
+
+	registrationCeremony ceremony = "webauthn.create"
+	assertionCeremony    ceremony = "webauthn.get"
+
+	publicKeyCredentialType = "public-key"
+	challengeLength         = 32
+
+	requiredUserVerification  = "required"
+	preferredUserVerification = "preferred"
+
+	// As per https://www.w3.org/TR/webauthn-2/#sctn-authenticator-data.
+	userPresentFlag            byte = 0x01
+	userVerifiedFlag           byte = 0x04
+	attestedCredentialDataFlag byte = 0x40
+
+	// As per https://www.iana.org/assignments/cose/cose.xhtml.
+	es256Algorithm int64 = -7
+	edDSAAlgorithm int64 = -8
+	rs256Algorithm int64 = -257
+)
+
+type (
+	ceremony string
+	client   struct {
+		db          *storage.DB
+		cfg         *config
+		tokenIssuer TokenIssuer
+	}
+	config struct {
+		RelyingParty struct {
+			ID   string `yaml:"id"`
+			Name string `yaml:"name"`
+		} `yaml:"relyingParty" mapstructure:"relyingParty"`
+		// Origins are the ones the clients are allowed to run the ceremonies from, i.e. https://ice.io or android:apk-key-hash:...
+		Origins                 []string            `yaml:"origins"`
+		ChallengeTTL            stdlibtime.Duration `yaml:"challengeTTL" mapstructure:"challengeTTL"`
+		RequireUserVerification bool                `yaml:"requireUserVerification" mapstructure:"requireUserVerification"`
+		Enabled                 bool                `yaml:"enabled"`
+	}
+	challenge struct {
+		ExpiresAt      *time.Time
+		Ceremony       ceremony
+		UserID         string
+		DeviceUniqueID string `db:"device_unique_id"`
+		Challenge      string
+	}
+	clientData struct {
+		Type      ceremony `json:"type"`
+		Challenge string   `json:"challenge"`
+		Origin    string   `json:"origin"`
+	}
+	attestationObject struct {
+		Format   string `codec:"fmt"`
+		AuthData []byte `codec:"authData"`
+	}
+	authenticatorData struct {
+		publicKey    *coseKey
+		rpIDHash     []byte
+		credentialID []byte
+		rawPublicKey []byte
+		signCount    uint32
+		flags        byte
+	}
+)
+
+// .
+var (
+	//go:embed DDL.sql
+	ddl string
+)
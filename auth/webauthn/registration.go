@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package webauthn
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (c *client) StartRegistration(ctx context.Context, userID, deviceUniqueID, userName string) (*CreationOptions, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	existing, err := c.GetPasskeys(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get passkeys of userID:%v", userID)
+	}
+	value, err := c.newChallenge(ctx, registrationCeremony, userID, deviceUniqueID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create registration challenge for userID:%v", userID)
+	}
+	excluded := make([]*CredentialDescriptor, 0, len(existing))
+	for _, pk := range existing {
+		// The passkey of the same device is replaced, so it's not excluded.
+		if pk.DeviceUniqueID != deviceUniqueID {
+			excluded = append(excluded, &CredentialDescriptor{Type: publicKeyCredentialType, ID: pk.CredentialID})
+		}
+	}
+
+	return &CreationOptions{
+		Challenge:    value,
+		RelyingParty: &RelyingParty{ID: c.cfg.RelyingParty.ID, Name: c.cfg.RelyingParty.Name},
+		User: &UserEntity{
+			ID:          base64.RawURLEncoding.EncodeToString([]byte(userID)),
+			Name:        userName,
+			DisplayName: userName,
+		},
+		PubKeyCredParams: []*CredentialParameter{
+			{Type: publicKeyCredentialType, Algorithm: es256Algorithm},
+			{Type: publicKeyCredentialType, Algorithm: edDSAAlgorithm},
+			{Type: publicKeyCredentialType, Algorithm: rs256Algorithm},
+		},
+		ExcludeCredentials: excluded,
+		AuthenticatorSelection: &AuthenticatorSelection{
+			ResidentKey:      "required",
+			UserVerification: c.cfg.userVerification(),
+		},
+		// Attestation statements are not verified, the passkeys are trusted the same way the email is.
+		Attestation: "none",
+		Timeout:     c.cfg.ChallengeTTL.Milliseconds(),
+	}, nil
+}
+
+func (c *client) FinishRegistration(ctx context.Context, userID, deviceUniqueID string, credential *RegistrationCredential) (*Passkey, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	if credential.Type != publicKeyCredentialType {
+		return nil, errors.Wrapf(ErrInvalidCredential, "unexpected credential type %v", credential.Type)
+	}
+	rawClientData, err := decode("clientDataJSON", credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	rawAttestationObject, err := decode("attestationObject", credential.Response.AttestationObject)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.verifyClientData(ctx, registrationCeremony, userID, deviceUniqueID, rawClientData); err != nil {
+		return nil, errors.Wrapf(err, "invalid registration clientDataJSON for userID:%v", userID)
+	}
+	ad, err := parseAttestationObject(rawAttestationObject)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid attestationObject for userID:%v", userID)
+	}
+	if err = c.verifyAuthenticatorData(ad); err != nil {
+		return nil, errors.Wrapf(err, "invalid authenticatorData for userID:%v", userID)
+	}
+	if ad.publicKey == nil || base64.RawURLEncoding.EncodeToString(ad.credentialID) != credential.ID {
+		return nil, errors.Wrapf(ErrInvalidCredential, "attested credential data doesn't match credential %v", credential.ID)
+	}
+	pk := &Passkey{
+		CreatedAt:      time.Now(),
+		UserID:         userID,
+		DeviceUniqueID: deviceUniqueID,
+		CredentialID:   credential.ID,
+		PublicKey:      ad.rawPublicKey,
+		SignCount:      int64(ad.signCount),
+	}
+
+	return pk, errors.Wrapf(c.upsertPasskey(ctx, pk), "failed to save passkey for userID:%v", userID)
+}
+
+func (c *client) upsertPasskey(ctx context.Context, pk *Passkey) error {
+	sql := `INSERT INTO passkeys (created_at, sign_count, user_id, device_unique_id, credential_id, public_key)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (user_id, device_unique_id) DO UPDATE
+				SET created_at = EXCLUDED.created_at,
+					last_used_at = NULL,
+					sign_count = EXCLUDED.sign_count,
+					credential_id = EXCLUDED.credential_id,
+					public_key = EXCLUDED.public_key`
+	_, err := storage.Exec(ctx, c.db, sql, pk.CreatedAt.Time, pk.SignCount, pk.UserID, pk.DeviceUniqueID, pk.CredentialID, pk.PublicKey)
+	if storage.IsErr(err, storage.ErrDuplicate) {
+		return errors.Wrapf(ErrPasskeyDuplicate, "credential %v is already registered", pk.CredentialID)
+	}
+
+	return errors.Wrapf(err, "failed to upsert passkey %#v", pk)
+}
+
+func (c *client) GetPasskeys(ctx context.Context, userID string) ([]*Passkey, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT * FROM passkeys WHERE user_id = $1 ORDER BY created_at`
+	res, err := storage.Select[Passkey](ctx, c.db, sql, userID)
+
+	return res, errors.Wrapf(err, "failed to select passkeys of userID:%v", userID)
+}
+
+func (c *client) DeletePasskey(ctx context.Context, userID, credentialID string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `DELETE FROM passkeys WHERE user_id = $1 AND credential_id = $2`
+	if deleted, err := storage.Exec(ctx, c.db, sql, userID, credentialID); err != nil {
+		return errors.Wrapf(err, "failed to delete passkey %v of userID:%v", credentialID, userID)
+	} else if deleted == 0 {
+		return errors.Wrapf(ErrPasskeyNotFound, "passkey %v of userID:%v not found", credentialID, userID)
+	}
+
+	return nil
+}
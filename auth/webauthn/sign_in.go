@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package webauthn
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (c *client) StartSignIn(ctx context.Context, deviceUniqueID string) (*RequestOptions, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	value, err := c.newChallenge(ctx, assertionCeremony, "", deviceUniqueID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create sign in challenge for deviceUniqueID:%v", deviceUniqueID)
+	}
+
+	return &RequestOptions{
+		Challenge:        value,
+		RelyingPartyID:   c.cfg.RelyingParty.ID,
+		UserVerification: c.cfg.userVerification(),
+		Timeout:          c.cfg.ChallengeTTL.Milliseconds(),
+	}, nil
+}
+
+//nolint:funlen // .
+func (c *client) SignIn(ctx context.Context, deviceUniqueID string, credential *AssertionCredential) (*emaillink.Tokens, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	if credential.Type != publicKeyCredentialType {
+		return nil, errors.Wrapf(ErrInvalidCredential, "unexpected credential type %v", credential.Type)
+	}
+	rawClientData, err := decode("clientDataJSON", credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	rawAuthenticatorData, err := decode("authenticatorData", credential.Response.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := decode("signature", credential.Response.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.verifyClientData(ctx, assertionCeremony, "", deviceUniqueID, rawClientData); err != nil {
+		return nil, errors.Wrapf(err, "invalid sign in clientDataJSON for credential %v", credential.ID)
+	}
+	pk, err := storage.Get[Passkey](ctx, c.db, `SELECT * FROM passkeys WHERE credential_id = $1`, credential.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get passkey %v", credential.ID)
+	}
+	if credential.Response.UserHandle != "" {
+		if userHandle, dErr := decode("userHandle", credential.Response.UserHandle); dErr != nil || string(userHandle) != pk.UserID {
+			return nil, errors.Wrapf(ErrInvalidCredential, "userHandle doesn't match the owner of passkey %v", credential.ID)
+		}
+	}
+	ad, err := parseAuthenticatorData(rawAuthenticatorData)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid authenticatorData for credential %v", credential.ID)
+	}
+	if err = c.verifyAuthenticatorData(ad); err != nil {
+		return nil, errors.Wrapf(err, "invalid authenticatorData for credential %v", credential.ID)
+	}
+	key, _, err := parseCOSEKey(pk.PublicKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid stored public key of passkey %v", credential.ID)
+	}
+	if err = key.verify(rawAuthenticatorData, rawClientData, signature); err != nil {
+		return nil, errors.Wrapf(err, "failed to verify assertion of passkey %v", credential.ID)
+	}
+	// Authenticators that don't count their signatures always report 0. Otherwise, a non increasing counter means a cloned authenticator.
+	if (ad.signCount != 0 || pk.SignCount != 0) && int64(ad.signCount) <= pk.SignCount {
+		return nil, errors.Wrapf(ErrInvalidCredential, "sign count %v of passkey %v didn't increase from %v", ad.signCount, credential.ID, pk.SignCount)
+	}
+	sql := `UPDATE passkeys SET sign_count = $2, last_used_at = $3 WHERE credential_id = $1`
+	if _, err = storage.Exec(ctx, c.db, sql, credential.ID, int64(ad.signCount), time.Now().Time); err != nil {
+		return nil, errors.Wrapf(err, "failed to update sign count of passkey %v", credential.ID)
+	}
+	tokens, err := c.tokenIssuer.IssueTokens(ctx, pk.UserID, deviceUniqueID, users.PasskeyVerifiedAuthEventType)
+
+	return tokens, errors.Wrapf(err, "failed to issue tokens for userID:%v", pk.UserID)
+}
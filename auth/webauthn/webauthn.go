@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package webauthn
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// New returns nil if passkeys are disabled.
+func New(ctx context.Context, tokenIssuer TokenIssuer) Client {
+	cfg := loadConfiguration()
+	if !cfg.Enabled {
+		return nil
+	}
+	log.Panic(cfg.validate()) //nolint:revive // That's intended.
+	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
+
+	return &client{
+		db:          db,
+		cfg:         cfg,
+		tokenIssuer: tokenIssuer,
+	}
+}
+
+// ValidateConfig reports, at once, all the problems of the config needed by New.
+func ValidateConfig() error {
+	if cfg := loadConfiguration(); cfg.Enabled {
+		return cfg.validate()
+	}
+
+	return nil
+}
+
+func (c *client) Close() error {
+	return errors.Wrap(c.db.Close(), "closing auth/webauthn repository failed")
+}
+
+func loadConfiguration() *config {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
+
+	return &cfg
+}
+
+func (cfg *config) validate() error {
+	var mErr *multierror.Error
+	if cfg.RelyingParty.ID == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.relyingParty.id` is missing", applicationYamlKey))
+	}
+	if cfg.RelyingParty.Name == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.relyingParty.name` is missing", applicationYamlKey))
+	}
+	if len(cfg.Origins) == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.origins` is missing", applicationYamlKey))
+	}
+	if cfg.ChallengeTTL <= 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.challengeTTL` must be positive", applicationYamlKey))
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // Not needed.
+}
+
+func (cfg *config) userVerification() string {
+	if cfg.RequireUserVerification {
+		return requiredUserVerification
+	}
+
+	return preferredUserVerification
+}
+
+// newChallenge stores a single use challenge for the ceremony. The expired ones are cleaned up along the way.
+func (c *client) newChallenge(ctx context.Context, cer ceremony, userID, deviceUniqueID string) (string, error) {
+	value := make([]byte, challengeLength)
+	if _, err := rand.Read(value); err != nil {
+		return "", errors.Wrap(err, "failed to generate challenge")
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(value)
+	now := time.Now()
+	sql := `WITH expired AS (
+				DELETE FROM passkey_challenges WHERE expires_at < $1
+			)
+			INSERT INTO passkey_challenges (expires_at, ceremony, user_id, device_unique_id, challenge) VALUES ($2, $3, $4, $5, $6)`
+	if _, err := storage.Exec(ctx, c.db, sql, now.Time, now.Add(c.cfg.ChallengeTTL), cer, userID, deviceUniqueID, encoded); err != nil {
+		return "", errors.Wrapf(err, "failed to insert %v challenge for userID:%v, deviceUniqueID:%v", cer, userID, deviceUniqueID)
+	}
+
+	return encoded, nil
+}
+
+// consumeChallenge deletes the challenge, so it can't be replayed, even if the ceremony fails afterwards.
+func (c *client) consumeChallenge(ctx context.Context, cer ceremony, userID, deviceUniqueID, value string) error {
+	sql := `DELETE FROM passkey_challenges WHERE challenge = $1 RETURNING *`
+	ch, err := storage.ExecOne[challenge](ctx, c.db, sql, value)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return errors.Wrapf(ErrChallengeNotFound, "challenge %v not found", value)
+		}
+
+		return errors.Wrapf(err, "failed to delete challenge %v", value)
+	}
+	if ch.Ceremony != cer || ch.UserID != userID || ch.DeviceUniqueID != deviceUniqueID || ch.ExpiresAt.Before(*time.Now().Time) {
+		return errors.Wrapf(ErrChallengeNotFound, "challenge %#v doesn't match %v for userID:%v, deviceUniqueID:%v", ch, cer, userID, deviceUniqueID)
+	}
+
+	return nil
+}
+
+// verifyClientData checks the clientDataJSON signed by the authenticator and consumes its challenge.
+func (c *client) verifyClientData(ctx context.Context, cer ceremony, userID, deviceUniqueID string, raw []byte) error {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return errors.Wrapf(ErrInvalidCredential, "invalid clientDataJSON: %v", err)
+	}
+	if cd.Type != cer {
+		return errors.Wrapf(ErrInvalidCredential, "unexpected clientDataJSON type %v, expected %v", cd.Type, cer)
+	}
+	if !slices.Contains(c.cfg.Origins, cd.Origin) {
+		return errors.Wrapf(ErrInvalidCredential, "origin %v is not allowed", cd.Origin)
+	}
+
+	return errors.Wrapf(c.consumeChallenge(ctx, cer, userID, deviceUniqueID, cd.Challenge), "invalid challenge for %v", cer)
+}
+
+func (c *client) verifyAuthenticatorData(ad *authenticatorData) error {
+	rpIDHash := sha256.Sum256([]byte(c.cfg.RelyingParty.ID))
+	if !bytes.Equal(ad.rpIDHash, rpIDHash[:]) {
+		return errors.Wrap(ErrInvalidCredential, "rpIdHash mismatch")
+	}
+	if ad.flags&userPresentFlag == 0 {
+		return errors.Wrap(ErrInvalidCredential, "user not present")
+	}
+	if c.cfg.RequireUserVerification && ad.flags&userVerifiedFlag == 0 {
+		return errors.Wrap(ErrInvalidCredential, "user not verified")
+	}
+
+	return nil
+}
+
+// decode accepts base64url, as per the WebAuthn JSON serialization, with or without padding.
+func decode(field, value string) ([]byte, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil || len(decoded) == 0 {
+		return nil, errors.Wrapf(ErrInvalidCredential, "invalid base64url %v", field)
+	}
+
+	return decoded, nil
+}
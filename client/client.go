@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func NewReadClient(cfg *Config, tokens TokenProvider) ReadClient {
+	return &readClient{client: newClient(cfg, tokens)}
+}
+
+func NewWriteClient(cfg *Config, tokens TokenProvider) WriteClient {
+	return &writeClient{client: newClient(cfg, tokens)}
+}
+
+// StaticTokens always provides the same tokens, e.g. the ones of a service account.
+func StaticTokens(accessToken, metadataToken string) TokenProvider {
+	return func(context.Context) (*Tokens, error) {
+		return &Tokens{AccessToken: accessToken, MetadataToken: metadataToken}, nil
+	}
+}
+
+// ContextWithTokens makes the requests using ctx send these tokens instead of the ones from the TokenProvider,
+// e.g. when calling on behalf of an user.
+func ContextWithTokens(ctx context.Context, tokens *Tokens) context.Context {
+	return context.WithValue(ctx, tokensCtxValueKey, tokens)
+}
+
+// IsErrorCode checks if err is an *Error with the provided code. The codes are listed by ReadClient.GetErrorCatalog.
+func IsErrorCode(err error, code string) bool {
+	var clientErr *Error
+
+	return errors.As(err, &clientErr) && clientErr.Code == code
+}
+
+// ForEachPage calls fetch with increasing offsets and visits every page until a page is smaller than pageSize.
+func ForEachPage[T any](ctx context.Context, pageSize uint64, fetch PageFetcher[T], visit func(page []*T) error) error {
+	for offset := uint64(0); ; offset += pageSize {
+		if ctx.Err() != nil {
+			return errors.Wrap(ctx.Err(), "context failed")
+		}
+		page, err := fetch(ctx, pageSize, offset)
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch page with limit:%v, offset:%v", pageSize, offset)
+		}
+		if len(page) != 0 {
+			if err = visit(page); err != nil {
+				return errors.Wrapf(err, "failed to visit page with limit:%v, offset:%v", pageSize, offset)
+			}
+		}
+		if uint64(len(page)) < pageSize {
+			return nil
+		}
+	}
+}
+
+// All collects all the pages via ForEachPage.
+func All[T any](ctx context.Context, pageSize uint64, fetch PageFetcher[T]) ([]*T, error) {
+	var all []*T
+
+	return all, ForEachPage(ctx, pageSize, fetch, func(page []*T) error {
+		all = append(all, page...)
+
+		return nil
+	})
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("[%v]%v %v failed with code:%v, message:%v", e.StatusCode, e.Method, e.URL, e.Code, e.Message)
+}
+
+func newClient(cfg *Config, tokens TokenProvider) *client {
+	timeout, retryCount, retryMinBackoff, retryMaxBackoff := cfg.Timeout, cfg.RetryCount, cfg.RetryMinBackoff, cfg.RetryMaxBackoff
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	if retryCount == 0 {
+		retryCount = defaultRetryCount
+	}
+	if cfg.DisableRetries {
+		retryCount = 0
+	}
+	if retryMinBackoff == 0 {
+		retryMinBackoff = defaultRetryMinBackoff
+	}
+	if retryMaxBackoff == 0 {
+		retryMaxBackoff = defaultRetryMaxBackoff
+	}
+	httpClient := req.C().
+		SetBaseURL(strings.TrimSuffix(cfg.BaseURL, "/")).
+		SetTimeout(timeout).
+		SetJsonMarshal(json.Marshal).
+		SetJsonUnmarshal(json.Unmarshal).
+		SetCommonRetryCount(retryCount).
+		SetCommonRetryBackoffInterval(retryMinBackoff, retryMaxBackoff).
+		SetCommonRetryCondition(shouldRetry).
+		OnBeforeRequest(func(_ *req.Client, r *req.Request) error {
+			return errors.Wrap(setTokens(r, tokens), "failed to setTokens")
+		})
+
+	return &client{http: httpClient}
+}
+
+func setTokens(r *req.Request, provider TokenProvider) error {
+	tokens, found := r.Context().Value(tokensCtxValueKey).(*Tokens)
+	if !found && provider != nil {
+		var err error
+		if tokens, err = provider(r.Context()); err != nil {
+			return errors.Wrap(err, "failed to get tokens")
+		}
+	}
+	if tokens == nil {
+		return nil
+	}
+	if tokens.AccessToken != "" {
+		r.SetBearerAuthToken(tokens.AccessToken)
+	}
+	if tokens.MetadataToken != "" {
+		r.SetHeader("X-Account-Metadata", tokens.MetadataToken)
+	}
+
+	return nil
+}
+
+// shouldRetry retries transport errors and 5xx responses only for idempotent requests,
+// because the others might have been processed already. 429 and 503 are always retried, cuz they're rejected before processing.
+func shouldRetry(resp *req.Response, err error) bool {
+	if resp == nil || resp.Request == nil {
+		return false
+	}
+	if resp.Request.Context().Err() != nil {
+		return false
+	}
+	idempotent := resp.Request.Method != http.MethodPost && resp.Request.Method != http.MethodPatch
+	if err != nil {
+		return idempotent
+	}
+	switch resp.GetStatusCode() {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// execute sends the request and decodes the response into RESP, or into an *Error, if not successful.
+// The body is decoded manually, because the failed decoding of a body would stop the retries.
+func execute[RESP any](ctx context.Context, r *req.Request, method, url string) (*RESP, error) {
+	resp, err := r.SetContext(ctx).Send(method, url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%v %v failed", method, url)
+	}
+	body, err := resp.ToBytes()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the response body of %v %v", method, url)
+	}
+	if resp.IsErrorState() {
+		var errResp server.ErrorResponse
+		if json.Unmarshal(body, &errResp) != nil {
+			errResp.Error = string(body)
+		}
+
+		return nil, &Error{
+			StatusCode: resp.GetStatusCode(),
+			Method:     method,
+			URL:        resp.Request.RawURL,
+			Code:       errResp.Code,
+			Message:    errResp.Error,
+			Data:       errResp.Data,
+		}
+	}
+	var result RESP
+	if len(body) != 0 {
+		if err = json.Unmarshal(body, &result); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal the response body of %v %v", method, url)
+		}
+	}
+
+	return &result, nil
+}
+
+func setOptionalQueryParam(r *req.Request, key, value string) {
+	if value != "" {
+		r.SetQueryParam(key, value)
+	}
+}
+
+func deref[T any](res *[]T) []T {
+	if res == nil {
+		return nil
+	}
+
+	return *res
+}
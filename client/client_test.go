@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	stdlibtime "time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ice-blockchain/eskimo/users"
+)
+
+func testConfig(baseURL string) *Config {
+	return &Config{BaseURL: baseURL, RetryMinBackoff: stdlibtime.Millisecond, RetryMaxBackoff: stdlibtime.Millisecond}
+}
+
+func TestTokensInjection(t *testing.T) {
+	t.Parallel()
+	var authorization, metadata atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization.Store(r.Header.Get("Authorization"))
+		metadata.Store(r.Header.Get("X-Account-Metadata"))
+		_, _ = w.Write([]byte(`[]`)) //nolint:errcheck // Not needed.
+	}))
+	defer srv.Close()
+	client := NewReadClient(testConfig(srv.URL), StaticTokens("service-token", ""))
+
+	_, err := client.GetErrorCatalog(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer service-token", authorization.Load())
+	assert.Equal(t, "", metadata.Load())
+
+	_, err = client.GetErrorCatalog(ContextWithTokens(context.Background(), &Tokens{AccessToken: "user-token", MetadataToken: "user-metadata"}))
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer user-token", authorization.Load())
+	assert.Equal(t, "user-metadata", metadata.Load())
+}
+
+func TestRetries(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2: //nolint:gomnd // .
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`{"id":"bogus","checksum":"123"}`)) //nolint:errcheck // Not needed.
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	usr, err := NewReadClient(testConfig(srv.URL), nil).GetUserByID(context.Background(), "bogus")
+	require.NoError(t, err)
+	assert.Equal(t, "bogus", usr.ID)
+	assert.Equal(t, "123", usr.Checksum)
+	assert.EqualValues(t, 3, calls.Load())
+
+	calls.Store(0)
+	_, err = NewWriteClient(testConfig(srv.URL), nil).CreateUser(context.Background(), &CreateUserArg{Email: "jdoe@gmail.com"})
+	require.Error(t, err)
+	assert.EqualValues(t, 2, calls.Load()) // The 503 is retried, but the 500 isn't, cuz it's not idempotent.
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error":"username already taken","code":"CONFLICT_WITH_ANOTHER_USER","data":{"field":"username"}}`)) //nolint:errcheck // .
+	}))
+	defer srv.Close()
+
+	_, err := NewWriteClient(testConfig(srv.URL), nil).ModifyUser(context.Background(), &ModifyUserArg{UserID: "bogus", Username: "jdoe"})
+	require.Error(t, err)
+	assert.True(t, IsErrorCode(err, "CONFLICT_WITH_ANOTHER_USER"))
+	assert.False(t, IsErrorCode(err, "USER_NOT_FOUND"))
+	var clientErr *Error
+	require.ErrorAs(t, err, &clientErr)
+	assert.Equal(t, http.StatusConflict, clientErr.StatusCode)
+	assert.Equal(t, "username", clientErr.Data["field"])
+}
+
+func TestModifyUserFormData(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1w/users/did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2", r.URL.Path)
+		assert.NoError(t, r.ParseMultipartForm(1024))
+		assert.Equal(t, []string{"level", "badges"}, r.MultipartForm.Value["hiddenProfileElements"])
+		assert.Equal(t, []string{"true"}, r.MultipartForm.Value["resetProfilePicture"])
+		assert.Equal(t, []string{`{"a":"b"}`}, r.MultipartForm.Value["clientData"])
+		assert.Equal(t, []string{"RO"}, r.MultipartForm.Value["country"])
+		assert.NotContains(t, r.MultipartForm.Value, "city")
+		assert.Equal(t, "p.jpg", r.MultipartForm.File["profilePicture"][0].Filename)
+		_, _ = w.Write([]byte(`{"id":"bogus","loginSession":"session"}`)) //nolint:errcheck // Not needed.
+	}))
+	defer srv.Close()
+
+	resetProfilePicture := true
+	usr, err := NewWriteClient(testConfig(srv.URL), nil).ModifyUser(context.Background(), &ModifyUserArg{
+		UserID:                "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2",
+		Country:               "RO",
+		ResetProfilePicture:   &resetProfilePicture,
+		HiddenProfileElements: &users.Enum[users.HiddenProfileElement]{"level", "badges"},
+		ClientData:            &users.JSON{"a": "b"},
+		ProfilePicture:        []byte("bogus"),
+		ProfilePictureName:    "p.jpg",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "bogus", usr.ID)
+	assert.Equal(t, "session", usr.LoginSession)
+}
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+	const total = 25
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, offset := r.URL.Query().Get("limit"), r.URL.Query().Get("offset")
+		var l, o int
+		require.NoError(t, json.Unmarshal([]byte(limit), &l))
+		require.NoError(t, json.Unmarshal([]byte(offset), &o))
+		page := make([]*users.CountryChange, 0, l)
+		for i := o; i < o+l && i < total; i++ {
+			page = append(page, &users.CountryChange{ToCountry: "RO"})
+		}
+		body, err := json.Marshal(page)
+		require.NoError(t, err)
+		_, _ = w.Write(body) //nolint:errcheck // Not needed.
+	}))
+	defer srv.Close()
+	client := NewReadClient(testConfig(srv.URL), nil)
+
+	all, err := All(context.Background(), 10, client.GetPendingCountryChanges)
+	require.NoError(t, err)
+	assert.Len(t, all, total)
+
+	var pages int
+	require.NoError(t, ForEachPage(context.Background(), 5, client.GetPendingCountryChanges, func([]*users.CountryChange) error {
+		pages++
+
+		return nil
+	}))
+	assert.Equal(t, 5, pages)
+}
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package client
+
+import (
+	"context"
+	stdlibtime "time"
+
+	"github.com/imroc/req/v3"
+
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	kycquiz "github.com/ice-blockchain/eskimo/kyc/quiz"
+	"github.com/ice-blockchain/eskimo/users"
+)
+
+// Public API.
+
+type (
+	// ReadClient calls the read only API (eskimo).
+	ReadClient interface {
+		GetUsers(ctx context.Context, arg *GetUsersArg) ([]*users.MinimalUserProfile, error)
+		GetUserByID(ctx context.Context, userID string) (*UserProfile, error)
+		GetUserByUsername(ctx context.Context, username string) (*users.UserProfile, error)
+
+		GetReferrals(ctx context.Context, arg *GetReferralsArg) (*users.Referrals, error)
+		GetReferralAcquisitionHistory(ctx context.Context, userID string, days uint64) ([]*users.ReferralAcquisition, error)
+
+		GetTopCountries(ctx context.Context, keyword string, limit, offset uint64) ([]*users.CountryStatistics, error)
+		GetUserGrowth(ctx context.Context, days uint64, tz string) (*users.UserGrowthStatistics, error)
+		GetGlobalValues(ctx context.Context, arg *GetGlobalValuesArg) ([]*users.GlobalUnsigned, error)
+
+		GetDuplicateAccountCandidates(ctx context.Context, minScore, limit, offset uint64) ([]*users.DuplicateAccountCandidate, error)
+		GetUserDuplicateAccountCandidates(ctx context.Context, userID string) ([]*users.DuplicateAccountCandidate, error)
+		GetPendingCountryChanges(ctx context.Context, limit, offset uint64) ([]*users.CountryChange, error)
+
+		GetErrorCatalog(ctx context.Context) ([]*errorcatalog.Entry, error)
+	}
+	// WriteClient calls the write API (eskimo-hut).
+	WriteClient interface {
+		CreateUser(ctx context.Context, arg *CreateUserArg) (*User, error)
+		ModifyUser(ctx context.Context, arg *ModifyUserArg) (*ModifiedUser, error)
+		DeleteUser(ctx context.Context, userID string) error
+		GenerateProfilePictureUploadURL(ctx context.Context, userID, contentType string) (*users.ProfilePictureUpload, error)
+		DecidePendingCountryChange(ctx context.Context, userID string, approve bool) (*users.CountryChange, error)
+
+		ReplaceDeviceMetadata(ctx context.Context, dm *users.DeviceMetadata) error
+		GetDeviceLocation(ctx context.Context, userID, deviceUniqueID string) (*users.DeviceLocation, error)
+	}
+	// TokenProvider returns the tokens to be sent with every request. Tokens set via ContextWithTokens take precedence.
+	TokenProvider func(ctx context.Context) (*Tokens, error)
+	Tokens        struct {
+		AccessToken   string
+		MetadataToken string
+	}
+	// PageFetcher is any of the client methods with `limit` and `offset`, e.g. ReadClient.GetPendingCountryChanges.
+	PageFetcher[T any] func(ctx context.Context, limit, offset uint64) ([]*T, error)
+	// Error is returned for every response that is not successful.
+	Error struct {
+		Data       map[string]any
+		Method     string
+		URL        string
+		Message    string
+		Code       string
+		StatusCode int
+	}
+	Config struct {
+		// The URL in front of `/v1r` or `/v1w`.
+		BaseURL         string              `yaml:"baseUrl" mapstructure:"baseUrl"`
+		Timeout         stdlibtime.Duration `yaml:"timeout" mapstructure:"timeout"`
+		RetryCount      int                 `yaml:"retryCount" mapstructure:"retryCount"`
+		RetryMinBackoff stdlibtime.Duration `yaml:"retryMinBackoff" mapstructure:"retryMinBackoff"`
+		RetryMaxBackoff stdlibtime.Duration `yaml:"retryMaxBackoff" mapstructure:"retryMaxBackoff"`
+		DisableRetries  bool                `yaml:"disableRetries" mapstructure:"disableRetries"`
+	}
+
+	UserProfile struct {
+		*users.UserProfile
+		Checksum string `json:"checksum,omitempty"`
+	}
+	User struct {
+		*users.User
+		*kycquiz.QuizStatus
+		Checksum string `json:"checksum,omitempty"`
+	}
+	ModifiedUser struct {
+		*User
+		LoginSession string `json:"loginSession,omitempty"`
+	}
+
+	GetUsersArg struct {
+		Keyword    string
+		SearchBy   string // `username` by default. The others, `email` and `phoneNumber`, are only for admins.
+		SearchMode string // `exact` by default. Only for SearchBy other than `username`.
+		Reason     string // Required if SearchBy is not `username`.
+		Limit      uint64
+		Offset     uint64
+	}
+	GetReferralsArg struct {
+		UserID string
+		Type   users.ReferralType
+		Limit  uint64
+		Offset uint64
+	}
+	GetGlobalValuesArg struct {
+		From      *stdlibtime.Time
+		To        *stdlibtime.Time
+		KeyPrefix string
+		Limit     uint64
+		Offset    uint64
+	}
+	CreateUserArg struct {
+		ClientData      *users.JSON `json:"clientData,omitempty"`
+		PhoneNumber     string      `json:"phoneNumber,omitempty"`
+		PhoneNumberHash string      `json:"phoneNumberHash,omitempty"`
+		Email           string      `json:"email,omitempty"`
+		FirstName       string      `json:"firstName,omitempty"`
+		LastName        string      `json:"lastName,omitempty"`
+		Language        string      `json:"language,omitempty"`
+		ReferredBy      string      `json:"referredBy,omitempty"`
+	}
+	// ModifyUserArg has only the fields that are to be changed set.
+	ModifyUserArg struct {
+		HiddenProfileElements               *users.Enum[users.HiddenProfileElement]
+		ClearHiddenProfileElements          *bool
+		ClearMiningBlockchainAccountAddress *bool
+		ResetProfilePicture                 *bool
+		ClientData                          *users.JSON
+		UserID                              string
+		ReferredBy                          string
+		ProfilePictureName                  string // The file name of ProfilePicture.
+		UploadedProfilePictureName          string
+		Country                             string
+		City                                string
+		Username                            string
+		FirstName                           string
+		LastName                            string
+		PhoneNumber                         string
+		PhoneNumberHash                     string
+		Email                               string
+		AgendaPhoneNumberHashes             string
+		BlockchainAccountAddress            string
+		MiningBlockchainAccountAddress      string
+		Language                            string
+		Checksum                            string
+		ProfilePicture                      []byte
+	}
+)
+
+// Private API.
+
+const (
+	defaultTimeout         = 25 * stdlibtime.Second
+	defaultRetryCount      = 3
+	defaultRetryMinBackoff = 100 * stdlibtime.Millisecond
+	defaultRetryMaxBackoff = 2 * stdlibtime.Second
+)
+
+const (
+	tokensCtxValueKey contextKey = "tokens"
+)
+
+type (
+	contextKey string
+	client     struct {
+		http *req.Client
+	}
+	readClient struct {
+		*client
+	}
+	writeClient struct {
+		*client
+	}
+)
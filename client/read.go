@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+)
+
+func (c *readClient) GetUsers(ctx context.Context, arg *GetUsersArg) ([]*users.MinimalUserProfile, error) {
+	r := c.http.R().
+		SetQueryParam("keyword", arg.Keyword).
+		SetQueryParam("limit", strconv.FormatUint(arg.Limit, 10)).
+		SetQueryParam("offset", strconv.FormatUint(arg.Offset, 10))
+	setOptionalQueryParam(r, "searchBy", arg.SearchBy)
+	setOptionalQueryParam(r, "searchMode", arg.SearchMode)
+	setOptionalQueryParam(r, "reason", arg.Reason)
+	res, err := execute[[]*users.MinimalUserProfile](ctx, r, http.MethodGet, "/v1r/users")
+
+	return deref(res), errors.Wrapf(err, "failed to get users for %#v", arg)
+}
+
+func (c *readClient) GetUserByID(ctx context.Context, userID string) (*UserProfile, error) {
+	r := c.http.R().SetPathParam("userId", userID)
+	res, err := execute[UserProfile](ctx, r, http.MethodGet, "/v1r/users/{userId}")
+
+	return res, errors.Wrapf(err, "failed to get user by id %v", userID)
+}
+
+func (c *readClient) GetUserByUsername(ctx context.Context, username string) (*users.UserProfile, error) {
+	r := c.http.R().SetQueryParam("username", username)
+	res, err := execute[users.UserProfile](ctx, r, http.MethodGet, "/v1r/user-views/username")
+
+	return res, errors.Wrapf(err, "failed to get user by username %v", username)
+}
+
+func (c *readClient) GetReferrals(ctx context.Context, arg *GetReferralsArg) (*users.Referrals, error) {
+	r := c.http.R().
+		SetPathParam("userId", arg.UserID).
+		SetQueryParam("type", string(arg.Type)).
+		SetQueryParam("limit", strconv.FormatUint(arg.Limit, 10)).
+		SetQueryParam("offset", strconv.FormatUint(arg.Offset, 10))
+	res, err := execute[users.Referrals](ctx, r, http.MethodGet, "/v1r/users/{userId}/referrals")
+
+	return res, errors.Wrapf(err, "failed to get referrals for %#v", arg)
+}
+
+func (c *readClient) GetReferralAcquisitionHistory(ctx context.Context, userID string, days uint64) ([]*users.ReferralAcquisition, error) {
+	r := c.http.R().
+		SetPathParam("userId", userID).
+		SetQueryParam("days", strconv.FormatUint(days, 10))
+	res, err := execute[[]*users.ReferralAcquisition](ctx, r, http.MethodGet, "/v1r/users/{userId}/referral-acquisition-history")
+
+	return deref(res), errors.Wrapf(err, "failed to get referral acquisition history for userID:%v, days:%v", userID, days)
+}
+
+func (c *readClient) GetTopCountries(ctx context.Context, keyword string, limit, offset uint64) ([]*users.CountryStatistics, error) {
+	r := c.http.R().
+		SetQueryParam("limit", strconv.FormatUint(limit, 10)).
+		SetQueryParam("offset", strconv.FormatUint(offset, 10))
+	setOptionalQueryParam(r, "keyword", keyword)
+	res, err := execute[[]*users.CountryStatistics](ctx, r, http.MethodGet, "/v1r/user-statistics/top-countries")
+
+	return deref(res), errors.Wrapf(err, "failed to get top countries for keyword:%v, limit:%v, offset:%v", keyword, limit, offset)
+}
+
+func (c *readClient) GetUserGrowth(ctx context.Context, days uint64, tz string) (*users.UserGrowthStatistics, error) {
+	r := c.http.R().SetQueryParam("days", strconv.FormatUint(days, 10))
+	setOptionalQueryParam(r, "tz", tz)
+	res, err := execute[users.UserGrowthStatistics](ctx, r, http.MethodGet, "/v1r/user-statistics/user-growth")
+
+	return res, errors.Wrapf(err, "failed to get user growth for days:%v, tz:%v", days, tz)
+}
+
+func (c *readClient) GetGlobalValues(ctx context.Context, arg *GetGlobalValuesArg) ([]*users.GlobalUnsigned, error) {
+	r := c.http.R().
+		SetQueryParam("keyPrefix", arg.KeyPrefix).
+		SetQueryParam("limit", strconv.FormatUint(arg.Limit, 10)).
+		SetQueryParam("offset", strconv.FormatUint(arg.Offset, 10))
+	if arg.From != nil {
+		r.SetQueryParam("from", arg.From.UTC().Format(stdlibtime.RFC3339Nano))
+	}
+	if arg.To != nil {
+		r.SetQueryParam("to", arg.To.UTC().Format(stdlibtime.RFC3339Nano))
+	}
+	res, err := execute[[]*users.GlobalUnsigned](ctx, r, http.MethodGet, "/v1r/global-values")
+
+	return deref(res), errors.Wrapf(err, "failed to get global values for %#v", arg)
+}
+
+func (c *readClient) GetDuplicateAccountCandidates(
+	ctx context.Context, minScore, limit, offset uint64,
+) ([]*users.DuplicateAccountCandidate, error) {
+	r := c.http.R().
+		SetQueryParam("minScore", strconv.FormatUint(minScore, 10)).
+		SetQueryParam("limit", strconv.FormatUint(limit, 10)).
+		SetQueryParam("offset", strconv.FormatUint(offset, 10))
+	res, err := execute[[]*users.DuplicateAccountCandidate](ctx, r, http.MethodGet, "/v1r/duplicate-accounts")
+
+	return deref(res), errors.Wrapf(err, "failed to get duplicate account candidates for minScore:%v, limit:%v, offset:%v", minScore, limit, offset)
+}
+
+func (c *readClient) GetUserDuplicateAccountCandidates(ctx context.Context, userID string) ([]*users.DuplicateAccountCandidate, error) {
+	r := c.http.R().SetPathParam("userId", userID)
+	res, err := execute[[]*users.DuplicateAccountCandidate](ctx, r, http.MethodGet, "/v1r/duplicate-accounts/{userId}")
+
+	return deref(res), errors.Wrapf(err, "failed to get duplicate account candidates for userID:%v", userID)
+}
+
+func (c *readClient) GetPendingCountryChanges(ctx context.Context, limit, offset uint64) ([]*users.CountryChange, error) {
+	r := c.http.R().
+		SetQueryParam("limit", strconv.FormatUint(limit, 10)).
+		SetQueryParam("offset", strconv.FormatUint(offset, 10))
+	res, err := execute[[]*users.CountryChange](ctx, r, http.MethodGet, "/v1r/pending-country-changes")
+
+	return deref(res), errors.Wrapf(err, "failed to get pending country changes for limit:%v, offset:%v", limit, offset)
+}
+
+func (c *readClient) GetErrorCatalog(ctx context.Context) ([]*errorcatalog.Entry, error) {
+	res, err := execute[[]*errorcatalog.Entry](ctx, c.http.R(), http.MethodGet, "/v1r/errors")
+
+	return deref(res), errors.Wrap(err, "failed to get error catalog")
+}
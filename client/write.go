@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/goccy/go-json"
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+)
+
+func (c *writeClient) CreateUser(ctx context.Context, arg *CreateUserArg) (*User, error) {
+	r := c.http.R().SetBody(arg)
+	res, err := execute[User](ctx, r, http.MethodPost, "/v1w/users")
+
+	return res, errors.Wrapf(err, "failed to create user for %#v", arg)
+}
+
+// ModifyUser sends the changes as multipart form data, the same way the mobile apps do.
+func (c *writeClient) ModifyUser(ctx context.Context, arg *ModifyUserArg) (*ModifiedUser, error) {
+	form, err := modifyUserFormData(arg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build form data for %#v", arg)
+	}
+	r := c.http.R().
+		SetPathParam("userId", arg.UserID).
+		EnableForceMultipart().
+		SetFormDataFromValues(form)
+	if len(arg.ProfilePicture) != 0 {
+		r.SetFileBytes("profilePicture", arg.ProfilePictureName, arg.ProfilePicture)
+	}
+	res, err := execute[ModifiedUser](ctx, r, http.MethodPatch, "/v1w/users/{userId}")
+
+	return res, errors.Wrapf(err, "failed to modify user %v", arg.UserID)
+}
+
+func modifyUserFormData(arg *ModifyUserArg) (url.Values, error) {
+	form := make(url.Values)
+	for key, value := range map[string]string{
+		"referredBy":                     arg.ReferredBy,
+		"uploadedProfilePictureName":     arg.UploadedProfilePictureName,
+		"country":                        arg.Country,
+		"city":                           arg.City,
+		"username":                       arg.Username,
+		"firstName":                      arg.FirstName,
+		"lastName":                       arg.LastName,
+		"phoneNumber":                    arg.PhoneNumber,
+		"phoneNumberHash":                arg.PhoneNumberHash,
+		"email":                          arg.Email,
+		"agendaPhoneNumberHashes":        arg.AgendaPhoneNumberHashes,
+		"blockchainAccountAddress":       arg.BlockchainAccountAddress,
+		"miningBlockchainAccountAddress": arg.MiningBlockchainAccountAddress,
+		"language":                       arg.Language,
+		"checksum":                       arg.Checksum,
+	} {
+		if value != "" {
+			form.Set(key, value)
+		}
+	}
+	for key, value := range map[string]*bool{
+		"clearHiddenProfileElements":          arg.ClearHiddenProfileElements,
+		"clearMiningBlockchainAccountAddress": arg.ClearMiningBlockchainAccountAddress,
+		"resetProfilePicture":                 arg.ResetProfilePicture,
+	} {
+		if value != nil {
+			form.Set(key, strconv.FormatBool(*value))
+		}
+	}
+	if arg.HiddenProfileElements != nil {
+		for _, element := range *arg.HiddenProfileElements {
+			form.Add("hiddenProfileElements", string(element))
+		}
+	}
+	if arg.ClientData != nil {
+		clientData, err := json.Marshal(arg.ClientData)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal clientData %#v", arg.ClientData)
+		}
+		form.Set("clientData", string(clientData))
+	}
+
+	return form, nil
+}
+
+func (c *writeClient) DeleteUser(ctx context.Context, userID string) error {
+	r := c.http.R().SetPathParam("userId", userID)
+	_, err := execute[struct{}](ctx, r, http.MethodDelete, "/v1w/users/{userId}")
+
+	return errors.Wrapf(err, "failed to delete user %v", userID)
+}
+
+func (c *writeClient) GenerateProfilePictureUploadURL(ctx context.Context, userID, contentType string) (*users.ProfilePictureUpload, error) {
+	r := c.http.R().
+		SetPathParam("userId", userID).
+		SetBody(&struct {
+			ContentType string `json:"contentType"`
+		}{ContentType: contentType})
+	res, err := execute[users.ProfilePictureUpload](ctx, r, http.MethodPost, "/v1w/users/{userId}/profile-picture-upload-urls")
+
+	return res, errors.Wrapf(err, "failed to generate profile picture upload url for userID:%v, contentType:%v", userID, contentType)
+}
+
+func (c *writeClient) DecidePendingCountryChange(ctx context.Context, userID string, approve bool) (*users.CountryChange, error) {
+	r := c.http.R().
+		SetPathParam("userId", userID).
+		SetBody(&struct {
+			Approve bool `json:"approve"`
+		}{Approve: approve})
+	res, err := execute[users.CountryChange](ctx, r, http.MethodPut, "/v1w/users/{userId}/pending-country-change/decision")
+
+	return res, errors.Wrapf(err, "failed to decide pending country change for userID:%v, approve:%v", userID, approve)
+}
+
+func (c *writeClient) ReplaceDeviceMetadata(ctx context.Context, dm *users.DeviceMetadata) error {
+	r := c.devicesRequest(dm.UserID, dm.DeviceUniqueID).SetBody(dm)
+	_, err := execute[struct{}](ctx, r, http.MethodPut, "/v1w/users/{userId}/devices/{deviceUniqueId}/metadata")
+
+	return errors.Wrapf(err, "failed to replace device metadata for %#v", dm.ID)
+}
+
+func (c *writeClient) GetDeviceLocation(ctx context.Context, userID, deviceUniqueID string) (*users.DeviceLocation, error) {
+	r := c.devicesRequest(userID, deviceUniqueID)
+	res, err := execute[users.DeviceLocation](ctx, r, http.MethodPut, "/v1w/users/{userId}/devices/{deviceUniqueId}/metadata/location")
+
+	return res, errors.Wrapf(err, "failed to get device location for userID:%v, deviceUniqueID:%v", userID, deviceUniqueID)
+}
+
+// devicesRequest uses `-` for the unknown ids, as the devices API expects.
+func (c *writeClient) devicesRequest(userID, deviceUniqueID string) *req.Request {
+	if userID == "" {
+		userID = "-"
+	}
+	if deviceUniqueID == "" {
+		deviceUniqueID = "-"
+	}
+
+	return c.http.R().
+		SetPathParam("userId", userID).
+		SetPathParam("deviceUniqueId", deviceUniqueID)
+}
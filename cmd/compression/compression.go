@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// Middleware compresses the responses of the routes it's registered for, with the encoding the client prefers in its `Accept-Encoding`
+// (zstd or gzip), if they have at least minSize bytes. The smaller ones aren't worth it, so they're sent as they are. 0 disables it.
+func Middleware(minSize int) gin.HandlerFunc {
+	if minSize <= 0 {
+		return func(*gin.Context) {}
+	}
+	zstdEncoder, err := zstd.NewWriter(nil)
+	log.Panic(errors.Wrap(err, "failed to create the zstd encoder")) //nolint:revive // Intended.
+
+	return func(ginCtx *gin.Context) {
+		ginCtx.Header(varyHeader, acceptEncodingHeader)
+		encoding := negotiate(ginCtx.GetHeader(acceptEncodingHeader))
+		if encoding == "" {
+			return
+		}
+		writer := &bufferingWriter{ResponseWriter: ginCtx.Writer, body: new(bytes.Buffer)}
+		ginCtx.Writer = writer
+		ginCtx.Next()
+		ginCtx.Writer = writer.ResponseWriter
+		body := writer.body.Bytes()
+		if len(body) >= minSize && writer.Header().Get(contentEncodingHeader) == "" {
+			if compressed, cErr := compress(zstdEncoder, encoding, body); cErr != nil {
+				log.Error(errors.Wrapf(cErr, "failed to compress the response of %v with %v", ginCtx.FullPath(), encoding))
+			} else {
+				body = compressed
+				writer.Header().Set(contentEncodingHeader, encoding)
+				writer.Header().Del(contentLengthHeader)
+			}
+		}
+		if len(body) == 0 {
+			writer.ResponseWriter.WriteHeaderNow()
+
+			return
+		}
+		_, err = writer.ResponseWriter.Write(body)
+		log.Error(errors.Wrapf(err, "failed to write the response of %v", ginCtx.FullPath()))
+	}
+}
+
+func (w *bufferingWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data) //nolint:wrapcheck // It never fails.
+}
+
+func (w *bufferingWriter) WriteString(data string) (int, error) {
+	return w.body.WriteString(data) //nolint:wrapcheck // It never fails.
+}
+
+// negotiate picks the supported encoding with the highest quality in the `Accept-Encoding` header, zstd if it's a tie.
+func negotiate(acceptEncoding string) string {
+	var selected string
+	var selectedQuality float64
+	for _, encodingRange := range strings.Split(acceptEncoding, ",") {
+		encoding, params, _ := strings.Cut(encodingRange, ";")
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			var err error
+			if quality, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if encoding == anyEncoding {
+			encoding = zstdEncoding
+		}
+		if (encoding != zstdEncoding && encoding != gzipEncoding) || quality <= 0 {
+			continue
+		}
+		if quality > selectedQuality || (quality == selectedQuality && encoding == zstdEncoding) {
+			selected, selectedQuality = encoding, quality
+		}
+	}
+
+	return selected
+}
+
+func compress(zstdEncoder *zstd.Encoder, encoding string, body []byte) ([]byte, error) {
+	if encoding == zstdEncoding {
+		return zstdEncoder.EncodeAll(body, make([]byte, 0, len(body))), nil
+	}
+	compressed := new(bytes.Buffer)
+	gzipWriter := gzip.NewWriter(compressed)
+	if _, err := gzipWriter.Write(body); err != nil {
+		return nil, errors.Wrap(err, "failed to gzip")
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close gzip writer")
+	}
+
+	return compressed.Bytes(), nil
+}
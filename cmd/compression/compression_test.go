@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package compression
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, zstdEncoding, negotiate("gzip, deflate, br, zstd"))
+	assert.Equal(t, gzipEncoding, negotiate("gzip, deflate, br"))
+	assert.Equal(t, gzipEncoding, negotiate("zstd;q=0.5, gzip;q=0.8"))
+	assert.Equal(t, gzipEncoding, negotiate("zstd;q=0, GZIP"))
+	assert.Equal(t, zstdEncoding, negotiate("*"))
+	assert.Empty(t, negotiate("deflate, br"))
+	assert.Empty(t, negotiate("gzip;q=x"))
+	assert.Empty(t, negotiate(""))
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+	body := strings.Repeat(`{"username":"jdoe"},`, 100)
+	router := gin.New()
+	router.GET("/small", Middleware(len(body)+1), func(ginCtx *gin.Context) { ginCtx.String(http.StatusOK, body) })
+	router.GET("/large", Middleware(len(body)), func(ginCtx *gin.Context) { ginCtx.String(http.StatusOK, body) })
+	router.GET("/empty", Middleware(1), func(ginCtx *gin.Context) { ginCtx.Status(http.StatusNoContent) })
+
+	resp := serve(router, "/small", "gzip")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, resp.Header().Get(contentEncodingHeader))
+	assert.Equal(t, body, resp.Body.String())
+
+	resp = serve(router, "/large", "")
+	assert.Empty(t, resp.Header().Get(contentEncodingHeader))
+	assert.Equal(t, acceptEncodingHeader, resp.Header().Get(varyHeader))
+	assert.Equal(t, body, resp.Body.String())
+
+	resp = serve(router, "/large", "gzip")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, gzipEncoding, resp.Header().Get(contentEncodingHeader))
+	gzipReader, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(gzipReader)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decompressed))
+
+	resp = serve(router, "/large", "gzip, zstd")
+	assert.Equal(t, zstdEncoding, resp.Header().Get(contentEncodingHeader))
+	zstdDecoder, err := zstd.NewReader(nil)
+	require.NoError(t, err)
+	defer zstdDecoder.Close()
+	decompressed, err = zstdDecoder.DecodeAll(resp.Body.Bytes(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decompressed))
+
+	resp = serve(router, "/empty", "gzip")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Header().Get(contentEncodingHeader))
+}
+
+func serve(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+	req.Header.Set(acceptEncodingHeader, acceptEncoding)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	return resp
+}
@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package compression
+
+import (
+	"bytes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Private API.
+
+const (
+	acceptEncodingHeader  = "Accept-Encoding"
+	contentEncodingHeader = "Content-Encoding"
+	contentLengthHeader   = "Content-Length"
+	varyHeader            = "Vary"
+
+	zstdEncoding = "zstd"
+	gzipEncoding = "gzip"
+	anyEncoding  = "*"
+)
+
+type (
+	// | bufferingWriter holds the body back, so that it can be compressed, or not, once it's complete.
+	bufferingWriter struct {
+		gin.ResponseWriter
+		body *bytes.Buffer
+	}
+)
@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package configreload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"strings"
+	"syscall"
+	stdlibtime "time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// Start re-reads application.yaml on SIGHUP and, if watchInterval is positive, whenever the file is modified,
+// and lets the reloadables apply their reloadable values. The changes of any other value are rejected: they're logged,
+// on every reload, until the service is restarted. It blocks until the context is done, so it's meant to be run in a goroutine.
+func Start(ctx context.Context, watchInterval stdlibtime.Duration, reloadables ...Reloadable) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	var ticks <-chan stdlibtime.Time
+	if watchInterval > 0 {
+		ticker := stdlibtime.NewTicker(watchInterval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+	r := &reloader{reloadables: reloadables, applied: settings(), modTime: configModTime()}
+	for {
+		select {
+		case <-signals:
+			log.Error(errors.Wrap(r.reload(), "failed to reload the config on SIGHUP"))
+		case <-ticks:
+			if modTime := configModTime(); !modTime.Equal(r.modTime) {
+				r.modTime = modTime
+				log.Error(errors.Wrap(r.reload(), "failed to reload the modified config"))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *reloader) reload() error {
+	if err := viper.ReadInConfig(); err != nil {
+		return errors.Wrapf(err, "failed to read %v", viper.ConfigFileUsed())
+	}
+	current := settings()
+	reloadable, rejected := r.changedKeys(current)
+	if len(rejected) != 0 {
+		log.Error(errors.Errorf("the changes of %v require a restart, they're ignored until then", rejected))
+	}
+	if len(reloadable) == 0 {
+		return nil
+	}
+	var mErr *multierror.Error
+	for _, rl := range r.reloadables {
+		mErr = multierror.Append(mErr, rl.ReloadConfig())
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return errors.Wrapf(err, "failed to apply the changes of %v", reloadable)
+	}
+	for _, key := range reloadable {
+		r.applied[key] = current[key]
+	}
+	log.Info(fmt.Sprintf("config reloaded, applied the changes of %v", reloadable))
+
+	return nil
+}
+
+// changedKeys compares the current settings with the ones last applied, so the rejected changes keep being reported until a restart.
+func (r *reloader) changedKeys(current map[string]any) (reloadable, rejected []string) {
+	var reloadableKeys []string
+	for _, rl := range r.reloadables {
+		for _, key := range rl.ReloadableConfigKeys() {
+			reloadableKeys = append(reloadableKeys, strings.ToLower(key))
+		}
+	}
+	for key := range r.applied {
+		if _, found := current[key]; !found {
+			current[key] = nil
+		}
+	}
+	for key, value := range current {
+		if reflect.DeepEqual(r.applied[key], value) {
+			continue
+		}
+		if isReloadable(key, reloadableKeys) {
+			reloadable = append(reloadable, key)
+		} else {
+			rejected = append(rejected, key)
+		}
+	}
+	slices.Sort(reloadable)
+	slices.Sort(rejected)
+
+	return reloadable, rejected
+}
+
+func isReloadable(key string, reloadableKeys []string) bool {
+	for _, reloadableKey := range reloadableKeys {
+		if key == reloadableKey || strings.HasPrefix(key, reloadableKey+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// settings are the (lower case) leaf keys of the config, with their values.
+func settings() map[string]any {
+	keys := viper.AllKeys()
+	values := make(map[string]any, len(keys))
+	for _, key := range keys {
+		values[key] = viper.Get(key)
+	}
+
+	return values
+}
+
+func configModTime() stdlibtime.Time {
+	info, err := os.Stat(viper.ConfigFileUsed())
+	if err != nil {
+		log.Error(errors.Wrapf(err, "failed to stat %v", viper.ConfigFileUsed()))
+
+		return stdlibtime.Time{}
+	}
+
+	return info.ModTime()
+}
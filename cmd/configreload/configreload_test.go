@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package configreload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	mockReloadable struct {
+		keys []string
+	}
+)
+
+func (m *mockReloadable) ReloadableConfigKeys() []string {
+	return m.keys
+}
+
+func (*mockReloadable) ReloadConfig() error {
+	return nil
+}
+
+func TestChangedKeys(t *testing.T) {
+	t.Parallel()
+	r := &reloader{
+		reloadables: []Reloadable{&mockReloadable{keys: []string{"users.kycAttemptLimits", "kyc/quiz.antiBotChallenge.minFraudScore"}}},
+		applied: map[string]any{
+			"users.kycattemptlimits.quiz.maxattempts": 3,
+			"users.kycattemptlimitsbogus":             1,
+			"kyc/quiz.antibotchallenge.minfraudscore": 50,
+			"kyc/quiz.antibotchallenge.provider":      "stub",
+			"users.messagebroker.urls":                []any{"localhost:9092"},
+			"users.removed":                           true,
+		},
+	}
+	reloadable, rejected := r.changedKeys(map[string]any{
+		"users.kycattemptlimits.quiz.maxattempts":   5,
+		"users.kycattemptlimits.quiz.cooldown":      "1h",
+		"users.kycattemptlimitsbogus":               2,
+		"kyc/quiz.antibotchallenge.minfraudscore":   50,
+		"kyc/quiz.antibotchallenge.provider":        "turnstile",
+		"users.messagebroker.urls":                  []any{"localhost:9092"},
+		"users.globalaggregationinterval.child":     "1h",
+		"users.globalaggregationinterval.nochanges": nil,
+	})
+	assert.Equal(t, []string{"users.kycattemptlimits.quiz.cooldown", "users.kycattemptlimits.quiz.maxattempts"}, reloadable)
+	assert.Equal(t, []string{"kyc/quiz.antibotchallenge.provider", "users.globalaggregationinterval.child", "users.kycattemptlimitsbogus", "users.removed"}, rejected)
+}
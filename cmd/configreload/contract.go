@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package configreload
+
+import (
+	stdlibtime "time"
+)
+
+// Public API.
+
+type (
+	// Reloadable is implemented by the repositories that can apply some of their config values without a restart.
+	Reloadable interface {
+		// ReloadableConfigKeys are the keys (I.E. `users.kycAttemptLimits`) applied by ReloadConfig, including all the keys under them.
+		ReloadableConfigKeys() []string
+		// ReloadConfig applies the latest values of ReloadableConfigKeys, the other values keep the ones they were started with.
+		ReloadConfig() error
+	}
+)
+
+// Private API.
+
+type (
+	reloader struct {
+		modTime     stdlibtime.Time
+		applied     map[string]any
+		reloadables []Reloadable
+	}
+)
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package deadline
+
+import (
+	"context"
+
+	"github.com/ice-blockchain/wintr/server"
+)
+
+// Public API.
+
+type (
+	// Handler is what server.RootHandler wraps.
+	Handler[REQ, RESP any] func(context.Context, *server.Request[REQ, RESP]) (*server.Response[RESP], *server.Response[server.ErrorResponse])
+)
+
+// Private API.
+
+const (
+	// timedOutError is the same error the server responds with when its `defaultEndpointTimeout` is exceeded.
+	timedOutError = "request timed out"
+)
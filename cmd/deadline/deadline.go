@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package deadline
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	stdlibtime "time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/server"
+)
+
+// Budget bounds the handler, and everything it calls with its context, to the budget of its route group, if positive.
+// If the budget is exceeded, the call fails with 504, the same way it does when the server's `defaultEndpointTimeout`,
+// which is the upper bound of any budget, is exceeded.
+func Budget[REQ, RESP any](budget stdlibtime.Duration, handler Handler[REQ, RESP]) Handler[REQ, RESP] {
+	if budget <= 0 {
+		return handler
+	}
+
+	return func(ctx context.Context, req *server.Request[REQ, RESP]) (*server.Response[RESP], *server.Response[server.ErrorResponse]) {
+		budgetCtx, cancel := context.WithTimeout(ctx, budget)
+		defer cancel()
+		success, failure := handler(budgetCtx, req)
+		if failure != nil && ctx.Err() == nil && budgetCtx.Err() != nil && errors.Is(failure.Data.InternalErr(), budgetCtx.Err()) {
+			return nil, timedOut(failure.Data.InternalErr(), budget)
+		}
+
+		return success, failure
+	}
+}
+
+// ValidateBudgets reports the budgets, by their key under `routeTimeouts`, that are negative or exceed the `defaultEndpointTimeout`.
+func ValidateBudgets(applicationYAMLKey string, defaultEndpointTimeout stdlibtime.Duration, budgets map[string]stdlibtime.Duration) error {
+	keys := make([]string, 0, len(budgets))
+	for key := range budgets {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	var mErr *multierror.Error
+	for _, key := range keys {
+		if budget := budgets[key]; budget < 0 || (defaultEndpointTimeout > 0 && budget > defaultEndpointTimeout) {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.routeTimeouts.%v` must be between 0 and `%v.defaultEndpointTimeout` (%v), not %v",
+				applicationYAMLKey, key, applicationYAMLKey, defaultEndpointTimeout, budget))
+		}
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+}
+
+func timedOut(err error, budget stdlibtime.Duration) *server.Response[server.ErrorResponse] {
+	errResp := server.Unexpected(errors.Wrapf(err, "deadline budget of %v exceeded", budget))
+	errResp.Code = http.StatusGatewayTimeout
+	errResp.Data.Error = timedOutError
+
+	return errResp
+}
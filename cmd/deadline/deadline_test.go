@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package deadline
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func TestBudget(t *testing.T) {
+	t.Parallel()
+	waitForDeadline := func(ctx context.Context, _ *server.Request[struct{}, struct{}]) (*server.Response[struct{}], *server.Response[server.ErrorResponse]) {
+		<-ctx.Done()
+
+		return nil, server.Unexpected(errors.Wrap(ctx.Err(), "failed to select"))
+	}
+	_, failure := Budget(stdlibtime.Millisecond, waitForDeadline)(context.Background(), new(server.Request[struct{}, struct{}]))
+	require.NotNil(t, failure)
+	assert.Equal(t, http.StatusGatewayTimeout, failure.Code)
+	assert.Equal(t, timedOutError, failure.Data.Error)
+	require.ErrorIs(t, failure.Data.InternalErr(), context.DeadlineExceeded)
+
+	parentCtx, cancel := context.WithTimeout(context.Background(), stdlibtime.Millisecond)
+	defer cancel()
+	_, failure = Budget(stdlibtime.Hour, waitForDeadline)(parentCtx, new(server.Request[struct{}, struct{}]))
+	require.NotNil(t, failure)
+	assert.NotEqual(t, http.StatusGatewayTimeout, failure.Code, "the server's own timeout is left to the server")
+
+	ok := func(context.Context, *server.Request[struct{}, struct{}]) (*server.Response[struct{}], *server.Response[server.ErrorResponse]) {
+		return server.OK(new(struct{})), nil
+	}
+	success, failure := Budget(stdlibtime.Second, ok)(context.Background(), new(server.Request[struct{}, struct{}]))
+	require.Nil(t, failure)
+	assert.Equal(t, http.StatusOK, success.Code)
+}
+
+func TestValidateBudgets(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, ValidateBudgets("cmd/eskimo", 30*stdlibtime.Second, map[string]stdlibtime.Duration{"profiles": 2 * stdlibtime.Second, "admin": 0}))
+	err := ValidateBudgets("cmd/eskimo", 30*stdlibtime.Second, map[string]stdlibtime.Duration{"profiles": -1, "admin": stdlibtime.Minute})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "`cmd/eskimo.routeTimeouts.profiles`")
+	assert.Contains(t, err.Error(), "`cmd/eskimo.routeTimeouts.admin`")
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package errorcatalog
+
+// Public API.
+
+const (
+	// NeverRetryPolicy means that retrying the same request will fail the same way.
+	NeverRetryPolicy RetryPolicy = "never"
+	// AfterFixRetryPolicy means that the request can be retried only after fixing the properties described in the error `details`.
+	AfterFixRetryPolicy RetryPolicy = "afterFix"
+	// AfterRefreshRetryPolicy means that the request can be retried after fetching the latest state of the resource, or after refreshing the token.
+	AfterRefreshRetryPolicy RetryPolicy = "afterRefresh"
+	// WithBackoffRetryPolicy means that the same request can be retried later, with an exponential backoff.
+	WithBackoffRetryPolicy RetryPolicy = "withBackoff"
+)
+
+const (
+	RequiredReason   DetailsReason = "required"
+	InvalidReason    DetailsReason = "invalid"
+	TooShortReason   DetailsReason = "tooShort"
+	ConflictReason   DetailsReason = "conflict"
+	NotAllowedReason DetailsReason = "notAllowed"
+)
+
+const (
+	EskimoService    Service = "eskimo"
+	EskimoHutService Service = "eskimo-hut"
+)
+
+// DetailsDataKey is the key, in server.ErrorResponse#Data, of the machine-readable Details.
+const DetailsDataKey = "details"
+
+type (
+	RetryPolicy   string
+	DetailsReason string
+	Service       string
+	// Entry describes a value of server.ErrorResponse#Code.
+	Entry struct {
+		Code         string      `json:"code" example:"USER_NOT_FOUND"`
+		Description  string      `json:"description" example:"The user was not found."`
+		Retry        RetryPolicy `json:"retry" example:"never" enums:"never,afterFix,afterRefresh,withBackoff"`
+		Services     []Service   `json:"services" example:"eskimo,eskimo-hut" enums:"eskimo,eskimo-hut"`
+		HTTPStatuses []int       `json:"httpStatuses" example:"404"`
+	}
+	// Details are the machine-readable details of an error, found in server.ErrorResponse#Data, under DetailsDataKey.
+	Details struct {
+		Reason DetailsReason `json:"reason,omitempty" example:"invalid" enums:"required,invalid,tooShort,conflict,notAllowed"`
+		Fields []string      `json:"fields,omitempty" example:"username"`
+	}
+)
@@ -0,0 +1,576 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package errorcatalog
+
+import (
+	"net/http"
+)
+
+// Catalog returns all the values of server.ErrorResponse#Code returned by eskimo and eskimo-hut, ordered by code.
+func Catalog() []*Entry {
+	return catalog
+}
+
+// Data returns the server.ErrorResponse#Data with the provided details, keeping the other keys of data, if any.
+func Data(details *Details, data ...map[string]any) map[string]any {
+	res := make(map[string]any, 1+len(data))
+	for _, d := range data {
+		for k, v := range d {
+			res[k] = v
+		}
+	}
+	res[DetailsDataKey] = details
+
+	return res
+}
+
+// FieldsData is a shortcut for Data with the provided reason and fields.
+func FieldsData(reason DetailsReason, fields ...string) map[string]any {
+	return Data(&Details{Reason: reason, Fields: fields})
+}
+
+// ConflictData returns the data for conflicts with another user, based on the `field` found in the data of the underlying terror.
+func ConflictData(data map[string]any) map[string]any {
+	details := &Details{Reason: ConflictReason}
+	if field, ok := data["field"].(string); ok && field != "" {
+		details.Fields = []string{field}
+	}
+
+	return Data(details, data)
+}
+
+//nolint:gochecknoglobals,lll,funlen // It's the catalog.
+var catalog = []*Entry{
+	{
+		Code:         "ACCOUNT_LOST",
+		Description:  "The account can't be recovered with the provided data.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "ANTI_BOT_CHALLENGE_FAILED",
+		Description:  "The anti-bot challenge token was rejected by the provider.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "ANTI_BOT_CHALLENGE_REQUIRED",
+		Description:  "An anti-bot challenge has to be solved and its token provided in the `X-Anti-Bot-Challenge-Token` header.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "BLOCKCHAIN_ADDRESS_CHALLENGE_NOT_FOUND",
+		Description:  "There's no pending challenge for the blockchain address, or it expired. A new one has to be created and signed.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "CONFIRMATION_CODE_ATTEMPTS_EXCEEDED",
+		Description:  "Too many wrong confirmation codes were provided. A new sign in link has to be requested, for sign ins after the `blockedUntil` found in `data`.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "CONFIRMATION_CODE_EXPIRED",
+		Description:  "The confirmation code expired, so a new sign in must be started.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "CONFIRMATION_CODE_NOT_FOUND",
+		Description:  "There is no confirmation code for the provided login session.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "CONFIRMATION_CODE_WRONG",
+		Description:  "The provided confirmation code is wrong. For sign ins, `data` contains the `remainingAttempts` before the sign in gets blocked.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "CONFLICT_WITH_ANOTHER_USER",
+		Description:  "Another user already has the same value for one of the unique properties (username, email, phone number, etc.), see `details`.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusConflict},
+	},
+	{
+		Code:         "COUNTRY_CHANGE_NOT_FOUND",
+		Description:  "The user has no pending country change.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "DATA_MISMATCH",
+		Description:  "The provided data doesn't match the data of the user.",
+		Retry:        AfterRefreshRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "DEVICE_ATTESTATION_CHALLENGE_NOT_FOUND",
+		Description:  "The device has no valid attestation challenge, because it was never requested, it was already used or it expired.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "DEVICE_ATTESTATION_REQUIRED",
+		Description:  "The device must pass an integrity attestation (Play Integrity or App Attest) before proceeding.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "DEVICE_FINGERPRINT_MISMATCH",
+		Description:  "The tokens are bound to another device, so the sign in must be confirmed again via a new email link.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "DEVICE_METADATA_NOT_FOUND",
+		Description:  "The device has no metadata, so its metadata must be submitted first.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "DISTRIBUTION_ELIGIBILITY_SNAPSHOT_NOT_FOUND",
+		Description:  "The distribution eligibility snapshot was not found.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "EMAIL_ALREADY_SET",
+		Description:  "The user already has an email.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusConflict},
+	},
+	{
+		Code:         "EMAIL_NOT_FOUND",
+		Description:  "The email is not a secondary email of the user, or it's not confirmed yet.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "EMAIL_USED_BY_SOMEBODY_ELSE",
+		Description:  "The email is already used by another user.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusConflict},
+	},
+	{
+		Code:         "EXPIRED_LINK",
+		Description:  "The sign in link expired. A new one has to be requested.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "EXPIRED_TELEGRAM_INIT_DATA",
+		Description:  "The Telegram init data is too old. The Mini App has to be reopened, to get a new one.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "FEATURE_RESTRICTED_IN_COUNTRY",
+		Description:  "The feature in `data.feature` is unavailable, or can't be skipped, in the country of the user. See `GET /v1w/kyc/config`.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "GUEST_ACCOUNT_NOT_ALLOWED",
+		Description:  "Guest accounts can't do this until they're upgraded, by signing in with an email.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "INVALID_BLOCKCHAIN_ADDRESS",
+		Description:  "The address isn't valid for its chain: e.g. not 20 hex bytes or with a wrong EIP-55 checksum for `evm`, not 32 base58 bytes for `solana`.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "INVALID_BLOCKCHAIN_ADDRESS_SIGNATURE",
+		Description:  "The signature of the message of the blockchain address challenge wasn't made by the address, or it's malformed.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "INVALID_DEVICE_ATTESTATION",
+		Description:  "The device integrity attestation is invalid or not bound to the issued challenge.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "INVALID_EMAIL",
+		Description:  "The email is invalid.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "INVALID_GUEST_TOKEN",
+		Description:  "The guest token is invalid, expired or not of a guest account, or the upgraded account is a guest too.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "INVALID_KEYWORD",
+		Description:  "The search keyword is invalid.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "INVALID_METADATA_TOKEN",
+		Description:  "The metadata token (X-Account-Metadata) is not a valid metadata token. A new one must be fetched via getMetadata.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusUnauthorized},
+	},
+	{
+		Code:         "INVALID_OTP",
+		Description:  "The one time password of the sign in link is invalid.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "INVALID_PASSKEY",
+		Description:  "The passkey credential, or its signature, is invalid, or its authenticator might have been cloned.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "INVALID_PROFILE_PICTURE",
+		Description:  "The profile picture, or its content type, is invalid.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "INVALID_PROPERTIES",
+		Description:  "Some of the provided properties are invalid, see `details`.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoService, EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+	},
+	{
+		Code:         "INVALID_TELEGRAM_INIT_DATA",
+		Description:  "The Telegram init data is malformed, or its signature isn't of the bot of the Mini App.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "INVALID_TOKEN",
+		Description:  "The access token is missing, invalid or expired.",
+		Retry:        AfterRefreshRetryPolicy,
+		Services:     []Service{EskimoService, EskimoHutService},
+		HTTPStatuses: []int{http.StatusUnauthorized},
+	},
+	{
+		Code:         "INVALID_USERNAME",
+		Description:  "The username doesn't match the allowed pattern.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoService, EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "INVALID_USER_IMPORT_FILE",
+		Description:  "The user import file is empty, too big or not NDJSON.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "KYC_STEP_ATTEMPTS_EXCEEDED",
+		Description:  "The KYC step was failed too many times. It can be attempted again after `data.nextAllowedAt`.",
+		Retry:        WithBackoffRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "MAINTENANCE_MODE",
+		Description:  "The service is under maintenance. `data.message` explains it, in the `Accept-Language`, and `data.eta`, if set, is when it's expected to end.",
+		Retry:        WithBackoffRetryPolicy,
+		Services:     []Service{EskimoService, EskimoHutService},
+		HTTPStatuses: []int{http.StatusServiceUnavailable},
+	},
+	{
+		Code:         "METADATA_NOT_FOUND",
+		Description:  "There is no metadata for the user.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "METADATA_TOKEN_EXPIRED",
+		Description:  "The metadata token (X-Account-Metadata) is too old. It must be refreshed via refreshMetadata.",
+		Retry:        AfterRefreshRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusUnauthorized},
+	},
+	{
+		Code:         "METADATA_TOKEN_MISMATCH",
+		Description:  "The metadata token belongs to another user.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "MISSING_PROPERTIES",
+		Description:  "Some of the required properties are missing.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoService, EskimoHutService},
+		HTTPStatuses: []int{http.StatusUnprocessableEntity},
+	},
+	{
+		Code:         "NOTHING_TO_RECTIFY",
+		Description:  "The user already has the values of the rectification.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusUnprocessableEntity},
+	},
+	{
+		Code:         "NO_PENDING_LOGIN_SESSION",
+		Description:  "There is no pending login session to check the status of.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "NO_PENDING_SIGN_IN_STEP_UP",
+		Description:  "There is no pending face liveness re-check to complete for the login session.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "OPERATION_NOT_ALLOWED",
+		Description:  "The authenticated user is not allowed to do this.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoService, EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "PASSKEY_ALREADY_REGISTERED",
+		Description:  "The passkey credential is already registered, for another device or user.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusConflict},
+	},
+	{
+		Code:         "PASSKEY_CHALLENGE_NOT_FOUND",
+		Description:  "The passkey challenge is unknown, expired or already used. New options have to be requested.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "PASSKEY_NOT_FOUND",
+		Description:  "The passkey is not registered, or it was deleted.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "PROFANITY_NOT_ALLOWED",
+		Description:  "The username, firstName or lastName contains a blocklisted word, in the language of the user. The field is in `data.details.fields`.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "QUIZ_DISABLED",
+		Description:  "The quiz is not available for the user.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "QUIZ_UNKNOWN_QUESTION_NUM",
+		Description:  "The question number is not the one expected by the quiz session.",
+		Retry:        AfterRefreshRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "RACE_CONDITION",
+		Description:  "The resource was changed in the meantime.",
+		Retry:        AfterRefreshRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest, http.StatusConflict},
+	},
+	{
+		Code:         "REFERRAL_INVITATION_NOT_FOUND",
+		Description:  "There is no referral invitation with the provided unsubscribe token.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "REFERRAL_NOT_FOUND",
+		Description:  "The referral (`referredBy`) was not found.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "SIGNED_UPLOAD_NOT_SUPPORTED",
+		Description:  "The profile picture storage doesn't support direct uploads. The picture has to be sent as `profilePicture` instead.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "SIGN_IN_LOCKOUT_NOT_FOUND",
+		Description:  "There is no sign in for the provided email and device, so there's nothing to unblock.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "SIGN_IN_STEP_UP_FAILED",
+		Description:  "The face liveness re-check did not pass, so the sign in can't be finished.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "SIGN_IN_STEP_UP_REQUIRED",
+		Description:  "The sign in is risky, so a face liveness re-check must be completed before the tokens are issued.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "SOCIAL_KYC_STEP_ALREADY_COMPLETED_SUCCESSFULLY",
+		Description:  "The social KYC step was already completed successfully.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusConflict},
+	},
+	{
+		Code:         "SOCIAL_KYC_STEP_NOT_AVAILABLE",
+		Description:  "The social KYC step is not available for the user right now.",
+		Retry:        WithBackoffRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "SQUATTED_USERNAME_NOT_FOUND",
+		Description:  "The user has no flagged username to release.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "STRUCTURE_VALIDATION_FAILED",
+		Description:  "The request couldn't be parsed.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoService, EskimoHutService},
+		HTTPStatuses: []int{http.StatusUnprocessableEntity},
+	},
+	{
+		Code:         "TOO_MANY_BLOCKCHAIN_ADDRESSES",
+		Description:  "The user already has the maximum number of blockchain addresses. One has to be removed first.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "TOO_MANY_EMAILS",
+		Description:  "The user already has the maximum number of secondary emails. One has to be removed first.",
+		Retry:        AfterFixRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "TOO_MANY_REQUESTS",
+		Description:  "Too many sign in links were requested (per IP, email, device or overall, see `data.throttle`), or too many public statistics requests were made from the same IP. `data.retryAfterSeconds`, like the `Retry-After` header, tells how long to wait.",
+		Retry:        WithBackoffRetryPolicy,
+		Services:     []Service{EskimoService, EskimoHutService},
+		HTTPStatuses: []int{http.StatusTooManyRequests},
+	},
+	{
+		Code:         "UNDERAGE_USER",
+		Description:  "The user is younger than the minimum age of its country, as per its date of birth. It's blocked and it's going to be deleted.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusForbidden},
+	},
+	{
+		Code:         "UPDATE_REQUIRED",
+		Description:  "The app version is not supported anymore, it has to be updated.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "USER_ALREADY_REPORTED",
+		Description:  "The user was already reported by the same user, and the report is still pending.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusConflict},
+	},
+	{
+		Code:         "USER_BLOCKED",
+		Description:  "The user is blocked, temporarily, because of too many attempts.",
+		Retry:        WithBackoffRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusBadRequest},
+	},
+	{
+		Code:         "USER_DELETION_BATCH_NOT_FOUND",
+		Description:  "The user deletion batch was not found.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "USER_IMPORT_BATCH_NOT_FOUND",
+		Description:  "The user import batch was not found.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "USER_NOT_FOUND",
+		Description:  "The user was not found.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoService, EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+	{
+		Code:         "USER_REPORTS_NOT_FOUND",
+		Description:  "The user has no pending reports.",
+		Retry:        NeverRetryPolicy,
+		Services:     []Service{EskimoHutService},
+		HTTPStatuses: []int{http.StatusNotFound},
+	},
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package errorcatalog
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogIsSortedAndUnique(t *testing.T) {
+	t.Parallel()
+	codes := make([]string, 0, len(Catalog()))
+	for _, entry := range Catalog() {
+		assert.NotEmpty(t, entry.Description, entry.Code)
+		assert.NotEmpty(t, entry.Retry, entry.Code)
+		assert.NotEmpty(t, entry.Services, entry.Code)
+		assert.NotEmpty(t, entry.HTTPStatuses, entry.Code)
+		codes = append(codes, entry.Code)
+	}
+	assert.True(t, sort.StringsAreSorted(codes))
+	for i := 1; i < len(codes); i++ {
+		assert.NotEqual(t, codes[i-1], codes[i])
+	}
+}
+
+func TestCatalogContainsAllErrorCodes(t *testing.T) {
+	t.Parallel()
+	cataloged := make(map[string]struct{}, len(Catalog()))
+	for _, entry := range Catalog() {
+		cataloged[entry.Code] = struct{}{}
+	}
+	for _, contract := range []string{"../eskimo/contract.go", "../eskimo-hut/contract.go", "../maintenance/contract.go"} {
+		codes := errorCodesDeclaredIn(t, contract)
+		require.NotEmpty(t, codes, contract)
+		for _, code := range codes {
+			assert.Contains(t, cataloged, code, contract)
+		}
+	}
+}
+
+func errorCodesDeclaredIn(t *testing.T, file string) []string {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ParseComments)
+	require.NoError(t, err)
+	codeRegex := regexp.MustCompile(`^[A-Z][A-Z_]+$`)
+	var codes []string
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST || genDecl.Doc == nil || !strings.Contains(genDecl.Doc.Text(), "server.ErrorResponse#Code") {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			for _, value := range spec.(*ast.ValueSpec).Values { //nolint:forcetypeassert // It's always a value spec for consts.
+				lit, isLit := value.(*ast.BasicLit)
+				if !isLit || lit.Kind != token.STRING {
+					continue
+				}
+				if code, uErr := strconv.Unquote(lit.Value); uErr == nil && codeRegex.MatchString(code) {
+					codes = append(codes, code)
+				}
+			}
+		}
+	}
+
+	return codes
+}
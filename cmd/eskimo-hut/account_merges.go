@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupAccountMergesRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("account-merges", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.MergeAccounts)))
+}
+
+// MergeAccounts godoc
+//
+//	@Schemes
+//	@Description	Merges a duplicate account (the source) into another one (the target), which keeps its ID, username, email and phone number. Only for admins.
+//	@Description	The referrals, devices, KYC state (if further) and metadata of the source are moved to the target; then the source is deleted.
+//	@Description	The same can be requested asynchronously, via the account merge requests topic.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string						true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string						false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			request				body		MergeAccountsRequestBody	true	"Request params"
+//	@Success		200					{object}	User					"the merged target user"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if any of the users is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/account-merges [POST].
+func (s *service) MergeAccounts( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[MergeAccountsRequestBody, User],
+) (*server.Response[User], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if strings.TrimSpace(req.Data.Reason) == "" {
+		return nil, server.UnprocessableEntity(errors.New("reason is required"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.RequiredReason, "reason"))
+	}
+	merge := &users.AccountMerge{
+		SourceUserID: req.Data.SourceUserID,
+		TargetUserID: req.Data.TargetUserID,
+		RequestedBy:  req.AuthenticatedUser.UserID,
+		Reason:       strings.TrimSpace(req.Data.Reason),
+	}
+	merged, err := s.usersProcessor.MergeAccounts(ctx, merge)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to MergeAccounts for %#v", req.Data)
+		switch {
+		case errors.Is(err, users.ErrInvalidAccountMerge):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode,
+				errorcatalog.FieldsData(errorcatalog.InvalidReason, "sourceUserId", "targetUserId"))
+		case errors.Is(err, users.ErrNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(&User{User: merged, Checksum: merged.Checksum()}), nil
+}
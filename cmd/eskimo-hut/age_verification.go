@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+// checkUnderage blocks the write calls of the underage users until they're deleted.
+// They can still read their data, sign in and delete their account themselves, while the admins are never blocked.
+func (s *service) checkUnderage(ginCtx *gin.Context) {
+	if ginCtx.Request.Method == http.MethodGet || strings.HasPrefix(ginCtx.FullPath(), authPathPrefix) ||
+		(ginCtx.Request.Method == http.MethodDelete && ginCtx.FullPath() == deleteUserPath) {
+		return
+	}
+	authorization := strings.TrimPrefix(ginCtx.GetHeader("Authorization"), "Bearer ")
+	if authorization == "" {
+		return
+	}
+	ctx := ginCtx.Request.Context()
+	token, err := server.Auth(ctx).VerifyToken(ctx, authorization)
+	if err != nil {
+		return
+	}
+	if token, err = server.Auth(ctx).ModifyTokenWithMetadata(token, ginCtx.GetHeader(xAccountMetadataHeader)); err != nil || token.Role == adminRole {
+		return
+	}
+	if err = s.usersProcessor.CheckUnderage(ctx, token.UserID); err != nil {
+		errResp := server.Unexpected(errors.Wrapf(err, "failed to CheckUnderage for userID:%v", token.UserID))
+		if errors.Is(err, users.ErrUnderage) {
+			errResp = server.ForbiddenWithCode(err, underageUserErrorCode)
+		}
+		log.Error(errors.Wrapf(err, "endpoint %v failed", ginCtx.FullPath()))
+		ginCtx.AbortWithStatusJSON(errResp.Code, errResp.Data)
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupAgendaContactNamesRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		PUT("users/:userId/agenda-contact-names", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.SetAgendaContactNames))).
+		DELETE("users/:userId/agenda-contact-names", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.DeleteAgendaContactNames)))
+}
+
+// SetAgendaContactNames godoc
+//
+//	@Schemes
+//	@Description	Stores, encrypted, the names the user has in its agenda for its contacts, keyed by their phone number hashes, so that they're returned
+//	@Description	with its `CONTACTS` referrals. Only the names the user consented to store are stored; the previously stored ones without it are deleted.
+//	@Description	Only for the user itself.
+//	@Tags			Referrals
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string								true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string								false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string								true	"ID of the user"
+//	@Param			request				body	SetAgendaContactNamesRequestBody	true	"Request params"
+//	@Success		200					"OK"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"user is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/agenda-contact-names [PUT].
+func (s *service) SetAgendaContactNames( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[SetAgendaContactNamesRequestBody, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := validateAgendaContactNames(req.Data.Contacts); err != nil {
+		return nil, err
+	}
+	if err := s.usersProcessor.SetAgendaContactNames(ctx, req.Data.UserID, req.Data.Contacts); err != nil {
+		err = errors.Wrapf(err, "failed to SetAgendaContactNames for userID:%v", req.Data.UserID)
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK[any](), nil
+}
+
+func validateAgendaContactNames(contacts []*users.AgendaContactName) *server.Response[server.ErrorResponse] {
+	if len(contacts) == 0 || len(contacts) > maxAgendaContactNames {
+		return server.UnprocessableEntity(errors.Errorf("between 1 and %v contacts are required", maxAgendaContactNames),
+			invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "contacts"))
+	}
+	for _, contact := range contacts {
+		if contact == nil || contact.PhoneNumberHash == "" {
+			return server.UnprocessableEntity(errors.New("each contact must have a phoneNumberHash"),
+				invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.RequiredReason, "phoneNumberHash"))
+		}
+		contact.DisplayName = strings.TrimSpace(contact.DisplayName)
+		if contact.Consent && (contact.DisplayName == "" || utf8.RuneCountInString(contact.DisplayName) > maxAgendaContactNameLength) {
+			return server.UnprocessableEntity(errors.Errorf("displayName must have between 1 and %v characters", maxAgendaContactNameLength),
+				invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "displayName"))
+		}
+	}
+
+	return nil
+}
+
+// DeleteAgendaContactNames godoc
+//
+//	@Schemes
+//	@Description	Deletes the stored name of a contact of the user, or all of them. Only for the user itself.
+//	@Tags			Referrals
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string	true	"ID of the user"
+//	@Param			phoneNumberHash		query	string	false	"Deletes only the name of the contact with it"
+//	@Success		200					"OK - deleted"
+//	@Success		204					"No Content - there was nothing to delete"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/agenda-contact-names [DELETE].
+func (s *service) DeleteAgendaContactNames( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[DeleteAgendaContactNamesArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := s.usersProcessor.DeleteAgendaContactNames(ctx, req.Data.UserID, req.Data.PhoneNumberHash); err != nil {
+		err = errors.Wrapf(err, "failed to DeleteAgendaContactNames for %#v", req.Data)
+		if errors.Is(err, users.ErrNotFound) {
+			return server.NoContent(), nil
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK[any](), nil
+}
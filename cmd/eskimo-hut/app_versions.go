@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupAppVersionRequirementsRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		PUT("app-version-requirements/:platform", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.SetAppVersionRequirement)))
+}
+
+// SetAppVersionRequirement godoc
+//
+//	@Schemes
+//	@Description	Overrides, at runtime, the earliest supported mobile app version of the platform and whether the older apps are forced to update. Only for admins.
+//	@Description	The devices with an older app can't update their metadata anymore and, if forced, every write call made from them fails with `UPDATE_REQUIRED`.
+//	@Tags			Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string								true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string								false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			platform			path		string								true	"The platform"					Enums(android,ios)
+//	@Param			request				body		SetAppVersionRequirementRequestBody	true	"Request params"
+//	@Success		200					{object}	users.AppVersionRequirement
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/app-version-requirements/{platform} [PUT].
+func (s *service) SetAppVersionRequirement( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[SetAppVersionRequirementRequestBody, users.AppVersionRequirement],
+) (*server.Response[users.AppVersionRequirement], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	requirement := &users.AppVersionRequirement{
+		UpdatedBy:   req.AuthenticatedUser.UserID,
+		Platform:    req.Data.Platform,
+		MinVersion:  req.Data.MinVersion,
+		ForceUpdate: *req.Data.ForceUpdate,
+	}
+	if err := s.usersProcessor.SetAppVersionRequirement(ctx, requirement); err != nil {
+		err = errors.Wrapf(err, "failed to SetAppVersionRequirement for %#v", requirement)
+		switch {
+		case errors.Is(err, users.ErrInvalidPlatform):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "platform"))
+		case errors.Is(err, users.ErrInvalidAppVersion):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "minVersion"))
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(requirement), nil
+}
+
+// checkAppVersion rejects the write calls made from the devices that are forced to update their app.
+// The calls needed to actually update it (the auth ones and replacing the device metadata) are let through,
+// and so are the ones without a device in the token, because there's nothing to check (the handlers deal with the invalid tokens).
+func (s *service) checkAppVersion(ginCtx *gin.Context) {
+	if ginCtx.Request.Method == http.MethodGet || ginCtx.FullPath() == replaceDeviceMetadataPath || strings.HasPrefix(ginCtx.FullPath(), authPathPrefix) {
+		return
+	}
+	authorization := strings.TrimPrefix(ginCtx.GetHeader("Authorization"), "Bearer ")
+	if authorization == "" {
+		return
+	}
+	ctx := ginCtx.Request.Context()
+	token, err := server.Auth(ctx).VerifyToken(ctx, authorization)
+	if err != nil {
+		return
+	}
+	if token, err = server.Auth(ctx).ModifyTokenWithMetadata(token, ginCtx.GetHeader(xAccountMetadataHeader)); err != nil {
+		return
+	}
+	deviceUniqueID, _ := token.Claims[deviceIDTokenClaim].(string) //nolint:errcheck // Firebase tokens don't have it.
+	if deviceUniqueID == "" {
+		return
+	}
+	deviceID := &users.DeviceID{UserID: token.UserID, DeviceUniqueID: deviceUniqueID}
+	if err = s.usersProcessor.CheckDeviceAppVersion(ctx, deviceID); err != nil {
+		errResp := server.Unexpected(errors.Wrapf(err, "failed to CheckDeviceAppVersion for %#v", deviceID))
+		if errors.Is(err, users.ErrOutdatedAppVersion) {
+			errResp = server.BadRequest(err, deviceMetadataAppUpdateRequireErrorCode)
+		}
+		log.Error(errors.Wrapf(err, "endpoint %v failed", ginCtx.FullPath()))
+		ginCtx.AbortWithStatusJSON(errResp.Code, errResp.Data)
+	}
+}
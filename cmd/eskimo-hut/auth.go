@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"net/http"
 	"net/mail"
 	"strings"
 	stdlibtime "time"
@@ -12,6 +13,9 @@ import (
 	"github.com/pkg/errors"
 
 	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/cmd/ratelimit"
 	"github.com/ice-blockchain/eskimo/users"
 	"github.com/ice-blockchain/wintr/auth"
 	"github.com/ice-blockchain/wintr/log"
@@ -23,13 +27,19 @@ import (
 func (s *service) setupAuthRoutes(router *server.Router) {
 	router.
 		Group("v1w").
-		POST("auth/sendSignInLinkToEmail", server.RootHandler(s.SendSignInLinkToEmail)).
-		POST("auth/refreshTokens", server.RootHandler(s.RegenerateTokens)).
-		POST("auth/signInWithEmailLink", server.RootHandler(s.SignIn)).
-		POST("auth/getConfirmationStatus", server.RootHandler(s.Status)).
-		POST("auth/getMetadata", server.RootHandler(s.Metadata)).
-		POST("auth/processFaceRecognitionResult", server.RootHandler(s.ProcessFaceRecognitionResult)).
-		POST("auth/getValidUserForPhoneNumberMigration", server.RootHandler(s.GetValidUserForPhoneNumberMigration))
+		POST("auth/sendSignInLinkToEmail", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.SendSignInLinkToEmail))).
+		POST("auth/refreshTokens", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.RegenerateTokens))).
+		POST("auth/signInWithEmailLink", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.SignIn))).
+		POST("auth/getConfirmationStatus", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.Status))).
+		POST("auth/completeSignInStepUp", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.CompleteSignInStepUp))).
+		POST("auth/unblockSignIn", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.UnblockSignIn))).
+		GET("auth/.well-known/jwks.json", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.GetJSONWebKeySet))).
+		POST("auth/introspect", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.IntrospectToken))).
+		POST("auth/logout-all", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.LogoutEverywhere))).
+		POST("auth/getMetadata", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.Metadata))).
+		POST("auth/refreshMetadata", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.RefreshMetadata))).
+		POST("auth/processFaceRecognitionResult", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.ProcessFaceRecognitionResult))).
+		POST("auth/getValidUserForPhoneNumberMigration", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.GetValidUserForPhoneNumberMigration)))
 }
 
 // SendSignInLinkToEmail godoc
@@ -44,9 +54,9 @@ func (s *service) setupAuthRoutes(router *server.Router) {
 //	@Param			X-User-ID		header		string							false	"UserID to process phone number migration for"	default()
 //	@Param			X-Forwarded-For	header		string							false	"Client IP"										default(1.1.1.1)
 //	@Success		200				{object}	Auth
-//	@Failure		403				{object}	server.ErrorResponse	"if too many pending auth requests from one IP"
 //	@Failure		409				{object}	server.ErrorResponse	"if email conflicts with another user's"
 //	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		429				{object}	server.ErrorResponse	"if too many sign in links were requested; `data.throttle` tells which throttle and `data.retryAfterSeconds`, like the `Retry-After` header, how long to wait"
 //	@Failure		500				{object}	server.ErrorResponse
 //	@Failure		504				{object}	server.ErrorResponse	"if request times out"
 //	@Router			/auth/sendSignInLinkToEmail [POST].
@@ -59,9 +69,10 @@ func (s *service) SendSignInLinkToEmail( //nolint:gocritic,funlen // .
 	}
 	email := strings.TrimSpace(strings.ToLower(req.Data.Email))
 	if _, err := mail.ParseAddress(email); err != nil {
-		return nil, server.BadRequest(err, invalidEmail)
+		return nil, server.BadRequest(err, invalidEmail, errorcatalog.FieldsData(errorcatalog.InvalidReason, "email"))
 	}
-	ctx = emaillink.ContextWithPhoneNumberToEmailMigration(ctx, req.Data.UserID) //nolint:revive // Not a problem.
+	ctx = emaillink.ContextWithPhoneNumberToEmailMigration(ctx, req.Data.UserID)  //nolint:revive // Not a problem.
+	ctx = emaillink.ContextWithDeviceFingerprint(ctx, req.Data.DeviceFingerprint) //nolint:revive // Not a problem.
 	loginSession, err := s.authEmailLinkClient.SendSignInLinkToEmail(ctx, email, req.Data.DeviceUniqueID, req.Data.Language, req.ClientIP.String())
 	if err != nil {
 		switch {
@@ -71,11 +82,17 @@ func (s *service) SendSignInLinkToEmail( //nolint:gocritic,funlen // .
 			}
 		case errors.Is(err, emaillink.ErrUserDuplicate):
 			if tErr := terror.As(err); tErr != nil {
-				return nil, server.Conflict(err, duplicateUserErrorCode, tErr.Data)
+				return nil, server.Conflict(err, duplicateUserErrorCode, errorcatalog.ConflictData(tErr.Data))
 			}
 		case errors.Is(err, emaillink.ErrTooManyAttempts):
 			if tErr := terror.As(err); tErr != nil {
-				return nil, server.ForbiddenWithCode(err, tooManyRequests, tErr.Data)
+				errResp := server.ForbiddenWithCode(err, tooManyRequests, tErr.Data)
+				errResp.Code = http.StatusTooManyRequests
+				if retryAfter, ok := tErr.Data["retryAfterSeconds"].(uint64); ok {
+					ratelimit.SetRetryAfter(ctx, retryAfter)
+				}
+
+				return nil, errResp
 			}
 		default:
 			return nil, server.Unexpected(errors.Wrapf(err, "failed to start email link auth %#v", req.Data))
@@ -93,7 +110,7 @@ func (s *service) SendSignInLinkToEmail( //nolint:gocritic,funlen // .
 //	@Produce		json
 //	@Param			request	body		MagicLinkPayload	true	"Request params"
 //	@Success		200		{object}	any
-//	@Failure		400		{object}	server.ErrorResponse	"if invalid or expired payload provided"
+//	@Failure		400		{object}	server.ErrorResponse	"if invalid or expired payload or confirmation code provided; for wrong confirmation codes, `data` contains `remainingAttempts` and `blockedUntil`, if blocked"
 //	@Failure		404		{object}	server.ErrorResponse	"if email does not need to be confirmed by magic link"
 //	@Failure		422		{object}	server.ErrorResponse	"if syntax fails"
 //	@Failure		500		{object}	server.ErrorResponse
@@ -112,7 +129,7 @@ func (s *service) SignIn( //nolint:gocritic //.
 			return nil, server.NotFound(err, userNotFoundErrorCode)
 		case errors.Is(err, users.ErrDuplicate):
 			if tErr := terror.As(err); tErr != nil {
-				return nil, server.Conflict(err, duplicateUserErrorCode, tErr.Data)
+				return nil, server.Conflict(err, duplicateUserErrorCode, errorcatalog.ConflictData(tErr.Data))
 			}
 		case errors.Is(err, emaillink.ErrNoConfirmationRequired):
 			return nil, server.NotFound(err, confirmationCodeNotFoundErrorCode)
@@ -121,9 +138,11 @@ func (s *service) SignIn( //nolint:gocritic //.
 		case errors.Is(err, emaillink.ErrInvalidToken):
 			return nil, server.BadRequest(err, invalidOTPCodeErrorCode)
 		case errors.Is(err, emaillink.ErrConfirmationCodeAttemptsExceeded):
-			return nil, server.BadRequest(err, confirmationCodeAttemptsExceededErrorCode)
+			return nil, server.BadRequest(err, confirmationCodeAttemptsExceededErrorCode, confirmationCodeAttemptsData(err))
 		case errors.Is(err, emaillink.ErrConfirmationCodeWrong):
-			return nil, server.BadRequest(err, confirmationCodeWrongErrorCode)
+			return nil, server.BadRequest(err, confirmationCodeWrongErrorCode, confirmationCodeAttemptsData(err))
+		case errors.Is(err, emaillink.ErrConfirmationCodeExpired):
+			return nil, server.BadRequest(err, confirmationCodeExpiredErrorCode)
 		default:
 			return nil, server.Unexpected(err)
 		}
@@ -132,6 +151,15 @@ func (s *service) SignIn( //nolint:gocritic //.
 	return server.OK[any](), nil
 }
 
+// confirmationCodeAttemptsData returns the `remainingAttempts` and, if the sign in got blocked, the `blockedUntil`, so that the clients can display them.
+func confirmationCodeAttemptsData(err error) map[string]any {
+	if tErr := terror.As(err); tErr != nil {
+		return tErr.Data
+	}
+
+	return nil
+}
+
 // RegenerateTokens godoc
 //
 //	@Schemes
@@ -139,11 +167,12 @@ func (s *service) SignIn( //nolint:gocritic //.
 //	@Tags			Auth
 //	@Accept			json
 //	@Produce		json
-//	@Param			Authorization	header		string			true	"Insert your access token"	default(Bearer <Add access token here>)
-//	@Param			request			body		RefreshToken	true	"Body containing customClaims"
+//	@Param			Authorization			header		string			true	"Insert your access token"											default(Bearer <Add access token here>)
+//	@Param			X-Device-Fingerprint	header		string			false	"Fingerprint of the device, if the tokens were bound to one at sign in"	default()
+//	@Param			request					body		RefreshToken	true	"Body containing customClaims"
 //	@Success		200				{object}	RefreshedToken
 //	@Failure		400				{object}	server.ErrorResponse	"if users data from token does not match data in db"
-//	@Failure		403				{object}	server.ErrorResponse	"if invalid or expired refresh token provided"
+//	@Failure		403				{object}	server.ErrorResponse	"if invalid or expired refresh token provided or if it's bound to another device;code:DEVICE_ATTESTATION_REQUIRED if the device has no recent valid attestation"
 //	@Failure		404				{object}	server.ErrorResponse	"if user or confirmation not found"
 //	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
 //	@Failure		500				{object}	server.ErrorResponse
@@ -154,9 +183,14 @@ func (s *service) RegenerateTokens( //nolint:gocritic // .
 	req *server.Request[RefreshToken, RefreshedToken],
 ) (*server.Response[RefreshedToken], *server.Response[server.ErrorResponse]) {
 	tokenPayload := strings.TrimPrefix(req.Data.Authorization, "Bearer ")
+	ctx = emaillink.ContextWithDeviceFingerprint(ctx, req.Data.DeviceFingerprint) //nolint:revive // Not a problem.
 	tokens, err := s.authEmailLinkClient.RegenerateTokens(ctx, tokenPayload)
 	if err != nil {
 		switch {
+		case errors.Is(err, emaillink.ErrDeviceFingerprintMismatch):
+			return nil, server.ForbiddenWithCode(err, deviceFingerprintMismatchErrorCode)
+		case errors.Is(err, emaillink.ErrDeviceAttestationRequired):
+			return nil, server.ForbiddenWithCode(err, deviceAttestationRequiredErrorCode)
 		case errors.Is(err, emaillink.ErrUserNotFound):
 			return nil, server.NotFound(err, userNotFoundErrorCode)
 		case errors.Is(err, emaillink.ErrExpiredToken):
@@ -183,7 +217,7 @@ func (s *service) RegenerateTokens( //nolint:gocritic // .
 //	@Param			request	body		StatusArg	true	"Request params"
 //	@Success		200		{object}	Auth
 //	@Failure		422		{object}	server.ErrorResponse	"if syntax fails"
-//	@Failure		403		{object}	server.ErrorResponse	"if invalid or expired login session provided"
+//	@Failure		403		{object}	server.ErrorResponse	"if invalid or expired login session provided or if a face liveness re-check is required"
 //	@Failure		404		{object}	server.ErrorResponse	"if login session not found or confirmation code verifying failed"
 //	@Failure		500		{object}	server.ErrorResponse
 //	@Failure		504		{object}	server.ErrorResponse	"if request times out"
@@ -192,6 +226,7 @@ func (s *service) Status( //nolint:gocritic // .
 	ctx context.Context,
 	req *server.Request[StatusArg, Status],
 ) (*server.Response[Status], *server.Response[server.ErrorResponse]) {
+	ctx = emaillink.ContextWithDeviceFingerprint(ctx, req.Data.DeviceFingerprint) //nolint:revive // Not a problem.
 	tokens, emailConfirmed, err := s.authEmailLinkClient.Status(ctx, req.Data.LoginSession)
 	if err != nil {
 		err = errors.Wrapf(err, "failed to get status for: %#v", req.Data)
@@ -201,6 +236,10 @@ func (s *service) Status( //nolint:gocritic // .
 				return nil, server.NotFound(err, noPendingLoginSessionErrorCode)
 			case errors.Is(err, emaillink.ErrStatusNotVerified):
 				return server.OK(&Status{}), nil
+			case errors.Is(err, emaillink.ErrDeviceFingerprintMismatch):
+				return nil, server.ForbiddenWithCode(err, deviceFingerprintMismatchErrorCode)
+			case errors.Is(err, emaillink.ErrSignInStepUpRequired):
+				return nil, server.ForbiddenWithCode(err, signInStepUpRequiredErrorCode)
 			case errors.Is(err, emaillink.ErrInvalidToken):
 				return nil, server.Forbidden(err)
 			case errors.Is(err, emaillink.ErrExpiredToken):
@@ -220,6 +259,167 @@ func (s *service) Status( //nolint:gocritic // .
 	}), nil
 }
 
+// CompleteSignInStepUp godoc
+//
+//	@Schemes
+//	@Description	Completes the face liveness re-check required for a risky sign in. After it, the tokens can be fetched via the status of the auth process.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	CompleteSignInStepUpArg	true	"Request params"
+//	@Success		200		"OK"
+//	@Failure		403		{object}	server.ErrorResponse	"if invalid or expired login session provided or if the face liveness re-check failed"
+//	@Failure		404		{object}	server.ErrorResponse	"if login session not found or there's no pending face liveness re-check"
+//	@Failure		422		{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500		{object}	server.ErrorResponse
+//	@Failure		504		{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/completeSignInStepUp [POST].
+func (s *service) CompleteSignInStepUp( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[CompleteSignInStepUpArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := s.authEmailLinkClient.CompleteSignInStepUp(ctx, req.Data.LoginSession, req.Data.LivenessSessionID); err != nil {
+		err = errors.Wrapf(err, "failed to complete sign in step up for: %#v", req.Data)
+		switch {
+		case errors.Is(err, emaillink.ErrNoPendingLoginSession):
+			return nil, server.NotFound(err, noPendingLoginSessionErrorCode)
+		case errors.Is(err, emaillink.ErrNoPendingSignInStepUp):
+			return nil, server.NotFound(err, noPendingSignInStepUpErrorCode)
+		case errors.Is(err, emaillink.ErrSignInStepUpFailed):
+			return nil, server.ForbiddenWithCode(err, signInStepUpFailedErrorCode)
+		case errors.Is(err, emaillink.ErrInvalidToken), errors.Is(err, emaillink.ErrExpiredToken):
+			return nil, server.Forbidden(err)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
+
+// UnblockSignIn godoc
+//
+//	@Schemes
+//	@Description	Lifts the lockout of a login identity (email+device) and/or resets the login attempts of an IP. Every unblock is audited. Only for admins.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string				true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string				false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			request				body	UnblockSignInArg	true	"Request params"
+//	@Success		200					"OK"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if there is no sign in for the provided email and device"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/unblockSignIn [POST].
+func (s *service) UnblockSignIn( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[UnblockSignInArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	unblock := &req.Data.SignInUnblock
+	if (unblock.Email == "" && unblock.IP == "") || (unblock.Email != "" && unblock.DeviceUniqueID == "") {
+		return nil, server.UnprocessableEntity(errors.New("either email+deviceUniqueId or ip is required"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.RequiredReason, "email", "deviceUniqueId", "ip"))
+	}
+	if strings.TrimSpace(unblock.Reason) == "" {
+		return nil, server.UnprocessableEntity(errors.New("reason is required"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.RequiredReason, "reason"))
+	}
+	unblock.Email, unblock.AdminUserID = strings.TrimSpace(strings.ToLower(unblock.Email)), req.AuthenticatedUser.UserID
+	if err := s.authEmailLinkClient.UnblockSignIn(ctx, unblock); err != nil {
+		err = errors.Wrapf(err, "failed to unblock sign in for %#v", unblock)
+		if errors.Is(err, emaillink.ErrUserNotFound) {
+			return nil, server.NotFound(err, signInLockoutNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK[any](), nil
+}
+
+// GetJSONWebKeySet godoc
+//
+//	@Schemes
+//	@Description	Returns the public keys used to sign the login sessions, so that other services can verify them without sharing any secret.
+//	@Description	Keys that are not active yet are published as well, so they can be cached before the rotation.
+//	@Tags			Auth
+//	@Produce		json
+//	@Success		200	{object}	emaillink.JSONWebKeySet
+//	@Failure		500	{object}	server.ErrorResponse
+//	@Failure		504	{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/.well-known/jwks.json [GET].
+func (s *service) GetJSONWebKeySet( //nolint:gocritic // .
+	_ context.Context,
+	_ *server.Request[GetJSONWebKeySetArg, emaillink.JSONWebKeySet],
+) (*server.Response[emaillink.JSONWebKeySet], *server.Response[server.ErrorResponse]) {
+	return server.OK(s.authEmailLinkClient.JSONWebKeySet()), nil
+}
+
+// IntrospectToken godoc
+//
+//	@Schemes
+//	@Description	Tells the other services whether the access token is active: valid, not expired and not revoked (i.e. by refreshing the tokens or signing in again).
+//	@Description	Its claims are returned only if it's active. It's meant for the services that can't verify it offline, after a key rotation or a revocation.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-API-Key	header		string				true	"Insert your api key"	default(<Add api key here>)
+//	@Param			request		body		IntrospectTokenArg	true	"Request params"
+//	@Success		200			{object}	emaillink.TokenIntrospection
+//	@Failure		403			{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422			{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500			{object}	server.ErrorResponse
+//	@Failure		504			{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/introspect [POST].
+func (s *service) IntrospectToken( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[IntrospectTokenArg, emaillink.TokenIntrospection],
+) (*server.Response[emaillink.TokenIntrospection], *server.Response[server.ErrorResponse]) {
+	if cfg.APIKey != req.Data.APIKey {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	introspection, err := s.authEmailLinkClient.IntrospectToken(ctx, req.Data.Token)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrap(err, "failed to introspect token"))
+	}
+
+	return server.OK(introspection), nil
+}
+
+// LogoutEverywhere godoc
+//
+//	@Schemes
+//	@Description	Revokes all the tokens of the authenticated user, on all of its devices, so that they can't be refreshed anymore. The login flows in progress are invalidated as well.
+//	@Description	The access tokens stay valid, for the services that verify them offline, until they expire. The ones that introspect them see them as inactive immediately.
+//	@Tags			Auth
+//	@Produce		json
+//	@Param			Authorization		header	string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			X-Forwarded-For		header	string	false	"Client IP"						default(1.1.1.1)
+//	@Success		200					"OK"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/logout-all [POST].
+func (s *service) LogoutEverywhere( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[LogoutEverywhereArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := s.authEmailLinkClient.LogoutEverywhere(ctx, req.AuthenticatedUser.UserID, req.ClientIP.String()); err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to logout everywhere userID:%v", req.AuthenticatedUser.UserID))
+	}
+
+	return server.OK[any](), nil
+}
+
 // Metadata godoc
 //
 //	@Schemes
@@ -272,7 +472,7 @@ func (s *service) findMetadataUsingIceID(ctx context.Context, loggedInUser *serv
 	if iErr != nil {
 		return nil, server.NotFound(multierror.Append(
 			errors.Wrapf(err, "metadata for user with id `%v` was not found", loggedInUser.UserID),
-			errors.Wrapf(iErr, "failed to fetch iceID for email `%v`", loggedInUser.Email),
+			errors.Wrapf(iErr, "failed to fetch iceID for email `%v`", users.RedactedEmail(loggedInUser.Email)),
 		).ErrorOrNil(), metadataNotFoundErrorCode)
 	}
 	if iceID != "" { //nolint:nestif // Error processing
@@ -409,6 +609,11 @@ func (s *service) ProcessFaceRecognitionResult(
 			return nil, server.Unexpected(err)
 		}
 	}
+	if len(req.Data.LastUpdatedAt) > 0 || *req.Data.Disabled {
+		if err = s.usersProcessor.RecordKYCStepAttempt(ctx, usr.ID, users.FacialRecognitionKYCStep, !*req.Data.Disabled); err != nil {
+			log.Error(errors.Wrapf(err, "failed to RecordKYCStepAttempt for userID:%v", usr.ID))
+		}
+	}
 
 	return server.OK[any](), nil
 }
@@ -481,7 +686,7 @@ func (s *service) GetValidUserForPhoneNumberMigration( //nolint:funlen,revive //
 ) (successResp *server.Response[User], errorResp *server.Response[server.ErrorResponse]) {
 	req.Data.Email = strings.TrimSpace(strings.ToLower(req.Data.Email))
 	if _, err := mail.ParseAddress(req.Data.Email); req.Data.Email != "" && err != nil {
-		return nil, server.BadRequest(err, invalidEmail)
+		return nil, server.BadRequest(err, invalidEmail, errorcatalog.FieldsData(errorcatalog.InvalidReason, "email"))
 	}
 
 	usr, err := s.usersProcessor.GetUserByPhoneNumber(ctx, req.Data.PhoneNumber)
@@ -493,7 +698,7 @@ func (s *service) GetValidUserForPhoneNumberMigration( //nolint:funlen,revive //
 	case usr == nil:
 		return nil, server.NotFound(users.ErrNotFound, userNotFoundErrorCode)
 	case usr.Email != "":
-		return nil, server.Conflict(users.ErrDuplicate, emailAlreadySetErrorCode)
+		return nil, server.Conflict(users.ErrDuplicate, emailAlreadySetErrorCode, errorcatalog.FieldsData(errorcatalog.ConflictReason, "email"))
 	case !usr.IsHuman():
 		return nil, server.ForbiddenWithCode(errors.New("account is lost"), accountLostErrorCode)
 	}
@@ -501,12 +706,12 @@ func (s *service) GetValidUserForPhoneNumberMigration( //nolint:funlen,revive //
 	emailUsedBySomebodyElse, err := s.usersProcessor.IsEmailUsedBySomebodyElse(ctx, usr.ID, req.Data.Email)
 	if err != nil {
 		if errors.Is(err, users.ErrDuplicate) {
-			return nil, server.Conflict(users.ErrDuplicate, emailAlreadySetErrorCode)
+			return nil, server.Conflict(users.ErrDuplicate, emailAlreadySetErrorCode, errorcatalog.FieldsData(errorcatalog.ConflictReason, "email"))
 		}
 
 		return nil, server.Unexpected(errors.Wrapf(err, "failed to IsEmailUsedBySomebodyElse(%v,%v)", usr.ID, req.Data.Email))
 	} else if emailUsedBySomebodyElse {
-		return nil, server.Conflict(users.ErrDuplicate, emailUsedBySomebodyElseEmail)
+		return nil, server.Conflict(users.ErrDuplicate, emailUsedBySomebodyElseEmail, errorcatalog.FieldsData(errorcatalog.ConflictReason, "email"))
 	}
 	if req.Data.Email != "" {
 		if uid, gErr := server.Auth(ctx).GetUserUIDByEmail(ctx, req.Data.Email); gErr != nil || uid != "" {
@@ -514,7 +719,7 @@ func (s *service) GetValidUserForPhoneNumberMigration( //nolint:funlen,revive //
 				return nil, server.Unexpected(gErr)
 			}
 
-			return nil, server.Conflict(users.ErrDuplicate, emailUsedBySomebodyElseEmail)
+			return nil, server.Conflict(users.ErrDuplicate, emailUsedBySomebodyElseEmail, errorcatalog.FieldsData(errorcatalog.ConflictReason, "email"))
 		}
 	}
 
@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupBlockchainAddressesRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("users/:userId/blockchain-addresses/challenge", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.CreateBlockchainAddressChallenge))).
+		POST("users/:userId/blockchain-addresses", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.AddBlockchainAddress))).
+		DELETE("users/:userId/blockchain-addresses/:chain/:address", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.RemoveBlockchainAddress)))
+}
+
+// CreateBlockchainAddressChallenge godoc
+//
+//	@Schemes
+//	@Description	Issues the one time message the address has to sign, before it expires, to be added to the user. Only for the user itself.
+//	@Description	It replaces any previous challenge of the user for the address.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string										true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string										false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string										true	"ID of the user"
+//	@Param			request				body		CreateBlockchainAddressChallengeRequestBody	true	"Request params"
+//	@Success		201					{object}	users.BlockchainAddressChallenge
+//	@Failure		400					{object}	server.ErrorResponse	"if the address is invalid"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if user not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails or the chain is not supported"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/blockchain-addresses/challenge [POST].
+func (s *service) CreateBlockchainAddressChallenge( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[CreateBlockchainAddressChallengeRequestBody, users.BlockchainAddressChallenge],
+) (*server.Response[users.BlockchainAddressChallenge], *server.Response[server.ErrorResponse]) {
+	address := &users.BlockchainAddress{UserID: req.Data.UserID, Chain: req.Data.Chain, Address: req.Data.Address}
+	challenge, err := s.usersProcessor.CreateBlockchainAddressChallenge(ctx, address)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to CreateBlockchainAddressChallenge for %#v", req.Data)
+		switch {
+		case errors.Is(err, users.ErrRelationNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		case errors.Is(err, users.ErrUnsupportedBlockchainChain):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "chain"))
+		case errors.Is(err, users.ErrInvalidBlockchainAddress):
+			return nil, server.BadRequest(err, invalidBlockchainAddressErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "address"))
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.Created(challenge), nil
+}
+
+// AddBlockchainAddress godoc
+//
+//	@Schemes
+//	@Description	Adds a blockchain address to the user, as verified, or verifies it again if the user has it already. Only for the user itself.
+//	@Description	Its ownership is proven by signing, with the wallet, the message of the address' challenge, which is consumed regardless of the result.
+//	@Description	`evm` expects a `personal_sign` signature and `solana` an ed25519 one.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string							true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string							false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string							true	"ID of the user"
+//	@Param			request				body		AddBlockchainAddressRequestBody	true	"Request params"
+//	@Success		200					{object}	users.BlockchainAddress	"the user had it already"
+//	@Success		201					{object}	users.BlockchainAddress
+//	@Failure		400					{object}	server.ErrorResponse	"if the address or its signature is invalid or the user has too many addresses"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if there's no pending challenge for the address, or it expired"
+//	@Failure		409					{object}	server.ErrorResponse	"if the address belongs to another user"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails or the chain is not supported"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/blockchain-addresses [POST].
+func (s *service) AddBlockchainAddress( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[AddBlockchainAddressRequestBody, users.BlockchainAddress],
+) (*server.Response[users.BlockchainAddress], *server.Response[server.ErrorResponse]) {
+	address := &users.BlockchainAddress{UserID: req.Data.UserID, Chain: req.Data.Chain, Address: req.Data.Address}
+	verified, err := s.usersProcessor.AddBlockchainAddress(ctx, address, req.Data.Signature)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to AddBlockchainAddress for userID:%v, chain:%v, address:%v", req.Data.UserID, req.Data.Chain, req.Data.Address)
+		switch {
+		case errors.Is(err, users.ErrBlockchainAddressChallengeNotFound):
+			return nil, server.NotFound(err, blockchainChallengeNotFoundErrorCode)
+		case errors.Is(err, users.ErrUnsupportedBlockchainChain):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "chain"))
+		case errors.Is(err, users.ErrInvalidBlockchainAddress):
+			return nil, server.BadRequest(err, invalidBlockchainAddressErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "address"))
+		case errors.Is(err, users.ErrInvalidBlockchainAddressSignature):
+			return nil, server.BadRequest(err, invalidBlockchainSignatureErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "signature"))
+		case errors.Is(err, users.ErrTooManyBlockchainAddresses):
+			return nil, server.BadRequest(err, tooManyBlockchainAddressesErrorCode)
+		case errors.Is(err, users.ErrBlockchainAddressUsedBySomebodyElse):
+			return nil, server.Conflict(err, duplicateUserErrorCode, errorcatalog.FieldsData(errorcatalog.ConflictReason, "address"))
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	if !verified.CreatedAt.Equal(*verified.VerifiedAt.Time) {
+		return server.OK(verified), nil
+	}
+
+	return server.Created(verified), nil
+}
+
+// RemoveBlockchainAddress godoc
+//
+//	@Schemes
+//	@Description	Removes a blockchain address of the user. Only for the user itself.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string	true	"ID of the user"
+//	@Param			chain				path	string	true	"The chain of the address"	enums(evm,solana)
+//	@Param			address				path	string	true	"The address"
+//	@Success		200					"OK - removed"
+//	@Success		204					"No Content - the user doesn't have it"
+//	@Failure		400					{object}	server.ErrorResponse	"if the address is invalid"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails or the chain is not supported"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/blockchain-addresses/{chain}/{address} [DELETE].
+func (s *service) RemoveBlockchainAddress( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[RemoveBlockchainAddressArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	address := &users.BlockchainAddress{UserID: req.Data.UserID, Chain: req.Data.Chain, Address: req.Data.Address}
+	if err := s.usersProcessor.RemoveBlockchainAddress(ctx, address); err != nil {
+		err = errors.Wrapf(err, "failed to RemoveBlockchainAddress for %#v", req.Data)
+		switch {
+		case errors.Is(err, users.ErrNotFound):
+			return server.NoContent(), nil
+		case errors.Is(err, users.ErrUnsupportedBlockchainChain):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "chain"))
+		case errors.Is(err, users.ErrInvalidBlockchainAddress):
+			return nil, server.BadRequest(err, invalidBlockchainAddressErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "address"))
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
@@ -7,15 +7,22 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
 	"github.com/ice-blockchain/eskimo/users"
 	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/terror"
 )
 
 func (s *service) setupDevicesRoutes(router *server.Router) {
 	router.
 		Group("v1w").
-		PUT("users/:userId/devices/:deviceUniqueId/metadata", server.RootHandler(s.ReplaceDeviceMetadata)).
-		PUT("users/:userId/devices/:deviceUniqueId/metadata/location", server.RootHandler(s.GetDeviceLocation))
+		PUT("users/:userId/devices/:deviceUniqueId/metadata", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Devices, s.ReplaceDeviceMetadata))).
+		PUT("users/:userId/devices/:deviceUniqueId/metadata/location", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Devices, s.GetDeviceLocation))).
+		POST("users/:userId/devices/:deviceUniqueId/attestation/challenge", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Devices, s.CreateDeviceAttestationChallenge))).
+		POST("users/:userId/devices/:deviceUniqueId/attestation", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Devices, s.VerifyDeviceAttestation))).
+		GET("users/:userId/devices", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Devices, s.GetDevices))).
+		DELETE("users/:userId/devices/:deviceUniqueId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Devices, s.RevokeDevice)))
 }
 
 // ReplaceDeviceMetadata godoc
@@ -36,7 +43,7 @@ func (s *service) setupDevicesRoutes(router *server.Router) {
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
 //	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
 //	@Failure		404					{object}	server.ErrorResponse	"if user not found"
-//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails or some properties, like `systemVersion` or `locale`, are invalid"
 //	@Failure		500					{object}	server.ErrorResponse
 //	@Failure		504					{object}	server.ErrorResponse	"if request times out"
 //	@Router			/users/{userId}/devices/{deviceUniqueId}/metadata [PUT].
@@ -55,7 +62,14 @@ func (s *service) ReplaceDeviceMetadata( //nolint:gocritic // False negative.
 		case errors.Is(err, users.ErrRelationNotFound):
 			return nil, server.NotFound(err, userNotFoundErrorCode)
 		case errors.Is(err, users.ErrInvalidAppVersion):
-			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode)
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "readableVersion"))
+		case errors.Is(err, users.ErrInvalidDeviceMetadata):
+			var fields []string
+			if tErr := terror.As(err); tErr != nil {
+				fields, _ = tErr.Data["fields"].([]string) //nolint:errcheck // It's always set.
+			}
+
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, fields...))
 		case errors.Is(err, users.ErrOutdatedAppVersion):
 			return nil, server.BadRequest(err, deviceMetadataAppUpdateRequireErrorCode)
 		default:
@@ -78,7 +92,7 @@ func (s *service) ReplaceDeviceMetadata( //nolint:gocritic // False negative.
 //	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"								default(<Add metadata token here>)
 //	@Param			userId				path		string	true	"ID of the user. Is optional, set an `-` if none."
 //	@Param			deviceUniqueId		path		string	true	"ID of the device. Is optional, set an `-` if none."
-//	@Success		200					{object}	users.DeviceLocation
+//	@Success		200					{object}	users.EstimatedDeviceLocation
 //	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
 //	@Failure		401					{object}	server.ErrorResponse	"if not authenticated"
 //	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
@@ -88,8 +102,8 @@ func (s *service) ReplaceDeviceMetadata( //nolint:gocritic // False negative.
 //	@Router			/users/{userId}/devices/{deviceUniqueId}/metadata/location [PUT].
 func (s *service) GetDeviceLocation( //nolint:gocritic // False negative.
 	ctx context.Context,
-	req *server.Request[GetDeviceLocationArg, users.DeviceLocation],
-) (*server.Response[users.DeviceLocation], *server.Response[server.ErrorResponse]) {
+	req *server.Request[GetDeviceLocationArg, users.EstimatedDeviceLocation],
+) (*server.Response[users.EstimatedDeviceLocation], *server.Response[server.ErrorResponse]) {
 	if req.Data.UserID == "-" {
 		req.Data.UserID = ""
 	}
@@ -100,3 +114,176 @@ func (s *service) GetDeviceLocation( //nolint:gocritic // False negative.
 
 	return server.OK(s.usersProcessor.GetDeviceMetadataLocation(ctx, deviceID, req.ClientIP)), nil
 }
+
+// CreateDeviceAttestationChallenge godoc
+//
+//	@Schemes
+//	@Description	Issues a new one time challenge, for the device, that the next integrity attestation (Play Integrity nonce / App Attest clientData) must be bound to.
+//	@Tags			Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Param			deviceUniqueId		path		string	true	"ID of the device"
+//	@Success		201					{object}	users.DeviceAttestationChallenge
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if the device has no metadata"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/devices/{deviceUniqueId}/attestation/challenge [POST].
+func (s *service) CreateDeviceAttestationChallenge( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[CreateDeviceAttestationChallengeArg, users.DeviceAttestationChallenge],
+) (*server.Response[users.DeviceAttestationChallenge], *server.Response[server.ErrorResponse]) {
+	deviceID := &users.DeviceID{UserID: req.Data.UserID, DeviceUniqueID: req.Data.DeviceUniqueID}
+	challenge, err := s.usersProcessor.CreateDeviceAttestationChallenge(ctx, deviceID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to CreateDeviceAttestationChallenge for %#v", deviceID)
+		switch {
+		case errors.Is(err, users.ErrNotFound):
+			return nil, server.NotFound(err, deviceMetadataNotFoundErrorCode)
+		case errors.Is(err, users.ErrUnsupportedDeviceAttestationProvider):
+			return nil, server.Forbidden(err)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.Created(challenge), nil
+}
+
+// VerifyDeviceAttestation godoc
+//
+//	@Schemes
+//	@Description	Verifies the device's integrity attestation against its latest challenge and stores the result in the device's metadata.
+//	@Tags			Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string								true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string								false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string								true	"ID of the user"
+//	@Param			deviceUniqueId		path	string								true	"ID of the device"
+//	@Param			request				body	VerifyDeviceAttestationRequestBody	true	"Request params"
+//	@Success		200					"OK"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail or if the attestation is invalid"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if there is no challenge for the device (it was already used or it expired)"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/devices/{deviceUniqueId}/attestation [POST].
+func (s *service) VerifyDeviceAttestation( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[VerifyDeviceAttestationRequestBody, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	req.Data.DeviceAttestation.ID = users.DeviceID{UserID: req.Data.UserID, DeviceUniqueID: req.Data.DeviceUniqueID}
+	if err := s.usersProcessor.VerifyDeviceAttestation(ctx, &req.Data.DeviceAttestation); err != nil {
+		err = errors.Wrapf(err, "failed to VerifyDeviceAttestation for %#v", &req.Data.DeviceAttestation.ID)
+		switch {
+		case errors.Is(err, users.ErrDeviceAttestationChallengeNotFound):
+			return nil, server.NotFound(err, deviceAttestationChallengeNotFoundErrorCode)
+		case errors.Is(err, users.ErrInvalidDeviceAttestation):
+			return nil, server.BadRequest(err, invalidDeviceAttestationErrorCode)
+		case errors.Is(err, users.ErrUnsupportedDeviceAttestationProvider):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "provider"))
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
+
+// checkDeviceAttestation requires a recent valid attestation of the device the user is logged in with, if it's enabled for the purpose.
+func (s *service) checkDeviceAttestation(
+	ctx context.Context, loggedInUser *server.AuthenticatedUser, purpose users.DeviceAttestationPurpose,
+) *server.Response[server.ErrorResponse] {
+	deviceUniqueID, _ := loggedInUser.Claims[deviceIDTokenClaim].(string) //nolint:errcheck // Firebase tokens don't have it.
+	deviceID := &users.DeviceID{UserID: loggedInUser.UserID, DeviceUniqueID: deviceUniqueID}
+	if err := s.usersProcessor.CheckDeviceAttestation(ctx, deviceID, purpose); err != nil {
+		if errors.Is(err, users.ErrDeviceAttestationRequired) {
+			return server.ForbiddenWithCode(err, deviceAttestationRequiredErrorCode)
+		}
+
+		return server.Unexpected(errors.Wrapf(err, "failed to CheckDeviceAttestation for %#v", deviceID))
+	}
+
+	return nil
+}
+
+// GetDevices godoc
+//
+//	@Schemes
+//	@Description	Lists the devices of the user, the most recently seen first. Only for the user itself and for admins.
+//	@Tags			Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Success		200					{array}		users.Device
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/devices [GET].
+func (s *service) GetDevices( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetDevicesArg, []*users.Device],
+) (*server.Response[[]*users.Device], *server.Response[server.ErrorResponse]) {
+	if req.Data.UserID != req.AuthenticatedUser.UserID && req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	devices, err := s.usersProcessor.GetDevices(ctx, req.Data.UserID)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to GetDevices for %#v", req.Data))
+	}
+
+	return server.OK(&devices), nil
+}
+
+// RevokeDevice godoc
+//
+//	@Schemes
+//	@Description	Revokes a device of the user: deletes its metadata, so its push notification token too, and invalidates its sessions.
+//	@Description	Its refresh tokens can't be used anymore, but its access tokens are valid until they expire. Only for the user itself and for admins.
+//	@Tags			Devices
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string	true	"ID of the user"
+//	@Param			deviceUniqueId		path	string	true	"ID of the device"
+//	@Success		200					"OK - found and revoked"
+//	@Success		204					"No Content - already revoked"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/devices/{deviceUniqueId} [DELETE].
+func (s *service) RevokeDevice( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[RevokeDeviceArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if req.Data.UserID != req.AuthenticatedUser.UserID && req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if err := s.usersProcessor.RevokeDevice(ctx, &users.DeviceID{UserID: req.Data.UserID, DeviceUniqueID: req.Data.DeviceUniqueID}); err != nil {
+		if errors.Is(err, users.ErrNotFound) {
+			return server.NoContent(), nil
+		}
+
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to RevokeDevice for %#v", req.Data))
+	}
+
+	return server.OK[any](), nil
+}
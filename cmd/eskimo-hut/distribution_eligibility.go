@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (s *service) setupDistributionEligibilityRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("distribution-eligibility-snapshots", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.CreateDistributionEligibilitySnapshot)))
+}
+
+// CreateDistributionEligibilitySnapshot godoc
+//
+//	@Schemes
+//	@Description	Requests the distribution eligibility of all the users created until `asOf`, for the token distributions. Only for admins.
+//	@Description	It's exported in the background, as CSV parts, with the hash of their records, to audit them. It's available via `GET /v1r/distribution-eligibility-snapshots/{snapshotId}`.
+//	@Description	There's only one snapshot per `asOf`; requesting it again returns the existing one, with 200.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string										true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string										false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			request				body		CreateDistributionEligibilitySnapshotRequestBody	true	"Request params"
+//	@Success		200					{object}	users.DistributionEligibilitySnapshot	"if it was requested already"
+//	@Success		201					{object}	users.DistributionEligibilitySnapshot
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails or asOf is in the future"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/distribution-eligibility-snapshots [POST].
+func (s *service) CreateDistributionEligibilitySnapshot( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[CreateDistributionEligibilitySnapshotRequestBody, users.DistributionEligibilitySnapshot],
+) (*server.Response[users.DistributionEligibilitySnapshot], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if req.Data.AsOf.IsNil() || req.Data.AsOf.After(*time.Now().Time) {
+		return nil, server.UnprocessableEntity(errors.Errorf("asOf `%v` is required and can't be in the future", req.Data.AsOf), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.InvalidReason, "asOf"))
+	}
+	snapshot := &users.DistributionEligibilitySnapshot{AsOf: req.Data.AsOf, RequestedBy: req.AuthenticatedUser.UserID}
+	if err := s.usersProcessor.CreateDistributionEligibilitySnapshot(ctx, snapshot); err != nil {
+		if errors.Is(err, users.ErrDuplicate) {
+			return server.OK(snapshot), nil
+		}
+
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to CreateDistributionEligibilitySnapshot for %#v", req.Data))
+	}
+
+	return server.Created(snapshot), nil
+}
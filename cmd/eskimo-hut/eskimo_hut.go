@@ -4,13 +4,22 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"strings"
+	stdlibtime "time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
 	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/auth/telegram"
+	"github.com/ice-blockchain/eskimo/auth/webauthn"
+	"github.com/ice-blockchain/eskimo/cmd/configreload"
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
 	"github.com/ice-blockchain/eskimo/cmd/eskimo-hut/api"
+	"github.com/ice-blockchain/eskimo/cmd/maintenance"
+	"github.com/ice-blockchain/eskimo/cmd/ratelimit"
 	kycquiz "github.com/ice-blockchain/eskimo/kyc/quiz"
 	"github.com/ice-blockchain/eskimo/kyc/social"
 	"github.com/ice-blockchain/eskimo/users"
@@ -35,34 +44,101 @@ func main() {
 	api.SwaggerInfo.Host = cfg.Host
 	api.SwaggerInfo.Version = cfg.Version
 	cfg.APIKey = strings.ReplaceAll(cfg.APIKey, "\n", "")
+	mustValidateConfig()
+	server.New(new(service), applicationYamlKey, swaggerRoot).ListenAndServe(ctx, cancel)
+}
+
+// mustValidateConfig checks the config of every package used by the service before starting anything,
+// so that all the problems are reported at once, instead of panicking on the first one found while initializing.
+func mustValidateConfig() {
+	var mErr *multierror.Error
 	if cfg.APIKey == "" {
-		log.Panic("'api-key' is missing")
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.api-key` is missing", applicationYamlKey))
 	}
-	server.New(new(service), applicationYamlKey, swaggerRoot).ListenAndServe(ctx, cancel)
+	if cfg.Host == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.host` is missing", applicationYamlKey))
+	}
+	mErr = multierror.Append(mErr,
+		deadline.ValidateBudgets(applicationYamlKey, cfg.DefaultEndpointTimeout, map[string]stdlibtime.Duration{
+			"users":   cfg.RouteTimeouts.Users,
+			"devices": cfg.RouteTimeouts.Devices,
+			"kyc":     cfg.RouteTimeouts.KYC,
+			"auth":    cfg.RouteTimeouts.Auth,
+			"admin":   cfg.RouteTimeouts.Admin,
+		}),
+		ratelimit.ValidateConfig(applicationYamlKey, &cfg.RateLimits),
+		users.ValidateProcessorConfig(),
+		emaillink.ValidateConfig(),
+		webauthn.ValidateConfig(),
+		telegram.ValidateConfig(),
+		social.ValidateConfig(),
+		kycquiz.ValidateConfig(),
+	)
+	log.Panic(errors.Wrap(mErr.ErrorOrNil(), "invalid config, refusing to start"))
 }
 
 func (s *service) RegisterRoutes(router *server.Router) {
+	// They must be registered before the routes, to apply to them.
+	router.Use(maintenance.Middleware(s.usersProcessor, maintenanceModePath), ratelimit.Middleware(&cfg.RateLimits), ratelimit.RetryAfterMiddleware(),
+		s.checkMetadataToken, s.checkAppVersion, s.checkUnderage)
 	s.setupKYCRoutes(router)
 	s.setupUserRoutes(router)
+	s.setupUserBlocksRoutes(router)
+	s.setupBlockchainAddressesRoutes(router)
+	s.setupUserReportsRoutes(router)
+	s.setupReferralInvitationsRoutes(router)
+	s.setupAgendaContactNamesRoutes(router)
+	s.setupReferralPingsRoutes(router)
 	s.setupDevicesRoutes(router)
+	s.setupAppVersionRequirementsRoutes(router)
+	s.setupMaintenanceModeRoutes(router)
+	s.setupUserDeletionBatchesRoutes(router)
+	s.setupDistributionEligibilityRoutes(router)
+	s.setupUserImportBatchesRoutes(router)
+	s.setupUsernameSquattingRoutes(router)
+	s.setupAccountMergesRoutes(router)
+	s.setupUserRectificationsRoutes(router)
+	s.setupReferralIntegrityRoutes(router)
 	s.setupAuthRoutes(router)
+	s.setupUserEmailsRoutes(router)
+	s.setupPasskeysRoutes(router)
+	s.setupTelegramRoutes(router)
+	s.setupGuestAccountsRoutes(router)
+	s.setupOpenAPIRoutes(router)
+	if cfg.MetricsPath != "" {
+		// After the OpenAPI routes, so that it's not documented.
+		router.GET(cfg.MetricsPath, gin.WrapH(expvar.Handler()))
+	}
 }
 
 func (s *service) Init(ctx context.Context, cancel context.CancelFunc) {
 	s.usersProcessor = users.StartProcessor(ctx, cancel)
 	s.authEmailLinkClient = emaillink.NewClient(ctx, s.usersProcessor, server.Auth(ctx))
+	s.webAuthnClient = webauthn.New(ctx, s.authEmailLinkClient)
+	s.telegramClient = telegram.New(ctx, s.authEmailLinkClient, s.usersProcessor)
 	s.socialRepository = social.New(ctx, s.usersProcessor)
 	s.quizRepository = kycquiz.NewRepository(ctx, s.usersProcessor)
+	go configreload.Start(ctx, cfg.ConfigWatchInterval, s.usersProcessor, s.quizRepository)
 }
 
 func (s *service) Close(ctx context.Context) error {
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "could not close usersProcessor because context ended")
 	}
+	var webAuthnErr error
+	if s.webAuthnClient != nil {
+		webAuthnErr = errors.Wrap(s.webAuthnClient.Close(), "could not close webAuthnClient")
+	}
+	var telegramErr error
+	if s.telegramClient != nil {
+		telegramErr = errors.Wrap(s.telegramClient.Close(), "could not close telegramClient")
+	}
 
 	return multierror.Append( //nolint:wrapcheck // Not needed.
 		errors.Wrap(s.quizRepository.Close(), "could not close quiz repository"),
 		errors.Wrap(s.socialRepository.Close(), "could not close socialRepository"),
+		webAuthnErr,
+		telegramErr,
 		errors.Wrap(s.authEmailLinkClient.Close(), "could not close authEmailLinkClient"),
 		errors.Wrap(s.usersProcessor.Close(), "could not close usersProcessor"),
 	).ErrorOrNil()
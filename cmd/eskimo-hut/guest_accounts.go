@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupGuestAccountsRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("auth/signInAsGuest", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.SignInAsGuest))).
+		POST("users/:userId/guest-upgrade", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.UpgradeGuest)))
+}
+
+// SignInAsGuest godoc
+//
+//	@Schemes
+//	@Description	Creates a provisional account, bound to the device, without an email or a phone number, and returns its tokens.
+//	@Description	The tokens have the `guest` role, with limited capabilities. The user is created via `POST /users`, as usual.
+//	@Description	Once the user signs in with an email, the guest account can be upgraded via `POST /users/{userId}/guest-upgrade`.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SignInAsGuestRequestBody	true	"Request params"
+//	@Success		200		{object}	RefreshedToken
+//	@Failure		403		{object}	server.ErrorResponse	"if guest accounts are disabled"
+//	@Failure		422		{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500		{object}	server.ErrorResponse
+//	@Failure		504		{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/signInAsGuest [POST].
+func (s *service) SignInAsGuest( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[SignInAsGuestRequestBody, RefreshedToken],
+) (*server.Response[RefreshedToken], *server.Response[server.ErrorResponse]) {
+	tokens, err := s.authEmailLinkClient.SignInAsGuest(ctx, req.Data.DeviceUniqueID, req.ClientIP.String())
+	if err != nil {
+		err = errors.Wrapf(err, "failed to SignInAsGuest for %#v", req.Data)
+		if errors.Is(err, emaillink.ErrGuestAccountsDisabled) {
+			return nil, server.Forbidden(err)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK(&RefreshedToken{Tokens: tokens}), nil
+}
+
+// UpgradeGuest godoc
+//
+//	@Schemes
+//	@Description	Merges the guest account, proven by its token, into the fully registered account of the user, after it signed in with an email.
+//	@Description	The referral of the guest is kept, unless the registered account got a non random one, and so is its client data. The guest is deleted.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string					true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string					false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string					true	"ID of the user"
+//	@Param			request				body		UpgradeGuestRequestBody	true	"Request params"
+//	@Success		200					{object}	User					"the upgraded user"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail or the guest token is invalid"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if any of the users is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/guest-upgrade [POST].
+func (s *service) UpgradeGuest( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[UpgradeGuestRequestBody, User],
+) (*server.Response[User], *server.Response[server.ErrorResponse]) {
+	if errResp := forbidGuests(&req.AuthenticatedUser); errResp != nil {
+		return nil, errResp
+	}
+	guestUserID, err := s.authEmailLinkClient.GuestUserID(ctx, req.Data.GuestToken)
+	if err != nil {
+		return nil, server.BadRequest(errors.Wrapf(err, "invalid guest token for userID:%v", req.Data.UserID), invalidGuestTokenErrorCode,
+			errorcatalog.FieldsData(errorcatalog.InvalidReason, "guestToken"))
+	}
+	upgraded, err := s.usersProcessor.UpgradeGuest(ctx, guestUserID, req.Data.UserID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to UpgradeGuest %v for userID:%v", guestUserID, req.Data.UserID)
+		switch {
+		case errors.Is(err, users.ErrInvalidAccountMerge):
+			return nil, server.BadRequest(err, invalidGuestTokenErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "guestToken"))
+		case errors.Is(err, users.ErrNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(&User{User: upgraded, Checksum: upgraded.Checksum()}), nil
+}
+
+// forbidGuests rejects the operations that guest accounts can't do until they're upgraded, because they need a verified identity.
+func forbidGuests(authenticatedUser *server.AuthenticatedUser) *server.Response[server.ErrorResponse] {
+	if authenticatedUser.Role != emaillink.GuestRole {
+		return nil
+	}
+
+	return server.ForbiddenWithCode(errors.Errorf("guest userID:%v has to be upgraded first", authenticatedUser.UserID), guestAccountNotAllowedErrorCode)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
 	kycquiz "github.com/ice-blockchain/eskimo/kyc/quiz"
 	kycsocial "github.com/ice-blockchain/eskimo/kyc/social"
 	"github.com/ice-blockchain/eskimo/users"
@@ -20,10 +21,13 @@ import (
 func (s *service) setupKYCRoutes(router *server.Router) {
 	router.
 		Group("v1w").
-		POST("kyc/startOrContinueKYCStep4Session/users/:userId", server.RootHandler(s.StartOrContinueKYCStep4Session)).
-		POST("kyc/checkKYCStep4Status/users/:userId", server.RootHandler(s.CheckKYCStep4Status)).
-		POST("kyc/verifySocialKYCStep/users/:userId", server.RootHandler(s.VerifySocialKYCStep)).
-		POST("kyc/tryResetKYCSteps/users/:userId", server.RootHandler(s.TryResetKYCSteps))
+		POST("kyc/startOrContinueKYCStep4Session/users/:userId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.KYC, s.StartOrContinueKYCStep4Session))).
+		POST("kyc/checkKYCStep4Status/users/:userId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.KYC, s.CheckKYCStep4Status))).
+		GET("kyc/quiz/cooldown", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.KYC, s.GetKYCStep4Cooldown))).
+		GET("kyc/config", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.KYC, s.GetKYCConfig))).
+		POST("kyc/verifySocialKYCStep/users/:userId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.KYC, s.VerifySocialKYCStep))).
+		POST("kyc/tryResetKYCSteps/users/:userId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.KYC, s.TryResetKYCSteps))).
+		POST("kyc/purgeKYCData/users/:userId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.KYC, s.PurgeKYCData)))
 }
 
 func (s *service) startQuizSession(ctx context.Context, userID users.UserID, lang string) (*kycquiz.Quiz, error) {
@@ -53,32 +57,53 @@ func (s *service) startQuizSession(ctx context.Context, userID users.UserID, lan
 //	@Accept			json
 //	@Produce		json
 //
-//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
-//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
-//	@Param			userId				path		string	true	"ID of the user"
-//	@Param			language			query		string	true	"language of the user"
-//	@Param			selectedOption		query		int		true	"index of the options array. Set it to 222 for the first call."
-//	@Param			questionNumber		query		int		true	"previous question number. Set it to 222 for the first call."
-//	@Success		200					{object}	kycquiz.Quiz
-//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
-//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
-//	@Failure		403					{object}	server.ErrorResponse	"not allowed due to various reasons"
-//	@Failure		404					{object}	server.ErrorResponse	"user is not found"
-//	@Failure		409					{object}	server.ErrorResponse	"if any conflicts occur or any prerequisites are not met"
-//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
-//	@Failure		500					{object}	server.ErrorResponse
-//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Param			Authorization				header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata			header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			X-Anti-Bot-Challenge-Token	header		string	false	"the token of the solved anti-bot challenge, required only when asked for (ANTI_BOT_CHALLENGE_REQUIRED)"
+//	@Param			userId						path		string	true	"ID of the user"
+//	@Param			language					query		string	true	"language of the user"
+//	@Param			selectedOption				query		int		true	"index of the options array. Set it to 222 for the first call."
+//	@Param			questionNumber				query		int		true	"previous question number. Set it to 222 for the first call."
+//	@Success		200							{object}	kycquiz.Quiz
+//	@Failure		400							{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401							{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403							{object}	server.ErrorResponse	"not allowed due to various reasons;code:FEATURE_RESTRICTED_IN_COUNTRY if the quiz is unavailable in the country of the user;code:KYC_STEP_ATTEMPTS_EXCEEDED if the quiz was failed too many times;code:ANTI_BOT_CHALLENGE_REQUIRED if an anti-bot challenge has to be solved first;code:ANTI_BOT_CHALLENGE_FAILED if the challenge token is invalid;code:DEVICE_ATTESTATION_REQUIRED if the device has no recent valid attestation;code:GUEST_ACCOUNT_NOT_ALLOWED if the account is a guest one"
+//	@Failure		404							{object}	server.ErrorResponse	"user is not found"
+//	@Failure		409							{object}	server.ErrorResponse	"if any conflicts occur or any prerequisites are not met"
+//	@Failure		422							{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500							{object}	server.ErrorResponse
+//	@Failure		504							{object}	server.ErrorResponse	"if request times out"
 //	@Router			/kyc/startOrContinueKYCStep4Session/users/{userId} [POST].
 func (s *service) StartOrContinueKYCStep4Session( //nolint:gocritic,funlen // .
 	ctx context.Context,
 	req *server.Request[StartOrContinueKYCStep4SessionRequestBody, kycquiz.Quiz],
 ) (*server.Response[kycquiz.Quiz], *server.Response[server.ErrorResponse]) {
+	if errResp := forbidGuests(&req.AuthenticatedUser); errResp != nil {
+		return nil, errResp
+	}
 	const (
 		magicNumberQuizStart = 222
 	)
 
 	// Handle the session start.
 	if *req.Data.QuestionNumber == magicNumberQuizStart && *req.Data.SelectedOption == magicNumberQuizStart {
+		if errResp := s.checkFeatureAvailable(ctx, req.AuthenticatedUser.UserID, users.QuizKYCCountryRestrictedFeature); errResp != nil {
+			return nil, errResp
+		}
+		if errResp := s.checkDeviceAttestation(ctx, &req.AuthenticatedUser, users.KYCDeviceAttestationPurpose); errResp != nil {
+			return nil, errResp
+		}
+		if attempts, err := s.usersProcessor.CheckKYCStepAttempt(ctx, req.AuthenticatedUser.UserID, users.QuizKYCStep); err != nil {
+			if errors.Is(err, users.ErrKYCStepAttemptsExceeded) {
+				return nil, server.ForbiddenWithCode(err, kycStepAttemptsExceededErrorCode, map[string]any{
+					"nextAllowedAt": attempts.NextAllowedAt,
+					"maxAttempts":   attempts.MaxAttempts,
+				})
+			}
+
+			return nil, server.Unexpected(errors.Wrapf(err, "failed to CheckKYCStepAttempt for userID:%v", req.AuthenticatedUser.UserID))
+		}
+		ctx = kycquiz.ContextWithChallengeToken(ctx, req.Data.ChallengeToken) //nolint:revive // .
 		quiz, err := s.startQuizSession(ctx, req.AuthenticatedUser.UserID, req.Data.Language)
 		err = errors.Wrapf(err, "failed to StartQuizSession for userID:%v,language:%v", req.AuthenticatedUser.UserID, req.Data.Language)
 		if err != nil {
@@ -93,6 +118,12 @@ func (s *service) StartOrContinueKYCStep4Session( //nolint:gocritic,funlen // .
 			case errors.Is(err, kycquiz.ErrNotAvailable):
 				return nil, server.ForbiddenWithCode(err, quizDisbledErrorCode)
 
+			case errors.Is(err, kycquiz.ErrChallengeRequired):
+				return nil, server.ForbiddenWithCode(err, antiBotChallengeRequiredErrorCode)
+
+			case errors.Is(err, kycquiz.ErrChallengeFailed):
+				return nil, server.ForbiddenWithCode(err, antiBotChallengeFailedErrorCode)
+
 			default:
 				return nil, server.Unexpected(err)
 			}
@@ -119,6 +150,11 @@ func (s *service) StartOrContinueKYCStep4Session( //nolint:gocritic,funlen // .
 			return nil, server.Unexpected(err)
 		}
 	}
+	if session.Result == kycquiz.SuccessResult || session.Result == kycquiz.FailureResult {
+		if err = s.usersProcessor.RecordKYCStepAttempt(ctx, req.AuthenticatedUser.UserID, users.QuizKYCStep, session.Result == kycquiz.SuccessResult); err != nil {
+			log.Error(errors.Wrapf(err, "failed to RecordKYCStepAttempt for userID:%v", req.AuthenticatedUser.UserID))
+		}
+	}
 
 	return server.OK(session), nil
 }
@@ -156,6 +192,33 @@ func (s *service) CheckKYCStep4Status( //nolint:gocritic // .
 	return server.OK(resp), nil
 }
 
+// GetKYCStep4Cooldown godoc
+//
+//	@Schemes
+//	@Description	Returns when the authenticated user can start a new quiz kyc step (4) session, after a failed one.
+//	@Tags			KYC
+//	@Produce		json
+//
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Success		200					{object}	kycquiz.QuizCooldown
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/kyc/quiz/cooldown [GET].
+func (s *service) GetKYCStep4Cooldown( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[GetKYCStep4CooldownArg, kycquiz.QuizCooldown],
+) (*server.Response[kycquiz.QuizCooldown], *server.Response[server.ErrorResponse]) {
+	resp, err := s.quizRepository.GetQuizCooldown(ctx, req.AuthenticatedUser.UserID)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to GetQuizCooldown for userID:%v", req.AuthenticatedUser.UserID))
+	}
+
+	return server.OK(resp), nil
+}
+
 // VerifySocialKYCStep godoc
 //
 //	@Schemes
@@ -175,7 +238,7 @@ func (s *service) CheckKYCStep4Status( //nolint:gocritic // .
 //	@Success		201					{object}	kycsocial.Verification
 //	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
-//	@Failure		403					{object}	server.ErrorResponse	"not allowed due to various reasons"
+//	@Failure		403					{object}	server.ErrorResponse	"not allowed due to various reasons;code:FEATURE_RESTRICTED_IN_COUNTRY if the social kyc is unavailable in the country of the user;code:DEVICE_ATTESTATION_REQUIRED if the device has no recent valid attestation"
 //	@Failure		404					{object}	server.ErrorResponse	"user is not found"
 //	@Failure		409					{object}	server.ErrorResponse	"if any conflicts occur or any prerequisites are not met"
 //	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
@@ -189,6 +252,12 @@ func (s *service) VerifySocialKYCStep( //nolint:gocritic // .
 	if err := validateVerifySocialKYCStep(req); err != nil {
 		return nil, server.UnprocessableEntity(errors.Wrapf(err, "validations failed for %#v", req.Data), invalidPropertiesErrorCode)
 	}
+	if errResp := s.checkFeatureAvailable(ctx, req.AuthenticatedUser.UserID, users.SocialKYCCountryRestrictedFeature); errResp != nil {
+		return nil, errResp
+	}
+	if errResp := s.checkDeviceAttestation(ctx, &req.AuthenticatedUser, users.KYCDeviceAttestationPurpose); errResp != nil {
+		return nil, errResp
+	}
 	result, err := s.socialRepository.VerifyPost(ctx, req.Data)
 	if err != nil {
 		err = errors.Wrapf(err, "failed to verify post for %#v", req.Data)
@@ -248,7 +317,7 @@ func validateVerifySocialKYCStep(req *server.Request[kycsocial.VerificationMetad
 //	@Success		200					{object}	User
 //	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
-//	@Failure		403					{object}	server.ErrorResponse	"not allowed due to various reasons"
+//	@Failure		403					{object}	server.ErrorResponse	"not allowed due to various reasons;code:FEATURE_RESTRICTED_IN_COUNTRY if a kyc step to skip is mandatory in the country of the user"
 //	@Failure		404					{object}	server.ErrorResponse	"user is not found"
 //	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
 //	@Failure		500					{object}	server.ErrorResponse
@@ -266,6 +335,9 @@ func (s *service) TryResetKYCSteps( //nolint:gocritic,funlen,gocognit,revive,cyc
 	for _, kycStep := range req.Data.SkipKYCSteps {
 		switch kycStep { //nolint:exhaustive // .
 		case users.Social1KYCStep, users.Social2KYCStep, users.Social3KYCStep, users.Social4KYCStep, users.Social5KYCStep, users.Social6KYCStep, users.Social7KYCStep:
+			if errResp := s.checkFeatureSkippable(ctx, req.Data.UserID, users.SocialKYCCountryRestrictedFeature); errResp != nil {
+				return nil, errResp
+			}
 			if err := s.socialRepository.SkipVerification(ctx, kycStep, req.Data.UserID); err != nil {
 				if errors.Is(err, kycsocial.ErrNotAvailable) || errors.Is(err, kycsocial.ErrDuplicate) {
 					log.Error(errors.Wrapf(err, "skipVerification failed unexpectedly during tryResetKYCSteps for kycStep:%v,userID:%v",
@@ -277,6 +349,9 @@ func (s *service) TryResetKYCSteps( //nolint:gocritic,funlen,gocognit,revive,cyc
 				}
 			}
 		case users.QuizKYCStep:
+			if errResp := s.checkFeatureSkippable(ctx, req.Data.UserID, users.QuizKYCCountryRestrictedFeature); errResp != nil {
+				return nil, errResp
+			}
 			if err := s.quizRepository.SkipQuizSession(ctx, req.Data.UserID); err != nil {
 				if errors.Is(err, kycquiz.ErrInvalidKYCState) || errors.Is(err, kycquiz.ErrNotAvailable) || errors.Is(err, kycquiz.ErrSessionFinished) || errors.Is(err, kycquiz.ErrSessionFinishedWithError) { //nolint:lll // .
 					log.Error(errors.Wrapf(err, "skipQuizSession failed unexpectedly during tryResetKYCSteps for userID:%v", req.Data.UserID))
@@ -304,3 +379,107 @@ func (s *service) TryResetKYCSteps( //nolint:gocritic,funlen,gocognit,revive,cyc
 
 	return server.OK(&User{User: resp, QuizStatus: quizStatus, Checksum: resp.Checksum()}), nil
 }
+
+// GetKYCConfig godoc
+//
+//	@Schemes
+//	@Description	Returns the KYC features that are unavailable, or mandatory (can't be skipped), for the authenticated user, based on its country.
+//	@Tags			KYC
+//	@Produce		json
+//
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Success		200					{object}	users.KYCConfig
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		404					{object}	server.ErrorResponse	"user is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/kyc/config [GET].
+func (s *service) GetKYCConfig( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[GetKYCConfigArg, users.KYCConfig],
+) (*server.Response[users.KYCConfig], *server.Response[server.ErrorResponse]) {
+	kycConfig, err := s.usersProcessor.GetKYCConfig(ctx, req.AuthenticatedUser.UserID)
+	if err = errors.Wrapf(err, "failed to GetKYCConfig for userID:%v", req.AuthenticatedUser.UserID); err != nil {
+		switch {
+		case errors.Is(err, users.ErrNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(kycConfig), nil
+}
+
+// checkFeatureAvailable rejects the features that are unavailable in the country of the user.
+// The face KYC is run by the provider, so its restriction is only returned by GetKYCConfig, for the clients not to start it.
+func (s *service) checkFeatureAvailable(
+	ctx context.Context, userID users.UserID, feature users.CountryRestrictedFeature,
+) *server.Response[server.ErrorResponse] {
+	return countryRestrictionErrorResponse(s.usersProcessor.CheckFeatureAvailable(ctx, userID, feature), userID, feature)
+}
+
+// checkFeatureSkippable rejects skipping the features that are mandatory in the country of the user.
+func (s *service) checkFeatureSkippable(
+	ctx context.Context, userID users.UserID, feature users.CountryRestrictedFeature,
+) *server.Response[server.ErrorResponse] {
+	return countryRestrictionErrorResponse(s.usersProcessor.CheckFeatureSkippable(ctx, userID, feature), userID, feature)
+}
+
+func countryRestrictionErrorResponse(err error, userID users.UserID, feature users.CountryRestrictedFeature) *server.Response[server.ErrorResponse] {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, users.ErrFeatureRestrictedInCountry):
+		return server.ForbiddenWithCode(err, featureRestrictedInCountryErrorCode, map[string]any{"feature": feature})
+	case errors.Is(err, users.ErrNotFound):
+		return server.NotFound(errors.Wrapf(err, "failed to check the country restrictions of %v for userID:%v", feature, userID), userNotFoundErrorCode)
+	default:
+		return server.Unexpected(errors.Wrapf(err, "failed to check the country restrictions of %v for userID:%v", feature, userID))
+	}
+}
+
+// PurgeKYCData godoc
+//
+//	@Schemes
+//	@Description	Deletes the user's applicant data at the KYC provider and the local KYC artifacts, as part of a GDPR erasure, and returns the proof of deletion.
+//	@Description	It's done automatically when the account is deleted, so it's needed only for erasing the KYC data while keeping the account.
+//	@Tags			KYC
+//	@Accept			json
+//	@Produce		json
+//
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Success		200					{object}	users.KYCDataPurge
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"user is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/kyc/purgeKYCData/users/{userId} [POST].
+func (s *service) PurgeKYCData( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[PurgeKYCDataRequestBody, users.KYCDataPurge],
+) (*server.Response[users.KYCDataPurge], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole && req.Data.UserID != req.AuthenticatedUser.UserID {
+		return nil, server.Forbidden(errors.New("operation not allowed"))
+	}
+	ctx = users.ContextWithXAccountMetadata(ctx, req.Data.XAccountMetadata) //nolint:revive // .
+	ctx = users.ContextWithAuthorization(ctx, req.Data.Authorization)       //nolint:revive // .
+	purge, err := s.usersProcessor.PurgeKYCData(ctx, req.Data.UserID, req.AuthenticatedUser.UserID)
+	if err = errors.Wrapf(err, "failed to PurgeKYCData for userID:%v", req.Data.UserID); err != nil {
+		switch {
+		case errors.Is(err, users.ErrNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(purge), nil
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupMaintenanceModeRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		PUT("maintenance-mode", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.SetMaintenanceMode)))
+}
+
+// SetMaintenanceMode godoc
+//
+//	@Schemes
+//	@Description	Enables or disables the maintenance mode of both the read and the write APIs. Only for admins.
+//	@Description	While it's enabled, all the calls fail with 503 `MAINTENANCE_MODE`, except the ones of the allowlisted users and the ones managing the maintenance mode.
+//	@Description	It's applied by all the replicas in at most `maintenanceMode.cacheTtl`. It can't be disabled if it's enabled in the config.
+//	@Tags			Maintenance
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string							true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string							false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			request				body		SetMaintenanceModeRequestBody	true	"Request params"
+//	@Success		200					{object}	users.MaintenanceMode
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/maintenance-mode [PUT].
+func (s *service) SetMaintenanceMode( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[SetMaintenanceModeRequestBody, users.MaintenanceMode],
+) (*server.Response[users.MaintenanceMode], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	mode := &users.MaintenanceMode{
+		ETA:       req.Data.ETA,
+		UpdatedBy: req.AuthenticatedUser.UserID,
+		Messages:  req.Data.Messages,
+		Allowlist: req.Data.Allowlist,
+		Enabled:   *req.Data.Enabled,
+	}
+	if err := s.usersProcessor.SetMaintenanceMode(ctx, mode); err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to SetMaintenanceMode to %#v", mode))
+	}
+
+	return server.OK(mode), nil
+}
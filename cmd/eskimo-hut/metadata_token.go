@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+// checkMetadataToken validates the X-Account-Metadata header ahead of the handlers, so that the clients get specific error codes for it,
+// and hints them, via the X-Account-Metadata-Refresh-Required response header, when the token is old enough for its claims to be stale.
+func (s *service) checkMetadataToken(ginCtx *gin.Context) {
+	metadataToken := ginCtx.GetHeader(xAccountMetadataHeader)
+	if metadataToken == "" || ginCtx.FullPath() == refreshMetadataPath {
+		return
+	}
+	md, err := s.authEmailLinkClient.ParseMetadataToken(metadataToken)
+	if err != nil {
+		errResp := server.Unauthorized(err)
+		errResp.Data.Code = invalidMetadataTokenErrorCode
+		if errors.Is(err, emaillink.ErrExpiredMetadataToken) {
+			errResp.Data.Code = metadataTokenExpiredErrorCode
+		}
+		log.Error(errors.Wrapf(err, "endpoint %v failed", ginCtx.FullPath()))
+		ginCtx.AbortWithStatusJSON(errResp.Code, errResp.Data)
+
+		return
+	}
+	if md.RefreshRequired {
+		ginCtx.Header(accountMetadataRefreshRequiredHeader, "true")
+	}
+}
+
+// RefreshMetadata godoc
+//
+//	@Schemes
+//	@Description	Re-issues the metadata token from the current account metadata. Expired tokens are accepted as well.
+//	@Description	Don't send the X-Account-Metadata header for this endpoint; the token to refresh goes in the body.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"Insert your access token"	default(Bearer <Add access token here>)
+//	@Param			request			body		RefreshMetadataRequestBody	true	"Request params"
+//	@Success		200				{object}	Metadata
+//	@Failure		401				{object}	server.ErrorResponse	"if not authorized or if the metadata token is invalid"
+//	@Failure		403				{object}	server.ErrorResponse	"if the metadata token belongs to another user"
+//	@Failure		404				{object}	server.ErrorResponse	"if user do not have a metadata anymore"
+//	@Failure		422				{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500				{object}	server.ErrorResponse
+//	@Failure		504				{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/refreshMetadata [POST].
+func (s *service) RefreshMetadata( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[RefreshMetadataRequestBody, Metadata],
+) (*server.Response[Metadata], *server.Response[server.ErrorResponse]) {
+	md, err := s.authEmailLinkClient.RefreshMetadata(ctx, req.AuthenticatedUser.UserID, req.AuthenticatedUser.Email, req.Data.Metadata)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to refresh metadata for userID:%v", req.AuthenticatedUser.UserID)
+		switch {
+		case errors.Is(err, emaillink.ErrInvalidMetadataToken):
+			errResp := server.Unauthorized(err)
+			errResp.Data.Code = invalidMetadataTokenErrorCode
+
+			return nil, errResp
+		case errors.Is(err, emaillink.ErrMetadataTokenMismatch):
+			return nil, server.ForbiddenWithCode(err, metadataTokenMismatchErrorCode)
+		case errors.Is(err, emaillink.ErrUserNotFound):
+			return nil, server.NotFound(err, metadataNotFoundErrorCode)
+		case errors.Is(err, emaillink.ErrUserDataMismatch):
+			return nil, server.BadRequest(err, dataMismatchErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+	if md.Metadata == "" {
+		return nil, server.NotFound(errors.Errorf("userID:%v has no metadata anymore", md.UserID), metadataNotFoundErrorCode)
+	}
+
+	return server.OK(&Metadata{Metadata: md.Metadata, UserID: md.UserID}), nil
+}
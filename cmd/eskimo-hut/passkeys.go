@@ -0,0 +1,262 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/auth/webauthn"
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupPasskeysRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("users/:userId/passkeys/registration-options", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.StartPasskeyRegistration))).
+		POST("users/:userId/passkeys", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.RegisterPasskey))).
+		GET("users/:userId/passkeys", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.GetPasskeys))).
+		DELETE("users/:userId/passkeys/:credentialId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.DeletePasskey))).
+		POST("auth/startSignInWithPasskey", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.StartSignInWithPasskey))).
+		POST("auth/signInWithPasskey", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.SignInWithPasskey)))
+}
+
+// StartPasskeyRegistration godoc
+//
+//	@Schemes
+//	@Description	Returns the options for `navigator.credentials.create()`, to register a passkey for the device of the user.
+//	@Description	The binary fields are base64url encoded. The challenge can be used only once, until the options timeout.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string								true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string								false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string								true	"ID of the user"
+//	@Param			request				body		StartPasskeyRegistrationRequestBody	true	"Request params"
+//	@Success		200					{object}	webauthn.CreationOptions
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed or passkeys are disabled;code:GUEST_ACCOUNT_NOT_ALLOWED if the account is a guest one"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/passkeys/registration-options [POST].
+func (s *service) StartPasskeyRegistration( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[StartPasskeyRegistrationRequestBody, webauthn.CreationOptions],
+) (*server.Response[webauthn.CreationOptions], *server.Response[server.ErrorResponse]) {
+	if errResp := forbidGuests(&req.AuthenticatedUser); errResp != nil {
+		return nil, errResp
+	}
+	if s.webAuthnClient == nil {
+		return nil, server.Forbidden(errNoPasskeys)
+	}
+	userName := req.AuthenticatedUser.Email
+	if userName == "" {
+		userName = req.Data.UserID
+	}
+	options, err := s.webAuthnClient.StartRegistration(ctx, req.Data.UserID, req.Data.DeviceUniqueID, userName)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to StartPasskeyRegistration for %#v", req.Data))
+	}
+
+	return server.OK(options), nil
+}
+
+// RegisterPasskey godoc
+//
+//	@Schemes
+//	@Description	Verifies and saves the passkey created with the options of `registration-options`. A passkey registered again on the same device replaces the previous one.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string						true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string						false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string						true	"ID of the user"
+//	@Param			request				body		RegisterPasskeyRequestBody	true	"Request params"
+//	@Success		201					{object}	webauthn.Passkey
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail or the credential is invalid"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed or passkeys are disabled"
+//	@Failure		404					{object}	server.ErrorResponse	"if the user or the challenge is not found"
+//	@Failure		409					{object}	server.ErrorResponse	"if the credential is already registered"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/passkeys [POST].
+func (s *service) RegisterPasskey( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[RegisterPasskeyRequestBody, webauthn.Passkey],
+) (*server.Response[webauthn.Passkey], *server.Response[server.ErrorResponse]) {
+	if s.webAuthnClient == nil {
+		return nil, server.Forbidden(errNoPasskeys)
+	}
+	passkey, err := s.webAuthnClient.FinishRegistration(ctx, req.Data.UserID, req.Data.DeviceUniqueID, req.Data.Credential)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to RegisterPasskey for userID:%v, deviceUniqueID:%v", req.Data.UserID, req.Data.DeviceUniqueID)
+		switch {
+		case errors.Is(err, webauthn.ErrChallengeNotFound):
+			return nil, server.NotFound(err, passkeyChallengeNotFoundErrorCode)
+		case errors.Is(err, webauthn.ErrInvalidCredential):
+			return nil, server.BadRequest(err, invalidPasskeyErrorCode)
+		case errors.Is(err, webauthn.ErrPasskeyDuplicate):
+			return nil, server.Conflict(err, passkeyAlreadyRegisteredErrorCode)
+		case errors.Is(err, webauthn.ErrUserNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.Created(passkey), nil
+}
+
+// GetPasskeys godoc
+//
+//	@Schemes
+//	@Description	Lists the passkeys of the user, one per device.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Success		200					{array}		webauthn.Passkey
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed or passkeys are disabled"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/passkeys [GET].
+func (s *service) GetPasskeys( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetPasskeysArg, []*webauthn.Passkey],
+) (*server.Response[[]*webauthn.Passkey], *server.Response[server.ErrorResponse]) {
+	if s.webAuthnClient == nil {
+		return nil, server.Forbidden(errNoPasskeys)
+	}
+	passkeys, err := s.webAuthnClient.GetPasskeys(ctx, req.Data.UserID)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to GetPasskeys for %#v", req.Data))
+	}
+
+	return server.OK(&passkeys), nil
+}
+
+// DeletePasskey godoc
+//
+//	@Schemes
+//	@Description	Deletes a passkey of the user. It can't be used to sign in anymore.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string	true	"ID of the user"
+//	@Param			credentialId		path	string	true	"ID of the passkey credential"
+//	@Success		200					"OK"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed or passkeys are disabled"
+//	@Failure		404					{object}	server.ErrorResponse	"if the passkey is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/passkeys/{credentialId} [DELETE].
+func (s *service) DeletePasskey( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[DeletePasskeyArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if s.webAuthnClient == nil {
+		return nil, server.Forbidden(errNoPasskeys)
+	}
+	if err := s.webAuthnClient.DeletePasskey(ctx, req.Data.UserID, req.Data.CredentialID); err != nil {
+		err = errors.Wrapf(err, "failed to DeletePasskey for %#v", req.Data)
+		if errors.Is(err, webauthn.ErrPasskeyNotFound) {
+			return nil, server.NotFound(err, passkeyNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK[any](), nil
+}
+
+// StartSignInWithPasskey godoc
+//
+//	@Schemes
+//	@Description	Returns the options for `navigator.credentials.get()`, to sign in with a passkey, without waiting for an email.
+//	@Description	The passkeys are discoverable, so the user is identified by the passkey it picks.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		StartSignInWithPasskeyRequestBody	true	"Request params"
+//	@Success		200		{object}	webauthn.RequestOptions
+//	@Failure		403		{object}	server.ErrorResponse	"if passkeys are disabled"
+//	@Failure		422		{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500		{object}	server.ErrorResponse
+//	@Failure		504		{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/startSignInWithPasskey [POST].
+func (s *service) StartSignInWithPasskey( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[StartSignInWithPasskeyRequestBody, webauthn.RequestOptions],
+) (*server.Response[webauthn.RequestOptions], *server.Response[server.ErrorResponse]) {
+	if s.webAuthnClient == nil {
+		return nil, server.Forbidden(errNoPasskeys)
+	}
+	options, err := s.webAuthnClient.StartSignIn(ctx, req.Data.DeviceUniqueID)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to StartSignInWithPasskey for %#v", req.Data))
+	}
+
+	return server.OK(options), nil
+}
+
+// SignInWithPasskey godoc
+//
+//	@Schemes
+//	@Description	Verifies the passkey assertion and issues the same tokens as the email link sign in, refreshable via `/auth/refreshTokens`.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SignInWithPasskeyRequestBody	true	"Request params"
+//	@Success		200		{object}	RefreshedToken
+//	@Failure		400		{object}	server.ErrorResponse	"if the credential is invalid or the user is blocked"
+//	@Failure		403		{object}	server.ErrorResponse	"if passkeys are disabled or the user has no email"
+//	@Failure		404		{object}	server.ErrorResponse	"if the passkey or the challenge is not found"
+//	@Failure		422		{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500		{object}	server.ErrorResponse
+//	@Failure		504		{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/signInWithPasskey [POST].
+func (s *service) SignInWithPasskey( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[SignInWithPasskeyRequestBody, RefreshedToken],
+) (*server.Response[RefreshedToken], *server.Response[server.ErrorResponse]) {
+	if s.webAuthnClient == nil {
+		return nil, server.Forbidden(errNoPasskeys)
+	}
+	tokens, err := s.webAuthnClient.SignIn(ctx, req.Data.DeviceUniqueID, req.Data.Credential)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to SignInWithPasskey for deviceUniqueID:%v", req.Data.DeviceUniqueID)
+		switch {
+		case errors.Is(err, webauthn.ErrChallengeNotFound):
+			return nil, server.NotFound(err, passkeyChallengeNotFoundErrorCode)
+		case errors.Is(err, webauthn.ErrInvalidCredential):
+			return nil, server.BadRequest(err, invalidPasskeyErrorCode)
+		case errors.Is(err, emaillink.ErrUserBlocked):
+			return nil, server.BadRequest(err, userBlockedErrorCode)
+		case errors.Is(err, emaillink.ErrPrimaryEmailRequired):
+			return nil, server.Forbidden(err)
+		case errors.Is(err, webauthn.ErrPasskeyNotFound):
+			return nil, server.NotFound(err, passkeyNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(&RefreshedToken{Tokens: tokens}), nil
+}
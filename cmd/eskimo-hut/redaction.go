@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ice-blockchain/eskimo/users"
+)
+
+func (a GetValidUserForPhoneNumberMigrationArg) Format(state fmt.State, verb rune) {
+	users.FormatRedacted(state, verb, a)
+}
+
+func (b CreateUserRequestBody) Format(state fmt.State, verb rune) { //nolint:gocritic // It has to be a value receiver, for the values to use it.
+	users.FormatRedacted(state, verb, b)
+}
+
+func (b ModifyUserRequestBody) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	users.FormatRedacted(state, verb, b)
+}
+
+func (b RectifyUserRequestBody) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	users.FormatRedacted(state, verb, b)
+}
+
+func (a SendSignInLinkToEmailRequestArg) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	users.FormatRedacted(state, verb, a)
+}
+
+func (b AddEmailRequestBody) Format(state fmt.State, verb rune) {
+	users.FormatRedacted(state, verb, b)
+}
+
+func (b ConfirmEmailRequestBody) Format(state fmt.State, verb rune) {
+	users.FormatRedacted(state, verb, b)
+}
+
+func (b SetPrimaryEmailRequestBody) Format(state fmt.State, verb rune) {
+	users.FormatRedacted(state, verb, b)
+}
+
+func (a RemoveEmailArg) Format(state fmt.State, verb rune) {
+	users.FormatRedacted(state, verb, a)
+}
+
+// Format is needed because User and ModifyUserResponse embed users.User, otherwise only the embedded users.User would be formatted.
+func (u User) Format(state fmt.State, verb rune) {
+	users.FormatRedacted(state, verb, u)
+}
+
+func (r ModifyUserResponse) Format(state fmt.State, verb rune) {
+	users.FormatRedacted(state, verb, r)
+}
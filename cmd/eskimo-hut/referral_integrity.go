@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupReferralIntegrityRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("referral-integrity-checks", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.CheckReferralIntegrity)))
+}
+
+// CheckReferralIntegrity godoc
+//
+//	@Schemes
+//	@Description	Finds the users with inconsistent referrals (self referrals, cycles, referrals of missing users or of usernames) and, optionally, re-parents them. Only for admins.
+//	@Description	The same check runs periodically, repairing the anomalies according to the configured policy. Every check is audited, except the dry runs.
+//	@Tags			Referrals
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string								true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string								false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			request				body		CheckReferralIntegrityRequestBody	true	"Request params"
+//	@Success		200					{object}	users.ReferralIntegrityReport
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/referral-integrity-checks [POST].
+func (s *service) CheckReferralIntegrity( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[CheckReferralIntegrityRequestBody, users.ReferralIntegrityReport],
+) (*server.Response[users.ReferralIntegrityReport], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if req.Data.DryRun {
+		ctx, _ = users.DryRunContext(ctx) //nolint:revive // .
+	}
+	report, err := s.usersProcessor.CheckReferralIntegrity(ctx, req.Data.Repair)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to CheckReferralIntegrity for %#v", req.Data))
+	}
+
+	return server.OK(report), nil
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupReferralInvitationsRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("users/:userId/referral-invitations", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.SendReferralInvitations))).
+		POST("referral-invitations/unsubscribe", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.UnsubscribeFromReferralInvitations)))
+}
+
+// SendReferralInvitations godoc
+//
+//	@Schemes
+//	@Description	Invites the contacts of the user, by email or sms, to sign up with its referral code, in its language.
+//	@Description	Only the contacts that consented are invited, at most once per user, and within the daily quota of the user.
+//	@Description	Once an invitee signs up with the invited email or phone number, the invitation is `joined`.
+//	@Tags			Referrals
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string								true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string								false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string								true	"ID of the user"
+//	@Param			request				body		SendReferralInvitationsRequestBody	true	"Request params"
+//	@Success		200					{object}	users.ReferralInvitationsResult
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"not allowed, or referral invitations are disabled;code:GUEST_ACCOUNT_NOT_ALLOWED if the account is a guest one"
+//	@Failure		404					{object}	server.ErrorResponse	"user is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/referral-invitations [POST].
+func (s *service) SendReferralInvitations( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[SendReferralInvitationsRequestBody, users.ReferralInvitationsResult],
+) (*server.Response[users.ReferralInvitationsResult], *server.Response[server.ErrorResponse]) {
+	if errResp := forbidGuests(&req.AuthenticatedUser); errResp != nil {
+		return nil, errResp
+	}
+	if err := validateReferralInvitationContacts(req.Data.Contacts); err != nil {
+		return nil, err
+	}
+	res, err := s.usersProcessor.SendReferralInvitations(ctx, req.Data.UserID, req.Data.Contacts)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to SendReferralInvitations for userID:%v", req.Data.UserID)
+		switch {
+		case errors.Is(err, users.ErrReferralInvitationsDisabled):
+			return nil, server.Forbidden(err)
+		case errors.Is(err, users.ErrNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(res), nil
+}
+
+func validateReferralInvitationContacts(contacts []*users.ReferralInvitationContact) *server.Response[server.ErrorResponse] {
+	if len(contacts) == 0 || len(contacts) > maxReferralInvitationContacts {
+		return server.UnprocessableEntity(errors.Errorf("between 1 and %v contacts are required", maxReferralInvitationContacts),
+			invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "contacts"))
+	}
+	for _, contact := range contacts {
+		if contact == nil || (contact.Email == "") == (contact.PhoneNumber == "") {
+			return server.UnprocessableEntity(errors.New("each contact must have either an email or a phoneNumber"),
+				invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.RequiredReason, "email", "phoneNumber"))
+		}
+		contact.Email, contact.PhoneNumber = strings.TrimSpace(contact.Email), strings.TrimSpace(contact.PhoneNumber)
+		if _, err := mail.ParseAddress(contact.Email); contact.Email != "" && err != nil {
+			return server.BadRequest(errors.Wrapf(err, "invalid email %v", users.RedactedEmail(contact.Email)), invalidEmail,
+				errorcatalog.FieldsData(errorcatalog.InvalidReason, "email"))
+		}
+		if contact.PhoneNumber != "" && contact.PhoneNumberHash == "" {
+			return server.UnprocessableEntity(errors.New("phoneNumber must be provided together with phoneNumberHash"),
+				invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.RequiredReason, "phoneNumberHash"))
+		}
+	}
+
+	return nil
+}
+
+// UnsubscribeFromReferralInvitations godoc
+//
+//	@Schemes
+//	@Description	Stops all the referral invitations to the email or phone number that got the invitation with the provided token.
+//	@Description	It's meant for the unsubscribe link of the invitations, so it doesn't require authorization.
+//	@Tags			Referrals
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	UnsubscribeFromReferralInvitationsRequestBody	true	"Request params"
+//	@Success		200		"ok"
+//	@Failure		404		{object}	server.ErrorResponse	"if there is no invitation with the token"
+//	@Failure		422		{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500		{object}	server.ErrorResponse
+//	@Failure		504		{object}	server.ErrorResponse	"if request times out"
+//	@Router			/referral-invitations/unsubscribe [POST].
+func (s *service) UnsubscribeFromReferralInvitations( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[UnsubscribeFromReferralInvitationsRequestBody, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := s.usersProcessor.UnsubscribeFromReferralInvitations(ctx, strings.TrimSpace(req.Data.Token)); err != nil {
+		err = errors.Wrap(err, "failed to UnsubscribeFromReferralInvitations")
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, server.NotFound(err, referralInvitationNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK[any](), nil
+}
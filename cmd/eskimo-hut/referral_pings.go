@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupReferralPingsRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("users/:userId/referral-pings", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.PingReferrals)))
+}
+
+// PingReferrals godoc
+//
+//	@Schemes
+//	@Description	Pings the referrals (T1 and the referrer), either the provided ones or all the pingable ones, with the `selectAllToken` of `GET /users/{userId}/referrals/pingable`.
+//	@Description	At most the configured max batch size of them are pinged at once. The ones that aren't pingable anymore (mining, or already pinged) are skipped.
+//	@Description	The pinged ones can't be pinged again until `cooldownEndedAt`.
+//	@Tags			Referrals
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string						true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string						false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string						true	"ID of the user"
+//	@Param			request				body		PingReferralsRequestBody	true	"Request params"
+//	@Success		200					{object}	users.ReferralPingResult
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails, or neither a valid selectAllToken nor the userIds, within the max batch size, are provided"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/referral-pings [POST].
+func (s *service) PingReferrals( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[PingReferralsRequestBody, users.ReferralPingResult],
+) (*server.Response[users.ReferralPingResult], *server.Response[server.ErrorResponse]) {
+	ping := &users.ReferralPing{SelectAllToken: req.Data.SelectAllToken, UserIDs: req.Data.UserIDs}
+	res, err := s.usersProcessor.PingReferrals(ctx, req.Data.UserID, ping)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to PingReferrals for userID:%v", req.Data.UserID)
+		if errors.Is(err, users.ErrInvalidReferralPing) {
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "selectAllToken", "userIds"))
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK(res), nil
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/auth/telegram"
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupTelegramRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("auth/signInWithTelegram", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.SignInWithTelegram)))
+}
+
+// SignInWithTelegram godoc
+//
+//	@Schemes
+//	@Description	Verifies the signature of the init data of the Telegram Mini App and issues the same tokens as the email link sign in,
+//	@Description	refreshable via `/auth/refreshTokens`. The user is created on the first sign in, referred by the user (ID or username) in its `start_param`.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SignInWithTelegramRequestBody	true	"Request params"
+//	@Success		200		{object}	RefreshedToken
+//	@Failure		400		{object}	server.ErrorResponse	"if the init data is invalid or expired, or the user is blocked"
+//	@Failure		403		{object}	server.ErrorResponse	"if the Telegram sign in is disabled"
+//	@Failure		422		{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500		{object}	server.ErrorResponse
+//	@Failure		504		{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/signInWithTelegram [POST].
+func (s *service) SignInWithTelegram( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[SignInWithTelegramRequestBody, RefreshedToken],
+) (*server.Response[RefreshedToken], *server.Response[server.ErrorResponse]) {
+	if s.telegramClient == nil {
+		return nil, server.Forbidden(errNoTelegram)
+	}
+	tokens, err := s.telegramClient.SignIn(ctx, req.Data.InitData, req.Data.DeviceUniqueID, req.ClientIP)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to SignInWithTelegram for deviceUniqueID:%v", req.Data.DeviceUniqueID)
+		switch {
+		case errors.Is(err, telegram.ErrInvalidInitData):
+			return nil, server.BadRequest(err, invalidTelegramInitDataErrorCode)
+		case errors.Is(err, telegram.ErrExpiredInitData):
+			return nil, server.BadRequest(err, expiredTelegramInitDataErrorCode)
+		case errors.Is(err, emaillink.ErrUserBlocked):
+			return nil, server.BadRequest(err, userBlockedErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(&RefreshedToken{Tokens: tokens}), nil
+}
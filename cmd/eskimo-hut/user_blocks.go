@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupUserBlocksRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		PUT("users/:userId/blocks/:blockedUserId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.BlockUser))).
+		DELETE("users/:userId/blocks/:blockedUserId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.UnblockUser)))
+}
+
+// BlockUser godoc
+//
+//	@Schemes
+//	@Description	Blocks another user: it's excluded from the user's searches and contacts. Only for the user itself.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string	true	"ID of the user"
+//	@Param			blockedUserId		path	string	true	"ID of the user to block"
+//	@Success		200					"OK - blocked"
+//	@Success		204					"No Content - already blocked"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if the user to block is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails or if the user tries to block itself"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/blocks/{blockedUserId} [PUT].
+func (s *service) BlockUser( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[BlockUserArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if req.Data.BlockedUserID == req.Data.UserID {
+		return nil, server.UnprocessableEntity(errors.New("users can't block themselves"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.InvalidReason, "blockedUserId"))
+	}
+	if err := s.usersProcessor.BlockUser(ctx, req.Data.UserID, req.Data.BlockedUserID); err != nil {
+		err = errors.Wrapf(err, "failed to BlockUser for %#v", req.Data)
+		switch {
+		case errors.Is(err, users.ErrDuplicate):
+			return server.NoContent(), nil
+		case errors.Is(err, users.ErrRelationNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
+
+// UnblockUser godoc
+//
+//	@Schemes
+//	@Description	Unblocks an user blocked by the user. Only for the user itself.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string	true	"ID of the user"
+//	@Param			blockedUserId		path	string	true	"ID of the blocked user"
+//	@Success		200					"OK - unblocked"
+//	@Success		204					"No Content - it wasn't blocked"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/blocks/{blockedUserId} [DELETE].
+func (s *service) UnblockUser( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[UnblockUserArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if err := s.usersProcessor.UnblockUser(ctx, req.Data.UserID, req.Data.BlockedUserID); err != nil {
+		if errors.Is(err, users.ErrNotFound) {
+			return server.NoContent(), nil
+		}
+
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to UnblockUser for %#v", req.Data))
+	}
+
+	return server.OK[any](), nil
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupUserDeletionBatchesRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("user-deletion-batches", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.CreateUserDeletionBatch)))
+}
+
+// CreateUserDeletionBatch godoc
+//
+//	@Schemes
+//	@Description	Deletes or anonymizes, in the background, all the provided users, for compliance requests (for example, the accounts of minors). Only for admins.
+//	@Description	The users are deleted in chunks, by all the replicas, so the batch is resumed if they are restarted. Its progress and final report are available via `GET /v1r/user-deletion-batches/{batchId}`.
+//	@Description	With `dryRun`, the batch isn't stored; the users are only checked and the preview of what would be deleted is returned, with 200.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string							true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string							false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			request				body		CreateUserDeletionBatchRequestBody	true	"Request params"
+//	@Success		200					{object}	users.UserDeletionBatch	"if dryRun"
+//	@Success		201					{object}	users.UserDeletionBatch
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/user-deletion-batches [POST].
+func (s *service) CreateUserDeletionBatch( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[CreateUserDeletionBatchRequestBody, users.UserDeletionBatch],
+) (*server.Response[users.UserDeletionBatch], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if err := req.Data.validate(); err != nil {
+		return nil, err
+	}
+	batch := &users.UserDeletionBatch{
+		RequestedBy: req.AuthenticatedUser.UserID,
+		Reason:      strings.TrimSpace(req.Data.Reason),
+		Mode:        req.Data.Mode,
+		UserIDs:     req.Data.UserIDs,
+	}
+	if req.Data.DryRun {
+		ctx, _ = users.DryRunContext(ctx) //nolint:revive // .
+	}
+	if err := s.usersProcessor.CreateUserDeletionBatch(ctx, batch); err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to CreateUserDeletionBatch for %#v", req.Data))
+	}
+	if batch.DryRun != nil {
+		return server.OK(batch), nil
+	}
+
+	return server.Created(batch), nil
+}
+
+func (arg *CreateUserDeletionBatchRequestBody) validate() *server.Response[server.ErrorResponse] {
+	if arg.Mode == "" {
+		arg.Mode = users.DeleteUserDeletionBatchMode
+	}
+	switch {
+	case arg.Mode != users.DeleteUserDeletionBatchMode && arg.Mode != users.AnonymizeUserDeletionBatchMode:
+		return server.UnprocessableEntity(errors.Errorf("invalid mode `%v`", arg.Mode), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.InvalidReason, "mode"))
+	case strings.TrimSpace(arg.Reason) == "":
+		return server.UnprocessableEntity(errors.New("reason is required"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.RequiredReason, "reason"))
+	case len(arg.UserIDs) == 0 || len(arg.UserIDs) > users.MaxUserDeletionBatchSize:
+		return server.UnprocessableEntity(errors.Errorf("between 1 and %v userIds are required", users.MaxUserDeletionBatchSize),
+			invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "userIds"))
+	}
+	for _, userID := range arg.UserIDs {
+		if strings.TrimSpace(userID) == "" {
+			return server.UnprocessableEntity(errors.New("empty userId"), invalidPropertiesErrorCode,
+				errorcatalog.FieldsData(errorcatalog.InvalidReason, "userIds"))
+		}
+	}
+
+	return nil
+}
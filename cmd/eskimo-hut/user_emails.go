@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/terror"
+)
+
+func (s *service) setupUserEmailsRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		GET("users/:userId/emails", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.GetEmails))).
+		POST("users/:userId/emails", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.AddEmail))).
+		POST("users/:userId/emails/confirmation", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.ConfirmEmail))).
+		PUT("users/:userId/emails/primary", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.SetPrimaryEmail))).
+		DELETE("users/:userId/emails/:email", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Auth, s.RemoveEmail)))
+}
+
+// GetEmails godoc
+//
+//	@Schemes
+//	@Description	Lists the emails of the user: the primary one, used for notifications, first, then the secondary ones, confirmed or not.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Success		200					{array}		emaillink.UserEmail
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/emails [GET].
+func (s *service) GetEmails( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetEmailsArg, []*emaillink.UserEmail],
+) (*server.Response[[]*emaillink.UserEmail], *server.Response[server.ErrorResponse]) {
+	emails, err := s.authEmailLinkClient.GetEmails(ctx, req.Data.UserID)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to GetEmails for %#v", req.Data))
+	}
+
+	return server.OK(&emails), nil
+}
+
+// AddEmail godoc
+//
+//	@Schemes
+//	@Description	Adds a secondary email to the user and sends a confirmation code to it. Once confirmed, it can be used to sign in too.
+//	@Description	Adding a pending email again sends a new confirmation code.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string				true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string				false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string				true	"ID of the user"
+//	@Param			request				body	AddEmailRequestBody	true	"Request params"
+//	@Success		200					"OK"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail or the user has too many emails"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed, or the user has no primary email yet;code:GUEST_ACCOUNT_NOT_ALLOWED if the account is a guest one"
+//	@Failure		404					{object}	server.ErrorResponse	"if user not found"
+//	@Failure		409					{object}	server.ErrorResponse	"if email conflicts with another user's"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/emails [POST].
+func (s *service) AddEmail( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[AddEmailRequestBody, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	if errResp := forbidGuests(&req.AuthenticatedUser); errResp != nil {
+		return nil, errResp
+	}
+	email := strings.TrimSpace(strings.ToLower(req.Data.Email))
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, server.BadRequest(err, invalidEmail, errorcatalog.FieldsData(errorcatalog.InvalidReason, "email"))
+	}
+	if err := s.authEmailLinkClient.AddEmail(ctx, req.Data.UserID, email, req.Data.Language); err != nil {
+		err = errors.Wrapf(err, "failed to AddEmail for %#v", req.Data)
+		switch {
+		case errors.Is(err, emaillink.ErrUserNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		case errors.Is(err, emaillink.ErrPrimaryEmailRequired):
+			return nil, server.Forbidden(err)
+		case errors.Is(err, emaillink.ErrTooManyEmails):
+			return nil, server.BadRequest(err, tooManyEmailsErrorCode)
+		case errors.Is(err, emaillink.ErrUserDuplicate):
+			if tErr := terror.As(err); tErr != nil {
+				return nil, server.Conflict(err, duplicateUserErrorCode, errorcatalog.ConflictData(tErr.Data))
+			}
+
+			fallthrough
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
+
+// ConfirmEmail godoc
+//
+//	@Schemes
+//	@Description	Confirms a secondary email of the user with the code sent to it.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string					true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string					false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string					true	"ID of the user"
+//	@Param			request				body	ConfirmEmailRequestBody	true	"Request params"
+//	@Success		200					"OK"
+//	@Failure		400					{object}	server.ErrorResponse	"if the confirmation code is wrong, expired or had too many wrong attempts"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if the email is not pending confirmation"
+//	@Failure		409					{object}	server.ErrorResponse	"if email conflicts with another user's"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/emails/confirmation [POST].
+func (s *service) ConfirmEmail( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[ConfirmEmailRequestBody, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	email := strings.TrimSpace(strings.ToLower(req.Data.Email))
+	if err := s.authEmailLinkClient.ConfirmEmail(ctx, req.Data.UserID, email, req.Data.ConfirmationCode); err != nil {
+		err = errors.Wrapf(err, "failed to ConfirmEmail for userID:%v, email:%v", req.Data.UserID, users.RedactedEmail(email))
+		switch {
+		case errors.Is(err, emaillink.ErrNoConfirmationRequired):
+			return nil, server.NotFound(err, confirmationCodeNotFoundErrorCode)
+		case errors.Is(err, emaillink.ErrConfirmationCodeAttemptsExceeded):
+			return nil, server.BadRequest(err, confirmationCodeAttemptsExceededErrorCode)
+		case errors.Is(err, emaillink.ErrConfirmationCodeWrong):
+			return nil, server.BadRequest(err, confirmationCodeWrongErrorCode)
+		case errors.Is(err, emaillink.ErrConfirmationCodeExpired):
+			return nil, server.BadRequest(err, confirmationCodeExpiredErrorCode)
+		case errors.Is(err, emaillink.ErrUserDuplicate):
+			if tErr := terror.As(err); tErr != nil {
+				return nil, server.Conflict(err, duplicateUserErrorCode, errorcatalog.ConflictData(tErr.Data))
+			}
+
+			fallthrough
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
+
+// SetPrimaryEmail godoc
+//
+//	@Schemes
+//	@Description	Makes a confirmed secondary email the primary one, used for notifications. The previous primary email becomes a secondary one.
+//	@Description	The sessions of the user continue, but its tokens have the new email only after they're refreshed.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string						true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string						false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string						true	"ID of the user"
+//	@Param			request				body	SetPrimaryEmailRequestBody	true	"Request params"
+//	@Success		200					"OK"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if the email is not a confirmed secondary email of the user"
+//	@Failure		409					{object}	server.ErrorResponse	"if email conflicts with another user's"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/emails/primary [PUT].
+func (s *service) SetPrimaryEmail( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[SetPrimaryEmailRequestBody, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	email := strings.TrimSpace(strings.ToLower(req.Data.Email))
+	if err := s.authEmailLinkClient.SetPrimaryEmail(ctx, req.Data.UserID, email); err != nil {
+		err = errors.Wrapf(err, "failed to SetPrimaryEmail for userID:%v, email:%v", req.Data.UserID, users.RedactedEmail(email))
+		switch {
+		case errors.Is(err, emaillink.ErrUserNotFound):
+			return nil, server.NotFound(err, emailNotFoundErrorCode)
+		case errors.Is(err, users.ErrDuplicate):
+			if tErr := terror.As(err); tErr != nil {
+				return nil, server.Conflict(err, duplicateUserErrorCode, errorcatalog.ConflictData(tErr.Data))
+			}
+
+			fallthrough
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK[any](), nil
+}
+
+// RemoveEmail godoc
+//
+//	@Schemes
+//	@Description	Removes a secondary email of the user, confirmed or not. The primary email can't be removed, only replaced.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header	string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path	string	true	"ID of the user"
+//	@Param			email				path	string	true	"The secondary email"
+//	@Success		200					"OK"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if the email is not a secondary email of the user"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/emails/{email} [DELETE].
+func (s *service) RemoveEmail( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[RemoveEmailArg, any],
+) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	email := strings.TrimSpace(strings.ToLower(req.Data.Email))
+	if err := s.authEmailLinkClient.RemoveEmail(ctx, req.Data.UserID, email); err != nil {
+		err = errors.Wrapf(err, "failed to RemoveEmail for userID:%v, email:%v", req.Data.UserID, users.RedactedEmail(email))
+		if errors.Is(err, emaillink.ErrUserNotFound) {
+			return nil, server.NotFound(err, emailNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK[any](), nil
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupUserImportBatchesRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("user-import-batches", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.CreateUserImportBatch)))
+}
+
+// CreateUserImportBatch godoc
+//
+//	@Schemes
+//	@Description	Imports, in the background, the users of an NDJSON file, for migrations from legacy systems. Only for admins.
+//	@Description	Every line is an object with `id`, `email` and, optionally, `username`, `referredBy` and `createdAt`. The referrers have to exist already or come before their referrals.
+//	@Description	The invalid lines and the ones repeating the id, email or username of an earlier line are only reported. The users that exist already are skipped, so the same file can be imported again, to resume an interrupted migration.
+//	@Description	The users are imported in chunks, by all the replicas, so the batch is resumed if they are restarted. Its progress and final report are available via `GET /v1r/user-import-batches/{batchId}`.
+//	@Description	With `dryRun`, the batch isn't stored; the records are only checked, against the existing users too, and the preview of what would be imported is returned, with 200.
+//	@Tags			Users
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			Authorization		header		string								true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string								false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			multiPartFormData	formData	CreateUserImportBatchRequestBody	true	"Request params"
+//	@Param			file				formData	file								true	"The NDJSON file with the users"
+//	@Success		200					{object}	users.UserImportBatch	"if dryRun"
+//	@Success		201					{object}	users.UserImportBatch
+//	@Failure		400					{object}	server.ErrorResponse	"if the file is empty, too big or not NDJSON"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/user-import-batches [POST].
+func (s *service) CreateUserImportBatch( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[CreateUserImportBatchRequestBody, users.UserImportBatch],
+) (*server.Response[users.UserImportBatch], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if err := req.Data.validate(); err != nil {
+		return nil, err
+	}
+	file, err := req.Data.File.Open()
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to open the user import file %v", req.Data.File.Filename))
+	}
+	defer func() {
+		log.Error(errors.Wrapf(file.Close(), "failed to close the user import file %v", req.Data.File.Filename))
+	}()
+	batch := &users.UserImportBatch{
+		RequestedBy: req.AuthenticatedUser.UserID,
+		Reason:      strings.TrimSpace(req.Data.Reason),
+		Snapshots:   req.Data.Snapshots,
+	}
+	if req.Data.DryRun {
+		ctx, _ = users.DryRunContext(ctx) //nolint:revive // .
+	}
+	if err = s.usersProcessor.CreateUserImportBatch(ctx, batch, file); err != nil {
+		err = errors.Wrapf(err, "failed to CreateUserImportBatch for %#v", batch)
+		if errors.Is(err, users.ErrInvalidUserImportFile) {
+			return nil, server.BadRequest(err, invalidUserImportFileErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "file"))
+		}
+
+		return nil, server.Unexpected(err)
+	}
+	if batch.DryRun != nil {
+		return server.OK(batch), nil
+	}
+
+	return server.Created(batch), nil
+}
+
+func (arg *CreateUserImportBatchRequestBody) validate() *server.Response[server.ErrorResponse] {
+	if arg.Snapshots == "" {
+		arg.Snapshots = users.SendUserImportSnapshotsMode
+	}
+	switch arg.Snapshots {
+	case users.SendUserImportSnapshotsMode, users.BatchUserImportSnapshotsMode, users.SuppressUserImportSnapshotsMode:
+	default:
+		return server.UnprocessableEntity(errors.Errorf("invalid snapshots `%v`", arg.Snapshots), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.InvalidReason, "snapshots"))
+	}
+	switch {
+	case strings.TrimSpace(arg.Reason) == "":
+		return server.UnprocessableEntity(errors.New("reason is required"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.RequiredReason, "reason"))
+	case arg.File == nil:
+		return server.UnprocessableEntity(errors.New("file is required"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.RequiredReason, "file"))
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/terror"
+)
+
+func (s *service) setupUserRectificationsRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("users/:userId/rectifications", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.RectifyUser)))
+}
+
+// RectifyUser godoc
+//
+//	@Schemes
+//	@Description	Rectifies the personal data of an user, for its right to rectification. Only for admins.
+//	@Description	Unlike `PATCH /users/{userId}`, a reason code and a justification are mandatory, and the country change limits don't apply.
+//	@Description	If the user was changed since `checksum`, it fails, unless `override` is true. The changes are audited and the user is notified.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string					true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string					false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string					true	"ID of the user"
+//	@Param			request				body		RectifyUserRequestBody	true	"Request params"
+//	@Success		200					{object}	User					"the rectified user"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if the user is not found"
+//	@Failure		409					{object}	server.ErrorResponse	"if the user was changed since `checksum`, without `override`; or if the username conflicts with another user's"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails or if the user already has the provided values"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/rectifications [POST].
+func (s *service) RectifyUser( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[RectifyUserRequestBody, User],
+) (*server.Response[User], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if err := validateRectifyUser(req); err != nil {
+		return nil, err
+	}
+	rectification := &users.UserRectification{
+		UserID:        req.Data.UserID,
+		RectifiedBy:   req.AuthenticatedUser.UserID,
+		ReasonCode:    req.Data.ReasonCode,
+		Justification: strings.TrimSpace(req.Data.Justification),
+		Checksum:      req.Data.Checksum,
+		Override:      req.Data.Override,
+	}
+	usr := new(users.User)
+	if req.Data.FirstName != "" {
+		usr.FirstName = &req.Data.FirstName
+	}
+	if req.Data.LastName != "" {
+		usr.LastName = &req.Data.LastName
+	}
+	usr.Username, usr.Country, usr.City = req.Data.Username, req.Data.Country, req.Data.City
+	if err := s.usersProcessor.RectifyUser(ctx, rectification, usr); err != nil {
+		err = errors.Wrapf(err, "failed to RectifyUser for %#v", req.Data)
+		switch {
+		case errors.Is(err, users.ErrRaceCondition):
+			return nil, server.Conflict(err, raceConditionErrorCode)
+		case errors.Is(err, users.ErrNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		case errors.Is(err, users.ErrNothingToRectify):
+			return nil, server.UnprocessableEntity(err, nothingToRectifyErrorCode)
+		case errors.Is(err, users.ErrInvalidCountry):
+			return nil, server.BadRequest(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "country"))
+		case errors.Is(err, users.ErrDuplicate):
+			if tErr := terror.As(err); tErr != nil {
+				return nil, server.Conflict(err, duplicateUserErrorCode, errorcatalog.ConflictData(tErr.Data))
+			}
+
+			fallthrough
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(&User{User: usr, Checksum: usr.Checksum()}), nil
+}
+
+func validateRectifyUser(req *server.Request[RectifyUserRequestBody, User]) *server.Response[server.ErrorResponse] {
+	if req.Data.FirstName == "" && req.Data.LastName == "" && req.Data.Username == "" && req.Data.Country == "" && req.Data.City == "" {
+		err := errors.New("at least one of firstName, lastName, username, country or city is required")
+
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.RequiredReason, "firstName", "lastName", "username", "country", "city"))
+	}
+	if err := verifyPhoneNumberAndUsername("", "", req.Data.Username); err != nil {
+		return err
+	}
+	var validReasonCode bool
+	for _, reasonCode := range users.RectificationReasonCodes {
+		validReasonCode = validReasonCode || reasonCode == req.Data.ReasonCode
+	}
+	if !validReasonCode {
+		err := errors.Errorf("reasonCode '%v' is invalid, valid values are %#v", req.Data.ReasonCode, users.RectificationReasonCodes)
+
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "reasonCode"))
+	}
+	if strings.TrimSpace(req.Data.Justification) == "" {
+		return server.UnprocessableEntity(errors.New("justification is required"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.RequiredReason, "justification"))
+	}
+	if req.Data.Checksum == "" && !req.Data.Override {
+		return server.UnprocessableEntity(errors.New("checksum is required, unless override is true"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.RequiredReason, "checksum"))
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupUserReportsRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		POST("users/:userId/reports", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.ReportUser))).
+		PUT("users/:userId/reports/resolution", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.ResolveUserReports)))
+}
+
+// ReportUser godoc
+//
+//	@Schemes
+//	@Description	Reports an user for abuse, on behalf of the authenticated user. The report is pending until an admin resolves it.
+//	@Description	Users with too many pending reports are flagged for review.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string					true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string					false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string					true	"ID of the reported user"
+//	@Param			request				body		ReportUserRequestBody	true	"Request params"
+//	@Success		201					{object}	users.UserReport
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		404					{object}	server.ErrorResponse	"if the reported user is not found"
+//	@Failure		409					{object}	server.ErrorResponse	"if the user already has a pending report of the reported user"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/reports [POST].
+func (s *service) ReportUser( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[ReportUserRequestBody, users.UserReport],
+) (*server.Response[users.UserReport], *server.Response[server.ErrorResponse]) {
+	report := &users.UserReport{
+		UserID:     req.Data.UserID,
+		ReportedBy: req.AuthenticatedUser.UserID,
+		Reason:     req.Data.Reason,
+		Text:       strings.TrimSpace(req.Data.Text),
+	}
+	if err := validateReportUser(report); err != nil {
+		return nil, err
+	}
+	if err := s.usersProcessor.ReportUser(ctx, report); err != nil {
+		err = errors.Wrapf(err, "failed to ReportUser for %#v", req.Data)
+		switch {
+		case errors.Is(err, users.ErrDuplicate):
+			return nil, server.Conflict(err, userAlreadyReportedErrorCode)
+		case errors.Is(err, users.ErrRelationNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.Created(report), nil
+}
+
+func validateReportUser(report *users.UserReport) *server.Response[server.ErrorResponse] {
+	if report.UserID == report.ReportedBy {
+		return server.UnprocessableEntity(errors.New("users can't report themselves"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.InvalidReason, "userId"))
+	}
+	var validReason bool
+	for _, reason := range users.UserReportReasons {
+		validReason = validReason || reason == report.Reason
+	}
+	if !validReason {
+		err := errors.Errorf("reason '%v' is invalid, valid values are %#v", report.Reason, users.UserReportReasons)
+
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "reason"))
+	}
+	if report.Reason == users.OtherUserReportReason && report.Text == "" {
+		return server.UnprocessableEntity(errors.New("text is required for the reason `other`"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.RequiredReason, "text"))
+	}
+	if len([]rune(report.Text)) > maxUserReportTextLength {
+		return server.UnprocessableEntity(errors.Errorf("text is longer than %v characters", maxUserReportTextLength), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.InvalidReason, "text"))
+	}
+
+	return nil
+}
+
+// ResolveUserReports godoc
+//
+//	@Schemes
+//	@Description	Resolves all the pending reports of an user, removing it from the moderation queue. Only for admins.
+//	@Description	`actioned` means the reports were founded and the user was dealt with, `dismissed` that they were not founded.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string							true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string							false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string							true	"ID of the reported user"
+//	@Param			request				body		ResolveUserReportsRequestBody	true	"Request params"
+//	@Success		200					{array}		users.UserReport				"the resolved reports"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if the user has no pending reports"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/reports/resolution [PUT].
+func (s *service) ResolveUserReports( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[ResolveUserReportsRequestBody, []*users.UserReport],
+) (*server.Response[[]*users.UserReport], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	resolved, err := s.usersProcessor.ResolveUserReports(ctx, req.Data.UserID, req.AuthenticatedUser.UserID, req.Data.Resolution)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to ResolveUserReports for %#v", req.Data)
+		switch {
+		case errors.Is(err, users.ErrInvalidUserReportResolution):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "resolution"))
+		case errors.Is(err, users.ErrNotFound):
+			return nil, server.NotFound(err, userReportsNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(&resolved), nil
+}
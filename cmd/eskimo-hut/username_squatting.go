@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupUsernameSquattingRoutes(router *server.Router) {
+	router.
+		Group("v1w").
+		PUT("users/:userId/squatted-username/decision", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.DecideSquattedUsername)))
+}
+
+// DecideSquattedUsername godoc
+//
+//	@Schemes
+//	@Description	Overrides the username squatting detection for an user. Only for admins.
+//	@Description	`exempt` makes sure the user is never flagged (again), while `release` frees the flagged username right away, even if the user became active.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string								true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string								false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string								true	"ID of the user"
+//	@Param			request				body		DecideSquattedUsernameRequestBody	true	"Request params"
+//	@Success		200					{object}	users.SquattedUsername
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if the user or its flagged username is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/squatted-username/decision [PUT].
+func (s *service) DecideSquattedUsername( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[DecideSquattedUsernameRequestBody, users.SquattedUsername],
+) (*server.Response[users.SquattedUsername], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	squatted, err := s.usersProcessor.DecideSquattedUsername(ctx, req.Data.UserID, req.AuthenticatedUser.UserID, req.Data.Decision)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to DecideSquattedUsername for %#v", req.Data)
+		switch {
+		case errors.Is(err, users.ErrInvalidSquattedUsernameDecision):
+			return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "decision"))
+		case errors.Is(err, users.ErrNotFound) && req.Data.Decision == users.ExemptSquattedUsernameDecision:
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		case errors.Is(err, users.ErrNotFound):
+			return nil, server.NotFound(err, squattedUsernameNotFoundErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(squatted), nil
+}
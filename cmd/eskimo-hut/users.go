@@ -13,6 +13,8 @@ import (
 	"github.com/pkg/errors"
 
 	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
 	"github.com/ice-blockchain/eskimo/users"
 	"github.com/ice-blockchain/wintr/auth"
 	"github.com/ice-blockchain/wintr/log"
@@ -23,9 +25,11 @@ import (
 func (s *service) setupUserRoutes(router *server.Router) {
 	router.
 		Group("v1w").
-		POST("users", server.RootHandler(s.CreateUser)).
-		PATCH("users/:userId", server.RootHandler(s.ModifyUser)).
-		DELETE("users/:userId", server.RootHandler(s.DeleteUser))
+		POST("users", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.CreateUser))).
+		PATCH("users/:userId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.ModifyUser))).
+		POST("users/:userId/profile-picture-upload-urls", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.GenerateProfilePictureUploadURL))).
+		PUT("users/:userId/pending-country-change/decision", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.DecidePendingCountryChange))).
+		DELETE("users/:userId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Users, s.DeleteUser)))
 }
 
 // CreateUser godoc
@@ -40,8 +44,9 @@ func (s *service) setupUserRoutes(router *server.Router) {
 //	@Param			X-Account-Metadata	header		string					false	"Insert your metadata token"	default(<Add metadata token here>)
 //	@Param			request				body		CreateUserRequestBody	true	"Request params"
 //	@Success		201					{object}	User
-//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail (ex. the dateOfBirth), or the names are profane"
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"code:UNDERAGE_USER if the user is younger than the minimum age of its country; it's created, but blocked"
 //	@Failure		404					{object}	server.ErrorResponse	"if no such referred by"
 //	@Failure		409					{object}	server.ErrorResponse	"user already exists with that ID, email or phone number"
 //	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
@@ -61,9 +66,15 @@ func (s *service) CreateUser( //nolint:funlen,gocritic // .
 		switch {
 		case errors.Is(err, users.ErrRelationNotFound):
 			return nil, server.NotFound(err, referralNotFoundErrorCode)
+		case errors.Is(err, users.ErrProfanity):
+			return nil, server.BadRequest(err, profanityNotAllowedErrorCode, profanityData(err))
+		case errors.Is(err, users.ErrInvalidDateOfBirth):
+			return nil, server.BadRequest(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "dateOfBirth"))
+		case errors.Is(err, users.ErrUnderage):
+			return nil, server.ForbiddenWithCode(err, underageUserErrorCode)
 		case errors.Is(err, users.ErrDuplicate):
 			if tErr := terror.As(err); tErr != nil {
-				return nil, server.Conflict(err, duplicateUserErrorCode, tErr.Data)
+				return nil, server.Conflict(err, duplicateUserErrorCode, errorcatalog.ConflictData(tErr.Data))
 			}
 
 			fallthrough
@@ -99,6 +110,7 @@ func buildUserForCreation(req *server.Request[CreateUserRequestBody, User]) *use
 	usr.ClientData = req.Data.ClientData
 	usr.Language = req.Data.Language
 	usr.ReferredBy = req.Data.ReferredBy
+	usr.DateOfBirth = req.Data.DateOfBirth
 
 	return usr
 }
@@ -108,7 +120,9 @@ func validateCreateUser(req *server.Request[CreateUserRequestBody, User]) *serve
 		return err
 	}
 	if strings.EqualFold(req.AuthenticatedUser.UserID, req.Data.ReferredBy) {
-		return server.UnprocessableEntity(errors.New("you cannot use yourself as your own referral"), invalidPropertiesErrorCode)
+		err := errors.New("you cannot use yourself as your own referral")
+
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.NotAllowedReason, "referredBy"))
 	}
 
 	return nil
@@ -126,11 +140,11 @@ func validateCreateUser(req *server.Request[CreateUserRequestBody, User]) *serve
 //	@Param			X-Account-Metadata	header		string					false	"Insert your metadata token"	default(<Add metadata token here>)
 //	@Param			userId				path		string					true	"ID of the user"
 //	@Param			multiPartFormData	formData	ModifyUserRequestBody	true	"Request params"
-//	@Param			profilePicture		formData	file					false	"The new profile picture for the user"
+//	@Param			profilePicture		formData	file					false	"The new profile picture for the user. Alternatively, it can be uploaded directly to the storage beforehand, see `uploadedProfilePictureName`"
 //	@Success		200					{object}	ModifyUserResponse
-//	@Failure		400					{object}	server.ErrorResponse	"if validations fail or user for modification email is blocked"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail, the username or names are profane, or user for modification email is blocked"
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
-//	@Failure		403					{object}	server.ErrorResponse	"not allowed"
+//	@Failure		403					{object}	server.ErrorResponse	"not allowed;code:GUEST_ACCOUNT_NOT_ALLOWED if the account is a guest one and the email is being changed;code:UNDERAGE_USER if the dateOfBirth is below the minimum age of the country of the user"
 //	@Failure		404					{object}	server.ErrorResponse	"user is not found; or the referred by is not found"
 //	@Failure		409					{object}	server.ErrorResponse	"if username, email or phoneNumber conflict with another user's"
 //	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
@@ -141,6 +155,11 @@ func (s *service) ModifyUser( //nolint:gocritic,funlen,revive,cyclop // .
 	ctx context.Context,
 	req *server.Request[ModifyUserRequestBody, ModifyUserResponse],
 ) (*server.Response[ModifyUserResponse], *server.Response[server.ErrorResponse]) {
+	if req.Data.Email != "" {
+		if errResp := forbidGuests(&req.AuthenticatedUser); errResp != nil {
+			return nil, errResp
+		}
+	}
 	if err := validateModifyUser(ctx, req); err != nil {
 		return nil, err
 	}
@@ -155,7 +174,7 @@ func (s *service) ModifyUser( //nolint:gocritic,funlen,revive,cyclop // .
 			return nil, server.BadRequest(err, userBlockedErrorCode)
 		case errors.Is(err, emaillink.ErrUserDuplicate):
 			if tErr := terror.As(err); tErr != nil {
-				return nil, server.Conflict(err, duplicateUserErrorCode, tErr.Data)
+				return nil, server.Conflict(err, duplicateUserErrorCode, errorcatalog.ConflictData(tErr.Data))
 			}
 		default:
 			return nil, server.Unexpected(errors.Wrapf(err, "failed to trigger email modification for request:%#v", req.Data))
@@ -172,10 +191,20 @@ func (s *service) ModifyUser( //nolint:gocritic,funlen,revive,cyclop // .
 		case errors.Is(err, users.ErrNotFound):
 			return nil, server.NotFound(err, userNotFoundErrorCode)
 		case errors.Is(err, users.ErrInvalidCountry):
-			return nil, server.BadRequest(errors.Errorf("invalid country %v", req.Data.Country), invalidPropertiesErrorCode)
+			err = errors.Errorf("invalid country %v", req.Data.Country)
+
+			return nil, server.BadRequest(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "country"))
+		case errors.Is(err, users.ErrProfanity):
+			return nil, server.BadRequest(err, profanityNotAllowedErrorCode, profanityData(err))
+		case errors.Is(err, users.ErrInvalidDateOfBirth):
+			return nil, server.BadRequest(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "dateOfBirth"))
+		case errors.Is(err, users.ErrUnderage):
+			return nil, server.ForbiddenWithCode(err, underageUserErrorCode)
+		case errors.Is(err, users.ErrInvalidProfilePicture):
+			return nil, server.BadRequest(err, invalidProfilePictureErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "profilePicture", "uploadedProfilePictureName"))
 		case errors.Is(err, users.ErrDuplicate):
 			if tErr := terror.As(err); tErr != nil {
-				return nil, server.Conflict(err, duplicateUserErrorCode, tErr.Data)
+				return nil, server.Conflict(err, duplicateUserErrorCode, errorcatalog.ConflictData(tErr.Data))
 			}
 
 			fallthrough
@@ -187,6 +216,48 @@ func (s *service) ModifyUser( //nolint:gocritic,funlen,revive,cyclop // .
 	return server.OK(&ModifyUserResponse{User: &User{User: usr, Checksum: usr.Checksum()}, LoginSession: loginSession}), nil
 }
 
+// GenerateProfilePictureUploadURL godoc
+//
+//	@Schemes
+//	@Description	Generates a pre-signed URL that the client can use to upload a profile picture directly to the storage. After uploading it, with the returned `method` and `headers`, the `pictureName` must be provided as `uploadedProfilePictureName` to `PATCH /users/{userId}`.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string										true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string										false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string										true	"ID of the user"
+//	@Param			request				body		GenerateProfilePictureUploadURLRequestBody	true	"Request params"
+//	@Success		201					{object}	users.ProfilePictureUpload
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail or signed uploads are not supported by the storage"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"user is not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/profile-picture-upload-urls [POST].
+func (s *service) GenerateProfilePictureUploadURL( //nolint:gocritic // .
+	ctx context.Context,
+	req *server.Request[GenerateProfilePictureUploadURLRequestBody, users.ProfilePictureUpload],
+) (*server.Response[users.ProfilePictureUpload], *server.Response[server.ErrorResponse]) {
+	upload, err := s.usersProcessor.GenerateProfilePictureUploadURL(ctx, req.Data.UserID, req.Data.ContentType)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to generate profile picture upload url for %#v", req.Data)
+		switch {
+		case errors.Is(err, users.ErrNotFound):
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		case errors.Is(err, users.ErrInvalidProfilePicture):
+			return nil, server.BadRequest(err, invalidProfilePictureErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "contentType"))
+		case errors.Is(err, users.ErrSignedUploadNotSupported):
+			return nil, server.BadRequest(err, signedUploadNotSupportedErrorCode)
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.Created(upload), nil
+}
+
 func validateModifyUser(ctx context.Context, req *server.Request[ModifyUserRequestBody, ModifyUserResponse]) *server.Response[server.ErrorResponse] {
 	if err := req.Data.verifyIfAtLeastOnePropertyProvided(); err != nil {
 		return err
@@ -195,12 +266,22 @@ func validateModifyUser(ctx context.Context, req *server.Request[ModifyUserReque
 		return err
 	}
 	if strings.EqualFold(req.AuthenticatedUser.UserID, req.Data.ReferredBy) {
-		return server.UnprocessableEntity(errors.New("you cannot use yourself as your own referral"), invalidPropertiesErrorCode)
+		err := errors.New("you cannot use yourself as your own referral")
+
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.NotAllowedReason, "referredBy"))
+	}
+	if req.Data.UploadedProfilePictureName != "" && (req.Data.ProfilePicture != nil || req.Data.ResetProfilePicture != nil) {
+		err := errors.New("uploadedProfilePictureName can't be provided together with profilePicture or resetProfilePicture")
+		data := errorcatalog.FieldsData(errorcatalog.NotAllowedReason, "uploadedProfilePictureName", "profilePicture", "resetProfilePicture")
+
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode, data)
 	}
 	if req.Data.ClientData != nil {
 		r := make(users.JSON)
 		if err := json.UnmarshalContext(ctx, []byte(*req.Data.ClientData), &r); err != nil {
-			return server.UnprocessableEntity(errors.Wrap(err, "`clientData` has to be a json structure"), invalidPropertiesErrorCode)
+			err = errors.Wrap(err, "`clientData` has to be a json structure")
+
+			return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "clientData"))
 		}
 		req.Data.clientData = &r
 	}
@@ -234,7 +315,7 @@ func (s *service) emailUpdateRequested(
 		users.ConfirmedEmailContext(ctx, loggedInUser.Email),
 		newEmail, deviceID, language, "",
 	); err != nil {
-		return "", "", errors.Wrapf(err, "can't send sign in link to email:%v", newEmail)
+		return "", "", errors.Wrapf(err, "can't send sign in link to email:%v", users.RedactedEmail(newEmail))
 	}
 
 	return "", loginSession, nil
@@ -265,7 +346,7 @@ func validateHiddenProfileElements(req *server.Request[ModifyUserRequestBody, Mo
 	if invalidHiddenProfileElement != nil {
 		err := errors.Errorf("hiddenProfileElement '%v' is invalid, valid values are %#v", *invalidHiddenProfileElement, users.HiddenProfileElements)
 
-		return server.UnprocessableEntity(err, invalidPropertiesErrorCode)
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "hiddenProfileElements"))
 	}
 
 	return nil
@@ -290,6 +371,9 @@ func buildUserForModification(req *server.Request[ModifyUserRequestBody, ModifyU
 		usr.MiningBlockchainAccountAddress = usr.ID
 	}
 	usr.Language = req.Data.Language
+	if req.Data.DateOfBirth != "" {
+		usr.DateOfBirth = &req.Data.DateOfBirth
+	}
 	if req.Data.ClearHiddenProfileElements != nil && *req.Data.ClearHiddenProfileElements {
 		empty := make(users.Enum[users.HiddenProfileElement], 0, 0) //nolint:gosimple // .
 		usr.HiddenProfileElements = &empty
@@ -302,14 +386,18 @@ func buildUserForModification(req *server.Request[ModifyUserRequestBody, ModifyU
 	if req.Data.ResetProfilePicture != nil && *req.Data.ResetProfilePicture {
 		req.Data.ProfilePicture = new(multipart.FileHeader)
 		req.Data.ProfilePicture.Header = textproto.MIMEHeader{"Reset": []string{"true"}}
+	} else if req.Data.UploadedProfilePictureName != "" {
+		req.Data.ProfilePicture = new(multipart.FileHeader)
+		req.Data.ProfilePicture.Filename = req.Data.UploadedProfilePictureName
+		req.Data.ProfilePicture.Header = textproto.MIMEHeader{"Uploaded": []string{"true"}}
 	}
 	if strings.TrimSpace(req.Data.ReferredBy) != "" {
 		log.Info(fmt.Sprintf("user(id:`%v`,email:`%v`) attempted to set referredBy to `%v`",
-			req.AuthenticatedUser.UserID, req.AuthenticatedUser.Email, req.Data.ReferredBy))
+			req.AuthenticatedUser.UserID, users.RedactedEmail(req.AuthenticatedUser.Email), req.Data.ReferredBy))
 	}
 	if strings.TrimSpace(req.Data.Username) != "" {
 		log.Info(fmt.Sprintf("user(id:`%v`,email:`%v`) attempted to set username to `%v`",
-			req.AuthenticatedUser.UserID, req.AuthenticatedUser.Email, req.Data.Username))
+			req.AuthenticatedUser.UserID, users.RedactedEmail(req.AuthenticatedUser.Email), req.Data.Username))
 	}
 
 	return usr
@@ -327,6 +415,7 @@ func (a *ModifyUserRequestBody) verifyIfAtLeastOnePropertyProvided() *server.Res
 		a.Username == "" &&
 		a.ReferredBy == "" &&
 		a.Language == "" &&
+		a.DateOfBirth == "" &&
 		a.AgendaPhoneNumberHashes == "" &&
 		a.BlockchainAccountAddress == "" &&
 		a.MiningBlockchainAccountAddress == "" &&
@@ -335,8 +424,9 @@ func (a *ModifyUserRequestBody) verifyIfAtLeastOnePropertyProvided() *server.Res
 		a.ClearHiddenProfileElements == nil &&
 		a.ClientData == nil &&
 		a.ProfilePicture == nil &&
+		a.UploadedProfilePictureName == "" &&
 		a.ResetProfilePicture == nil {
-		return server.UnprocessableEntity(errors.New("modify request without values"), invalidPropertiesErrorCode)
+		return server.UnprocessableEntity(errors.New("modify request without values"), invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.RequiredReason))
 	}
 
 	return nil
@@ -344,28 +434,87 @@ func (a *ModifyUserRequestBody) verifyIfAtLeastOnePropertyProvided() *server.Res
 
 func verifyPhoneNumberAndUsername(phoneNumber, phoneNumberHash, username string) *server.Response[server.ErrorResponse] {
 	if (phoneNumber == "" && phoneNumberHash != "") || (phoneNumberHash == "" && phoneNumber != "") {
-		return server.UnprocessableEntity(errors.New("phoneNumber must be provided only together with phoneNumberHash"), invalidPropertiesErrorCode)
+		err := errors.New("phoneNumber must be provided only together with phoneNumberHash")
+
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.RequiredReason, "phoneNumber", "phoneNumberHash"))
 	}
 	if username != "" && !users.CompiledUsernameRegex.MatchString(username) {
 		err := errors.Errorf("username: %v is invalid, it should match regex: %v", username, users.UsernameRegex)
 
-		return server.BadRequest(err, invalidUsernameErrorCode)
+		return server.BadRequest(err, invalidUsernameErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "username"))
 	}
 
 	return nil
 }
 
+// profanityData provides the field found to be profane, so that clients can show a friendly message next to it.
+func profanityData(err error) map[string]any {
+	fields := make([]string, 0, 1)
+	if tErr := terror.As(err); tErr != nil {
+		if field, ok := tErr.Data["field"].(string); ok && field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	return errorcatalog.FieldsData(errorcatalog.InvalidReason, fields...)
+}
+
+// DecidePendingCountryChange godoc
+//
+//	@Schemes
+//	@Description	Approves or rejects the pending country change of an user. If approved, the country is changed. Only for admins.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string									true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string									false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string									true	"ID of the user"
+//	@Param			request				body		DecidePendingCountryChangeRequestBody	true	"Request params"
+//	@Success		200					{object}	users.CountryChange
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if there is no pending country change for the user"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/pending-country-change/decision [PUT].
+func (s *service) DecidePendingCountryChange( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[DecidePendingCountryChangeRequestBody, users.CountryChange],
+) (*server.Response[users.CountryChange], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	change, err := s.usersProcessor.DecidePendingCountryChange(ctx, req.Data.UserID, req.AuthenticatedUser.UserID, *req.Data.Approve)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to decide pending country change for %#v", req.Data)
+		switch {
+		case errors.Is(err, users.ErrNotFound):
+			return nil, server.NotFound(err, countryChangeNotFoundErrorCode)
+		case errors.Is(err, users.ErrInvalidCountry):
+			return nil, server.BadRequest(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "country"))
+		default:
+			return nil, server.Unexpected(err)
+		}
+	}
+
+	return server.OK(change), nil
+}
+
 // DeleteUser godoc
 //
 //	@Schemes
-//	@Description	Deletes an user account
+//	@Description	Deletes an user account, or anonymizes it (strips its PII, but keeps it, with its referrals), depending on the configured deletion policy.
 //	@Tags			Accounts
 //	@Accept			json
 //	@Produce		json
 //	@Param			Authorization		header	string	true	"Insert your access token"		default(Bearer <Add access token here>)
 //	@Param			X-Account-Metadata	header	string	false	"Insert your metadata token"	default(<Add metadata token here>)
 //	@Param			userId				path	string	true	"ID of the User"
-//	@Success		200					"OK - found and deleted"
+//	@Param			anonymize			query	bool	false	"Only for admins. Overrides the configured deletion policy"
+//	@Param			dryRun				query	bool	false	"If true, nothing is deleted; the preview of what would be is returned instead"
+//	@Success		200					{object}	users.DryRunPreview	"OK - found and deleted; or the preview, if dryRun"
 //	@Success		204					"No Content - already deleted"
 //	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
@@ -378,18 +527,30 @@ func (s *service) DeleteUser( //nolint:gocritic // False negative.
 	ctx context.Context,
 	req *server.Request[DeleteUserArg, any],
 ) (*server.Response[any], *server.Response[server.ErrorResponse]) {
-	if req.Data.UserID != req.AuthenticatedUser.UserID {
+	if req.Data.UserID != req.AuthenticatedUser.UserID || req.Data.Anonymize != nil {
 		if req.AuthenticatedUser.Role != adminRole {
 			return nil, server.Forbidden(errors.New("not allowed"))
 		}
 	}
-	if err := s.usersProcessor.DeleteUser(ctx, req.Data.UserID); err != nil {
+	ctx = users.ContextWithXAccountMetadata(ctx, req.Data.XAccountMetadata)                       //nolint:revive // .
+	ctx = users.ContextWithAuthorization(ctx, req.Data.Authorization)                             //nolint:revive // .
+	ctx = context.WithValue(ctx, users.RequestingUserIDCtxValueKey, req.AuthenticatedUser.UserID) //nolint:revive,staticcheck // .
+	var preview *users.DryRunPreview
+	if req.Data.DryRun {
+		ctx, preview = users.DryRunContext(ctx) //nolint:revive // .
+	}
+	if err := s.usersProcessor.DeleteOrAnonymizeUser(ctx, req.Data.UserID, req.Data.Anonymize); err != nil {
 		if errors.Is(err, users.ErrNotFound) {
 			return server.NoContent(), nil
 		}
 
 		return nil, server.Unexpected(errors.Wrapf(err, "failed to delete user with id: %v", req.Data.UserID))
 	}
+	if preview != nil {
+		var resp any = preview
+
+		return server.OK(&resp), nil
+	}
 	if err := server.Auth(ctx).DeleteUser(ctx, req.Data.UserID); err != nil && !errors.Is(err, auth.ErrUserNotFound) {
 		return nil, server.Unexpected(errors.Wrapf(err, "failed to delete auth user:%#v", req.Data.UserID))
 	}
@@ -271,7 +271,7 @@ func TestService_CreateUser_Failure_PhoneNumberRequiredIfPhoneNumberHashIsSet(t
 			assert.Equal(t, 422, status)
 		})
 		IT("returns specific error code and some error message", func() {
-			expected := `{"error":".*phoneNumber.*phoneNumberHash.*","code":"INVALID_PROPERTIES"}` //nolint:goconst // Nope, we need to be descriptive.
+			expected := `{"data":{"details":{"reason":"required","fields":\["phoneNumber","phoneNumberHash"\]}},"error":".*phoneNumber.*phoneNumberHash.*","code":"INVALID_PROPERTIES"}` //nolint:goconst // Nope, we need to be descriptive.
 			bridge.AssertResponseBody(t, expected, body)
 		})
 	})
@@ -300,7 +300,7 @@ func TestService_CreateUser_Failure_PhoneNumberHashRequiredIfPhoneNumberIsSet(t
 			assert.Equal(t, 422, status)
 		})
 		IT("returns specific error code and some error message", func() {
-			expected := `{"error":".*phoneNumber.*phoneNumberHash.*","code":"INVALID_PROPERTIES"}`
+			expected := `{"data":{"details":{"reason":"required","fields":\["phoneNumber","phoneNumberHash"\]}},"error":".*phoneNumber.*phoneNumberHash.*","code":"INVALID_PROPERTIES"}`
 			bridge.AssertResponseBody(t, expected, body)
 		})
 	})
@@ -395,7 +395,7 @@ func TestService_CreateUser_Failure_InvalidUsername(t *testing.T) {
 				assert.Equal(t, 400, status, "for username %v %v", username, username[len(username)-1])
 			})
 			IT("returns specific error code and some error message", func() {
-				expected := `{"error":".+","code":"INVALID_USERNAME"}`
+				expected := `{"data":{"details":{"reason":"invalid","fields":\["username"\]}},"error":".+","code":"INVALID_USERNAME"}`
 				bridge.AssertResponseBody(t, expected, body)
 			})
 		})
@@ -450,7 +450,7 @@ func TestService_CreateUser_Failure_DuplicateUserID(t *testing.T) {
 			assert.Equal(t, 409, status)
 		})
 		IT("returns specific error code, extra data and some error message", func() {
-			expected := `{"data":{"field":"id"},"error":".+","code":"CONFLICT_WITH_ANOTHER_USER"}`
+			expected := `{"data":{"details":{"reason":"conflict","fields":\["id"\]},"field":"id"},"error":".+","code":"CONFLICT_WITH_ANOTHER_USER"}`
 			bridge.AssertResponseBody(t, expected, body)
 		})
 	})
@@ -482,7 +482,7 @@ func TestService_CreateUser_Failure_DuplicateUserName(t *testing.T) {
 			assert.Equal(t, 409, status)
 		})
 		IT("returns specific error code, extra data and some error message", func() {
-			expected := `{"data":{"field":"username"},"error":".+","code":"CONFLICT_WITH_ANOTHER_USER"}`
+			expected := `{"data":{"details":{"reason":"conflict","fields":\["username"\]},"field":"username"},"error":".+","code":"CONFLICT_WITH_ANOTHER_USER"}`
 			bridge.AssertResponseBody(t, expected, body)
 		})
 	})
@@ -540,7 +540,7 @@ func TestService_CreateUser_Failure_SelfReferred(t *testing.T) {
 			assert.Equal(t, 422, status)
 		})
 		IT("returns specific error code and some error message", func() {
-			expected := `{"error":".+","code":"INVALID_PROPERTIES"}`
+			expected := `{"data":{"details":{"reason":"notAllowed","fields":\["referredBy"\]}},"error":".+","code":"INVALID_PROPERTIES"}`
 			bridge.AssertResponseBody(t, expected, body)
 		})
 	})
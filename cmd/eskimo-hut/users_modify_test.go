@@ -131,7 +131,7 @@ func TestService_ModifyUser_Failure_NonExistingCountry(t *testing.T) {
 			assert.Equal(t, 400, status)
 		})
 		IT("returns error code and message", func() {
-			bridge.AssertResponseBody(t, `{"error":"invalid country NON_EXISTING_COUNTRY","code":"INVALID_PROPERTIES"}`, body)
+			bridge.AssertResponseBody(t, `{"data":{"details":{"reason":"invalid","fields":\["country"\]}},"error":"invalid country NON_EXISTING_COUNTRY","code":"INVALID_PROPERTIES"}`, body)
 		})
 	})
 }
@@ -195,7 +195,7 @@ func TestService_ModifyUser_Failure_PhoneNumberNoHash(t *testing.T) {
 			assert.Equal(t, 422, status)
 		})
 		IT("returns error code and message", func() {
-			bridge.AssertResponseBody(t, `{"error":"phoneNumber.+phoneNumberHash.*","code":"INVALID_PROPERTIES"}`, body)
+			bridge.AssertResponseBody(t, `{"data":{"details":{"reason":"required","fields":\["phoneNumber","phoneNumberHash"\]}},"error":"phoneNumber.+phoneNumberHash.*","code":"INVALID_PROPERTIES"}`, body)
 		})
 	})
 	WHEN("we try to update phoneNumberHash without phoneNumber", func() {
@@ -208,7 +208,7 @@ func TestService_ModifyUser_Failure_PhoneNumberNoHash(t *testing.T) {
 			assert.Equal(t, 422, status)
 		})
 		IT("returns error code and message", func() {
-			bridge.AssertResponseBody(t, `{"error":"phoneNumber.+phoneNumberHash.*","code":"INVALID_PROPERTIES"}`, body)
+			bridge.AssertResponseBody(t, `{"data":{"details":{"reason":"required","fields":\["phoneNumber","phoneNumberHash"\]}},"error":"phoneNumber.+phoneNumberHash.*","code":"INVALID_PROPERTIES"}`, body)
 		})
 	})
 }
@@ -303,7 +303,7 @@ func TestService_ModifyUser_Failure_NoValues(t *testing.T) {
 			assert.Equal(t, 422, status)
 		})
 		IT("returns error code and description", func() {
-			bridge.AssertResponseBody(t, `{"error":"modify request without values","code":"INVALID_PROPERTIES"}`, body)
+			bridge.AssertResponseBody(t, `{"data":{"details":{"reason":"required"}},"error":"modify request without values","code":"INVALID_PROPERTIES"}`, body)
 		})
 	})
 }
@@ -398,7 +398,7 @@ func TestService_ModifyUser_Failure_InvalidUsername(t *testing.T) {
 				assert.Equal(t, 400, status, "for username %v", username)
 			})
 			IT("returns specific error code and some error message", func() {
-				bridge.AssertResponseBody(t, `{"error":".+","code":"INVALID_USERNAME"}`, body)
+				bridge.AssertResponseBody(t, `{"data":{"details":{"reason":"invalid","fields":\["username"\]}},"error":".+","code":"INVALID_USERNAME"}`, body)
 			})
 		})
 	}
@@ -432,7 +432,7 @@ func TestService_ModifyUser_Failure_DuplicatedByUsername(t *testing.T) {
 			assert.Equal(t, 409, status)
 		})
 		IT("returns specific error code and some error message", func() {
-			bridge.AssertResponseBody(t, `{"data":{"field":"username"},"error":".+","code":"CONFLICT_WITH_ANOTHER_USER"}`, body)
+			bridge.AssertResponseBody(t, `{"data":{"details":{"reason":"conflict","fields":\["username"\]},"field":"username"},"error":".+","code":"CONFLICT_WITH_ANOTHER_USER"}`, body)
 		})
 	})
 }
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupAdminDashboardRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("admin-dashboard", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetAdminDashboard)))
+}
+
+// GetAdminDashboard godoc
+//
+//	@Schemes
+//	@Description	Summarizes the current (UTC) day from the counters: the signups, the deletions, the total and the active users, the KYC funnel,
+//	@Description	the blocked sign ins and the availability of the providers (the data residency databases and the sign in links). Only for admins.
+//	@Description	If the sign in statistics can't be read, they're missing and the `signInLinks` provider is unavailable, instead of failing.
+//	@Tags			Statistics
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Success		200					{object}	AdminDashboard
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/admin-dashboard [GET].
+func (s *service) GetAdminDashboard( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetAdminDashboardArg, AdminDashboard],
+) (*server.Response[AdminDashboard], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	dashboard, err := s.usersRepository.GetAdminDashboard(ctx)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrap(err, "failed to get the admin dashboard"))
+	}
+	signIns, err := s.iceClient.GetSignInStatistics(ctx)
+	if err != nil {
+		log.Error(errors.Wrap(err, "failed to get the sign in statistics for the admin dashboard"))
+	}
+	dashboard.Providers = append(dashboard.Providers, &users.ProviderAvailability{
+		Name:      "signInLinks",
+		Available: err == nil && !signIns.SignInLinksThrottled,
+	})
+
+	return server.OK(&AdminDashboard{AdminDashboard: dashboard, SignInStatistics: signIns}), nil
+}
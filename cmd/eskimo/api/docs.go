@@ -20,9 +20,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/user-statistics/top-countries": {
+        "/admin-dashboard": {
             "get": {
-                "description": "Returns the paginated view of users per country.",
+                "description": "Summarizes the current (UTC) day from the counters: the signups, the deletions, the total and the active users, the KYC funnel,\nthe blocked sign ins and the availability of the providers (the data residency databases and the sign in links). Only for admins.\nIf the sign in statistics can't be read, they're missing and the ` + "`" + `signInLinks` + "`" + ` provider is unavailable, instead of failing.",
                 "consumes": [
                     "application/json"
                 ],
@@ -47,53 +47,60 @@ const docTemplate = `{
                         "description": "Insert your metadata token",
                         "name": "X-Account-Metadata",
                         "in": "header"
-                    },
-                    {
-                        "type": "string",
-                        "description": "a keyword to look for in all country codes or names",
-                        "name": "keyword",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit of elements to return. Defaults to 10",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of elements to skip before collecting elements to return",
-                        "name": "offset",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/users.CountryStatistics"
-                            }
+                            "$ref": "#/definitions/main.AdminDashboard"
                         }
                     },
-                    "400": {
-                        "description": "if validations fail",
+                    "401": {
+                        "description": "if not authorized",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "if not authorized",
+                    "403": {
+                        "description": "if not allowed",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "if syntax fails",
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/app-version-requirements": {
+            "get": {
+                "description": "Returns the earliest supported mobile app version of every platform and whether the older apps are forced to update.\nIf forced, every write call made from a device with an older app fails with ` + "`" + `UPDATE_REQUIRED` + "`" + `.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Devices"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.AppVersionRequirement"
+                            }
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -110,9 +117,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user-statistics/user-growth": {
+        "/auth/firebase-migrations/{userId}": {
             "get": {
-                "description": "Returns statistics about user growth.",
+                "description": "Returns whether the user, if registered with Firebase, was migrated to an ice ID, when and how. Only for the user itself or admins.",
                 "consumes": [
                     "application/json"
                 ],
@@ -120,7 +127,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Statistics"
+                    "Auth"
                 ],
                 "parameters": [
                     {
@@ -138,24 +145,19 @@ const docTemplate = `{
                         "name": "X-Account-Metadata",
                         "in": "header"
                     },
-                    {
-                        "type": "integer",
-                        "description": "number of days in the past to look for. Defaults to 3. Max is 90.",
-                        "name": "days",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Timezone in format +04:30 or -03:45",
-                        "name": "tz",
-                        "in": "query"
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/users.UserGrowthStatistics"
+                            "$ref": "#/definitions/emaillinkiceauth.FirebaseMigration"
                         }
                     },
                     "400": {
@@ -170,6 +172,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "422": {
                         "description": "if syntax fails",
                         "schema": {
@@ -191,9 +205,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user-views/username": {
+        "/auth/sign-in-attempts-per-ip": {
             "get": {
-                "description": "Returns public information about an user account based on an username, making sure the username is valid first.",
+                "description": "Returns the login attempts per IP, per hourly window, most recent windows first. Only for admins.",
                 "consumes": [
                     "application/json"
                 ],
@@ -201,7 +215,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Accounts"
+                    "Auth"
                 ],
                 "parameters": [
                     {
@@ -220,18 +234,32 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "description": "username of the user. It will validate it first",
-                        "name": "username",
-                        "in": "query",
-                        "required": true
+                        "type": "integer",
+                        "description": "Minimum login attempts of the IPs to return",
+                        "name": "minAttempts",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/users.UserProfile"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/emaillinkiceauth.IPSignInAttempts"
+                            }
                         }
                     },
                     "400": {
@@ -246,8 +274,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "if not found",
+                    "403": {
+                        "description": "if not allowed",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -273,9 +301,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
+        "/auth/sign-in-lockouts": {
             "get": {
-                "description": "Returns a list of user account based on the provided query parameters.",
+                "description": "Returns the login identities (email+device) that are currently blocked or that have wrong confirmation code attempts, blocked ones first. Only for admins.",
                 "consumes": [
                     "application/json"
                 ],
@@ -283,7 +311,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Accounts"
+                    "Auth"
                 ],
                 "parameters": [
                     {
@@ -301,13 +329,6 @@ const docTemplate = `{
                         "name": "X-Account-Metadata",
                         "in": "header"
                     },
-                    {
-                        "type": "string",
-                        "description": "A keyword to look for in the usernames",
-                        "name": "keyword",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "integer",
                         "description": "Limit of elements to return. Defaults to 10",
@@ -316,7 +337,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Elements to skip before starting to look for",
+                        "description": "Number of elements to skip before collecting elements to return",
                         "name": "offset",
                         "in": "query"
                     }
@@ -327,7 +348,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/users.MinimalUserProfile"
+                                "$ref": "#/definitions/emaillinkiceauth.SignInLockout"
                             }
                         }
                     },
@@ -343,6 +364,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "422": {
                         "description": "if syntax fails",
                         "schema": {
@@ -364,9 +391,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{userId}": {
+        "/client-config": {
             "get": {
-                "description": "Returns an user's account.",
+                "description": "Returns the values that differ between the deployments, i.e. the support email, the referral URL, the min app versions, the features and the KYC steps.\nIt's versioned with the ` + "`" + `ETag` + "`" + ` header: if it's sent back as ` + "`" + `If-None-Match` + "`" + `, it responds with 304, without a body, unless it changed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Devices"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.ClientConfig"
+                        }
+                    },
+                    "304": {
+                        "description": "if it didn't change since the ` + "`" + `ETag` + "`" + ` in ` + "`" + `If-None-Match` + "`" + `"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/distribution-eligibility-snapshots/{snapshotId}": {
+            "get": {
+                "description": "Returns a distribution eligibility snapshot. Once ` + "`" + `completedAt` + "`" + ` is set, its CSV parts, ` + "`" + `distribution_eligibility/as_of=\u003casOf\u003e/part-\u003c00000..parts-1\u003e.csv` + "`" + `, are exported. Only for admins.\n` + "`" + `hash` + "`" + ` is the hex sha256 of the records of all the parts, in order, without their header lines, so that the exported files can be audited.",
                 "consumes": [
                     "application/json"
                 ],
@@ -374,7 +435,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Accounts"
+                    "Users"
                 ],
                 "parameters": [
                     {
@@ -394,8 +455,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID of the user",
-                        "name": "userId",
+                        "description": "ID of the snapshot",
+                        "name": "snapshotId",
                         "in": "path",
                         "required": true
                     }
@@ -404,17 +465,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.User"
+                            "$ref": "#/definitions/users.DistributionEligibilitySnapshot"
                         }
                     },
-                    "400": {
-                        "description": "if validations fail",
+                    "401": {
+                        "description": "if not authorized",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "if not authorized",
+                    "403": {
+                        "description": "if not allowed",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -446,9 +507,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{userId}/referral-acquisition-history": {
+        "/duplicate-accounts": {
             "get": {
-                "description": "Returns the history of referral acquisition for the provided user id.",
+                "description": "Returns the pairs of accounts that probably belong to the same person, ordered by their score (0-100). Only for admins.",
                 "consumes": [
                     "application/json"
                 ],
@@ -456,7 +517,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Referrals"
+                    "Users"
                 ],
                 "parameters": [
                     {
@@ -475,16 +536,21 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "description": "ID of the user",
-                        "name": "userId",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Minimum score of the pairs to return",
+                        "name": "minScore",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Always is 5, cannot be changed due to DB schema",
-                        "name": "days",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
                         "in": "query"
                     }
                 ],
@@ -494,7 +560,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/users.ReferralAcquisition"
+                                "$ref": "#/definitions/users.DuplicateAccountCandidate"
                             }
                         }
                     },
@@ -537,9 +603,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{userId}/referrals": {
+        "/duplicate-accounts/{userId}": {
             "get": {
-                "description": "Returns the referrals of an user.",
+                "description": "Returns all the accounts that probably belong to the same person as the provided user. Only for admins.",
                 "consumes": [
                     "application/json"
                 ],
@@ -547,7 +613,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Referrals"
+                    "Users"
                 ],
                 "parameters": [
                     {
@@ -571,32 +637,16 @@ const docTemplate = `{
                         "name": "userId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Type of referrals: ` + "`" + `CONTACTS` + "`" + ` or ` + "`" + `T1` + "`" + ` or ` + "`" + `T2` + "`" + `",
-                        "name": "type",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit of elements to return. Defaults to 10",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of elements to skip before collecting elements to return",
-                        "name": "offset",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/users.Referrals"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.DuplicateAccountCandidate"
+                            }
                         }
                     },
                     "400": {
@@ -637,319 +687,4877 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "main.User": {
-            "type": "object",
-            "properties": {
-                "agendaPhoneNumberHashes": {
-                    "type": "string",
-                    "example": "Ef86A6021afCDe5673511376B2,Ef86A6021afCDe5673511376B2,Ef86A6021afCDe5673511376B2,Ef86A6021afCDe5673511376B2"
-                },
-                "blockchainAccountAddress": {
-                    "type": "string",
-                    "example": "0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
-                },
-                "checksum": {
-                    "type": "string",
-                    "example": "1232412415326543647657"
-                },
-                "city": {
-                    "type": "string",
-                    "example": "New York"
-                },
-                "clientData": {
-                    "$ref": "#/definitions/users.JSON"
-                },
-                "country": {
-                    "type": "string",
-                    "example": "US"
-                },
-                "createdAt": {
-                    "type": "string",
-                    "example": "2022-01-03T16:20:52.156534Z"
-                },
-                "email": {
-                    "type": "string",
+        },
+        "/errors": {
+            "get": {
+                "description": "Returns all the possible values of ` + "`" + `code` + "`" + ` in the error responses of both the read and the write APIs, with their description and whether the request can be retried.\nWhere applicable, the error responses also have machine-readable details in ` + "`" + `data.details` + "`" + `: the ` + "`" + `reason` + "`" + ` (required, invalid, tooShort, conflict or notAllowed) and the ` + "`" + `fields` + "`" + ` that caused it, so that the errors can be localized.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Errors"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/errorcatalog.Entry"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/global-values": {
+            "get": {
+                "description": "Returns the raw global counters (TOTAL_USERS_*, TOTAL_ACTIVE_USERS_*, etc) matching the provided key prefix and covering a period,\nby the date suffix of their keys (e.g. ` + "`" + `TOTAL_USERS_2022-01-22T16` + "`" + ` covers that hour, in UTC), that overlaps with the provided time range.\nThe ones without a date suffix, like TOTAL_USERS, are always returned. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "prefix of the keys to look for",
+                        "name": "keyPrefix",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 lower bound of the periods covered by the keys. Defaults to 24h before ` + "`" + `to` + "`" + `",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 upper bound of the periods covered by the keys. Defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.GlobalUnsigned"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/global-values/csv": {
+            "get": {
+                "description": "Same as GET /global-values, but the result is exported as CSV. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "prefix of the keys to look for",
+                        "name": "keyPrefix",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 lower bound of the periods covered by the keys. Defaults to 24h before ` + "`" + `to` + "`" + `",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 upper bound of the periods covered by the keys. Defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV with key,value,updatedAt columns"
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/maintenance-mode": {
+            "get": {
+                "description": "Returns the maintenance mode of both the read and the write APIs, as applied by this replica. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maintenance"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.MaintenanceMode"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pending-country-changes": {
+            "get": {
+                "description": "Returns the country changes waiting for an admin to approve or reject them, oldest first. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.CountryChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public-statistics": {
+            "get": {
+                "description": "Returns the total number of users and of countries they're from, for the marketing website. It doesn't require authorization.\nIt's heavily cached, both by the server and, via the ` + "`" + `Cache-Control` + "`" + ` header, by the CDNs. It's rate limited per IP.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.PublicStatistics"
+                        }
+                    },
+                    "429": {
+                        "description": "if rate limited; ` + "`" + `data.retryAfterSeconds` + "`" + `, like the ` + "`" + `Retry-After` + "`" + ` header, tells how long to wait",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/query-audit": {
+            "get": {
+                "description": "Returns how long the queries took on this replica, the slowest first, while the query audit is enabled. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maintenance"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.QueryAudit"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/referral-acquisition-histories": {
+            "get": {
+                "description": "Streams the referral acquisition histories of the provided users, or of all the users of the provided country, as newline delimited JSON: one users.UserReferralAcquisitionHistory per line, ordered by user ID. The users without referrals are skipped. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Referrals"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "IDs of the users, at most 1000. Either them or the country are required",
+                        "name": "userIds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country of the users. Either it or the userIds are required",
+                        "name": "country",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "newline delimited users.UserReferralAcquisitionHistory"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reported-users": {
+            "get": {
+                "description": "Returns the moderation queue: the users with pending reports, the ones flagged for review first, then the most reported ones. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether to return only the users flagged for review",
+                        "name": "flaggedOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.ReportedUser"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/squatted-usernames": {
+            "get": {
+                "description": "Returns the desirable usernames held by dormant users (never verified, never mined), the pending ones first, ordered by when they're released. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.SquattedUsername"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/underage-users": {
+            "get": {
+                "description": "Returns the users found to be younger than the minimum age of their country, as per their date of birth, the pending deletions first, ordered by when they're due. Only for admins.\nThey're blocked as soon as they're found and deleted after ` + "`" + `ageVerification.deletionDelay` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.UnderageUser"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-deletion-batches/{batchId}": {
+            "get": {
+                "description": "Returns the progress of an user deletion batch, with the users that couldn't be deleted. It's the final report once ` + "`" + `finishedAt` + "`" + ` is set. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the batch",
+                        "name": "batchId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.UserDeletionBatchReport"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-events": {
+            "get": {
+                "description": "Returns the recorded lifecycle events of the users (created, modified fields, deleted, kyc transitions), the latest first. Only for admins.\nThey're kept regardless of the retention of the message broker, so that they can be investigated later.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maintenance"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user to return the events of",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created",
+                            "modified",
+                            "deleted",
+                            "anonymized",
+                            "merged",
+                            "kycStepPassed",
+                            "kycStepBlocked"
+                        ],
+                        "type": "string",
+                        "description": "type of the events to return",
+                        "name": "eventType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 lower bound of the time of the events. Defaults to 7 days before ` + "`" + `to` + "`" + `",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 upper bound of the time of the events. Defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.UserEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-import-batches/{batchId}": {
+            "get": {
+                "description": "Returns the progress of an user import batch, with the lines that were invalid or couldn't be imported. It's the final report once ` + "`" + `finishedAt` + "`" + ` is set. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the batch",
+                        "name": "batchId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.UserImportBatchReport"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-statistics/email-domains": {
+            "get": {
+                "description": "Returns, per day (UTC), the email domains with the most signups, for fraud monitoring. Only for admins.\nA domain is ` + "`" + `anomalous` + "`" + ` in a day if its signups spiked, compared to its ` + "`" + `baseline` + "`" + `, i.e. its daily average of the previous days.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "Wed, 21 Oct 2015 07:28:00 GMT",
+                        "description": "Last-Modified value of a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of days in the past to look for, including the current one. Defaults to 7. Max is 90.",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of domains to return per day. Defaults to 10. Max is 100.",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.EmailDomainStatistics"
+                        }
+                    },
+                    "304": {
+                        "description": "if not modified since the provided If-Modified-Since"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-statistics/kyc-funnel": {
+            "get": {
+                "description": "Returns, per day (UTC) and per KYC step, how many users entered (attempted it for the first time), passed, failed and got blocked. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "Wed, 21 Oct 2015 07:28:00 GMT",
+                        "description": "Last-Modified value of a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of days in the past to look for, including the current one. Defaults to 7. Max is 90.",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.KYCFunnelStatistics"
+                        }
+                    },
+                    "304": {
+                        "description": "if not modified since the provided If-Modified-Since"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-statistics/top-countries": {
+            "get": {
+                "description": "Returns the paginated view of users per country, ranked among all of them.\nIf ` + "`" + `includeOwnCountry` + "`" + ` is true, the country of the authenticated user is flagged as ` + "`" + `own` + "`" + ` and, if it's not in the requested page, it's appended to it.\nIf ` + "`" + `paginated` + "`" + ` is true, they're returned as a ` + "`" + `Page` + "`" + `, with ` + "`" + `items` + "`" + `, ` + "`" + `total` + "`" + `, ` + "`" + `hasMore` + "`" + ` and ` + "`" + `nextCursor` + "`" + `, rather than as just the list.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "Wed, 21 Oct 2015 07:28:00 GMT",
+                        "description": "Last-Modified value of a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "a keyword to look for in all country codes or names",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "if true, it includes the country of the authenticated user too",
+                        "name": "includeOwnCountry",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "if true, it returns a ` + "`" + `Page` + "`" + ` rather than just the list",
+                        "name": "paginated",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.CountryStatistics"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "if not modified since the provided If-Modified-Since"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-statistics/user-growth": {
+            "get": {
+                "description": "Returns statistics about user growth.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "Wed, 21 Oct 2015 07:28:00 GMT",
+                        "description": "Last-Modified value of a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of days in the past to look for. Defaults to 3. Max is 90.",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Timezone in format +04:30 or -03:45, between -12:00 and +14:00. Invalid values are rejected with 400",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "if true, it also returns the distinct users active in the last 7 (WAU) and 30 (MAU) days",
+                        "name": "rollingActiveUsers",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.UserGrowthStatistics"
+                        }
+                    },
+                    "304": {
+                        "description": "if not modified since the provided If-Modified-Since"
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-views/username": {
+            "get": {
+                "description": "Returns public information about an user account based on an username, making sure the username is valid first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "username of the user. It will validate it first",
+                        "name": "username",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.UserProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Returns a list of user account based on the provided query parameters.\nWhen searching by ` + "`" + `username` + "`" + `, each result has a ` + "`" + `searchMatch` + "`" + `, with where the keyword matched, so it can be highlighted.\nIf the search index is enabled, the usernames can also match approximately (e.g. with typos), in which case there's no ` + "`" + `searchMatch` + "`" + `.\nIf ` + "`" + `paginated` + "`" + ` is true, they're returned as a ` + "`" + `Page` + "`" + `, with ` + "`" + `items` + "`" + `, ` + "`" + `hasMore` + "`" + ` and ` + "`" + `nextCursor` + "`" + `, rather than as just the list.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "A keyword to look for in the usernames (or in the emails/phone numbers, see ` + "`" + `searchBy` + "`" + `)",
+                        "name": "keyword",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "username",
+                            "email",
+                            "phoneNumber"
+                        ],
+                        "type": "string",
+                        "description": "What to search by. Defaults to ` + "`" + `username` + "`" + `. ` + "`" + `email` + "`" + ` and ` + "`" + `phoneNumber` + "`" + ` (which matches the phone number hashes as well) are only for admins",
+                        "name": "searchBy",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "prefix"
+                        ],
+                        "type": "string",
+                        "description": "How to match the keyword, if ` + "`" + `searchBy` + "`" + ` is not ` + "`" + `username` + "`" + `. Defaults to ` + "`" + `exact` + "`" + `",
+                        "name": "searchMode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Why the search is needed (for ex the support ticket). Required if ` + "`" + `searchBy` + "`" + ` is not ` + "`" + `username` + "`" + `, because those searches are audited",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Elements to skip before starting to look for",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "if true, it returns a ` + "`" + `Page` + "`" + ` rather than just the list",
+                        "name": "paginated",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.MinimalUserProfile"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if searching by something other than ` + "`" + `username` + "`" + ` without being an admin",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}": {
+            "get": {
+                "description": "Returns an user's account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.User"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/badges": {
+            "get": {
+                "description": "Returns the badges awarded to an user, oldest first. None are returned for the other users, if the user hid them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.Badge"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/blockchain-addresses": {
+            "get": {
+                "description": "Returns the blockchain addresses whose ownership was proven by the user, the earliest added first. Only for the user itself.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.BlockchainAddress"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/blocks": {
+            "get": {
+                "description": "Returns the users blocked by the user, the most recently blocked first. Only for the user itself.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.UserBlock"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/changes": {
+            "get": {
+                "description": "Long-polls the changes of the user's own profile, so that it doesn't need to be fetched again every time the app is foregrounded.\nIt waits until the checksum of the profile differs from ` + "`" + `sinceChecksum` + "`" + `, for at most the configured timeout, and responds with 204 if it doesn't.\nOtherwise, it returns only the fields that changed since the last response of this endpoint, if that's the ` + "`" + `sinceChecksum` + "`" + ` version,\nor all of them, with ` + "`" + `full` + "`" + ` set, if it's not (e.g. the first call, without ` + "`" + `sinceChecksum` + "`" + `).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the checksum of the last version of the profile known by the client",
+                        "name": "sinceChecksum",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.ProfileChanges"
+                        }
+                    },
+                    "204": {
+                        "description": "if the profile didn't change"
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/distribution-eligibility": {
+            "get": {
+                "description": "Returns the distribution eligibility of an user as of ` + "`" + `asOf` + "`" + `, as the snapshots compute it, with the currently configured criteria. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 point in time. Defaults to now",
+                        "name": "asOf",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.DistributionEligibility"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if user not found or created after asOf",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/level": {
+            "get": {
+                "description": "Returns the level of an user and, only for the user itself, its progress towards the next one.\nThe level isn't returned for the other users, if the user hid it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.UserLevel"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/mutual": {
+            "get": {
+                "description": "Returns the connections that the authenticated user and another one have in common: their referrer, if it's the same one,\nand their mutual contacts, i.e. the users that both of them have in their agendas and that have both of them in theirs.\nNothing is returned if any of them blocked the other, and the users blocked by, or that blocked, any of them are left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Referrals"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the other user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.MutualConnections"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "if not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails or the other user is the authenticated one",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/referral-acquisition-history": {
+            "get": {
+                "description": "Returns the history of referral acquisition for the provided user id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Referrals"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Always is 5, cannot be changed due to DB schema",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.ReferralAcquisition"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/referral-invitations": {
+            "get": {
+                "description": "Returns the referral invitations sent by the user, the most recent first, with the ones whose invitee signed up as ` + "`" + `joined` + "`" + `.\nOnly for the user itself.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Referrals"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.ReferralInvitation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/referrals": {
+            "get": {
+                "description": "Returns the referrals of an user.\nIf ` + "`" + `paginated` + "`" + ` is true, they have ` + "`" + `hasMore` + "`" + ` and ` + "`" + `nextCursor` + "`" + ` too.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Referrals"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of referrals: ` + "`" + `CONTACTS` + "`" + ` or ` + "`" + `T1` + "`" + ` or ` + "`" + `T2` + "`" + `",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "if true, it returns whether there are more too",
+                        "name": "paginated",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.Referrals"
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/referrals/pingable": {
+            "get": {
+                "description": "Returns the referrals (T1 and the referrer) that can be pinged now: they're not mining and they weren't pinged during the cooldown.\nIts ` + "`" + `selectAllToken` + "`" + ` pings all of them, not just the ones in this page, via ` + "`" + `POST /users/{userId}/referral-pings` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Referrals"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.PingableReferrals"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/reports": {
+            "get": {
+                "description": "Returns the reports of an user, the pending ones first, then the most recent ones. Only for admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\u003cAdd metadata token here\u003e",
+                        "description": "Insert your metadata token",
+                        "name": "X-Account-Metadata",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the reported user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of elements to return. Defaults to 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of elements to skip before collecting elements to return",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.UserReport"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "if validations fail",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "if not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "if not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "if syntax fails",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "if request times out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "devicemetadata.Platform": {
+            "type": "string",
+            "enum": [
+                "android",
+                "ios"
+            ],
+            "x-enum-varnames": [
+                "AndroidPlatform",
+                "IOSPlatform"
+            ]
+        },
+        "emaillinkiceauth.FirebaseMigration": {
+            "type": "object",
+            "properties": {
+                "deviceUniqueId": {
+                    "type": "string",
+                    "example": "6FB988F3-36F4-433D-9C7C-555887E57EB2"
+                },
+                "firebaseId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "migrated": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "migratedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "registeredWithProvider": {
+                    "type": "string",
+                    "example": "firebase"
+                },
+                "source": {
+                    "description": "Source is ` + "`" + `signIn` + "`" + ` if the user was migrated by signing in with an email link, ` + "`" + `batch` + "`" + ` if by the batch migration\nor ` + "`" + `native` + "`" + ` if the user registered with ice in the first place.",
+                    "type": "string",
+                    "enum": [
+                        "signIn",
+                        "batch",
+                        "native"
+                    ],
+                    "example": "signIn"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "emaillinkiceauth.IPSignInAttempts": {
+            "type": "object",
+            "properties": {
+                "ip": {
+                    "type": "string",
+                    "example": "1.1.1.1"
+                },
+                "loginAttempts": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "maxAttempts": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "windowStartedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:00:00Z"
+                }
+            }
+        },
+        "emaillinkiceauth.SignInLockout": {
+            "type": "object",
+            "properties": {
+                "blockedUntil": {
+                    "type": "string",
+                    "example": "2022-01-03T16:30:52.156534Z"
+                },
+                "confirmationCodeWrongAttemptsCount": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "deviceUniqueId": {
+                    "type": "string",
+                    "example": "6FB988F3-36F4-433D-9C7C-555887E57EB2"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "someone1@example.com"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "errorcatalog.Entry": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "USER_NOT_FOUND"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "The user was not found."
+                },
+                "httpStatuses": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        404
+                    ]
+                },
+                "retry": {
+                    "enum": [
+                        "never",
+                        "afterFix",
+                        "afterRefresh",
+                        "withBackoff"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/errorcatalog.RetryPolicy"
+                        }
+                    ],
+                    "example": "never"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "enum": [
+                            "eskimo",
+                            "eskimo-hut"
+                        ],
+                        "$ref": "#/definitions/errorcatalog.Service"
+                    },
+                    "example": [
+                        "eskimo",
+                        "eskimo-hut"
+                    ]
+                }
+            }
+        },
+        "errorcatalog.RetryPolicy": {
+            "type": "string",
+            "enum": [
+                "never",
+                "afterFix",
+                "afterRefresh",
+                "withBackoff"
+            ],
+            "x-enum-varnames": [
+                "NeverRetryPolicy",
+                "AfterFixRetryPolicy",
+                "AfterRefreshRetryPolicy",
+                "WithBackoffRetryPolicy"
+            ]
+        },
+        "errorcatalog.Service": {
+            "type": "string",
+            "enum": [
+                "eskimo",
+                "eskimo-hut"
+            ],
+            "x-enum-varnames": [
+                "EskimoService",
+                "EskimoHutService"
+            ]
+        },
+        "main.AdminDashboard": {
+            "type": "object",
+            "properties": {
+                "activeUsers": {
+                    "type": "integer",
+                    "example": 3000000
+                },
+                "blockedSignIns": {
+                    "description": "BlockedSignIns are the login identities (email+device) that are locked out right now.",
+                    "type": "integer",
+                    "example": 12
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2022-01-03T00:00:00Z"
+                },
+                "deletions": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "kycSteps": {
+                    "description": "KYCSteps are today's KYC funnel, per step.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.KYCStepFunnel"
+                    }
+                },
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.ProviderAvailability"
+                    }
+                },
+                "signInLinksThrottled": {
+                    "description": "SignInLinksThrottled tells whether the global sign in links throttle is exhausted, so no sign in link is sent until its window ends.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "signups": {
+                    "description": "Signups and Deletions are the users created and deleted today, whether they ever mined or not.",
+                    "type": "integer",
+                    "example": 1200
+                },
+                "totalUsers": {
+                    "description": "TotalUsers and ActiveUsers are as per the user growth statistics, so they count only the users that mined after the human verification.",
+                    "type": "integer",
+                    "example": 12121212
+                }
+            }
+        },
+        "main.ClientConfig": {
+            "type": "object",
+            "properties": {
+                "appVersionRequirements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.AppVersionRequirement"
+                    }
+                },
+                "features": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "kycSteps": {
+                    "description": "KYCSteps are the KYC steps, in the order the users go through them.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.KYCStep"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3,
+                        4
+                    ]
+                },
+                "referralUrlTemplate": {
+                    "description": "ReferralURLTemplate is the referral URL of an user, after replacing ` + "`" + `{username}` + "`" + ` with its username.",
+                    "type": "string",
+                    "example": "https://ice.io/@{username}"
+                },
+                "supportEmail": {
+                    "type": "string",
+                    "example": "support@ice.io"
+                }
+            }
+        },
+        "main.Referrals": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "hasMore": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "nextCursor": {
+                    "description": "The ` + "`" + `offset` + "`" + ` of the next page. Empty if there are no more.",
+                    "type": "string",
+                    "example": "20"
+                },
+                "referrals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.MinimalUserProfile"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 11
+                }
+            }
+        },
+        "main.User": {
+            "type": "object",
+            "properties": {
+                "agendaPhoneNumberHashes": {
+                    "type": "string",
+                    "example": "Ef86A6021afCDe5673511376B2,Ef86A6021afCDe5673511376B2,Ef86A6021afCDe5673511376B2,Ef86A6021afCDe5673511376B2"
+                },
+                "blockchainAccountAddress": {
+                    "type": "string",
+                    "example": "0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "checksum": {
+                    "type": "string",
+                    "example": "1232412415326543647657"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "New York"
+                },
+                "clientData": {
+                    "$ref": "#/definitions/users.JSON"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "US"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "jdoe@gmail.com"
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "John"
+                },
+                "globalRank": {
+                    "description": "The position of the user among all the ones with referrals, by ` + "`" + `globalRank.metric` + "`" + `, as of the last time they were ranked.\nThe ones with the same metric share it. Hidden from the other users, if the user hid it.",
+                    "type": "integer",
+                    "example": 37
+                },
+                "hiddenProfileElements": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "globalRank",
+                            "referralCount",
+                            "level",
+                            "role",
+                            "badges",
+                            "profileViews"
+                        ]
+                    },
+                    "example": [
+                        "level"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "kycStepBlocked": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.KYCStep"
+                        }
+                    ],
+                    "example": 0
+                },
+                "kycStepPassed": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.KYCStep"
+                        }
+                    ],
+                    "example": 0
+                },
+                "kycStepsCreatedAt": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2022-01-03T16:20:52.156534Z"
+                    ]
+                },
+                "kycStepsLastUpdatedAt": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2022-01-03T16:20:52.156534Z"
+                    ]
+                },
+                "kycTimeline": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.KYCStepProgress"
+                    }
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "level": {
+                    "description": "Hidden from the other users, if the user hid it. See UserLevel.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "miningBlockchainAccountAddress": {
+                    "type": "string",
+                    "example": "0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "pendingCountryChange": {
+                    "$ref": "#/definitions/users.CountryChange"
+                },
+                "phoneNumber": {
+                    "type": "string",
+                    "example": "+12099216581"
+                },
+                "profileCompleteness": {
+                    "description": "Percentage of the profile that's complete. Only for the user itself.",
+                    "type": "integer",
+                    "example": 75
+                },
+                "profilePictureUrl": {
+                    "type": "string",
+                    "example": "https://somecdn.com/p1.jpg"
+                },
+                "profileViews": {
+                    "description": "Only for the user itself, unless it hid them.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.ProfileViews"
+                        }
+                    ]
+                },
+                "referredBy": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "repeatableKYCSteps": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "residency": {
+                    "enum": [
+                        "EU",
+                        "US",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.Residency"
+                        }
+                    ],
+                    "example": "EU"
+                },
+                "t1ReferralCount": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "t2ReferralCount": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "jdoe"
+                },
+                "verified": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "server.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "SOMETHING_NOT_FOUND"
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "error": {
+                    "type": "string",
+                    "example": "something is missing"
+                }
+            }
+        },
+        "users.AppVersionRequirement": {
+            "type": "object",
+            "properties": {
+                "forceUpdate": {
+                    "description": "ForceUpdate makes every write call fail for the devices with an app version older than ` + "`" + `MinVersion` + "`" + `.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "minVersion": {
+                    "description": "MinVersion is the earliest supported version. Devices with an older one can't update their metadata.",
+                    "type": "string",
+                    "example": "v1.2.3"
+                },
+                "platform": {
+                    "enum": [
+                        "android",
+                        "ios"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/devicemetadata.Platform"
+                        }
+                    ],
+                    "example": "android"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "updatedBy": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "users.Badge": {
+            "type": "object",
+            "properties": {
+                "awardedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Invited 10 users"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "inviter"
+                }
+            }
+        },
+        "users.BlockchainAddress": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "The canonical form of the address: EIP-55 checksummed for the EVM chains.",
+                    "type": "string",
+                    "example": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+                },
+                "chain": {
+                    "enum": [
+                        "evm",
+                        "solana"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.BlockchainChain"
+                        }
+                    ],
+                    "example": "evm"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "verifiedAt": {
+                    "description": "The last time the ownership was proven. Answering a new challenge for an address the user has already proves it again.",
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                }
+            }
+        },
+        "users.BlockchainChain": {
+            "type": "string",
+            "enum": [
+                "evm",
+                "solana"
+            ],
+            "x-enum-varnames": [
+                "EVMChain",
+                "SolanaChain"
+            ]
+        },
+        "users.CountryChange": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "decidedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "decidedBy": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3"
+                },
+                "fromCountry": {
+                    "type": "string",
+                    "example": "US"
+                },
+                "status": {
+                    "enum": [
+                        "applied",
+                        "verified",
+                        "pending",
+                        "approved",
+                        "rejected"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.CountryChangeStatus"
+                        }
+                    ],
+                    "example": "pending"
+                },
+                "toCity": {
+                    "type": "string",
+                    "example": "Bucharest"
+                },
+                "toCountry": {
+                    "type": "string",
+                    "example": "RO"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "users.CountryChangeStatus": {
+            "type": "string",
+            "enum": [
+                "applied",
+                "verified",
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "AppliedCountryChangeStatus",
+                "VerifiedCountryChangeStatus",
+                "PendingCountryChangeStatus",
+                "ApprovedCountryChangeStatus",
+                "RejectedCountryChangeStatus"
+            ]
+        },
+        "users.CountryStatistics": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "description": "ISO 3166 country code.",
+                    "type": "string",
+                    "example": "US"
+                },
+                "own": {
+                    "description": "Whether it's the country of the authenticated user.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "percentile": {
+                    "description": "The percentage of the other countries that have fewer users.",
+                    "type": "number",
+                    "example": 85.25
+                },
+                "rank": {
+                    "description": "The position of the country among all of them, by their users. The ones with the same number of users share it.",
+                    "type": "integer",
+                    "example": 37
+                },
+                "userCount": {
+                    "type": "integer",
+                    "example": 12121212
+                }
+            }
+        },
+        "users.DistributionEligibility": {
+            "type": "object",
+            "properties": {
+                "asOf": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "banned": {
+                    "description": "If the user is blocked from the KYC or as underage.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "blockchainAddress": {
+                    "description": "The first address the user verified, as ` + "`" + `\u003cchain\u003e:\u003caddress\u003e` + "`" + `, if any.",
+                    "type": "string",
+                    "example": "evm:0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "US"
+                },
+                "countryRestricted": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "eligible": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "kycStepPassed": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.KYCStep"
+                        }
+                    ],
+                    "example": 2
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "users.DistributionEligibilitySnapshot": {
+            "type": "object",
+            "properties": {
+                "asOf": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "completedAt": {
+                    "type": "string",
+                    "example": "2022-01-04T16:30:52.156534Z"
+                },
+                "eligibleUsers": {
+                    "type": "integer",
+                    "example": 800
+                },
+                "hash": {
+                    "description": "The hex sha256 of the CSV records of all the parts, in order, without their headers, to audit the exported files.",
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "b0a0c1b4-9f3d-4a5e-8c41-2b8f1e0d7a11"
+                },
+                "parts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "requestedAt": {
+                    "type": "string",
+                    "example": "2022-01-04T16:20:52.156534Z"
+                },
+                "requestedBy": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "requiredKycStep": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.KYCStep"
+                        }
+                    ],
+                    "example": 2
+                },
+                "restrictedCountries": {
+                    "description": "The configured criteria, as of when the snapshot was requested.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "US"
+                    ]
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 1000
+                }
+            }
+        },
+        "users.DryRunPreview": {
+            "type": "object",
+            "properties": {
+                "affectedRecords": {
+                    "description": "How many records of each kind would be changed. For example, ` + "`" + `users` + "`" + `, ` + "`" + `t1Referrals` + "`" + ` or ` + "`" + `devices` + "`" + `.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "events": {
+                    "description": "How many user snapshots of each event, or ` + "`" + `tombstoned` + "`" + ` messages, would be sent.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "skipped": {
+                    "description": "The records that would be skipped, and why. At most the first 1000.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.DryRunSkip"
+                    }
+                },
+                "userIds": {
+                    "description": "The users that would be changed. At most the first 1000.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "users.DryRunSkip": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "not found"
+                },
+                "record": {
+                    "description": "The ID of the user or, for imports, the line of the file.",
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "users.DuplicateAccountCandidate": {
+            "type": "object",
+            "properties": {
+                "agendaOverlap": {
+                    "type": "number",
+                    "example": 0.75
+                },
+                "detectedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "duplicateUserId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3"
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 65
+                },
+                "signals": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "device",
+                            "phoneNumber",
+                            "ip",
+                            "agenda",
+                            "emailDomain"
+                        ]
+                    },
+                    "example": [
+                        "device",
+                        "ip"
+                    ]
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "users.EmailDomainSignups": {
+            "type": "object",
+            "properties": {
+                "anomalous": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "baseline": {
+                    "type": "number",
+                    "example": 35.5
+                },
+                "domain": {
+                    "type": "string",
+                    "example": "ice.io"
+                },
+                "signups": {
+                    "type": "integer",
+                    "example": 1200
+                }
+            }
+        },
+        "users.EmailDomainStatistics": {
+            "type": "object",
+            "properties": {
+                "timeSeries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.EmailDomainsDataPoint"
+                    }
+                }
+            }
+        },
+        "users.EmailDomainsDataPoint": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2022-01-03T00:00:00Z"
+                },
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.EmailDomainSignups"
+                    }
+                }
+            }
+        },
+        "users.GlobalUnsigned": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "TOTAL_USERS_2022-01-22:16"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "value": {
+                    "type": "integer",
+                    "example": 123676
+                }
+            }
+        },
+        "users.JSON": {
+            "type": "object",
+            "additionalProperties": {}
+        },
+        "users.KYCFunnelDataPoint": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2022-01-03T00:00:00Z"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.KYCStepFunnel"
+                    }
+                }
+            }
+        },
+        "users.KYCFunnelStatistics": {
+            "type": "object",
+            "properties": {
+                "steps": {
+                    "description": "The totals of all the days, per step.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.KYCStepFunnel"
+                    }
+                },
+                "timeSeries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.KYCFunnelDataPoint"
+                    }
+                }
+            }
+        },
+        "users.KYCStep": {
+            "type": "integer",
+            "enum": [
+                0,
+                1,
+                2,
+                3,
+                4,
+                5,
+                6,
+                7,
+                8,
+                9,
+                10
+            ],
+            "x-enum-varnames": [
+                "NoneKYCStep",
+                "FacialRecognitionKYCStep",
+                "LivenessDetectionKYCStep",
+                "Social1KYCStep",
+                "QuizKYCStep",
+                "Social2KYCStep",
+                "Social3KYCStep",
+                "Social4KYCStep",
+                "Social5KYCStep",
+                "Social6KYCStep",
+                "Social7KYCStep"
+            ]
+        },
+        "users.KYCStepFunnel": {
+            "type": "object",
+            "properties": {
+                "blocked": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "entered": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "passed": {
+                    "type": "integer",
+                    "example": 800
+                },
+                "step": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.KYCStep"
+                        }
+                    ],
+                    "example": 1
+                }
+            }
+        },
+        "users.KYCStepProgress": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "blocked": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "lastUpdatedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "maxAttempts": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "nextAllowedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "passed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "remainingAttempts": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "step": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.KYCStep"
+                        }
+                    ],
+                    "example": 1
+                }
+            }
+        },
+        "users.LevelCriterion": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "name": {
+                    "enum": [
+                        "t1Referrals",
+                        "kycStepPassed",
+                        "miningSessions"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.LevelCriterionName"
+                        }
+                    ],
+                    "example": "t1Referrals"
+                },
+                "required": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "users.LevelCriterionName": {
+            "type": "string",
+            "enum": [
+                "t1Referrals",
+                "kycStepPassed",
+                "miningSessions"
+            ],
+            "x-enum-varnames": [
+                "T1ReferralsLevelCriterion",
+                "KYCStepPassedLevelCriterion",
+                "MiningSessionsLevelCriterion"
+            ]
+        },
+        "users.LevelProgress": {
+            "type": "object",
+            "properties": {
+                "criteria": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.LevelCriterion"
+                    }
+                },
+                "level": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "percentage": {
+                    "description": "The average completion of the criteria, each one capped at 100.",
+                    "type": "integer",
+                    "example": 40
+                }
+            }
+        },
+        "users.MaintenanceMode": {
+            "type": "object",
+            "properties": {
+                "allowlist": {
+                    "description": "The users whose calls are still served.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                    ]
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "eta": {
+                    "description": "Optional. When the maintenance is expected to end.",
+                    "type": "string",
+                    "example": "2022-01-03T18:20:52.156534Z"
+                },
+                "messages": {
+                    "description": "The message shown to the users, per language. ` + "`" + `en` + "`" + ` is used for the languages without one.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "en": "We'll be back soon!"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "updatedBy": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "users.MinimalUserProfile": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "city": {
+                    "type": "string",
+                    "example": "New York"
+                },
+                "contactName": {
+                    "description": "The name the user has for the contact in its agenda. Set only for the CONTACTS referrals, if the user stored it. See AgendaContactName.",
+                    "type": "string",
+                    "example": "Alice"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "US"
+                },
+                "email": {
+                    "type": "string",
                     "example": "jdoe@gmail.com"
                 },
-                "firstName": {
+                "id": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "phoneNumber": {
+                    "type": "string",
+                    "example": "+12099216581"
+                },
+                "pinged": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "profilePictureUrl": {
+                    "type": "string",
+                    "example": "https://somecdn.com/p1.jpg"
+                },
+                "referralType": {
+                    "enum": [
+                        "CONTACTS",
+                        "T0",
+                        "T1",
+                        "T2"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.ReferralType"
+                        }
+                    ],
+                    "example": "T1"
+                },
+                "searchMatch": {
+                    "description": "Set only for the results of GetUsers.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.SearchMatch"
+                        }
+                    ]
+                },
+                "username": {
+                    "type": "string",
+                    "example": "jdoe"
+                },
+                "verified": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "users.MutualConnections": {
+            "type": "object",
+            "properties": {
+                "contacts": {
+                    "description": "The users that both of them have in their agendas and that have both of them in theirs, at most maxMutualContacts.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.PublicUserInformation"
+                    }
+                },
+                "sharedReferrer": {
+                    "description": "Set if both of them were referred by the same user.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.PublicUserInformation"
+                        }
+                    ]
+                },
+                "totalContacts": {
+                    "description": "The number of all of them, not just the returned ones.",
+                    "type": "integer",
+                    "example": 25
+                }
+            }
+        },
+        "users.PingableReferrals": {
+            "type": "object",
+            "properties": {
+                "referrals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.MinimalUserProfile"
+                    }
+                },
+                "selectAllToken": {
+                    "description": "SelectAllToken pings all of them, not just the ones in this page, without listing them. See ReferralPing.",
+                    "type": "string",
+                    "example": "ZGlkOmV0aHI6MHg0QjczQzU4MzcwQUVmY0VmODZBNjAyMUFmQ0RlNTY3MzUxMTM3NkIyOjE3MDAwMDAwMDAwMDAwMDAwMDA"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "users.ProfileChanges": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "checksum": {
+                    "type": "string",
+                    "example": "1232412415326543647657"
+                },
+                "full": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "phoneNumber"
+                    ]
+                }
+            }
+        },
+        "users.ProfileViews": {
+            "type": "object",
+            "properties": {
+                "last30Days": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "last7Days": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "today": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "users.ProviderAvailability": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "database/EU"
+                }
+            }
+        },
+        "users.PublicStatistics": {
+            "type": "object",
+            "properties": {
+                "totalCountries": {
+                    "type": "integer",
+                    "example": 180
+                },
+                "totalUsers": {
+                    "type": "integer",
+                    "example": 12121212
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                }
+            }
+        },
+        "users.PublicUserInformation": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "profilePictureUrl": {
+                    "type": "string",
+                    "example": "https://somecdn.com/p1.jpg"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "jdoe"
+                }
+            }
+        },
+        "users.QueryAudit": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "queries": {
+                    "description": "The slowest queries first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.QueryStatistics"
+                    }
+                },
+                "slowQueryThreshold": {
+                    "description": "The queries slower than it are logged and, if enabled, explained.",
+                    "type": "string",
+                    "example": "500ms"
+                },
+                "startedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                }
+            }
+        },
+        "users.QueryStatistics": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "histogram": {
+                    "description": "How many executions took at most each duration. The ones slower than all of them are counted in ` + "`" + `+Inf` + "`" + `.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "10ms": 3
+                    }
+                },
+                "plan": {
+                    "description": "The EXPLAIN output of the slowest execution, if it was slow and the slow queries are explained.",
+                    "type": "string",
+                    "example": "Index Scan using users_pkey on users"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "SELECT * FROM users WHERE id = $1"
+                },
+                "slowestArgs": {
+                    "description": "The arguments of the slowest execution. Only the numbers, the booleans and the dates are kept, the rest are redacted.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "***"
+                    ]
+                },
+                "slowestAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "slowestMillis": {
+                    "type": "number",
+                    "example": 7.2
+                },
+                "totalMillis": {
+                    "type": "number",
+                    "example": 12.5
+                }
+            }
+        },
+        "users.ReferralAcquisition": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2022-01-03"
+                },
+                "t1": {
+                    "type": "integer",
+                    "example": 22
+                },
+                "t2": {
+                    "type": "integer",
+                    "example": 13
+                }
+            }
+        },
+        "users.ReferralInvitation": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "enum": [
+                        "email",
+                        "sms"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.ReferralInvitationChannel"
+                        }
+                    ],
+                    "example": "email"
+                },
+                "contactHint": {
+                    "description": "The masked email or phone number, for the user to recognize it.",
+                    "type": "string",
+                    "example": "j***@gmail.com"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "inviteeUserId": {
                     "type": "string",
-                    "example": "John"
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3"
                 },
-                "hiddenProfileElements": {
+                "joinedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "status": {
+                    "enum": [
+                        "sent",
+                        "joined"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.ReferralInvitationStatus"
+                        }
+                    ],
+                    "example": "sent"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "users.ReferralInvitationChannel": {
+            "type": "string",
+            "enum": [
+                "email",
+                "sms"
+            ],
+            "x-enum-varnames": [
+                "EmailChannel",
+                "SMSChannel"
+            ]
+        },
+        "users.ReferralInvitationStatus": {
+            "type": "string",
+            "enum": [
+                "sent",
+                "joined",
+                "alreadyInvited",
+                "unsubscribed",
+                "noConsent",
+                "quotaExceeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "SentReferralInvitationStatus",
+                "JoinedReferralInvitationStatus",
+                "AlreadyInvitedReferralInvitationStatus",
+                "UnsubscribedReferralInvitationStatus",
+                "NoConsentReferralInvitationStatus",
+                "QuotaExceededReferralInvitationStatus",
+                "FailedReferralInvitationStatus"
+            ]
+        },
+        "users.ReferralType": {
+            "type": "string",
+            "enum": [
+                "CONTACTS",
+                "T1",
+                "T2",
+                "TEAM"
+            ],
+            "x-enum-varnames": [
+                "ContactsReferrals",
+                "Tier1Referrals",
+                "Tier2Referrals",
+                "TeamReferrals"
+            ]
+        },
+        "users.ReportedUser": {
+            "type": "object",
+            "properties": {
+                "firstReportedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "flaggedForReview": {
+                    "description": "Whether it has at least ` + "`" + `userReports.flagThreshold` + "`" + ` pending reports, so it needs a review.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "lastReportedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "pendingReports": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "reasons": {
+                    "description": "The reasons of the pending reports, without duplicates.",
                     "type": "array",
                     "items": {
-                        "type": "string",
-                        "enum": [
-                            "globalRank",
-                            "referralCount",
-                            "level",
-                            "role",
-                            "badges"
-                        ]
+                        "$ref": "#/definitions/users.UserReportReason"
                     },
                     "example": [
-                        "level"
+                        "spam",
+                        "scam"
                     ]
                 },
-                "id": {
-                    "type": "string",
-                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
-                },
-                "kycStepBlocked": {
+                "resolution": {
+                    "description": "Set only on the topic, when the reports are resolved.",
+                    "enum": [
+                        "actioned",
+                        "dismissed"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/users.KYCStep"
+                            "$ref": "#/definitions/users.UserReportStatus"
                         }
                     ],
-                    "example": 0
+                    "example": "actioned"
                 },
-                "kycStepPassed": {
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "users.Residency": {
+            "type": "string",
+            "enum": [
+                "EU",
+                "US",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "EUResidency",
+                "USResidency",
+                "OtherResidency"
+            ]
+        },
+        "users.RollingActiveUsers": {
+            "type": "object",
+            "properties": {
+                "monthly": {
+                    "type": "integer",
+                    "example": 333
+                },
+                "weekly": {
+                    "type": "integer",
+                    "example": 111
+                }
+            }
+        },
+        "users.SearchMatch": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "field": {
+                    "enum": [
+                        "username"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/users.KYCStep"
+                            "$ref": "#/definitions/users.UserSearchField"
                         }
                     ],
-                    "example": 0
-                },
-                "kycStepsCreatedAt": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "2022-01-03T16:20:52.156534Z"
-                    ]
-                },
-                "kycStepsLastUpdatedAt": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "2022-01-03T16:20:52.156534Z"
-                    ]
+                    "example": "username"
                 },
-                "language": {
+                "start": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "users.SquattedUsername": {
+            "type": "object",
+            "properties": {
+                "exemptedAt": {
                     "type": "string",
-                    "example": "en"
+                    "example": "2022-01-05T16:20:52.156534Z"
                 },
-                "lastName": {
+                "exemptedBy": {
                     "type": "string",
-                    "example": "Doe"
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3"
                 },
-                "miningBlockchainAccountAddress": {
+                "expiresAt": {
+                    "description": "When the username is released, unless the user becomes active.",
                     "type": "string",
-                    "example": "0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                    "example": "2022-01-10T16:20:52.156534Z"
                 },
-                "phoneNumber": {
+                "flaggedAt": {
                     "type": "string",
-                    "example": "+12099216581"
+                    "example": "2022-01-03T16:20:52.156534Z"
                 },
-                "profilePictureUrl": {
+                "releasedAt": {
                     "type": "string",
-                    "example": "https://somecdn.com/p1.jpg"
+                    "example": "2022-01-10T16:20:52.156534Z"
                 },
-                "referredBy": {
+                "userId": {
                     "type": "string",
                     "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
                 },
-                "repeatableKYCSteps": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "t1ReferralCount": {
+                "username": {
+                    "type": "string",
+                    "example": "jdoe"
+                }
+            }
+        },
+        "users.UnderageUser": {
+            "type": "object",
+            "properties": {
+                "age": {
                     "type": "integer",
-                    "example": 100
+                    "example": 12
                 },
-                "t2ReferralCount": {
-                    "type": "integer",
-                    "example": 100
+                "country": {
+                    "type": "string",
+                    "example": "US"
                 },
-                "updatedAt": {
+                "deletedAt": {
                     "type": "string",
-                    "example": "2022-01-03T16:20:52.156534Z"
+                    "example": "2022-02-03T16:20:52.156534Z"
                 },
-                "username": {
+                "deletionDueAt": {
                     "type": "string",
-                    "example": "jdoe"
+                    "example": "2022-02-03T16:20:52.156534Z"
                 },
-                "verified": {
-                    "type": "boolean",
-                    "example": true
+                "detectedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "minimumAge": {
+                    "type": "integer",
+                    "example": 13
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
                 }
             }
         },
-        "server.ErrorResponse": {
+        "users.UserBlock": {
             "type": "object",
             "properties": {
-                "code": {
+                "blockedUserId": {
                     "type": "string",
-                    "example": "SOMETHING_NOT_FOUND"
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3"
                 },
-                "data": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
                 },
-                "error": {
+                "userId": {
                     "type": "string",
-                    "example": "something is missing"
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
                 }
             }
         },
-        "users.CountryStatistics": {
+        "users.UserCountTimeSeriesDataPoint": {
             "type": "object",
             "properties": {
-                "country": {
-                    "description": "ISO 3166 country code.",
+                "active": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "date": {
                     "type": "string",
-                    "example": "US"
+                    "example": "2022-01-03T16:20:52.156534Z"
                 },
-                "userCount": {
+                "total": {
                     "type": "integer",
-                    "example": 12121212
+                    "example": 11
                 }
             }
         },
-        "users.JSON": {
-            "type": "object",
-            "additionalProperties": {}
-        },
-        "users.KYCStep": {
-            "type": "integer",
+        "users.UserDeletionBatchMode": {
+            "type": "string",
             "enum": [
-                0,
-                1,
-                2,
-                3,
-                4,
-                5,
-                6,
-                7,
-                8,
-                9,
-                10
+                "delete",
+                "anonymize"
             ],
             "x-enum-varnames": [
-                "NoneKYCStep",
-                "FacialRecognitionKYCStep",
-                "LivenessDetectionKYCStep",
-                "Social1KYCStep",
-                "QuizKYCStep",
-                "Social2KYCStep",
-                "Social3KYCStep",
-                "Social4KYCStep",
-                "Social5KYCStep",
-                "Social6KYCStep",
-                "Social7KYCStep"
+                "DeleteUserDeletionBatchMode",
+                "AnonymizeUserDeletionBatchMode"
             ]
         },
-        "users.MinimalUserProfile": {
+        "users.UserDeletionBatchReport": {
             "type": "object",
             "properties": {
-                "active": {
-                    "type": "boolean",
-                    "example": true
+                "anonymized": {
+                    "description": "The users that were anonymized, in the ` + "`" + `anonymize` + "`" + ` mode.",
+                    "type": "integer",
+                    "example": 0
                 },
-                "city": {
+                "createdAt": {
                     "type": "string",
-                    "example": "New York"
+                    "example": "2022-01-03T16:20:52.156534Z"
                 },
-                "country": {
-                    "type": "string",
-                    "example": "US"
+                "deleted": {
+                    "type": "integer",
+                    "example": 880
                 },
-                "email": {
-                    "type": "string",
-                    "example": "jdoe@gmail.com"
+                "dryRun": {
+                    "description": "Set only for dry runs, which aren't stored.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.DryRunPreview"
+                        }
+                    ]
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 5
                 },
-                "id": {
-                    "type": "string",
-                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                "failures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.UserDeletionFailure"
+                    }
                 },
-                "phoneNumber": {
+                "finishedAt": {
                     "type": "string",
-                    "example": "+12099216581"
-                },
-                "pinged": {
-                    "type": "boolean",
-                    "example": false
+                    "example": "2022-01-03T18:20:52.156534Z"
                 },
-                "profilePictureUrl": {
+                "id": {
                     "type": "string",
-                    "example": "https://somecdn.com/p1.jpg"
+                    "example": "b0a0c1b4-9f3d-4a5e-8c41-2b8f1e0d7a11"
                 },
-                "referralType": {
+                "mode": {
                     "enum": [
-                        "CONTACTS",
-                        "T0",
-                        "T1",
-                        "T2"
+                        "delete",
+                        "anonymize"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/users.ReferralType"
+                            "$ref": "#/definitions/users.UserDeletionBatchMode"
                         }
                     ],
-                    "example": "T1"
+                    "example": "delete"
                 },
-                "username": {
+                "notFound": {
+                    "description": "The users that were already deleted.",
+                    "type": "integer",
+                    "example": 15
+                },
+                "processed": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "reason": {
                     "type": "string",
-                    "example": "jdoe"
+                    "example": "ticket 1234: accounts of minors"
                 },
-                "verified": {
-                    "type": "boolean",
-                    "example": true
+                "requestedBy": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1000
                 }
             }
         },
-        "users.ReferralAcquisition": {
+        "users.UserDeletionFailure": {
             "type": "object",
             "properties": {
-                "date": {
+                "error": {
                     "type": "string",
-                    "example": "2022-01-03"
+                    "example": "context deadline exceeded"
                 },
-                "t1": {
-                    "type": "integer",
-                    "example": 22
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "users.UserEvent": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
                 },
-                "t2": {
-                    "type": "integer",
-                    "example": 13
+                "eventType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.UserEventType"
+                        }
+                    ],
+                    "example": "modified"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "username",
+                        "country"
+                    ]
+                },
+                "kycStep": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.KYCStep"
+                        }
+                    ],
+                    "example": 1
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
                 }
             }
         },
-        "users.ReferralType": {
+        "users.UserEventType": {
             "type": "string",
             "enum": [
-                "CONTACTS",
-                "T1",
-                "T2",
-                "TEAM"
+                "created",
+                "modified",
+                "deleted",
+                "anonymized",
+                "merged",
+                "kycStepPassed",
+                "kycStepBlocked"
             ],
             "x-enum-varnames": [
-                "ContactsReferrals",
-                "Tier1Referrals",
-                "Tier2Referrals",
-                "TeamReferrals"
+                "CreatedUserEventType",
+                "ModifiedUserEventType",
+                "DeletedUserEventType",
+                "AnonymizedUserEventType",
+                "MergedUserEventType",
+                "KYCStepPassedUserEventType",
+                "KYCStepBlockedUserEventType"
             ]
         },
-        "users.Referrals": {
+        "users.UserGrowthStatistics": {
             "type": "object",
             "properties": {
                 "active": {
                     "type": "integer",
                     "example": 11
                 },
-                "referrals": {
+                "rollingActiveUsers": {
+                    "description": "RollingActiveUsers is provided only if requested.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.RollingActiveUsers"
+                        }
+                    ]
+                },
+                "timeSeries": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/users.MinimalUserProfile"
+                        "$ref": "#/definitions/users.UserCountTimeSeriesDataPoint"
                     }
                 },
                 "total": {
@@ -958,39 +5566,155 @@ const docTemplate = `{
                 }
             }
         },
-        "users.UserCountTimeSeriesDataPoint": {
+        "users.UserImportBatchReport": {
             "type": "object",
             "properties": {
-                "active": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "dryRun": {
+                    "description": "Set only for dry runs, which aren't stored.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.DryRunPreview"
+                        }
+                    ]
+                },
+                "duplicate": {
+                    "description": "The records whose users exist already, or that repeat the ID, email or username of an earlier line.",
                     "type": "integer",
-                    "example": 11
+                    "example": 15
                 },
-                "date": {
+                "failed": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "failures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.UserImportFailure"
+                    }
+                },
+                "finishedAt": {
                     "type": "string",
-                    "example": "2022-01-03T16:20:52.156534Z"
+                    "example": "2022-01-03T18:20:52.156534Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "b0a0c1b4-9f3d-4a5e-8c41-2b8f1e0d7a11"
+                },
+                "imported": {
+                    "type": "integer",
+                    "example": 870
+                },
+                "invalid": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "processed": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "migration of the legacy users"
+                },
+                "requestedBy": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                },
+                "snapshots": {
+                    "enum": [
+                        "send",
+                        "batch",
+                        "suppress"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.UserImportSnapshotsMode"
+                        }
+                    ],
+                    "example": "send"
                 },
                 "total": {
                     "type": "integer",
-                    "example": 11
+                    "example": 1000
                 }
             }
         },
-        "users.UserGrowthStatistics": {
+        "users.UserImportFailure": {
             "type": "object",
             "properties": {
-                "active": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid email"
+                },
+                "line": {
                     "type": "integer",
-                    "example": 11
+                    "example": 17
                 },
-                "timeSeries": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/users.UserCountTimeSeriesDataPoint"
-                    }
+                "outcome": {
+                    "enum": [
+                        "invalid",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.UserImportOutcome"
+                        }
+                    ],
+                    "example": "invalid"
                 },
-                "total": {
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "users.UserImportOutcome": {
+            "type": "string",
+            "enum": [
+                "imported",
+                "duplicate",
+                "invalid",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ImportedUserImportOutcome",
+                "DuplicateUserImportOutcome",
+                "InvalidUserImportOutcome",
+                "FailedUserImportOutcome"
+            ]
+        },
+        "users.UserImportSnapshotsMode": {
+            "type": "string",
+            "enum": [
+                "send",
+                "batch",
+                "suppress"
+            ],
+            "x-enum-varnames": [
+                "SendUserImportSnapshotsMode",
+                "BatchUserImportSnapshotsMode",
+                "SuppressUserImportSnapshotsMode"
+            ]
+        },
+        "users.UserLevel": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "description": "Hidden from the other users, if the user hid it.",
                     "type": "integer",
-                    "example": 11
+                    "example": 2
+                },
+                "next": {
+                    "description": "The progress towards the next level. Only for the user itself, unless it reached the last one.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.LevelProgress"
+                        }
+                    ]
                 }
             }
         },
@@ -1028,6 +5752,11 @@ const docTemplate = `{
                     "type": "string",
                     "example": "John"
                 },
+                "globalRank": {
+                    "description": "The position of the user among all the ones with referrals, by ` + "`" + `globalRank.metric` + "`" + `, as of the last time they were ranked.\nThe ones with the same metric share it. Hidden from the other users, if the user hid it.",
+                    "type": "integer",
+                    "example": 37
+                },
                 "hiddenProfileElements": {
                     "type": "array",
                     "items": {
@@ -1037,7 +5766,8 @@ const docTemplate = `{
                             "referralCount",
                             "level",
                             "role",
-                            "badges"
+                            "badges",
+                            "profileViews"
                         ]
                     },
                     "example": [
@@ -1082,6 +5812,12 @@ const docTemplate = `{
                         "2022-01-03T16:20:52.156534Z"
                     ]
                 },
+                "kycTimeline": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.KYCStepProgress"
+                    }
+                },
                 "language": {
                     "type": "string",
                     "example": "en"
@@ -1090,18 +5826,39 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Doe"
                 },
+                "level": {
+                    "description": "Hidden from the other users, if the user hid it. See UserLevel.",
+                    "type": "integer",
+                    "example": 2
+                },
                 "miningBlockchainAccountAddress": {
                     "type": "string",
                     "example": "0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
                 },
+                "pendingCountryChange": {
+                    "$ref": "#/definitions/users.CountryChange"
+                },
                 "phoneNumber": {
                     "type": "string",
                     "example": "+12099216581"
                 },
+                "profileCompleteness": {
+                    "description": "Percentage of the profile that's complete. Only for the user itself.",
+                    "type": "integer",
+                    "example": 75
+                },
                 "profilePictureUrl": {
                     "type": "string",
                     "example": "https://somecdn.com/p1.jpg"
                 },
+                "profileViews": {
+                    "description": "Only for the user itself, unless it hid them.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.ProfileViews"
+                        }
+                    ]
+                },
                 "referredBy": {
                     "type": "string",
                     "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
@@ -1112,6 +5869,19 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "residency": {
+                    "enum": [
+                        "EU",
+                        "US",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.Residency"
+                        }
+                    ],
+                    "example": "EU"
+                },
                 "t1ReferralCount": {
                     "type": "integer",
                     "example": 100
@@ -1133,6 +5903,109 @@ const docTemplate = `{
                     "example": true
                 }
             }
+        },
+        "users.UserReport": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "reason": {
+                    "enum": [
+                        "spam",
+                        "scam",
+                        "harassment",
+                        "impersonation",
+                        "inappropriateContent",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.UserReportReason"
+                        }
+                    ],
+                    "example": "spam"
+                },
+                "reportedBy": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3"
+                },
+                "resolvedAt": {
+                    "type": "string",
+                    "example": "2022-01-03T16:20:52.156534Z"
+                },
+                "resolvedBy": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B4"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "actioned",
+                        "dismissed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/users.UserReportStatus"
+                        }
+                    ],
+                    "example": "pending"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "sends me referral links every day"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"
+                }
+            }
+        },
+        "users.UserReportReason": {
+            "type": "string",
+            "enum": [
+                "spam",
+                "scam",
+                "harassment",
+                "impersonation",
+                "inappropriateContent",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "SpamUserReportReason",
+                "ScamUserReportReason",
+                "HarassmentUserReportReason",
+                "ImpersonationUserReportReason",
+                "InappropriateContentUserReportReason",
+                "OtherUserReportReason"
+            ]
+        },
+        "users.UserReportStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "actioned",
+                "dismissed"
+            ],
+            "x-enum-varnames": [
+                "PendingUserReportStatus",
+                "ActionedUserReportStatus",
+                "DismissedUserReportStatus"
+            ]
+        },
+        "users.UserSearchField": {
+            "type": "string",
+            "enum": [
+                "username",
+                "email",
+                "phoneNumber"
+            ],
+            "x-enum-varnames": [
+                "UsernameUserSearchField",
+                "EmailUserSearchField",
+                "PhoneNumberUserSearchField"
+            ]
         }
     }
 }`
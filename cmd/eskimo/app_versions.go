@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupAppVersionRequirementsRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("app-version-requirements", server.RootHandler(s.GetAppVersionRequirements))
+}
+
+// GetAppVersionRequirements godoc
+//
+//	@Schemes
+//	@Description	Returns the earliest supported mobile app version of every platform and whether the older apps are forced to update.
+//	@Description	If forced, every write call made from a device with an older app fails with `UPDATE_REQUIRED`.
+//	@Tags			Devices
+//	@Produce		json
+//	@Success		200	{array}		users.AppVersionRequirement
+//	@Failure		500	{object}	server.ErrorResponse
+//	@Failure		504	{object}	server.ErrorResponse	"if request times out"
+//	@Router			/app-version-requirements [GET].
+func (s *service) GetAppVersionRequirements( //nolint:gocritic // False negative.
+	ctx context.Context,
+	_ *server.Request[GetAppVersionRequirementsArg, []*users.AppVersionRequirement],
+) (*server.Response[[]*users.AppVersionRequirement], *server.Response[server.ErrorResponse]) {
+	res, err := s.usersRepository.GetAppVersionRequirements(ctx)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrap(err, "failed to get app version requirements"))
+	}
+
+	return server.OK(&res), nil
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupBadgesRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("users/:userId/badges", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Profiles, s.GetBadges)))
+}
+
+// GetBadges godoc
+//
+//	@Schemes
+//	@Description	Returns the badges awarded to an user, oldest first. None are returned for the other users, if the user hid them.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Success		200					{array}		users.Badge
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		404					{object}	server.ErrorResponse	"if not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/badges [GET].
+func (s *service) GetBadges( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetBadgesArg, []*users.Badge],
+) (*server.Response[[]*users.Badge], *server.Response[server.ErrorResponse]) {
+	badges, err := s.usersRepository.GetBadges(ctx, req.Data.UserID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get the badges of userID:%v", req.Data.UserID)
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK(&badges), nil
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupBlockchainAddressesRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("users/:userId/blockchain-addresses", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Profiles, s.GetBlockchainAddresses)))
+}
+
+// GetBlockchainAddresses godoc
+//
+//	@Schemes
+//	@Description	Returns the blockchain addresses whose ownership was proven by the user, the earliest added first. Only for the user itself.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Success		200					{array}		users.BlockchainAddress
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/blockchain-addresses [GET].
+func (s *service) GetBlockchainAddresses( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetBlockchainAddressesArg, []*users.BlockchainAddress],
+) (*server.Response[[]*users.BlockchainAddress], *server.Response[server.ErrorResponse]) {
+	res, err := s.usersRepository.GetBlockchainAddresses(ctx, req.Data.UserID)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get blockchain addresses for %#v", req.Data))
+	}
+	if res == nil {
+		res = []*users.BlockchainAddress{}
+	}
+
+	return server.OK(&res), nil
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupClientConfigRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("client-config", server.RootHandler(s.GetClientConfig))
+}
+
+// GetClientConfig godoc
+//
+//	@Schemes
+//	@Description	Returns the values that differ between the deployments, i.e. the support email, the referral URL, the min app versions, the features and the KYC steps.
+//	@Description	It's versioned with the `ETag` header: if it's sent back as `If-None-Match`, it responds with 304, without a body, unless it changed.
+//	@Tags			Devices
+//	@Produce		json
+//	@Success		200	{object}	ClientConfig
+//	@Success		304	"if it didn't change since the `ETag` in `If-None-Match`"
+//	@Failure		500	{object}	server.ErrorResponse
+//	@Failure		504	{object}	server.ErrorResponse	"if request times out"
+//	@Router			/client-config [GET].
+func (s *service) GetClientConfig( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetClientConfigArg, ClientConfig],
+) (*server.Response[ClientConfig], *server.Response[server.ErrorResponse]) {
+	appVersionRequirements, err := s.usersRepository.GetAppVersionRequirements(ctx)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrap(err, "failed to get app version requirements"))
+	}
+	resp := &ClientConfig{
+		SupportEmail:           cfg.ClientConfig.SupportEmail,
+		ReferralURLTemplate:    cfg.ClientConfig.ReferralURLTemplate,
+		Features:               cfg.ClientConfig.Features,
+		AppVersionRequirements: appVersionRequirements,
+		KYCSteps:               cfg.ClientConfig.KYCSteps,
+	}
+	eTag, err := resp.eTag()
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to compute the etag of %#v", resp))
+	}
+	headers := map[string]string{"ETag": eTag}
+	if slices.Contains(strings.Split(strings.ReplaceAll(req.Data.IfNoneMatch, " ", ""), ","), eTag) {
+		return &server.Response[ClientConfig]{Code: http.StatusNotModified, Headers: headers}, nil
+	}
+	okResp := server.OK(resp)
+	okResp.Headers = headers
+
+	return okResp, nil
+}
+
+// eTag is the hash of the JSON of the config, so that it changes whenever any of its values does, whichever replica serves it.
+func (c *ClientConfig) eTag() (string, error) {
+	// The map keys are sorted by json.Marshal, so the same config always has the same JSON.
+	body, err := json.Marshal(c)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the client config")
+	}
+	hash := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(hash[:sha256.Size/2]) + `"`, nil
+}
+
+func validateClientConfig() error {
+	var mErr *multierror.Error
+	if cfg.ClientConfig.SupportEmail == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.clientConfig.supportEmail` is missing", applicationYamlKey))
+	}
+	if !strings.Contains(cfg.ClientConfig.ReferralURLTemplate, "{username}") {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.clientConfig.referralUrlTemplate` must contain `{username}`", applicationYamlKey))
+	}
+	for ix, step := range cfg.ClientConfig.KYCSteps {
+		if step < users.FacialRecognitionKYCStep || step > users.Social7KYCStep || slices.Contains(cfg.ClientConfig.KYCSteps[:ix], step) {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.clientConfig.kycSteps` has an invalid or duplicate step %v", applicationYamlKey, step))
+		}
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // Not needed.
+}
@@ -4,48 +4,230 @@ package main
 
 import (
 	"regexp"
+	"sync"
+	stdlibtime "time"
+
+	"github.com/gin-gonic/gin"
 
 	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/cmd/ratelimit"
 	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/time"
 )
 
 // Public API.
 
 type (
 	GetUsersArg struct {
-		Keyword string `form:"keyword" required:"true" example:"john"`
-		Limit   uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
-		Offset  uint64 `form:"offset" example:"5"`
+		Keyword    string `form:"keyword" required:"true" example:"john"`
+		SearchBy   string `form:"searchBy" example:"email" enums:"username,email,phoneNumber"` // `username` by default. The others are only for admins.
+		SearchMode string `form:"searchMode" example:"prefix" enums:"exact,prefix"`            // `exact` by default. Only for `searchBy` other than `username`.
+		Reason     string `form:"reason" example:"support ticket #123"`                        // Required if `searchBy` is not `username`. It's audited.
+		Limit      uint64 `form:"limit" maximum:"1000" example:"10"`                           // 10 by default.
+		Offset     uint64 `form:"offset" example:"5"`
+		Paginated  bool   `form:"paginated" example:"true"` // Whether to return a Page rather than just the list.
 	}
 	GetUserByIDArg struct {
 		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
 	}
+	GetBadgesArg struct {
+		UserID string `uri:"userId" required:"true" allowForbiddenGet:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+	}
+	GetUserLevelArg struct {
+		UserID string `uri:"userId" required:"true" allowForbiddenGet:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+	}
+	GetProfileChangesArg struct {
+		UserID        string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		SinceChecksum string `form:"sinceChecksum" example:"1232412415326543647657"`
+	}
 	GetUserByUsernameArg struct {
 		Username string `form:"username" required:"true" example:"jdoe"`
 	}
 	GetTopCountriesArg struct {
-		Keyword string `form:"keyword" example:"united states"`
-		Limit   uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
-		Offset  uint64 `form:"offset" example:"5"`
+		IfModifiedSince string `header:"If-Modified-Since" swaggerignore:"true" example:"Wed, 21 Oct 2015 07:28:00 GMT"`
+		Keyword         string `form:"keyword" example:"united states"`
+		Limit           uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset          uint64 `form:"offset" example:"5"`
+		// Whether to include the country of the authenticated user too, even if it's not in the requested page.
+		IncludeOwnCountry bool `form:"includeOwnCountry" example:"true"`
+		// Whether to return a Page rather than just the list.
+		Paginated bool `form:"paginated" example:"true"`
 	}
 	GetUserGrowthArg struct {
-		TZ   string `form:"tz" example:"+4:30"`
-		Days uint64 `form:"days" example:"7"`
+		IfModifiedSince string `header:"If-Modified-Since" swaggerignore:"true" example:"Wed, 21 Oct 2015 07:28:00 GMT"`
+		TZ              string `form:"tz" example:"+04:30"`
+		Days            uint64 `form:"days" example:"7"`
+		// Whether to include the distinct users active in the last 7 and 30 days.
+		RollingActiveUsers bool `form:"rollingActiveUsers" example:"true"`
+	}
+	GetKYCFunnelArg struct {
+		IfModifiedSince string `header:"If-Modified-Since" swaggerignore:"true" example:"Wed, 21 Oct 2015 07:28:00 GMT"`
+		Days            uint64 `form:"days" example:"7"`
+	}
+	GetEmailDomainStatisticsArg struct {
+		IfModifiedSince string `header:"If-Modified-Since" swaggerignore:"true" example:"Wed, 21 Oct 2015 07:28:00 GMT"`
+		Days            uint64 `form:"days" example:"7"`
+		Limit           uint64 `form:"limit" maximum:"100" example:"10"` // 10 by default.
+	}
+	GetGlobalValuesArg struct {
+		KeyPrefix string `form:"keyPrefix" required:"true" example:"TOTAL_USERS_"`
+		From      string `form:"from" example:"2022-01-03T16:20:52.156534Z"`
+		To        string `form:"to" example:"2022-01-04T16:20:52.156534Z"`
+		Limit     uint64 `form:"limit" maximum:"10000" example:"100"` // 100 by default.
+		Offset    uint64 `form:"offset" example:"5"`
 	}
 	GetReferralAcquisitionHistoryArg struct {
 		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
 		Days   uint64 `form:"days" maximum:"30" example:"5"`
 	}
+	ExportReferralAcquisitionHistoriesArg struct {
+		UserIDs []string `form:"userIds" maximum:"1000" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Country string   `form:"country" example:"RO"`
+	}
 	GetReferralsArg struct {
 		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
 		Type   string `form:"type" required:"true" example:"T1" enums:"T1,T2,CONTACTS"`
 		Limit  uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
 		Offset uint64 `form:"offset" example:"5"`
+		// Whether to return whether there are more too.
+		Paginated bool `form:"paginated" example:"true"`
+	}
+	GetPingableReferralsArg struct {
+		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Limit  uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset uint64 `form:"offset" example:"5"`
+	}
+	GetUserBlocksArg struct {
+		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Limit  uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset uint64 `form:"offset" example:"5"`
+	}
+	GetBlockchainAddressesArg struct {
+		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+	}
+	GetMutualConnectionsArg struct {
+		UserID string `uri:"userId" required:"true" allowForbiddenGet:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+	}
+	GetReferralInvitationsArg struct {
+		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Limit  uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset uint64 `form:"offset" example:"5"`
+	}
+	GetReportedUsersArg struct {
+		FlaggedOnly bool   `form:"flaggedOnly" example:"true"`
+		Limit       uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset      uint64 `form:"offset" example:"5"`
+	}
+	GetUserReportsArg struct {
+		UserID string `uri:"userId" required:"true" allowForbiddenGet:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Limit  uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset uint64 `form:"offset" example:"5"`
+	}
+	GetDuplicateAccountCandidatesArg struct {
+		MinScore uint64 `form:"minScore" maximum:"100" example:"50"`
+		Limit    uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset   uint64 `form:"offset" example:"5"`
+	}
+	GetUserDuplicateAccountCandidatesArg struct {
+		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+	}
+	GetUserEventsArg struct {
+		UserID    string              `form:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		EventType users.UserEventType `form:"eventType" example:"modified"`
+		From      string              `form:"from" example:"2022-01-03T16:20:52.156534Z"`
+		To        string              `form:"to" example:"2022-01-04T16:20:52.156534Z"`
+		Limit     uint64              `form:"limit" maximum:"1000" example:"100"` // 100 by default.
+		Offset    uint64              `form:"offset" example:"5"`
+	}
+	GetPendingCountryChangesArg struct {
+		Limit  uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset uint64 `form:"offset" example:"5"`
+	}
+	GetPublicStatisticsArg struct {
+		_ struct{} `allowUnauthorized:"true"` //nolint:revive // It's processed by the router.
+	}
+	GetSquattedUsernamesArg struct {
+		Limit  uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset uint64 `form:"offset" example:"5"`
+	}
+	GetUnderageUsersArg struct {
+		Limit  uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset uint64 `form:"offset" example:"5"`
+	}
+	GetSignInLockoutsArg struct {
+		Limit  uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset uint64 `form:"offset" example:"5"`
+	}
+	GetSignInAttemptsPerIPArg struct {
+		MinAttempts uint64 `form:"minAttempts" example:"5"`
+		Limit       uint64 `form:"limit" maximum:"1000" example:"10"` // 10 by default.
+		Offset      uint64 `form:"offset" example:"5"`
+	}
+	GetFirebaseMigrationArg struct {
+		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+	}
+	GetErrorCatalogArg struct {
+		_ struct{} `allowUnauthorized:"true"` //nolint:revive // It's processed by the router.
+	}
+	GetMaintenanceModeArg        struct{}
+	GetQueryAuditArg             struct{}
+	GetAppVersionRequirementsArg struct {
+		_ struct{} `allowUnauthorized:"true"` //nolint:revive // It's processed by the router.
+	}
+	GetClientConfigArg struct {
+		_           struct{} `allowUnauthorized:"true"` //nolint:revive // It's processed by the router.
+		IfNoneMatch string   `header:"If-None-Match" swaggerignore:"true" example:"\"5d41402abc4b2a76b9719d911017c592\""`
+	}
+	// ClientConfig has the values that differ between the deployments, so that the clients don't hardcode them.
+	ClientConfig struct {
+		SupportEmail string `json:"supportEmail" example:"support@ice.io"`
+		// ReferralURLTemplate is the referral URL of an user, after replacing `{username}` with its username.
+		ReferralURLTemplate    string                         `json:"referralUrlTemplate" example:"https://ice.io/@{username}"`
+		Features               map[string]bool                `json:"features"`
+		AppVersionRequirements []*users.AppVersionRequirement `json:"appVersionRequirements"`
+		// KYCSteps are the KYC steps, in the order the users go through them.
+		KYCSteps []users.KYCStep `json:"kycSteps" example:"1,2,3,4"`
+	}
+	GetAdminDashboardArg struct{}
+	AdminDashboard       struct {
+		*users.AdminDashboard
+		*emaillink.SignInStatistics
+	}
+	GetUserDeletionBatchArg struct {
+		BatchID string `uri:"batchId" required:"true" example:"b0a0c1b4-9f3d-4a5e-8c41-2b8f1e0d7a11"`
+	}
+	GetDistributionEligibilitySnapshotArg struct {
+		SnapshotID string `uri:"snapshotId" required:"true" example:"b0a0c1b4-9f3d-4a5e-8c41-2b8f1e0d7a11"`
+	}
+	GetDistributionEligibilityArg struct {
+		UserID string `uri:"userId" required:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		AsOf   string `form:"asOf" example:"2022-01-03T16:20:52.156534Z"` // Now by default.
+	}
+	GetUserImportBatchArg struct {
+		BatchID string `uri:"batchId" required:"true" example:"b0a0c1b4-9f3d-4a5e-8c41-2b8f1e0d7a11"`
 	}
 	User struct {
 		*users.UserProfile
 		Checksum string `json:"checksum,omitempty" example:"1232412415326543647657"`
 	}
+	// Page is a page of a list along with whether there are more, so that the clients don't have to compare its size to the limit.
+	// For backward compatibility, it's returned as such only if `paginated` is true, otherwise just its items are.
+	Page[T any] struct {
+		// Set only if it's cheap to count all of them.
+		Total *uint64 `json:"total,omitempty" example:"100"`
+		// The `offset` of the next page. Empty if there are no more.
+		NextCursor string `json:"nextCursor,omitempty" example:"20"`
+		Items      []T    `json:"items"`
+		HasMore    bool   `json:"hasMore" example:"true"`
+		enveloped  bool
+	}
+	// Referrals have whether there are more too, if `paginated` is true.
+	Referrals struct {
+		*users.Referrals
+		HasMore *bool `json:"hasMore,omitempty" example:"true"`
+		// The `offset` of the next page. Empty if there are no more.
+		NextCursor string `json:"nextCursor,omitempty" example:"20"`
+	}
 )
 
 // Private API.
@@ -54,6 +236,45 @@ const (
 	applicationYamlKey                  = "cmd/eskimo"
 	swaggerRoot                         = "/users/r"
 	everythingNotAllowedInUsernameRegex = `[^.a-zA-Z0-9]+`
+	requestDeadline                     = 25 * stdlibtime.Second
+
+	defaultGlobalValuesLimit = 100
+	maxGlobalValuesCSVLimit  = 10000
+
+	maxReferralAcquisitionHistoriesUserIDs     = 1000
+	referralAcquisitionHistoriesFlushEvery     = 100
+	referralAcquisitionHistoriesExportDeadline = 10 * stdlibtime.Minute
+	defaultGlobalValuesTimeRange               = 24 * stdlibtime.Hour
+
+	defaultUserGrowthDays = 3
+	maxUserGrowthDays     = 90
+
+	defaultKYCFunnelDays = 7
+	maxKYCFunnelDays     = 90
+
+	defaultEmailDomainStatisticsDays  = 7
+	maxEmailDomainStatisticsDays      = 90
+	defaultEmailDomainStatisticsLimit = 10
+	maxEmailDomainStatisticsLimit     = 100
+
+	usernameSearchBy          = "username"
+	minUserSearchPrefixLength = 3
+
+	defaultUserEventsLimit     = 100
+	defaultUserEventsTimeRange = 7 * 24 * stdlibtime.Hour
+
+	defaultDuplicateAccountCandidatesLimit = 10
+	defaultUserBlocksLimit                 = 10
+	defaultReferralInvitationsLimit        = 10
+	defaultReportedUsersLimit              = 10
+	defaultUserReportsLimit                = 10
+	defaultPendingCountryChangesLimit      = 10
+	defaultSignInLockoutsLimit             = 10
+	defaultSquattedUsernamesLimit          = 10
+	defaultUnderageUsersLimit              = 10
+	defaultUsersLimit                      = 10
+	defaultReferralsLimit                  = 10
+	defaultTopCountriesLimit               = 10
 )
 
 // Values for server.ErrorResponse#Code.
@@ -62,9 +283,17 @@ const (
 	invalidUsernameErrorCode   = "INVALID_USERNAME"
 	invalidKeywordErrorCode    = "INVALID_KEYWORD"
 	invalidPropertiesErrorCode = "INVALID_PROPERTIES"
+	tooManyRequestsErrorCode   = "TOO_MANY_REQUESTS"
+
+	userDeletionBatchNotFoundErrorCode = "USER_DELETION_BATCH_NOT_FOUND"
+	userImportBatchNotFoundErrorCode   = "USER_IMPORT_BATCH_NOT_FOUND"
+
+	distributionEligibilitySnapshotNotFoundErrorCode = "DISTRIBUTION_ELIGIBILITY_SNAPSHOT_NOT_FOUND"
 
 	requestingUserIDCtxValueKey = "requestingUserIDCtxValueKey"
 
+	maintenanceModePath = "/v1r/maintenance-mode"
+
 	adminRole = "admin"
 )
 
@@ -77,11 +306,59 @@ var (
 type (
 	// | service implements server.State and is responsible for managing the state and lifecycle of the package.
 	service struct {
-		usersRepository users.Repository
-		iceClient       emaillink.IceUserIDClient
+		usersRepository         users.Repository
+		iceClient               emaillink.IceUserIDClient
+		publicStatisticsLimiter *ipRateLimiter
+		compressResponse        gin.HandlerFunc
+	}
+	// | ipRateLimiter allows at most `maxRequests` per IP in every `window`.
+	ipRateLimiter struct {
+		windowStart *time.Time
+		requests    map[string]uint64
+		maxRequests uint64
+		window      stdlibtime.Duration
+		mx          sync.Mutex
 	}
 	config struct {
 		Host    string `yaml:"host"`
 		Version string `yaml:"version"`
+		// ConfigWatchInterval is how often application.yaml is checked for changes, to reload the values that support it. 0 disables it.
+		// SIGHUP always reloads them.
+		ConfigWatchInterval stdlibtime.Duration `yaml:"configWatchInterval"`
+		// RouteTimeouts are the deadline budgets of the route groups. They can't exceed the `defaultEndpointTimeout`, which is used if they're 0.
+		RouteTimeouts struct {
+			Profiles   stdlibtime.Duration `yaml:"profiles"`
+			Referrals  stdlibtime.Duration `yaml:"referrals"`
+			Statistics stdlibtime.Duration `yaml:"statistics"`
+			Admin      stdlibtime.Duration `yaml:"admin"`
+			LongPolls  stdlibtime.Duration `yaml:"longPolls"`
+		} `yaml:"routeTimeouts"`
+		// RateLimits are the soft limits of the calls each caller can make to each route, reported to the clients via the `X-RateLimit-*` headers.
+		RateLimits ratelimit.Config `yaml:"rateLimits"`
+		// ProfileChangesLongPollTimeout is how long GET /users/{userId}/changes waits for the profile to change before responding with 204.
+		// It must be shorter than the `longPolls` route timeout.
+		ProfileChangesLongPollTimeout stdlibtime.Duration `yaml:"profileChangesLongPollTimeout"`
+		DefaultEndpointTimeout        stdlibtime.Duration `yaml:"defaultEndpointTimeout"`
+		PublicStatistics              struct {
+			// CacheMaxAge is how long the CDNs and the browsers can cache the public statistics.
+			CacheMaxAge stdlibtime.Duration `yaml:"cacheMaxAge"`
+			// RateLimit allows at most `maxRequests` per IP in every `window`. 0 disables it.
+			RateLimit struct {
+				MaxRequests uint64              `yaml:"maxRequests"`
+				Window      stdlibtime.Duration `yaml:"window"`
+			} `yaml:"rateLimit"`
+		} `yaml:"publicStatistics"`
+		ResponseCompression struct {
+			// MinSize is the size, in bytes, from which the responses of the list-heavy endpoints are compressed. 0 disables it.
+			MinSize int `yaml:"minSize"`
+		} `yaml:"responseCompression"`
+		// MaxResponseItems caps the number of items the list-heavy endpoints return, whatever the `limit` asked for. 0 disables it.
+		MaxResponseItems uint64 `yaml:"maxResponseItems"`
+		ClientConfig     struct {
+			SupportEmail        string          `yaml:"supportEmail"`
+			ReferralURLTemplate string          `yaml:"referralUrlTemplate"`
+			Features            map[string]bool `yaml:"features"`
+			KYCSteps            []users.KYCStep `yaml:"kycSteps"`
+		} `yaml:"clientConfig"`
 	}
 )
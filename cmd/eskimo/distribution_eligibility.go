@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (s *service) setupDistributionEligibilityRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("distribution-eligibility-snapshots/:snapshotId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetDistributionEligibilitySnapshot))).
+		GET("users/:userId/distribution-eligibility", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetDistributionEligibility)))
+}
+
+// GetDistributionEligibilitySnapshot godoc
+//
+//	@Schemes
+//	@Description	Returns a distribution eligibility snapshot. Once `completedAt` is set, its CSV parts, `distribution_eligibility/as_of=<asOf>/part-<00000..parts-1>.csv`, are exported. Only for admins.
+//	@Description	`hash` is the hex sha256 of the records of all the parts, in order, without their header lines, so that the exported files can be audited.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			snapshotId			path		string	true	"ID of the snapshot"
+//	@Success		200					{object}	users.DistributionEligibilitySnapshot
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/distribution-eligibility-snapshots/{snapshotId} [GET].
+func (s *service) GetDistributionEligibilitySnapshot( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetDistributionEligibilitySnapshotArg, users.DistributionEligibilitySnapshot],
+) (*server.Response[users.DistributionEligibilitySnapshot], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	snapshot, err := s.usersRepository.GetDistributionEligibilitySnapshot(ctx, req.Data.SnapshotID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get distribution eligibility snapshot %v", req.Data.SnapshotID)
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, server.NotFound(err, distributionEligibilitySnapshotNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK(snapshot), nil
+}
+
+// GetDistributionEligibility godoc
+//
+//	@Schemes
+//	@Description	Returns the distribution eligibility of an user as of `asOf`, as the snapshots compute it, with the currently configured criteria. Only for admins.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Param			asOf				query		string	false	"RFC3339 point in time. Defaults to now"
+//	@Success		200					{object}	users.DistributionEligibility
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if user not found or created after asOf"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/distribution-eligibility [GET].
+func (s *service) GetDistributionEligibility( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetDistributionEligibilityArg, users.DistributionEligibility],
+) (*server.Response[users.DistributionEligibility], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	asOf := time.Now()
+	if req.Data.AsOf != "" {
+		parsed, err := stdlibtime.Parse(stdlibtime.RFC3339Nano, req.Data.AsOf)
+		if err != nil {
+			return nil, server.UnprocessableEntity(errors.Wrapf(err, "invalid asOf `%v`", req.Data.AsOf), invalidPropertiesErrorCode,
+				errorcatalog.FieldsData(errorcatalog.InvalidReason, "asOf"))
+		}
+		asOf = time.New(parsed.UTC())
+	}
+	eligibility, err := s.usersRepository.GetDistributionEligibility(ctx, req.Data.UserID, asOf)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get the distribution eligibility for %#v", req.Data)
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK(eligibility), nil
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupDuplicateAccountsRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("duplicate-accounts", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetDuplicateAccountCandidates))).
+		GET("duplicate-accounts/:userId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetUserDuplicateAccountCandidates)))
+}
+
+// GetDuplicateAccountCandidates godoc
+//
+//	@Schemes
+//	@Description	Returns the pairs of accounts that probably belong to the same person, ordered by their score (0-100). Only for admins.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			minScore			query		uint64	false	"Minimum score of the pairs to return"
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{array}		users.DuplicateAccountCandidate
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/duplicate-accounts [GET].
+func (s *service) GetDuplicateAccountCandidates( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetDuplicateAccountCandidatesArg, []*users.DuplicateAccountCandidate],
+) (*server.Response[[]*users.DuplicateAccountCandidate], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultDuplicateAccountCandidatesLimit
+	}
+	res, err := s.usersRepository.GetDuplicateAccountCandidates(ctx, req.Data.MinScore, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get duplicate account candidates for %#v", req.Data))
+	}
+	if res == nil {
+		res = []*users.DuplicateAccountCandidate{}
+	}
+
+	return server.OK(&res), nil
+}
+
+// GetUserDuplicateAccountCandidates godoc
+//
+//	@Schemes
+//	@Description	Returns all the accounts that probably belong to the same person as the provided user. Only for admins.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Success		200					{array}		users.DuplicateAccountCandidate
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/duplicate-accounts/{userId} [GET].
+func (s *service) GetUserDuplicateAccountCandidates( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetUserDuplicateAccountCandidatesArg, []*users.DuplicateAccountCandidate],
+) (*server.Response[[]*users.DuplicateAccountCandidate], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	res, err := s.usersRepository.GetUserDuplicateAccountCandidates(ctx, req.Data.UserID)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get duplicate account candidates for userID:%v", req.Data.UserID))
+	}
+	if res == nil {
+		res = []*users.DuplicateAccountCandidate{}
+	}
+
+	return server.OK(&res), nil
+}
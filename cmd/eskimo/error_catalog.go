@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupErrorCatalogRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("errors", server.RootHandler(s.GetErrorCatalog))
+}
+
+// GetErrorCatalog godoc
+//
+//	@Schemes
+//	@Description	Returns all the possible values of `code` in the error responses of both the read and the write APIs, with their description and whether the request can be retried.
+//	@Description	Where applicable, the error responses also have machine-readable details in `data.details`: the `reason` (required, invalid, tooShort, conflict or notAllowed) and the `fields` that caused it, so that the errors can be localized.
+//	@Tags			Errors
+//	@Produce		json
+//	@Success		200	{array}		errorcatalog.Entry
+//	@Failure		500	{object}	server.ErrorResponse
+//	@Failure		504	{object}	server.ErrorResponse	"if request times out"
+//	@Router			/errors [GET].
+func (*service) GetErrorCatalog( //nolint:gocritic // False negative.
+	_ context.Context,
+	_ *server.Request[GetErrorCatalogArg, []*errorcatalog.Entry],
+) (*server.Response[[]*errorcatalog.Entry], *server.Response[server.ErrorResponse]) {
+	catalog := errorcatalog.Catalog()
+
+	return server.OK(&catalog), nil
+}
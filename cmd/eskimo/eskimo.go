@@ -4,12 +4,18 @@ package main
 
 import (
 	"context"
+	stdlibtime "time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
 	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/cmd/compression"
+	"github.com/ice-blockchain/eskimo/cmd/configreload"
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
 	"github.com/ice-blockchain/eskimo/cmd/eskimo/api"
+	"github.com/ice-blockchain/eskimo/cmd/maintenance"
+	"github.com/ice-blockchain/eskimo/cmd/ratelimit"
 	"github.com/ice-blockchain/eskimo/users"
 	appcfg "github.com/ice-blockchain/wintr/config"
 	"github.com/ice-blockchain/wintr/log"
@@ -31,18 +37,79 @@ func main() {
 	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
 	api.SwaggerInfo.Host = cfg.Host
 	api.SwaggerInfo.Version = cfg.Version
+	mustValidateConfig()
 	server.New(new(service), applicationYamlKey, swaggerRoot).ListenAndServe(ctx, cancel)
 }
 
+// mustValidateConfig checks the config of every package used by the service before starting anything,
+// so that all the problems are reported at once, instead of panicking on the first one found while initializing.
+func mustValidateConfig() {
+	var mErr *multierror.Error
+	if cfg.Host == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.host` is missing", applicationYamlKey))
+	}
+	longPollsBudget := cfg.RouteTimeouts.LongPolls
+	if longPollsBudget <= 0 {
+		longPollsBudget = cfg.DefaultEndpointTimeout
+	}
+	if cfg.ProfileChangesLongPollTimeout < 0 || (longPollsBudget > 0 && cfg.ProfileChangesLongPollTimeout >= longPollsBudget) {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.profileChangesLongPollTimeout` must be between 0 and the `longPolls` route timeout (%v), not %v",
+			applicationYamlKey, longPollsBudget, cfg.ProfileChangesLongPollTimeout))
+	}
+	mErr = multierror.Append(mErr,
+		deadline.ValidateBudgets(applicationYamlKey, cfg.DefaultEndpointTimeout, map[string]stdlibtime.Duration{
+			"profiles":   cfg.RouteTimeouts.Profiles,
+			"referrals":  cfg.RouteTimeouts.Referrals,
+			"statistics": cfg.RouteTimeouts.Statistics,
+			"admin":      cfg.RouteTimeouts.Admin,
+			"longPolls":  cfg.RouteTimeouts.LongPolls,
+		}),
+		ratelimit.ValidateConfig(applicationYamlKey, &cfg.RateLimits),
+		users.ValidateConfig(),
+		validateClientConfig(),
+	)
+	log.Panic(errors.Wrap(mErr.ErrorOrNil(), "invalid config, refusing to start"))
+}
+
 func (s *service) RegisterRoutes(router *server.Router) {
+	// They must be registered before the routes, to apply to them.
+	router.Use(maintenance.Middleware(s.usersRepository, maintenanceModePath), ratelimit.Middleware(&cfg.RateLimits), ratelimit.RetryAfterMiddleware())
+	s.compressResponse = compression.Middleware(cfg.ResponseCompression.MinSize)
 	s.setupUserRoutes(router)
 	s.setupUserReferralRoutes(router)
+	s.setupMutualConnectionsRoutes(router)
+	s.setupReferralInvitationsRoutes(router)
+	s.setupUserBlocksRoutes(router)
+	s.setupBlockchainAddressesRoutes(router)
+	s.setupBadgesRoutes(router)
+	s.setupUserLevelsRoutes(router)
+	s.setupUserReportsRoutes(router)
 	s.setupUserStatisticsRoutes(router)
+	s.setupAdminDashboardRoutes(router)
+	s.setupGlobalValuesRoutes(router)
+	s.setupDuplicateAccountsRoutes(router)
+	s.setupCountryChangesRoutes(router)
+	s.setupSignInLockoutsRoutes(router)
+	s.setupFirebaseMigrationsRoutes(router)
+	s.setupErrorCatalogRoutes(router)
+	s.setupAppVersionRequirementsRoutes(router)
+	s.setupClientConfigRoutes(router)
+	s.setupMaintenanceModeRoutes(router)
+	s.setupQueryAuditRoutes(router)
+	s.setupUserEventsRoutes(router)
+	s.setupUserDeletionBatchesRoutes(router)
+	s.setupDistributionEligibilityRoutes(router)
+	s.setupUserImportBatchesRoutes(router)
+	s.setupUsernameSquattingRoutes(router)
+	s.setupUnderageUsersRoutes(router)
+	s.setupPublicStatisticsRoutes(router)
+	s.setupOpenAPIRoutes(router)
 }
 
 func (s *service) Init(ctx context.Context, cancel context.CancelFunc) {
 	s.usersRepository = users.New(ctx, cancel)
 	s.iceClient = emaillink.NewROClient(ctx)
+	go configreload.Start(ctx, cfg.ConfigWatchInterval, s.usersRepository)
 }
 
 func (s *service) Close(ctx context.Context) error {
@@ -58,7 +125,7 @@ func (s *service) Close(ctx context.Context) error {
 
 func (s *service) CheckHealth(ctx context.Context) error {
 	log.Debug("checking health...", "package", "users")
-	_, err := s.usersRepository.GetTopCountries(ctx, "", 1, 0)
+	_, _, _, err := s.usersRepository.GetTopCountries(ctx, "", "", 1, 0)
 
 	return errors.Wrapf(err, "get top countries failed")
 }
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupFirebaseMigrationsRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("auth/firebase-migrations/:userId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Profiles, s.GetFirebaseMigration)))
+}
+
+// GetFirebaseMigration godoc
+//
+//	@Schemes
+//	@Description	Returns whether the user, if registered with Firebase, was migrated to an ice ID, when and how. Only for the user itself or admins.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Success		200					{object}	emaillink.FirebaseMigration
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/firebase-migrations/{userId} [GET].
+func (s *service) GetFirebaseMigration( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetFirebaseMigrationArg, emaillink.FirebaseMigration],
+) (*server.Response[emaillink.FirebaseMigration], *server.Response[server.ErrorResponse]) {
+	if req.Data.UserID != req.AuthenticatedUser.UserID && req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	res, err := s.iceClient.GetFirebaseMigration(ctx, req.Data.UserID)
+	if err != nil {
+		if errors.Is(err, emaillink.ErrUserNotFound) {
+			return nil, server.NotFound(errors.Wrapf(err, "user with id `%v` was not found", req.Data.UserID), userNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get the firebase migration of userID:%v", req.Data.UserID))
+	}
+
+	return server.OK(res), nil
+}
@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/cmd/params"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (s *service) setupGlobalValuesRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("global-values", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Statistics, s.GetGlobalValues))).
+		GET("global-values/csv", s.ExportGlobalValues)
+}
+
+// GetGlobalValues godoc
+//
+//	@Schemes
+//	@Description	Returns the raw global counters (TOTAL_USERS_*, TOTAL_ACTIVE_USERS_*, etc) matching the provided key prefix and covering a period,
+//	@Description	by the date suffix of their keys (e.g. `TOTAL_USERS_2022-01-22T16` covers that hour, in UTC), that overlaps with the provided time range.
+//	@Description	The ones without a date suffix, like TOTAL_USERS, are always returned. Only for admins.
+//	@Tags			Statistics
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			keyPrefix			query		string	true	"prefix of the keys to look for"
+//	@Param			from				query		string	false	"RFC3339 lower bound of the periods covered by the keys. Defaults to 24h before `to`"
+//	@Param			to					query		string	false	"RFC3339 upper bound of the periods covered by the keys. Defaults to now"
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 100"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{array}		users.GlobalUnsigned
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/global-values [GET].
+func (s *service) GetGlobalValues( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetGlobalValuesArg, []*users.GlobalUnsigned],
+) (*server.Response[[]*users.GlobalUnsigned], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	from, to, err := req.Data.timeRange()
+	if err != nil {
+		err = errors.Wrapf(err, "invalid time range for %#v", req.Data)
+
+		return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "from", "to"))
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultGlobalValuesLimit
+	}
+	res, err := s.usersRepository.GetGlobalValues(ctx, req.Data.KeyPrefix, from, to, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get global values for %#v", req.Data))
+	}
+
+	return server.OK(&res), nil
+}
+
+// ExportGlobalValues godoc
+//
+//	@Schemes
+//	@Description	Same as GET /global-values, but the result is exported as CSV. Only for admins.
+//	@Tags			Statistics
+//	@Accept			json
+//	@Produce		text/csv
+//	@Param			Authorization		header	string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			keyPrefix			query	string	true	"prefix of the keys to look for"
+//	@Param			from				query	string	false	"RFC3339 lower bound of the periods covered by the keys. Defaults to 24h before `to`"
+//	@Param			to					query	string	false	"RFC3339 upper bound of the periods covered by the keys. Defaults to now"
+//	@Param			limit				query	uint64	false	"Limit of elements to return. Defaults to 10000"
+//	@Param			offset				query	uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					"CSV with key,value,updatedAt columns"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Router			/global-values/csv [GET].
+func (s *service) ExportGlobalValues(ginCtx *gin.Context) {
+	ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), requestDeadline)
+	defer cancel()
+	if errResp := authorizeAdmin(ctx, ginCtx); errResp != nil {
+		abortWithError(ginCtx, errResp)
+
+		return
+	}
+	var arg GetGlobalValuesArg
+	if err := ginCtx.ShouldBindQuery(&arg); err != nil {
+		abortWithError(ginCtx, server.UnprocessableEntity(errors.Wrap(err, "binding failed"), invalidPropertiesErrorCode))
+
+		return
+	}
+	if arg.KeyPrefix == "" {
+		err := errors.New("properties `KeyPrefix` are required")
+		abortWithError(ginCtx, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.RequiredReason, "keyPrefix")))
+
+		return
+	}
+	from, to, err := arg.timeRange()
+	if err != nil {
+		err = errors.Wrapf(err, "invalid time range for %#v", arg)
+		abortWithError(ginCtx, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "from", "to")))
+
+		return
+	}
+	if arg.Limit == 0 || arg.Limit > maxGlobalValuesCSVLimit {
+		arg.Limit = maxGlobalValuesCSVLimit
+	}
+	res, err := s.usersRepository.GetGlobalValues(ctx, arg.KeyPrefix, from, to, arg.Limit, arg.Offset)
+	if err != nil {
+		log.Error(errors.Wrapf(err, "failed to get global values for %#v", arg))
+		ginCtx.JSON(http.StatusInternalServerError, &server.ErrorResponse{Error: "oops, something went wrong"})
+
+		return
+	}
+	records := make([][]string, 0, len(res)+1)
+	records = append(records, []string{"key", "value", "updatedAt"})
+	for _, val := range res {
+		var updatedAt string
+		if !val.UpdatedAt.IsNil() {
+			updatedAt = val.UpdatedAt.Format(stdlibtime.RFC3339Nano)
+		}
+		records = append(records, []string{val.Key, strconv.FormatUint(val.Value, 10), updatedAt}) //nolint:gomnd // Decimal.
+	}
+	ginCtx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="global-values-%v.csv"`, to.Format(stdlibtime.RFC3339)))
+	ginCtx.Header("Content-Type", "text/csv")
+	ginCtx.Status(http.StatusOK)
+	if err = csv.NewWriter(ginCtx.Writer).WriteAll(records); err != nil {
+		log.Error(errors.Wrapf(err, "failed to write global values csv for %#v", arg))
+	}
+}
+
+func (a *GetGlobalValuesArg) timeRange() (from, to *time.Time, err error) {
+	return params.TimeRange(a.From, a.To, defaultGlobalValuesTimeRange, 0) //nolint:wrapcheck // Nothing to add.
+}
+
+func authorizeAdmin(ctx context.Context, ginCtx *gin.Context) *server.Response[server.ErrorResponse] {
+	token, err := server.Auth(ctx).VerifyToken(ctx, strings.TrimPrefix(ginCtx.GetHeader("Authorization"), "Bearer "))
+	if err != nil {
+		return server.Unauthorized(err)
+	}
+	if token, err = server.Auth(ctx).ModifyTokenWithMetadata(token, ginCtx.GetHeader("X-Account-Metadata")); err != nil {
+		return server.Unauthorized(err)
+	}
+	if token.Role != adminRole {
+		return server.Forbidden(errors.Errorf("not allowed for role `%v`", token.Role))
+	}
+
+	return nil
+}
+
+func abortWithError(ginCtx *gin.Context, errResp *server.Response[server.ErrorResponse]) {
+	log.Error(errors.Wrapf(errResp.Data.InternalErr(), "endpoint %v failed", ginCtx.FullPath()))
+	ginCtx.JSON(errResp.Code, errResp.Data)
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupMaintenanceModeRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("maintenance-mode", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetMaintenanceMode)))
+}
+
+// GetMaintenanceMode godoc
+//
+//	@Schemes
+//	@Description	Returns the maintenance mode of both the read and the write APIs, as applied by this replica. Only for admins.
+//	@Tags			Maintenance
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Success		200					{object}	users.MaintenanceMode
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/maintenance-mode [GET].
+func (s *service) GetMaintenanceMode( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetMaintenanceModeArg, users.MaintenanceMode],
+) (*server.Response[users.MaintenanceMode], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	mode, err := s.usersRepository.GetMaintenanceMode(ctx)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrap(err, "failed to get maintenance mode"))
+	}
+
+	return server.OK(mode), nil
+}
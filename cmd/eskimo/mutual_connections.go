@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupMutualConnectionsRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("users/:userId/mutual", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Referrals, s.GetMutualConnections)))
+}
+
+// GetMutualConnections godoc
+//
+//	@Schemes
+//	@Description	Returns the connections that the authenticated user and another one have in common: their referrer, if it's the same one,
+//	@Description	and their mutual contacts, i.e. the users that both of them have in their agendas and that have both of them in theirs.
+//	@Description	Nothing is returned if any of them blocked the other, and the users blocked by, or that blocked, any of them are left out.
+//	@Tags			Referrals
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the other user"
+//	@Success		200					{object}	users.MutualConnections
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		404					{object}	server.ErrorResponse	"if not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails or the other user is the authenticated one"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/mutual [GET].
+func (s *service) GetMutualConnections( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetMutualConnectionsArg, users.MutualConnections],
+) (*server.Response[users.MutualConnections], *server.Response[server.ErrorResponse]) {
+	if req.Data.UserID == req.AuthenticatedUser.UserID {
+		return nil, server.UnprocessableEntity(errors.New("the other user can't be the authenticated one"), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.InvalidReason, "userId"))
+	}
+	mutual, err := s.usersRepository.GetMutualConnections(ctx, req.AuthenticatedUser.UserID, req.Data.UserID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get the mutual connections of userID:%v with %#v", req.AuthenticatedUser.UserID, req.Data)
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK(mutual), nil
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/cmd/openapi"
+	"github.com/ice-blockchain/eskimo/cmd/ratelimit"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+//go:generate go run ../openapi/routesgen
+
+// setupOpenAPIRoutes must be called after all the other routes are registered, because it documents all of them.
+func (*service) setupOpenAPIRoutes(router *server.Router) {
+	info := &openapi.Info{
+		Title:       "User Accounts, User Devices, User Statistics API",
+		Description: "API that handles everything related to read only operations for user's account, user's devices and statistics about accounts and devices.",
+		Version:     cfg.Version,
+	}
+	enums := []*openapi.Enum{
+		openapi.EnumOf(users.ReferralTypes...),
+		openapi.EnumOf(users.HiddenProfileElements...),
+		openapi.EnumOf(users.NoneKYCStep, users.FacialRecognitionKYCStep, users.LivenessDetectionKYCStep, users.Social1KYCStep, users.QuizKYCStep,
+			users.Social2KYCStep, users.Social3KYCStep, users.Social4KYCStep, users.Social5KYCStep, users.Social6KYCStep, users.Social7KYCStep),
+		openapi.EnumOf(users.AppliedCountryChangeStatus, users.VerifiedCountryChangeStatus, users.PendingCountryChangeStatus,
+			users.ApprovedCountryChangeStatus, users.RejectedCountryChangeStatus),
+		openapi.EnumOf(users.AndroidPlatform, users.IOSPlatform),
+		openapi.EnumOf(users.UserEventTypes...),
+		openapi.EnumOf(errorcatalog.NeverRetryPolicy, errorcatalog.AfterFixRetryPolicy, errorcatalog.AfterRefreshRetryPolicy, errorcatalog.WithBackoffRetryPolicy),
+		openapi.EnumOf(errorcatalog.EskimoService, errorcatalog.EskimoHutService),
+	}
+	doc := openapi.Generate(info, router.Routes(), ratelimit.Document(&cfg.RateLimits, openAPIRoutes()), enums...)
+	doc.Servers = []*openapi.Server{{URL: "https://" + cfg.Host}}
+	router.GET(openapi.Path, openapi.Handler(doc))
+}
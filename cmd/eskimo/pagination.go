@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"strconv"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+)
+
+// lookahead is the limit to query with: one more, if it's paginated, to find out whether there are more, without counting them.
+func lookahead(limit uint64, paginated bool) uint64 {
+	if paginated {
+		return limit + 1
+	}
+
+	return limit
+}
+
+// pageOf drops the extra item queried because of the lookahead, if there is one, since it means that there are more.
+func pageOf[T any](items []T, limit, offset uint64, paginated bool) *Page[T] {
+	page := &Page[T]{Items: items, enveloped: paginated}
+	if paginated && uint64(len(items)) > limit {
+		page.Items = items[:limit]
+		page.hasMore(limit, offset)
+	}
+
+	return page
+}
+
+func (p *Page[T]) hasMore(limit, offset uint64) {
+	p.HasMore, p.NextCursor = true, strconv.FormatUint(offset+limit, 10)
+}
+
+func (p *Page[T]) MarshalJSON() ([]byte, error) {
+	if !p.enveloped {
+		bytes, err := json.Marshal(p.Items)
+
+		return bytes, errors.Wrap(err, "failed to marshal the items")
+	}
+	bytes, err := json.Marshal(&struct {
+		Total      *uint64 `json:"total,omitempty"`
+		NextCursor string  `json:"nextCursor,omitempty"`
+		Items      []T     `json:"items"`
+		HasMore    bool    `json:"hasMore"`
+	}{Total: p.Total, NextCursor: p.NextCursor, Items: p.Items, HasMore: p.HasMore})
+
+	return bytes, errors.Wrap(err, "failed to marshal the page")
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/ratelimit"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (s *service) setupPublicStatisticsRoutes(router *server.Router) {
+	s.publicStatisticsLimiter = &ipRateLimiter{
+		requests:    make(map[string]uint64),
+		maxRequests: cfg.PublicStatistics.RateLimit.MaxRequests,
+		window:      cfg.PublicStatistics.RateLimit.Window,
+	}
+	router.
+		Group("v1r").
+		GET("public-statistics", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Statistics, s.GetPublicStatistics)))
+}
+
+// GetPublicStatistics godoc
+//
+//	@Schemes
+//	@Description	Returns the total number of users and of countries they're from, for the marketing website. It doesn't require authorization.
+//	@Description	It's heavily cached, both by the server and, via the `Cache-Control` header, by the CDNs. It's rate limited per IP.
+//	@Tags			Statistics
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	users.PublicStatistics
+//	@Failure		429	{object}	server.ErrorResponse	"if rate limited; `data.retryAfterSeconds`, like the `Retry-After` header, tells how long to wait"
+//	@Failure		500	{object}	server.ErrorResponse
+//	@Failure		504	{object}	server.ErrorResponse	"if request times out"
+//	@Router			/public-statistics [GET].
+func (s *service) GetPublicStatistics( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetPublicStatisticsArg, users.PublicStatistics],
+) (*server.Response[users.PublicStatistics], *server.Response[server.ErrorResponse]) {
+	if retryAfter := s.publicStatisticsLimiter.allow(req.ClientIP.String()); retryAfter > 0 {
+		err := errors.Errorf("too many requests from %v", req.ClientIP)
+		retryAfterSeconds := uint64(math.Ceil(retryAfter.Seconds()))
+		ratelimit.SetRetryAfter(ctx, retryAfterSeconds)
+		errResp := server.ForbiddenWithCode(err, tooManyRequestsErrorCode, map[string]any{"retryAfterSeconds": retryAfterSeconds})
+		errResp.Code = http.StatusTooManyRequests
+
+		return nil, errResp
+	}
+	stats, err := s.usersRepository.GetPublicStatistics(ctx)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrap(err, "failed to get public statistics"))
+	}
+	resp := server.OK(stats)
+	maxAge := int(cfg.PublicStatistics.CacheMaxAge.Seconds())
+	resp.Headers = map[string]string{
+		"Cache-Control": fmt.Sprintf("public, max-age=%[1]v, s-maxage=%[1]v, stale-while-revalidate=%[1]v, stale-if-error=%[2]v", maxAge, 2*maxAge), //nolint:gomnd // .
+	}
+
+	return resp, nil
+}
+
+// allow counts the requests of the IP in the current window and returns how long it has to wait, if it made too many.
+// A fixed window is enough here and its memory is freed every window.
+func (l *ipRateLimiter) allow(ip string) (retryAfter stdlibtime.Duration) {
+	if l.maxRequests == 0 || l.window == 0 {
+		return 0
+	}
+	now := time.Now()
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if l.windowStart == nil || now.Sub(*l.windowStart.Time) >= l.window {
+		l.windowStart = now
+		clear(l.requests)
+	}
+	if l.requests[ip] >= l.maxRequests {
+		return l.windowStart.Add(l.window).Sub(*now.Time) + stdlibtime.Second
+	}
+	l.requests[ip]++
+
+	return 0
+}
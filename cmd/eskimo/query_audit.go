@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupQueryAuditRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("query-audit", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetQueryAudit)))
+}
+
+// GetQueryAudit godoc
+//
+//	@Schemes
+//	@Description	Returns how long the queries took on this replica, the slowest first, while the query audit is enabled. Only for admins.
+//	@Tags			Maintenance
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Success		200					{object}	users.QueryAudit
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/query-audit [GET].
+func (s *service) GetQueryAudit( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetQueryAuditArg, users.QueryAudit],
+) (*server.Response[users.QueryAudit], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	audit, err := s.usersRepository.GetQueryAudit(ctx)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrap(err, "failed to get query audit"))
+	}
+
+	return server.OK(audit), nil
+}
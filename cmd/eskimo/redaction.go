@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ice-blockchain/eskimo/users"
+)
+
+// Format is needed because User embeds users.UserProfile, otherwise only the embedded users.UserProfile would be formatted.
+func (u User) Format(state fmt.State, verb rune) {
+	users.FormatRedacted(state, verb, u)
+}
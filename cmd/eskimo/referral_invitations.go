@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupReferralInvitationsRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("users/:userId/referral-invitations", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Referrals, s.GetReferralInvitations)))
+}
+
+// GetReferralInvitations godoc
+//
+//	@Schemes
+//	@Description	Returns the referral invitations sent by the user, the most recent first, with the ones whose invitee signed up as `joined`.
+//	@Description	Only for the user itself.
+//	@Tags			Referrals
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{array}		users.ReferralInvitation
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/referral-invitations [GET].
+func (s *service) GetReferralInvitations( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetReferralInvitationsArg, []*users.ReferralInvitation],
+) (*server.Response[[]*users.ReferralInvitation], *server.Response[server.ErrorResponse]) {
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultReferralInvitationsLimit
+	}
+	res, err := s.usersRepository.GetReferralInvitations(ctx, req.Data.UserID, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get referral invitations for %#v", req.Data))
+	}
+	if res == nil {
+		res = []*users.ReferralInvitation{}
+	}
+
+	return server.OK(&res), nil
+}
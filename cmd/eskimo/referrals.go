@@ -4,19 +4,31 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"strings"
+	stdlibtime "time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
 
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/cmd/params"
 	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/log"
 	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/time"
 )
 
 func (s *service) setupUserReferralRoutes(router *server.Router) {
 	router.
 		Group("v1r").
-		GET("users/:userId/referral-acquisition-history", server.RootHandler(s.GetReferralAcquisitionHistory)).
-		GET("users/:userId/referrals", server.RootHandler(s.GetReferrals))
+		GET("users/:userId/referral-acquisition-history", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Referrals, s.GetReferralAcquisitionHistory))).
+		GET("referral-acquisition-histories", s.ExportReferralAcquisitionHistories).
+		GET("users/:userId/referrals", s.compressResponse, server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Referrals, s.GetReferrals))).
+		GET("users/:userId/referrals/pingable", s.compressResponse, server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Referrals, s.GetPingableReferrals)))
 }
 
 // GetReferralAcquisitionHistory godoc
@@ -50,10 +62,64 @@ func (s *service) GetReferralAcquisitionHistory( //nolint:gocritic // False nega
 	return server.OK(&res), nil
 }
 
+// ExportReferralAcquisitionHistories godoc
+//
+//	@Schemes
+//	@Description	Streams the referral acquisition histories of the provided users, or of all the users of the provided country, as newline delimited JSON: one users.UserReferralAcquisitionHistory per line, ordered by user ID. The users without referrals are skipped. Only for admins.
+//	@Tags			Referrals
+//	@Accept			json
+//	@Produce		application/x-ndjson
+//	@Param			Authorization		header	string		true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header	string		false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userIds				query	[]string	false	"IDs of the users, at most 1000. Either them or the country are required"	collectionFormat(multi)
+//	@Param			country				query	string		false	"Country of the users. Either it or the userIds are required"
+//	@Success		200					"newline delimited users.UserReferralAcquisitionHistory"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Router			/referral-acquisition-histories [GET].
+func (s *service) ExportReferralAcquisitionHistories(ginCtx *gin.Context) {
+	ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), referralAcquisitionHistoriesExportDeadline)
+	defer cancel()
+	if errResp := authorizeAdmin(ctx, ginCtx); errResp != nil {
+		abortWithError(ginCtx, errResp)
+
+		return
+	}
+	var arg ExportReferralAcquisitionHistoriesArg
+	if err := ginCtx.ShouldBindQuery(&arg); err != nil {
+		abortWithError(ginCtx, server.UnprocessableEntity(errors.Wrap(err, "binding failed"), invalidPropertiesErrorCode))
+
+		return
+	}
+	if (len(arg.UserIDs) == 0) == (arg.Country == "") || len(arg.UserIDs) > maxReferralAcquisitionHistoriesUserIDs {
+		err := errors.Errorf("either the country or at most %v userIds are required", maxReferralAcquisitionHistoriesUserIDs)
+		abortWithError(ginCtx, server.BadRequest(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "userIds", "country")))
+
+		return
+	}
+	ginCtx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="referral-acquisition-histories-%v.ndjson"`, time.Now().Format(stdlibtime.RFC3339)))
+	ginCtx.Header("Content-Type", "application/x-ndjson")
+	ginCtx.Status(http.StatusOK)
+	encoder, streamed := json.NewEncoder(ginCtx.Writer), 0
+	err := s.usersRepository.StreamReferralAcquisitionHistories(ctx, arg.UserIDs, strings.ToUpper(arg.Country), func(history *users.UserReferralAcquisitionHistory) error {
+		if streamed++; streamed%referralAcquisitionHistoriesFlushEvery == 0 {
+			ginCtx.Writer.Flush()
+		}
+
+		return errors.Wrap(encoder.Encode(history), "failed to encode")
+	})
+	// The status was already sent, so the client notices only that the stream ended abruptly.
+	log.Error(errors.Wrapf(err, "failed to stream referral acquisition histories for %#v, after %v of them", arg, streamed))
+}
+
 // GetReferrals godoc
 //
 //	@Schemes
 //	@Description	Returns the referrals of an user.
+//	@Description	If `paginated` is true, they have `hasMore` and `nextCursor` too.
 //	@Tags			Referrals
 //	@Accept			json
 //	@Produce		json
@@ -63,7 +129,8 @@ func (s *service) GetReferralAcquisitionHistory( //nolint:gocritic // False nega
 //	@Param			type				query		string	true	"Type of referrals: `CONTACTS` or `T1` or `T2`"
 //	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
 //	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
-//	@Success		200					{object}	users.Referrals
+//	@Param			paginated			query		bool	false	"if true, it returns whether there are more too"
+//	@Success		200					{object}	Referrals
 //	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
 //	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
@@ -73,11 +140,9 @@ func (s *service) GetReferralAcquisitionHistory( //nolint:gocritic // False nega
 //	@Router			/users/{userId}/referrals [GET].
 func (s *service) GetReferrals( //nolint:gocritic // False negative.
 	ctx context.Context,
-	req *server.Request[GetReferralsArg, users.Referrals],
-) (*server.Response[users.Referrals], *server.Response[server.ErrorResponse]) {
-	if req.Data.Limit == 0 {
-		req.Data.Limit = 10
-	}
+	req *server.Request[GetReferralsArg, Referrals],
+) (*server.Response[Referrals], *server.Response[server.ErrorResponse]) {
+	req.Data.Limit = params.Capped(req.Data.Limit, defaultReferralsLimit, cfg.MaxResponseItems)
 	var validType bool
 	for _, referralType := range users.ReferralTypes {
 		if strings.EqualFold(req.Data.Type, string(referralType)) {
@@ -89,13 +154,51 @@ func (s *service) GetReferrals( //nolint:gocritic // False negative.
 	if !validType {
 		err := errors.Errorf("type '%v' is invalid, valid types are %v", req.Data.Type, users.ReferralTypes)
 
-		return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode)
+		return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "type"))
 	}
-
-	referrals, err := s.usersRepository.GetReferrals(ctx, req.Data.UserID, users.ReferralType(strings.ToUpper(req.Data.Type)), req.Data.Limit, req.Data.Offset)
+	referralType, limit := users.ReferralType(strings.ToUpper(req.Data.Type)), lookahead(req.Data.Limit, req.Data.Paginated)
+	referrals, err := s.usersRepository.GetReferrals(ctx, req.Data.UserID, referralType, limit, req.Data.Offset)
 	if err != nil {
 		return nil, server.Unexpected(errors.Wrapf(err, "failed to get referrals for %#v", req.Data))
 	}
+	resp := &Referrals{Referrals: referrals}
+	if req.Data.Paginated {
+		page := pageOf(referrals.Referrals, req.Data.Limit, req.Data.Offset, true)
+		resp.Referrals.Referrals, resp.HasMore, resp.NextCursor = page.Items, &page.HasMore, page.NextCursor
+	}
+
+	return server.OK(resp), nil
+}
+
+// GetPingableReferrals godoc
+//
+//	@Schemes
+//	@Description	Returns the referrals (T1 and the referrer) that can be pinged now: they're not mining and they weren't pinged during the cooldown.
+//	@Description	Its `selectAllToken` pings all of them, not just the ones in this page, via `POST /users/{userId}/referral-pings`.
+//	@Tags			Referrals
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{object}	users.PingableReferrals
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/referrals/pingable [GET].
+func (s *service) GetPingableReferrals( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetPingableReferralsArg, users.PingableReferrals],
+) (*server.Response[users.PingableReferrals], *server.Response[server.ErrorResponse]) {
+	req.Data.Limit = params.Capped(req.Data.Limit, defaultReferralsLimit, cfg.MaxResponseItems)
+	res, err := s.usersRepository.GetPingableReferrals(ctx, req.Data.UserID, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get pingable referrals for %#v", req.Data))
+	}
 
-	return server.OK(referrals), nil
+	return server.OK(res), nil
 }
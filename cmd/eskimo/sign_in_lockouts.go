@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupSignInLockoutsRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("auth/sign-in-lockouts", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetSignInLockouts))).
+		GET("auth/sign-in-attempts-per-ip", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetSignInAttemptsPerIP)))
+}
+
+// GetSignInLockouts godoc
+//
+//	@Schemes
+//	@Description	Returns the login identities (email+device) that are currently blocked or that have wrong confirmation code attempts, blocked ones first. Only for admins.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{array}		emaillink.SignInLockout
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/sign-in-lockouts [GET].
+func (s *service) GetSignInLockouts( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetSignInLockoutsArg, []*emaillink.SignInLockout],
+) (*server.Response[[]*emaillink.SignInLockout], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultSignInLockoutsLimit
+	}
+	res, err := s.iceClient.GetSignInLockouts(ctx, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get sign in lockouts for %#v", req.Data))
+	}
+	if res == nil {
+		res = []*emaillink.SignInLockout{}
+	}
+
+	return server.OK(&res), nil
+}
+
+// GetSignInAttemptsPerIP godoc
+//
+//	@Schemes
+//	@Description	Returns the login attempts per IP, per hourly window, most recent windows first. Only for admins.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			minAttempts			query		uint64	false	"Minimum login attempts of the IPs to return"
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{array}		emaillink.IPSignInAttempts
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/auth/sign-in-attempts-per-ip [GET].
+func (s *service) GetSignInAttemptsPerIP( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetSignInAttemptsPerIPArg, []*emaillink.IPSignInAttempts],
+) (*server.Response[[]*emaillink.IPSignInAttempts], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultSignInLockoutsLimit
+	}
+	res, err := s.iceClient.GetSignInAttemptsPerIP(ctx, req.Data.MinAttempts, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get sign in attempts per ip for %#v", req.Data))
+	}
+	if res == nil {
+		res = []*emaillink.IPSignInAttempts{}
+	}
+
+	return server.OK(&res), nil
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupUnderageUsersRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("underage-users", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetUnderageUsers)))
+}
+
+// GetUnderageUsers godoc
+//
+//	@Schemes
+//	@Description	Returns the users found to be younger than the minimum age of their country, as per their date of birth, the pending deletions first, ordered by when they're due. Only for admins.
+//	@Description	They're blocked as soon as they're found and deleted after `ageVerification.deletionDelay`.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{array}		users.UnderageUser
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/underage-users [GET].
+func (s *service) GetUnderageUsers( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetUnderageUsersArg, []*users.UnderageUser],
+) (*server.Response[[]*users.UnderageUser], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultUnderageUsersLimit
+	}
+	res, err := s.usersRepository.GetUnderageUsers(ctx, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get underage users for %#v", req.Data))
+	}
+	if res == nil {
+		res = []*users.UnderageUser{}
+	}
+
+	return server.OK(&res), nil
+}
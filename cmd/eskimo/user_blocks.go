@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupUserBlocksRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("users/:userId/blocks", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Profiles, s.GetUserBlocks)))
+}
+
+// GetUserBlocks godoc
+//
+//	@Schemes
+//	@Description	Returns the users blocked by the user, the most recently blocked first. Only for the user itself.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{array}		users.UserBlock
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/blocks [GET].
+func (s *service) GetUserBlocks( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetUserBlocksArg, []*users.UserBlock],
+) (*server.Response[[]*users.UserBlock], *server.Response[server.ErrorResponse]) {
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultUserBlocksLimit
+	}
+	res, err := s.usersRepository.GetUserBlocks(ctx, req.Data.UserID, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get user blocks for %#v", req.Data))
+	}
+	if res == nil {
+		res = []*users.UserBlock{}
+	}
+
+	return server.OK(&res), nil
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/cmd/params"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (s *service) setupUserEventsRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("user-events", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetUserEvents)))
+}
+
+// GetUserEvents godoc
+//
+//	@Schemes
+//	@Description	Returns the recorded lifecycle events of the users (created, modified fields, deleted, kyc transitions), the latest first. Only for admins.
+//	@Description	They're kept regardless of the retention of the message broker, so that they can be investigated later.
+//	@Tags			Maintenance
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				query		string	false	"ID of the user to return the events of"
+//	@Param			eventType			query		string	false	"type of the events to return"	Enums(created,modified,deleted,anonymized,merged,kycStepPassed,kycStepBlocked)
+//	@Param			from				query		string	false	"RFC3339 lower bound of the time of the events. Defaults to 7 days before `to`"
+//	@Param			to					query		string	false	"RFC3339 upper bound of the time of the events. Defaults to now"
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 100"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{array}		users.UserEvent
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/user-events [GET].
+func (s *service) GetUserEvents( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetUserEventsArg, []*users.UserEvent],
+) (*server.Response[[]*users.UserEvent], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if errResp := req.Data.validate(); errResp != nil {
+		return nil, errResp
+	}
+	from, to, err := req.Data.timeRange()
+	if err != nil {
+		err = errors.Wrapf(err, "invalid time range for %#v", req.Data)
+
+		return nil, server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "from", "to"))
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultUserEventsLimit
+	}
+	res, err := s.usersRepository.GetUserEvents(ctx, req.Data.UserID, req.Data.EventType, from, to, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get user events for %#v", req.Data))
+	}
+	if res == nil {
+		res = []*users.UserEvent{}
+	}
+
+	return server.OK(&res), nil
+}
+
+func (a *GetUserEventsArg) validate() *server.Response[server.ErrorResponse] {
+	if a.EventType == "" {
+		return nil
+	}
+	for _, eventType := range users.UserEventTypes {
+		if eventType == a.EventType {
+			return nil
+		}
+	}
+	err := errors.Errorf("eventType '%v' is invalid, valid values are %#v", a.EventType, users.UserEventTypes)
+
+	return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "eventType"))
+}
+
+func (a *GetUserEventsArg) timeRange() (from, to *time.Time, err error) {
+	return params.TimeRange(a.From, a.To, defaultUserEventsTimeRange, 0) //nolint:wrapcheck // Nothing to add.
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupUserImportBatchesRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("user-import-batches/:batchId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetUserImportBatch)))
+}
+
+// GetUserImportBatch godoc
+//
+//	@Schemes
+//	@Description	Returns the progress of an user import batch, with the lines that were invalid or couldn't be imported. It's the final report once `finishedAt` is set. Only for admins.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			batchId				path		string	true	"ID of the batch"
+//	@Success		200					{object}	users.UserImportBatchReport
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/user-import-batches/{batchId} [GET].
+func (s *service) GetUserImportBatch( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetUserImportBatchArg, users.UserImportBatchReport],
+) (*server.Response[users.UserImportBatchReport], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	report, err := s.usersRepository.GetUserImportBatch(ctx, req.Data.BatchID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get user import batch %v", req.Data.BatchID)
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, server.NotFound(err, userImportBatchNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK(report), nil
+}
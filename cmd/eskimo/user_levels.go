@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupUserLevelsRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("users/:userId/level", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Profiles, s.GetUserLevel)))
+}
+
+// GetUserLevel godoc
+//
+//	@Schemes
+//	@Description	Returns the level of an user and, only for the user itself, its progress towards the next one.
+//	@Description	The level isn't returned for the other users, if the user hid it.
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Success		200					{object}	users.UserLevel
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		404					{object}	server.ErrorResponse	"if not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/level [GET].
+func (s *service) GetUserLevel( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetUserLevelArg, users.UserLevel],
+) (*server.Response[users.UserLevel], *server.Response[server.ErrorResponse]) {
+	level, err := s.usersRepository.GetUserLevel(ctx, req.Data.UserID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get the level of userID:%v", req.Data.UserID)
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, server.NotFound(err, userNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(err)
+	}
+
+	return server.OK(level), nil
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupUserReportsRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("reported-users", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetReportedUsers))).
+		GET("users/:userId/reports", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetUserReports)))
+}
+
+// GetReportedUsers godoc
+//
+//	@Schemes
+//	@Description	Returns the moderation queue: the users with pending reports, the ones flagged for review first, then the most reported ones. Only for admins.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			flaggedOnly			query		bool	false	"Whether to return only the users flagged for review"
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{array}		users.ReportedUser
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/reported-users [GET].
+func (s *service) GetReportedUsers( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetReportedUsersArg, []*users.ReportedUser],
+) (*server.Response[[]*users.ReportedUser], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultReportedUsersLimit
+	}
+	res, err := s.usersRepository.GetReportedUsers(ctx, req.Data.FlaggedOnly, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get reported users for %#v", req.Data))
+	}
+	if res == nil {
+		res = []*users.ReportedUser{}
+	}
+
+	return server.OK(&res), nil
+}
+
+// GetUserReports godoc
+//
+//	@Schemes
+//	@Description	Returns the reports of an user, the pending ones first, then the most recent ones. Only for admins.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the reported user"
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{array}		users.UserReport
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/reports [GET].
+func (s *service) GetUserReports( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetUserReportsArg, []*users.UserReport],
+) (*server.Response[[]*users.UserReport], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultUserReportsLimit
+	}
+	res, err := s.usersRepository.GetUserReports(ctx, req.Data.UserID, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get user reports for %#v", req.Data))
+	}
+	if res == nil {
+		res = []*users.UserReport{}
+	}
+
+	return server.OK(&res), nil
+}
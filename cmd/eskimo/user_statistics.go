@@ -4,34 +4,47 @@ package main
 
 import (
 	"context"
+	"net/http"
 	stdlibtime "time"
 
 	"github.com/pkg/errors"
 
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/cmd/params"
 	"github.com/ice-blockchain/eskimo/users"
 	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/time"
 )
 
 func (s *service) setupUserStatisticsRoutes(router *server.Router) {
 	router.
 		Group("v1r").
-		GET("user-statistics/top-countries", server.RootHandler(s.GetTopCountries)).
-		GET("user-statistics/user-growth", server.RootHandler(s.GetUserGrowth))
+		GET("user-statistics/top-countries", s.compressResponse, server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Statistics, s.GetTopCountries))).
+		GET("user-statistics/user-growth", s.compressResponse, server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Statistics, s.GetUserGrowth))).
+		GET("user-statistics/kyc-funnel", s.compressResponse, server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Statistics, s.GetKYCFunnel))).
+		GET("user-statistics/email-domains", s.compressResponse, server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Statistics, s.GetEmailDomainStatistics)))
 }
 
 // GetTopCountries godoc
 //
 //	@Schemes
-//	@Description	Returns the paginated view of users per country.
+//	@Description	Returns the paginated view of users per country, ranked among all of them.
+//	@Description	If `includeOwnCountry` is true, the country of the authenticated user is flagged as `own` and, if it's not in the requested page, it's appended to it.
+//	@Description	If `paginated` is true, they're returned as a `Page`, with `items`, `total`, `hasMore` and `nextCursor`, rather than as just the list.
 //	@Tags			Statistics
 //	@Accept			json
 //	@Produce		json
 //	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
 //	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			If-Modified-Since	header		string	false	"Last-Modified value of a previous response"	default(Wed, 21 Oct 2015 07:28:00 GMT)
 //	@Param			keyword				query		string	false	"a keyword to look for in all country codes or names"
 //	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
 //	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Param			includeOwnCountry	query		bool	false	"if true, it includes the country of the authenticated user too"
+//	@Param			paginated			query		bool	false	"if true, it returns a `Page` rather than just the list"
 //	@Success		200					{array}		users.CountryStatistics
+//	@Success		304					"if not modified since the provided If-Modified-Since"
 //	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
 //	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
@@ -40,17 +53,23 @@ func (s *service) setupUserStatisticsRoutes(router *server.Router) {
 //	@Router			/user-statistics/top-countries [GET].
 func (s *service) GetTopCountries( //nolint:gocritic // False negative.
 	ctx context.Context,
-	req *server.Request[GetTopCountriesArg, []*users.CountryStatistics],
-) (*server.Response[[]*users.CountryStatistics], *server.Response[server.ErrorResponse]) {
-	if req.Data.Limit == 0 {
-		req.Data.Limit = 10
+	req *server.Request[GetTopCountriesArg, Page[*users.CountryStatistics]],
+) (*server.Response[Page[*users.CountryStatistics]], *server.Response[server.ErrorResponse]) {
+	req.Data.Limit = params.Capped(req.Data.Limit, defaultTopCountriesLimit, cfg.MaxResponseItems)
+	var ownUserID users.UserID
+	if req.Data.IncludeOwnCountry {
+		ownUserID = req.AuthenticatedUser.UserID
 	}
-	result, err := s.usersRepository.GetTopCountries(ctx, req.Data.Keyword, req.Data.Limit, req.Data.Offset)
+	result, total, lastUpdatedAt, err := s.usersRepository.GetTopCountries(ctx, req.Data.Keyword, ownUserID, req.Data.Limit, req.Data.Offset)
 	if err != nil {
 		return nil, server.Unexpected(errors.Wrapf(err, "failed to get top countries for: %#v", req.Data))
 	}
+	page := &Page[*users.CountryStatistics]{Items: result, Total: &total, enveloped: req.Data.Paginated}
+	if req.Data.Offset+req.Data.Limit < total { // The own country, if appended, isn't part of the page, so it's counted rather than looked ahead.
+		page.hasMore(req.Data.Limit, req.Data.Offset)
+	}
 
-	return server.OK(&result), nil
+	return conditionalOK(page, lastUpdatedAt, req.Data.IfModifiedSince), nil
 }
 
 // GetUserGrowth godoc
@@ -62,9 +81,12 @@ func (s *service) GetTopCountries( //nolint:gocritic // False negative.
 //	@Produce		json
 //	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
 //	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			If-Modified-Since	header		string	false	"Last-Modified value of a previous response"	default(Wed, 21 Oct 2015 07:28:00 GMT)
 //	@Param			days				query		uint64	false	"number of days in the past to look for. Defaults to 3. Max is 90."
-//	@Param			tz					query		string	false	"Timezone in format +04:30 or -03:45"
+//	@Param			tz					query		string	false	"Timezone in format +04:30 or -03:45, between -12:00 and +14:00. Invalid values are rejected with 400"
+//	@Param			rollingActiveUsers	query		bool	false	"if true, it also returns the distinct users active in the last 7 (WAU) and 30 (MAU) days"
 //	@Success		200					{object}	users.UserGrowthStatistics
+//	@Success		304					"if not modified since the provided If-Modified-Since"
 //	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
 //	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
@@ -75,29 +97,118 @@ func (s *service) GetUserGrowth( //nolint:gocritic // False negative.
 	ctx context.Context,
 	req *server.Request[GetUserGrowthArg, users.UserGrowthStatistics],
 ) (*server.Response[users.UserGrowthStatistics], *server.Response[server.ErrorResponse]) {
-	const defaultDays, maxDays = 3, 90
-	if req.Data.Days == 0 {
-		req.Data.Days = defaultDays
+	req.Data.Days = params.Capped(req.Data.Days, defaultUserGrowthDays, maxUserGrowthDays)
+	tz, err := params.InvertedTZ(req.Data.TZ)
+	if err != nil {
+		return nil, server.BadRequest(errors.Wrapf(err, "invalid tz for %#v", req.Data), invalidPropertiesErrorCode,
+			errorcatalog.FieldsData(errorcatalog.InvalidReason, "tz"))
 	}
-	if req.Data.Days > maxDays {
-		req.Data.Days = maxDays
+	result, lastUpdatedAt, err := s.usersRepository.GetUserGrowth(ctx, req.Data.Days, tz)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get user growth stats for: %#v", req.Data))
 	}
-	tz := stdlibtime.UTC
-	if req.Data.TZ != "" {
-		var invertedTZ string
-		if req.Data.TZ[0] == '-' {
-			invertedTZ = "+" + req.Data.TZ[1:]
-		} else {
-			invertedTZ = "-" + req.Data.TZ[1:]
-		}
-		if t, err := stdlibtime.Parse("-07:00", invertedTZ); err == nil {
-			tz = t.Location()
+	if req.Data.RollingActiveUsers {
+		rollingActiveUsers, rErr := s.usersRepository.GetRollingActiveUsers(ctx)
+		if rErr != nil {
+			return nil, server.Unexpected(errors.Wrapf(rErr, "failed to get rolling active users for: %#v", req.Data))
 		}
+		withRollingActiveUsers := *result // The result is shared with the cache, so it's not mutated.
+		withRollingActiveUsers.RollingActiveUsers = rollingActiveUsers
+		result = &withRollingActiveUsers
+	}
+
+	return conditionalOK(result, lastUpdatedAt, req.Data.IfModifiedSince), nil
+}
+
+// GetKYCFunnel godoc
+//
+//	@Schemes
+//	@Description	Returns, per day (UTC) and per KYC step, how many users entered (attempted it for the first time), passed, failed and got blocked. Only for admins.
+//	@Tags			Statistics
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			If-Modified-Since	header		string	false	"Last-Modified value of a previous response"	default(Wed, 21 Oct 2015 07:28:00 GMT)
+//	@Param			days				query		uint64	false	"number of days in the past to look for, including the current one. Defaults to 7. Max is 90."
+//	@Success		200					{object}	users.KYCFunnelStatistics
+//	@Success		304					"if not modified since the provided If-Modified-Since"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/user-statistics/kyc-funnel [GET].
+func (s *service) GetKYCFunnel( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetKYCFunnelArg, users.KYCFunnelStatistics],
+) (*server.Response[users.KYCFunnelStatistics], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
 	}
-	result, err := s.usersRepository.GetUserGrowth(ctx, req.Data.Days, tz)
+	req.Data.Days = params.Capped(req.Data.Days, defaultKYCFunnelDays, maxKYCFunnelDays)
+	result, lastUpdatedAt, err := s.usersRepository.GetKYCFunnel(ctx, req.Data.Days)
 	if err != nil {
-		return nil, server.Unexpected(errors.Wrapf(err, "failed to get user growth stats for: %#v", req.Data))
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get kyc funnel stats for: %#v", req.Data))
+	}
+
+	return conditionalOK(result, lastUpdatedAt, req.Data.IfModifiedSince), nil
+}
+
+// GetEmailDomainStatistics godoc
+//
+//	@Schemes
+//	@Description	Returns, per day (UTC), the email domains with the most signups, for fraud monitoring. Only for admins.
+//	@Description	A domain is `anomalous` in a day if its signups spiked, compared to its `baseline`, i.e. its daily average of the previous days.
+//	@Tags			Statistics
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			If-Modified-Since	header		string	false	"Last-Modified value of a previous response"	default(Wed, 21 Oct 2015 07:28:00 GMT)
+//	@Param			days				query		uint64	false	"number of days in the past to look for, including the current one. Defaults to 7. Max is 90."
+//	@Param			limit				query		uint64	false	"Limit of domains to return per day. Defaults to 10. Max is 100."
+//	@Success		200					{object}	users.EmailDomainStatistics
+//	@Success		304					"if not modified since the provided If-Modified-Since"
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/user-statistics/email-domains [GET].
+func (s *service) GetEmailDomainStatistics( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetEmailDomainStatisticsArg, users.EmailDomainStatistics],
+) (*server.Response[users.EmailDomainStatistics], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	req.Data.Days = params.Capped(req.Data.Days, defaultEmailDomainStatisticsDays, maxEmailDomainStatisticsDays)
+	req.Data.Limit = params.Capped(req.Data.Limit, defaultEmailDomainStatisticsLimit, maxEmailDomainStatisticsLimit)
+	result, lastUpdatedAt, err := s.usersRepository.GetEmailDomainStatistics(ctx, req.Data.Days, req.Data.Limit)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get email domain stats for: %#v", req.Data))
+	}
+
+	return conditionalOK(result, lastUpdatedAt, req.Data.IfModifiedSince), nil
+}
+
+// conditionalOK returns a 304 without a body if nothing changed since `ifModifiedSince`, otherwise a 200 with the `Last-Modified` header set.
+func conditionalOK[RESP any](resp *RESP, lastUpdatedAt *time.Time, ifModifiedSince string) *server.Response[RESP] {
+	if lastUpdatedAt.IsNil() {
+		return server.OK(resp)
+	}
+	lastModified := lastUpdatedAt.UTC().Truncate(stdlibtime.Second)
+	headers := map[string]string{"Last-Modified": lastModified.Format(http.TimeFormat)}
+	if ifModifiedSince != "" {
+		if since, err := http.ParseTime(ifModifiedSince); err == nil && !lastModified.After(since) {
+			return &server.Response[RESP]{Code: http.StatusNotModified, Headers: headers}
+		}
 	}
+	okResp := server.OK(resp)
+	okResp.Headers = headers
 
-	return server.OK(result), nil
+	return okResp
 }
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+func (s *service) setupUsernameSquattingRoutes(router *server.Router) {
+	router.
+		Group("v1r").
+		GET("squatted-usernames", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Admin, s.GetSquattedUsernames)))
+}
+
+// GetSquattedUsernames godoc
+//
+//	@Schemes
+//	@Description	Returns the desirable usernames held by dormant users (never verified, never mined), the pending ones first, ordered by when they're released. Only for admins.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
+//	@Param			offset				query		uint64	false	"Number of elements to skip before collecting elements to return"
+//	@Success		200					{array}		users.SquattedUsername
+//	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/squatted-usernames [GET].
+func (s *service) GetSquattedUsernames( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetSquattedUsernamesArg, []*users.SquattedUsername],
+) (*server.Response[[]*users.SquattedUsername], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.New("not allowed"))
+	}
+	if req.Data.Limit == 0 {
+		req.Data.Limit = defaultSquattedUsernamesLimit
+	}
+	res, err := s.usersRepository.GetSquattedUsernames(ctx, req.Data.Limit, req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get squatted usernames for %#v", req.Data))
+	}
+	if res == nil {
+		res = []*users.SquattedUsername{}
+	}
+
+	return server.OK(&res), nil
+}
@@ -4,10 +4,14 @@ package main
 
 import (
 	"context"
+	"net/http"
 	"strings"
 
 	"github.com/pkg/errors"
 
+	"github.com/ice-blockchain/eskimo/cmd/deadline"
+	"github.com/ice-blockchain/eskimo/cmd/errorcatalog"
+	"github.com/ice-blockchain/eskimo/cmd/params"
 	"github.com/ice-blockchain/eskimo/users"
 	"github.com/ice-blockchain/wintr/server"
 )
@@ -15,49 +19,115 @@ import (
 func (s *service) setupUserRoutes(router *server.Router) {
 	router.
 		Group("v1r").
-		GET("users", server.RootHandler(s.GetUsers)).
-		GET("users/:userId", server.RootHandler(s.GetUserByID)).
-		GET("user-views/username", server.RootHandler(s.GetUserByUsername))
+		GET("users", s.compressResponse, server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Profiles, s.GetUsers))).
+		GET("users/:userId", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Profiles, s.GetUserByID))).
+		GET("users/:userId/changes", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.LongPolls, s.GetProfileChanges))).
+		GET("user-views/username", server.RootHandler(deadline.Budget(cfg.RouteTimeouts.Profiles, s.GetUserByUsername)))
 }
 
 // GetUsers godoc
 //
 //	@Schemes
 //	@Description	Returns a list of user account based on the provided query parameters.
+//	@Description	When searching by `username`, each result has a `searchMatch`, with where the keyword matched, so it can be highlighted.
+//	@Description	If the search index is enabled, the usernames can also match approximately (e.g. with typos), in which case there's no `searchMatch`.
+//	@Description	If `paginated` is true, they're returned as a `Page`, with `items`, `hasMore` and `nextCursor`, rather than as just the list.
 //	@Tags			Accounts
 //	@Accept			json
 //	@Produce		json
 //	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
 //	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
-//	@Param			keyword				query		string	true	"A keyword to look for in the usernames"
+//	@Param			keyword				query		string	true	"A keyword to look for in the usernames (or in the emails/phone numbers, see `searchBy`)"
+//	@Param			searchBy			query		string	false	"What to search by. Defaults to `username`. `email` and `phoneNumber` (which matches the phone number hashes as well) are only for admins"	Enums(username,email,phoneNumber)
+//	@Param			searchMode			query		string	false	"How to match the keyword, if `searchBy` is not `username`. Defaults to `exact`"	Enums(exact,prefix)
+//	@Param			reason				query		string	false	"Why the search is needed (for ex the support ticket). Required if `searchBy` is not `username`, because those searches are audited"
 //	@Param			limit				query		uint64	false	"Limit of elements to return. Defaults to 10"
 //	@Param			offset				query		uint64	false	"Elements to skip before starting to look for"
+//	@Param			paginated			query		bool	false	"if true, it returns a `Page` rather than just the list"
 //	@Success		200					{array}		users.MinimalUserProfile
 //	@Failure		400					{object}	server.ErrorResponse	"if validations fail"
 //	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if searching by something other than `username` without being an admin"
 //	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
 //	@Failure		500					{object}	server.ErrorResponse
 //	@Failure		504					{object}	server.ErrorResponse	"if request times out"
 //	@Router			/users [GET].
 func (s *service) GetUsers( //nolint:gocritic // False negative.
 	ctx context.Context,
-	req *server.Request[GetUsersArg, []*users.MinimalUserProfile],
-) (*server.Response[[]*users.MinimalUserProfile], *server.Response[server.ErrorResponse]) {
+	req *server.Request[GetUsersArg, Page[*users.MinimalUserProfile]],
+) (*server.Response[Page[*users.MinimalUserProfile]], *server.Response[server.ErrorResponse]) {
+	if req.Data.SearchBy != "" && req.Data.SearchBy != usernameSearchBy {
+		return s.searchUsers(ctx, req)
+	}
 	key := string(everythingNotAllowedInUsernamePattern.ReplaceAll([]byte(strings.ToLower(req.Data.Keyword)), []byte("")))
 	if key == "" || !strings.EqualFold(key, req.Data.Keyword) {
 		err := errors.Errorf("username: %v is invalid, it should match regex: %v", req.Data.Keyword, everythingNotAllowedInUsernamePattern)
 
-		return nil, server.BadRequest(err, invalidKeywordErrorCode)
-	}
-	if req.Data.Limit == 0 {
-		req.Data.Limit = 10
+		return nil, server.BadRequest(err, invalidKeywordErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "keyword"))
 	}
-	resp, err := s.usersRepository.GetUsers(ctx, req.Data.Keyword, req.Data.Limit, req.Data.Offset)
+	req.Data.Limit = params.Capped(req.Data.Limit, defaultUsersLimit, cfg.MaxResponseItems)
+	resp, err := s.usersRepository.GetUsers(ctx, req.Data.Keyword, lookahead(req.Data.Limit, req.Data.Paginated), req.Data.Offset)
 	if err != nil {
 		return nil, server.Unexpected(errors.Wrapf(err, "failed to get users by %#v", req.Data))
 	}
 
-	return server.OK(&resp), nil
+	return server.OK(pageOf(resp, req.Data.Limit, req.Data.Offset, req.Data.Paginated)), nil
+}
+
+func (s *service) searchUsers( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetUsersArg, Page[*users.MinimalUserProfile]],
+) (*server.Response[Page[*users.MinimalUserProfile]], *server.Response[server.ErrorResponse]) {
+	if req.AuthenticatedUser.Role != adminRole {
+		return nil, server.Forbidden(errors.Errorf("searching by `%v` is not allowed", req.Data.SearchBy))
+	}
+	search := &users.UserSearch{
+		AdminUserID: req.AuthenticatedUser.UserID,
+		Field:       users.UserSearchField(req.Data.SearchBy),
+		Mode:        users.UserSearchMode(req.Data.SearchMode),
+		Keyword:     strings.TrimSpace(req.Data.Keyword),
+		Reason:      strings.TrimSpace(req.Data.Reason),
+	}
+	if search.Mode == "" {
+		search.Mode = users.ExactUserSearchMode
+	}
+	if err := validateUserSearch(search); err != nil {
+		return nil, err
+	}
+	req.Data.Limit = params.Capped(req.Data.Limit, defaultUsersLimit, cfg.MaxResponseItems)
+	resp, err := s.usersRepository.SearchUsers(ctx, search, lookahead(req.Data.Limit, req.Data.Paginated), req.Data.Offset)
+	if err != nil {
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to search users by %#v", req.Data))
+	}
+
+	return server.OK(pageOf(resp, req.Data.Limit, req.Data.Offset, req.Data.Paginated)), nil
+}
+
+func validateUserSearch(search *users.UserSearch) *server.Response[server.ErrorResponse] {
+	if search.Field != users.EmailUserSearchField && search.Field != users.PhoneNumberUserSearchField {
+		err := errors.Errorf("invalid searchBy `%v`, valid values are %v", search.Field,
+			[]string{usernameSearchBy, string(users.EmailUserSearchField), string(users.PhoneNumberUserSearchField)})
+
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "searchBy"))
+	}
+	if search.Mode != users.ExactUserSearchMode && search.Mode != users.PrefixUserSearchMode {
+		err := errors.Errorf("invalid searchMode `%v`, valid values are %v", search.Mode,
+			[]users.UserSearchMode{users.ExactUserSearchMode, users.PrefixUserSearchMode})
+
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "searchMode"))
+	}
+	if search.Keyword == "" || search.Reason == "" {
+		err := errors.New("keyword and reason are required")
+
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.RequiredReason, "keyword", "reason"))
+	}
+	if search.Mode == users.PrefixUserSearchMode && len(search.Keyword) < minUserSearchPrefixLength {
+		err := errors.Errorf("keyword must have at least %v characters for prefix searches", minUserSearchPrefixLength)
+
+		return server.UnprocessableEntity(err, invalidPropertiesErrorCode, errorcatalog.FieldsData(errorcatalog.TooShortReason, "keyword"))
+	}
+
+	return nil
 }
 
 // GetUserByID godoc
@@ -97,6 +167,48 @@ func (s *service) GetUserByID( //nolint:gocritic // False negative.
 	return server.OK(&User{UserProfile: usr, Checksum: usr.Checksum()}), nil
 }
 
+// GetProfileChanges godoc
+//
+//	@Schemes
+//	@Description	Long-polls the changes of the user's own profile, so that it doesn't need to be fetched again every time the app is foregrounded.
+//	@Description	It waits until the checksum of the profile differs from `sinceChecksum`, for at most the configured timeout, and responds with 204 if it doesn't.
+//	@Description	Otherwise, it returns only the fields that changed since the last response of this endpoint, if that's the `sinceChecksum` version,
+//	@Description	or all of them, with `full` set, if it's not (e.g. the first call, without `sinceChecksum`).
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization		header		string	true	"Insert your access token"		default(Bearer <Add access token here>)
+//	@Param			X-Account-Metadata	header		string	false	"Insert your metadata token"	default(<Add metadata token here>)
+//	@Param			userId				path		string	true	"ID of the user"
+//	@Param			sinceChecksum		query		string	false	"the checksum of the last version of the profile known by the client"
+//	@Success		200					{object}	users.ProfileChanges
+//	@Success		204					"if the profile didn't change"
+//	@Failure		401					{object}	server.ErrorResponse	"if not authorized"
+//	@Failure		403					{object}	server.ErrorResponse	"if not allowed"
+//	@Failure		404					{object}	server.ErrorResponse	"if not found"
+//	@Failure		422					{object}	server.ErrorResponse	"if syntax fails"
+//	@Failure		500					{object}	server.ErrorResponse
+//	@Failure		504					{object}	server.ErrorResponse	"if request times out"
+//	@Router			/users/{userId}/changes [GET].
+func (s *service) GetProfileChanges( //nolint:gocritic // False negative.
+	ctx context.Context,
+	req *server.Request[GetProfileChangesArg, users.ProfileChanges],
+) (*server.Response[users.ProfileChanges], *server.Response[server.ErrorResponse]) {
+	changes, err := s.usersRepository.GetProfileChanges(ctx, req.Data.UserID, req.Data.SinceChecksum, cfg.ProfileChangesLongPollTimeout)
+	if err != nil {
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, server.NotFound(errors.Wrapf(err, "user with id `%v` was not found", req.Data.UserID), userNotFoundErrorCode)
+		}
+
+		return nil, server.Unexpected(errors.Wrapf(err, "failed to get profile changes for %#v", req.Data))
+	}
+	if changes == nil {
+		return &server.Response[users.ProfileChanges]{Code: http.StatusNoContent}, nil
+	}
+
+	return server.OK(changes), nil
+}
+
 // GetUserByUsername godoc
 //
 //	@Schemes
@@ -122,7 +234,7 @@ func (s *service) GetUserByUsername( //nolint:gocritic // False negative.
 	if !users.CompiledUsernameRegex.MatchString(req.Data.Username) {
 		err := errors.Errorf("username: %v is invalid, it should match regex: %v", req.Data.Username, users.UsernameRegex)
 
-		return nil, server.BadRequest(err, invalidUsernameErrorCode)
+		return nil, server.BadRequest(err, invalidUsernameErrorCode, errorcatalog.FieldsData(errorcatalog.InvalidReason, "username"))
 	}
 
 	resp, err := s.usersRepository.GetUserByUsername(ctx, strings.ToLower(req.Data.Username))
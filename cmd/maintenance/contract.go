@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package maintenance
+
+import (
+	"context"
+
+	"github.com/ice-blockchain/eskimo/users"
+)
+
+// Public API.
+
+type (
+	Repository interface {
+		GetMaintenanceMode(ctx context.Context) (*users.MaintenanceMode, error)
+	}
+)
+
+// Private API.
+
+const (
+	apiPathPrefix   = "/v1"
+	defaultLanguage = "en"
+
+	authorizationHeader    = "Authorization"
+	xAccountMetadataHeader = "X-Account-Metadata"
+	acceptLanguageHeader   = "Accept-Language"
+	retryAfterHeader       = "Retry-After"
+)
+
+// Values for server.ErrorResponse#Code.
+const (
+	maintenanceModeErrorCode = "MAINTENANCE_MODE"
+)
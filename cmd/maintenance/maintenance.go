@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package maintenance
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// Middleware fails all the API calls with 503 while the maintenance mode is enabled, except the ones of the allowlisted users
+// and the ones to the exempted paths (e.g. the ones managing the maintenance mode). The other routes, like the health check, are not affected.
+// If the maintenance mode can't be loaded, the calls are served, so that a database hiccup doesn't take the whole API down.
+func Middleware(repo Repository, exemptedPaths ...string) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		if !strings.HasPrefix(ginCtx.FullPath(), apiPathPrefix) || slices.Contains(exemptedPaths, ginCtx.FullPath()) {
+			return
+		}
+		ctx := ginCtx.Request.Context()
+		mode, err := repo.GetMaintenanceMode(ctx)
+		if err != nil {
+			log.Error(errors.Wrapf(err, "failed to GetMaintenanceMode for endpoint %v", ginCtx.FullPath()))
+
+			return
+		}
+		if !mode.Enabled || isAllowlisted(ctx, ginCtx, mode.Allowlist) {
+			return
+		}
+		if mode.ETA != nil {
+			if untilETA := mode.ETA.Sub(*time.Now().Time); untilETA > 0 {
+				ginCtx.Header(retryAfterHeader, strconv.Itoa(int(math.Ceil(untilETA.Seconds()))))
+			}
+		}
+		errResp := server.BadRequest(errors.New("maintenance mode is enabled"), maintenanceModeErrorCode, Data(mode, ginCtx.GetHeader(acceptLanguageHeader)))
+		errResp.Code = http.StatusServiceUnavailable
+		ginCtx.AbortWithStatusJSON(errResp.Code, errResp.Data)
+	}
+}
+
+func isAllowlisted(ctx context.Context, ginCtx *gin.Context, allowlist []users.UserID) bool {
+	authorization := strings.TrimPrefix(ginCtx.GetHeader(authorizationHeader), "Bearer ")
+	if len(allowlist) == 0 || authorization == "" {
+		return false
+	}
+	token, err := server.Auth(ctx).VerifyToken(ctx, authorization)
+	if err != nil {
+		return false
+	}
+	if token, err = server.Auth(ctx).ModifyTokenWithMetadata(token, ginCtx.GetHeader(xAccountMetadataHeader)); err != nil {
+		return false
+	}
+
+	return slices.Contains(allowlist, token.UserID)
+}
+
+// Data is the payload of the maintenance mode error responses: the ETA, if known, and the message in the first language,
+// of the `Accept-Language` header, that has one (English otherwise).
+func Data(mode *users.MaintenanceMode, acceptLanguage string) map[string]any {
+	data := map[string]any{"message": message(mode.Messages, acceptLanguage)}
+	if mode.ETA != nil {
+		data["eta"] = mode.ETA
+	}
+
+	return data
+}
+
+func message(messages map[string]string, acceptLanguage string) string {
+	for _, languageRange := range strings.Split(acceptLanguage, ",") {
+		language, _, _ := strings.Cut(languageRange, ";")
+		language, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(language)), "-")
+		if msg, found := messages[language]; found && language != "" {
+			return msg
+		}
+	}
+
+	return messages[defaultLanguage]
+}
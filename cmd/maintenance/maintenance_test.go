@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package maintenance
+
+import (
+	"testing"
+	stdlibtime "time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func TestMessage(t *testing.T) {
+	t.Parallel()
+	messages := map[string]string{"en": "We'll be back soon!", "ro": "Revenim curand!"}
+	assert.Equal(t, "We'll be back soon!", message(messages, ""))
+	assert.Equal(t, "Revenim curand!", message(messages, "ro"))
+	assert.Equal(t, "Revenim curand!", message(messages, "ro-RO"))
+	assert.Equal(t, "Revenim curand!", message(messages, "fr-CH, fr;q=0.9, ro;q=0.8, en;q=0.7"))
+	assert.Equal(t, "We'll be back soon!", message(messages, "fr-CH, fr;q=0.9"))
+	assert.Empty(t, message(nil, "en"))
+}
+
+func TestData(t *testing.T) {
+	t.Parallel()
+	mode := &users.MaintenanceMode{Messages: map[string]string{"en": "We'll be back soon!"}, Enabled: true}
+	assert.Equal(t, map[string]any{"message": "We'll be back soon!"}, Data(mode, "de"))
+
+	mode.ETA = time.New(stdlibtime.Date(2024, 1, 1, 0, 0, 0, 0, stdlibtime.UTC))
+	assert.Equal(t, map[string]any{"message": "We'll be back soon!", "eta": mode.ETA}, Data(mode, "de"))
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package openapi
+
+import (
+	"mime/multipart"
+	"reflect"
+	stdlibtime "time"
+
+	"github.com/ice-blockchain/wintr/server"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// Public API.
+
+const (
+	Version = "3.1.0"
+	Path    = "/openapi.json"
+)
+
+type (
+	// Route describes the request and the response of a route registered in the router.
+	// Request and Response are zero values of the types used in server.Request[REQ,RESP], e.g. `new(users.User)`.
+	// A nil Response means the route has no response body.
+	// A non nil RateLimit is documented as the `x-rateLimit` extension of the operation, along with the headers that report it.
+	Route struct {
+		Request     any
+		Response    any
+		RateLimit   *RateLimit
+		Method      string
+		Path        string
+		Name        string
+		Summary     string
+		Tags        []string
+		SuccessCode int
+	}
+	// RateLimit is the number of calls a caller can make to the route in each window.
+	RateLimit struct {
+		Limit         uint64 `json:"limit"`
+		WindowSeconds uint64 `json:"windowSeconds"`
+	}
+	// Enum is the list of allowed values of a type. Build it with EnumOf.
+	Enum struct {
+		typ    reflect.Type
+		values []any
+	}
+	Document struct {
+		Paths      map[string]map[string]*Operation `json:"paths"`
+		Components *Components                      `json:"components"`
+		Info       *Info                            `json:"info"`
+		OpenAPI    string                           `json:"openapi"`
+		Servers    []*Server                        `json:"servers,omitempty"`
+	}
+	Info struct {
+		Title       string `json:"title"`
+		Description string `json:"description,omitempty"`
+		Version     string `json:"version"`
+	}
+	Server struct {
+		URL string `json:"url"`
+	}
+	Components struct {
+		Schemas         map[string]*Schema         `json:"schemas"`
+		SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+	}
+	SecurityScheme struct {
+		Type         string `json:"type"`
+		Scheme       string `json:"scheme,omitempty"`
+		BearerFormat string `json:"bearerFormat,omitempty"`
+	}
+	Operation struct {
+		RequestBody *RequestBody          `json:"requestBody,omitempty"`
+		RateLimit   *RateLimit            `json:"x-rateLimit,omitempty"`
+		Responses   map[string]*Response  `json:"responses"`
+		OperationID string                `json:"operationId"`
+		Summary     string                `json:"summary,omitempty"`
+		Tags        []string              `json:"tags,omitempty"`
+		Parameters  []*Parameter          `json:"parameters,omitempty"`
+		Security    []map[string][]string `json:"security"`
+	}
+	Parameter struct {
+		Schema   *Schema `json:"schema"`
+		Name     string  `json:"name"`
+		In       string  `json:"in"`
+		Required bool    `json:"required,omitempty"`
+	}
+	RequestBody struct {
+		Content  map[string]*MediaType `json:"content"`
+		Required bool                  `json:"required"`
+	}
+	Response struct {
+		Content     map[string]*MediaType `json:"content,omitempty"`
+		Headers     map[string]*Header    `json:"headers,omitempty"`
+		Description string                `json:"description"`
+	}
+	Header struct {
+		Schema      *Schema `json:"schema"`
+		Description string  `json:"description,omitempty"`
+	}
+	MediaType struct {
+		Schema *Schema `json:"schema"`
+	}
+	Schema struct {
+		Items                *Schema            `json:"items,omitempty"`
+		AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+		Properties           map[string]*Schema `json:"properties,omitempty"`
+		Maximum              *float64           `json:"maximum,omitempty"`
+		Minimum              *float64           `json:"minimum,omitempty"`
+		Ref                  string             `json:"$ref,omitempty"`
+		Type                 string             `json:"type,omitempty"`
+		Format               string             `json:"format,omitempty"`
+		Enum                 []any              `json:"enum,omitempty"`
+		Examples             []any              `json:"examples,omitempty"`
+		Required             []string           `json:"required,omitempty"`
+	}
+)
+
+// Private API.
+
+const (
+	bearerSecurityScheme   = "bearerAuth"
+	errorResponseReference = "#/components/schemas/server.ErrorResponse"
+	jsonContentType        = "application/json"
+	multipartContentType   = "multipart/form-data"
+
+	rateLimitLimitHeader     = "X-RateLimit-Limit"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+//nolint:gochecknoglobals // They're just the types we need to compare against.
+var (
+	wintrTimeType  = reflect.TypeOf(time.Time{})
+	stdlibTimeType = reflect.TypeOf(stdlibtime.Time{})
+	fileType       = reflect.TypeOf(multipart.FileHeader{})
+	errorType      = reflect.TypeOf(server.ErrorResponse{})
+)
+
+type (
+	generator struct {
+		schemas map[string]*Schema
+		names   map[reflect.Type]string
+		enums   map[reflect.Type][]any
+	}
+)
@@ -0,0 +1,412 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package openapi
+
+import (
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// EnumOf registers the allowed values of T, so that every field, parameter or item of type T gets them as `enum`.
+func EnumOf[T any](values ...T) *Enum {
+	enum := &Enum{typ: reflect.TypeOf((*T)(nil)).Elem(), values: make([]any, 0, len(values))}
+	for _, value := range values {
+		enum.values = append(enum.values, value)
+	}
+
+	return enum
+}
+
+// Generate builds the document for all the routes registered in the router.
+// Routes that are not described are still documented, but without request/response schemas.
+func Generate(info *Info, registered gin.RoutesInfo, described []*Route, enums ...*Enum) *Document {
+	gen := &generator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+		enums:   make(map[reflect.Type][]any, len(enums)),
+	}
+	for _, enum := range enums {
+		gen.enums[enum.typ] = enum.values
+	}
+	gen.schema(errorType)
+	routes := make(map[string]*Route, len(described))
+	for _, route := range described {
+		routes[route.Method+" "+strings.TrimPrefix(route.Path, "/")] = route
+	}
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]map[string]*Operation, len(registered)),
+		Components: &Components{
+			Schemas: gen.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerSecurityScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+	for _, info := range registered {
+		if info.Path == Path {
+			continue
+		}
+		route, found := routes[info.Method+" "+strings.TrimPrefix(info.Path, "/")]
+		if !found {
+			route = &Route{Method: info.Method, Path: info.Path}
+		}
+		oasPath := toOpenAPIPath(info.Path)
+		if doc.Paths[oasPath] == nil {
+			doc.Paths[oasPath] = make(map[string]*Operation, 1)
+		}
+		doc.Paths[oasPath][strings.ToLower(info.Method)] = gen.operation(route)
+	}
+
+	return doc
+}
+
+// Handler serves the document, which is marshaled only once.
+func Handler(doc *Document) gin.HandlerFunc {
+	body, err := json.Marshal(doc)
+	log.Panic(errors.Wrap(err, "failed to marshal openapi document")) //nolint:revive // Nope.
+
+	return func(ginCtx *gin.Context) {
+		ginCtx.Data(http.StatusOK, jsonContentType, body)
+	}
+}
+
+//nolint:gochecknoglobals // They're just the compiled regexes.
+var (
+	ginPathParamRegex         = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+	typeArgsPackagePathRegex  = regexp.MustCompile(`[A-Za-z0-9_.\-]*/`)
+	invalidComponentNameRegex = regexp.MustCompile(`[^A-Za-z0-9._]+`)
+)
+
+func toOpenAPIPath(ginPath string) string {
+	return ginPathParamRegex.ReplaceAllString("/"+strings.TrimPrefix(ginPath, "/"), "{$1}")
+}
+
+func (g *generator) operation(route *Route) *Operation {
+	op := &Operation{
+		OperationID: route.Name,
+		Summary:     route.Summary,
+		Tags:        route.Tags,
+		Responses:   make(map[string]*Response, 2), //nolint:gomnd // Success and error.
+		Security:    []map[string][]string{{bearerSecurityScheme: {}}},
+	}
+	if op.OperationID == "" {
+		op.OperationID = strings.ToLower(route.Method) + strings.ReplaceAll("/"+strings.Trim(ginPathParamRegex.ReplaceAllString(route.Path, "$1"), "/"), "/", "_")
+	}
+	if route.Request != nil {
+		if g.request(op, route.Method, reflect.TypeOf(route.Request)) {
+			op.Security = []map[string][]string{}
+		}
+	}
+	g.addMissingPathParameters(op, route.Path)
+	successCode := route.SuccessCode
+	if successCode == 0 {
+		successCode = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(successCode)}
+	if route.Response != nil {
+		success.Content = map[string]*MediaType{jsonContentType: {Schema: g.schema(reflect.TypeOf(route.Response))}}
+	}
+	op.Responses[strconv.Itoa(successCode)] = success
+	op.Responses["default"] = &Response{
+		Description: "Error",
+		Content:     map[string]*MediaType{jsonContentType: {Schema: &Schema{Ref: errorResponseReference}}},
+	}
+	if route.RateLimit != nil {
+		op.RateLimit = route.RateLimit
+		for _, resp := range op.Responses {
+			resp.Headers = rateLimitHeaders()
+		}
+	}
+
+	return op
+}
+
+// rateLimitHeaders are the headers every response of a rate limited route has, so that the clients can back off before they hit the limit.
+func rateLimitHeaders() map[string]*Header {
+	return map[string]*Header{
+		rateLimitLimitHeader:     {Description: "The number of calls allowed in the current window.", Schema: &Schema{Type: "integer"}},
+		rateLimitRemainingHeader: {Description: "The number of calls left in the current window.", Schema: &Schema{Type: "integer"}},
+		rateLimitResetHeader:     {Description: "When the current window ends, as Unix time in seconds.", Schema: &Schema{Type: "integer"}},
+	}
+}
+
+// request splits the fields of the request into path/query/header parameters and json/multipart body properties,
+// the same way the router binds them. It returns true if the route allows unauthorized calls.
+func (g *generator) request(op *Operation, method string, typ reflect.Type) (allowUnauthorized bool) {
+	jsonBody := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	multipartBody := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	forEachField(typ, func(field *reflect.StructField) {
+		if field.Tag.Get("allowUnauthorized") == "true" {
+			allowUnauthorized = true
+		}
+		if !field.IsExported() {
+			return
+		}
+		required := field.Tag.Get("required") == "true"
+		switch {
+		case field.Tag.Get("uri") != "":
+			op.Parameters = append(op.Parameters, &Parameter{Name: field.Tag.Get("uri"), In: "path", Required: true, Schema: g.fieldSchema(field)})
+		case field.Tag.Get("header") != "":
+			op.Parameters = append(op.Parameters, &Parameter{Name: field.Tag.Get("header"), In: "header", Required: required, Schema: g.fieldSchema(field)})
+		case field.Tag.Get("formMultipart") != "" && method != http.MethodGet:
+			addProperty(multipartBody, field.Tag.Get("formMultipart"), g.fieldSchema(field), required)
+		case field.Tag.Get("form") != "":
+			op.Parameters = append(op.Parameters, &Parameter{Name: field.Tag.Get("form"), In: "query", Required: required, Schema: g.fieldSchema(field)})
+		default:
+			if name := jsonName(field); name != "" && field.Tag.Get("swaggerignore") != "true" {
+				addProperty(jsonBody, name, g.fieldSchema(field), required)
+			}
+		}
+	})
+	if len(jsonBody.Properties) != 0 || len(multipartBody.Properties) != 0 {
+		op.RequestBody = &RequestBody{Content: make(map[string]*MediaType, 1)}
+	}
+	if len(jsonBody.Properties) != 0 {
+		op.RequestBody.Content[jsonContentType] = &MediaType{Schema: jsonBody}
+		op.RequestBody.Required = len(jsonBody.Required) != 0
+	}
+	if len(multipartBody.Properties) != 0 {
+		op.RequestBody.Content[multipartContentType] = &MediaType{Schema: multipartBody}
+		op.RequestBody.Required = op.RequestBody.Required || len(multipartBody.Required) != 0
+	}
+
+	return allowUnauthorized
+}
+
+func (*generator) addMissingPathParameters(op *Operation, ginPath string) {
+	for _, match := range ginPathParamRegex.FindAllStringSubmatch(ginPath, -1) {
+		var found bool
+		for _, param := range op.Parameters {
+			found = found || (param.In == "path" && param.Name == match[1])
+		}
+		if !found {
+			op.Parameters = append(op.Parameters, &Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+}
+
+//nolint:exhaustive // The rest are primitives.
+func (g *generator) schema(typ reflect.Type) *Schema {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if values, found := g.enums[typ]; found {
+		schema := primitiveSchema(typ)
+		schema.Enum = values
+
+		return schema
+	}
+	switch typ {
+	case wintrTimeType, stdlibTimeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case fileType:
+		return &Schema{Type: "string", Format: "binary"}
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		return g.component(typ)
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: g.schema(typ.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(typ.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	default:
+		return primitiveSchema(typ)
+	}
+}
+
+// component registers named structs in the components, so they're described only once, and references them.
+func (g *generator) component(typ reflect.Type) *Schema {
+	name, found := g.names[typ]
+	if !found && typ.Name() != "" {
+		name = componentName(typ)
+		g.names[typ] = name
+		g.schemas[name] = g.object(typ)
+	} else if !found {
+		return g.object(typ)
+	}
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName is the package and the name of the type, followed, for the instances of generic types, by their type arguments,
+// so that, for example, `Page[*users.User]` and `Page[*users.Badge]` are different components.
+func componentName(typ reflect.Type) string {
+	name, typeArgs, generic := strings.Cut(typ.Name(), "[")
+	name = path.Base(typ.PkgPath()) + "." + name
+	if generic {
+		typeArgs = typeArgsPackagePathRegex.ReplaceAllString(typeArgs, "")
+		name += "-" + strings.Trim(invalidComponentNameRegex.ReplaceAllString(typeArgs, "-"), "-")
+	}
+
+	return name
+}
+
+func (g *generator) object(typ reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	forEachField(typ, func(field *reflect.StructField) {
+		if !field.IsExported() || field.Tag.Get("swaggerignore") == "true" {
+			return
+		}
+		if name := jsonName(field); name != "" {
+			addProperty(schema, name, g.fieldSchema(field), field.Tag.Get("required") == "true")
+		}
+	})
+
+	return schema
+}
+
+// fieldSchema is the schema of the field's type, adjusted with the `swaggertype`, `enums`, `example`, `minimum` and `maximum` tags.
+func (g *generator) fieldSchema(field *reflect.StructField) *Schema {
+	var schema *Schema
+	if swaggerType := field.Tag.Get("swaggertype"); swaggerType != "" {
+		schema = swaggerTypeSchema(strings.Split(swaggerType, ","))
+	} else {
+		schema = g.schema(field.Type)
+	}
+	if schema.Ref != "" {
+		return schema
+	}
+	valuesSchema := schema
+	if schema.Type == "array" && schema.Items != nil {
+		valuesSchema = schema.Items
+	}
+	if enums := field.Tag.Get("enums"); enums != "" {
+		valuesSchema.Enum = nil
+		for _, value := range strings.Split(enums, ",") {
+			valuesSchema.Enum = append(valuesSchema.Enum, parseValue(valuesSchema.Type, value))
+		}
+	}
+	if example := field.Tag.Get("example"); example != "" {
+		if schema.Type == "array" && valuesSchema.Type != "object" {
+			values := make([]any, 0, 1)
+			for _, value := range strings.Split(example, ",") {
+				values = append(values, parseValue(valuesSchema.Type, value))
+			}
+			schema.Examples = []any{values}
+		} else {
+			schema.Examples = []any{parseValue(schema.Type, example)}
+		}
+	}
+	schema.Minimum, schema.Maximum = parseLimit(field.Tag.Get("minimum"), schema.Minimum), parseLimit(field.Tag.Get("maximum"), schema.Maximum)
+
+	return schema
+}
+
+func swaggerTypeSchema(parts []string) *Schema {
+	if parts[0] == "array" && len(parts) > 1 {
+		return &Schema{Type: "array", Items: swaggerTypeSchema(parts[1:])}
+	}
+
+	return &Schema{Type: parts[0]}
+}
+
+//nolint:exhaustive // The rest are strings.
+func primitiveSchema(typ reflect.Type) *Schema {
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		minimum := float64(0)
+
+		return &Schema{Type: "integer", Format: "int64", Minimum: &minimum}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+func parseValue(schemaType, value string) any {
+	switch schemaType {
+	case "integer":
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	case "number":
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	case "boolean":
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+
+	return value
+}
+
+func parseLimit(value string, defaultLimit *float64) *float64 {
+	if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+		return &parsed
+	}
+
+	return defaultLimit
+}
+
+func addProperty(schema *Schema, name string, property *Schema, required bool) {
+	schema.Properties[name] = property
+	if required {
+		schema.Required = append(schema.Required, name)
+		sort.Strings(schema.Required)
+	}
+}
+
+// forEachField visits the fields of the struct, including the ones of its embedded structs, like the json encoder does.
+func forEachField(typ reflect.Type, visit func(*reflect.StructField)) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && jsonName(&field) == "" && field.Tag.Get("json") != "-" {
+			forEachField(field.Type, visit)
+
+			continue
+		}
+		visit(&field)
+	}
+}
+
+// jsonName is the name of the field in the json, or empty if the field is not serialized or it's embedded.
+func jsonName(field *reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	if field.Anonymous || field.Tag.Get("uri") != "" || field.Tag.Get("form") != "" || field.Tag.Get("header") != "" {
+		return ""
+	}
+
+	return field.Name
+}
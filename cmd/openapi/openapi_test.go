@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package openapi
+
+import (
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+type (
+	testStep   int8
+	testKind   string
+	testNested struct {
+		CreatedAt *time.Time  `json:"createdAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		Parent    *testNested `json:"parent,omitempty"`
+	}
+	testEmbedded struct {
+		Step testStep `json:"step" example:"1"`
+	}
+	testResponse struct {
+		testEmbedded
+		Nested   *testNested       `json:"nested"`
+		Data     map[string]any    `json:"data,omitempty"`
+		Ignored  string            `json:"ignored" swaggerignore:"true"`
+		Skipped  string            `json:"-"`
+		Kinds    []testKind        `json:"kinds" example:"a,b"`
+		Tags     []string          `json:"tags" enums:"x,y"`
+		Counters map[string]uint64 `json:"counters"`
+	}
+	testPage[T any] struct {
+		Items []T `json:"items"`
+	}
+	testRequest struct {
+		_         struct{}              `allowUnauthorized:"true"` //nolint:revive // It's processed by the router.
+		File      *multipart.FileHeader `form:"file" formMultipart:"file"`
+		UserID    string                `uri:"userId" required:"true" example:"bogus"`
+		APIKey    string                `header:"X-API-Key" required:"true"`
+		Language  string                `form:"language" example:"en"`
+		Username  string                `form:"username" formMultipart:"username" required:"true"`
+		Limit     uint64                `form:"limit" maximum:"1000" example:"10"`
+		Kind      testKind              `json:"kind" required:"true"`
+		Confirmed *bool                 `json:"confirmed" example:"true"`
+	}
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PATCH("v1w/things/:userId", func(*gin.Context) {})
+	router.GET("v1w/things/:userId/other/:otherId", func(*gin.Context) {})
+	routes := []*Route{{
+		Method: http.MethodPatch, Path: "v1w/things/:userId", Name: "ModifyThing", SuccessCode: http.StatusCreated,
+		Request: new(testRequest), Response: new(testResponse), RateLimit: &RateLimit{Limit: 10, WindowSeconds: 60},
+	}}
+	doc := Generate(&Info{Title: "test", Version: "v1"}, router.Routes(), routes, EnumOf[testStep](1, 2), EnumOf[testKind]("a", "b"))
+	assert.Equal(t, Version, doc.OpenAPI)
+	require.Len(t, doc.Paths, 2)
+
+	op := doc.Paths["/v1w/things/{userId}"]["patch"]
+	require.NotNil(t, op)
+	assert.Equal(t, "ModifyThing", op.OperationID)
+	assert.Empty(t, op.Security)
+	params := make(map[string]*Parameter, len(op.Parameters))
+	for _, param := range op.Parameters {
+		params[param.In+":"+param.Name] = param
+	}
+	assert.Len(t, params, 4)
+	assert.True(t, params["path:userId"].Required)
+	assert.True(t, params["header:X-API-Key"].Required)
+	assert.False(t, params["query:language"].Required)
+	assert.Equal(t, []any{"en"}, params["query:language"].Schema.Examples)
+	assert.EqualValues(t, 1000, *params["query:limit"].Schema.Maximum)
+	assert.Equal(t, []any{int64(10)}, params["query:limit"].Schema.Examples)
+	jsonBody := op.RequestBody.Content[jsonContentType].Schema
+	assert.Equal(t, []string{"kind"}, jsonBody.Required)
+	assert.Equal(t, []any{testKind("a"), testKind("b")}, jsonBody.Properties["kind"].Enum)
+	assert.Equal(t, []any{true}, jsonBody.Properties["confirmed"].Examples)
+	multipartBody := op.RequestBody.Content[multipartContentType].Schema
+	assert.Equal(t, "binary", multipartBody.Properties["file"].Format)
+	assert.Equal(t, []string{"username"}, multipartBody.Required)
+	require.Contains(t, op.Responses, "201")
+	require.Contains(t, op.Responses, "default")
+	assert.Equal(t, "#/components/schemas/openapi.testResponse", op.Responses["201"].Content[jsonContentType].Schema.Ref)
+	assert.Equal(t, &RateLimit{Limit: 10, WindowSeconds: 60}, op.RateLimit)
+	assert.ElementsMatch(t, []string{rateLimitLimitHeader, rateLimitRemainingHeader, rateLimitResetHeader}, keys(op.Responses["201"].Headers))
+	assert.Len(t, op.Responses["default"].Headers, 3)
+
+	response := doc.Components.Schemas["openapi.testResponse"]
+	require.NotNil(t, response)
+	assert.ElementsMatch(t, []string{"step", "nested", "data", "kinds", "tags", "counters"}, keys(response.Properties))
+	assert.Equal(t, []any{testStep(1), testStep(2)}, response.Properties["step"].Enum)
+	assert.Equal(t, []any{int64(1)}, response.Properties["step"].Examples)
+	assert.Equal(t, []any{testKind("a"), testKind("b")}, response.Properties["kinds"].Items.Enum)
+	assert.Equal(t, []any{[]any{"a", "b"}}, response.Properties["kinds"].Examples)
+	assert.Equal(t, []any{"x", "y"}, response.Properties["tags"].Items.Enum)
+	assert.Equal(t, "integer", response.Properties["counters"].AdditionalProperties.Type)
+	nested := doc.Components.Schemas["openapi.testNested"]
+	require.NotNil(t, nested)
+	assert.Equal(t, "date-time", nested.Properties["createdAt"].Format)
+	assert.Equal(t, "#/components/schemas/openapi.testNested", nested.Properties["parent"].Ref)
+	assert.Contains(t, doc.Components.Schemas, "server.ErrorResponse")
+
+	undescribed := doc.Paths["/v1w/things/{userId}/other/{otherId}"]["get"]
+	require.NotNil(t, undescribed)
+	assert.Len(t, undescribed.Parameters, 2)
+	assert.Equal(t, []map[string][]string{{bearerSecurityScheme: {}}}, undescribed.Security)
+	assert.Contains(t, undescribed.Responses, "200")
+	assert.Equal(t, "get_v1w_things_userId_other_otherId", undescribed.OperationID)
+	assert.Nil(t, undescribed.RateLimit)
+	assert.Empty(t, undescribed.Responses["200"].Headers)
+}
+
+func TestComponentName(t *testing.T) {
+	t.Parallel()
+	gen := &generator{schemas: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+
+	assert.Equal(t, "#/components/schemas/openapi.testNested", gen.schema(reflect.TypeOf(new(testNested))).Ref)
+	assert.Equal(t, "#/components/schemas/openapi.testPage-openapi.testNested", gen.schema(reflect.TypeOf(new(testPage[*testNested]))).Ref)
+	assert.Equal(t, "#/components/schemas/openapi.testPage-string", gen.schema(reflect.TypeOf(new(testPage[string]))).Ref)
+	assert.Equal(t, "#/components/schemas/openapi.testNested", gen.schemas["openapi.testPage-openapi.testNested"].Properties["items"].Items.Ref)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("v1r/things", func(*gin.Context) {})
+	router.GET(Path, Handler(Generate(&Info{Title: "test", Version: "v1"}, router.Routes(), nil)))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, Path, http.NoBody))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &doc))
+	assert.Equal(t, Version, doc["openapi"])
+	assert.Len(t, doc["paths"], 1)
+	assert.Contains(t, doc["paths"], "/v1r/things")
+}
+
+func keys[V any](m map[string]V) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+
+	return res
+}
@@ -0,0 +1,368 @@
+// SPDX-License-Identifier: ice License 1.0
+
+// Command routesgen generates the openAPIRoutes of a service, from the swag annotations of its handlers
+// and from the generic Request/Response types of their server.Request, so that they can't drift apart.
+// It's run, via `go generate`, in the directory of the service.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/log"
+)
+
+const (
+	openAPIImportPath = "github.com/ice-blockchain/eskimo/cmd/openapi"
+	localImportPrefix = "github.com/ice-blockchain/"
+)
+
+type (
+	route struct {
+		Method      string
+		Path        string
+		Name        string
+		Summary     string
+		Request     string
+		Response    string
+		Tags        []string
+		SuccessCode int
+	}
+	parsedFile struct {
+		file    *ast.File
+		imports map[string]string
+	}
+)
+
+//nolint:gochecknoglobals // They're just the compiled regexes.
+var (
+	swagPathParamRegex = regexp.MustCompile(`{([A-Za-z0-9_]+)}`)
+	versionRegex       = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+func main() {
+	dir := flag.String("d", ".", "the directory of the service")
+	output := flag.String("o", "openapi_routes.go", "the generated file, in the directory of the service")
+	flag.Parse()
+	src, err := generate(*dir, *output)
+	log.Panic(errors.Wrapf(err, "failed to generate the routes of %v", *dir))                                    //nolint:revive // Intended.
+	log.Panic(errors.Wrap(os.WriteFile(filepath.Join(*dir, *output), src, 0o644), "failed to write the routes")) //nolint:gosec,gomnd,revive // It's source code.
+}
+
+func generate(dir, output string) ([]byte, error) {
+	files, err := parseFiles(dir, output)
+	if err != nil {
+		return nil, err
+	}
+	basePath := ""
+	for _, file := range files {
+		for _, group := range file.file.Comments {
+			if value, found := annotation(group, "@BasePath"); found {
+				basePath = value
+			}
+		}
+	}
+	routes := make([]*route, 0, len(files))
+	imports := map[string]string{"net/http": "http", openAPIImportPath: "openapi"}
+	for _, file := range files {
+		for _, decl := range file.file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Doc != nil {
+				fileRoutes, rErr := handlerRoutes(fn, basePath, file.imports, imports)
+				if rErr != nil {
+					return nil, errors.Wrapf(rErr, "invalid annotations of %v", fn.Name.Name)
+				}
+				routes = append(routes, fileRoutes...)
+			}
+		}
+	}
+
+	return render(files[0].file.Name.Name, routes, imports)
+}
+
+func parseFiles(dir, output string) ([]*parsedFile, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the files of %v", dir)
+	}
+	sort.Strings(names)
+	fset := token.NewFileSet()
+	files := make([]*parsedFile, 0, len(names))
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") || filepath.Base(name) == output {
+			continue
+		}
+		file, pErr := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if pErr != nil {
+			return nil, errors.Wrapf(pErr, "failed to parse %v", name)
+		}
+		imports := make(map[string]string, len(file.Imports))
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value) //nolint:errcheck // They're valid, since they were parsed.
+			imports[packageName(spec, importPath)] = importPath
+		}
+		files = append(files, &parsedFile{file: file, imports: imports})
+	}
+	if len(files) == 0 {
+		return nil, errors.Errorf("there are no go files in %v", dir)
+	}
+
+	return files, nil
+}
+
+// handlerRoutes returns the routes of the handler, one per `@Router` annotation, if it has any
+// and it takes a server.Request or binds the request itself from the gin.Context.
+func handlerRoutes(fn *ast.FuncDecl, basePath string, fileImports, imports map[string]string) ([]*route, error) {
+	request, response := handlerTypes(fn)
+	if request == nil {
+		return nil, nil
+	}
+	template := &route{Name: fn.Name.Name, Request: exprString(request)}
+	if response != nil {
+		if template.Response = exprString(response); template.Response == "any" {
+			template.Response = ""
+		}
+	}
+	var paths []string
+	summaryCompleted := false
+	for _, line := range strings.Split(fn.Doc.Text(), "\n") {
+		name, value, _ := strings.Cut(strings.TrimSpace(line), "\t")
+		switch value = strings.TrimSpace(value); strings.TrimSpace(name) {
+		case "@Description":
+			if !summaryCompleted {
+				template.Summary = strings.TrimSpace(template.Summary + " " + value)
+				summaryCompleted = strings.HasSuffix(value, ".")
+			}
+		case "@Tags":
+			template.Tags = strings.Split(value, ",")
+		case "@Success":
+			if template.SuccessCode == 0 {
+				template.SuccessCode, _ = strconv.Atoi(strings.Fields(value)[0]) //nolint:errcheck // It's checked below.
+			}
+		case "@Router":
+			paths = append(paths, strings.TrimSuffix(value, "."))
+		}
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	if template.SuccessCode == 0 {
+		return nil, errors.New("`@Success` is required")
+	}
+	for _, expr := range []ast.Expr{request, response} {
+		if expr == nil {
+			continue
+		}
+		ast.Inspect(expr, func(node ast.Node) bool {
+			if selector, ok := node.(*ast.SelectorExpr); ok {
+				if pkg, isIdent := selector.X.(*ast.Ident); isIdent && fileImports[pkg.Name] != "" {
+					imports[fileImports[pkg.Name]] = pkg.Name
+				}
+			}
+
+			return true
+		})
+	}
+	routes := make([]*route, 0, len(paths))
+	for _, routerPath := range paths {
+		swagPath, method, found := strings.Cut(routerPath, " ")
+		method = strings.ToUpper(strings.Trim(strings.TrimSpace(method), "[]"))
+		if !found || !isMethod(method) {
+			return nil, errors.Errorf("invalid `@Router %v`", routerPath)
+		}
+		r := *template
+		r.Method = method
+		r.Path = strings.Trim(basePath, "/") + "/" + strings.TrimPrefix(swagPathParamRegex.ReplaceAllString(swagPath, ":$1"), "/")
+		routes = append(routes, &r)
+	}
+
+	return routes, nil
+}
+
+// handlerTypes returns the REQ and RESP of the `*server.Request[REQ, RESP]` the handler takes or,
+// if it takes a `*gin.Context`, the type of the variable it binds and no response, since it writes it itself.
+func handlerTypes(fn *ast.FuncDecl) (request, response ast.Expr) {
+	for _, param := range fn.Type.Params.List {
+		star, ok := param.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		if exprString(star.X) == "gin.Context" && fn.Body != nil {
+			return boundType(fn.Body), nil
+		}
+		generic, ok := star.X.(*ast.IndexListExpr)
+		if !ok || len(generic.Indices) != 2 || exprString(generic.X) != "server.Request" {
+			continue
+		}
+
+		return generic.Indices[0], generic.Indices[1]
+	}
+
+	return nil, nil
+}
+
+// boundType returns the type of the `var arg T` that's bound via `ginCtx.ShouldBind...(&arg)`, if any.
+func boundType(body *ast.BlockStmt) (typ ast.Expr) {
+	vars := make(map[string]ast.Expr)
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				vars[name.Name] = n.Type
+			}
+		case *ast.CallExpr:
+			selector, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || !strings.HasPrefix(selector.Sel.Name, "ShouldBind") || len(n.Args) != 1 {
+				break
+			}
+			if ref, isRef := n.Args[0].(*ast.UnaryExpr); isRef && ref.Op == token.AND {
+				if ident, isIdent := ref.X.(*ast.Ident); isIdent && vars[ident.Name] != nil && typ == nil {
+					typ = vars[ident.Name]
+				}
+			}
+		}
+
+		return typ == nil
+	})
+
+	return typ
+}
+
+func render(pkg string, routes []*route, imports map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// SPDX-License-Identifier: ice License 1.0\n\n")
+	fmt.Fprintf(&buf, "// Code generated by routesgen from the swag annotations of the handlers. DO NOT EDIT.\n\npackage %v\n\n", pkg)
+	renderImports(&buf, imports)
+	fmt.Fprintf(&buf, "//nolint:funlen,lll // Generated.\nfunc openAPIRoutes() []*openapi.Route {\n\treturn []*openapi.Route{\n")
+	for _, r := range routes {
+		tags := make([]string, 0, len(r.Tags))
+		for _, tag := range r.Tags {
+			tags = append(tags, strconv.Quote(strings.TrimSpace(tag)))
+		}
+		fmt.Fprintf(&buf, "\t\t{\n\t\t\tMethod: http.Method%v, Path: %q, Name: %q, Tags: []string{%v},\n",
+			methodConstant(r.Method), r.Path, r.Name, strings.Join(tags, ", "))
+		fmt.Fprintf(&buf, "\t\t\tSummary: %q,", r.Summary)
+		if r.SuccessCode != http.StatusOK {
+			fmt.Fprintf(&buf, " SuccessCode: %v,", statusConstant(r.SuccessCode))
+		}
+		fmt.Fprintf(&buf, "\n\t\t\tRequest: new(%v),", r.Request)
+		if r.Response != "" {
+			fmt.Fprintf(&buf, " Response: new(%v),", r.Response)
+		}
+		fmt.Fprintf(&buf, "\n\t\t},\n")
+	}
+	fmt.Fprintf(&buf, "\t}\n}\n")
+	src, err := format.Source(buf.Bytes())
+
+	return src, errors.Wrap(err, "failed to format the generated routes")
+}
+
+// renderImports groups the imports like the rest of the code: the standard library, the third parties and ours.
+func renderImports(buf *bytes.Buffer, imports map[string]string) {
+	groups := make([][]string, 3) //nolint:gomnd // The groups.
+	for importPath := range imports {
+		switch {
+		case !strings.Contains(strings.Split(importPath, "/")[0], "."):
+			groups[0] = append(groups[0], importPath)
+		case strings.HasPrefix(importPath, localImportPrefix):
+			groups[2] = append(groups[2], importPath)
+		default:
+			groups[1] = append(groups[1], importPath)
+		}
+	}
+	buf.WriteString("import (\n")
+	first := true
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		if !first {
+			buf.WriteString("\n")
+		}
+		first = false
+		sort.Strings(group)
+		for _, importPath := range group {
+			if name := imports[importPath]; name != defaultPackageName(importPath) {
+				fmt.Fprintf(buf, "\t%v %q\n", name, importPath)
+			} else {
+				fmt.Fprintf(buf, "\t%q\n", importPath)
+			}
+		}
+	}
+	buf.WriteString(")\n\n")
+}
+
+func annotation(group *ast.CommentGroup, name string) (string, bool) {
+	for _, line := range strings.Split(group.Text(), "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 && fields[0] == name {
+			return fields[1], true
+		}
+	}
+
+	return "", false
+}
+
+func packageName(spec *ast.ImportSpec, importPath string) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+
+	return defaultPackageName(importPath)
+}
+
+func defaultPackageName(importPath string) string {
+	name := path.Base(importPath)
+	if versionRegex.MatchString(name) {
+		name = path.Base(path.Dir(importPath))
+	}
+
+	return name
+}
+
+func isMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func methodConstant(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+func statusConstant(code int) string {
+	switch code {
+	case http.StatusCreated:
+		return "http.StatusCreated"
+	case http.StatusAccepted:
+		return "http.StatusAccepted"
+	case http.StatusNoContent:
+		return "http.StatusNoContent"
+	default:
+		return strconv.Itoa(code)
+	}
+}
+
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	log.Panic(errors.Wrap(printer.Fprint(&buf, token.NewFileSet(), expr), "failed to print the expression")) //nolint:revive // Intended.
+
+	return buf.String()
+}
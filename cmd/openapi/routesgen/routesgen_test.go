@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	expected, err := os.ReadFile("testdata/service_routes.golden")
+	require.NoError(t, err)
+
+	src, err := generate("testdata", "service_routes.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(src))
+}
+
+func TestGeneratedRoutesAreUpToDate(t *testing.T) {
+	t.Parallel()
+	for _, service := range []string{"../../eskimo", "../../eskimo-hut"} {
+		committed, err := os.ReadFile(service + "/openapi_routes.go")
+		require.NoError(t, err)
+		src, err := generate(service, "openapi_routes.go")
+		require.NoError(t, err)
+		assert.Equal(t, string(committed), string(src), "run `go generate` in %v", service)
+	}
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: ice License 1.0
+
+// @BasePath	/v1w
+package main
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	emaillink "github.com/ice-blockchain/eskimo/auth/email_link"
+	"github.com/ice-blockchain/eskimo/users"
+	"github.com/ice-blockchain/wintr/server"
+)
+
+// CreateThing godoc
+//
+//	@Schemes
+//	@Description	Creates a thing,
+//	@Description	for the user.
+//	@Description	Not part of the summary.
+//	@Tags			Things, Users
+//	@Success		201	{object}	users.User
+//	@Router			/users/{userId}/things [POST].
+func (s *service) CreateThing(
+	ctx context.Context,
+	req *server.Request[CreateThingRequestBody, Page[*users.User]],
+) (*server.Response[Page[*users.User]], *server.Response[server.ErrorResponse]) {
+	return nil, nil
+}
+
+// DeleteThing godoc
+//
+//	@Description	Deletes a thing.
+//	@Tags			Things
+//	@Success		200	"OK"
+//	@Router			/things/{thingId} [DELETE].
+func (s *service) DeleteThing(ctx context.Context, req *server.Request[emaillink.Thing, any]) (*server.Response[any], *server.Response[server.ErrorResponse]) {
+	return nil, nil
+}
+
+// ExportThings godoc
+//
+//	@Description	Exports the things.
+//	@Tags			Things
+//	@Success		200	"CSV"
+//	@Router			/things/csv [GET].
+func (s *service) ExportThings(ginCtx *gin.Context) {
+	var arg ExportThingsArg
+	if err := ginCtx.ShouldBindQuery(&arg); err != nil {
+		return
+	}
+}
+
+// NotAHandler isn't annotated.
+func (s *service) NotAHandler(ctx context.Context, req *server.Request[CreateThingRequestBody, any]) {
+}
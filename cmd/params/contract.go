@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package params
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// Public API.
+
+var (
+	ErrInvalidTZ        = errors.New("invalid tz")
+	ErrInvalidTimeRange = errors.New("invalid time range")
+)
+
+type (
+	Number interface {
+		~int | ~int64 | ~uint64
+	}
+)
+
+// Private API.
+
+const (
+	maxPositiveTZOffsetHours = 14
+	maxNegativeTZOffsetHours = 12
+	minutesInOneHour         = 60
+)
+
+var (
+	//nolint:gochecknoglobals // It's just the compiled regex.
+	tzRegex = regexp.MustCompile(`^([+-])(\d{1,2}):(\d{2})$`)
+)
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package params
+
+import (
+	"fmt"
+	"strconv"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// TZ parses an UTC offset, like `+04:30` or `-03:45`, into a fixed location. An empty tz means UTC.
+func TZ(tz string) (*stdlibtime.Location, error) {
+	offset, err := parseTZOffset(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	return location(offset), nil
+}
+
+// InvertedTZ is the same as TZ, but the offset of the location is inverted (`+04:30` becomes `-04:30`).
+// It's how GET /user-statistics/user-growth has always interpreted its `tz`.
+func InvertedTZ(tz string) (*stdlibtime.Location, error) {
+	offset, err := parseTZOffset(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	return location(-offset), nil
+}
+
+func parseTZOffset(tz string) (stdlibtime.Duration, error) {
+	if tz == "" {
+		return 0, nil
+	}
+	matches := tzRegex.FindStringSubmatch(tz)
+	if matches == nil {
+		return 0, errors.Wrapf(ErrInvalidTZ, "`%v` doesn't match the format +04:30 or -03:45", tz)
+	}
+	hours, _ := strconv.Atoi(matches[2])   //nolint:errcheck // It's validated by the regex.
+	minutes, _ := strconv.Atoi(matches[3]) //nolint:errcheck // It's validated by the regex.
+	offset := stdlibtime.Duration(hours)*stdlibtime.Hour + stdlibtime.Duration(minutes)*stdlibtime.Minute
+	if minutes >= minutesInOneHour ||
+		(matches[1] == "+" && offset > maxPositiveTZOffsetHours*stdlibtime.Hour) ||
+		(matches[1] == "-" && offset > maxNegativeTZOffsetHours*stdlibtime.Hour) {
+		return 0, errors.Wrapf(ErrInvalidTZ, "`%v` is out of range [-%02d:00, +%02d:00]", tz, maxNegativeTZOffsetHours, maxPositiveTZOffsetHours)
+	}
+	if matches[1] == "-" {
+		offset = -offset
+	}
+
+	return offset, nil
+}
+
+func location(offset stdlibtime.Duration) *stdlibtime.Location {
+	if offset == 0 {
+		return stdlibtime.UTC
+	}
+	sign, abs := "+", offset
+	if offset < 0 {
+		sign, abs = "-", -offset
+	}
+	name := fmt.Sprintf("%v%02d:%02d", sign, int(abs.Hours()), int(abs.Minutes())%minutesInOneHour)
+
+	return stdlibtime.FixedZone(name, int(offset.Seconds()))
+}
+
+// Capped returns the defaultValue if value is not provided (0), otherwise the value, but not more than maxValue.
+func Capped[T Number](value, defaultValue, maxValue T) T {
+	if value == 0 {
+		value = defaultValue
+	}
+	if maxValue > 0 && value > maxValue {
+		value = maxValue
+	}
+
+	return value
+}
+
+// TimeRange parses the optional RFC3339 bounds of a time range. `to` defaults to now and `from` to `defaultWindow` before `to`.
+// If maxWindow is provided, ranges longer than it are rejected.
+func TimeRange(fromArg, toArg string, defaultWindow, maxWindow stdlibtime.Duration) (from, to *time.Time, err error) {
+	to = time.Now()
+	if toArg != "" {
+		parsed, pErr := stdlibtime.Parse(stdlibtime.RFC3339Nano, toArg)
+		if pErr != nil {
+			return nil, nil, errors.Wrapf(ErrInvalidTimeRange, "invalid `to` %v: %v", toArg, pErr)
+		}
+		to = time.New(parsed.UTC())
+	}
+	from = time.New(to.Add(-defaultWindow))
+	if fromArg != "" {
+		parsed, pErr := stdlibtime.Parse(stdlibtime.RFC3339Nano, fromArg)
+		if pErr != nil {
+			return nil, nil, errors.Wrapf(ErrInvalidTimeRange, "invalid `from` %v: %v", fromArg, pErr)
+		}
+		from = time.New(parsed.UTC())
+	}
+	if from.After(*to.Time) {
+		return nil, nil, errors.Wrapf(ErrInvalidTimeRange, "`from` %v is after `to` %v", from, to)
+	}
+	if maxWindow > 0 && to.Sub(*from.Time) > maxWindow {
+		return nil, nil, errors.Wrapf(ErrInvalidTimeRange, "the time range is longer than %v", maxWindow)
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package params
+
+import (
+	"testing"
+	stdlibtime "time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTZ(t *testing.T) {
+	t.Parallel()
+	for tz, expectedOffset := range map[string]stdlibtime.Duration{
+		"":       0,
+		"+00:00": 0,
+		"-00:00": 0,
+		"+4:30":  4*stdlibtime.Hour + 30*stdlibtime.Minute,
+		"+04:30": 4*stdlibtime.Hour + 30*stdlibtime.Minute,
+		"-03:45": -(3*stdlibtime.Hour + 45*stdlibtime.Minute),
+		"+14:00": 14 * stdlibtime.Hour,
+		"-12:00": -12 * stdlibtime.Hour,
+	} {
+		loc, err := TZ(tz)
+		require.NoError(t, err, tz)
+		_, offset := stdlibtime.Now().In(loc).Zone()
+		assert.Equal(t, int(expectedOffset.Seconds()), offset, tz)
+
+		inverted, err := InvertedTZ(tz)
+		require.NoError(t, err, tz)
+		_, offset = stdlibtime.Now().In(inverted).Zone()
+		assert.Equal(t, -int(expectedOffset.Seconds()), offset, tz)
+	}
+}
+
+func TestTZInvalid(t *testing.T) {
+	t.Parallel()
+	for _, tz := range []string{"UTC", "4:30", "+4", "+04:3", "+04:60", "+14:30", "-12:30", "+123:00", "+04:30 ", "Europe/Bucharest"} {
+		_, err := TZ(tz)
+		require.ErrorIs(t, err, ErrInvalidTZ, tz)
+		_, err = InvertedTZ(tz)
+		require.ErrorIs(t, err, ErrInvalidTZ, tz)
+	}
+}
+
+func TestCapped(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, uint64(3), Capped(uint64(0), 3, 90))
+	assert.Equal(t, uint64(7), Capped(uint64(7), 3, 90))
+	assert.Equal(t, uint64(90), Capped(uint64(1000), 3, 90))
+	assert.Equal(t, uint64(1000), Capped(uint64(1000), 3, 0))
+	assert.Equal(t, stdlibtime.Hour, Capped(0, stdlibtime.Hour, 24*stdlibtime.Hour))
+	assert.Equal(t, 24*stdlibtime.Hour, Capped(48*stdlibtime.Hour, stdlibtime.Hour, 24*stdlibtime.Hour))
+}
+
+func TestTimeRange(t *testing.T) {
+	t.Parallel()
+	from, to, err := TimeRange("", "", stdlibtime.Hour, 0)
+	require.NoError(t, err)
+	assert.Equal(t, stdlibtime.Hour, to.Sub(*from.Time))
+
+	from, to, err = TimeRange("2022-01-03T16:20:52.156534Z", "2022-01-04T16:20:52.156534Z", stdlibtime.Hour, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 24*stdlibtime.Hour, to.Sub(*from.Time))
+
+	_, _, err = TimeRange("", "2022-01-04T16:20:52.156534Z", stdlibtime.Hour, 0)
+	require.NoError(t, err)
+
+	for _, tc := range [][2]string{
+		{"bogus", ""},
+		{"", "bogus"},
+		{"2022-01-05T16:20:52.156534Z", "2022-01-04T16:20:52.156534Z"},
+	} {
+		_, _, err = TimeRange(tc[0], tc[1], stdlibtime.Hour, 0)
+		require.ErrorIs(t, err, ErrInvalidTimeRange, tc)
+	}
+	_, _, err = TimeRange("2022-01-01T16:20:52.156534Z", "2022-01-04T16:20:52.156534Z", stdlibtime.Hour, 48*stdlibtime.Hour)
+	require.ErrorIs(t, err, ErrInvalidTimeRange)
+}
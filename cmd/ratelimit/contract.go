@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+	stdlibtime "time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// Public API.
+
+type (
+	// Config is the number of calls each caller can make to each route in every window.
+	// The calls aren't rejected when over the limit, they're only reported, via headers, so that the clients can back off pre-emptively.
+	Config struct {
+		// Routes override the default limit of the routes, keyed by their method and path, e.g. `GET /v1r/users/:userId`. 0 disables it for the route.
+		Routes map[string]uint64 `yaml:"routes"`
+		// Window is how often the counters are reset. 0 disables it.
+		Window stdlibtime.Duration `yaml:"window"`
+		// Default is the limit of the routes that aren't in Routes. 0 disables it for them.
+		Default uint64 `yaml:"default"`
+	}
+)
+
+// Private API.
+
+const (
+	apiPathPrefix       = "/v1"
+	authorizationHeader = "Authorization"
+
+	limitHeader     = "X-RateLimit-Limit"
+	remainingHeader = "X-RateLimit-Remaining"
+	resetHeader     = "X-RateLimit-Reset"
+
+	retryAfterHeader      = "Retry-After"
+	retryAfterCtxValueKey = "retryAfterCtxValueKey"
+)
+
+type (
+	// | limiter counts the calls of each caller to each route, in fixed windows.
+	limiter struct {
+		windowStart *time.Time
+		calls       map[callKey]uint64
+		cfg         *Config
+		mx          sync.Mutex
+	}
+	// | callKey is the route and the hash of the caller's authorization token, so that the tokens themselves aren't held in memory.
+	callKey struct {
+		route  string
+		caller uint64
+	}
+	// | retryAfterWriter sets the `Retry-After` header right before the status is written, if the handler set the seconds.
+	retryAfterWriter struct {
+		gin.ResponseWriter
+		seconds *atomic.Uint64
+	}
+)
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package ratelimit
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/zeebo/xxh3"
+
+	"github.com/ice-blockchain/eskimo/cmd/openapi"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// Middleware reports, via the `X-RateLimit-Limit`, `X-RateLimit-Remaining` and `X-RateLimit-Reset` headers, how many calls the caller can still make
+// to the route in the current window, on all the authenticated API calls. The callers are told apart by their authorization token.
+// It never rejects a call, the limits are soft, so that the clients can back off pre-emptively, before the hard limits kick in.
+func Middleware(cfg *Config) gin.HandlerFunc {
+	if cfg.Window <= 0 {
+		return func(*gin.Context) {}
+	}
+	lim := &limiter{cfg: cfg, calls: make(map[callKey]uint64)}
+
+	return func(ginCtx *gin.Context) {
+		authorization := strings.TrimPrefix(ginCtx.GetHeader(authorizationHeader), "Bearer ")
+		if authorization == "" || !strings.HasPrefix(ginCtx.FullPath(), apiPathPrefix) {
+			return
+		}
+		route := routeKey(ginCtx.Request.Method, ginCtx.FullPath())
+		limit := cfg.limit(route)
+		if limit == 0 {
+			return
+		}
+		calls, reset := lim.count(callKey{route: route, caller: xxh3.HashString(authorization)})
+		var remaining uint64
+		if calls < limit {
+			remaining = limit - calls
+		}
+		ginCtx.Header(limitHeader, strconv.FormatUint(limit, 10))
+		ginCtx.Header(remainingHeader, strconv.FormatUint(remaining, 10))
+		ginCtx.Header(resetHeader, strconv.FormatInt(reset.Unix(), 10))
+	}
+}
+
+// Document sets the rate limits of the routes, so that they're documented in the OpenAPI document.
+func Document(cfg *Config, routes []*openapi.Route) []*openapi.Route {
+	if cfg.Window <= 0 {
+		return routes
+	}
+	for _, route := range routes {
+		if limit := cfg.limit(routeKey(route.Method, route.Path)); limit > 0 {
+			route.RateLimit = &openapi.RateLimit{Limit: limit, WindowSeconds: uint64(cfg.Window.Seconds())}
+		}
+	}
+
+	return routes
+}
+
+// ValidateConfig reports the window, if negative, and the routes, under `rateLimits`, that aren't keyed by their method and path.
+func ValidateConfig(applicationYAMLKey string, cfg *Config) error {
+	var mErr *multierror.Error
+	if cfg.Window < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.rateLimits.window` can't be negative, not %v", applicationYAMLKey, cfg.Window))
+	}
+	routes := make([]string, 0, len(cfg.Routes))
+	for route := range cfg.Routes {
+		routes = append(routes, route)
+	}
+	slices.Sort(routes)
+	for _, route := range routes {
+		if method, path, found := strings.Cut(route, " "); !found || !isMethod(method) || !strings.HasPrefix(path, apiPathPrefix) {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.rateLimits.routes` must be keyed by the method and path of the routes, e.g. `GET %v/...`, not `%v`",
+				applicationYAMLKey, apiPathPrefix, route))
+		}
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+}
+
+func isMethod(method string) bool {
+	return slices.Contains([]string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}, method)
+}
+
+func routeKey(method, path string) string {
+	return method + " /" + strings.TrimPrefix(path, "/")
+}
+
+func (cfg *Config) limit(route string) uint64 {
+	if limit, found := cfg.Routes[route]; found {
+		return limit
+	}
+
+	return cfg.Default
+}
+
+// count counts the call and returns the number of calls made in the current window, including it, and when the window ends.
+func (l *limiter) count(key callKey) (calls uint64, reset stdlibtime.Time) {
+	now := time.Now()
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if l.windowStart == nil || now.Sub(*l.windowStart.Time) >= l.cfg.Window {
+		l.windowStart = now
+		clear(l.calls)
+	}
+	l.calls[key]++
+
+	return l.calls[key], l.windowStart.Add(l.cfg.Window)
+}
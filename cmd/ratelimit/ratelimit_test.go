@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	stdlibtime "time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ice-blockchain/eskimo/cmd/openapi"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware(&Config{Window: stdlibtime.Hour, Default: 2, Routes: map[string]uint64{"GET /v1r/unlimited": 0}}))
+	router.GET("v1r/things/:userId", func(*gin.Context) {})
+	router.GET("v1r/unlimited", func(*gin.Context) {})
+	call := func(path, token string) http.Header {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		if token != "" {
+			req.Header.Set(authorizationHeader, "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		return recorder.Header()
+	}
+
+	headers := call("/v1r/things/a", "token1")
+	assert.Equal(t, "2", headers.Get(limitHeader))
+	assert.Equal(t, "1", headers.Get(remainingHeader))
+	reset, err := strconv.ParseInt(headers.Get(resetHeader), 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, stdlibtime.Now().Add(stdlibtime.Hour).Unix(), reset, 1)
+	assert.Equal(t, "0", call("/v1r/things/b", "token1").Get(remainingHeader))
+	assert.Equal(t, "0", call("/v1r/things/a", "token1").Get(remainingHeader), "it never rejects the calls")
+	assert.Equal(t, "1", call("/v1r/things/a", "token2").Get(remainingHeader))
+	assert.Empty(t, call("/v1r/things/a", "").Get(limitHeader))
+	assert.Empty(t, call("/v1r/unlimited", "token1").Get(limitHeader))
+}
+
+func TestDocument(t *testing.T) {
+	t.Parallel()
+	routes := []*openapi.Route{
+		{Method: http.MethodGet, Path: "v1r/things/:userId"},
+		{Method: http.MethodPost, Path: "v1w/things"},
+		{Method: http.MethodGet, Path: "v1r/unlimited"},
+	}
+	cfg := &Config{Window: stdlibtime.Minute, Default: 100, Routes: map[string]uint64{"POST /v1w/things": 10, "GET /v1r/unlimited": 0}}
+	Document(cfg, routes)
+	assert.Equal(t, &openapi.RateLimit{Limit: 100, WindowSeconds: 60}, routes[0].RateLimit)
+	assert.Equal(t, &openapi.RateLimit{Limit: 10, WindowSeconds: 60}, routes[1].RateLimit)
+	assert.Nil(t, routes[2].RateLimit)
+	assert.Nil(t, Document(&Config{Default: 100}, []*openapi.Route{{Method: http.MethodGet, Path: "v1r/other"}})[0].RateLimit)
+}
+
+func TestValidateConfig(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, ValidateConfig("bogus", &Config{Window: stdlibtime.Minute, Routes: map[string]uint64{"GET /v1r/things/:userId": 1}}))
+	err := ValidateConfig("bogus", &Config{Window: -1, Routes: map[string]uint64{"/v1r/things": 1, "FETCH /v1r/things": 1, "GET v1r/things": 1}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "`bogus.rateLimits.window`")
+	assert.Contains(t, err.Error(), "not `/v1r/things`")
+	assert.Contains(t, err.Error(), "not `FETCH /v1r/things`")
+	assert.Contains(t, err.Error(), "not `GET v1r/things`")
+}
+
+func TestRetryAfterMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RetryAfterMiddleware())
+	router.GET("v1r/limited", func(ginCtx *gin.Context) {
+		SetRetryAfter(ginCtx.Request.Context(), 30)
+		ginCtx.JSON(http.StatusTooManyRequests, map[string]any{"code": "TOO_MANY_REQUESTS"})
+	})
+	router.GET("v1r/unlimited", func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, map[string]any{})
+	})
+	call := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+
+		return recorder
+	}
+
+	recorder := call("/v1r/limited")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "30", recorder.Header().Get(retryAfterHeader))
+	assert.JSONEq(t, `{"code":"TOO_MANY_REQUESTS"}`, recorder.Body.String())
+	assert.Empty(t, call("/v1r/unlimited").Header().Get(retryAfterHeader))
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetryAfterMiddleware lets the handlers set the `Retry-After` header, via SetRetryAfter, on their error responses too,
+// since the server writes the headers of their responses only if they succeed.
+func RetryAfterMiddleware() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		seconds := new(atomic.Uint64)
+		ginCtx.Request = ginCtx.Request.WithContext(context.WithValue(ginCtx.Request.Context(), retryAfterCtxValueKey, seconds))
+		ginCtx.Writer = &retryAfterWriter{ResponseWriter: ginCtx.Writer, seconds: seconds}
+	}
+}
+
+// SetRetryAfter sets the `Retry-After` header of the response to the call, if it goes through RetryAfterMiddleware.
+func SetRetryAfter(ctx context.Context, seconds uint64) {
+	if holder, ok := ctx.Value(retryAfterCtxValueKey).(*atomic.Uint64); ok {
+		holder.Store(seconds)
+	}
+}
+
+func (w *retryAfterWriter) WriteHeader(code int) {
+	if seconds := w.seconds.Load(); seconds > 0 && !w.Written() {
+		w.Header().Set(retryAfterHeader, strconv.FormatUint(seconds, 10))
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
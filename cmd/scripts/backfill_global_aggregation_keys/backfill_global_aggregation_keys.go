@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+const (
+	applicationYamlEskimoKey = "users"
+
+	totalUsersGlobalKeyPrefix       = "TOTAL_USERS_"
+	totalActiveUsersGlobalKeyPrefix = "TOTAL_ACTIVE_USERS_"
+
+	dayFormat, hourFormat, minuteFormat = "2006-01-02", "2006-01-02T15", "2006-01-02T15:04"
+	hoursInADay                         = 24
+
+	batchSize              = 1000
+	defaultValidateDays    = 90
+	maxReportedMissingKeys = 20
+)
+
+type (
+	config struct {
+		GlobalAggregationInterval struct {
+			Parent stdlibtime.Duration `yaml:"parent"`
+			Child  stdlibtime.Duration `yaml:"child"`
+		} `yaml:"globalAggregationInterval"`
+	}
+	global struct {
+		Key   string `db:"key"`
+		Value uint64 `db:"value"`
+	}
+	// bucket is a parsed global key: its start time and the granularity it was written with.
+	bucket struct {
+		start    stdlibtime.Time
+		interval stdlibtime.Duration
+		value    uint64
+	}
+)
+
+// This script re-buckets the historical TOTAL_USERS_* and TOTAL_ACTIVE_USERS_* keys into the layout described by the current
+// `users.globalAggregationInterval` config, so that GetUserGrowth can read the history after the config changes.
+// Each historical key describes its own granularity (by the length of its date suffix), so the old layout doesn't need to be provided.
+// Existing keys are never overwritten, because the ones written after the switch are more accurate than the backfilled ones.
+// Since TOTAL_USERS_* keys are snapshots of the total, they're moved to the new bucket that contains the end of the old one.
+// Since TOTAL_ACTIVE_USERS_* keys are counts per interval, coarser buckets take the max of the finer ones (as GetUserGrowth does)
+// and finer buckets inherit the value of the coarser one they're part of.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only report what would be inserted")
+	validateDays := flag.Uint64("validate-days", defaultValidateDays, "number of days in the past to validate against GetUserGrowth expectations")
+	flag.Parse()
+
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYamlEskimoKey, &cfg)
+	parentFormat, childFormat := dateFormat(cfg.GlobalAggregationInterval.Parent), dateFormat(cfg.GlobalAggregationInterval.Child)
+
+	ctx := context.Background()
+	db := storage.MustConnect(ctx, "", applicationYamlEskimoKey)
+	defer db.Close()
+
+	existing := getExistingGlobalValues(ctx, db)
+	planned := make(map[string]uint64)
+	totalUsers, activeUsers := parseBuckets(existing, totalUsersGlobalKeyPrefix), parseBuckets(existing, totalActiveUsersGlobalKeyPrefix)
+	for _, interval := range []stdlibtime.Duration{cfg.GlobalAggregationInterval.Parent, cfg.GlobalAggregationInterval.Child} {
+		for key, value := range rebucketTotalUsers(totalUsers, interval) {
+			if _, found := existing[key]; !found {
+				planned[key] = value
+			}
+		}
+	}
+	for key, value := range rebucketTotalActiveUsers(activeUsers, cfg.GlobalAggregationInterval.Child) {
+		if _, found := existing[key]; !found {
+			planned[key] = value
+		}
+	}
+	log.Info(fmt.Sprintf("backfill planned %v new keys (parent format `%v`, child format `%v`)", len(planned), parentFormat, childFormat))
+	if !*dryRun {
+		insertGlobalValues(ctx, db, planned)
+	}
+	for key, value := range planned {
+		existing[key] = value
+	}
+	validate(&cfg, existing, *validateDays)
+}
+
+func dateFormat(interval stdlibtime.Duration) string {
+	switch interval { //nolint:exhaustive // We don't care about the others.
+	case stdlibtime.Minute:
+		return minuteFormat
+	case stdlibtime.Hour:
+		return hourFormat
+	case hoursInADay * stdlibtime.Hour:
+		return dayFormat
+	default:
+		log.Panic(fmt.Sprintf("invalid interval: %v", interval))
+
+		return ""
+	}
+}
+
+func getExistingGlobalValues(ctx context.Context, db *storage.DB) map[string]uint64 {
+	sql := `SELECT key, value FROM global WHERE starts_with(key, $1) OR starts_with(key, $2)`
+	rows, err := storage.Select[global](ctx, db, sql, totalUsersGlobalKeyPrefix, totalActiveUsersGlobalKeyPrefix)
+	log.Panic(errors.Wrap(err, "failed to select global values")) //nolint:revive // Intended.
+	res := make(map[string]uint64, len(rows))
+	for _, row := range rows {
+		res[row.Key] = row.Value
+	}
+
+	return res
+}
+
+func parseBuckets(values map[string]uint64, prefix string) []*bucket {
+	buckets := make([]*bucket, 0, len(values))
+	for key, value := range values {
+		suffix, found := strings.CutPrefix(key, prefix)
+		if !found {
+			continue
+		}
+		var interval stdlibtime.Duration
+		var format string
+		switch len(suffix) {
+		case len(dayFormat):
+			interval, format = hoursInADay*stdlibtime.Hour, dayFormat
+		case len(hourFormat):
+			interval, format = stdlibtime.Hour, hourFormat
+		case len(minuteFormat):
+			interval, format = stdlibtime.Minute, minuteFormat
+		default:
+			log.Error(errors.Errorf("skipping key `%v` with unknown date format", key))
+
+			continue
+		}
+		start, err := stdlibtime.Parse(format, suffix)
+		if err != nil {
+			log.Error(errors.Wrapf(err, "skipping key `%v` with invalid date", key))
+
+			continue
+		}
+		buckets = append(buckets, &bucket{start: start, interval: interval, value: value})
+	}
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return buckets[i].end().Before(buckets[j].end()) ||
+			(buckets[i].end().Equal(buckets[j].end()) && buckets[i].interval > buckets[j].interval)
+	})
+
+	return buckets
+}
+
+func (b *bucket) end() stdlibtime.Time {
+	return b.start.Add(b.interval)
+}
+
+func rebucketTotalUsers(buckets []*bucket, interval stdlibtime.Duration) map[string]uint64 {
+	format := dateFormat(interval)
+	res := make(map[string]uint64, len(buckets))
+	for _, b := range buckets { // They're sorted by end, so the latest snapshot wins.
+		res[totalUsersGlobalKeyPrefix+b.end().Add(-1).Truncate(interval).Format(format)] = b.value
+	}
+
+	return res
+}
+
+func rebucketTotalActiveUsers(buckets []*bucket, interval stdlibtime.Duration) map[string]uint64 {
+	format := dateFormat(interval)
+	res := make(map[string]uint64, len(buckets))
+	for _, b := range buckets {
+		for current := b.start.Truncate(interval); current.Before(b.end()); current = current.Add(interval) {
+			key := totalActiveUsersGlobalKeyPrefix + current.Format(format)
+			if res[key] < b.value {
+				res[key] = b.value
+			}
+		}
+	}
+
+	return res
+}
+
+func insertGlobalValues(ctx context.Context, db *storage.DB, values map[string]uint64) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for start := 0; start < len(keys); start += batchSize {
+		end := start + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		placeholders := make([]string, 0, end-start)
+		params := make([]any, 0, 2*(end-start)) //nolint:gomnd // Key and value.
+		for ix, key := range keys[start:end] {
+			placeholders = append(placeholders, fmt.Sprintf("($%v,$%v)", 2*ix+1, 2*ix+2)) //nolint:gomnd // Key and value.
+			params = append(params, key, values[key])
+		}
+		sql := fmt.Sprintf(`INSERT INTO global (key, value) VALUES %v ON CONFLICT (key) DO NOTHING`, strings.Join(placeholders, ","))
+		_, err := storage.Exec(ctx, db, sql, params...)
+		log.Panic(errors.Wrapf(err, "failed to insert global values batch [%v:%v]", start, end)) //nolint:revive // Intended.
+	}
+}
+
+// validate checks that the keys GetUserGrowth reads for the last `days` days are available.
+func validate(cfg *config, values map[string]uint64, days uint64) {
+	parent, child := cfg.GlobalAggregationInterval.Parent, cfg.GlobalAggregationInterval.Child
+	parentFormat, childFormat := dateFormat(parent), dateFormat(child)
+	now := stdlibtime.Now().UTC()
+	var missingTotal, missingActive []string
+	for day := stdlibtime.Duration(0); day < stdlibtime.Duration(days); day++ {
+		currentDay := now.Add(-1 * day * parent)
+		if key := totalUsersGlobalKeyPrefix + currentDay.Format(parentFormat); !hasKey(values, key) {
+			missingTotal = append(missingTotal, key)
+		}
+		parentStart := currentDay.Truncate(parent)
+		for current := parentStart; current.Before(parentStart.Add(parent)) && current.Before(now); current = current.Add(child) {
+			if key := totalActiveUsersGlobalKeyPrefix + current.Format(childFormat); !hasKey(values, key) {
+				missingActive = append(missingActive, key)
+			}
+		}
+	}
+	log.Info(fmt.Sprintf("validation for the last %v days: %v missing TOTAL_USERS parent keys %v, %v missing TOTAL_ACTIVE_USERS child keys %v",
+		days, len(missingTotal), firstN(missingTotal), len(missingActive), firstN(missingActive)))
+}
+
+func hasKey(values map[string]uint64, key string) bool {
+	_, found := values[key]
+
+	return found
+}
+
+func firstN(keys []string) []string {
+	if len(keys) > maxReportedMissingKeys {
+		return keys[:maxReportedMissingKeys]
+	}
+
+	return keys
+}
@@ -2,5 +2,7 @@
 CREATE TABLE IF NOT EXISTS merge_firebase_phone_login_with_ice_email_login (
                     created_at   timestamp DEFAULT current_timestamp,
                     email        text NOT NULL UNIQUE,
-                    phone_number text PRIMARY KEY
-                    );
\ No newline at end of file
+                    phone_number text PRIMARY KEY,
+                    phone_number_index text
+                    );
+ALTER TABLE merge_firebase_phone_login_with_ice_email_login ADD COLUMN IF NOT EXISTS phone_number_index text;
\ No newline at end of file
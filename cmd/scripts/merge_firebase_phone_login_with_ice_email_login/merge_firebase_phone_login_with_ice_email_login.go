@@ -53,11 +53,12 @@ func main() {
 	defer usersProcessor.Close()
 	defer authEmailLinkClient.Close()
 
+	indexPhoneNumbers(db, usersProcessor)
 	offset := uint64(0)
 	concurrencyGuard := make(chan struct{}, concurrencyCount)
 	wg := new(sync.WaitGroup)
 	for {
-		records := getUsersToMerge(db, defaultLimit, offset)
+		records := getUsersToMerge(db, usersProcessor, defaultLimit, offset)
 		if len(records) == 0 {
 			break
 		}
@@ -139,7 +140,28 @@ func updateFirebaseEmail(authClient auth.Client, usr *record, idx uint64) {
 	}
 }
 
-func getUsersToMerge(db *storage.DB, limit, offset uint64) []*record {
+// indexPhoneNumbers sets the blind indexes of the phone numbers to merge, for them to be joined with the encrypted ones of the users.
+func indexPhoneNumbers(db *storage.DB, usersProcessor users.Processor) {
+	for {
+		sql := `SELECT phone_number FROM merge_firebase_phone_login_with_ice_email_login WHERE phone_number_index IS NULL LIMIT $1`
+		records, err := storage.Select[record](context.Background(), db, sql, defaultLimit)
+		log.Panic(errors.Wrap(err, "can't select phone numbers to index")) //nolint:revive // Intended.
+		for _, rec := range records {
+			index := usersProcessor.PhoneNumberIndex(rec.PhoneNumber)
+			if index == nil {
+				return
+			}
+			sql = `UPDATE merge_firebase_phone_login_with_ice_email_login SET phone_number_index = $2 WHERE phone_number = $1`
+			_, err = storage.Exec(context.Background(), db, sql, rec.PhoneNumber, *index)
+			log.Panic(errors.Wrapf(err, "can't index phone number:%v", users.RedactedPhoneNumber(rec.PhoneNumber)))
+		}
+		if len(records) < defaultLimit {
+			return
+		}
+	}
+}
+
+func getUsersToMerge(db *storage.DB, usersProcessor users.Processor, limit, offset uint64) []*record {
 	params := []any{limit, offset}
 	sql := `SELECT 
 				coalesce(u.id,'') AS id,
@@ -148,11 +170,17 @@ func getUsersToMerge(db *storage.DB, limit, offset uint64) []*record {
 				coalesce(u.email,'') AS current_email
 			FROM merge_firebase_phone_login_with_ice_email_login m
 				LEFT JOIN users u
-					ON m.phone_number = u.phone_number
+					ON u.phone_number_index = m.phone_number_index
+						OR u.phone_number = m.phone_number
 			ORDER BY m.created_at ASC
 			LIMIT $1 OFFSET $2`
 	result, err := storage.Select[record](context.Background(), db, sql, params...)
 	log.Panic(errors.Wrapf(err, "can't select records for limit:%v, offset:%v", limit, offset))
+	for _, rec := range result {
+		info := users.SensitiveUserInformation{Email: rec.CurrentEmail}
+		usersProcessor.OpenSensitiveUserInformation(&info)
+		rec.CurrentEmail = info.Email
+	}
 
 	return result
 }
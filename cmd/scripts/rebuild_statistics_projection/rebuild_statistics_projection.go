@@ -0,0 +1,299 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users"
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+const (
+	applicationYamlEskimoKey = "users"
+
+	totalUsersGlobalKey             = "TOTAL_USERS"
+	totalUsersGlobalKeyPrefix       = "TOTAL_USERS_"
+	totalActiveUsersGlobalKeyPrefix = "TOTAL_ACTIVE_USERS_"
+
+	shadowKeyPrefix                = "REBUILD_"
+	shadowGlobalKeyPrefix          = shadowKeyPrefix + "GLOBAL_"
+	shadowUsersPerCountryKeyPrefix = shadowKeyPrefix + "USERS_PER_COUNTRY_"
+
+	dayFormat, hourFormat, minuteFormat = "2006-01-02", "2006-01-02T15", "2006-01-02T15:04"
+	hoursInADay                         = 24
+
+	batchSize           = 1000
+	defaultActiveWindow = hoursInADay * stdlibtime.Hour
+)
+
+type (
+	config struct {
+		GlobalAggregationInterval struct {
+			Parent stdlibtime.Duration `yaml:"parent"`
+			Child  stdlibtime.Duration `yaml:"child"`
+		} `yaml:"globalAggregationInterval"`
+	}
+	global struct {
+		Key   string `db:"key"`
+		Value uint64 `db:"value"`
+	}
+	countryCount struct {
+		Country   string `db:"country"`
+		UserCount uint64 `db:"user_count"`
+	}
+	bucketCount struct {
+		Bucket stdlibtime.Time `db:"bucket"`
+		Value  uint64          `db:"value"`
+	}
+	// projection is the rebuilt state of the statistics counters.
+	projection struct {
+		globals         map[string]uint64
+		usersPerCountry map[string]uint64
+		activeKeys      []string
+	}
+)
+
+// This script rebuilds the statistics counters (TOTAL_USERS, TOTAL_USERS_*, TOTAL_ACTIVE_USERS_* and users_per_country)
+// from the users table, for when they're lost or corrupted beyond what the counters reconciler can fix.
+// The rebuilt values are first written to a shadow namespace (REBUILD_* keys in the global table), then swapped with the live ones
+// in a single transaction, so the readers never see a partially rebuilt state.
+// Users are counted (like the live counters do) once they pass the liveness detection and mine afterwards, in the bucket they passed it.
+// Deleted users can't be replayed, so the rebuilt TOTAL_USERS_* history doesn't include them anymore.
+// Mining sessions history isn't kept either, so only the TOTAL_ACTIVE_USERS_* keys within `-active-window` are rebuilt, the older ones are left as they are.
+// Run it with the write API in maintenance mode, otherwise the counter updates made while it runs are lost by the swap.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only report what would change")
+	noSwap := flag.Bool("no-swap", false, "only write the shadow keys, for inspection, without swapping them with the live ones")
+	activeWindow := flag.Duration("active-window", defaultActiveWindow, "how far back to rebuild the TOTAL_ACTIVE_USERS_* keys")
+	flag.Parse()
+
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYamlEskimoKey, &cfg)
+
+	ctx := context.Background()
+	db := storage.MustConnect(ctx, "", applicationYamlEskimoKey)
+	defer db.Close()
+
+	now := stdlibtime.Now().UTC()
+	proj := &projection{globals: make(map[string]uint64), usersPerCountry: make(map[string]uint64)}
+	proj.rebuildUsersPerCountry(ctx, db)
+	proj.rebuildTotalUsers(ctx, db, &cfg, now)
+	proj.rebuildTotalActiveUsers(ctx, db, &cfg, now, *activeWindow)
+	proj.report(ctx, db)
+	if *dryRun {
+		return
+	}
+	proj.writeShadow(ctx, db)
+	if *noSwap {
+		log.Info(fmt.Sprintf("shadow keys written with the `%v` prefix, not swapped", shadowKeyPrefix))
+
+		return
+	}
+	proj.swap(ctx, db)
+	log.Info("statistics counters swapped with the rebuilt ones")
+}
+
+func dateFormat(interval stdlibtime.Duration) string {
+	switch interval { //nolint:exhaustive // We don't care about the others.
+	case stdlibtime.Minute:
+		return minuteFormat
+	case stdlibtime.Hour:
+		return hourFormat
+	case hoursInADay * stdlibtime.Hour:
+		return dayFormat
+	default:
+		log.Panic(fmt.Sprintf("invalid interval: %v", interval))
+
+		return ""
+	}
+}
+
+func dateTruncField(interval stdlibtime.Duration) string {
+	switch interval { //nolint:exhaustive // We don't care about the others.
+	case stdlibtime.Minute:
+		return "minute"
+	case stdlibtime.Hour:
+		return "hour"
+	default:
+		return "day"
+	}
+}
+
+// hadAtLeastAMiningAfterHumanVerificationSQLCondition matches the users that are part of the live counters.
+func hadAtLeastAMiningAfterHumanVerificationSQLCondition() string {
+	return fmt.Sprintf(`kyc_step_passed >= %[1]v
+		  AND last_mining_started_at IS NOT NULL
+		  AND kyc_steps_created_at[%[1]v] IS NOT NULL
+		  AND kyc_steps_last_updated_at[%[1]v] IS NOT NULL
+		  AND kyc_steps_created_at[%[1]v] < last_mining_started_at`, users.LivenessDetectionKYCStep)
+}
+
+func (p *projection) rebuildUsersPerCountry(ctx context.Context, db *storage.DB) {
+	sql := fmt.Sprintf(`SELECT country,
+							   count(1) AS user_count
+						FROM users
+						WHERE %v
+						GROUP BY country`, hadAtLeastAMiningAfterHumanVerificationSQLCondition())
+	rows, err := storage.Select[countryCount](ctx, db, sql)
+	log.Panic(errors.Wrap(err, "failed to count users per country")) //nolint:revive // Intended.
+	var total uint64
+	for _, row := range rows {
+		p.usersPerCountry[row.Country] = row.UserCount
+		total += row.UserCount
+	}
+	p.globals[totalUsersGlobalKey] = total
+}
+
+// rebuildTotalUsers replays the users into the TOTAL_USERS_* snapshots: the child keys of the buckets where the total changed
+// and the parent keys of every bucket since the first user, as GetUserGrowth reads all of them.
+func (p *projection) rebuildTotalUsers(ctx context.Context, db *storage.DB, cfg *config, now stdlibtime.Time) {
+	parent, child := cfg.GlobalAggregationInterval.Parent, cfg.GlobalAggregationInterval.Child
+	sql := fmt.Sprintf(`SELECT date_trunc('%[1]v', kyc_steps_created_at[%[2]v]) AS bucket,
+							   count(1) 								   AS value
+						FROM users
+						WHERE %[3]v
+						GROUP BY 1
+						ORDER BY 1`, dateTruncField(child), users.LivenessDetectionKYCStep, hadAtLeastAMiningAfterHumanVerificationSQLCondition())
+	buckets, err := storage.Select[bucketCount](ctx, db, sql)
+	log.Panic(errors.Wrap(err, "failed to count users per bucket")) //nolint:revive // Intended.
+	if len(buckets) == 0 {
+		return
+	}
+	var total uint64
+	parentFormat, childFormat := dateFormat(parent), dateFormat(child)
+	for ix, current := 0, buckets[0].Bucket.Truncate(parent); !current.After(now); current = current.Add(parent) {
+		for ; ix < len(buckets) && buckets[ix].Bucket.Before(current.Add(parent)); ix++ {
+			total += buckets[ix].Value
+			p.globals[totalUsersGlobalKeyPrefix+buckets[ix].Bucket.Format(childFormat)] = total
+		}
+		p.globals[totalUsersGlobalKeyPrefix+current.Format(parentFormat)] = total
+	}
+}
+
+// rebuildTotalActiveUsers counts, for every child bucket within the window, the users whose last mining session overlaps it.
+// Older buckets can't be rebuilt this way, because the users could have mined again since.
+func (p *projection) rebuildTotalActiveUsers(ctx context.Context, db *storage.DB, cfg *config, now stdlibtime.Time, window stdlibtime.Duration) {
+	child := cfg.GlobalAggregationInterval.Child
+	childFormat := dateFormat(child)
+	sql := `SELECT count(1) AS value
+			FROM users
+			WHERE last_mining_started_at < $1
+			  AND last_mining_ended_at >= $2`
+	for start := now.Add(-window).Truncate(child); !start.After(now); start = start.Add(child) {
+		count, err := storage.Get[global](ctx, db, sql, start.Add(child), start)
+		log.Panic(errors.Wrapf(err, "failed to count active users since %v", start)) //nolint:revive // Intended.
+		key := totalActiveUsersGlobalKeyPrefix + start.Format(childFormat)
+		p.globals[key] = count.Value
+		p.activeKeys = append(p.activeKeys, key)
+	}
+}
+
+func (p *projection) report(ctx context.Context, db *storage.DB) {
+	sql := `SELECT key, value FROM global WHERE key = $1 OR starts_with(key, $2) OR key = ANY($3)`
+	rows, err := storage.Select[global](ctx, db, sql, totalUsersGlobalKey, totalUsersGlobalKeyPrefix, p.activeKeys)
+	log.Panic(errors.Wrap(err, "failed to select live global values")) //nolint:revive // Intended.
+	live := make(map[string]uint64, len(rows))
+	for _, row := range rows {
+		live[row.Key] = row.Value
+	}
+	changed, removed := diff(live, p.globals)
+	log.Info(fmt.Sprintf("rebuilt %v global keys: %v changed, %v removed, TOTAL_USERS %v -> %v",
+		len(p.globals), changed, removed, live[totalUsersGlobalKey], p.globals[totalUsersGlobalKey]))
+	countries, err := storage.Select[countryCount](ctx, db, `SELECT country, user_count FROM users_per_country`)
+	log.Panic(errors.Wrap(err, "failed to select live users_per_country")) //nolint:revive // Intended.
+	live = make(map[string]uint64, len(countries))
+	for _, row := range countries {
+		live[row.Country] = row.UserCount
+	}
+	changed, removed = diff(live, p.usersPerCountry)
+	log.Info(fmt.Sprintf("rebuilt %v countries: %v changed, %v removed", len(p.usersPerCountry), changed, removed))
+}
+
+func diff(live, rebuilt map[string]uint64) (changed, removed int) {
+	for key, value := range rebuilt {
+		if liveValue, found := live[key]; !found || liveValue != value {
+			changed++
+		}
+	}
+	for key := range live {
+		if _, found := rebuilt[key]; !found {
+			removed++
+		}
+	}
+
+	return changed, removed
+}
+
+// writeShadow replaces any leftovers of a previous run with the rebuilt values, under the shadow prefixes.
+func (p *projection) writeShadow(ctx context.Context, db *storage.DB) {
+	_, err := storage.Exec(ctx, db, `DELETE FROM global WHERE starts_with(key, $1)`, shadowKeyPrefix)
+	log.Panic(errors.Wrap(err, "failed to delete the shadow keys of a previous run")) //nolint:revive // Intended.
+	shadow := make(map[string]uint64, len(p.globals)+len(p.usersPerCountry))
+	for key, value := range p.globals {
+		shadow[shadowGlobalKeyPrefix+key] = value
+	}
+	for country, value := range p.usersPerCountry {
+		shadow[shadowUsersPerCountryKeyPrefix+country] = value
+	}
+	keys := make([]string, 0, len(shadow))
+	for key := range shadow {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for start := 0; start < len(keys); start += batchSize {
+		end := start + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		placeholders := make([]string, 0, end-start)
+		params := make([]any, 0, 2*(end-start)) //nolint:gomnd // Key and value.
+		for ix, key := range keys[start:end] {
+			placeholders = append(placeholders, fmt.Sprintf("($%v,$%v)", 2*ix+1, 2*ix+2)) //nolint:gomnd // Key and value.
+			params = append(params, key, shadow[key])
+		}
+		sql := fmt.Sprintf(`INSERT INTO global (key, value) VALUES %v`, strings.Join(placeholders, ","))
+		_, err = storage.Exec(ctx, db, sql, params...)
+		log.Panic(errors.Wrapf(err, "failed to insert shadow values batch [%v:%v]", start, end)) //nolint:revive // Intended.
+	}
+}
+
+// swap replaces the live counters with the shadow ones, atomically. The TOTAL_ACTIVE_USERS_* keys outside the window are kept.
+func (p *projection) swap(ctx context.Context, db *storage.DB) {
+	err := storage.DoInTransaction(ctx, db, func(conn storage.QueryExecer) error {
+		sql := `DELETE FROM global WHERE key = $1 OR starts_with(key, $2) OR key = ANY($3)`
+		if _, err := storage.Exec(ctx, conn, sql, totalUsersGlobalKey, totalUsersGlobalKeyPrefix, p.activeKeys); err != nil {
+			return errors.Wrap(err, "failed to delete the live global keys")
+		}
+		sql = `INSERT INTO global (key, value)
+			   SELECT substr(key, length($1) + 1), value
+			   FROM global
+			   WHERE starts_with(key, $1)`
+		if _, err := storage.Exec(ctx, conn, sql, shadowGlobalKeyPrefix); err != nil {
+			return errors.Wrap(err, "failed to swap in the shadow global keys")
+		}
+		if _, err := storage.Exec(ctx, conn, `DELETE FROM users_per_country`); err != nil {
+			return errors.Wrap(err, "failed to delete the live users_per_country")
+		}
+		sql = `INSERT INTO users_per_country (country, user_count)
+			   SELECT substr(key, length($1) + 1), value
+			   FROM global
+			   WHERE starts_with(key, $1)`
+		if _, err := storage.Exec(ctx, conn, sql, shadowUsersPerCountryKeyPrefix); err != nil {
+			return errors.Wrap(err, "failed to swap in the shadow users_per_country")
+		}
+		_, err := storage.Exec(ctx, conn, `DELETE FROM global WHERE starts_with(key, $1)`, shadowKeyPrefix)
+
+		return errors.Wrap(err, "failed to delete the shadow keys")
+	})
+	log.Panic(errors.Wrap(err, "failed to swap the statistics counters")) //nolint:revive // Intended.
+}
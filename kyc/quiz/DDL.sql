@@ -10,6 +10,7 @@ create table if not exists questions
     unique (question, language),
     primary key (language, id)
 );
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS difficulty smallint NOT NULL DEFAULT 1;
 
 create table if not exists failed_quiz_sessions
 (
@@ -48,6 +49,7 @@ create table if not exists quiz_sessions
     user_id            text       primary key references users (id) ON DELETE CASCADE,
     language           text       not null
 );
+ALTER TABLE quiz_sessions ADD COLUMN IF NOT EXISTS seed bigint NOT NULL DEFAULT 0;
 
 CREATE INDEX IF NOT EXISTS quiz_sessions_lookup1_ix ON quiz_sessions (ended_successfully,ended_at DESC NULLS LAST);
 
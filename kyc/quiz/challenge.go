@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package quiz
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	quizchallenge "github.com/ice-blockchain/eskimo/kyc/quiz/internal/challenge"
+)
+
+func ContextWithChallengeToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, challengeTokenCtxValueKey, token) //nolint:revive,staticcheck // Not an issue.
+}
+
+// checkAntiBotChallenge requires the users that look like they're farming the quiz (with an elevated duplicate account score)
+// to solve the configured challenge (captcha or attestation) before starting a session.
+func (r *repositoryImpl) checkAntiBotChallenge(ctx context.Context, userID UserID) error {
+	if r.challenge == nil {
+		return nil
+	}
+	candidates, err := r.Users.GetUserDuplicateAccountCandidates(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get duplicate account candidates for userID:%v", userID)
+	}
+	if len(candidates) == 0 || candidates[0].Score < r.live().AntiBotChallenge.MinFraudScore {
+		return nil
+	}
+	token, _ := ctx.Value(challengeTokenCtxValueKey).(string) //nolint:errcheck // Not needed.
+	if token == "" {
+		return errors.Wrapf(ErrChallengeRequired, "fraud score %v for userID:%v", candidates[0].Score, userID)
+	}
+	if err = r.challenge.Verify(ctx, &quizchallenge.Request{UserID: userID, Token: token}); err != nil {
+		if errors.Is(err, quizchallenge.ErrInvalidToken) {
+			return errors.Wrapf(ErrChallengeFailed, "%v", err)
+		}
+
+		return errors.Wrapf(err, "failed to verify the anti-bot challenge for userID:%v", userID)
+	}
+
+	return nil
+}
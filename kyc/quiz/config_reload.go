@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package quiz
+
+import (
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+func (*readRepository) ReloadableConfigKeys() []string {
+	return []string{
+		applicationYamlKey + ".availabilityWindowSeconds",
+		applicationYamlKey + ".maxSessionDurationSeconds",
+		applicationYamlKey + ".maxQuestionsPerSession",
+		applicationYamlKey + ".maxWrongAnswersPerSession",
+		applicationYamlKey + ".sessionCoolDownSeconds",
+		applicationYamlKey + ".maxAttemptsAllowed",
+		applicationYamlKey + ".adaptiveDifficulty",
+		applicationYamlKey + ".antiBotChallenge.minFraudScore",
+	}
+}
+
+// ReloadConfig copies only the reloadable values from the re-read config, so the rest keep the values they were started with.
+func (r *readRepository) ReloadConfig() error {
+	var loaded config
+	if err := viper.UnmarshalKey(applicationYamlKey, &loaded); err != nil {
+		return errors.Wrapf(err, "failed to load config by key %q", applicationYamlKey)
+	}
+	if err := multierror.Append(loaded.validateRead(), loaded.validate()).ErrorOrNil(); err != nil {
+		return errors.Wrapf(err, "invalid reloaded config of %v", applicationYamlKey)
+	}
+	latest := r.config
+	latest.AvailabilityWindowSeconds = loaded.AvailabilityWindowSeconds
+	latest.MaxSessionDurationSeconds = loaded.MaxSessionDurationSeconds
+	latest.MaxQuestionsPerSession = loaded.MaxQuestionsPerSession
+	latest.MaxWrongAnswersPerSession = loaded.MaxWrongAnswersPerSession
+	latest.SessionCoolDownSeconds = loaded.SessionCoolDownSeconds
+	latest.MaxAttemptsAllowed = loaded.MaxAttemptsAllowed
+	latest.AdaptiveDifficulty = loaded.AdaptiveDifficulty
+	latest.AntiBotChallenge.MinFraudScore = loaded.AntiBotChallenge.MinFraudScore
+	r.config.reloaded.Store(&latest)
+
+	return nil
+}
+
+// live returns the config with the latest values of ReloadableConfigKeys, which must always be read through it.
+func (c *config) live() *config {
+	if c.reloaded != nil {
+		if latest := c.reloaded.Load(); latest != nil {
+			return latest
+		}
+	}
+
+	return c
+}
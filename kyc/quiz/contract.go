@@ -10,6 +10,7 @@ import (
 	"sync/atomic"
 	stdlibtime "time"
 
+	quizchallenge "github.com/ice-blockchain/eskimo/kyc/quiz/internal/challenge"
 	"github.com/ice-blockchain/eskimo/users"
 	"github.com/ice-blockchain/wintr/connectors/storage/v2"
 	"github.com/ice-blockchain/wintr/time"
@@ -29,6 +30,10 @@ type (
 		io.Closer
 		GetQuizStatus(ctx context.Context, userIDs ...string) (map[string]*QuizStatus, error)
 		CheckHealth(ctx context.Context) error
+		// ReloadableConfigKeys are the keys of the config values (the quiz parameters) applied by ReloadConfig.
+		ReloadableConfigKeys() []string
+		// ReloadConfig applies the latest values of ReloadableConfigKeys, without a restart.
+		ReloadConfig() error
 	}
 	Repository interface {
 		ReadRepository
@@ -39,11 +44,14 @@ type (
 		CheckQuizStatus(ctx context.Context, userID UserID) (*QuizStatus, error)
 
 		ContinueQuizSession(ctx context.Context, userID UserID, question, answer uint8) (*Quiz, error)
+
+		GetQuizCooldown(ctx context.Context, userID UserID) (*QuizCooldown, error)
 	}
 
 	UserRepository interface {
 		GetUserByID(ctx context.Context, userID string) (*users.UserProfile, error)
 		ModifyUser(ctx context.Context, usr *users.User, profilePicture *multipart.FileHeader) error
+		GetUserDuplicateAccountCandidates(ctx context.Context, userID UserID) ([]*users.DuplicateAccountCandidate, error)
 	}
 	QuizStatus struct { //nolint:revive // Nope cuz we want to be able to embed this
 		KYCQuizAvailabilityStartedAt *time.Time   `json:"kycQuizAvailabilityStartedAt" db:"kyc_quiz_availability_started_at"`
@@ -59,8 +67,14 @@ type (
 	Result string
 
 	Quiz struct {
-		Progress *Progress `json:"progress,omitempty"`
-		Result   Result    `json:"result,omitempty"`
+		Progress       *Progress  `json:"progress,omitempty"`
+		CooldownEndsAt *time.Time `json:"cooldownEndsAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		Result         Result     `json:"result,omitempty"`
+	}
+
+	QuizCooldown struct {
+		CooldownEndsAt *time.Time `json:"cooldownEndsAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		InCooldown     bool       `json:"inCooldown" example:"true"`
 	}
 
 	Progress struct {
@@ -72,10 +86,11 @@ type (
 	}
 
 	Question struct {
-		Text    string   `json:"text" example:"Какая температура на улице?" db:"question"`
-		Options []string `json:"options" example:"+21,-2,+33,0" db:"options"`
-		Number  uint8    `json:"number" example:"1"`
-		ID      uint     `json:"-" db:"id"`
+		Text       string   `json:"text" example:"Какая температура на улице?" db:"question"`
+		Options    []string `json:"options" example:"+21,-2,+33,0" db:"options"`
+		Number     uint8    `json:"number" example:"1"`
+		Difficulty uint8    `json:"-" db:"difficulty"`
+		ID         uint     `json:"-" db:"id"`
 	}
 )
 
@@ -88,12 +103,15 @@ var (
 	ErrUnknownQuestionNumber    = newError("unknown question number")
 	ErrUnknownSession           = newError("unknown session and/or user")
 	ErrNotAvailable             = newError("quiz kyc not available")
+	ErrChallengeRequired        = newError("anti-bot challenge required")
+	ErrChallengeFailed          = newError("anti-bot challenge failed")
 )
 
 const (
 	applicationYamlKey = "kyc/quiz"
 
-	clientTypeCtxValueKey = "clientTypeCtxValueKey"
+	clientTypeCtxValueKey     = "clientTypeCtxValueKey"
+	challengeTokenCtxValueKey = "challengeTokenCtxValueKey"
 
 	requestDeadline = 25 * stdlibtime.Second
 )
@@ -116,6 +134,8 @@ type (
 		Questions      []uint8    `db:"questions"`
 		Answers        []uint8    `db:"answers"`
 		CorrectAnswers []uint8    `db:"correct_answers"`
+		Difficulties   []uint8    `db:"difficulties"`
+		Seed           int64      `db:"seed"`
 	}
 	readRepository struct {
 		DB       *storage.DB
@@ -124,7 +144,8 @@ type (
 	}
 	repositoryImpl struct {
 		*readRepository
-		Users UserRepository
+		Users     UserRepository
+		challenge quizchallenge.Provider
 	}
 
 	kycConfigJSON struct {
@@ -138,6 +159,13 @@ type (
 		} `json:"web-quiz-kyc"` //nolint:tagliatelle // .
 	}
 
+	adaptiveDifficulty struct {
+		// Seed is used for every session, if set, so that the questions they get are reproducible (I.E. in tests).
+		Seed                    int64 `yaml:"seed"`
+		CorrectAnswersToLevelUp uint8 `yaml:"correctAnswersToLevelUp"`
+		Enabled                 bool  `yaml:"enabled"`
+	}
+
 	config struct {
 		alertFrequency            *atomic.Pointer[stdlibtime.Duration]
 		kycConfigJSON             *atomic.Pointer[kycConfigJSON]
@@ -154,5 +182,14 @@ type (
 		SessionCoolDownSeconds    int    `yaml:"sessionCoolDownSeconds"`
 		EnableAlerts              bool   `yaml:"enable-alerts" mapstructure:"enable-alerts"` //nolint:tagliatelle // .
 		MaxAttemptsAllowed        uint8  `yaml:"maxAttemptsAllowed"`
+
+		AdaptiveDifficulty adaptiveDifficulty `yaml:"adaptiveDifficulty"`
+		AntiBotChallenge   struct {
+			// MinFraudScore is the duplicate account score starting from which the users have to solve the challenge to start a session.
+			MinFraudScore uint64 `yaml:"minFraudScore"`
+		} `yaml:"antiBotChallenge"`
+
+		// reloaded is the config with the latest values of the reloadable keys, once they're reloaded at runtime.
+		reloaded *atomic.Pointer[config]
 	}
 )
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package quiz
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+func (r *repositoryImpl) newSessionSeed() int64 {
+	if r.live().AdaptiveDifficulty.Seed != 0 {
+		return r.live().AdaptiveDifficulty.Seed
+	}
+
+	return rand.Int63() //nolint:gosec // Not an issue.
+}
+
+// selectAdaptiveQuestions starts every session with the easiest questions; harder ones replace them as the user answers correctly.
+func (r *repositoryImpl) selectAdaptiveQuestions(ctx context.Context, tx storage.QueryExecer, lang string, seed int64) ([]*Question, error) {
+	const stmt = `
+select id, options, question, difficulty from questions where "language" = $1 order by id
+	`
+
+	questions, err := storage.Select[Question](ctx, tx, stmt, lang)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select questions")
+	}
+
+	return pickInitialQuestions(questions, r.live().MaxQuestionsPerSession, seed), nil
+}
+
+func pickInitialQuestions(questions []*Question, count int, seed int64) []*Question {
+	rnd := rand.New(rand.NewSource(seed)) //nolint:gosec // Not an issue.
+	rnd.Shuffle(len(questions), func(i, j int) {
+		questions[i], questions[j] = questions[j], questions[i]
+	})
+	sort.SliceStable(questions, func(i, j int) bool {
+		return questions[i].Difficulty < questions[j].Difficulty
+	})
+	if len(questions) > count {
+		questions = questions[:count]
+	}
+
+	return questions
+}
+
+// levelUpDifficulty returns the difficulty of the next question, if the user just got another `correctAnswersToLevelUp` answers right in a row.
+func levelUpDifficulty(difficulties, answers, correctAnswers []uint8, correctAnswersToLevelUp uint8) (uint8, bool) {
+	streak := 0
+	for ix := len(answers) - 1; ix >= 0 && answers[ix] == correctAnswers[ix]; ix-- {
+		streak++
+	}
+	if streak == 0 || streak%int(correctAnswersToLevelUp) != 0 {
+		return 0, false
+	}
+
+	return difficulties[len(answers)-1] + 1, true
+}
+
+func (r *repositoryImpl) adaptNextQuestion(ctx context.Context, tx storage.QueryExecer, userID UserID, progress *userProgress, answers []uint8) error {
+	next := len(answers)
+	if !r.live().AdaptiveDifficulty.Enabled || next >= len(progress.Questions) {
+		return nil
+	}
+	difficulty, levelUp := levelUpDifficulty(progress.Difficulties, answers, progress.CorrectAnswers, r.live().AdaptiveDifficulty.CorrectAnswersToLevelUp)
+	if !levelUp || progress.Difficulties[next] >= difficulty {
+		return nil
+	}
+	sessionQuestions := make([]int64, 0, len(progress.Questions))
+	for _, id := range progress.Questions {
+		sessionQuestions = append(sessionQuestions, int64(id))
+	}
+	const selectStmt = `
+select id from questions where "language" = $1 and difficulty = $2 and id != all($3) order by id
+	`
+	candidates, err := storage.Select[struct {
+		ID uint `db:"id"`
+	}](ctx, tx, selectStmt, progress.Lang, difficulty, sessionQuestions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to select questions with difficulty %v", difficulty)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	rnd := rand.New(rand.NewSource(progress.Seed + int64(next))) //nolint:gosec // Not an issue.
+	questionID := candidates[rnd.Intn(len(candidates))].ID
+
+	// $1: user_id.
+	// $2: the index (1-based) of the question to replace.
+	// $3: the new question.
+	const updateStmt = `
+update quiz_sessions set questions[$2] = $3 where user_id = $1
+	`
+	if _, err = storage.Exec(ctx, tx, updateStmt, userID, next+1, questionID); err != nil {
+		return errors.Wrapf(err, "failed to replace question %v with %v for userID:%v", next+1, questionID, userID)
+	}
+	progress.Questions[next] = uint8(questionID)
+
+	return nil
+}
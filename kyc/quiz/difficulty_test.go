@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package quiz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func helperQuestionIDs(questions []*Question) []uint {
+	ids := make([]uint, 0, len(questions))
+	for _, q := range questions {
+		ids = append(ids, q.ID)
+	}
+
+	return ids
+}
+
+func TestPickInitialQuestions(t *testing.T) {
+	t.Parallel()
+
+	newQuestions := func() []*Question {
+		return []*Question{
+			{ID: 1, Difficulty: 3},
+			{ID: 2, Difficulty: 1},
+			{ID: 3, Difficulty: 2},
+			{ID: 4, Difficulty: 1},
+			{ID: 5, Difficulty: 1},
+			{ID: 6, Difficulty: 2},
+		}
+	}
+
+	picked := pickInitialQuestions(newQuestions(), 4, 42)
+	require.Len(t, picked, 4)
+	require.ElementsMatch(t, []uint{2, 4, 5}, helperQuestionIDs(picked[:3]))
+	require.EqualValues(t, 2, picked[3].Difficulty)
+	require.Equal(t, helperQuestionIDs(picked), helperQuestionIDs(pickInitialQuestions(newQuestions(), 4, 42)))
+
+	require.Len(t, pickInitialQuestions(newQuestions(), 10, 42), 6)
+}
+
+func TestLevelUpDifficulty(t *testing.T) {
+	t.Parallel()
+
+	correct := []uint8{1, 2, 3, 1, 2}
+	difficulties := []uint8{1, 1, 2, 2, 3}
+
+	_, levelUp := levelUpDifficulty(difficulties, []uint8{1}, correct, 2)
+	require.False(t, levelUp)
+
+	difficulty, levelUp := levelUpDifficulty(difficulties, []uint8{1, 2}, correct, 2)
+	require.True(t, levelUp)
+	require.EqualValues(t, 2, difficulty)
+
+	_, levelUp = levelUpDifficulty(difficulties, []uint8{1, 2, 3}, correct, 2)
+	require.False(t, levelUp)
+
+	difficulty, levelUp = levelUpDifficulty(difficulties, []uint8{1, 2, 3, 1}, correct, 2)
+	require.True(t, levelUp)
+	require.EqualValues(t, 3, difficulty)
+
+	_, levelUp = levelUpDifficulty(difficulties, []uint8{1, 2, 0}, correct, 2)
+	require.False(t, levelUp)
+
+	_, levelUp = levelUpDifficulty(difficulties, []uint8{0, 2, 3}, correct, 2)
+	require.True(t, levelUp)
+}
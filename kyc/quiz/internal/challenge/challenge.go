@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package quizchallenge
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// New returns the configured challenge provider, or nil if the challenge is disabled.
+func New(applicationYAMLKey string) Provider {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+	log.Panic(cfg.validate(applicationYAMLKey)) //nolint:revive // That's intended.
+
+	switch cfg.AntiBotChallenge.Provider {
+	case StubProvider:
+		return &stub{validToken: cfg.AntiBotChallenge.Stub.ValidToken}
+	case TurnstileProvider:
+		return newSiteVerify(applicationYAMLKey, &cfg, turnstileVerifyURL)
+	case RecaptchaProvider:
+		return newSiteVerify(applicationYAMLKey, &cfg, recaptchaVerifyURL)
+	default:
+		return nil
+	}
+}
+
+// ValidateConfig reports the problems of the config of the provider that New would use.
+func ValidateConfig(applicationYAMLKey string) error {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+
+	return cfg.validate(applicationYAMLKey)
+}
+
+func (c *config) validate(applicationYAMLKey string) error {
+	switch c.AntiBotChallenge.Provider {
+	case "":
+		return nil
+	case StubProvider:
+		if c.AntiBotChallenge.Stub.ValidToken == "" {
+			return errors.Errorf("`%v.antiBotChallenge.stub.validToken` is missing", applicationYAMLKey)
+		}
+	case TurnstileProvider, RecaptchaProvider:
+		if c.secret(applicationYAMLKey) == "" {
+			return errors.Errorf("`%v.antiBotChallenge.secret` (or the %v env variable) is missing for provider `%v`",
+				applicationYAMLKey, secretEnv, c.AntiBotChallenge.Provider)
+		}
+	default:
+		return errors.Errorf("`%v.antiBotChallenge.provider` is unsupported: `%v`", applicationYAMLKey, c.AntiBotChallenge.Provider)
+	}
+
+	return nil
+}
+
+func (c *config) secret(applicationYAMLKey string) string {
+	if c.AntiBotChallenge.Secret != "" {
+		return c.AntiBotChallenge.Secret
+	}
+
+	return loadFromEnv(applicationYAMLKey, secretEnv)
+}
+
+func (s *stub) Verify(ctx context.Context, r *Request) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	if r.Token != s.validToken {
+		return errors.Wrapf(ErrInvalidToken, "stub rejected token for userID:%v", r.UserID)
+	}
+
+	return nil
+}
+
+func newSiteVerify(applicationYAMLKey string, cfg *config, url string) *siteVerify {
+	return &siteVerify{provider: cfg.AntiBotChallenge.Provider, url: url, secret: cfg.secret(applicationYAMLKey)}
+}
+
+func (s *siteVerify) Verify(ctx context.Context, r *Request) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, requestDeadline)
+	defer cancel()
+	var result siteVerifyResponse
+	resp, err := req.
+		SetContext(reqCtx).
+		SetFormData(map[string]string{
+			"secret":   s.secret,
+			"response": r.Token,
+		}).
+		SetSuccessResult(&result).
+		Post(s.url)
+	if err != nil {
+		return errors.Wrapf(err, "%v siteverify request failed for userID:%v", s.provider, r.UserID)
+	}
+	if !resp.IsSuccessState() {
+		return errors.Errorf("%v siteverify request failed with status: %v", s.provider, resp.GetStatusCode())
+	}
+	if !result.Success {
+		return errors.Wrapf(ErrInvalidToken, "%v rejected token for userID:%v, error codes: %v", s.provider, r.UserID, result.ErrorCodes)
+	}
+
+	return nil
+}
+
+func loadFromEnv(applicationYAMLKey, env string) string {
+	module := strings.ToUpper(strings.ReplaceAll(strings.ReplaceAll(applicationYAMLKey, "-", "_"), "/", "_"))
+	if val := os.Getenv(module + "_" + env); val != "" {
+		return val
+	}
+
+	return os.Getenv(env)
+}
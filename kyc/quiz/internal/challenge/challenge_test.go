@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package quizchallenge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStubVerify(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	provider := &stub{validToken: "valid"}
+	require.NoError(t, provider.Verify(ctx, &Request{UserID: "a", Token: "valid"}))
+	require.ErrorIs(t, provider.Verify(ctx, &Request{UserID: "a", Token: "bogus"}), ErrInvalidToken)
+	require.ErrorIs(t, provider.Verify(ctx, &Request{UserID: "a"}), ErrInvalidToken)
+}
+
+func TestSiteVerify(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.PostFormValue("secret"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostFormValue("response") == "valid" {
+			_, _ = w.Write([]byte(`{"success":true}`))
+		} else {
+			_, _ = w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+		}
+	}))
+	defer srv.Close()
+
+	provider := &siteVerify{provider: TurnstileProvider, url: srv.URL, secret: "secret"}
+	require.NoError(t, provider.Verify(ctx, &Request{UserID: "a", Token: "valid"}))
+	require.ErrorIs(t, provider.Verify(ctx, &Request{UserID: "a", Token: "bogus"}), ErrInvalidToken)
+}
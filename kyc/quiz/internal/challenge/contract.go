@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package quizchallenge
+
+import (
+	"context"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+)
+
+// Public API.
+
+const (
+	StubProvider      ProviderType = "stub"
+	TurnstileProvider ProviderType = "turnstile"
+	RecaptchaProvider ProviderType = "recaptcha"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid anti-bot challenge token")
+)
+
+type (
+	ProviderType string
+	Request      struct {
+		UserID string
+		Token  string
+	}
+	Provider interface {
+		// Verify returns ErrInvalidToken if the token of the solved challenge is not valid.
+		Verify(ctx context.Context, req *Request) error
+	}
+)
+
+// Private API.
+
+const (
+	turnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	recaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+	requestDeadline    = 25 * stdlibtime.Second
+	secretEnv          = "ANTI_BOT_CHALLENGE_SECRET" //nolint:gosec // It's just the name.
+)
+
+type (
+	// | stub accepts only the configured token. It's meant for tests and local environments.
+	stub struct {
+		validToken string
+	}
+	// | siteVerify verifies the tokens using the `siteverify` API, which both Cloudflare Turnstile and Google reCAPTCHA implement.
+	siteVerify struct {
+		provider ProviderType
+		url      string
+		secret   string
+	}
+	siteVerifyResponse struct {
+		ErrorCodes []string `json:"error-codes"` //nolint:tagliatelle // It's their API.
+		Success    bool     `json:"success"`
+	}
+	// | config holds the configuration of this package mounted from `application.yaml`.
+	config struct {
+		AntiBotChallenge struct {
+			Stub struct {
+				ValidToken string `yaml:"validToken"`
+			} `yaml:"stub"`
+			Secret   string       `yaml:"secret"`
+			Provider ProviderType `yaml:"provider"`
+		} `yaml:"antiBotChallenge"`
+	}
+)
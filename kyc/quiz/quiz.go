@@ -8,8 +8,10 @@ import (
 	"sync/atomic"
 	stdlibtime "time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
+	quizchallenge "github.com/ice-blockchain/eskimo/kyc/quiz/internal/challenge"
 	"github.com/ice-blockchain/eskimo/users"
 	appcfg "github.com/ice-blockchain/wintr/config"
 	"github.com/ice-blockchain/wintr/connectors/storage/v2"
@@ -18,22 +20,8 @@ import (
 )
 
 func mustLoadConfig() config {
-	cfg := mustLoadReadConfig()
-	if cfg.MaxSessionDurationSeconds == 0 {
-		panic("maxSessionDurationSeconds is not set")
-	}
-
-	if cfg.MaxQuestionsPerSession == 0 {
-		panic("maxQuestionsPerSession is not set")
-	}
-
-	if cfg.SessionCoolDownSeconds == 0 {
-		panic("sessionCoolDownSeconds is not set")
-	}
-
-	if cfg.MaxWrongAnswersPerSession == 0 {
-		panic("maxWrongAnswersPerSession is not set")
-	}
+	cfg, err := loadConfig()
+	log.Panic(err) //nolint:revive // .
 
 	defaultAlertFrequency := alertFrequency
 	cfg.alertFrequency = new(atomic.Pointer[stdlibtime.Duration])
@@ -43,30 +31,79 @@ func mustLoadConfig() config {
 }
 
 func mustLoadReadConfig() config {
-	var cfg config
+	cfg, err := loadReadConfig()
+	log.Panic(err) //nolint:revive // .
+
+	return cfg
+}
+
+// ValidateConfig reports, at once, all the problems of the config needed by NewRepository.
+func ValidateConfig() error {
+	_, err := loadConfig()
+
+	return multierror.Append(err, quizchallenge.ValidateConfig(applicationYamlKey)).ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+}
+
+func loadConfig() (config, error) {
+	cfg, err := loadReadConfig()
 
+	return cfg, multierror.Append(err, cfg.validate()).ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+}
+
+func loadReadConfig() (config, error) {
+	var cfg config
 	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
+	cfg.reloaded = new(atomic.Pointer[config])
 
-	if cfg.MaxResetCount == nil {
-		panic("maxResetCount is not set")
-	}
+	return cfg, cfg.validateRead().ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+}
 
-	if cfg.GlobalStartDate == "" {
-		panic("globalStartDate is not set")
+func (c *config) validate() *multierror.Error {
+	var mErr *multierror.Error
+	for _, required := range []struct {
+		key   string
+		value int
+	}{
+		{key: "maxSessionDurationSeconds", value: c.MaxSessionDurationSeconds},
+		{key: "maxQuestionsPerSession", value: c.MaxQuestionsPerSession},
+		{key: "sessionCoolDownSeconds", value: c.SessionCoolDownSeconds},
+		{key: "maxWrongAnswersPerSession", value: c.MaxWrongAnswersPerSession},
+	} {
+		if required.value == 0 {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.%v` is not set", applicationYamlKey, required.key))
+		}
 	}
-	globalStartDate, err := stdlibtime.ParseInLocation(stdlibtime.RFC3339Nano, cfg.GlobalStartDate, stdlibtime.UTC)
-	log.Panic(err) //nolint:revive // .
-	cfg.globalStartDate = time.New(globalStartDate)
-
-	if cfg.AvailabilityWindowSeconds == 0 {
-		panic("availabilityWindowSeconds is not set")
+	if c.AdaptiveDifficulty.Enabled && c.AdaptiveDifficulty.CorrectAnswersToLevelUp == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.adaptiveDifficulty.correctAnswersToLevelUp` is not set", applicationYamlKey))
 	}
+	if c.EnableAlerts && (c.AlertSlackWebhook == "" || c.Environment == "") {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.alert-slack-webhook` and `%v.environment` are required when alerts are enabled", applicationYamlKey, applicationYamlKey))
+	}
+
+	return mErr
+}
 
-	if cfg.MaxAttemptsAllowed == 0 {
-		panic("maxAttemptsAllowed is not set")
+// validateRead also parses the globalStartDate.
+func (c *config) validateRead() *multierror.Error {
+	var mErr *multierror.Error
+	if c.MaxResetCount == nil {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.maxResetCount` is not set", applicationYamlKey))
+	}
+	if c.GlobalStartDate == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.globalStartDate` is not set", applicationYamlKey))
+	} else if globalStartDate, err := stdlibtime.ParseInLocation(stdlibtime.RFC3339Nano, c.GlobalStartDate, stdlibtime.UTC); err != nil {
+		mErr = multierror.Append(mErr, errors.Wrapf(err, "`%v.globalStartDate` is invalid", applicationYamlKey))
+	} else {
+		c.globalStartDate = time.New(globalStartDate)
+	}
+	if c.AvailabilityWindowSeconds == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.availabilityWindowSeconds` is not set", applicationYamlKey))
+	}
+	if c.MaxAttemptsAllowed == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.maxAttemptsAllowed` is not set", applicationYamlKey))
 	}
 
-	return cfg
+	return mErr
 }
 
 func (e *quizError) Error() string {
@@ -104,7 +141,8 @@ func newRepositoryImpl(ctx context.Context, userRepo UserRepository) *repository
 			Shutdown: db.Close,
 			config:   mustLoadConfig(),
 		},
-		Users: userRepo,
+		Users:     userRepo,
+		challenge: quizchallenge.New(applicationYamlKey),
 	}
 }
 
@@ -139,7 +177,7 @@ func (r *repositoryImpl) validateKycStep(user *users.User) error {
 		return ErrNotAvailable
 	}
 
-	if sessionCoolDown := stdlibtime.Duration(r.config.SessionCoolDownSeconds) * stdlibtime.Second; user.KYCStepPassed == nil ||
+	if sessionCoolDown := stdlibtime.Duration(r.live().SessionCoolDownSeconds) * stdlibtime.Second; user.KYCStepPassed == nil ||
 		*user.KYCStepPassed < users.FacialRecognitionKYCStep ||
 		(user.KYCStepPassed != nil &&
 			user.KYCStepsLastUpdatedAt != nil &&
@@ -226,8 +264,8 @@ func (r *repositoryImpl) prepareUserForReset(ctx context.Context, userID UserID,
 		return errors.Wrap(err, "failed to get failed attempts count")
 	}
 
-	if count < int(r.config.MaxAttemptsAllowed) {
-		for i := 0; i < int(r.config.MaxAttemptsAllowed)-count; i++ {
+	if count < int(r.live().MaxAttemptsAllowed) {
+		for i := 0; i < int(r.live().MaxAttemptsAllowed)-count; i++ {
 			ts := now.Add(-stdlibtime.Second * stdlibtime.Duration(i))
 			_, err = r.addFailedAttempt(ctx, userID, time.New(ts), tx, true)
 			if err != nil {
@@ -331,9 +369,9 @@ func (r *readRepository) GetQuizStatus(ctx context.Context, userIDs ...string) (
 		sql,
 		userIDs,
 		r.config.globalStartDate.Time,
-		r.config.AvailabilityWindowSeconds,
+		r.live().AvailabilityWindowSeconds,
 		*r.config.MaxResetCount,
-		r.config.MaxAttemptsAllowed,
+		r.live().MaxAttemptsAllowed,
 	)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
@@ -349,21 +387,29 @@ func (r *readRepository) GetQuizStatus(ctx context.Context, userIDs ...string) (
 	return res, nil
 }
 
-func (r *repositoryImpl) SelectQuestions(ctx context.Context, tx storage.QueryExecer, lang string) ([]*Question, error) {
+func (r *repositoryImpl) SelectQuestions(ctx context.Context, tx storage.QueryExecer, lang string, seed int64) ([]*Question, error) {
 	const stmt = `
 select id, options, question from questions where "language" = $1 order by random() limit $2
 	`
 
-	questions, err := storage.Select[Question](ctx, tx, stmt, lang, r.config.MaxQuestionsPerSession)
+	var (
+		questions []*Question
+		err       error
+	)
+	if r.live().AdaptiveDifficulty.Enabled {
+		questions, err = r.selectAdaptiveQuestions(ctx, tx, lang, seed)
+	} else {
+		questions, err = storage.Select[Question](ctx, tx, stmt, lang, r.live().MaxQuestionsPerSession)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to select questions")
 	} else if len(questions) == 0 {
 		return nil, errors.Wrap(ErrUnknownLanguage, lang)
 	}
 
-	if len(questions) < r.config.MaxQuestionsPerSession {
+	if len(questions) < r.live().MaxQuestionsPerSession {
 		panic(fmt.Sprintf("not enough questions for language %v: wanted %d but has only %v",
-			lang, r.config.MaxQuestionsPerSession, len(questions)))
+			lang, r.live().MaxQuestionsPerSession, len(questions)))
 	}
 
 	for i := range questions {
@@ -414,7 +460,7 @@ func (r *repositoryImpl) moveFailedAttempts(ctx context.Context, tx storage.Quer
 		return false, errors.Wrap(err, "failed to get failed attempts count")
 	}
 
-	if count < int(r.config.MaxAttemptsAllowed) {
+	if count < int(r.live().MaxAttemptsAllowed) {
 		return false, nil
 	}
 
@@ -492,7 +538,7 @@ func (r *repositoryImpl) finishUnfinishedSession(
 	`
 	data, err := storage.ExecOne[struct {
 		CooldownAt *time.Time `db:"cooldown_at"`
-	}](ctx, tx, stmt, userID, now.Time, r.config.SessionCoolDownSeconds)
+	}](ctx, tx, stmt, userID, now.Time, r.live().SessionCoolDownSeconds)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			err = nil
@@ -520,12 +566,14 @@ func (r *repositoryImpl) startNewSession( //nolint:funlen //.
 	userID UserID,
 	lang string,
 	questions []*Question,
+	seed int64,
 ) (*Quiz, error) {
 	// $1: user_id.
 	// $2: language.
 	// $3: questions.
 	// $4: session cool down (seconds).
 	// $5: max session duration (seconds).
+	// $6: seed.
 	const stmt = `
 	with session_failed as (
 		select
@@ -553,13 +601,14 @@ func (r *repositoryImpl) startNewSession( //nolint:funlen //.
 	),
 	session_upsert as (
 		insert into quiz_sessions
-			(user_id, language, questions, started_at, answers)
+			(user_id, language, questions, started_at, answers, seed)
 		select
 			$1,
 			$2,
 			$3,
 			now(),
-			'{}'::smallint[]
+			'{}'::smallint[],
+			$6
 		where
 			coalesce((select false from session_failed), true) and
 			coalesce((select
@@ -575,7 +624,8 @@ func (r *repositoryImpl) startNewSession( //nolint:funlen //.
 			started_at = excluded.started_at,
 			questions = excluded.questions,
 			answers = excluded.answers,
-			language = excluded.language
+			language = excluded.language,
+			seed = excluded.seed
 		returning
 			quiz_sessions.*,
 			quiz_sessions.started_at + make_interval(secs => $5) as deadline
@@ -605,7 +655,7 @@ func (r *repositoryImpl) startNewSession( //nolint:funlen //.
 		ActiveEndedAt           *time.Time `db:"active_ended_at"`
 		UpsertStartedAt         *time.Time `db:"upsert_started_at"`
 		UpsertDeadline          *time.Time `db:"upsert_deadline"`
-	}](ctx, r.DB, stmt, userID, lang, questionsToSlice(questions), r.config.SessionCoolDownSeconds, r.config.MaxSessionDurationSeconds)
+	}](ctx, r.DB, stmt, userID, lang, questionsToSlice(questions), r.live().SessionCoolDownSeconds, r.live().MaxSessionDurationSeconds, seed)
 	if err != nil {
 		if errors.Is(err, storage.ErrRelationNotFound) {
 			err = ErrUnknownUser
@@ -617,7 +667,7 @@ func (r *repositoryImpl) startNewSession( //nolint:funlen //.
 	switch {
 	case data.FailedAt != nil: // Failed session is still in cool down.
 		return nil, errors.Wrapf(ErrSessionFinishedWithError, "wait until %v",
-			data.FailedAt.Add(stdlibtime.Duration(r.config.SessionCoolDownSeconds)*stdlibtime.Second))
+			data.FailedAt.Add(stdlibtime.Duration(r.live().SessionCoolDownSeconds)*stdlibtime.Second))
 
 	case data.ActiveStartedAt != nil && data.UpsertStartedAt == nil: // Active session is still running or ended with some result.
 		if *data.ActiveFinished {
@@ -659,7 +709,8 @@ func (r *repositoryImpl) IsQuizEnabledForUser(ctx context.Context, userID UserID
 }
 
 func (r *repositoryImpl) StartQuizSession(ctx context.Context, userID UserID, lang string) (*Quiz, error) {
-	questions, err := r.SelectQuestions(ctx, r.DB, lang)
+	seed := r.newSessionSeed()
+	questions, err := r.SelectQuestions(ctx, r.DB, lang, seed)
 	if err != nil {
 		return nil, err
 	}
@@ -683,7 +734,48 @@ func (r *repositoryImpl) StartQuizSession(ctx context.Context, userID UserID, la
 		return nil, err
 	}
 
-	return r.startNewSession(ctx, userID, lang, questions)
+	if err = r.checkAntiBotChallenge(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return r.startNewSession(ctx, userID, lang, questions, seed)
+}
+
+func (r *repositoryImpl) GetQuizCooldown(ctx context.Context, userID UserID) (*QuizCooldown, error) {
+	cooldownEndsAt, err := r.cooldownEndsAt(ctx, r.DB, userID, *time.Now().Time)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuizCooldown{CooldownEndsAt: cooldownEndsAt, InCooldown: cooldownEndsAt != nil}, nil
+}
+
+func (r *repositoryImpl) cooldownEndsAt(ctx context.Context, tx storage.QueryExecer, userID UserID, now stdlibtime.Time) (*time.Time, error) {
+	// $1: user_id.
+	// $2: now.
+	// $3: session cool down (seconds).
+	const stmt = `
+	select
+		max(ended_at) + make_interval(secs => $3) as cooldown_ends_at
+	from
+		failed_quiz_sessions
+	where
+		user_id = $1
+	having
+		max(ended_at) > ($2::timestamp - make_interval(secs => $3))
+	`
+	data, err := storage.ExecOne[struct {
+		CooldownEndsAt *time.Time `db:"cooldown_ends_at"`
+	}](ctx, tx, stmt, userID, now, r.live().SessionCoolDownSeconds)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil //nolint:nilnil // Nope.
+		}
+
+		return nil, errors.Wrapf(err, "failed to get quiz cooldown for userID:%v", userID)
+	}
+
+	return data.CooldownEndsAt, nil
 }
 
 func calculateProgress(correctAnswers, currentAnswers []uint8) (correctNum, incorrectNum uint8) {
@@ -726,6 +818,8 @@ select
 	session.language,
 	answers,
 	array_agg(questions.correct_option order by q.nr) as correct_answers,
+	array_agg(questions.difficulty order by q.nr) as difficulties,
+	seed,
 	ended_successfully
 from
 	quiz_sessions session,
@@ -741,10 +835,11 @@ group by
 	questions,
 	session.language,
 	answers,
+	seed,
 	ended_successfully
 `
 
-	data, err := storage.ExecOne[userSession](ctx, tx, stmt, userID, r.config.MaxSessionDurationSeconds)
+	data, err := storage.ExecOne[userSession](ctx, tx, stmt, userID, r.live().MaxSessionDurationSeconds)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			return userProgress{}, ErrUnknownSession
@@ -761,7 +856,7 @@ group by
 		return userProgress{}, ErrSessionFinishedWithError
 	}
 
-	deadline := data.StartedAt.Add(stdlibtime.Duration(r.config.MaxSessionDurationSeconds) * stdlibtime.Second)
+	deadline := data.StartedAt.Add(stdlibtime.Duration(r.live().MaxSessionDurationSeconds) * stdlibtime.Second)
 	if deadline.Before(now) {
 		return userProgress{}, errSessionExpired
 	}
@@ -928,7 +1023,9 @@ func (r *repositoryImpl) ContinueQuizSession( //nolint:funlen,revive,gocognit //
 		if pErr != nil {
 			if errors.Is(pErr, errSessionExpired) {
 				quiz = &Quiz{Result: FailureResult}
-				pErr = r.UserMarkSessionAsFinished(ctx, userID, now, tx, false, false)
+				if pErr = r.UserMarkSessionAsFinished(ctx, userID, now, tx, false, false); pErr == nil {
+					quiz.CooldownEndsAt, pErr = r.cooldownEndsAt(ctx, tx, userID, now)
+				}
 			}
 
 			return pErr
@@ -956,10 +1053,17 @@ func (r *repositoryImpl) ContinueQuizSession( //nolint:funlen,revive,gocognit //
 				},
 			}
 
-			if int(incorrectNum) > r.config.MaxWrongAnswersPerSession {
+			if int(incorrectNum) > r.live().MaxWrongAnswersPerSession {
 				quiz.Result = FailureResult
+				if err = r.UserMarkSessionAsFinished(ctx, userID, now, tx, false, false); err != nil {
+					return err
+				}
+				quiz.CooldownEndsAt, err = r.cooldownEndsAt(ctx, tx, userID, now)
 
-				return r.UserMarkSessionAsFinished(ctx, userID, now, tx, false, false)
+				return err
+			}
+			if aErr = r.adaptNextQuestion(ctx, tx, userID, &progress, newAnswers); aErr != nil {
+				return aErr
 			}
 		default:
 			answeredQuestionsCount = len(progress.Answers)
@@ -141,6 +141,10 @@ func (*mockUserReader) GetUserByID(ctx context.Context, userID UserID) (*UserPro
 	return profile, nil
 }
 
+func (*mockUserReader) GetUserDuplicateAccountCandidates(context.Context, UserID) ([]*users.DuplicateAccountCandidate, error) {
+	return nil, nil
+}
+
 func (m *mockUserReader) ModifyUser(ctx context.Context, usr *users.User, profilePicture *multipart.FileHeader) error {
 	if m.OnModifyUser != nil {
 		return m.OnModifyUser(ctx, usr, profilePicture)
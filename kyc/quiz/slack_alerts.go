@@ -102,7 +102,7 @@ func (r *repositoryImpl) sendAlertToSlack(ctx context.Context, ticker *stdlibtim
 					  count(1) AS counter
 			   FROM quiz_resets
 			   WHERE resets[1] >= $1`
-		stats, err = storage.Select[quizStats](ctx, conn, sql, alert.LastAlertAt.Time, r.config.MaxSessionDurationSeconds)
+		stats, err = storage.Select[quizStats](ctx, conn, sql, alert.LastAlertAt.Time, r.live().MaxSessionDurationSeconds)
 		if err != nil {
 			return errors.Wrap(err, "failed to select stats")
 		}
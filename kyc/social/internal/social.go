@@ -3,8 +3,12 @@
 package social
 
 import (
+	"net/url"
 	"os"
 
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
 	appcfg "github.com/ice-blockchain/wintr/config"
 	"github.com/ice-blockchain/wintr/log"
 )
@@ -28,6 +32,25 @@ func loadConfig() *config {
 	return &cfg
 }
 
+// ValidateConfig reports, at once, all the problems of the config needed by the verifiers.
+func ValidateConfig() error {
+	conf := loadConfig()
+	var mErr *multierror.Error
+	if conf.WebScrapingAPI.URL == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.web-scraping-api.url` (or the WEB_SCRAPING_API_URL env variable) is missing", applicationYAMLKey))
+	} else if _, err := url.Parse(conf.WebScrapingAPI.URL); err != nil {
+		mErr = multierror.Append(mErr, errors.Wrapf(err, "`%v.web-scraping-api.url` is invalid", applicationYAMLKey))
+	}
+	if conf.SocialLinks.Facebook.AppID == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.social-links.facebook.app-id` (or the FACEBOOK_APP_ID env variable) is missing", applicationYAMLKey))
+	}
+	if conf.SocialLinks.Facebook.AppSecret == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.social-links.facebook.app-secret` (or the FACEBOOK_APP_SECRET env variable) is missing", applicationYAMLKey))
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+}
+
 func New(st StrategyType) Verifier {
 	conf := loadConfig()
 
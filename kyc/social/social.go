@@ -11,6 +11,7 @@ import (
 	"text/template"
 	stdlibtime "time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
 	social "github.com/ice-blockchain/eskimo/kyc/social/internal"
@@ -81,6 +82,27 @@ func New(ctx context.Context, usrRepo UserRepository) Repository {
 	return repo
 }
 
+// ValidateConfig reports, at once, all the problems of the config needed by New.
+func ValidateConfig() error {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
+	mErr := multierror.Append(nil, social.ValidateConfig())
+	if cfg.EnableAlerts && (cfg.AlertSlackWebhook == "" || cfg.Environment == "") {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.alert-slack-webhook` and `%v.environment` are required when alerts are enabled", applicationYamlKey, applicationYamlKey))
+	}
+	if cfg.SessionWindow <= 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.session-window` must be positive", applicationYamlKey))
+	}
+	if cfg.MaxSessionsAllowed <= 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.max-sessions-allowed` must be positive", applicationYamlKey))
+	}
+	if cfg.MaxAttemptsAllowed == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.max-attempts-allowed` must be positive", applicationYamlKey))
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+}
+
 func (r *repository) Close() error {
 	return errors.Wrap(r.db.Close(), "closing kyc/social repository failed")
 }
@@ -47,17 +47,24 @@ END $$;
 ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_step_blocked smallint NOT NULL DEFAULT 0;
 ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_steps_last_updated_at timestamp[];
 ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_steps_created_at timestamp[];
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_number_index text;
+CREATE UNIQUE INDEX IF NOT EXISTS users_phone_number_index_key ON users (phone_number_index);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_index text;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_index_key ON users (email_index);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS residency text;
 INSERT INTO users (created_at,updated_at,phone_number,phone_number_hash,email,id,username,profile_picture_name,referred_by,city,country,mining_blockchain_account_address,blockchain_account_address, lookup)
                          VALUES (current_timestamp,current_timestamp,'bogus','bogus','bogus','bogus','bogus','bogus.jpg','bogus','bogus','RO','bogus','bogus',to_tsvector('bogus')),
                                 (current_timestamp,current_timestamp,'icenetwork','icenetwork','icenetwork','icenetwork','icenetwork','icenetwork.jpg','icenetwork','icenetwork','RO','icenetwork','icenetwork',to_tsvector('icenetwork'))
 ON CONFLICT DO NOTHING;
 CREATE INDEX IF NOT EXISTS users_referred_by_ix ON users (referred_by);
+CREATE INDEX IF NOT EXISTS users_last_mining_ended_at_ix ON users (last_mining_ended_at);
 CREATE EXTENSION IF NOT EXISTS btree_gin;
 CREATE INDEX IF NOT EXISTS users_lookup_gin_idx ON users USING GIN (lookup);
 CREATE TABLE IF NOT EXISTS users_per_country  (
                     user_count BIGINT NOT NULL DEFAULT 0,
                     country text primary key
                      );
+ALTER TABLE users_per_country ADD COLUMN IF NOT EXISTS updated_at timestamp NOT NULL DEFAULT current_timestamp;
 
 CREATE TABLE IF NOT EXISTS kyc_steps_reset_requests  (
                     user_id text primary key,
@@ -127,8 +134,137 @@ CREATE TABLE IF NOT EXISTS global  (
                     value bigint NOT NULL,
                     key text primary key)
                     WITH (FILLFACTOR = 70);
+ALTER TABLE global ADD COLUMN IF NOT EXISTS updated_at timestamp NOT NULL DEFAULT current_timestamp;
 INSERT INTO global (key,value) VALUES ('TOTAL_USERS', 0) ON CONFLICT DO NOTHING;
 
+CREATE TABLE IF NOT EXISTS counters_reconciliation_audit (
+                    reconciled_at timestamp NOT NULL,
+                    expected      bigint NOT NULL,
+                    actual        bigint NOT NULL,
+                    corrected     boolean NOT NULL DEFAULT false,
+                    key           text NOT NULL,
+                    primary key(reconciled_at, key));
+CREATE INDEX IF NOT EXISTS counters_reconciliation_audit_key_ix ON counters_reconciliation_audit (key, reconciled_at);
+
+CREATE TABLE IF NOT EXISTS user_search_index_backfills (
+                    completed_at timestamp,
+                    index_name   text NOT NULL primary key,
+                    last_user_id text NOT NULL DEFAULT '');
+
+CREATE TABLE IF NOT EXISTS profile_picture_moderation_events (
+                    created_at               timestamp NOT NULL,
+                    user_id                  text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    picture_name             text NOT NULL,
+                    reverted_to_picture_name text NOT NULL,
+                    provider                 text NOT NULL,
+                    violations               text[] NOT NULL,
+                    reverted                 boolean NOT NULL DEFAULT false,
+                    primary key(user_id, picture_name));
+
+CREATE TABLE IF NOT EXISTS user_search_audit (
+                    searched_at   timestamp NOT NULL,
+                    admin_user_id text NOT NULL,
+                    field         text NOT NULL,
+                    mode          text NOT NULL,
+                    keyword       text NOT NULL,
+                    reason        text NOT NULL);
+CREATE INDEX IF NOT EXISTS user_search_audit_searched_at_ix ON user_search_audit (searched_at);
+
+CREATE INDEX IF NOT EXISTS device_metadata_device_unique_id_ix ON device_metadata (device_unique_id);
+
+CREATE TABLE IF NOT EXISTS device_metadata_ips (
+                    last_seen_at     timestamp NOT NULL,
+                    user_id          text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    device_unique_id text NOT NULL,
+                    ip               text NOT NULL,
+                    primary key(user_id, device_unique_id, ip));
+CREATE INDEX IF NOT EXISTS device_metadata_ips_ip_ix ON device_metadata_ips (ip);
+CREATE INDEX IF NOT EXISTS device_metadata_ips_last_seen_at_ix ON device_metadata_ips (last_seen_at);
+
+ALTER TABLE device_metadata ADD COLUMN IF NOT EXISTS attested_at timestamp;
+ALTER TABLE device_metadata ADD COLUMN IF NOT EXISTS attestation_provider text;
+ALTER TABLE device_metadata ADD COLUMN IF NOT EXISTS attestation_passed boolean;
+ALTER TABLE device_metadata ADD COLUMN IF NOT EXISTS app_version text;
+ALTER TABLE device_metadata ADD COLUMN IF NOT EXISTS os_version text;
+ALTER TABLE device_metadata ADD COLUMN IF NOT EXISTS app_build_number text;
+ALTER TABLE device_metadata ADD COLUMN IF NOT EXISTS locale text;
+ALTER TABLE device_metadata ADD COLUMN IF NOT EXISTS screen_density NUMERIC;
+UPDATE device_metadata
+SET app_version = (SELECT 'v' || m[1]::bigint || '.' || m[2]::bigint || '.' || m[3]::bigint
+                   FROM regexp_match(readable_version, '^v?(\d{1,9})\.(\d{1,9})\.(\d{1,9})(?:\.\d{1,9})?$') m)
+WHERE app_version IS NULL
+  AND readable_version ~ '^v?\d{1,9}\.\d{1,9}\.\d{1,9}(\.\d{1,9})?$';
+UPDATE device_metadata
+SET os_version = (SELECT 'v' || m[1]::bigint || '.' || COALESCE(m[2], '0')::bigint || '.' || COALESCE(m[3], '0')::bigint
+                  FROM regexp_match(system_version, '^(\d{1,9})(?:\.(\d{1,9}))?(?:\.(\d{1,9}))?(?:\.\d{1,9})?$') m)
+WHERE os_version IS NULL
+  AND system_version ~ '^\d{1,9}(\.\d{1,9}){0,3}$';
+
+CREATE TABLE IF NOT EXISTS device_attestation_challenges (
+                    created_at       timestamp NOT NULL,
+                    user_id          text NOT NULL,
+                    device_unique_id text NOT NULL,
+                    challenge        text NOT NULL,
+                    FOREIGN KEY (user_id, device_unique_id) REFERENCES device_metadata (user_id, device_unique_id) ON DELETE CASCADE,
+                    primary key(user_id, device_unique_id));
+
+CREATE TABLE IF NOT EXISTS app_version_requirements (
+                    updated_at   timestamp NOT NULL,
+                    force_update boolean NOT NULL DEFAULT false,
+                    platform     text NOT NULL primary key,
+                    min_version  text NOT NULL,
+                    updated_by   text NOT NULL);
+
+CREATE TABLE IF NOT EXISTS duplicate_account_candidates (
+                    detected_at       timestamp NOT NULL,
+                    updated_at        timestamp NOT NULL,
+                    agenda_overlap    double precision NOT NULL DEFAULT 0,
+                    score             bigint NOT NULL,
+                    signals           text[] NOT NULL,
+                    user_id           text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    duplicate_user_id text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    primary key(user_id, duplicate_user_id));
+CREATE INDEX IF NOT EXISTS duplicate_account_candidates_duplicate_user_id_ix ON duplicate_account_candidates (duplicate_user_id);
+CREATE INDEX IF NOT EXISTS duplicate_account_candidates_score_ix ON duplicate_account_candidates (score DESC);
+
+CREATE TABLE IF NOT EXISTS country_changes (
+                    created_at   timestamp NOT NULL,
+                    decided_at   timestamp,
+                    user_id      text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    from_country text NOT NULL,
+                    to_country   text NOT NULL,
+                    to_city      text NOT NULL DEFAULT '',
+                    status       text NOT NULL,
+                    decided_by   text NOT NULL DEFAULT '',
+                    primary key(user_id, created_at));
+CREATE UNIQUE INDEX IF NOT EXISTS country_changes_pending_ix ON country_changes (user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS country_changes_status_created_at_ix ON country_changes (status, created_at);
+
+CREATE TABLE IF NOT EXISTS kyc_step_attempts (
+                    attempted_at timestamp NOT NULL,
+                    kyc_step     smallint NOT NULL,
+                    successful   boolean NOT NULL,
+                    user_id      text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    primary key(user_id, kyc_step, attempted_at));
+
+-- No foreign key on purpose: the proofs of deletion must outlive the deleted users.
+CREATE TABLE IF NOT EXISTS kyc_data_purges (
+                    purged_at            timestamp NOT NULL,
+                    provider_status_code smallint NOT NULL,
+                    user_id              text NOT NULL,
+                    requested_by         text NOT NULL,
+                    provider_response    text NOT NULL DEFAULT '',
+                    primary key(user_id, purged_at));
+
+CREATE TABLE IF NOT EXISTS maintenance_mode (
+                    updated_at timestamp NOT NULL,
+                    eta        timestamp,
+                    enabled    boolean NOT NULL,
+                    id         smallint NOT NULL DEFAULT 1 primary key CHECK (id = 1),
+                    updated_by text NOT NULL,
+                    messages   jsonb NOT NULL DEFAULT '{}'::jsonb,
+                    allowlist  text[] NOT NULL DEFAULT '{}');
+
 CREATE TABLE IF NOT EXISTS referral_acquisition_history (
      T1                      BIGINT DEFAULT 0,
      T1_TODAY                BIGINT DEFAULT 0,
@@ -163,4 +299,240 @@ DO $$ BEGIN
         ALTER TABLE processed_referrals
             ADD CONSTRAINT processed_referrals_id_refby_deleted_pkey PRIMARY KEY(user_id, referred_by, deleted);
     end if;
-END $$;
\ No newline at end of file
+END $$;
+CREATE TABLE IF NOT EXISTS user_deletion_batches (
+                    created_at   timestamp NOT NULL,
+                    finished_at  timestamp,
+                    id           text NOT NULL primary key,
+                    requested_by text NOT NULL,
+                    reason       text NOT NULL,
+                    mode         text NOT NULL);
+
+CREATE TABLE IF NOT EXISTS user_deletion_batch_items (
+                    processed_at timestamp,
+                    claimed_at   timestamp,
+                    batch_id     text NOT NULL REFERENCES user_deletion_batches(id) ON DELETE CASCADE,
+                    user_id      text NOT NULL,
+                    outcome      text,
+                    error        text,
+                    primary key (batch_id, user_id));
+CREATE INDEX IF NOT EXISTS user_deletion_batch_items_pending_ix ON user_deletion_batch_items (claimed_at) WHERE processed_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_import_batches (
+                    created_at   timestamp NOT NULL,
+                    finished_at  timestamp,
+                    id           text NOT NULL primary key,
+                    requested_by text NOT NULL,
+                    reason       text NOT NULL,
+                    snapshots    text NOT NULL);
+
+CREATE TABLE IF NOT EXISTS user_import_batch_items (
+                    processed_at timestamp,
+                    claimed_at   timestamp,
+                    line         bigint NOT NULL,
+                    batch_id     text NOT NULL REFERENCES user_import_batches(id) ON DELETE CASCADE,
+                    user_id      text,
+                    record       jsonb,
+                    outcome      text,
+                    error        text,
+                    primary key (batch_id, line));
+CREATE INDEX IF NOT EXISTS user_import_batch_items_pending_ix ON user_import_batch_items (claimed_at) WHERE processed_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS squatted_usernames (
+                    flagged_at   timestamp NOT NULL,
+                    expires_at   timestamp NOT NULL,
+                    released_at  timestamp,
+                    exempted_at  timestamp,
+                    user_id      text NOT NULL primary key REFERENCES users(id) ON DELETE CASCADE,
+                    username     text NOT NULL,
+                    exempted_by  text);
+CREATE INDEX IF NOT EXISTS squatted_usernames_expires_at_ix ON squatted_usernames (expires_at) WHERE released_at IS NULL AND exempted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS account_merges (
+                    merged_at      timestamp NOT NULL,
+                    source_user_id text NOT NULL primary key,
+                    target_user_id text NOT NULL,
+                    requested_by   text NOT NULL,
+                    reason         text NOT NULL);
+CREATE INDEX IF NOT EXISTS account_merges_target_user_id_ix ON account_merges (target_user_id);
+
+CREATE TABLE IF NOT EXISTS referral_integrity_audit (
+                    checked_at           timestamp NOT NULL,
+                    user_id              text NOT NULL,
+                    referred_by          text NOT NULL,
+                    repaired_referred_by text,
+                    type                 text NOT NULL,
+                    primary key(checked_at, user_id));
+
+CREATE TABLE IF NOT EXISTS user_rectifications (
+                    rectified_at  timestamp NOT NULL,
+                    changes       jsonb NOT NULL,
+                    user_id       text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    rectified_by  text NOT NULL,
+                    reason_code   text NOT NULL,
+                    justification text NOT NULL,
+                    overridden    boolean NOT NULL,
+                    primary key(user_id, rectified_at));
+
+CREATE TABLE IF NOT EXISTS kyc_funnel_statistics (
+                    day        date NOT NULL,
+                    updated_at timestamp NOT NULL,
+                    kyc_step   smallint NOT NULL,
+                    entered    bigint NOT NULL DEFAULT 0,
+                    passed     bigint NOT NULL DEFAULT 0,
+                    failed     bigint NOT NULL DEFAULT 0,
+                    blocked    bigint NOT NULL DEFAULT 0,
+                    primary key(day, kyc_step));
+
+CREATE TABLE IF NOT EXISTS email_domain_signups (
+                    day        date NOT NULL,
+                    updated_at timestamp NOT NULL,
+                    domain     text NOT NULL,
+                    signups    bigint NOT NULL DEFAULT 0,
+                    primary key(day, domain));
+
+CREATE TABLE IF NOT EXISTS user_blocks (
+                    created_at      timestamp NOT NULL,
+                    user_id         text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    blocked_user_id text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    primary key(user_id, blocked_user_id));
+CREATE INDEX IF NOT EXISTS user_blocks_blocked_user_id_ix ON user_blocks (blocked_user_id);
+
+CREATE TABLE IF NOT EXISTS user_blockchain_addresses (
+                    created_at  timestamp NOT NULL,
+                    verified_at timestamp NOT NULL,
+                    user_id     text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    chain       text NOT NULL,
+                    address     text NOT NULL,
+                    primary key(chain, address));
+CREATE INDEX IF NOT EXISTS user_blockchain_addresses_user_id_ix ON user_blockchain_addresses (user_id);
+
+CREATE TABLE IF NOT EXISTS blockchain_address_challenges (
+                    created_at timestamp NOT NULL,
+                    user_id    text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    chain      text NOT NULL,
+                    address    text NOT NULL,
+                    nonce      text NOT NULL,
+                    primary key(user_id, chain, address));
+
+CREATE TABLE IF NOT EXISTS user_reports (
+                    created_at  timestamp NOT NULL,
+                    resolved_at timestamp,
+                    user_id     text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    reported_by text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    resolved_by text NOT NULL DEFAULT '',
+                    reason      text NOT NULL,
+                    text        text NOT NULL DEFAULT '',
+                    status      text NOT NULL,
+                    primary key(user_id, reported_by, created_at));
+CREATE UNIQUE INDEX IF NOT EXISTS user_reports_pending_ix ON user_reports (user_id, reported_by) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS user_reports_reported_by_ix ON user_reports (reported_by);
+
+CREATE TABLE IF NOT EXISTS referral_invitations (
+                    created_at        timestamp NOT NULL,
+                    joined_at         timestamp,
+                    user_id           text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    invitee_user_id   text REFERENCES users(id) ON DELETE SET NULL,
+                    contact_hash      text NOT NULL,
+                    contact_hint      text NOT NULL,
+                    unsubscribe_token text NOT NULL UNIQUE,
+                    channel           text NOT NULL,
+                    status            text NOT NULL,
+                    primary key(user_id, contact_hash));
+CREATE INDEX IF NOT EXISTS referral_invitations_contact_hash_ix ON referral_invitations (contact_hash);
+CREATE INDEX IF NOT EXISTS referral_invitations_user_id_created_at_ix ON referral_invitations (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS referral_invitation_unsubscriptions (
+                    unsubscribed_at timestamp NOT NULL,
+                    contact_hash    text NOT NULL primary key);
+
+CREATE TABLE IF NOT EXISTS secondary_emails (
+                    created_at                             timestamp NOT NULL,
+                    confirmed_at                           timestamp,
+                    confirmation_code_expires_at           timestamp,
+                    confirmation_code_wrong_attempts_count bigint NOT NULL DEFAULT 0,
+                    user_id                                text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    email                                  text NOT NULL,
+                    confirmation_code                      text,
+                    primary key(email, user_id));
+CREATE UNIQUE INDEX IF NOT EXISTS secondary_emails_confirmed_email_ix ON secondary_emails (email) WHERE confirmed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS secondary_emails_user_id_ix ON secondary_emails (user_id);
+
+CREATE TABLE IF NOT EXISTS profile_sync_bases (
+                    updated_at timestamp NOT NULL,
+                    user_id    text NOT NULL primary key REFERENCES users(id) ON DELETE CASCADE,
+                    checksum   text NOT NULL,
+                    profile    jsonb NOT NULL);
+
+CREATE TABLE IF NOT EXISTS user_dates_of_birth (
+                    updated_at    timestamp NOT NULL,
+                    user_id       text NOT NULL primary key REFERENCES users(id) ON DELETE CASCADE,
+                    date_of_birth text NOT NULL);
+
+CREATE TABLE IF NOT EXISTS underage_users (
+                    detected_at     timestamp NOT NULL,
+                    deletion_due_at timestamp NOT NULL,
+                    deleted_at      timestamp,
+                    minimum_age     smallint NOT NULL,
+                    age             smallint NOT NULL,
+                    user_id         text NOT NULL primary key,
+                    country         text NOT NULL);
+CREATE INDEX IF NOT EXISTS underage_users_deletion_due_at_ix ON underage_users (deletion_due_at) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_events (
+                    created_at timestamp NOT NULL,
+                    kyc_step   smallint,
+                    user_id    text NOT NULL,
+                    event_type text NOT NULL,
+                    fields     text[],
+                    primary key(user_id, created_at, event_type));
+CREATE INDEX IF NOT EXISTS user_events_created_at_ix ON user_events (created_at);
+
+CREATE TABLE IF NOT EXISTS distribution_eligibility_snapshots (
+                    as_of                timestamp NOT NULL UNIQUE,
+                    requested_at         timestamp NOT NULL,
+                    claimed_at           timestamp,
+                    completed_at         timestamp,
+                    users                bigint NOT NULL DEFAULT 0,
+                    eligible_users       bigint NOT NULL DEFAULT 0,
+                    parts                bigint NOT NULL DEFAULT 0,
+                    required_kyc_step    smallint NOT NULL,
+                    restricted_countries text[] NOT NULL,
+                    id                   text NOT NULL primary key,
+                    requested_by         text NOT NULL,
+                    hash                 text NOT NULL DEFAULT '');
+
+CREATE TABLE IF NOT EXISTS profile_viewers (
+                    day         date NOT NULL,
+                    viewer_hash bigint NOT NULL,
+                    user_id     text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    primary key(day, user_id, viewer_hash));
+
+CREATE TABLE IF NOT EXISTS profile_views (
+                    day     date NOT NULL,
+                    views   bigint NOT NULL,
+                    user_id text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    primary key(user_id, day));
+CREATE INDEX IF NOT EXISTS profile_views_day_ix ON profile_views (day);
+
+CREATE TABLE IF NOT EXISTS global_ranks (
+                    rank    bigint NOT NULL,
+                    user_id text NOT NULL primary key REFERENCES users(id) ON DELETE CASCADE);
+
+CREATE TABLE IF NOT EXISTS user_badges (
+                    awarded_at timestamp NOT NULL,
+                    name       text NOT NULL,
+                    user_id    text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    primary key(user_id, name));
+
+CREATE TABLE IF NOT EXISTS user_levels (
+                    level           bigint NOT NULL DEFAULT 0,
+                    mining_sessions bigint NOT NULL DEFAULT 0,
+                    user_id         text NOT NULL primary key REFERENCES users(id) ON DELETE CASCADE);
+
+CREATE TABLE IF NOT EXISTS agenda_contact_names (
+                    consented_at      timestamp NOT NULL,
+                    user_id           text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                    phone_number_hash text NOT NULL,
+                    display_name      text NOT NULL,
+                    primary key(user_id, phone_number_hash));
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (s *accountMergeSource) Process(ctx context.Context, msg *messagebroker.Message) error {
+	if ctx.Err() != nil || len(msg.Value) == 0 {
+		return errors.Wrap(ctx.Err(), "unexpected deadline while processing message")
+	}
+	merge := new(AccountMerge)
+	if err := json.UnmarshalContext(ctx, msg.Value, merge); err != nil {
+		return errors.Wrapf(err, "process: cannot unmarshall %v into %#v", string(msg.Value), merge)
+	}
+	if _, err := s.MergeAccounts(ctx, merge); err != nil {
+		if errors.Is(err, ErrInvalidAccountMerge) || errors.Is(err, ErrNotFound) { // Retrying won't help; most likely, it's already merged.
+			log.Error(errors.Wrapf(err, "skipped account merge %#v", merge))
+
+			return nil
+		}
+
+		return errors.Wrapf(err, "failed to MergeAccounts for %#v", merge)
+	}
+
+	return nil
+}
+
+// MergeAccounts keeps the target user, with its ID and identity (username, email, phone number),
+// and moves everything else that's worth keeping from the source user: its referrals, its devices, its KYC state, if it's further,
+// the blanks of its metadata and its sign ins, so that signing in with the email of the source lands on the target.
+// Afterwards, the source is deleted, with a `merged` snapshot, followed by its tombstone.
+func (r *repository) MergeAccounts(ctx context.Context, merge *AccountMerge) (*User, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	if merge.SourceUserID == "" || merge.TargetUserID == "" || merge.SourceUserID == merge.TargetUserID {
+		return nil, errors.Wrapf(ErrInvalidAccountMerge, "source `%v` and target `%v` must be different users", merge.SourceUserID, merge.TargetUserID)
+	}
+	source, err := r.getUserByID(ctx, merge.SourceUserID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get source user for userID:%v", merge.SourceUserID)
+	}
+	target, err := r.getUserByID(ctx, merge.TargetUserID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get target user for userID:%v", merge.TargetUserID)
+	}
+	merge.MergedAt = time.Now()
+	if err = storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		return r.mergeAccounts(ctx, conn, merge)
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to merge accounts for %#v", merge)
+	}
+	if hasKYCData(source) && source.KYCStepPassed != nil && target.KYCStepPassed != nil && *source.KYCStepPassed <= *target.KYCStepPassed {
+		if _, err = r.purgeKYCData(ctx, source.ID, merge.RequestedBy); err != nil {
+			return nil, errors.Wrapf(err, "failed to purgeKYCData for userID:%v", source.ID)
+		}
+	}
+	if err = r.deleteUser(ctx, source); err != nil {
+		return nil, errors.Wrapf(err, "failed to deleteUser for:%#v", source)
+	}
+	merged, err := r.getUserByID(ctx, target.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get merged user for userID:%v", target.ID)
+	}
+
+	return merged, errors.Wrapf(r.sendAccountMergeSnapshots(ctx, source, target, merged), "failed to sendAccountMergeSnapshots for %#v", merge)
+}
+
+//nolint:funlen // A lot of SQL.
+func (r *repository) mergeAccounts(ctx context.Context, conn storage.QueryExecer, merge *AccountMerge) error {
+	sql := `SELECT id FROM users WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	locked, err := auditedSelect[User](ctx, conn, sql, []UserID{merge.SourceUserID, merge.TargetUserID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to lock the users of %#v", merge)
+	}
+	if len(locked) != 2 { //nolint:gomnd // The source and the target.
+		return errors.Wrapf(ErrNotFound, "one of the users of %#v was deleted in the meantime", merge)
+	}
+	sql = `INSERT INTO account_merges (merged_at, source_user_id, target_user_id, requested_by, reason) VALUES ($1, $2, $3, $4, $5)`
+	if _, err = auditedExec(ctx, conn, sql, merge.MergedAt.Time, merge.SourceUserID, merge.TargetUserID, merge.RequestedBy, merge.Reason); err != nil {
+		return errors.Wrapf(err, "failed to insert account merge %#v", merge)
+	}
+	for _, stmt := range []struct {
+		description string
+		sql         string
+		args        []any
+	}{
+		{
+			description: "referrals",
+			sql: `UPDATE users
+				  SET referred_by = $2
+				  WHERE referred_by = $1
+				    AND id != $1
+				    AND id != $2`,
+			args: []any{merge.SourceUserID, merge.TargetUserID},
+		},
+		{
+			description: "referrer",
+			sql: `UPDATE users t
+				  SET referred_by = (CASE WHEN s.referred_by IN ($1, $2) THEN $3 ELSE s.referred_by END),
+				      random_referred_by = s.random_referred_by
+				  FROM users s
+				  WHERE t.id = $2
+				    AND s.id = $1
+				    AND t.referred_by = $1`,
+			args: []any{merge.SourceUserID, merge.TargetUserID, icenetwork},
+		},
+		{
+			description: "KYC state",
+			sql: `UPDATE users t
+				  SET kyc_step_passed = s.kyc_step_passed,
+				      kyc_step_blocked = s.kyc_step_blocked,
+				      kyc_steps_last_updated_at = s.kyc_steps_last_updated_at,
+				      kyc_steps_created_at = s.kyc_steps_created_at
+				  FROM users s
+				  WHERE t.id = $2
+				    AND s.id = $1
+				    AND s.kyc_step_passed > t.kyc_step_passed`,
+			args: []any{merge.SourceUserID, merge.TargetUserID},
+		},
+		{
+			// The guest was the first account of the user, so it has the referral, unless the registered one got a non random one already.
+			description: "guest referrer and client data",
+			sql: `UPDATE users t
+				  SET referred_by = (CASE WHEN (t.referred_by = t.id OR t.random_referred_by) AND s.referred_by NOT IN ($1, $2)
+				                          THEN s.referred_by ELSE t.referred_by END),
+				      random_referred_by = (CASE WHEN (t.referred_by = t.id OR t.random_referred_by) AND s.referred_by NOT IN ($1, $2)
+				                                 THEN s.random_referred_by ELSE t.random_referred_by END),
+				      client_data = (CASE WHEN json_typeof(s.client_data::json) = 'object' AND json_typeof(t.client_data::json) = 'object'
+				                          THEN (s.client_data::jsonb || t.client_data::jsonb)::text
+				                          ELSE COALESCE(t.client_data, s.client_data) END)
+				  FROM users s
+				  WHERE t.id = $2
+				    AND s.id = $1
+				    AND $3`,
+			args: []any{merge.SourceUserID, merge.TargetUserID, IsGuest(merge.SourceUserID)},
+		},
+		{
+			description: "metadata",
+			sql: `UPDATE users t
+				  SET first_name = COALESCE(t.first_name, s.first_name),
+				      last_name = COALESCE(t.last_name, s.last_name),
+				      client_data = COALESCE(t.client_data, s.client_data),
+				      hidden_profile_elements = COALESCE(t.hidden_profile_elements, s.hidden_profile_elements),
+				      agenda_contact_user_ids = COALESCE(t.agenda_contact_user_ids, s.agenda_contact_user_ids),
+				      updated_at = $3
+				  FROM users s
+				  WHERE t.id = $2
+				    AND s.id = $1`,
+			args: []any{merge.SourceUserID, merge.TargetUserID, merge.MergedAt.Time},
+		},
+		{
+			description: "kyc step attempts",
+			sql: `UPDATE kyc_step_attempts a
+				  SET user_id = $2
+				  WHERE user_id = $1
+				    AND NOT EXISTS (SELECT 1 FROM kyc_step_attempts t
+				                    WHERE t.user_id = $2 AND t.kyc_step = a.kyc_step AND t.attempted_at = a.attempted_at)`,
+			args: []any{merge.SourceUserID, merge.TargetUserID},
+		},
+		{
+			description: "devices",
+			sql: `UPDATE device_metadata d
+				  SET user_id = $2
+				  WHERE user_id = $1
+				    AND NOT EXISTS (SELECT 1 FROM device_metadata t WHERE t.user_id = $2 AND t.device_unique_id = d.device_unique_id)`,
+			args: []any{merge.SourceUserID, merge.TargetUserID},
+		},
+		{
+			description: "device IPs",
+			sql: `UPDATE device_metadata_ips d
+				  SET user_id = $2
+				  WHERE user_id = $1
+				    AND NOT EXISTS (SELECT 1 FROM device_metadata_ips t
+				                    WHERE t.user_id = $2 AND t.device_unique_id = d.device_unique_id AND t.ip = d.ip)`,
+			args: []any{merge.SourceUserID, merge.TargetUserID},
+		},
+		{
+			description: "account metadata",
+			sql: `INSERT INTO account_metadata (user_id, metadata)
+				  SELECT $2, metadata FROM account_metadata WHERE user_id = $1
+				  ON CONFLICT (user_id) DO UPDATE
+					  SET metadata = EXCLUDED.metadata || account_metadata.metadata`,
+			args: []any{merge.SourceUserID, merge.TargetUserID},
+		},
+		{
+			description: "sign ins",
+			sql:         `UPDATE email_link_sign_ins SET user_id = $2 WHERE user_id = $1`,
+			args:        []any{merge.SourceUserID, merge.TargetUserID},
+		},
+	} {
+		if _, err = auditedExec(ctx, conn, stmt.sql, stmt.args...); err != nil {
+			return errors.Wrapf(err, "failed to merge the %v of %#v", stmt.description, merge)
+		}
+	}
+
+	return nil
+}
+
+func (r *repository) sendAccountMergeSnapshots(ctx context.Context, source, target, merged *User) error {
+	us := &UserSnapshot{User: r.sanitizeUser(merged), Before: r.sanitizeUser(target)}
+	if err := r.sendUserSnapshotMessage(ctx, us); err != nil {
+		return errors.Wrapf(err, "failed to send merged user message for %#v", us)
+	}
+	us = &UserSnapshot{Before: r.sanitizeUser(source), Event: MergedUserSnapshotEvent}
+	if err := r.sendUserSnapshotMessage(ctx, us); err != nil {
+		return errors.Wrapf(err, "failed to send merged source user message for %#v", us)
+	}
+	if err := r.sendTombstonedUserMessage(ctx, us); err != nil {
+		return errors.Wrapf(err, "failed to sendTombstonedUserMessage for userID:%v", source.ID)
+	}
+	log.Info(fmt.Sprintf("user `%v` merged into `%v`", source.ID, target.ID))
+
+	return nil
+}
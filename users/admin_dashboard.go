@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetAdminDashboard(ctx context.Context) (*AdminDashboard, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	now := time.Now()
+	dashboard := &AdminDashboard{Date: time.New(now.Truncate(hoursInOneDay * stdlibtime.Hour))}
+	signupsKey, deletionsKey := dailyCounterKey(dailySignupsGlobalKey, now), dailyCounterKey(dailyDeletionsGlobalKey, now)
+	keys := append([]string{totalUsersGlobalKey, signupsKey, deletionsKey}, r.totalActiveUsersGlobalChildrenKeys(now.Time)...)
+	values, err := r.getGlobalValues(ctx, keys...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to getGlobalValues for keys:%#v", keys)
+	}
+	for _, row := range values {
+		switch row.Key {
+		case totalUsersGlobalKey:
+			dashboard.TotalUsers = row.Value
+		case signupsKey:
+			dashboard.Signups = row.Value
+		case deletionsKey:
+			dashboard.Deletions = row.Value
+		default:
+			dashboard.ActiveUsers = max(dashboard.ActiveUsers, row.Value)
+		}
+	}
+	sql := `SELECT kyc_step, entered, passed, failed, blocked
+			FROM kyc_funnel_statistics
+			WHERE day = $1
+			ORDER BY kyc_step`
+	if dashboard.KYCSteps, err = auditedSelect[KYCStepFunnel](ctx, r.db, sql, dashboard.Date.Time); err != nil {
+		return nil, errors.Wrap(err, "failed to select today's kyc funnel statistics")
+	}
+	dashboard.Providers = r.residencyClustersAvailability(ctx)
+
+	return dashboard, nil
+}
+
+// residencyClustersAvailability pings the clusters of the data residency. The main one isn't, since the dashboard was read from it.
+func (r *repository) residencyClustersAvailability(ctx context.Context) []*ProviderAvailability {
+	availability := make([]*ProviderAvailability, 0, len(r.residencyClusters))
+	for residency, db := range r.residencyClusters {
+		_, err := auditedGet[struct{ Ping int }](ctx, db, `SELECT 1 AS ping`)
+		availability = append(availability, &ProviderAvailability{Name: fmt.Sprintf("database/%v", residency), Available: err == nil})
+	}
+	sort.Slice(availability, func(i, j int) bool { return availability[i].Name < availability[j].Name })
+
+	return availability
+}
+
+// incrementDailyCounter counts an event of the current (UTC) day, in the global values, like the total users are.
+func (r *repository) incrementDailyCounter(ctx context.Context, keyPrefix string) error {
+	key := dailyCounterKey(keyPrefix, time.Now())
+	sql := `INSERT INTO global (key, value) VALUES ($1, 1)
+			ON CONFLICT (key) DO UPDATE
+				SET value = global.value + 1,
+					updated_at = current_timestamp`
+	_, err := auditedExec(ctx, r.db, sql, key)
+
+	return errors.Wrapf(err, "failed to increment %v", key)
+}
+
+func dailyCounterKey(keyPrefix string, now *time.Time) string {
+	return fmt.Sprintf("%v_%v", keyPrefix, now.UTC().Format(dayFormat))
+}
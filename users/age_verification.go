@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetUnderageUsers(ctx context.Context, limit, offset uint64) ([]*UnderageUser, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT *
+			FROM underage_users
+			ORDER BY deleted_at IS NOT NULL, deletion_due_at, user_id
+			LIMIT $1 OFFSET $2`
+	res, err := auditedSelect[UnderageUser](ctx, r.db, sql, limit, offset)
+
+	return res, errors.Wrap(err, "failed to select underage users")
+}
+
+func (r *repository) CheckUnderage(ctx context.Context, userID UserID) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT deletion_due_at FROM underage_users WHERE user_id = $1 AND deleted_at IS NULL`
+	underage, err := auditedGet[UnderageUser](ctx, r.db, sql, userID)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return nil
+		}
+
+		return errors.Wrapf(err, "failed to check if userID:%v is underage", userID)
+	}
+
+	return errors.Wrapf(ErrUnderage, "userID:%v is blocked and it's going to be deleted at %v", userID, underage.DeletionDueAt)
+}
+
+// validateDateOfBirth rejects the malformed dates and the ones in the future, before anything is stored.
+func validateDateOfBirth(dateOfBirth *string) error {
+	if dateOfBirth == nil {
+		return nil
+	}
+	parsed, err := stdlibtime.Parse(stdlibtime.DateOnly, *dateOfBirth)
+	if err != nil || parsed.After(*time.Now().Time) {
+		return errors.Wrapf(ErrInvalidDateOfBirth, "`%v` must be a past date, formatted as YYYY-MM-DD", *dateOfBirth)
+	}
+
+	return nil
+}
+
+// captureDateOfBirth stores the date of birth, encrypted, and applies the minimum age of the country of the user:
+// if it's younger, it's blocked and its deletion is scheduled, otherwise it's unblocked, in case the date was corrected.
+// It's routed to the residency cluster of the user.
+func (r *repository) captureDateOfBirth(ctx context.Context, usr *User, dateOfBirth *string) (underage bool, err error) {
+	userID, country := usr.ID, usr.Country
+	if dateOfBirth == nil {
+		return false, nil
+	}
+	if r.piiCipher == nil {
+		return false, errors.New("piiEncryption.keys are required for capturing the dates of birth")
+	}
+	encrypted, err := r.piiCipher.Encrypt(*dateOfBirth)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to encrypt the date of birth of userID:%v", userID)
+	}
+	now := time.Now()
+	sql := `INSERT INTO user_dates_of_birth (updated_at, user_id, date_of_birth) VALUES ($1, $2, $3)
+			ON CONFLICT (user_id) DO UPDATE
+				SET updated_at    = EXCLUDED.updated_at,
+					date_of_birth = EXCLUDED.date_of_birth`
+	if _, err = auditedExec(ctx, r.dbFor(usr.Residency), sql, now.Time, userID, encrypted); err != nil {
+		return false, errors.Wrapf(err, "failed to upsert the date of birth of userID:%v", userID)
+	}
+	parsed, err := stdlibtime.Parse(stdlibtime.DateOnly, *dateOfBirth)
+	if err != nil {
+		return false, errors.Wrapf(ErrInvalidDateOfBirth, "`%v` must be formatted as YYYY-MM-DD", *dateOfBirth)
+	}
+	age, minimumAge := ageAt(parsed, *now.Time), r.cfg.minimumAge(country)
+	if age >= minimumAge {
+		sql = `DELETE FROM underage_users WHERE user_id = $1 AND deleted_at IS NULL`
+		_, err = auditedExec(ctx, r.db, sql, userID)
+
+		return false, errors.Wrapf(err, "failed to unblock userID:%v", userID)
+	}
+	sql = `INSERT INTO underage_users (detected_at, deletion_due_at, minimum_age, age, user_id, country) VALUES ($1, $2, $3, $4, $5, $6)
+		   ON CONFLICT (user_id) DO UPDATE
+				SET minimum_age = EXCLUDED.minimum_age,
+					age         = EXCLUDED.age,
+					country     = EXCLUDED.country
+				WHERE underage_users.deleted_at IS NULL`
+	_, err = auditedExec(ctx, r.db, sql, now.Time, now.Add(r.cfg.AgeVerification.DeletionDelay), minimumAge, age, userID, country)
+
+	return err == nil, errors.Wrapf(err, "failed to block underage userID:%v", userID)
+}
+
+func (c *config) minimumAge(country string) uint64 {
+	for perCountry, minimumAge := range c.AgeVerification.MinimumAgePerCountry {
+		if strings.EqualFold(perCountry, country) {
+			return minimumAge
+		}
+	}
+
+	return c.AgeVerification.MinimumAge
+}
+
+// ageAt is the number of full years from the date of birth to `at`.
+func ageAt(dateOfBirth, at stdlibtime.Time) uint64 {
+	age := at.Year() - dateOfBirth.Year()
+	if at.Month() < dateOfBirth.Month() || (at.Month() == dateOfBirth.Month() && at.Day() < dateOfBirth.Day()) {
+		age--
+	}
+
+	return uint64(max(age, 0))
+}
+
+func (p *processor) startUnderageUsersDeleter(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.AgeVerification.DeletionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 5 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(p.deleteUnderageUsers(reqCtx), "failed to deleteUnderageUsers"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *processor) deleteUnderageUsers(ctx context.Context) error {
+	const batchSize = 100
+	sql := `SELECT user_id FROM underage_users WHERE deleted_at IS NULL AND deletion_due_at <= $1 ORDER BY deletion_due_at LIMIT $2`
+	due, err := auditedSelect[UnderageUser](ctx, p.db, sql, time.Now().Time, batchSize)
+	if err != nil {
+		return errors.Wrap(err, "failed to select the underage users due for deletion")
+	}
+	for _, underage := range due {
+		if err = p.DeleteUser(ctx, underage.UserID); err != nil && !errors.Is(err, ErrNotFound) {
+			return errors.Wrapf(err, "failed to delete underage userID:%v", underage.UserID)
+		}
+		sql = `UPDATE underage_users SET deleted_at = $2 WHERE user_id = $1`
+		if _, err = auditedExec(ctx, p.db, sql, underage.UserID, time.Now().Time); err != nil {
+			return errors.Wrapf(err, "failed to mark underage userID:%v as deleted", underage.UserID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users/internal/encryption"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// SetAgendaContactNames stores the names encrypted, like the dates of birth, so the encryption has to be enabled.
+func (r *repository) SetAgendaContactNames(ctx context.Context, userID UserID, contacts []*AgendaContactName) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	if r.piiCipher == nil {
+		return errors.New("piiEncryption.keys are required for storing the agenda contact names")
+	}
+	consentedHashes, displayNames := make([]string, 0, len(contacts)), make([]string, 0, len(contacts))
+	revokedHashes := make([]string, 0, len(contacts))
+	for _, contact := range contacts {
+		if !contact.Consent {
+			revokedHashes = append(revokedHashes, contact.PhoneNumberHash)
+
+			continue
+		}
+		encrypted, err := r.piiCipher.Encrypt(contact.DisplayName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encrypt the agenda contact name of userID:%v", userID)
+		}
+		consentedHashes, displayNames = append(consentedHashes, contact.PhoneNumberHash), append(displayNames, encrypted)
+	}
+	if len(revokedHashes) != 0 {
+		sql := `DELETE FROM agenda_contact_names WHERE user_id = $1 AND phone_number_hash = ANY($2)`
+		if _, err := auditedExec(ctx, r.db, sql, userID, revokedHashes); err != nil {
+			return errors.Wrapf(err, "failed to delete %v agenda contact names of userID:%v", len(revokedHashes), userID)
+		}
+	}
+	if len(consentedHashes) == 0 {
+		return nil
+	}
+	sql := `INSERT INTO agenda_contact_names (consented_at, user_id, phone_number_hash, display_name)
+			SELECT $1, $2, c.phone_number_hash, c.display_name
+			FROM unnest($3::text[], $4::text[]) AS c(phone_number_hash, display_name)
+			ON CONFLICT (user_id, phone_number_hash) DO UPDATE
+				SET consented_at = EXCLUDED.consented_at,
+					display_name = EXCLUDED.display_name`
+	if _, err := auditedExec(ctx, r.db, sql, time.Now().Time, userID, consentedHashes, displayNames); err != nil {
+		if errors.Is(err, ErrRelationNotFound) {
+			err = errors.Wrap(ErrNotFound, err.Error())
+		}
+
+		return errors.Wrapf(err, "failed to upsert %v agenda contact names of userID:%v", len(consentedHashes), userID)
+	}
+
+	return nil
+}
+
+func (r *repository) DeleteAgendaContactNames(ctx context.Context, userID UserID, phoneNumberHash string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `DELETE FROM agenda_contact_names WHERE user_id = $1 AND ($2 = '' OR phone_number_hash = $2)`
+	if deleted, err := auditedExec(ctx, r.db, sql, userID, phoneNumberHash); err != nil || deleted == 0 {
+		if err == nil {
+			err = ErrNotFound
+		}
+
+		return errors.Wrapf(err, "failed to delete the agenda contact names of userID:%v, phoneNumberHash:%v", userID, phoneNumberHash)
+	}
+
+	return nil
+}
+
+// setContactNames sets the names the user stored for its CONTACTS referrals, matched by their phone number hashes.
+// The ones that can't be decrypted are left out, since they're useless anyway.
+func (r *repository) setContactNames(ctx context.Context, userID UserID, referrals []*MinimalUserProfile) error {
+	if len(referrals) == 0 {
+		return nil
+	}
+	referralIDs := make([]UserID, 0, len(referrals))
+	for _, referral := range referrals {
+		referralIDs = append(referralIDs, referral.ID)
+	}
+	sql := `SELECT referrals.id AS user_id,
+				   n.display_name
+			FROM agenda_contact_names n
+				JOIN users referrals
+					ON referrals.phone_number_hash = n.phone_number_hash
+			WHERE n.user_id = $1
+			  AND referrals.id = ANY($2)`
+	names, err := auditedSelect[struct {
+		UserID      UserID
+		DisplayName string
+	}](ctx, r.db, sql, userID, referralIDs)
+	if err != nil {
+		return errors.Wrapf(err, "failed to select the agenda contact names of userID:%v", userID)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	if r.piiCipher == nil {
+		log.Error(errors.New("piiEncryption.keys are required for decrypting the agenda contact names"))
+
+		return nil
+	}
+	displayNames := make(map[UserID]string, len(names))
+	for _, name := range names {
+		displayName, dErr := r.piiCipher.Decrypt(name.DisplayName)
+		if dErr != nil {
+			log.Error(errors.Wrapf(dErr, "failed to decrypt the agenda contact name of userID:%v for userID:%v", name.UserID, userID))
+
+			continue
+		}
+		displayNames[name.UserID] = displayName
+	}
+	for _, referral := range referrals {
+		referral.ContactName = displayNames[referral.ID]
+	}
+
+	return nil
+}
+
+func (p *processor) reencryptAgendaContactNames(ctx context.Context) (reencrypted, skipped int, err error) {
+	sql := `SELECT user_id, phone_number_hash, display_name
+			FROM agenda_contact_names
+			WHERE NOT starts_with(display_name, $1)
+			  AND display_name != ALL($3::text[])
+			LIMIT $2`
+	names, err := auditedSelect[struct {
+		UserID          UserID
+		PhoneNumberHash string
+		DisplayName     string
+	}](ctx, p.db, sql, p.piiCipher.PrimaryKeyPrefix(), p.cfg.PIIReencryption.BatchSize, p.undecryptablePII)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to select the agenda contact names to reencrypt")
+	}
+	userIDs, values := make([]UserID, 0, len(names)), make([]string, 0, len(names))
+	for _, name := range names {
+		userIDs, values = append(userIDs, name.UserID), append(values, name.DisplayName)
+	}
+	undecryptable, err := encryption.Reencrypt(p.piiCipher, values, func(idx int, _, reencrypted string) error {
+		sql = `UPDATE agenda_contact_names SET display_name = $4 WHERE user_id = $1 AND phone_number_hash = $2 AND display_name = $3`
+		_, uErr := auditedExec(ctx, p.db, sql, userIDs[idx], names[idx].PhoneNumberHash, values[idx], reencrypted)
+
+		return errors.Wrapf(uErr, "failed to update the agenda contact name of userID:%v", userIDs[idx])
+	})
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to reencrypt the agenda contact names")
+	}
+
+	return len(values) - len(undecryptable), p.skipUndecryptablePII("agenda contact name", userIDs, values, undecryptable), nil
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+)
+
+// SendAuthEvent publishes the auth events (produced by auth/email_link) using the same broker as the rest of the user events,
+// keyed by the login identity (email+device), so that all the events of a login flow land, in order, on the same partition.
+func (r *repository) SendAuthEvent(ctx context.Context, event *AuthEvent) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	valueBytes, err := json.MarshalContext(ctx, event)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", event)
+	}
+	msg := &messagebroker.Message{
+		Headers: map[string]string{"producer": "eskimo"},
+		Key:     event.Email + "~" + event.DeviceUniqueID,
+		Topic:   r.cfg.MessageBroker.Topics[7].Name,
+		Value:   valueBytes,
+	}
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+
+	return errors.Wrapf(<-responder, "failed to send auth event message to broker")
+}
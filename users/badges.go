@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetBadges(ctx context.Context, userID UserID) ([]*Badge, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	type owner struct {
+		HiddenProfileElements *Enum[HiddenProfileElement] `db:"hidden_profile_elements"`
+	}
+	usr, err := auditedGet[owner](ctx, r.db, `SELECT hidden_profile_elements FROM users WHERE id = $1`, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the hidden profile elements of userID:%v", userID)
+	}
+	if userID != requestingUserID(ctx) && usr.HiddenProfileElements != nil &&
+		slices.Contains(*usr.HiddenProfileElements, BadgesHiddenProfileElement) {
+		return []*Badge{}, nil
+	}
+	badges, err := r.selectBadges(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, badge := range badges {
+		for _, definition := range r.cfg.Badges {
+			if definition.Name == badge.Name {
+				badge.Description = definition.Description
+			}
+		}
+	}
+
+	return badges, nil
+}
+
+func (r *repository) selectBadges(ctx context.Context, userID UserID) ([]*Badge, error) {
+	sql := `SELECT awarded_at, name FROM user_badges WHERE user_id = $1 ORDER BY awarded_at, name`
+	badges, err := auditedSelect[Badge](ctx, r.db, sql, userID)
+
+	return badges, errors.Wrapf(err, "failed to select the badges of userID:%v", userID)
+}
+
+// badgeNames returns the names of the badges of the user, to be included in its snapshots.
+func (r *repository) badgeNames(ctx context.Context, userID UserID) ([]string, error) {
+	badges, err := r.selectBadges(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(badges))
+	for _, badge := range badges {
+		names = append(names, badge.Name)
+	}
+
+	return names, nil
+}
+
+// awardBadges checks the criteria of the `badges` for the user and for its referrer, whose T1 referrals might have just changed.
+// Every badge is awarded once, so the users that are awarded new ones get a new snapshot, which doesn't award anything else.
+func (s *userSnapshotSource) awardBadges(ctx context.Context, us *UserSnapshot) error {
+	if us.User == nil || len(s.cfg.Badges) == 0 {
+		return nil
+	}
+	userIDs := []UserID{us.ID}
+	if us.ReferredBy != "" && us.ReferredBy != us.ID {
+		userIDs = append(userIDs, us.ReferredBy)
+	}
+	type progress struct {
+		UserID        UserID  `db:"user_id"`
+		T1Referrals   uint64  `db:"t1_referrals"`
+		KYCStepPassed KYCStep `db:"kyc_step_passed"`
+	}
+	sql := `SELECT u.id AS user_id,
+				   COALESCE(refs.t1, 0) AS t1_referrals,
+				   COALESCE(u.kyc_step_passed, 0) AS kyc_step_passed
+			FROM users u
+				LEFT JOIN referral_acquisition_history refs
+					   ON refs.user_id = u.id
+			WHERE u.id = ANY($1)`
+	candidates, err := auditedSelect[progress](ctx, s.db, sql, userIDs)
+	if err != nil {
+		return errors.Wrapf(err, "failed to select the badge progress of userIDs:%v", userIDs)
+	}
+	awardedUserIDs, names := make([]UserID, 0, len(candidates)*len(s.cfg.Badges)), make([]string, 0, len(candidates)*len(s.cfg.Badges))
+	for _, candidate := range candidates {
+		for _, badge := range s.cfg.Badges {
+			if candidate.T1Referrals >= badge.T1Referrals && candidate.KYCStepPassed >= badge.KYCStepPassed {
+				awardedUserIDs, names = append(awardedUserIDs, candidate.UserID), append(names, badge.Name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	type awarded struct {
+		UserID UserID `db:"user_id"`
+	}
+	sql = `INSERT INTO user_badges (awarded_at, user_id, name)
+		   SELECT $1, b.user_id, b.name
+		   FROM unnest($2::text[], $3::text[]) AS b(user_id, name)
+				JOIN users u ON u.id = b.user_id
+		   ON CONFLICT DO NOTHING
+		   RETURNING user_id`
+	newlyAwarded, err := auditedExecMany[awarded](ctx, s.db, sql, time.Now().Time, awardedUserIDs, names)
+	if err != nil {
+		return errors.Wrapf(err, "failed to award the badges %v to userIDs:%v", names, awardedUserIDs)
+	}
+	notified := make(map[UserID]struct{}, len(newlyAwarded))
+	for _, award := range newlyAwarded {
+		if _, alreadyNotified := notified[award.UserID]; alreadyNotified {
+			continue
+		}
+		notified[award.UserID] = struct{}{}
+		if err = s.sendBadgesChangedUserSnapshotMessage(ctx, award.UserID); err != nil {
+			return errors.Wrapf(err, "failed to sendBadgesChangedUserSnapshotMessage for userID:%v", award.UserID)
+		}
+	}
+
+	return nil
+}
+
+func (r *repository) sendBadgesChangedUserSnapshotMessage(ctx context.Context, userID UserID) error {
+	usr, err := r.getUserByID(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get user for userID:%v", userID)
+	}
+	usr = r.sanitizeUser(usr)
+	us := &UserSnapshot{User: usr, Before: usr, Event: BadgesChangedUserSnapshotEvent}
+
+	return errors.Wrapf(r.sendUserSnapshotMessage(ctx, us), "failed to send badges changed user message for userID:%v", userID)
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users/internal/blockchain"
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetBlockchainAddresses(ctx context.Context, userID UserID) ([]*BlockchainAddress, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT * FROM user_blockchain_addresses WHERE user_id = $1 ORDER BY created_at, chain, address`
+	res, err := auditedSelect[BlockchainAddress](ctx, r.db, sql, userID)
+
+	return res, errors.Wrapf(err, "failed to select blockchain addresses for userID:%v", userID)
+}
+
+// CreateBlockchainAddressChallenge drops the expired challenges of the user too, so that they don't pile up.
+func (r *repository) CreateBlockchainAddressChallenge(ctx context.Context, address *BlockchainAddress) (*BlockchainAddressChallenge, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	canonical, err := blockchain.ValidateAddress(address.Chain, address.Address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid blockchain address %v:%v", address.Chain, address.Address)
+	}
+	rawNonce := make([]byte, blockchainAddressChallengeNonceSize)
+	if _, err = rand.Read(rawNonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate blockchain address challenge nonce")
+	}
+	now := time.Now()
+	challenge := &blockchainAddressChallenge{CreatedAt: now, UserID: address.UserID, Chain: address.Chain, Address: canonical, Nonce: hex.EncodeToString(rawNonce)}
+	sql := `INSERT INTO blockchain_address_challenges (created_at, user_id, chain, address, nonce)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id, chain, address) DO UPDATE
+				SET created_at = EXCLUDED.created_at,
+					nonce      = EXCLUDED.nonce`
+	if _, err = auditedExec(ctx, r.db, sql, now.Time, challenge.UserID, challenge.Chain, challenge.Address, challenge.Nonce); err != nil {
+		return nil, errors.Wrapf(err, "failed to insert blockchain address challenge for %v:%v, userID:%v", challenge.Chain, challenge.Address, challenge.UserID)
+	}
+	sql = `DELETE FROM blockchain_address_challenges WHERE user_id = $1 AND created_at < $2`
+	if _, err = auditedExec(ctx, r.db, sql, challenge.UserID, now.Add(-r.cfg.BlockchainAddresses.ChallengeTTL)); err != nil {
+		return nil, errors.Wrapf(err, "failed to delete the expired blockchain address challenges of userID:%v", challenge.UserID)
+	}
+
+	return &BlockchainAddressChallenge{ExpiresAt: time.New(now.Add(r.cfg.BlockchainAddresses.ChallengeTTL)), Message: challenge.message()}, nil
+}
+
+func (c *blockchainAddressChallenge) message() string {
+	return fmt.Sprintf(blockchainAddressChallengeMessage, c.Chain, c.Address, c.UserID, c.Nonce)
+}
+
+// AddBlockchainAddress consumes the challenge regardless of the result, so that every signature attempt needs a new one.
+func (r *repository) AddBlockchainAddress(ctx context.Context, address *BlockchainAddress, signature string) (*BlockchainAddress, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	canonical, err := blockchain.ValidateAddress(address.Chain, address.Address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid blockchain address %v:%v", address.Chain, address.Address)
+	}
+	if err = r.consumeBlockchainAddressChallenge(ctx, address.UserID, address.Chain, canonical, signature); err != nil {
+		return nil, errors.Wrapf(err, "failed to verify the ownership of %v:%v by userID:%v", address.Chain, canonical, address.UserID)
+	}
+	now := time.Now()
+	verified := &BlockchainAddress{CreatedAt: now, VerifiedAt: now, UserID: address.UserID, Chain: address.Chain, Address: canonical}
+	if verified, err = r.upsertBlockchainAddress(ctx, verified); err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert blockchain address %v:%v for userID:%v", address.Chain, canonical, address.UserID)
+	}
+	if err = r.sendBlockchainAddressVerificationMessage(ctx, verified); err != nil {
+		return nil, errors.Wrapf(err, "failed to sendBlockchainAddressVerificationMessage for %v:%v, userID:%v", verified.Chain, verified.Address, verified.UserID)
+	}
+
+	return verified, errors.Wrapf(r.sendBlockchainAddressesChangedUserSnapshotMessage(ctx, verified.UserID),
+		"failed to sendBlockchainAddressesChangedUserSnapshotMessage for userID:%v", verified.UserID)
+}
+
+func (r *repository) consumeBlockchainAddressChallenge(ctx context.Context, userID UserID, chain BlockchainChain, address, signature string) error {
+	sql := `DELETE FROM blockchain_address_challenges WHERE user_id = $1 AND chain = $2 AND address = $3 RETURNING *`
+	challenge, err := auditedExecOne[blockchainAddressChallenge](ctx, r.db, sql, userID, chain, address)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return errors.Wrapf(ErrBlockchainAddressChallengeNotFound, "no challenge for %v:%v", chain, address)
+		}
+
+		return errors.Wrapf(err, "failed to consume the blockchain address challenge for %v:%v", chain, address)
+	}
+	if challenge.CreatedAt.Add(r.cfg.BlockchainAddresses.ChallengeTTL).Before(*time.Now().Time) {
+		return errors.Wrapf(ErrBlockchainAddressChallengeNotFound, "challenge expired for %v:%v", chain, address)
+	}
+
+	return errors.Wrapf(blockchain.VerifySignature(chain, address, challenge.message(), signature), "invalid signature of the challenge for %v:%v", chain, address)
+}
+
+// upsertBlockchainAddress verifies the address again if the user has it already, otherwise it inserts it, within the limit of addresses per user.
+func (r *repository) upsertBlockchainAddress(ctx context.Context, address *BlockchainAddress) (*BlockchainAddress, error) {
+	sql := `UPDATE user_blockchain_addresses SET verified_at = $1 WHERE user_id = $2 AND chain = $3 AND address = $4 RETURNING *`
+	reverified, err := auditedExecOne[BlockchainAddress](ctx, r.db, sql, address.VerifiedAt.Time, address.UserID, address.Chain, address.Address)
+	if !errors.Is(err, ErrNotFound) {
+		return reverified, errors.Wrap(err, "failed to update verified_at")
+	}
+	sql = `INSERT INTO user_blockchain_addresses (created_at, verified_at, user_id, chain, address)
+		   SELECT $1, $1, $2, $3, $4
+		   WHERE (SELECT count(1) FROM user_blockchain_addresses WHERE user_id = $2) < $5`
+	inserted, err := auditedExec(ctx, r.db, sql, address.CreatedAt.Time, address.UserID, address.Chain, address.Address, maxBlockchainAddressesPerUser)
+	if err != nil {
+		if errors.Is(err, ErrDuplicate) {
+			err = errors.Wrap(ErrBlockchainAddressUsedBySomebodyElse, err.Error())
+		}
+
+		return nil, errors.Wrap(err, "failed to insert")
+	}
+	if inserted == 0 {
+		return nil, errors.Wrapf(ErrTooManyBlockchainAddresses, "userID:%v has %v blockchain addresses already", address.UserID, maxBlockchainAddressesPerUser)
+	}
+
+	return address, nil
+}
+
+func (r *repository) RemoveBlockchainAddress(ctx context.Context, address *BlockchainAddress) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	canonical, err := blockchain.ValidateAddress(address.Chain, address.Address)
+	if err != nil {
+		return errors.Wrapf(err, "invalid blockchain address %v:%v", address.Chain, address.Address)
+	}
+	sql := `DELETE FROM user_blockchain_addresses WHERE user_id = $1 AND chain = $2 AND address = $3`
+	if deleted, dErr := auditedExec(ctx, r.db, sql, address.UserID, address.Chain, canonical); dErr != nil || deleted == 0 {
+		if dErr == nil {
+			dErr = ErrNotFound
+		}
+
+		return errors.Wrapf(dErr, "failed to delete blockchain address %v:%v for userID:%v", address.Chain, canonical, address.UserID)
+	}
+
+	return errors.Wrapf(r.sendBlockchainAddressesChangedUserSnapshotMessage(ctx, address.UserID),
+		"failed to sendBlockchainAddressesChangedUserSnapshotMessage for userID:%v", address.UserID)
+}
+
+func (r *repository) sendBlockchainAddressesChangedUserSnapshotMessage(ctx context.Context, userID UserID) error {
+	usr, err := r.getUserByID(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get user for userID:%v", userID)
+	}
+	usr = r.sanitizeUser(usr)
+	us := &UserSnapshot{User: usr, Before: usr, Event: BlockchainAddressesChangedUserSnapshotEvent}
+
+	return errors.Wrapf(r.sendUserSnapshotMessage(ctx, us), "failed to send blockchain addresses changed user message for userID:%v", userID)
+}
+
+// sendBlockchainAddressVerificationMessage notifies the eligibility checks (airdrops, etc.) that the user proved the ownership of the address.
+func (r *repository) sendBlockchainAddressVerificationMessage(ctx context.Context, address *BlockchainAddress) error {
+	valueBytes, err := json.MarshalContext(ctx, address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", address)
+	}
+	msg := &messagebroker.Message{
+		Headers: map[string]string{"producer": "eskimo"},
+		Key:     address.UserID,
+		Topic:   r.cfg.MessageBroker.Topics[13].Name,
+		Value:   valueBytes,
+	}
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+
+	return errors.Wrapf(<-responder, "failed to send `%v` message to broker", msg.Topic)
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+func (r *repository) ReloadableConfigKeys() []string {
+	return append([]string{
+		applicationYamlKey + ".kycAttemptLimits",
+		applicationYamlKey + ".maintenanceMode",
+		applicationYamlKey + ".countryRestrictions",
+	}, r.DeviceMetadataRepository.ReloadableConfigKeys()...)
+}
+
+// ReloadConfig copies only the reloadable values from the re-read config, so the rest keep the values they were started with.
+func (r *repository) ReloadConfig() error {
+	var loaded config
+	if err := viper.UnmarshalKey(applicationYamlKey, &loaded); err != nil {
+		return errors.Wrapf(err, "failed to load config by key %q", applicationYamlKey)
+	}
+	if err := loaded.validate().ErrorOrNil(); err != nil {
+		return errors.Wrapf(err, "invalid reloaded config of %v", applicationYamlKey)
+	}
+	latest := *r.cfg
+	latest.KYCAttemptLimits = loaded.KYCAttemptLimits
+	latest.MaintenanceMode = loaded.MaintenanceMode
+	latest.CountryRestrictions = loaded.CountryRestrictions
+	r.cfg.reloaded.Store(&latest)
+
+	return errors.Wrap(r.DeviceMetadataRepository.ReloadConfig(), "failed to reload the device metadata config")
+}
+
+// live returns the config with the latest values of ReloadableConfigKeys, which must always be read through it.
+func (c *config) live() *config {
+	if c.reloaded != nil {
+		if latest := c.reloaded.Load(); latest != nil {
+			return latest
+		}
+	}
+
+	return c
+}
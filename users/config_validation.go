@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"regexp"
+	stdlibtime "time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	devicemetadata "github.com/ice-blockchain/eskimo/users/internal/device/metadata"
+	appcfg "github.com/ice-blockchain/wintr/config"
+)
+
+// ValidateConfig reports, at once, all the problems of the config needed by New.
+func ValidateConfig() error {
+	cfg := loadConfig()
+
+	return multierror.Append(cfg.validate(), devicemetadata.ValidateConfig(false)).ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+}
+
+// ValidateProcessorConfig reports, at once, all the problems of the config needed by StartProcessor.
+func ValidateProcessorConfig() error {
+	cfg := loadConfig()
+
+	return multierror.Append(cfg.validate(), cfg.validateProcessor(), devicemetadata.ValidateConfig(true)).ErrorOrNil() //nolint:wrapcheck // .
+}
+
+func loadConfig() *config {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
+
+	return &cfg
+}
+
+func (c *config) validate() *multierror.Error {
+	var mErr *multierror.Error
+	for _, interval := range []struct {
+		key   string
+		value stdlibtime.Duration
+	}{
+		{key: "parent", value: c.GlobalAggregationInterval.Parent},
+		{key: "child", value: c.GlobalAggregationInterval.Child},
+	} {
+		if interval.value != stdlibtime.Minute && interval.value != stdlibtime.Hour && interval.value != hoursInOneDay*stdlibtime.Hour {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.globalAggregationInterval.%v` must be 1m, 1h or 24h, not `%v`", applicationYamlKey, interval.key, interval.value))
+		}
+	}
+	if c.GlobalAggregationInterval.Child > c.GlobalAggregationInterval.Parent {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.globalAggregationInterval.child` can't be bigger than the parent one", applicationYamlKey))
+	}
+	if c.MaintenanceMode.CacheTTL < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.maintenanceMode.cacheTtl` can't be negative", applicationYamlKey))
+	}
+	if c.profileCompletenessEnabled() && c.ProfileCompleteness.Weights.KYCSteps > 0 && c.ProfileCompleteness.KYCStepsTarget <= NoneKYCStep {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.profileCompleteness.kycStepsTarget` must be positive if `%v.profileCompleteness.weights.kycSteps` is",
+			applicationYamlKey, applicationYamlKey))
+	}
+	for ix, threshold := range c.ProfileCompleteness.Thresholds {
+		if threshold > 100 { //nolint:gomnd // It's a percentage.
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.profileCompleteness.thresholds[%v]` must be a percentage", applicationYamlKey, ix))
+		}
+	}
+	if c.ReferralIntegrity.Policy != "" && c.ReferralIntegrity.Policy != ReportReferralRepairPolicy &&
+		c.ReferralIntegrity.Policy != ReparentReferralRepairPolicy {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.referralIntegrity.policy` must be `%v` or `%v`, not `%v`",
+			applicationYamlKey, ReportReferralRepairPolicy, ReparentReferralRepairPolicy, c.ReferralIntegrity.Policy))
+	}
+	if c.GlobalRank.Metric != "" && c.GlobalRank.Metric != T1ReferralsGlobalRankMetric && c.GlobalRank.Metric != TotalReferralsGlobalRankMetric {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.globalRank.metric` must be `%v` or `%v`, not `%v`",
+			applicationYamlKey, T1ReferralsGlobalRankMetric, TotalReferralsGlobalRankMetric, c.GlobalRank.Metric))
+	}
+	if c.UserSnapshots.Key != "" && c.UserSnapshots.Key != UserIDUserSnapshotKey && c.UserSnapshots.Key != UsernameUserSnapshotKey {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.userSnapshots.key` must be `%v` or `%v`, not `%v`",
+			applicationYamlKey, UserIDUserSnapshotKey, UsernameUserSnapshotKey, c.UserSnapshots.Key))
+	}
+	for feature, restriction := range c.countryRestrictions() {
+		for _, country := range restriction.Unavailable {
+			if restriction.mandatoryIn(country) {
+				mErr = multierror.Append(mErr, errors.Errorf("`%v.countryRestrictions.%v` can't be both unavailable and mandatory in `%v`",
+					applicationYamlKey, feature, country))
+			}
+		}
+	}
+	if c.AgeVerification.DeletionDelay < 0 || c.AgeVerification.DeletionInterval < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.ageVerification.deletionDelay` and `%v.ageVerification.deletionInterval` can't be negative",
+			applicationYamlKey, applicationYamlKey))
+	}
+	if c.UserIDs.Scheme != "" && c.UserIDs.Scheme != ULIDUserIDScheme && c.UserIDs.Scheme != UUIDUserIDScheme {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.userIds.scheme` must be `%v` or `%v`, not `%v`",
+			applicationYamlKey, ULIDUserIDScheme, UUIDUserIDScheme, c.UserIDs.Scheme))
+	}
+	if c.BlockchainAddresses.ChallengeTTL <= 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.blockchainAddresses.challengeTtl` must be positive", applicationYamlKey))
+	}
+	if c.DeletionPolicy != DeleteDeletionPolicy && c.DeletionPolicy != AnonymizeDeletionPolicy {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.deletionPolicy` must be `%v` or `%v`, not `%v`",
+			applicationYamlKey, DeleteDeletionPolicy, AnonymizeDeletionPolicy, c.DeletionPolicy))
+	}
+
+	return mErr
+}
+
+func (c *config) validateProcessor() *multierror.Error {
+	var mErr *multierror.Error
+	if len(c.MessageBroker.URLs) == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.messageBroker.urls` is missing", applicationYamlKey))
+	}
+	if len(c.MessageBroker.Topics) < requiredProducingTopics {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.messageBroker.topics` must have at least %v topics, in order, it has %v",
+			applicationYamlKey, requiredProducingTopics, len(c.MessageBroker.Topics)))
+	}
+	for ix, topic := range c.MessageBroker.Topics {
+		if topic == nil || topic.Name == "" {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.messageBroker.topics[%v].name` is missing", applicationYamlKey, ix))
+		}
+	}
+	if hint := c.UserSnapshots.PartitionCountHint; hint > 0 && len(c.MessageBroker.Topics) > 1 && c.MessageBroker.Topics[1] != nil &&
+		c.MessageBroker.Topics[1].Partitions != hint {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.messageBroker.topics[1].partitions` is %v, but `%v.userSnapshots.partitionCountHint` expects %v",
+			applicationYamlKey, c.MessageBroker.Topics[1].Partitions, applicationYamlKey, hint))
+	}
+	if !c.DisableConsumer {
+		if c.MessageBroker.ConsumerGroup == "" {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.messageBroker.consumerGroup` is missing", applicationYamlKey))
+		}
+		if len(c.MessageBroker.ConsumingTopics) < requiredConsumingTopics {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.messageBroker.consumingTopics` must have at least %v topics, in order, it has %v",
+				applicationYamlKey, requiredConsumingTopics, len(c.MessageBroker.ConsumingTopics)))
+		}
+	}
+	if c.KYC.KYCStep1ResetURL == "" {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.kyc.kyc-step1-reset-url` is missing", applicationYamlKey))
+	}
+	if c.CountersReconciliation.Interval < 0 || c.DuplicateAccountsDetection.Interval < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.countersReconciliation.interval` and `%v.duplicateAccountsDetection.interval` can't be negative",
+			applicationYamlKey, applicationYamlKey))
+	}
+	if c.UserDeletionBatches.Interval < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.userDeletionBatches.interval` can't be negative", applicationYamlKey))
+	}
+	if c.UserDeletionBatches.Interval > 0 {
+		if c.UserDeletionBatches.ClaimTTL <= 0 {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.userDeletionBatches.claimTtl` must be positive", applicationYamlKey))
+		}
+		if c.UserDeletionBatches.ChunkSize == 0 || c.UserDeletionBatches.Concurrency == 0 {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.userDeletionBatches.chunkSize` and `%v.userDeletionBatches.concurrency` must be positive",
+				applicationYamlKey, applicationYamlKey))
+		}
+	}
+	if c.UserImportBatches.Interval < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.userImportBatches.interval` can't be negative", applicationYamlKey))
+	}
+	if c.UserImportBatches.Interval > 0 {
+		if c.UserImportBatches.ClaimTTL <= 0 {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.userImportBatches.claimTtl` must be positive", applicationYamlKey))
+		}
+		if c.UserImportBatches.ChunkSize == 0 || c.UserImportBatches.Concurrency == 0 {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.userImportBatches.chunkSize` and `%v.userImportBatches.concurrency` must be positive",
+				applicationYamlKey, applicationYamlKey))
+		}
+	}
+	if c.ReferralIntegrity.Interval < 0 || c.ReferralIntegrity.SelfReferralGracePeriod < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.referralIntegrity.interval` and `%v.referralIntegrity.selfReferralGracePeriod` can't be negative",
+			applicationYamlKey, applicationYamlKey))
+	}
+	if c.UserReports.FlagThreshold == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.userReports.flagThreshold` must be positive", applicationYamlKey))
+	}
+	if c.ReferralInvitations.DailyQuota == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.referralInvitations.dailyQuota` must be positive", applicationYamlKey))
+	}
+	if c.ReferralPings.Cooldown <= 0 || c.ReferralPings.MaxBatchSize == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.referralPings.cooldown` and `%v.referralPings.maxBatchSize` must be positive",
+			applicationYamlKey, applicationYamlKey))
+	}
+	if c.EmailDomainStatistics.SpikeFactor <= 1 || c.EmailDomainStatistics.BaselineDays == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.emailDomainStatistics.spikeFactor` must be greater than 1 and `%v.emailDomainStatistics.baselineDays` positive",
+			applicationYamlKey, applicationYamlKey))
+	}
+	if c.GlobalRank.Interval < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.globalRank.interval` can't be negative", applicationYamlKey))
+	}
+	if c.LocationCanonicalization.Interval < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.locationCanonicalization.interval` can't be negative", applicationYamlKey))
+	}
+	if c.LocationCanonicalization.Interval > 0 && c.LocationCanonicalization.BatchSize == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.locationCanonicalization.batchSize` must be positive", applicationYamlKey))
+	}
+	if c.PIIReencryption.Interval < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.piiReencryption.interval` can't be negative", applicationYamlKey))
+	}
+	if c.PIIReencryption.Interval > 0 && c.PIIReencryption.BatchSize == 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.piiReencryption.batchSize` must be positive", applicationYamlKey))
+	}
+	if c.UserEvents.Retention < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.userEvents.retention` can't be negative", applicationYamlKey))
+	}
+	if c.StatisticsSnapshots.Interval < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.statisticsSnapshots.interval` can't be negative", applicationYamlKey))
+	}
+	if c.StatisticsSnapshots.BackfillFrom != "" {
+		if _, err := stdlibtime.Parse(stdlibtime.DateOnly, c.StatisticsSnapshots.BackfillFrom); err != nil {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.statisticsSnapshots.backfillFrom` must be a YYYY-MM-DD date", applicationYamlKey))
+		}
+	}
+	if c.DistributionEligibility.Interval < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.distributionEligibility.interval` can't be negative", applicationYamlKey))
+	}
+	if c.DistributionEligibility.Interval > 0 && (c.DistributionEligibility.ClaimTTL <= 0 || c.DistributionEligibility.PartSize == 0) {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.distributionEligibility.claimTtl` and `%v.distributionEligibility.partSize` must be positive",
+			applicationYamlKey, applicationYamlKey))
+	}
+	if c.DistributionEligibility.RequiredKYCStep < NoneKYCStep || c.DistributionEligibility.RequiredKYCStep > Social7KYCStep {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.distributionEligibility.requiredKycStep` must be a valid kyc step", applicationYamlKey))
+	}
+	for ix, milestone := range c.UserMilestones {
+		if milestone == 0 || (ix > 0 && milestone <= c.UserMilestones[ix-1]) {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.userMilestones` must be positive and in ascending order", applicationYamlKey))
+
+			break
+		}
+	}
+	badgeNames := make(map[string]struct{}, len(c.Badges))
+	for ix, badge := range c.Badges {
+		if badge == nil || badge.Name == "" || (badge.T1Referrals == 0 && badge.KYCStepPassed == NoneKYCStep) {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.badges[%v]` must have a name and at least a criteria", applicationYamlKey, ix))
+
+			continue
+		}
+		if _, duplicate := badgeNames[badge.Name]; duplicate {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.badges[%v].name` `%v` isn't unique", applicationYamlKey, ix, badge.Name))
+		}
+		badgeNames[badge.Name] = struct{}{}
+	}
+	for ix, level := range c.Levels {
+		if level == nil || (level.T1Referrals == 0 && level.MiningSessions == 0 && level.KYCStepPassed == NoneKYCStep) {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.levels[%v]` must have at least a criteria", applicationYamlKey, ix))
+		}
+	}
+	if c.QueryAudit.Enabled && c.QueryAudit.SlowQueryThreshold <= 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.queryAudit.slowQueryThreshold` must be positive", applicationYamlKey))
+	}
+	for residency := range c.DataResidency.Countries {
+		if residency != EUResidency && residency != USResidency {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.dataResidency.countries` can only have the %v and %v residencies, not %v",
+				applicationYamlKey, EUResidency, USResidency, residency))
+		}
+	}
+	for residency, clusterApplicationYAMLKey := range c.DataResidency.Clusters {
+		if residency != EUResidency && residency != USResidency && residency != OtherResidency {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.dataResidency.clusters.%v` isn't a residency", applicationYamlKey, residency))
+		}
+		if clusterApplicationYAMLKey == "" || clusterApplicationYAMLKey == applicationYamlKey {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.dataResidency.clusters.%v` must be the key of another storage config", applicationYamlKey, residency))
+		}
+	}
+	if c.UsernameSquatting.Interval < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.usernameSquatting.interval` can't be negative", applicationYamlKey))
+	}
+	if c.UsernameSquatting.Interval > 0 {
+		if c.UsernameSquatting.DormancyPeriod <= 0 || c.UsernameSquatting.GracePeriod <= 0 {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.usernameSquatting.dormancyPeriod` and `%v.usernameSquatting.gracePeriod` must be positive",
+				applicationYamlKey, applicationYamlKey))
+		}
+		if _, err := regexp.Compile(c.UsernameSquatting.DesirableUsernameRegex); err != nil || c.UsernameSquatting.DesirableUsernameRegex == "" {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.usernameSquatting.desirableUsernameRegex` is missing or invalid", applicationYamlKey))
+		}
+	}
+
+	return mErr
+}
@@ -6,21 +6,31 @@ import (
 	"context"
 	"database/sql"
 	_ "embed"
+	"expvar"
 	"io"
 	"mime/multipart"
 	"net"
 	"regexp"
+	"sync"
+	"sync/atomic"
 	stdlibtime "time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/pkg/errors"
 
+	"github.com/ice-blockchain/eskimo/users/internal/blockchain"
 	"github.com/ice-blockchain/eskimo/users/internal/device"
 	devicemetadata "github.com/ice-blockchain/eskimo/users/internal/device/metadata"
+	"github.com/ice-blockchain/eskimo/users/internal/encryption"
+	"github.com/ice-blockchain/eskimo/users/internal/invitation"
+	"github.com/ice-blockchain/eskimo/users/internal/objectstorage"
+	picturemoderation "github.com/ice-blockchain/eskimo/users/internal/picture/moderation"
+	picturestorage "github.com/ice-blockchain/eskimo/users/internal/picture/storage"
+	"github.com/ice-blockchain/eskimo/users/internal/profanity"
+	"github.com/ice-blockchain/eskimo/users/internal/search"
 	"github.com/ice-blockchain/wintr/analytics/tracking"
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
 	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
-	"github.com/ice-blockchain/wintr/multimedia/picture"
 	"github.com/ice-blockchain/wintr/time"
 )
 
@@ -29,6 +39,16 @@ import (
 const (
 	UsernameRegex               = `^[.a-zA-Z0-9]{4,30}$`
 	RequestingUserIDCtxValueKey = "requestingUserIDCtxValueKey"
+	// GuestUserIDPrefix is the prefix of the IDs of guest accounts, which have neither an email nor a phone number yet.
+	GuestUserIDPrefix = "ice_guest_"
+	// IceUserIDPrefix is the prefix of the IDs issued by us, instead of by Firebase, including the ones generated by CreateUser.
+	IceUserIDPrefix = "ice_"
+)
+
+const (
+	// ULIDUserIDScheme generates IDs sortable by the time they were generated at. It's the default.
+	ULIDUserIDScheme UserIDScheme = "ulid"
+	UUIDUserIDScheme UserIDScheme = "uuid"
 )
 
 const (
@@ -37,6 +57,229 @@ const (
 	LevelHiddenProfileElement         HiddenProfileElement = "level"
 	RoleHiddenProfileElement          HiddenProfileElement = "role"
 	BadgesHiddenProfileElement        HiddenProfileElement = "badges"
+	// ProfileViewsHiddenProfileElement stops the counting of the views of the profile too.
+	ProfileViewsHiddenProfileElement HiddenProfileElement = "profileViews"
+)
+
+const (
+	UsernameUserSearchField    UserSearchField = "username"
+	EmailUserSearchField       UserSearchField = "email"
+	PhoneNumberUserSearchField UserSearchField = "phoneNumber"
+)
+
+const (
+	ExactUserSearchMode  UserSearchMode = "exact"
+	PrefixUserSearchMode UserSearchMode = "prefix"
+)
+
+const (
+	DeviceDuplicateAccountSignal      DuplicateAccountSignal = "device"
+	PhoneNumberDuplicateAccountSignal DuplicateAccountSignal = "phoneNumber"
+	IPDuplicateAccountSignal          DuplicateAccountSignal = "ip"
+	AgendaDuplicateAccountSignal      DuplicateAccountSignal = "agenda"
+	EmailDomainDuplicateAccountSignal DuplicateAccountSignal = "emailDomain"
+)
+
+const (
+	AppliedCountryChangeStatus  CountryChangeStatus = "applied"
+	VerifiedCountryChangeStatus CountryChangeStatus = "verified"
+	PendingCountryChangeStatus  CountryChangeStatus = "pending"
+	ApprovedCountryChangeStatus CountryChangeStatus = "approved"
+	RejectedCountryChangeStatus CountryChangeStatus = "rejected"
+)
+
+const (
+	LoginRequestedAuthEventType  AuthEventType = "loginRequested"
+	CodeSentAuthEventType        AuthEventType = "codeSent"
+	CodeVerifiedAuthEventType    AuthEventType = "codeVerified"
+	CodeFailedAuthEventType      AuthEventType = "codeFailed"
+	SessionBlockedAuthEventType  AuthEventType = "sessionBlocked"
+	TokensRefreshedAuthEventType AuthEventType = "tokensRefreshed"
+	PasskeyVerifiedAuthEventType AuthEventType = "passkeyVerified"
+	GuestSignedInAuthEventType   AuthEventType = "guestSignedIn"
+	// TelegramVerifiedAuthEventType is sent when the user signs in with the init data of the Telegram Mini App.
+	TelegramVerifiedAuthEventType AuthEventType = "telegramVerified"
+	// LoggedOutEverywhereAuthEventType is sent for every device of the user, when all of its tokens are revoked.
+	LoggedOutEverywhereAuthEventType AuthEventType = "loggedOutEverywhere"
+)
+
+const (
+	DeleteUserDeletionBatchMode    UserDeletionBatchMode = "delete"
+	AnonymizeUserDeletionBatchMode UserDeletionBatchMode = "anonymize"
+)
+
+const (
+	DeletedUserDeletionOutcome    UserDeletionOutcome = "deleted"
+	AnonymizedUserDeletionOutcome UserDeletionOutcome = "anonymized"
+	NotFoundUserDeletionOutcome   UserDeletionOutcome = "notFound"
+	FailedUserDeletionOutcome     UserDeletionOutcome = "failed"
+)
+
+const (
+	SendUserImportSnapshotsMode     UserImportSnapshotsMode = "send"
+	BatchUserImportSnapshotsMode    UserImportSnapshotsMode = "batch"
+	SuppressUserImportSnapshotsMode UserImportSnapshotsMode = "suppress"
+)
+
+const (
+	ImportedUserImportOutcome  UserImportOutcome = "imported"
+	DuplicateUserImportOutcome UserImportOutcome = "duplicate"
+	InvalidUserImportOutcome   UserImportOutcome = "invalid"
+	FailedUserImportOutcome    UserImportOutcome = "failed"
+)
+
+const (
+	ExemptSquattedUsernameDecision  SquattedUsernameDecision = "exempt"
+	ReleaseSquattedUsernameDecision SquattedUsernameDecision = "release"
+)
+
+const (
+	ProfilePictureProfileElement ProfileElement = "profilePicture"
+	CountryProfileElement        ProfileElement = "country"
+	EmailProfileElement          ProfileElement = "email"
+	KYCStepsProfileElement       ProfileElement = "kycSteps"
+)
+
+const (
+	FlaggedUsernameSquattingEventType  UsernameSquattingEventType = "flagged"
+	ReleasedUsernameSquattingEventType UsernameSquattingEventType = "released"
+)
+
+const (
+	// SelfReferralAnomalyType is an user referred by itself. Only the root users are.
+	SelfReferralAnomalyType ReferralAnomalyType = "selfReferral"
+	// CycleReferralAnomalyType is an user referred, directly or not, by one of its own referrals.
+	// Only one user, the newest, is reported per cycle, as re-parenting it breaks the cycle.
+	CycleReferralAnomalyType ReferralAnomalyType = "cycle"
+	// DanglingReferralAnomalyType is an user referred by an user that doesn't exist (anymore).
+	DanglingReferralAnomalyType ReferralAnomalyType = "dangling"
+	// UsernameReferralAnomalyType is an user referred by the username of its referral, instead of its ID.
+	UsernameReferralAnomalyType ReferralAnomalyType = "username"
+)
+
+const (
+	// ReportReferralRepairPolicy only reports the referral anomalies.
+	ReportReferralRepairPolicy ReferralRepairPolicy = "report"
+	// ReparentReferralRepairPolicy also repairs them: the users referred by an username are re-parented to the user with that username,
+	// while the rest to icenetwork, as random referrals, like the referrals of the deleted users.
+	ReparentReferralRepairPolicy ReferralRepairPolicy = "reparent"
+)
+
+const (
+	T1ReferralsLevelCriterion    LevelCriterionName = "t1Referrals"
+	KYCStepPassedLevelCriterion  LevelCriterionName = "kycStepPassed"
+	MiningSessionsLevelCriterion LevelCriterionName = "miningSessions"
+)
+
+const (
+	// T1ReferralsGlobalRankMetric ranks the users by their T1 referrals.
+	T1ReferralsGlobalRankMetric GlobalRankMetric = "t1Referrals"
+	// TotalReferralsGlobalRankMetric ranks the users by their T1 and T2 referrals.
+	TotalReferralsGlobalRankMetric GlobalRankMetric = "totalReferrals"
+)
+
+const (
+	// InaccurateRectificationReasonCode is for the personal data that's wrong.
+	InaccurateRectificationReasonCode RectificationReasonCode = "inaccurate"
+	// IncompleteRectificationReasonCode is for the personal data that's missing parts.
+	IncompleteRectificationReasonCode RectificationReasonCode = "incomplete"
+	// LegalOrderRectificationReasonCode is for the rectifications ordered by a court or an authority.
+	LegalOrderRectificationReasonCode RectificationReasonCode = "legalOrder"
+)
+
+const (
+	SpamUserReportReason                 UserReportReason = "spam"
+	ScamUserReportReason                 UserReportReason = "scam"
+	HarassmentUserReportReason           UserReportReason = "harassment"
+	ImpersonationUserReportReason        UserReportReason = "impersonation"
+	InappropriateContentUserReportReason UserReportReason = "inappropriateContent"
+	OtherUserReportReason                UserReportReason = "other"
+)
+
+const (
+	PendingUserReportStatus   UserReportStatus = "pending"
+	ActionedUserReportStatus  UserReportStatus = "actioned"
+	DismissedUserReportStatus UserReportStatus = "dismissed"
+)
+
+const (
+	EmailReferralInvitationChannel = invitation.EmailChannel
+	SMSReferralInvitationChannel   = invitation.SMSChannel
+)
+
+const (
+	// SentReferralInvitationStatus means the invitation was sent and the invitee didn't sign up yet.
+	SentReferralInvitationStatus ReferralInvitationStatus = "sent"
+	// JoinedReferralInvitationStatus means the invitee signed up, with the email or phone number it was invited to.
+	JoinedReferralInvitationStatus ReferralInvitationStatus = "joined"
+	// The next ones are only returned when sending, for the contacts that were not invited.
+	AlreadyInvitedReferralInvitationStatus ReferralInvitationStatus = "alreadyInvited"
+	UnsubscribedReferralInvitationStatus   ReferralInvitationStatus = "unsubscribed"
+	NoConsentReferralInvitationStatus      ReferralInvitationStatus = "noConsent"
+	QuotaExceededReferralInvitationStatus  ReferralInvitationStatus = "quotaExceeded"
+	FailedReferralInvitationStatus         ReferralInvitationStatus = "failed"
+)
+
+const (
+	DeleteDeletionPolicy    DeletionPolicy = "delete"
+	AnonymizeDeletionPolicy DeletionPolicy = "anonymize"
+)
+
+const (
+	// AnonymizedUserSnapshotEvent is set on the snapshots of the users whose PII was stripped. They aren't deleted.
+	AnonymizedUserSnapshotEvent UserSnapshotEvent = "anonymized"
+	// MergedUserSnapshotEvent is set on the deletion snapshots of the users merged into other users. Their tombstone follows.
+	MergedUserSnapshotEvent UserSnapshotEvent = "merged"
+	// BlocksChangedUserSnapshotEvent is set on the snapshots sent when the user blocks or unblocks another user. The user itself doesn't change.
+	BlocksChangedUserSnapshotEvent UserSnapshotEvent = "blocksChanged"
+	// BlockchainAddressesChangedUserSnapshotEvent is set on the snapshots sent when the user adds or removes a blockchain address.
+	BlockchainAddressesChangedUserSnapshotEvent UserSnapshotEvent = "blockchainAddressesChanged"
+	// BadgesChangedUserSnapshotEvent is set on the snapshots sent when the user is awarded new badges.
+	BadgesChangedUserSnapshotEvent UserSnapshotEvent = "badgesChanged"
+	// The rest are derived from the snapshot and are found only in the `eventType` header and in UserSnapshotEnvelope.
+	CreatedUserSnapshotEvent UserSnapshotEvent = "created"
+	UpdatedUserSnapshotEvent UserSnapshotEvent = "updated"
+	DeletedUserSnapshotEvent UserSnapshotEvent = "deleted"
+)
+
+const (
+	CreatedUserEventType    UserEventType = "created"
+	ModifiedUserEventType   UserEventType = "modified"
+	DeletedUserEventType    UserEventType = "deleted"
+	AnonymizedUserEventType UserEventType = "anonymized"
+	MergedUserEventType     UserEventType = "merged"
+	// The KYC transitions are recorded along with the modifications that caused them. Their `kycStep` is the new value (0 if reset).
+	KYCStepPassedUserEventType  UserEventType = "kycStepPassed"
+	KYCStepBlockedUserEventType UserEventType = "kycStepBlocked"
+)
+
+const (
+	// UserIDUserSnapshotKey keys the user snapshots by the user ID, so all the snapshots of an user are ordered. It's the default.
+	UserIDUserSnapshotKey UserSnapshotKey = "userId"
+	// UsernameUserSnapshotKey keys the user snapshots by the username, so compacted topics keep the latest user of every username.
+	// The snapshots of an user are ordered only as long as the user keeps its username.
+	UsernameUserSnapshotKey UserSnapshotKey = "username"
+)
+
+const (
+	// EUResidency is the residency of the users from the `dataResidency.countries.EU` countries.
+	EUResidency Residency = "EU"
+	// USResidency is the residency of the users from the `dataResidency.countries.US` countries.
+	USResidency Residency = "US"
+	// OtherResidency is the residency of the users from any other country.
+	OtherResidency Residency = "other"
+)
+
+const (
+	// UserSnapshotSchemaVersion is the version of the UserSnapshot schema. It's bumped on every breaking change.
+	UserSnapshotSchemaVersion = "1"
+)
+
+const (
+	// MaxUserDeletionBatchSize is the maximum number of users in a single deletion batch.
+	MaxUserDeletionBatchSize = 10000
+	// MaxUserImportBatchSize is the maximum number of records in a single import batch.
+	MaxUserImportBatchSize = 100000
 )
 
 const (
@@ -60,14 +303,64 @@ const (
 	Social7KYCStep
 )
 
+const (
+	FaceKYCCountryRestrictedFeature   CountryRestrictedFeature = "faceKyc"
+	SocialKYCCountryRestrictedFeature CountryRestrictedFeature = "socialKyc"
+	QuizKYCCountryRestrictedFeature   CountryRestrictedFeature = "quizKyc"
+)
+
+const (
+	KYCDeviceAttestationPurpose  = devicemetadata.KYCDeviceAttestationPurpose
+	AuthDeviceAttestationPurpose = devicemetadata.AuthDeviceAttestationPurpose
+)
+
+const (
+	AndroidPlatform = devicemetadata.AndroidPlatform
+	IOSPlatform     = devicemetadata.IOSPlatform
+)
+
+const (
+	EVMBlockchainChain    = blockchain.EVMChain
+	SolanaBlockchainChain = blockchain.SolanaChain
+)
+
 var (
-	ErrNotFound           = storage.ErrNotFound
-	ErrRelationNotFound   = storage.ErrRelationNotFound
-	ErrDuplicate          = storage.ErrDuplicate
-	ErrInvalidAppVersion  = devicemetadata.ErrInvalidAppVersion
-	ErrOutdatedAppVersion = devicemetadata.ErrOutdatedAppVersion
-	ErrInvalidCountry     = errors.New("country invalid")
-	ErrRaceCondition      = errors.New("race condition")
+	ErrNotFound                 = storage.ErrNotFound
+	ErrRelationNotFound         = storage.ErrRelationNotFound
+	ErrDuplicate                = storage.ErrDuplicate
+	ErrInvalidAppVersion        = devicemetadata.ErrInvalidAppVersion
+	ErrOutdatedAppVersion       = devicemetadata.ErrOutdatedAppVersion
+	ErrInvalidCountry           = errors.New("country invalid")
+	ErrRaceCondition            = errors.New("race condition")
+	ErrInvalidProfilePicture    = errors.New("invalid profile picture")
+	ErrSignedUploadNotSupported = picturestorage.ErrSignedUploadNotSupported
+	ErrKYCStepAttemptsExceeded  = errors.New("kyc step attempts exceeded")
+
+	ErrInvalidSquattedUsernameDecision = errors.New("invalid squatted username decision")
+	ErrInvalidAccountMerge             = errors.New("invalid account merge")
+	ErrNothingToRectify                = errors.New("nothing to rectify")
+	ErrInvalidUserReportResolution     = errors.New("invalid user report resolution")
+	ErrProfanity                       = errors.New("profanity")
+	ErrReferralInvitationsDisabled     = errors.New("referral invitations disabled")
+	ErrInvalidUserImportFile           = errors.New("invalid user import file")
+	ErrInvalidReferralPing             = errors.New("invalid referral ping")
+	ErrFeatureRestrictedInCountry      = errors.New("feature restricted in country")
+	ErrInvalidDateOfBirth              = errors.New("invalid date of birth")
+	ErrUnderage                        = errors.New("underage")
+
+	ErrUnsupportedBlockchainChain          = blockchain.ErrUnsupportedChain
+	ErrInvalidBlockchainAddress            = blockchain.ErrInvalidAddress
+	ErrInvalidBlockchainAddressSignature   = blockchain.ErrInvalidSignature
+	ErrBlockchainAddressUsedBySomebodyElse = errors.New("blockchain address used by somebody else")
+	ErrBlockchainAddressChallengeNotFound  = errors.New("blockchain address challenge not found")
+	ErrTooManyBlockchainAddresses          = errors.New("too many blockchain addresses")
+
+	ErrDeviceAttestationRequired            = devicemetadata.ErrDeviceAttestationRequired
+	ErrDeviceAttestationChallengeNotFound   = devicemetadata.ErrDeviceAttestationChallengeNotFound
+	ErrInvalidDeviceAttestation             = devicemetadata.ErrInvalidDeviceAttestation
+	ErrUnsupportedDeviceAttestationProvider = devicemetadata.ErrUnsupportedDeviceAttestationProvider
+	ErrInvalidPlatform                      = devicemetadata.ErrInvalidPlatform
+	ErrInvalidDeviceMetadata                = devicemetadata.ErrInvalidDeviceMetadata
 	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
 	ReferralTypes = Enum[ReferralType]{ContactsReferrals, Tier1Referrals, Tier2Referrals, TeamReferrals}
 	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
@@ -77,26 +370,56 @@ var (
 		LevelHiddenProfileElement,
 		RoleHiddenProfileElement,
 		BadgesHiddenProfileElement,
+		ProfileViewsHiddenProfileElement,
+	}
+	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
+	RectificationReasonCodes = Enum[RectificationReasonCode]{
+		InaccurateRectificationReasonCode,
+		IncompleteRectificationReasonCode,
+		LegalOrderRectificationReasonCode,
+	}
+	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
+	UserEventTypes = Enum[UserEventType]{
+		CreatedUserEventType,
+		ModifiedUserEventType,
+		DeletedUserEventType,
+		AnonymizedUserEventType,
+		MergedUserEventType,
+		KYCStepPassedUserEventType,
+		KYCStepBlockedUserEventType,
+	}
+	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
+	UserReportReasons = Enum[UserReportReason]{
+		SpamUserReportReason,
+		ScamUserReportReason,
+		HarassmentUserReportReason,
+		ImpersonationUserReportReason,
+		InappropriateContentUserReportReason,
+		OtherUserReportReason,
 	}
 	CompiledUsernameRegex = regexp.MustCompile(UsernameRegex)
 )
 
 type (
-	KYCStep                  int8
-	ReferralType             string
-	HiddenProfileElement     string
-	NotExpired               bool
-	Enum[T ~string]          []T
-	JSON                     map[string]any
-	UserID                   = string
+	KYCStep              int8
+	ReferralType         string
+	HiddenProfileElement string
+	NotExpired           bool
+	Enum[T ~string]      []T
+	JSON                 map[string]any
+	UserID               = string
+	// RedactedEmail is an email that's redacted when it's formatted (logged), unless the unredacted logs are enabled.
+	RedactedEmail string
+	// RedactedPhoneNumber is a phone number that's redacted when it's formatted (logged), unless the unredacted logs are enabled.
+	RedactedPhoneNumber      string
 	SensitiveUserInformation struct {
-		PhoneNumber string `json:"phoneNumber,omitempty" example:"+12099216581" swaggertype:"string" db:"phone_number"`
-		Email       string `json:"email,omitempty" example:"jdoe@gmail.com" swaggertype:"string" db:"email"`
+		PhoneNumber string `json:"phoneNumber,omitempty" example:"+12099216581" swaggertype:"string" db:"phone_number" redact:"phoneNumber"`
+		Email       string `json:"email,omitempty" example:"jdoe@gmail.com" swaggertype:"string" db:"email" redact:"email"`
 	}
 	PrivateUserInformation struct {
 		SensitiveUserInformation
-		FirstName *string `json:"firstName,omitempty" example:"John" db:"first_name"`
-		LastName  *string `json:"lastName,omitempty" example:"Doe" db:"last_name"`
+		FirstName *string `json:"firstName,omitempty" example:"John" db:"first_name" redact:"name"`
+		LastName  *string `json:"lastName,omitempty" example:"Doe" db:"last_name" redact:"name"`
 		devicemetadata.DeviceLocation
 	}
 	PublicUserInformation struct {
@@ -107,10 +430,10 @@ type (
 	User struct {
 		CreatedAt               *time.Time                  `json:"createdAt,omitempty" example:"2022-01-03T16:20:52.156534Z" db:"created_at"`
 		UpdatedAt               *time.Time                  `json:"updatedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" db:"updated_at"`
-		LastMiningStartedAt     *time.Time                  `json:"lastMiningStartedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" swaggerignore:"true" db:"last_mining_started_at"`                                       //nolint:lll // .
-		LastMiningEndedAt       *time.Time                  `json:"lastMiningEndedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" swaggerignore:"true" db:"last_mining_ended_at"`                                           //nolint:lll // .
-		LastPingCooldownEndedAt *time.Time                  `json:"lastPingCooldownEndedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" swaggerignore:"true" db:"last_ping_cooldown_ended_at"`                              //nolint:lll // .
-		HiddenProfileElements   *Enum[HiddenProfileElement] `json:"hiddenProfileElements,omitempty" swaggertype:"array,string" example:"level" enums:"globalRank,referralCount,level,role,badges" db:"hidden_profile_elements"` //nolint:lll // .
+		LastMiningStartedAt     *time.Time                  `json:"lastMiningStartedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" swaggerignore:"true" db:"last_mining_started_at"`                                                    //nolint:lll // .
+		LastMiningEndedAt       *time.Time                  `json:"lastMiningEndedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" swaggerignore:"true" db:"last_mining_ended_at"`                                                        //nolint:lll // .
+		LastPingCooldownEndedAt *time.Time                  `json:"lastPingCooldownEndedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" swaggerignore:"true" db:"last_ping_cooldown_ended_at"`                                           //nolint:lll // .
+		HiddenProfileElements   *Enum[HiddenProfileElement] `json:"hiddenProfileElements,omitempty" swaggertype:"array,string" example:"level" enums:"globalRank,referralCount,level,role,badges,profileViews" db:"hidden_profile_elements"` //nolint:lll // .
 		RandomReferredBy        *bool                       `json:"randomReferredBy,omitempty" example:"true" swaggerignore:"true" db:"random_referred_by"`
 		Verified                *bool                       `json:"verified,omitempty" example:"true" db:"-"`
 		QuizCompleted           *bool                       `json:"-" db:"quiz_completed"`
@@ -120,10 +443,16 @@ type (
 		KYCStepBlocked          *KYCStep                    `json:"kycStepBlocked,omitempty" example:"0" db:"kyc_step_blocked"`
 		ClientData              *JSON                       `json:"clientData,omitempty" db:"client_data"`
 		RepeatableKYCSteps      *map[KYCStep]*time.Time     `json:"repeatableKYCSteps,omitempty" db:"-"` //nolint:tagliatelle // Nope.
+		PendingCountryChange    *CountryChange              `json:"pendingCountryChange,omitempty" db:"-"`
+		Residency               *Residency                  `json:"residency,omitempty" example:"EU" enums:"EU,US,other" db:"residency"`
+		// DateOfBirth is only captured (encrypted), it's never returned. Format: `YYYY-MM-DD`.
+		DateOfBirth *string `json:"dateOfBirth,omitempty" example:"2000-01-31" swaggerignore:"true" db:"-" redact:"dateOfBirth"`
 		PrivateUserInformation
 		PublicUserInformation
 		ReferredBy                     UserID   `json:"referredBy,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"referred_by"`
 		PhoneNumberHash                string   `json:"phoneNumberHash,omitempty" example:"Ef86A6021afCDe5673511376B2" swaggerignore:"true" db:"phone_number_hash"`
+		PhoneNumberIndex               *string  `json:"-" swaggerignore:"true" db:"phone_number_index"`
+		EmailIndex                     *string  `json:"-" swaggerignore:"true" db:"email_index"`
 		AgendaPhoneNumberHashes        *string  `json:"agendaPhoneNumberHashes,omitempty" example:"Ef86A6021afCDe5673511376B2,Ef86A6021afCDe5673511376B2,Ef86A6021afCDe5673511376B2,Ef86A6021afCDe5673511376B2" db:"-"` //nolint:lll // .
 		MiningBlockchainAccountAddress string   `json:"miningBlockchainAccountAddress,omitempty" example:"0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"mining_blockchain_account_address"`                           //nolint:lll // .
 		BlockchainAccountAddress       string   `json:"blockchainAccountAddress,omitempty" example:"0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"blockchain_account_address"`                                        //nolint:lll // .
@@ -140,29 +469,597 @@ type (
 		PublicUserInformation
 		devicemetadata.DeviceLocation
 		ReferralType ReferralType `json:"referralType,omitempty" example:"T1" enums:"CONTACTS,T0,T1,T2"`
+		// The name the user has for the contact in its agenda. Set only for the CONTACTS referrals, if the user stored it. See AgendaContactName.
+		ContactName string `json:"contactName,omitempty" example:"Alice"`
+		// Set only for the results of GetUsers.
+		SearchMatch *SearchMatch `json:"searchMatch,omitempty"`
+	}
+	// PingableReferrals are the T1 referrals and the referrer (T0) of the user that can be pinged now:
+	// they're not mining and they weren't pinged in the last `referralPings.cooldown`.
+	PingableReferrals struct {
+		Referrals []*MinimalUserProfile `json:"referrals"`
+		// SelectAllToken pings all of them, not just the ones in this page, without listing them. See ReferralPing.
+		SelectAllToken string `json:"selectAllToken" example:"ZGlkOmV0aHI6MHg0QjczQzU4MzcwQUVmY0VmODZBNjAyMUFmQ0RlNTY3MzUxMTM3NkIyOjE3MDAwMDAwMDAwMDAwMDAwMDA"`
+		Total          uint64 `json:"total" example:"100"`
+	}
+	// MutualConnections are the users that both the requesting user and another one know, for the "you both know X" feature.
+	// Nothing is returned if any of them blocked the other.
+	MutualConnections struct {
+		// Set if both of them were referred by the same user.
+		SharedReferrer *PublicUserInformation `json:"sharedReferrer,omitempty"`
+		// The users that both of them have in their agendas and that have both of them in theirs, at most maxMutualContacts.
+		Contacts []*PublicUserInformation `json:"contacts"`
+		// The number of all of them, not just the returned ones.
+		TotalContacts uint64 `json:"totalContacts" example:"25"`
+	}
+	// ReferralPing pings either the referrals with the provided IDs or all the ones selected by the SelectAllToken of PingableReferrals,
+	// at most `referralPings.maxBatchSize` of them. The ones that aren't pingable anymore are skipped.
+	ReferralPing struct {
+		SelectAllToken string   `json:"selectAllToken,omitempty" example:"ZGlkOmV0aHI6MHg0QjczQzU4MzcwQUVmY0VmODZBNjAyMUFmQ0RlNTY3MzUxMTM3NkIyOjE3MDAwMDAwMDAwMDAwMDAwMDA"`
+		UserIDs        []UserID `json:"userIds,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+	}
+	// ReferralPingResult are the referrals that were pinged and when they can be pinged again.
+	ReferralPingResult struct {
+		CooldownEndedAt *time.Time `json:"cooldownEndedAt" example:"2022-01-03T16:20:52.156534Z"`
+		UserIDs         []UserID   `json:"userIds" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+	}
+	// SearchMatch is where the keyword matched, so it can be highlighted: the characters of the field in [start, end).
+	SearchMatch struct {
+		Field UserSearchField `json:"field" example:"username" enums:"username"`
+		Start uint64          `json:"start" example:"0"`
+		End   uint64          `json:"end" example:"4"`
 	}
 	UserProfile struct {
 		*User
-		T1ReferralCount *uint64 `json:"t1ReferralCount,omitempty" example:"100"`
-		T2ReferralCount *uint64 `json:"t2ReferralCount,omitempty" example:"100"`
+		T1ReferralCount *uint64            `json:"t1ReferralCount,omitempty" example:"100"`
+		T2ReferralCount *uint64            `json:"t2ReferralCount,omitempty" example:"100"`
+		KYCTimeline     []*KYCStepProgress `json:"kycTimeline,omitempty"`
+		// Percentage of the profile that's complete. Only for the user itself.
+		ProfileCompleteness *uint64 `json:"profileCompleteness,omitempty" example:"75" db:"-"`
+		// Only for the user itself, unless it hid them.
+		ProfileViews *ProfileViews `json:"profileViews,omitempty" db:"-"`
+		// The position of the user among all the ones with referrals, by `globalRank.metric`, as of the last time they were ranked.
+		// The ones with the same metric share it. Hidden from the other users, if the user hid it.
+		GlobalRank *uint64 `json:"globalRank,omitempty" example:"37" db:"global_rank"`
+		// Hidden from the other users, if the user hid it. See UserLevel.
+		Level *uint64 `json:"level,omitempty" example:"2" db:"level"`
+	}
+	// Badge is an achievement of the user, awarded once it meets all the criteria of one of the `badges`. It's never revoked.
+	Badge struct {
+		AwardedAt   *time.Time `json:"awardedAt" example:"2022-01-03T16:20:52.156534Z" db:"awarded_at"`
+		Name        string     `json:"name" example:"inviter" db:"name"`
+		Description string     `json:"description,omitempty" example:"Invited 10 users" db:"-"`
+	}
+	// UserLevel is the highest level the user reached, by meeting the criteria of it and of all the `levels` before it. It's never lost.
+	UserLevel struct {
+		// Hidden from the other users, if the user hid it.
+		Level *uint64 `json:"level,omitempty" example:"2"`
+		// The progress towards the next level. Only for the user itself, unless it reached the last one.
+		Next *LevelProgress `json:"next,omitempty"`
+	}
+	LevelProgress struct {
+		Criteria []*LevelCriterion `json:"criteria"`
+		Level    uint64            `json:"level" example:"3"`
+		// The average completion of the criteria, each one capped at 100.
+		Percentage uint64 `json:"percentage" example:"40"`
+	}
+	LevelCriterion struct {
+		Name     LevelCriterionName `json:"name" example:"t1Referrals" enums:"t1Referrals,kycStepPassed,miningSessions"`
+		Current  uint64             `json:"current" example:"4"`
+		Required uint64             `json:"required" example:"10"`
+	}
+	// ProfileViews are how many other users viewed the profile, each counted once per (UTC) day.
+	ProfileViews struct {
+		Today      uint64 `json:"today" example:"3" db:"today"`
+		Last7Days  uint64 `json:"last7Days" example:"12" db:"last_7_days"`
+		Last30Days uint64 `json:"last30Days" example:"40" db:"last_30_days"`
+	}
+	// ProfileChanges are the fields of the profile that changed since the version of it with the `sinceChecksum`,
+	// or all of them (`full`), if that version is unknown.
+	ProfileChanges struct {
+		Changes  map[string]any `json:"changes,omitempty"`
+		Checksum string         `json:"checksum" example:"1232412415326543647657"`
+		Removed  []string       `json:"removed,omitempty" example:"phoneNumber"`
+		Full     bool           `json:"full" example:"false"`
+	}
+	// KYCStepProgress is the history of a KYC step, as derived from the kycSteps* arrays, plus the attempts recorded for it.
+	KYCStepProgress struct {
+		CreatedAt     *time.Time `json:"createdAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		LastUpdatedAt *time.Time `json:"lastUpdatedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		Attempts      uint64     `json:"attempts" example:"2"`
+		Step          KYCStep    `json:"step" example:"1"`
+		Passed        bool       `json:"passed" example:"true"`
+		Blocked       bool       `json:"blocked" example:"false"`
+		*KYCStepAttempts
+	}
+	CountryRestrictedFeature string
+	// KYCConfig is what the KYC flow looks like for the user, based on its country.
+	KYCConfig struct {
+		Country             string                     `json:"country" example:"US"`
+		UnavailableFeatures []CountryRestrictedFeature `json:"unavailableFeatures" example:"faceKyc" enums:"faceKyc,socialKyc,quizKyc"`
+		MandatoryFeatures   []CountryRestrictedFeature `json:"mandatoryFeatures" example:"quizKyc" enums:"faceKyc,socialKyc,quizKyc"`
+	}
+	// KYCStepAttempts is set only for the KYC steps with attempt limits.
+	KYCStepAttempts struct {
+		NextAllowedAt     *time.Time `json:"nextAllowedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		RemainingAttempts uint64     `json:"remainingAttempts" example:"2"`
+		MaxAttempts       uint64     `json:"maxAttempts" example:"3"`
 	}
 	Referrals struct {
 		Referrals []*MinimalUserProfile `json:"referrals"`
 		UserCount
 	}
-	UserSnapshot struct {
+	UserSnapshotEvent string
+	UserSnapshot      struct {
 		*User
 		Before *User `json:"before,omitempty"`
+		// Optional. Set only for the events that can't be derived from `before` and the user.
+		Event UserSnapshotEvent `json:"event,omitempty" example:"anonymized" enums:"anonymized,merged,blocksChanged,blockchainAddressesChanged,badgesChanged"`
+		// The users the user blocked. Set for all the snapshots of existing users.
+		BlockedUserIDs []UserID `json:"blockedUserIds,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3"`
+		// The blockchain addresses proven to be owned by the user. Set for all the snapshots of existing users.
+		BlockchainAddresses []*BlockchainAddress `json:"blockchainAddresses,omitempty"`
+		// The names of the badges awarded to the user. Set for all the snapshots of existing users.
+		Badges []string `json:"badges,omitempty" example:"inviter"`
+	}
+	UserSnapshotKey string
+	// Residency is where the data of the user must reside. It's set at signup, from the device location, and it never changes.
+	Residency string
+	// UserSnapshotEnvelope is an UserSnapshot along with the metadata it's sent to the broker with: its key and its headers.
+	// Snapshot is nil for tombstones.
+	UserSnapshotEnvelope struct {
+		Snapshot      *UserSnapshot
+		Key           string
+		EventType     UserSnapshotEvent
+		SchemaVersion string
+		Tenant        string
+	}
+	UserSearchField string
+	UserSearchMode  string
+	UserSearch      struct {
+		AdminUserID UserID
+		Field       UserSearchField
+		Mode        UserSearchMode
+		Keyword     string
+		// Reason is recorded in the audit. For example, the support ticket it's for.
+		Reason string
+	}
+	CountryChangeStatus string
+	// CountryChange is a change of the country of an user. If the user changed it too many times, it's pending until it's verified.
+	CountryChange struct {
+		CreatedAt   *time.Time          `json:"createdAt" example:"2022-01-03T16:20:52.156534Z" db:"created_at"`
+		DecidedAt   *time.Time          `json:"decidedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" db:"decided_at"`
+		UserID      UserID              `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		FromCountry string              `json:"fromCountry" example:"US" db:"from_country"`
+		ToCountry   string              `json:"toCountry" example:"RO" db:"to_country"`
+		ToCity      string              `json:"toCity,omitempty" example:"Bucharest" db:"to_city"`
+		Status      CountryChangeStatus `json:"status" example:"pending" enums:"applied,verified,pending,approved,rejected" db:"status"`
+		DecidedBy   string              `json:"decidedBy,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3" db:"decided_by"`
+	}
+	DuplicateAccountSignal    string
+	DuplicateAccountCandidate struct {
+		DetectedAt      *time.Time `json:"detectedAt" example:"2022-01-03T16:20:52.156534Z" db:"detected_at"`
+		UpdatedAt       *time.Time `json:"updatedAt" example:"2022-01-03T16:20:52.156534Z" db:"updated_at"`
+		UserID          UserID     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		DuplicateUserID UserID     `json:"duplicateUserId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3" db:"duplicate_user_id"`
+		Signals         []string   `json:"signals" example:"device,ip" enums:"device,phoneNumber,ip,agenda,emailDomain" db:"signals"`
+		AgendaOverlap   float64    `json:"agendaOverlap" example:"0.75" db:"agenda_overlap"`
+		Score           uint64     `json:"score" example:"65" db:"score"`
+	}
+	ProfilePictureModerationEvent struct {
+		CreatedAt             *time.Time `json:"createdAt" example:"2022-01-03T16:20:52.156534Z" db:"created_at"`
+		UserID                UserID     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		PictureName           string     `json:"pictureName" example:"1_1672762852156534.jpg" db:"picture_name"`
+		RevertedToPictureName string     `json:"revertedToPictureName" example:"default-profile-picture-1.png" db:"reverted_to_picture_name"`
+		Provider              string     `json:"provider" example:"sightengine" db:"provider"`
+		Violations            []string   `json:"violations" example:"nsfw,faceMismatch" db:"violations"`
+		Reverted              bool       `json:"reverted" example:"true" db:"reverted"`
+	}
+	// UserBlock is an user blocking another one: the blocked user is hidden from its searches and its contacts.
+	UserBlock struct {
+		CreatedAt     *time.Time `json:"createdAt" example:"2022-01-03T16:20:52.156534Z" db:"created_at"`
+		UserID        UserID     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		BlockedUserID UserID     `json:"blockedUserId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3" db:"blocked_user_id"`
+	}
+	// BlockchainAddress is an address whose ownership was proven by the user, by signing the message of a BlockchainAddressChallenge with it.
+	// An address belongs to a single user. It's the schema of the messages sent to the blockchain address verifications topic too.
+	BlockchainAddress struct {
+		CreatedAt *time.Time `json:"createdAt" example:"2022-01-03T16:20:52.156534Z" db:"created_at"`
+		// The last time the ownership was proven. Answering a new challenge for an address the user has already proves it again.
+		VerifiedAt *time.Time      `json:"verifiedAt" example:"2022-01-03T16:20:52.156534Z" db:"verified_at"`
+		UserID     UserID          `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		Chain      BlockchainChain `json:"chain" example:"evm" enums:"evm,solana" db:"chain"`
+		// The canonical form of the address: EIP-55 checksummed for the EVM chains.
+		Address string `json:"address" example:"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed" db:"address"`
+	}
+	// BlockchainAddressChallenge is the one time message the address has to sign, within the expiration, to be added to the user.
+	BlockchainAddressChallenge struct {
+		ExpiresAt *time.Time `json:"expiresAt" example:"2022-01-03T16:20:52.156534Z"`
+		Message   string     `json:"message" example:"I own the evm address 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed and I'm linking it to the ice account did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2. Nonce: 6f2b8c3a1e9d4f7b8a0c5e2d1f3b4a6c"` //nolint:lll // .
+	}
+	// KYCDataPurge is the proof that the KYC data of an user was deleted, both at the provider and locally.
+	KYCDataPurge struct {
+		PurgedAt           *time.Time `json:"purgedAt" example:"2022-01-03T16:20:52.156534Z" db:"purged_at"`
+		UserID             UserID     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		RequestedBy        UserID     `json:"requestedBy" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"requested_by"`
+		ProviderResponse   string     `json:"providerResponse,omitempty" example:"{}" db:"provider_response"`
+		ProviderStatusCode int        `json:"providerStatusCode" example:"200" db:"provider_status_code"`
+	}
+	UserEventType string
+	// UserEvent is an entry of the append-only log of the lifecycle of the users, kept for `userEvents.retention`,
+	// so that it can be investigated even after the retention of the message broker expired. It has no PII, only the names of the modified fields.
+	UserEvent struct {
+		CreatedAt *time.Time    `json:"createdAt" example:"2022-01-03T16:20:52.156534Z" db:"created_at"`
+		KYCStep   *KYCStep      `json:"kycStep,omitempty" example:"1" db:"kyc_step"`
+		UserID    UserID        `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		EventType UserEventType `json:"eventType" example:"modified" db:"event_type"`
+		Fields    []string      `json:"fields,omitempty" example:"username,country" db:"fields"`
+	}
+	// QueryAudit is how long the queries of the repository took on this replica, since it started, while the query audit is enabled.
+	QueryAudit struct {
+		StartedAt *time.Time `json:"startedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		// The slowest queries first.
+		Queries []*QueryStatistics `json:"queries"`
+		// The queries slower than it are logged and, if enabled, explained.
+		SlowQueryThreshold string `json:"slowQueryThreshold,omitempty" example:"500ms"`
+		Enabled            bool   `json:"enabled" example:"true"`
+	}
+	QueryStatistics struct {
+		SlowestAt *time.Time `json:"slowestAt" example:"2022-01-03T16:20:52.156534Z"`
+		// How many executions took at most each duration. The ones slower than all of them are counted in `+Inf`.
+		Histogram map[string]uint64 `json:"histogram" example:"10ms:3"`
+		Query     string            `json:"query" example:"SELECT * FROM users WHERE id = $1"`
+		// The EXPLAIN output of the slowest execution, if it was slow and the slow queries are explained.
+		Plan string `json:"plan,omitempty" example:"Index Scan using users_pkey on users"`
+		// The arguments of the slowest execution. Only the numbers, the booleans and the dates are kept, the rest are redacted.
+		SlowestArgs   []string `json:"slowestArgs" example:"***"`
+		Count         uint64   `json:"count" example:"3"`
+		TotalMillis   float64  `json:"totalMillis" example:"12.5"`
+		SlowestMillis float64  `json:"slowestMillis" example:"7.2"`
+	}
+	// MaintenanceMode makes all the API calls fail, except the ones of the allowlisted users, while it's enabled.
+	MaintenanceMode struct {
+		UpdatedAt *time.Time `json:"updatedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" db:"updated_at"`
+		// Optional. When the maintenance is expected to end.
+		ETA       *time.Time `json:"eta,omitempty" example:"2022-01-03T18:20:52.156534Z" db:"eta"`
+		UpdatedBy UserID     `json:"updatedBy,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"updated_by"`
+		// The message shown to the users, per language. `en` is used for the languages without one.
+		Messages map[string]string `json:"messages,omitempty" example:"en:We'll be back soon!" db:"messages"`
+		// The users whose calls are still served.
+		Allowlist []UserID `json:"allowlist,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"allowlist"`
+		Enabled   bool     `json:"enabled" example:"true" db:"enabled"`
+	}
+	ProfileElement string
+	// ProfileCompletenessChange is the schema of the messages sent to the profile completeness changes topic, so that the users can be nudged.
+	// They're sent only when the percentage crosses one of the configured thresholds.
+	ProfileCompletenessChange struct {
+		CreatedAt *time.Time `json:"createdAt" example:"2022-01-03T16:20:52.156534Z"`
+		UserID    UserID     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		// What the user still has to do to complete the profile.
+		Missing []ProfileElement `json:"missing,omitempty" example:"profilePicture,kycSteps" enums:"profilePicture,country,email,kycSteps"`
+		Before  uint64           `json:"before" example:"50"`
+		After   uint64           `json:"after" example:"75"`
+	}
+	SquattedUsernameDecision   string
+	UsernameSquattingEventType string
+	// SquattedUsername is a desirable username held by a dormant user (never verified, never mined), that's released unless they become active.
+	SquattedUsername struct {
+		FlaggedAt *time.Time `json:"flaggedAt" example:"2022-01-03T16:20:52.156534Z" db:"flagged_at"`
+		// When the username is released, unless the user becomes active.
+		ExpiresAt  *time.Time `json:"expiresAt" example:"2022-01-10T16:20:52.156534Z" db:"expires_at"`
+		ReleasedAt *time.Time `json:"releasedAt,omitempty" example:"2022-01-10T16:20:52.156534Z" db:"released_at"`
+		ExemptedAt *time.Time `json:"exemptedAt,omitempty" example:"2022-01-05T16:20:52.156534Z" db:"exempted_at"`
+		UserID     UserID     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		Username   string     `json:"username" example:"jdoe" db:"username"`
+		ExemptedBy *UserID    `json:"exemptedBy,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3" db:"exempted_by"`
+	}
+	// UnderageUser is an user younger than the minimum age of its country, as per its date of birth.
+	// It's blocked as soon as it's detected and it's deleted at `deletionDueAt`.
+	UnderageUser struct {
+		DetectedAt    *time.Time `json:"detectedAt" example:"2022-01-03T16:20:52.156534Z" db:"detected_at"`
+		DeletionDueAt *time.Time `json:"deletionDueAt" example:"2022-02-03T16:20:52.156534Z" db:"deletion_due_at"`
+		DeletedAt     *time.Time `json:"deletedAt,omitempty" example:"2022-02-03T16:20:52.156534Z" db:"deleted_at"`
+		UserID        UserID     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		Country       string     `json:"country" example:"US" db:"country"`
+		MinimumAge    uint64     `json:"minimumAge" example:"13" db:"minimum_age"`
+		Age           uint64     `json:"age" example:"12" db:"age"`
+	}
+	// UsernameSquattingEvent is the schema of the messages sent to the username squatting events topic, to notify the users.
+	UsernameSquattingEvent struct {
+		*SquattedUsername
+		Type UsernameSquattingEventType `json:"type" example:"flagged" enums:"flagged,released"`
+	}
+	// AccountMerge merges the source user into the target one, which keeps its ID. It's also the schema of the account merge requests topic.
+	AccountMerge struct {
+		MergedAt     *time.Time `json:"mergedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" db:"merged_at"`
+		SourceUserID UserID     `json:"sourceUserId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3" db:"source_user_id"`
+		TargetUserID UserID     `json:"targetUserId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"target_user_id"`
+		RequestedBy  UserID     `json:"requestedBy" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B4" db:"requested_by"`
+		// Why the accounts are merged. For example, the support ticket.
+		Reason string `json:"reason" example:"ticket 1234: duplicate account" db:"reason"`
+	}
+	RectificationReasonCode string
+	// UserRectification is an admin edit of the personal data of an user, for its right to rectification.
+	// It's also the schema of the user rectifications topic, so that the user is notified.
+	UserRectification struct {
+		RectifiedAt *time.Time `json:"rectifiedAt" example:"2022-01-03T16:20:52.156534Z" db:"rectified_at"`
+		// The rectified fields, with their values before and after.
+		Changes     map[string]*UserRectificationChange `json:"changes" db:"changes"`
+		UserID      UserID                              `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		RectifiedBy UserID                              `json:"rectifiedBy" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3" db:"rectified_by"`
+		ReasonCode  RectificationReasonCode             `json:"reasonCode" example:"inaccurate" enums:"inaccurate,incomplete,legalOrder" db:"reason_code"`
+		// Why the data is rectified. For example, the support ticket and the evidence.
+		Justification string `json:"justification" example:"ticket 1234: last name misspelled, as per ID card" db:"justification"`
+		// Whether the user was changed since the checksum the rectification is based on, and the changes were overridden.
+		Overridden bool `json:"overridden" example:"false" db:"overridden"`
+		// The checksum of the user the rectification is based on.
+		Checksum string `json:"-" db:"-"`
+		// Whether to rectify even if the user was changed since Checksum, or without it.
+		Override bool `json:"-" db:"-"`
+	}
+	UserRectificationChange struct {
+		Before string `json:"before" example:"Do"`
+		After  string `json:"after" example:"Doe"`
+	}
+	UserReportReason string
+	UserReportStatus string
+	// UserReport is an user reporting another one for abuse. It's pending until an admin resolves all the pending reports of the reported user.
+	UserReport struct {
+		CreatedAt  *time.Time       `json:"createdAt" example:"2022-01-03T16:20:52.156534Z" db:"created_at"`
+		ResolvedAt *time.Time       `json:"resolvedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" db:"resolved_at"`
+		UserID     UserID           `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		ReportedBy UserID           `json:"reportedBy" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3" db:"reported_by"`
+		ResolvedBy UserID           `json:"resolvedBy,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B4" db:"resolved_by"`
+		Reason     UserReportReason `json:"reason" example:"spam" enums:"spam,scam,harassment,impersonation,inappropriateContent,other" db:"reason"`
+		Text       string           `json:"text,omitempty" example:"sends me referral links every day" db:"text"`
+		Status     UserReportStatus `json:"status" example:"pending" enums:"pending,actioned,dismissed" db:"status"`
+	}
+	// ReportedUser is an user in the moderation queue: it has pending reports.
+	// It's also the schema of the user reports topic, sent every time its reports change, with 0 pendingReports once they're resolved.
+	ReportedUser struct {
+		FirstReportedAt *time.Time `json:"firstReportedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" db:"first_reported_at"`
+		LastReportedAt  *time.Time `json:"lastReportedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" db:"last_reported_at"`
+		UserID          UserID     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		// The reasons of the pending reports, without duplicates.
+		Reasons []UserReportReason `json:"reasons" example:"spam,scam" db:"reasons"`
+		// Set only on the topic, when the reports are resolved.
+		Resolution     UserReportStatus `json:"resolution,omitempty" example:"actioned" enums:"actioned,dismissed" db:"-"`
+		PendingReports uint64           `json:"pendingReports" example:"3" db:"pending_reports"`
+		// Whether it has at least `userReports.flagThreshold` pending reports, so it needs a review.
+		FlaggedForReview bool `json:"flaggedForReview" example:"true" db:"flagged_for_review"`
+	}
+	ReferralInvitationStatus  string
+	ReferralInvitationContact struct {
+		// Either the email, or the phoneNumber together with its phoneNumberHash.
+		Email           string `json:"email,omitempty" example:"jdoe@gmail.com" redact:"email"`
+		PhoneNumber     string `json:"phoneNumber,omitempty" example:"+12099216581" redact:"phoneNumber"`
+		PhoneNumberHash string `json:"phoneNumberHash,omitempty" example:"Ef86A6021afCDe5673511376B2"`
+		// Whether the contact agreed to receive the invitation. Contacts without it are not invited.
+		Consent bool `json:"consent" example:"true"`
+	}
+	// AgendaContactName is the name the user has, in its agenda, for the contact with the phone number hash, stored (encrypted) only with its consent,
+	// so that the clients can render the contacts without deriving their names from the agenda again.
+	AgendaContactName struct {
+		PhoneNumberHash string `json:"phoneNumberHash" example:"Ef86A6021afCDe5673511376B2"`
+		DisplayName     string `json:"displayName" example:"Alice" redact:"name"`
+		// Whether the user agreed to store the name. The names without it are not stored and the previously stored ones are deleted.
+		Consent bool `json:"consent" example:"true"`
+	}
+	// ReferralInvitation is an invitation sent by an user to one of its contacts. The contact itself is not stored, only its hint.
+	ReferralInvitation struct {
+		CreatedAt     *time.Time `json:"createdAt" example:"2022-01-03T16:20:52.156534Z" db:"created_at"`
+		JoinedAt      *time.Time `json:"joinedAt,omitempty" example:"2022-01-03T16:20:52.156534Z" db:"joined_at"`
+		UserID        UserID     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		InviteeUserID *UserID    `json:"inviteeUserId,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B3" db:"invitee_user_id"`
+		// The masked email or phone number, for the user to recognize it.
+		ContactHint      string                    `json:"contactHint" example:"j***@gmail.com" db:"contact_hint"`
+		ContactHash      string                    `json:"-" db:"contact_hash"`
+		UnsubscribeToken string                    `json:"-" db:"unsubscribe_token"`
+		Channel          ReferralInvitationChannel `json:"channel" example:"email" enums:"email,sms" db:"channel"`
+		Status           ReferralInvitationStatus  `json:"status" example:"sent" enums:"sent,joined" db:"status"`
+	}
+	ReferralInvitationResult struct {
+		ContactHint string                    `json:"contactHint" example:"j***@gmail.com"`
+		Channel     ReferralInvitationChannel `json:"channel" example:"email" enums:"email,sms"`
+		Status      ReferralInvitationStatus  `json:"status" example:"sent" enums:"sent,alreadyInvited,unsubscribed,noConsent,quotaExceeded,failed"`
+	}
+	ReferralInvitationsResult struct {
+		// In the same order as the provided contacts.
+		Results []*ReferralInvitationResult `json:"results"`
+		// How many more invitations can be sent today (UTC).
+		RemainingDailyQuota uint64 `json:"remainingDailyQuota" example:"17"`
+	}
+	ReferralAnomalyType  string
+	ReferralRepairPolicy string
+	GlobalRankMetric     string
+	LevelCriterionName   string
+	// ReferralAnomaly is an user whose referredBy is inconsistent.
+	ReferralAnomaly struct {
+		UserID     UserID `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		ReferredBy string `json:"referredBy" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"referred_by"`
+		// Set only if it was repaired.
+		RepairedReferredBy *UserID             `json:"repairedReferredBy,omitempty" example:"icenetwork" db:"repaired_referred_by"`
+		Type               ReferralAnomalyType `json:"type" example:"selfReferral" enums:"selfReferral,cycle,dangling,username" db:"type"`
+	}
+	ReferralIntegrityReport struct {
+		CheckedAt *time.Time `json:"checkedAt" example:"2022-01-03T16:20:52.156534Z"`
+		// Set only for dry runs, which aren't audited.
+		DryRun    *DryRunPreview     `json:"dryRun,omitempty"`
+		Anomalies []*ReferralAnomaly `json:"anomalies"`
+		Repaired  uint64             `json:"repaired" example:"3"`
+	}
+	DeletionPolicy        string
+	UserIDScheme          string
+	UserDeletionBatchMode string
+	UserDeletionOutcome   string
+	// UserDeletionBatch is a list of users deleted in the background, for compliance requests, like purging the accounts of minors.
+	UserDeletionBatch struct {
+		CreatedAt   *time.Time            `json:"createdAt" example:"2022-01-03T16:20:52.156534Z" db:"created_at"`
+		FinishedAt  *time.Time            `json:"finishedAt,omitempty" example:"2022-01-03T18:20:52.156534Z" db:"finished_at"`
+		ID          string                `json:"id" example:"b0a0c1b4-9f3d-4a5e-8c41-2b8f1e0d7a11" db:"id"`
+		RequestedBy UserID                `json:"requestedBy" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"requested_by"`
+		Reason      string                `json:"reason" example:"ticket 1234: accounts of minors" db:"reason"`
+		Mode        UserDeletionBatchMode `json:"mode" example:"delete" enums:"delete,anonymize" db:"mode"`
+		UserIDs     []UserID              `json:"-" db:"-"`
+		// Set only for dry runs, which aren't stored.
+		DryRun *DryRunPreview `json:"dryRun,omitempty" db:"-"`
+	}
+	// UserDeletionBatchReport is the progress of an UserDeletionBatch. It's final once `finishedAt` is set.
+	UserDeletionBatchReport struct {
+		*UserDeletionBatch
+		Failures  []*UserDeletionFailure `json:"failures"`
+		Total     uint64                 `json:"total" example:"1000"`
+		Processed uint64                 `json:"processed" example:"900"`
+		Deleted   uint64                 `json:"deleted" example:"880"`
+		// The users that were anonymized, in the `anonymize` mode.
+		Anonymized uint64 `json:"anonymized" example:"0"`
+		// The users that were already deleted.
+		NotFound uint64 `json:"notFound" example:"15"`
+		Failed   uint64 `json:"failed" example:"5"`
+	}
+	UserDeletionFailure struct {
+		UserID UserID `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		Error  string `json:"error" example:"context deadline exceeded" db:"error"`
+	}
+	// DistributionEligibility is what decides if the user is eligible for the token distributions, as of a point in time.
+	DistributionEligibility struct {
+		AsOf    *time.Time `json:"asOf" example:"2022-01-03T16:20:52.156534Z" db:"-"`
+		UserID  UserID     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		Country string     `json:"country" example:"US" db:"country"`
+		// The first address the user verified, as `<chain>:<address>`, if any.
+		BlockchainAddress string  `json:"blockchainAddress,omitempty" example:"evm:0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed" db:"blockchain_address"`
+		KYCStepPassed     KYCStep `json:"kycStepPassed" example:"2" db:"kyc_step_passed"`
+		// If the user is blocked from the KYC or as underage.
+		Banned            bool `json:"banned" example:"false" db:"banned"`
+		CountryRestricted bool `json:"countryRestricted" example:"false" db:"-"`
+		Eligible          bool `json:"eligible" example:"true" db:"-"`
+	}
+	// DistributionEligibilitySnapshot is the DistributionEligibility of all the users created until `asOf`, exported in the background.
+	// It's complete once `completedAt` is set.
+	DistributionEligibilitySnapshot struct {
+		AsOf        *time.Time `json:"asOf" example:"2022-01-03T16:20:52.156534Z" db:"as_of"`
+		RequestedAt *time.Time `json:"requestedAt" example:"2022-01-04T16:20:52.156534Z" db:"requested_at"`
+		CompletedAt *time.Time `json:"completedAt,omitempty" example:"2022-01-04T16:30:52.156534Z" db:"completed_at"`
+		ID          string     `json:"id" example:"b0a0c1b4-9f3d-4a5e-8c41-2b8f1e0d7a11" db:"id"`
+		RequestedBy UserID     `json:"requestedBy" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"requested_by"`
+		// The hex sha256 of the CSV records of all the parts, in order, without their headers, to audit the exported files.
+		Hash string `json:"hash,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08" db:"hash"`
+		// The configured criteria, as of when the snapshot was requested.
+		RestrictedCountries []string `json:"restrictedCountries" example:"US" db:"restricted_countries"`
+		RequiredKYCStep     KYCStep  `json:"requiredKycStep" example:"2" db:"required_kyc_step"`
+		Users               uint64   `json:"users" example:"1000" db:"users"`
+		EligibleUsers       uint64   `json:"eligibleUsers" example:"800" db:"eligible_users"`
+		Parts               uint64   `json:"parts" example:"1" db:"parts"`
+	}
+	UserImportSnapshotsMode string
+	UserImportOutcome       string
+	// UserImportRecord is a line of the NDJSON files imported by UserImportBatch, for migrating the users of a legacy system.
+	UserImportRecord struct {
+		CreatedAt *time.Time `json:"createdAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		ID        UserID     `json:"id" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Email     string     `json:"email" example:"jdoe@gmail.com" redact:"email"`
+		Username  string     `json:"username,omitempty" example:"jdoe"`
+		// Optional. It has to be an existing user or one from an earlier line of the file.
+		ReferredBy UserID `json:"referredBy,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+	}
+	// UserImportBatch is a list of users created in the background, from an NDJSON file of UserImportRecord.
+	UserImportBatch struct {
+		CreatedAt   *time.Time              `json:"createdAt" example:"2022-01-03T16:20:52.156534Z" db:"created_at"`
+		FinishedAt  *time.Time              `json:"finishedAt,omitempty" example:"2022-01-03T18:20:52.156534Z" db:"finished_at"`
+		ID          string                  `json:"id" example:"b0a0c1b4-9f3d-4a5e-8c41-2b8f1e0d7a11" db:"id"`
+		RequestedBy UserID                  `json:"requestedBy" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"requested_by"`
+		Reason      string                  `json:"reason" example:"migration of the legacy users" db:"reason"`
+		Snapshots   UserImportSnapshotsMode `json:"snapshots" example:"send" enums:"send,batch,suppress" db:"snapshots"`
+		// Set only for dry runs, which aren't stored.
+		DryRun *DryRunPreview `json:"dryRun,omitempty" db:"-"`
+	}
+	// UserImportBatchReport is the progress of an UserImportBatch. It's final once `finishedAt` is set.
+	UserImportBatchReport struct {
+		*UserImportBatch
+		Failures  []*UserImportFailure `json:"failures"`
+		Total     uint64               `json:"total" example:"1000"`
+		Processed uint64               `json:"processed" example:"900"`
+		Imported  uint64               `json:"imported" example:"870"`
+		// The records whose users exist already, or that repeat the ID, email or username of an earlier line.
+		Duplicate uint64 `json:"duplicate" example:"15"`
+		Invalid   uint64 `json:"invalid" example:"10"`
+		Failed    uint64 `json:"failed" example:"5"`
+	}
+	// UserImportFailure is an invalid or failed record.
+	UserImportFailure struct {
+		UserID  *UserID           `json:"userId,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2" db:"user_id"`
+		Outcome UserImportOutcome `json:"outcome" example:"invalid" enums:"invalid,failed" db:"outcome"`
+		Error   string            `json:"error" example:"invalid email" db:"error"`
+		Line    uint64            `json:"line" example:"17" db:"line"`
+	}
+	// DryRunPreview is what a destructive admin operation would change and send, if it wasn't a dry run. See DryRunContext.
+	DryRunPreview struct {
+		mx *sync.Mutex
+		// How many records of each kind would be changed. For example, `users`, `t1Referrals` or `devices`.
+		AffectedRecords map[string]uint64 `json:"affectedRecords"`
+		// How many user snapshots of each event, or `tombstoned` messages, would be sent.
+		Events map[string]uint64 `json:"events"`
+		// The users that would be changed. At most the first 1000.
+		UserIDs []UserID `json:"userIds"`
+		// The records that would be skipped, and why. At most the first 1000.
+		Skipped []*DryRunSkip `json:"skipped,omitempty"`
+	}
+	DryRunSkip struct {
+		// The ID of the user or, for imports, the line of the file.
+		Record string `json:"record" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Reason string `json:"reason" example:"not found"`
+	}
+	AuthEventType string
+	// AuthEvent is the schema of the messages sent to the auth events topic, one per step of the login funnel.
+	AuthEvent struct {
+		CreatedAt      *time.Time    `json:"createdAt" example:"2022-01-03T16:20:52.156534Z"`
+		Type           AuthEventType `json:"type" example:"codeSent" enums:"loginRequested,codeSent,codeVerified,codeFailed,sessionBlocked,tokensRefreshed,passkeyVerified,guestSignedIn,telegramVerified,loggedOutEverywhere"`
+		UserID         UserID        `json:"userId,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Email          string        `json:"email" example:"jdoe@gmail.com" redact:"email"`
+		DeviceUniqueID string        `json:"deviceUniqueId" example:"70063ABB-E69F-4FD2-8B83-90DD372802DA"`
+		ClientIP       string        `json:"clientIp,omitempty" example:"1.1.1.1"`
 	}
 	ReferralAcquisition struct {
 		Date *time.Time `json:"date" example:"2022-01-03"`
 		T1   uint64     `json:"t1" example:"22"`
 		T2   uint64     `json:"t2" example:"13"`
 	}
+	UserReferralAcquisitionHistory struct {
+		UserID  UserID                 `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		History []*ReferralAcquisition `json:"history"`
+	}
+	// PublicStatistics are the only statistics available to anybody, for example to the marketing website.
+	PublicStatistics struct {
+		UpdatedAt      *time.Time `json:"updatedAt" example:"2022-01-03T16:20:52.156534Z" db:"-"`
+		TotalUsers     uint64     `json:"totalUsers" example:"12121212" db:"total_users"`
+		TotalCountries uint64     `json:"totalCountries" example:"180" db:"total_countries"`
+	}
+	AdminDashboard struct {
+		Date *time.Time `json:"date" example:"2022-01-03T00:00:00Z"`
+		// Signups and Deletions are the users created and deleted today, whether they ever mined or not.
+		Signups   uint64 `json:"signups" example:"1200"`
+		Deletions uint64 `json:"deletions" example:"30"`
+		// TotalUsers and ActiveUsers are as per the user growth statistics, so they count only the users that mined after the human verification.
+		TotalUsers  uint64 `json:"totalUsers" example:"12121212"`
+		ActiveUsers uint64 `json:"activeUsers" example:"3000000"`
+		// KYCSteps are today's KYC funnel, per step.
+		KYCSteps  []*KYCStepFunnel        `json:"kycSteps"`
+		Providers []*ProviderAvailability `json:"providers"`
+	}
+	ProviderAvailability struct {
+		Name      string `json:"name" example:"database/EU"`
+		Available bool   `json:"available" example:"true"`
+	}
 	CountryStatistics struct {
 		// ISO 3166 country code.
 		Country   devicemetadata.Country `json:"country" example:"US"`
 		UserCount uint64                 `json:"userCount" example:"12121212"`
+		// The position of the country among all of them, by their users. The ones with the same number of users share it.
+		Rank uint64 `json:"rank" example:"37"`
+		// The percentage of the other countries that have fewer users.
+		Percentile float64 `json:"percentile" example:"85.25"`
+		// Whether it's the country of the authenticated user.
+		Own bool `json:"own,omitempty" example:"true"`
 	}
 	UserCount struct {
 		Active uint64 `json:"active" example:"11"`
@@ -173,12 +1070,67 @@ type (
 		UserCount
 	}
 	UserGrowthStatistics struct {
-		TimeSeries []*UserCountTimeSeriesDataPoint `json:"timeSeries"`
+		// RollingActiveUsers is provided only if requested.
+		RollingActiveUsers *RollingActiveUsers             `json:"rollingActiveUsers,omitempty"`
+		TimeSeries         []*UserCountTimeSeriesDataPoint `json:"timeSeries"`
 		UserCount
 	}
+	// RollingActiveUsers are the distinct users that mined in the last 7 (WAU) and 30 (MAU) days, as opposed to the active ones
+	// of the time series, which are per aggregation interval.
+	RollingActiveUsers struct {
+		Weekly  uint64 `json:"weekly" example:"111" db:"weekly"`
+		Monthly uint64 `json:"monthly" example:"333" db:"monthly"`
+	}
+	// KYCStepFunnel counts the users that entered a KYC step (attempted it for the first time), passed it,
+	// failed an attempt of it and got blocked at it.
+	KYCStepFunnel struct {
+		Step    KYCStep `json:"step" example:"1" db:"kyc_step"`
+		Entered uint64  `json:"entered" example:"1000" db:"entered"`
+		Passed  uint64  `json:"passed" example:"800" db:"passed"`
+		Failed  uint64  `json:"failed" example:"300" db:"failed"`
+		Blocked uint64  `json:"blocked" example:"20" db:"blocked"`
+	}
+	KYCFunnelDataPoint struct {
+		Date  *time.Time       `json:"date" example:"2022-01-03T00:00:00Z"`
+		Steps []*KYCStepFunnel `json:"steps"`
+	}
+	KYCFunnelStatistics struct {
+		TimeSeries []*KYCFunnelDataPoint `json:"timeSeries"`
+		// The totals of all the days, per step.
+		Steps []*KYCStepFunnel `json:"steps"`
+	}
+	// EmailDomainSignups counts the users that signed up with an email of a domain in a day.
+	// It's anomalous if they're at least `minSignups` and `spikeFactor` times more than the daily average of the previous `baselineDays` days.
+	EmailDomainSignups struct {
+		Domain    string  `json:"domain" example:"ice.io" db:"domain"`
+		Signups   uint64  `json:"signups" example:"1200" db:"signups"`
+		Baseline  float64 `json:"baseline" example:"35.5" db:"baseline"`
+		Anomalous bool    `json:"anomalous" example:"true" db:"anomalous"`
+	}
+	EmailDomainsDataPoint struct {
+		Date    *time.Time            `json:"date" example:"2022-01-03T00:00:00Z"`
+		Domains []*EmailDomainSignups `json:"domains"`
+	}
+	EmailDomainStatistics struct {
+		TimeSeries []*EmailDomainsDataPoint `json:"timeSeries"`
+	}
+	// UserMilestone is the schema of the messages sent to the user milestones topic, exactly once per milestone the total users crossed.
+	UserMilestone struct {
+		ReachedAt  *time.Time `json:"reachedAt" example:"2022-01-03T16:20:52.156534Z"`
+		Milestone  uint64     `json:"milestone" example:"1000000"`
+		TotalUsers uint64     `json:"totalUsers" example:"1000002"`
+	}
+	// UserLevelUp is the schema of the messages sent to the user level ups topic, whenever the user reaches a higher level.
+	UserLevelUp struct {
+		ReachedAt     *time.Time `json:"reachedAt" example:"2022-01-03T16:20:52.156534Z"`
+		UserID        UserID     `json:"userId" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Level         uint64     `json:"level" example:"3"`
+		PreviousLevel uint64     `json:"previousLevel" example:"2"`
+	}
 	GlobalUnsigned struct {
-		Key   string `json:"key" example:"TOTAL_USERS_2022-01-22:16"`
-		Value uint64 `json:"value" example:"123676"`
+		UpdatedAt *time.Time `json:"updatedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		Key       string     `json:"key" example:"TOTAL_USERS_2022-01-22:16"`
+		Value     uint64     `json:"value" example:"123676"`
 	}
 	Contact struct {
 		UserID        UserID `json:"userId,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
@@ -186,24 +1138,157 @@ type (
 	}
 	ReadRepository interface {
 		GetUsers(ctx context.Context, keyword string, limit, offset uint64) ([]*MinimalUserProfile, error)
+		SearchUsers(ctx context.Context, search *UserSearch, limit, offset uint64) ([]*MinimalUserProfile, error)
 		GetUserByUsername(ctx context.Context, username string) (*UserProfile, error)
 		GetUserByPhoneNumber(ctx context.Context, phoneNumber string) (*User, error)
 		GetUserByID(ctx context.Context, userID string) (*UserProfile, error)
+		GetUserBlocks(ctx context.Context, userID UserID, limit, offset uint64) ([]*UserBlock, error)
+		GetBlockchainAddresses(ctx context.Context, userID UserID) ([]*BlockchainAddress, error)
+		// GetMutualConnections fails with ErrNotFound if any of the users doesn't exist.
+		GetMutualConnections(ctx context.Context, userID, otherUserID UserID) (*MutualConnections, error)
+		// GetDistributionEligibility fails with ErrNotFound if the user doesn't exist or was created after asOf.
+		GetDistributionEligibility(ctx context.Context, userID UserID, asOf *time.Time) (*DistributionEligibility, error)
+		GetDistributionEligibilitySnapshot(ctx context.Context, snapshotID string) (*DistributionEligibilitySnapshot, error)
+		// GetBadges returns the badges of the user, oldest first, or none if the user hid them from the requesting one.
+		// It fails with ErrNotFound if the user doesn't exist.
+		GetBadges(ctx context.Context, userID UserID) ([]*Badge, error)
+		// GetUserLevel fails with ErrNotFound if the user doesn't exist.
+		GetUserLevel(ctx context.Context, userID UserID) (*UserLevel, error)
+		// GetProfileChanges waits, up to `wait`, for the checksum of the user to differ from sinceChecksum and returns what changed.
+		// It returns nil if it didn't change in the meantime.
+		GetProfileChanges(ctx context.Context, userID UserID, sinceChecksum string, wait stdlibtime.Duration) (*ProfileChanges, error)
 
-		GetTopCountries(ctx context.Context, keyword string, limit, offset uint64) ([]*CountryStatistics, error)
-		GetUserGrowth(ctx context.Context, days uint64, tz *stdlibtime.Location) (*UserGrowthStatistics, error)
+		// GetTopCountries includes the country of the user too, if userID is provided, flagged as own, even if it's not in the requested page,
+		// in which case it's the last one. The total is the number of all the countries matching the keyword.
+		GetTopCountries(
+			ctx context.Context, keyword string, userID UserID, limit, offset uint64,
+		) (cs []*CountryStatistics, total uint64, lastUpdatedAt *time.Time, err error)
+		GetUserGrowth(ctx context.Context, days uint64, tz *stdlibtime.Location) (ugs *UserGrowthStatistics, lastUpdatedAt *time.Time, err error)
+		GetRollingActiveUsers(ctx context.Context) (*RollingActiveUsers, error)
+		GetKYCFunnel(ctx context.Context, days uint64) (kfs *KYCFunnelStatistics, lastUpdatedAt *time.Time, err error)
+		GetEmailDomainStatistics(ctx context.Context, days, limit uint64) (eds *EmailDomainStatistics, lastUpdatedAt *time.Time, err error)
+		GetGlobalValues(ctx context.Context, keyPrefix string, from, to *time.Time, limit, offset uint64) ([]*GlobalUnsigned, error)
+		GetPublicStatistics(ctx context.Context) (*PublicStatistics, error)
+		// GetAdminDashboard summarizes the current (UTC) day from the counters, for the ops dashboard.
+		GetAdminDashboard(ctx context.Context) (*AdminDashboard, error)
 
 		GetReferrals(ctx context.Context, userID string, referralType ReferralType, limit, offset uint64) (*Referrals, error)
 		GetReferralAcquisitionHistory(ctx context.Context, userID string) ([]*ReferralAcquisition, error)
+		// StreamReferralAcquisitionHistories calls fn with the acquisition history of every user with referrals, among the provided ones
+		// or, if nil, among the ones of the provided country, ordered by their IDs. It stops at the first error of fn.
+		StreamReferralAcquisitionHistories(ctx context.Context, userIDs []UserID, country string, fn func(*UserReferralAcquisitionHistory) error) error
+		GetPingableReferrals(ctx context.Context, userID UserID, limit, offset uint64) (*PingableReferrals, error)
+
+		GetDuplicateAccountCandidates(ctx context.Context, minScore, limit, offset uint64) ([]*DuplicateAccountCandidate, error)
+		GetUserDuplicateAccountCandidates(ctx context.Context, userID UserID) ([]*DuplicateAccountCandidate, error)
+
+		GetPendingCountryChanges(ctx context.Context, limit, offset uint64) ([]*CountryChange, error)
+
+		GetSquattedUsernames(ctx context.Context, limit, offset uint64) ([]*SquattedUsername, error)
+
+		// GetUnderageUsers returns the underage users, the pending deletions first, ordered by when they're due.
+		GetUnderageUsers(ctx context.Context, limit, offset uint64) ([]*UnderageUser, error)
+
+		GetReportedUsers(ctx context.Context, flaggedOnly bool, limit, offset uint64) ([]*ReportedUser, error)
+		GetUserReports(ctx context.Context, userID UserID, limit, offset uint64) ([]*UserReport, error)
+
+		GetReferralInvitations(ctx context.Context, userID UserID, limit, offset uint64) ([]*ReferralInvitation, error)
+
+		GetMaintenanceMode(ctx context.Context) (*MaintenanceMode, error)
+
+		// GetQueryAudit returns the statistics of the queries of this replica, if the query audit is enabled.
+		GetQueryAudit(ctx context.Context) (*QueryAudit, error)
+
+		// GetUserEvents returns the lifecycle events recorded in the provided time range, the latest first,
+		// optionally only the ones of the provided user and/or of the provided type.
+		GetUserEvents(ctx context.Context, userID UserID, eventType UserEventType, from, to *time.Time, limit, offset uint64) ([]*UserEvent, error)
+
+		GetUserDeletionBatch(ctx context.Context, batchID string) (*UserDeletionBatchReport, error)
+		GetUserImportBatch(ctx context.Context, batchID string) (*UserImportBatchReport, error)
 
 		IsEmailUsedBySomebodyElse(ctx context.Context, userID, email string) (bool, error)
+		// PhoneNumberIndex is the blind index stored in `users.phone_number_index`, for joining on phone numbers, or nil if they're not encrypted.
+		PhoneNumberIndex(phoneNumber string) *string
+		// EmailIndex is the blind index stored in `users.email_index`, for looking up the emails, or nil if they're not encrypted.
+		EmailIndex(email string) *string
+		// OpenSensitiveUserInformation decrypts the phone number and the email, as they're read from the users table.
+		OpenSensitiveUserInformation(info *SensitiveUserInformation)
 	}
 	WriteRepository interface {
+		// CreateUser generates the ID of the user, as per `userIds.scheme`, if it's not set.
 		CreateUser(ctx context.Context, usr *User, clientIP net.IP) error
 		DeleteUser(ctx context.Context, userID UserID) error
+		AnonymizeUser(ctx context.Context, userID UserID) error
+		// DeleteOrAnonymizeUser applies the `deletionPolicy`, unless `anonymize` is provided.
+		DeleteOrAnonymizeUser(ctx context.Context, userID UserID, anonymize *bool) error
 		ModifyUser(ctx context.Context, usr *User, profilePicture *multipart.FileHeader) error
+		GenerateProfilePictureUploadURL(ctx context.Context, userID UserID, contentType string) (*ProfilePictureUpload, error)
+		DecidePendingCountryChange(ctx context.Context, userID, adminUserID UserID, approve bool) (*CountryChange, error)
+		DecideSquattedUsername(ctx context.Context, userID, adminUserID UserID, decision SquattedUsernameDecision) (*SquattedUsername, error)
+		// MergeAccounts moves the referrals, devices, KYC state and metadata of the source user to the target one and deletes the source.
+		MergeAccounts(ctx context.Context, merge *AccountMerge) (*User, error)
+		// UpgradeGuest merges the guest account into the fully registered one of the same user, keeping the referral and the client data of the guest.
+		UpgradeGuest(ctx context.Context, guestUserID, userID UserID) (*User, error)
+		// PingReferrals sets the ping cooldown of the pinged referrals, atomically, so that they can't be pinged twice.
+		PingReferrals(ctx context.Context, userID UserID, ping *ReferralPing) (*ReferralPingResult, error)
+		// RectifyUser applies the personal data of usr as an admin, audits the changes and notifies the user.
+		RectifyUser(ctx context.Context, rectification *UserRectification, usr *User) error
+		// BlockUser blocks blockedUserID for userID. It fails with ErrDuplicate if it's already blocked.
+		BlockUser(ctx context.Context, userID, blockedUserID UserID) error
+		// UnblockUser unblocks blockedUserID for userID. It fails with ErrNotFound if it's not blocked.
+		UnblockUser(ctx context.Context, userID, blockedUserID UserID) error
+		// CreateBlockchainAddressChallenge issues a new one time challenge for the address, replacing any previous one of the user for it.
+		CreateBlockchainAddressChallenge(ctx context.Context, address *BlockchainAddress) (*BlockchainAddressChallenge, error)
+		// AddBlockchainAddress consumes the challenge of the address and, if the signature of its message by the address is valid,
+		// adds the address to the user as verified, or verifies it again if the user has it already.
+		// It fails with ErrBlockchainAddressChallengeNotFound if there's no pending challenge and with ErrBlockchainAddressUsedBySomebodyElse
+		// if another user has the address.
+		AddBlockchainAddress(ctx context.Context, address *BlockchainAddress, signature string) (*BlockchainAddress, error)
+		// RemoveBlockchainAddress fails with ErrNotFound if the user doesn't have the address.
+		RemoveBlockchainAddress(ctx context.Context, address *BlockchainAddress) error
+		// SetAgendaContactNames stores the names of the contacts with consent, replacing the previous ones, and deletes the ones without it.
+		SetAgendaContactNames(ctx context.Context, userID UserID, contacts []*AgendaContactName) error
+		// DeleteAgendaContactNames deletes the name of the contact with the phone number hash, or all of them if it's empty.
+		// It fails with ErrNotFound if there was none.
+		DeleteAgendaContactNames(ctx context.Context, userID UserID, phoneNumberHash string) error
+		// ReportUser reports an user for abuse. It fails with ErrDuplicate if the reporter has a pending report of it already.
+		ReportUser(ctx context.Context, report *UserReport) error
+		// ResolveUserReports resolves all the pending reports of the user. It fails with ErrNotFound if there are none.
+		ResolveUserReports(ctx context.Context, userID, adminUserID UserID, resolution UserReportStatus) ([]*UserReport, error)
+		// SendReferralInvitations invites the contacts to sign up with the referral code of the user, within its daily quota.
+		// It fails with ErrReferralInvitationsDisabled if they're disabled.
+		SendReferralInvitations(ctx context.Context, userID UserID, contacts []*ReferralInvitationContact) (*ReferralInvitationsResult, error)
+		// UnsubscribeFromReferralInvitations stops all the invitations to the contact of the invitation with the token.
+		// It fails with ErrNotFound if there's no such invitation.
+		UnsubscribeFromReferralInvitations(ctx context.Context, unsubscribeToken string) error
+		// RevokeDevice deletes the metadata of the device and invalidates its sessions.
+		RevokeDevice(ctx context.Context, id *DeviceID) error
+		// CheckReferralIntegrity reports the referral anomalies and, if `repair`, re-parents the users with them.
+		CheckReferralIntegrity(ctx context.Context, repair bool) (*ReferralIntegrityReport, error)
+		SendAuthEvent(ctx context.Context, event *AuthEvent) error
 
 		TryResetKYCSteps(ctx context.Context, userID string) (*User, error)
+		CheckKYCStepAttempt(ctx context.Context, userID UserID, step KYCStep) (*KYCStepAttempts, error)
+		RecordKYCStepAttempt(ctx context.Context, userID UserID, step KYCStep, successful bool) error
+		// GetKYCConfig returns the features that are unavailable or mandatory for the user, based on its country.
+		GetKYCConfig(ctx context.Context, userID UserID) (*KYCConfig, error)
+		// CheckFeatureAvailable fails with ErrFeatureRestrictedInCountry if the feature is unavailable in the country of the user.
+		CheckFeatureAvailable(ctx context.Context, userID UserID, feature CountryRestrictedFeature) error
+		// CheckFeatureSkippable fails with ErrFeatureRestrictedInCountry if the feature is mandatory in the country of the user.
+		CheckFeatureSkippable(ctx context.Context, userID UserID, feature CountryRestrictedFeature) error
+		// CheckUnderage fails with ErrUnderage if the user is blocked as underage.
+		CheckUnderage(ctx context.Context, userID UserID) error
+		PurgeKYCData(ctx context.Context, userID, requestedBy UserID) (*KYCDataPurge, error)
+
+		SetMaintenanceMode(ctx context.Context, mode *MaintenanceMode) error
+
+		CreateUserDeletionBatch(ctx context.Context, batch *UserDeletionBatch) error
+		// CreateDistributionEligibilitySnapshot only stores the snapshot; it's exported in the background, by the processor.
+		// If there's one as of the same time already, it fails with ErrDuplicate and the snapshot is set to that one.
+		CreateDistributionEligibilitySnapshot(ctx context.Context, snapshot *DistributionEligibilitySnapshot) error
+		// CreateUserImportBatch validates and deduplicates the NDJSON records and stores them, to be imported in the background.
+		// It fails with ErrInvalidUserImportFile if the file is empty, too big or not NDJSON.
+		CreateUserImportBatch(ctx context.Context, batch *UserImportBatch, records io.Reader) error
 	}
 	// Repository main API exposed that handles all the features of this package.
 	Repository interface {
@@ -221,10 +1306,24 @@ type (
 
 // Proxy Internal Types.
 type (
-	DeviceID               = device.ID
-	DeviceMetadataSnapshot = devicemetadata.DeviceMetadataSnapshot
-	DeviceMetadata         = devicemetadata.DeviceMetadata
-	DeviceLocation         = devicemetadata.DeviceLocation
+	DeviceID                = device.ID
+	DeviceMetadataSnapshot  = devicemetadata.DeviceMetadataSnapshot
+	DeviceMetadata          = devicemetadata.DeviceMetadata
+	DeviceLocation          = devicemetadata.DeviceLocation
+	EstimatedDeviceLocation = devicemetadata.EstimatedDeviceLocation
+	Device                  = devicemetadata.Device
+	ProfilePictureUpload    = picturestorage.SignedUpload
+
+	ReferralInvitationChannel = invitation.Channel
+
+	DeviceAttestation          = devicemetadata.DeviceAttestation
+	DeviceAttestationChallenge = devicemetadata.DeviceAttestationChallenge
+	DeviceAttestationPurpose   = devicemetadata.DeviceAttestationPurpose
+
+	Platform              = devicemetadata.Platform
+	AppVersionRequirement = devicemetadata.AppVersionRequirement
+
+	BlockchainChain = blockchain.Chain
 )
 
 // Private API.
@@ -234,29 +1333,118 @@ const (
 	applicationYamlKey                  = "users"
 	dayFormat, hourFormat, minuteFormat = "2006-01-02", "2006-01-02T15", "2006-01-02T15:04"
 	totalUsersGlobalKey                 = "TOTAL_USERS"
+	userMilestoneGlobalKeyPrefix        = "USERS_MILESTONE"
 	totalActiveUsersGlobalKey           = "TOTAL_ACTIVE_USERS"
+	dailySignupsGlobalKey               = "DAILY_SIGNUPS"
+	dailyDeletionsGlobalKey             = "DAILY_DELETIONS"
 	checksumCtxValueKey                 = "versioningChecksumCtxValueKey"
 	confirmedEmailCtxValueKey           = "confirmedEmailCtxValueKey"
 	authorizationCtxValueKey            = "authorizationCtxValueKey"
 	xAccountMetadataCtxValueKey         = "xAccountMetadataCtxValueKey"
+	countryChangeDecidedCtxValueKey     = "countryChangeDecidedCtxValueKey"
+	profanityOverriddenCtxValueKey      = "profanityOverriddenCtxValueKey"
+	dryRunCtxValueKey                   = "dryRunCtxValueKey"
+	dryRunMaxListed                     = 1000
+	profileChangesPollInterval          = stdlibtime.Second
+	tombstonedDryRunEvent               = UserSnapshotEvent("tombstoned")
 	totalNoOfDefaultProfilePictures     = 20
 	defaultProfilePictureName           = "default-profile-picture-%v.png"
 	defaultProfilePictureNameRegex      = "default-profile-picture-\\d+[.]png"
 	usernameDBColumnName                = "username"
+	redactionTag                        = "redact"
+	redactionEmail                      = "email"
+	redactionPhoneNumber                = "phoneNumber"
 	requestDeadline                     = 25 * stdlibtime.Second
+	userEventsCleanupInterval           = stdlibtime.Hour
+	maxUserIDGenerationAttempts         = 3
+	maxBlockchainAddressesPerUser       = 10
+	blockchainAddressChallengeMessage   = "I own the %v address %v and I'm linking it to the ice account %v. Nonce: %v"
+	blockchainAddressChallengeNonceSize = 16
+
+	distributionEligibilityExportTable = "distribution_eligibility"
 
 	maxDaysReferralsHistory = 5
 
+	referralAcquisitionHistoriesPageSize = 1000
+
+	topCountriesStatisticsCachePrefix = "top-countries"
+	userGrowthStatisticsCachePrefix   = "user-growth"
+	kycFunnelStatisticsCachePrefix    = "kyc-funnel"
+	emailDomainStatisticsCachePrefix  = "email-domains"
+	maxStatisticsCacheEntries         = 10000
+	maxBufferedProfileViews           = 100000
+	maxMutualContacts                 = 10
+	profileViewsDays                  = 30
+	weeklyActiveUsersWindow           = 7
+	monthlyActiveUsersWindow          = 30
+
+	usersPerCountryReconciliationKeyPrefix  = "USERS_PER_COUNTRY_"
+	t1ReferralsReconciliationKeyPrefix      = "T1_REFERRALS_"
+	t2ReferralsReconciliationKeyPrefix      = "T2_REFERRALS_"
+	maxReferralCountDriftsPerReconciliation = 1000
+
+	// maxUserSearchIndexCandidates bounds the users found by the search index, before GetUsers re-checks and pages them.
+	maxUserSearchIndexCandidates = 1000
+	// The T1 referrals reindexed at once when their referrer's referrer changes.
+	userSearchIndexReferralsBatchSize = 1000
+
+	// statisticsSnapshotsExportedUntilGlobalKey holds the (unix) day of the last exported statistics snapshot.
+	statisticsSnapshotsExportedUntilGlobalKey = "STATISTICS_SNAPSHOTS_EXPORTED_UNTIL"
+
+	// globalRanksComputedAtGlobalKey holds the (unix) time the global ranks were last computed at, by any replica.
+	globalRanksComputedAtGlobalKey  = "GLOBAL_RANKS_COMPUTED_AT"
+	maxStatisticsSnapshotDaysPerRun = 31
+
+	maxDuplicateAccountScore = 100
+
 	icenetwork = "icenetwork"
+
+	// maxAuditedQueries bounds the memory of the query audit, in case some query texts aren't constant.
+	maxAuditedQueries = 1000
+
+	guestUpgradeAccountMergeReason = "guest upgrade"
+
+	// The topics are used by their index, so they must be configured in the same order as in `application.yaml`.
+	requiredProducingTopics = 15
+	requiredConsumingTopics = 4
 )
 
 var (
 	//go:embed DDL.sql
 	ddl string
+	//go:embed residency_DDL.sql
+	residencyDDL string
 
 	_ sql.Scanner        = (*JSON)(nil)
 	_ sql.Scanner        = (*NotExpired)(nil)
 	_ pgtype.ArraySetter = (*Enum[HiddenProfileElement])(nil)
+
+	//nolint:gochecknoglobals // It's set once, from the config, and it's needed by the formatters, which have no access to it.
+	unredactedLogs = new(atomic.Bool)
+	//nolint:gochecknoglobals // It's per replica, and the audited queries have no access to the repository.
+	queryAudit = new(atomic.Pointer[queryAuditor])
+	//nolint:gochecknoglobals // It's just for performance.
+	queryDurationBuckets = []stdlibtime.Duration{
+		stdlibtime.Millisecond, 5 * stdlibtime.Millisecond, 10 * stdlibtime.Millisecond, 50 * stdlibtime.Millisecond, 100 * stdlibtime.Millisecond,
+		500 * stdlibtime.Millisecond, stdlibtime.Second, 5 * stdlibtime.Second,
+	}
+
+	//nolint:gochecknoglobals // They're exported process wide, via expvar, like the runtime ones.
+	countersDriftMetrics = expvar.NewMap("countersDrift")
+	//nolint:gochecknoglobals // They're exported process wide, via expvar, like the runtime ones.
+	countersReconciliationMetrics = expvar.NewMap("countersReconciliation")
+
+	//nolint:gochecknoglobals // It's just for performance.
+	compiledDefaultProfilePictureNameRegex = regexp.MustCompile(defaultProfilePictureNameRegex)
+
+	//nolint:gochecknoglobals // It's a static mapping.
+	profilePictureExtensions = map[string]string{
+		"image/jpeg": ".jpg",
+		"image/png":  ".png",
+		"image/gif":  ".gif",
+		"image/webp": ".webp",
+		"image/heic": ".heic",
+	}
 )
 
 type (
@@ -268,7 +1456,40 @@ type (
 		UserID                     string              `json:"userId,omitempty" swaggerignore:"true" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
 		Extension                  stdlibtime.Duration `json:"extension,omitempty" swaggerignore:"true" example:"24h"`
 	}
+	blockchainAddressChallenge struct {
+		CreatedAt *time.Time      `db:"created_at"`
+		UserID    UserID          `db:"user_id"`
+		Chain     BlockchainChain `db:"chain"`
+		Address   string          `db:"address"`
+		Nonce     string          `db:"nonce"`
+	}
 
+	// | pingableReferral is a PingableReferrals item, with the total of them.
+	referralAcquisitionHistory struct {
+		Date          *time.Time `db:"date"`
+		UserID        UserID     `db:"user_id"`
+		T1            int64      `db:"t1"`
+		T2            int64      `db:"t2"`
+		T1Today       int64      `db:"t1_today"`
+		T2Today       int64      `db:"t2_today"`
+		T1TodayMinus1 int64      `db:"t1_today_minus_1"`
+		T2TodayMinus1 int64      `db:"t2_today_minus_1"`
+		T1TodayMinus2 int64      `db:"t1_today_minus_2"`
+		T2TodayMinus2 int64      `db:"t2_today_minus_2"`
+		T1TodayMinus3 int64      `db:"t1_today_minus_3"`
+		T2TodayMinus3 int64      `db:"t2_today_minus_3"`
+		T1TodayMinus4 int64      `db:"t1_today_minus_4"`
+		T2TodayMinus4 int64      `db:"t2_today_minus_4"`
+	}
+	pingableReferral struct {
+		*MinimalUserProfile
+		Total uint64 `db:"total"`
+	}
+	// | profileSyncBase is the last version of the profile returned by GetProfileChanges, to diff the next one with.
+	profileSyncBase struct {
+		Profile  *JSON  `db:"profile"`
+		Checksum string `db:"checksum"`
+	}
 	userSnapshotSource struct {
 		*processor
 	}
@@ -278,6 +1499,9 @@ type (
 	userPingSource struct {
 		*processor
 	}
+	accountMergeSource struct {
+		*processor
+	}
 
 	// | repository implements the public API that this package exposes.
 	repository struct {
@@ -285,13 +1509,163 @@ type (
 		db  *storage.DB
 		mb  messagebroker.Client
 		devicemetadata.DeviceMetadataRepository
-		pictureClient  picture.Client
-		trackingClient tracking.Client
-		shutdown       func() error
+		pictureClient     picturestorage.Client
+		pictureModerator  picturemoderation.Provider
+		profanityScreener profanity.Screener
+		invitationSender  invitation.Sender
+		piiCipher         encryption.Cipher
+		residencyClusters map[Residency]*storage.DB
+		lastUserMilestone atomic.Uint64
+		trackingClient    tracking.Client
+		statisticsCache   *statisticsCache
+		shutdown          func() error
+		maintenanceMode   maintenanceModeCache
+		publicStatistics  publicStatisticsCache
+		profileViews      profileViewsBuffer
+		searchIndex       search.Index
+		// Whether the search index was backfilled, so that it can be used by GetUsers. It's loaded until it is.
+		searchIndexBackfilled atomic.Bool
 	}
 
 	processor struct {
 		*repository
+		snapshotStorage    objectstorage.Client
+		eligibilityStorage objectstorage.Client
+		// The PII values that can never be decrypted, excluded from the reencryption, so that they don't stall it.
+		undecryptablePII []string
+	}
+
+	statisticsCache struct {
+		cfg     *config
+		entries map[string]*statisticsCacheEntry
+		mx      sync.RWMutex
+	}
+	queryAuditor struct {
+		startedAt          *time.Time
+		queries            map[string]*QueryStatistics
+		slowQueryThreshold stdlibtime.Duration
+		mx                 sync.RWMutex
+		explainSlowQueries bool
+	}
+	// | maintenanceModeCache holds the maintenance mode loaded at `loadedAt`, because it's checked on every API call.
+	maintenanceModeCache struct {
+		loadedAt *time.Time
+		mode     *MaintenanceMode
+		mx       sync.RWMutex
+	}
+	publicStatisticsCache struct {
+		stats *PublicStatistics
+		mx    sync.RWMutex
+	}
+	// | profileViewsBuffer holds the unique views of this replica since the last flush, so that the hot profiles are updated once per flush.
+	profileViewsBuffer struct {
+		views map[profileView]struct{}
+		mx    sync.Mutex
+	}
+	// | profileView is a view of the profile of the user, by the viewer with the hash, in the (UTC) day.
+	profileView struct {
+		Day        string
+		UserID     UserID
+		ViewerHash int64
+	}
+	statisticsCacheEntry struct {
+		value         any
+		lastUpdatedAt *time.Time
+		version       *time.Time
+		expiresAt     *time.Time
+	}
+	counterDrift struct {
+		Key         string
+		Expected    int64
+		Actual      int64
+		Corrected   bool
+		correctable bool
+		confirmed   bool
+	}
+	countryCount struct {
+		Country   string `db:"country"`
+		UserCount int64  `db:"user_count"`
+	}
+	globalCount struct {
+		Value int64 `db:"value"`
+	}
+	referralCountDrift struct {
+		UserID     UserID `db:"user_id"`
+		ExpectedT1 int64  `db:"expected_t1"`
+		ActualT1   int64  `db:"actual_t1"`
+		ExpectedT2 int64  `db:"expected_t2"`
+		ActualT2   int64  `db:"actual_t2"`
+	}
+	duplicateAccountsGroup struct {
+		UserIDs []UserID `db:"user_ids"`
+	}
+	userDeletionBatchItem struct {
+		BatchID     string                `db:"batch_id"`
+		UserID      UserID                `db:"user_id"`
+		RequestedBy UserID                `db:"requested_by"`
+		Mode        UserDeletionBatchMode `db:"mode"`
+	}
+	userDeletionBatchCount struct {
+		Outcome *UserDeletionOutcome `db:"outcome"`
+		Count   uint64               `db:"count"`
+	}
+	userRemovalCounts struct {
+		Devices        uint64 `db:"devices"`
+		Rectifications uint64 `db:"rectifications"`
+		T1Referrals    uint64 `db:"t1_referrals"`
+	}
+	userImportBatchItem struct {
+		Record      *UserImportRecord       `db:"record"`
+		BatchID     string                  `db:"batch_id"`
+		RequestedBy UserID                  `db:"requested_by"`
+		Snapshots   UserImportSnapshotsMode `db:"snapshots"`
+		Line        uint64                  `db:"line"`
+	}
+	userImportBatchCount struct {
+		Outcome *UserImportOutcome `db:"outcome"`
+		Count   uint64             `db:"count"`
+	}
+	userAgenda struct {
+		ID                   UserID   `db:"id"`
+		Email                string   `db:"email" redact:"email"`
+		AgendaContactUserIDs []UserID `db:"agenda_contact_user_ids"`
+	}
+	levelProgress struct {
+		HiddenProfileElements *Enum[HiddenProfileElement] `db:"hidden_profile_elements"`
+		UserID                UserID                      `db:"user_id"`
+		T1Referrals           uint64                      `db:"t1_referrals"`
+		MiningSessions        uint64                      `db:"mining_sessions"`
+		Level                 uint64                      `db:"level"`
+		KYCStepPassed         KYCStep                     `db:"kyc_step_passed"`
+	}
+	searchIndexedUser struct {
+		ID                 UserID `db:"id"`
+		Username           string `db:"username"`
+		ReferredBy         UserID `db:"referred_by"`
+		ReferrerReferredBy UserID `db:"referrer_referred_by"`
+	}
+	userSearchIndexBackfill struct {
+		CompletedAt *time.Time `db:"completed_at"`
+		LastUserID  UserID     `db:"last_user_id"`
+	}
+	topCountryStatistics struct {
+		LastUpdatedAt *time.Time `db:"last_updated_at"`
+		CountryStatistics
+		Total uint64 `db:"total"`
+	}
+	topCountries struct {
+		Countries []*CountryStatistics
+		Total     uint64
+	}
+	kycFunnelStatistics struct {
+		Day           *time.Time `db:"day"`
+		LastUpdatedAt *time.Time `db:"last_updated_at"`
+		KYCStepFunnel
+	}
+	emailDomainStatistics struct {
+		Day           *time.Time `db:"day"`
+		LastUpdatedAt *time.Time `db:"last_updated_at"`
+		EmailDomainSignups
 	}
 	// | config holds the configuration of this package mounted from `application.yaml`.
 	config struct {
@@ -304,8 +1678,251 @@ type (
 			Parent                   stdlibtime.Duration `yaml:"parent"`
 			Child                    stdlibtime.Duration `yaml:"child"`
 		} `yaml:"globalAggregationInterval"`
+		CountersReconciliation struct {
+			Interval     stdlibtime.Duration `yaml:"interval"`
+			CorrectDrift bool                `yaml:"correctDrift"`
+		} `yaml:"countersReconciliation"`
+		ProfilePictureModeration struct {
+			NotifyUser bool `yaml:"notifyUser"`
+		} `yaml:"profilePictureModeration"`
+		CountryChangeVerification struct {
+			// Country changes beyond this, in the last year, must match the device geolocation, otherwise they need to be approved by an admin.
+			MaxUnverifiedChangesPerYear uint64 `yaml:"maxUnverifiedChangesPerYear"`
+			Enabled                     bool   `yaml:"enabled"`
+		} `yaml:"countryChangeVerification"`
+		DuplicateAccountsDetection struct {
+			Weights struct {
+				Device      uint64 `yaml:"device"`
+				PhoneNumber uint64 `yaml:"phoneNumber"`
+				IP          uint64 `yaml:"ip"`
+				Agenda      uint64 `yaml:"agenda"`
+				EmailDomain uint64 `yaml:"emailDomain"`
+			} `yaml:"weights"`
+			Interval     stdlibtime.Duration `yaml:"interval"`
+			IPWindow     stdlibtime.Duration `yaml:"ipWindow"`
+			MinScore     uint64              `yaml:"minScore"`
+			MaxGroupSize int                 `yaml:"maxGroupSize"`
+		} `yaml:"duplicateAccountsDetection"`
+		UserReports struct {
+			FlagThreshold uint64 `yaml:"flagThreshold"`
+		} `yaml:"userReports"`
+		// UserMilestones are the total users, in ascending order, that are announced on the user milestones topic when they're reached.
+		UserMilestones []uint64 `yaml:"userMilestones"`
+		// Badges are awarded, once, to the users that meet all the criteria of any of them, as their snapshots are processed.
+		Badges []*badgeDefinition `yaml:"badges"`
+		// Levels are the criteria of every level, in ascending order, starting with the 1st one. See UserLevel.
+		Levels              []*levelCriteria `yaml:"levels"`
+		ReferralInvitations struct {
+			DailyQuota uint64 `yaml:"dailyQuota"`
+		} `yaml:"referralInvitations"`
+		ReferralPings struct {
+			// How long the pinged referrals can't be pinged again.
+			Cooldown     stdlibtime.Duration `yaml:"cooldown"`
+			MaxBatchSize uint64              `yaml:"maxBatchSize"`
+		} `yaml:"referralPings"`
+		AgeVerification struct {
+			// MinimumAgePerCountry overrides the MinimumAge for the (ISO 3166) countries in it.
+			MinimumAgePerCountry map[string]uint64 `yaml:"minimumAgePerCountry" mapstructure:"minimumAgePerCountry"` //nolint:tagliatelle // Nope.
+			// The users younger than it, as per their dateOfBirth, are blocked. 0 disables the check.
+			MinimumAge uint64 `yaml:"minimumAge" mapstructure:"minimumAge"` //nolint:tagliatelle // Nope.
+			// How long after being blocked the underage users are deleted, for them, or their guardians, to reach out to the support.
+			DeletionDelay stdlibtime.Duration `yaml:"deletionDelay" mapstructure:"deletionDelay"` //nolint:tagliatelle // Nope.
+			// How often the underage users due for deletion are deleted. Zero disables the deletions.
+			DeletionInterval stdlibtime.Duration `yaml:"deletionInterval" mapstructure:"deletionInterval"` //nolint:tagliatelle // Nope.
+		} `yaml:"ageVerification" mapstructure:"ageVerification"` //nolint:tagliatelle // Nope.
+		UserSearchIndex struct {
+			// How often the next batch of users is indexed, until all the ones stored before the index was configured are.
+			// The users are searched in the database until then. Zero disables it.
+			BackfillInterval  stdlibtime.Duration `yaml:"backfillInterval"`
+			BackfillBatchSize uint64              `yaml:"backfillBatchSize"`
+		} `yaml:"userSearchIndex"`
+		PIIReencryption struct {
+			// How often the next batch of PII is (re-)encrypted with the primary key. Zero disables it.
+			Interval  stdlibtime.Duration `yaml:"interval"`
+			BatchSize uint64              `yaml:"batchSize"`
+		} `yaml:"piiReencryption" mapstructure:"piiReencryption"` //nolint:tagliatelle // Nope.
+		QueryAudit struct {
+			// The queries slower than it are logged, with their arguments redacted.
+			SlowQueryThreshold stdlibtime.Duration `yaml:"slowQueryThreshold"`
+			// Whether to EXPLAIN the slow queries, when they get slower than they ever were, so that the worst offenders can be analyzed.
+			ExplainSlowQueries bool `yaml:"explainSlowQueries"`
+			// Whether the durations of all the queries are recorded. It adds a little overhead to every query.
+			Enabled bool `yaml:"enabled"`
+		} `yaml:"queryAudit"`
+		DataResidency struct {
+			// Countries are the (ISO 3166) countries of the EU and US residencies. The users from any other country have the `other` one.
+			Countries map[Residency][]string `yaml:"countries"`
+			// Clusters are the application.yaml keys of the storage configs of the database clusters the rows of the users
+			// with those residencies are routed to. The ones without a cluster stay in the default one.
+			Clusters map[Residency]string `yaml:"clusters"`
+		} `yaml:"dataResidency"`
+		StatisticsSnapshots struct {
+			Storage objectstorage.Config `yaml:"storage"`
+			// How often the complete days that weren't exported yet are exported. Zero disables the export.
+			Interval stdlibtime.Duration `yaml:"interval"`
+			// The first day (YYYY-MM-DD) that's exported, if none was yet. It defaults to the previous day.
+			BackfillFrom string `yaml:"backfillFrom"`
+		} `yaml:"statisticsSnapshots"`
+		EmailDomainStatistics struct {
+			SpikeFactor  float64 `yaml:"spikeFactor"`
+			MinSignups   uint64  `yaml:"minSignups"`
+			BaselineDays uint64  `yaml:"baselineDays"`
+		} `yaml:"emailDomainStatistics"`
+		UserDeletionBatches struct {
+			// How often the pending batch items are processed. Zero disables the processing.
+			Interval stdlibtime.Duration `yaml:"interval"`
+			// Claimed items that weren't processed in this long, because the replica died, are claimed again.
+			ClaimTTL    stdlibtime.Duration `yaml:"claimTtl"`
+			ChunkSize   uint64              `yaml:"chunkSize"`
+			Concurrency uint64              `yaml:"concurrency"`
+		} `yaml:"userDeletionBatches"`
+		UserImportBatches struct {
+			// How often the pending batch items are imported. Zero disables the import.
+			Interval stdlibtime.Duration `yaml:"interval"`
+			// Claimed items that weren't imported in this long, because the replica died, are claimed again.
+			ClaimTTL    stdlibtime.Duration `yaml:"claimTtl"`
+			ChunkSize   uint64              `yaml:"chunkSize"`
+			Concurrency uint64              `yaml:"concurrency"`
+		} `yaml:"userImportBatches"`
+		UsernameSquatting struct {
+			// The usernames matching it (a POSIX regex) are the desirable ones.
+			DesirableUsernameRegex string `yaml:"desirableUsernameRegex"`
+			// How often the squatters are detected. Zero disables the detection.
+			Interval stdlibtime.Duration `yaml:"interval"`
+			// How long after their creation the dormant users are flagged.
+			DormancyPeriod stdlibtime.Duration `yaml:"dormancyPeriod"`
+			// How long after being flagged, and notified, their username is released.
+			GracePeriod stdlibtime.Duration `yaml:"gracePeriod"`
+		} `yaml:"usernameSquatting"`
+		ProfileCompleteness struct {
+			// The user earns them as they set a custom profile picture, a country and an email.
+			// The one of the kyc steps is earned proportionally to the passed steps, up to `kycStepsTarget`.
+			Weights struct {
+				ProfilePicture uint64 `yaml:"profilePicture" mapstructure:"profilePicture"` //nolint:tagliatelle // Nope.
+				Country        uint64 `yaml:"country"`
+				Email          uint64 `yaml:"email"`
+				KYCSteps       uint64 `yaml:"kycSteps" mapstructure:"kycSteps"` //nolint:tagliatelle // Nope.
+			} `yaml:"weights"`
+			// The changes are sent to the broker only when they cross one of these percentages. If empty, all of them are sent.
+			Thresholds     []uint64 `yaml:"thresholds"`
+			KYCStepsTarget KYCStep  `yaml:"kycStepsTarget" mapstructure:"kycStepsTarget"` //nolint:tagliatelle // Nope.
+		} `yaml:"profileCompleteness" mapstructure:"profileCompleteness"` //nolint:tagliatelle // Nope.
+		PublicStatistics struct {
+			CacheTTL stdlibtime.Duration `yaml:"cacheTtl"`
+		} `yaml:"publicStatistics"`
+		ReferralIntegrity struct {
+			// How often the referrals are checked. Zero disables the checks.
+			Interval stdlibtime.Duration `yaml:"interval"`
+			// `report` or `reparent`. See ReferralRepairPolicy.
+			Policy ReferralRepairPolicy `yaml:"policy"`
+			// The longest referral cycle detected. The longer, the slower the checks. Zero disables the detection of cycles.
+			MaxCycleLength uint64 `yaml:"maxCycleLength"`
+			// The users are created referred by themselves, until they set their referral, so they're anomalies only after this.
+			SelfReferralGracePeriod stdlibtime.Duration `yaml:"selfReferralGracePeriod"`
+		} `yaml:"referralIntegrity"`
+		GlobalRank struct {
+			// How often the users are ranked again. Zero disables the ranking.
+			Interval stdlibtime.Duration `yaml:"interval"`
+			// `t1Referrals` or `totalReferrals`. See GlobalRankMetric.
+			Metric GlobalRankMetric `yaml:"metric"`
+		} `yaml:"globalRank"`
+		LocationCanonicalization struct {
+			// How often the next batch of users gets its country and city canonicalized. Zero disables the backfill.
+			Interval  stdlibtime.Duration `yaml:"interval"`
+			BatchSize uint64              `yaml:"batchSize"`
+		} `yaml:"locationCanonicalization"`
+		UserEvents struct {
+			// How long the lifecycle events are kept. Zero keeps them forever.
+			Retention stdlibtime.Duration `yaml:"retention"`
+		} `yaml:"userEvents"`
+		BlockchainAddresses struct {
+			// How long the users have to sign the message of a challenge.
+			ChallengeTTL stdlibtime.Duration `yaml:"challengeTtl"`
+		} `yaml:"blockchainAddresses"`
+		DistributionEligibility struct {
+			Storage objectstorage.Config `yaml:"storage"`
+			// The users from these countries aren't eligible.
+			RestrictedCountries []string `yaml:"restrictedCountries"`
+			// The users have to have passed it to be eligible.
+			RequiredKYCStep KYCStep `yaml:"requiredKycStep" mapstructure:"requiredKycStep"` //nolint:tagliatelle // Nope.
+			// How often the pending snapshots are exported. Zero disables the export.
+			Interval stdlibtime.Duration `yaml:"interval"`
+			// Claimed snapshots that weren't exported in this long, because the replica died, are claimed again. It's the deadline of an export too.
+			ClaimTTL stdlibtime.Duration `yaml:"claimTtl"`
+			// How many users each CSV part has.
+			PartSize uint64 `yaml:"partSize"`
+		} `yaml:"distributionEligibility"`
+		ProfileViews struct {
+			// How often the views buffered by each replica are flushed. Zero disables the counting.
+			FlushInterval stdlibtime.Duration `yaml:"flushInterval"`
+		} `yaml:"profileViews"`
+		UserSnapshots struct {
+			// What the snapshots are keyed by: `userId` (the default) or `username`.
+			Key UserSnapshotKey `yaml:"key"`
+			// If set, the tenant header of every snapshot.
+			Tenant string `yaml:"tenant"`
+			// If set, it must match the partitions of the snapshots topic. Changing them reshuffles the keys, so it breaks the ordering.
+			PartitionCountHint uint64 `yaml:"partitionCountHint"`
+			// Adds the `eventType`, `schemaVersion` and `tenant` headers to every snapshot.
+			EnrichHeaders bool `yaml:"enrichHeaders"`
+		} `yaml:"userSnapshots"`
 		//nolint:tagliatelle // .
 		IntervalBetweenRepeatableKYCSteps stdlibtime.Duration `yaml:"intervalBetweenRepeatableKYCSteps" mapstructure:"intervalBetweenRepeatableKYCSteps"`
+		StatisticsCacheTTL                stdlibtime.Duration `yaml:"statisticsCacheTTL"`
 		DisableConsumer                   bool                `yaml:"disableConsumer"`
+		// UnredactedLogs dumps the PII (emails, phone numbers, etc.) in the logs as it is. It's honored only in development.
+		UnredactedLogs   bool `yaml:"unredactedLogs"`
+		KYCAttemptLimits struct {
+			FacialRecognition kycAttemptLimit `yaml:"facialRecognition" mapstructure:"facialRecognition"` //nolint:tagliatelle // Nope.
+			Quiz              kycAttemptLimit `yaml:"quiz"`
+		} `yaml:"kycAttemptLimits" mapstructure:"kycAttemptLimits"` //nolint:tagliatelle // Nope.
+
+		// MaintenanceMode is merged with the one set at runtime: if it's enabled here, it can't be disabled at runtime.
+		MaintenanceMode struct {
+			Messages  map[string]string   `yaml:"messages"`
+			Allowlist []UserID            `yaml:"allowlist"`
+			CacheTTL  stdlibtime.Duration `yaml:"cacheTtl"`
+			Enabled   bool                `yaml:"enabled"`
+		} `yaml:"maintenanceMode"`
+
+		// CountryRestrictions are, per feature, the countries (ISO 3166) where it's unavailable or mandatory.
+		CountryRestrictions struct {
+			FaceKYC   countryRestriction `yaml:"faceKyc" mapstructure:"faceKyc"`     //nolint:tagliatelle // Nope.
+			SocialKYC countryRestriction `yaml:"socialKyc" mapstructure:"socialKyc"` //nolint:tagliatelle // Nope.
+			QuizKYC   countryRestriction `yaml:"quizKyc" mapstructure:"quizKyc"`     //nolint:tagliatelle // Nope.
+		} `yaml:"countryRestrictions" mapstructure:"countryRestrictions"` //nolint:tagliatelle // Nope.
+
+		// DeletionPolicy is what deleting an user does, by default: `delete` or `anonymize`.
+		DeletionPolicy DeletionPolicy `yaml:"deletionPolicy"`
+
+		UserIDs struct {
+			// How the IDs of the users created without one are generated: `ulid` (the default) or `uuid`. They're prefixed with `ice_`.
+			Scheme UserIDScheme `yaml:"scheme"`
+		} `yaml:"userIds"`
+
+		// reloaded is the config with the latest values of the reloadable keys, once they're reloaded at runtime.
+		reloaded *atomic.Pointer[config]
+	}
+	// | kycAttemptLimit allows at most `MaxAttempts` failed attempts in any `Cooldown` window. 0 means unlimited.
+	kycAttemptLimit struct {
+		MaxAttempts uint64              `yaml:"maxAttempts" mapstructure:"maxAttempts"` //nolint:tagliatelle // Nope.
+		Cooldown    stdlibtime.Duration `yaml:"cooldown"`
+	}
+	// | badgeDefinition is a badge and its criteria. The ones that are zero aren't checked, but at least one must be set.
+	badgeDefinition struct {
+		Name          string  `yaml:"name"`
+		Description   string  `yaml:"description"`
+		T1Referrals   uint64  `yaml:"t1Referrals" mapstructure:"t1Referrals"`     //nolint:tagliatelle // Nope.
+		KYCStepPassed KYCStep `yaml:"kycStepPassed" mapstructure:"kycStepPassed"` //nolint:tagliatelle // Nope.
+	}
+	// | levelCriteria are what a level requires. The ones that are zero aren't checked, but at least one must be set.
+	levelCriteria struct {
+		T1Referrals    uint64  `yaml:"t1Referrals" mapstructure:"t1Referrals"`       //nolint:tagliatelle // Nope.
+		MiningSessions uint64  `yaml:"miningSessions" mapstructure:"miningSessions"` //nolint:tagliatelle // Nope.
+		KYCStepPassed  KYCStep `yaml:"kycStepPassed" mapstructure:"kycStepPassed"`   //nolint:tagliatelle // Nope.
+	}
+	countryRestriction struct {
+		Unavailable []string `yaml:"unavailable"`
+		Mandatory   []string `yaml:"mandatory"`
 	}
 )
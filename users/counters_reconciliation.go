@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (p *processor) startCountersReconciler(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.CountersReconciliation.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 5 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(p.reconcileCounters(reqCtx), "failed to reconcileCounters"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *processor) reconcileCounters(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	now := time.Now()
+	drifts, err := p.detectCountersDrift(ctx, now)
+	if err != nil {
+		return errors.Wrap(err, "failed to detectCountersDrift")
+	}
+	defer exportCountersDrift(drifts)
+	if len(drifts) == 0 {
+		return nil
+	}
+	if err = p.confirmCountersDrift(ctx, now, drifts); err != nil {
+		return errors.Wrap(err, "failed to confirmCountersDrift")
+	}
+	for _, drift := range drifts {
+		log.Info(fmt.Sprintf("counter drift detected for `%v`: expected %v, actual %v, drift %v, confirmed %v",
+			drift.Key, drift.Expected, drift.Actual, drift.Actual-drift.Expected, drift.confirmed))
+	}
+	if p.cfg.CountersReconciliation.CorrectDrift {
+		if err = p.correctCountersDrift(ctx, drifts); err != nil {
+			return errors.Wrapf(err, "failed to correctCountersDrift for %#v", drifts)
+		}
+	}
+
+	return errors.Wrapf(p.insertCountersReconciliationAudit(ctx, now, drifts), "failed to insertCountersReconciliationAudit for %#v", drifts)
+}
+
+//nolint:funlen // .
+func (p *processor) detectCountersDrift(ctx context.Context, now *time.Time) ([]*counterDrift, error) {
+	expectedPerCountry, err := auditedSelect[countryCount](ctx, p.db, fmt.Sprintf(`
+		SELECT country,
+			   count(1) AS user_count
+		FROM users
+		WHERE %v
+		GROUP BY country`, hadAtLeastAMiningAfterHumanVerificationSQLCondition()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count users per country from users")
+	}
+	actualPerCountry, err := auditedSelect[countryCount](ctx, p.db, `SELECT country, user_count FROM users_per_country`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select users_per_country")
+	}
+	drifts := make([]*counterDrift, 0, len(actualPerCountry))
+	counts := make(map[string]*counterDrift, len(expectedPerCountry))
+	var expectedTotal int64
+	for _, row := range expectedPerCountry {
+		expectedTotal += row.UserCount
+		counts[row.Country] = &counterDrift{Key: usersPerCountryReconciliationKey(row.Country), Expected: row.UserCount, correctable: true}
+	}
+	for _, row := range actualPerCountry {
+		if _, found := counts[row.Country]; !found {
+			counts[row.Country] = &counterDrift{Key: usersPerCountryReconciliationKey(row.Country), correctable: true}
+		}
+		counts[row.Country].Actual = row.UserCount
+	}
+	for _, drift := range counts {
+		if drift.Expected != drift.Actual {
+			drifts = append(drifts, drift)
+		}
+	}
+	actualTotal, err := auditedGet[globalCount](ctx, p.db, `SELECT value FROM global WHERE key = $1`, totalUsersGlobalKey)
+	if err != nil && !storage.IsErr(err, storage.ErrNotFound) {
+		return nil, errors.Wrapf(err, "failed to get global value for key:%v", totalUsersGlobalKey)
+	}
+	if actualTotal == nil {
+		actualTotal = new(globalCount)
+	}
+	if actualTotal.Value != expectedTotal {
+		drifts = append(drifts, &counterDrift{Key: totalUsersGlobalKey, Expected: expectedTotal, Actual: actualTotal.Value, correctable: true})
+	}
+	activeDrift, err := p.detectTotalActiveUsersDrift(ctx, now)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to detectTotalActiveUsersDrift")
+	}
+	if activeDrift != nil {
+		drifts = append(drifts, activeDrift)
+	}
+	referralCountDrifts, err := p.detectReferralCountsDrift(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to detectReferralCountsDrift")
+	}
+
+	return append(drifts, referralCountDrifts...), nil
+}
+
+// detectReferralCountsDrift compares the T1 and T2 referral counts, maintained incrementally as the referrals change
+// and read as they are by GetUserByID, with the ones counted from the users. It's the only place where they're counted that way.
+func (p *processor) detectReferralCountsDrift(ctx context.Context) ([]*counterDrift, error) {
+	sql := `WITH t1 AS (
+				SELECT referred_by AS user_id,
+					   count(1)    AS referrals
+				FROM users
+				WHERE referred_by != id
+				GROUP BY referred_by
+			), t2 AS (
+				SELECT t1.referred_by AS user_id,
+					   count(1)       AS referrals
+				FROM users t2
+					JOIN users t1
+						ON t1.id = t2.referred_by
+					   AND t1.referred_by != t1.id
+				WHERE t2.referred_by != t2.id
+				GROUP BY t1.referred_by
+			)
+			SELECT u.id                      AS user_id,
+				   COALESCE(t1.referrals, 0) AS expected_t1,
+				   COALESCE(h.t1, 0)         AS actual_t1,
+				   COALESCE(t2.referrals, 0) AS expected_t2,
+				   COALESCE(h.t2, 0)         AS actual_t2
+			FROM users u
+				LEFT JOIN t1 ON t1.user_id = u.id
+				LEFT JOIN t2 ON t2.user_id = u.id
+				LEFT JOIN referral_acquisition_history h ON h.user_id = u.id
+			WHERE COALESCE(t1.referrals, 0) != COALESCE(h.t1, 0)
+			   OR COALESCE(t2.referrals, 0) != COALESCE(h.t2, 0)
+			ORDER BY u.id
+			LIMIT $1`
+	counts, err := auditedSelect[referralCountDrift](ctx, p.db, sql, maxReferralCountDriftsPerReconciliation)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count the referrals of the users")
+	}
+	drifts := make([]*counterDrift, 0, len(counts))
+	for _, count := range counts {
+		if count.ExpectedT1 != count.ActualT1 {
+			drifts = append(drifts, &counterDrift{
+				Key: t1ReferralsReconciliationKeyPrefix + count.UserID, Expected: count.ExpectedT1, Actual: count.ActualT1, correctable: true,
+			})
+		}
+		if count.ExpectedT2 != count.ActualT2 {
+			drifts = append(drifts, &counterDrift{
+				Key: t2ReferralsReconciliationKeyPrefix + count.UserID, Expected: count.ExpectedT2, Actual: count.ActualT2, correctable: true,
+			})
+		}
+	}
+
+	return drifts, nil
+}
+
+// detectTotalActiveUsersDrift only checks the previous (already closed) child interval,
+// because the historical ones can't be recomputed (we don't keep the mining sessions history) and the current one is still changing.
+func (p *processor) detectTotalActiveUsersDrift(ctx context.Context, now *time.Time) (*counterDrift, error) {
+	end := now.Truncate(p.cfg.GlobalAggregationInterval.Child)
+	start := end.Add(-p.cfg.GlobalAggregationInterval.Child)
+	key := p.totalActiveUsersGlobalChildKey(&start)
+	expected, err := auditedGet[globalCount](ctx, p.db, `
+		SELECT count(1) AS value
+		FROM users
+		WHERE last_mining_started_at < $1
+		  AND last_mining_ended_at >= $2`, end, start)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to count active users between %v and %v", start, end)
+	}
+	actual, err := auditedGet[globalCount](ctx, p.db, `SELECT value FROM global WHERE key = $1`, key)
+	if err != nil && !storage.IsErr(err, storage.ErrNotFound) {
+		return nil, errors.Wrapf(err, "failed to get global value for key:%v", key)
+	}
+	if actual == nil {
+		actual = new(globalCount)
+	}
+	if actual.Value == expected.Value {
+		return nil, nil //nolint:nilnil // Nope.
+	}
+
+	return &counterDrift{Key: key, Expected: expected.Value, Actual: actual.Value}, nil
+}
+
+// confirmCountersDrift confirms the drifts that a previous reconciliation, of at least half an interval ago (so not the one of another replica
+// that just ran too) and at most two, found too, with the same values. The others might be just the changes that are still in flight,
+// because the users are changed before their snapshots update the counters.
+func (p *processor) confirmCountersDrift(ctx context.Context, now *time.Time, drifts []*counterDrift) error {
+	keys := make([]string, 0, len(drifts))
+	for _, drift := range drifts {
+		keys = append(keys, drift.Key)
+	}
+	sql := `SELECT DISTINCT ON (key) key, expected, actual
+			FROM counters_reconciliation_audit
+			WHERE key = ANY($1)
+			  AND reconciled_at >= $2
+			  AND reconciled_at < $3
+			ORDER BY key, reconciled_at DESC`
+	interval := p.cfg.CountersReconciliation.Interval
+	previous, err := auditedSelect[counterDrift](ctx, p.db, sql, keys, now.Add(-2*interval), now.Add(-interval/2)) //nolint:gomnd // .
+	if err != nil {
+		return errors.Wrapf(err, "failed to select the previous drifts of %v counters", len(keys))
+	}
+	previousPerKey := make(map[string]*counterDrift, len(previous))
+	for _, drift := range previous {
+		previousPerKey[drift.Key] = drift
+	}
+	for _, drift := range drifts {
+		if prev, found := previousPerKey[drift.Key]; found {
+			drift.confirmed = prev.Expected == drift.Expected && prev.Actual == drift.Actual
+		}
+	}
+
+	return nil
+}
+
+// correctCountersDrift corrects the confirmed drifts by how much they're off, only if the counters didn't change since they were read,
+// so that no concurrent update is overwritten. The ones that did are left for the next reconciliations.
+func (p *processor) correctCountersDrift(ctx context.Context, drifts []*counterDrift) error {
+	for _, drift := range drifts {
+		if !drift.correctable || !drift.confirmed {
+			continue
+		}
+		var sql string
+		params := []any{drift.Expected, drift.Actual}
+		switch {
+		case strings.HasPrefix(drift.Key, usersPerCountryReconciliationKeyPrefix):
+			sql = `INSERT INTO users_per_country (country, user_count) VALUES ($3, $1)
+				   ON CONFLICT (country) DO UPDATE
+						SET user_count = users_per_country.user_count + ($1 - $2),
+							updated_at = current_timestamp
+						WHERE users_per_country.user_count = $2`
+			params = append(params, strings.TrimPrefix(drift.Key, usersPerCountryReconciliationKeyPrefix))
+		case strings.HasPrefix(drift.Key, t1ReferralsReconciliationKeyPrefix):
+			sql = `INSERT INTO referral_acquisition_history (user_id, date, t1) VALUES ($3, current_date, $1)
+				   ON CONFLICT (user_id) DO UPDATE
+						SET t1 = referral_acquisition_history.t1 + ($1 - $2)
+						WHERE referral_acquisition_history.t1 = $2`
+			params = append(params, strings.TrimPrefix(drift.Key, t1ReferralsReconciliationKeyPrefix))
+		case strings.HasPrefix(drift.Key, t2ReferralsReconciliationKeyPrefix):
+			sql = `INSERT INTO referral_acquisition_history (user_id, date, t2) VALUES ($3, current_date, $1)
+				   ON CONFLICT (user_id) DO UPDATE
+						SET t2 = referral_acquisition_history.t2 + ($1 - $2)
+						WHERE referral_acquisition_history.t2 = $2`
+			params = append(params, strings.TrimPrefix(drift.Key, t2ReferralsReconciliationKeyPrefix))
+		default:
+			sql = `INSERT INTO global (key, value) VALUES ($3, $1)
+				   ON CONFLICT (key) DO UPDATE
+						SET value = global.value + ($1 - $2),
+							updated_at = current_timestamp
+						WHERE global.value = $2`
+			params = append(params, drift.Key)
+		}
+		updated, err := auditedExec(ctx, p.db, sql, params...)
+		if err != nil {
+			return errors.Wrapf(err, "failed to correct counter drift %#v", drift)
+		}
+		drift.Corrected = updated == 1
+	}
+
+	return nil
+}
+
+// exportCountersDrift exports, via expvar, the drift (actual - expected) of each counter confirmed by the last reconciliation,
+// with the referral counts summed up per tier, since they're per user, and how many reconciliations, drifts and corrections
+// there were since the replica started.
+func exportCountersDrift(drifts []*counterDrift) {
+	countersDriftMetrics.Init()
+	var confirmed, corrected int64
+	for _, drift := range drifts {
+		if !drift.confirmed {
+			continue
+		}
+		confirmed++
+		key := drift.Key
+		for _, prefix := range []string{t1ReferralsReconciliationKeyPrefix, t2ReferralsReconciliationKeyPrefix} {
+			if strings.HasPrefix(key, prefix) {
+				key = strings.TrimSuffix(prefix, "_")
+			}
+		}
+		countersDriftMetrics.Add(key, drift.Actual-drift.Expected)
+		if drift.Corrected {
+			corrected++
+		}
+	}
+	countersReconciliationMetrics.Add("runs", 1)
+	countersReconciliationMetrics.Add("drifts", confirmed)
+	countersReconciliationMetrics.Add("corrections", corrected)
+}
+
+func (p *processor) insertCountersReconciliationAudit(ctx context.Context, now *time.Time, drifts []*counterDrift) error {
+	values := make([]string, 0, len(drifts))
+	const fields = 4
+	params := make([]any, 0, fields*len(drifts)+1)
+	params = append(params, now.Time)
+	for ix, drift := range drifts {
+		values = append(values, fmt.Sprintf("($1, $%v, $%v, $%v, $%v)", fields*ix+2, fields*ix+3, fields*ix+4, fields*ix+5)) //nolint:gomnd // .
+		params = append(params, drift.Key, drift.Expected, drift.Actual, drift.Corrected)
+	}
+	sql := fmt.Sprintf(`INSERT INTO counters_reconciliation_audit (reconciled_at, key, expected, actual, corrected)
+						VALUES %v
+						ON CONFLICT DO NOTHING`, strings.Join(values, ","))
+	_, err := auditedExec(ctx, p.db, sql, params...)
+
+	return errors.Wrapf(err, "failed to insert counters reconciliation audit for %#v", drifts)
+}
+
+func hadAtLeastAMiningAfterHumanVerificationSQLCondition() string {
+	return fmt.Sprintf(`kyc_step_passed >= %[1]v
+		  AND last_mining_started_at IS NOT NULL
+		  AND kyc_steps_created_at[%[1]v] IS NOT NULL
+		  AND kyc_steps_last_updated_at[%[1]v] IS NOT NULL
+		  AND kyc_steps_created_at[%[1]v] < last_mining_started_at`, LivenessDetectionKYCStep)
+}
+
+func usersPerCountryReconciliationKey(country string) string {
+	return usersPerCountryReconciliationKeyPrefix + country
+}
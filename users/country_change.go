@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// checkCountryChange decides what happens with a country change: the first `maxUnverifiedChangesPerYear` ones are applied directly,
+// the next ones are applied only if they match the geolocation of the user's device, otherwise they're pending for an admin to approve them.
+func (r *repository) checkCountryChange(ctx context.Context, oldUsr, usr *User) (*CountryChange, error) {
+	change := &CountryChange{
+		CreatedAt:   usr.UpdatedAt,
+		UserID:      usr.ID,
+		FromCountry: oldUsr.Country,
+		ToCountry:   usr.Country,
+		ToCity:      usr.City,
+		Status:      AppliedCountryChangeStatus,
+	}
+	if !r.cfg.CountryChangeVerification.Enabled {
+		return change, nil
+	}
+	sql := `SELECT count(1) AS count
+			FROM country_changes
+			WHERE user_id = $1
+			  AND created_at > $2
+			  AND status = ANY($3)`
+	oneYearAgo := usr.UpdatedAt.AddDate(-1, 0, 0)
+	statuses := []CountryChangeStatus{AppliedCountryChangeStatus, VerifiedCountryChangeStatus, ApprovedCountryChangeStatus}
+	changes, err := auditedGet[struct{ Count uint64 }](ctx, r.db, sql, usr.ID, oneYearAgo, statuses)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to count country changes for userID:%v", usr.ID)
+	}
+	if changes.Count < r.cfg.CountryChangeVerification.MaxUnverifiedChangesPerYear {
+		return change, nil
+	}
+	sql = `SELECT country_short
+		   FROM device_metadata
+		   WHERE user_id = $1
+			 AND COALESCE(country_short, '') != ''
+		   ORDER BY updated_at DESC
+		   LIMIT 1`
+	geoCountries, err := auditedSelect[string](ctx, r.db, sql, usr.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get device geolocation country for userID:%v", usr.ID)
+	}
+	if len(geoCountries) != 0 && strings.EqualFold(*geoCountries[0], usr.Country) {
+		change.Status = VerifiedCountryChangeStatus
+	} else {
+		change.Status = PendingCountryChangeStatus
+	}
+
+	return change, nil
+}
+
+// recordCountryChange stores the change. A pending one replaces the previous pending one, if any,
+// while an applied one clears it, because it's not relevant anymore.
+func (r *repository) recordCountryChange(ctx context.Context, change *CountryChange) error {
+	if change.Status == PendingCountryChangeStatus {
+		sql := `INSERT INTO country_changes (created_at, user_id, from_country, to_country, to_city, status)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (user_id) WHERE status = 'pending' DO UPDATE
+					SET created_at = EXCLUDED.created_at,
+						from_country = EXCLUDED.from_country,
+						to_country = EXCLUDED.to_country,
+						to_city = EXCLUDED.to_city`
+		_, err := auditedExec(ctx, r.db, sql, change.CreatedAt.Time, change.UserID, change.FromCountry, change.ToCountry, change.ToCity, change.Status)
+
+		return errors.Wrapf(err, "failed to upsert pending country change %#v", change)
+	}
+	sql := `WITH deleted_pending AS (
+				DELETE FROM country_changes WHERE user_id = $2 AND status = 'pending'
+			)
+			INSERT INTO country_changes (created_at, user_id, from_country, to_country, to_city, status)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT DO NOTHING`
+	_, err := auditedExec(ctx, r.db, sql, change.CreatedAt.Time, change.UserID, change.FromCountry, change.ToCountry, change.ToCity, change.Status)
+
+	return errors.Wrapf(err, "failed to insert country change %#v", change)
+}
+
+func (r *repository) getPendingCountryChange(ctx context.Context, userID UserID) (*CountryChange, error) {
+	if !r.cfg.CountryChangeVerification.Enabled {
+		return nil, nil //nolint:nilnil // Nope.
+	}
+	change, err := auditedGet[CountryChange](ctx, r.db, `SELECT * FROM country_changes WHERE user_id = $1 AND status = 'pending'`, userID)
+	if err != nil && storage.IsErr(err, storage.ErrNotFound) {
+		return nil, nil //nolint:nilnil // Nope.
+	}
+
+	return change, errors.Wrapf(err, "failed to get pending country change for userID:%v", userID)
+}
+
+func (r *repository) GetPendingCountryChanges(ctx context.Context, limit, offset uint64) ([]*CountryChange, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT *
+			FROM country_changes
+			WHERE status = 'pending'
+			ORDER BY created_at
+			LIMIT $1 OFFSET $2`
+	res, err := auditedSelect[CountryChange](ctx, r.db, sql, limit, offset)
+
+	return res, errors.Wrap(err, "failed to select pending country changes")
+}
+
+func (r *repository) DecidePendingCountryChange(ctx context.Context, userID, adminUserID UserID, approve bool) (*CountryChange, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	change, err := r.getPendingCountryChange(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to getPendingCountryChange for userID:%v", userID)
+	}
+	if change == nil {
+		return nil, ErrNotFound
+	}
+	change.DecidedAt, change.DecidedBy, change.Status = time.Now(), adminUserID, RejectedCountryChangeStatus
+	if approve {
+		change.Status = ApprovedCountryChangeStatus
+		usr := new(User)
+		usr.ID, usr.Country, usr.City = userID, change.ToCountry, change.ToCity
+		if err = r.ModifyUser(context.WithValue(ctx, countryChangeDecidedCtxValueKey, true), usr, nil); err != nil { //nolint:revive,staticcheck // .
+			return nil, errors.Wrapf(err, "failed to apply the approved country change %#v", change)
+		}
+	}
+	sql := `UPDATE country_changes
+			SET status = $3,
+				decided_at = $4,
+				decided_by = $5
+			WHERE user_id = $1
+			  AND created_at = $2
+			  AND status = 'pending'`
+	if _, err = auditedExec(ctx, r.db, sql, userID, change.CreatedAt.Time, change.Status, change.DecidedAt.Time, adminUserID); err != nil {
+		return nil, errors.Wrapf(err, "failed to update country change to %#v", change)
+	}
+
+	return change, nil
+}
+
+func countryChangeDecided(ctx context.Context) bool {
+	decided, _ := ctx.Value(countryChangeDecidedCtxValueKey).(bool) //nolint:errcheck // Not needed.
+
+	return decided
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+func (r *repository) GetKYCConfig(ctx context.Context, userID UserID) (*KYCConfig, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	country, err := r.getCountry(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	kycConfig := &KYCConfig{Country: country, UnavailableFeatures: []CountryRestrictedFeature{}, MandatoryFeatures: []CountryRestrictedFeature{}}
+	for _, feature := range []CountryRestrictedFeature{FaceKYCCountryRestrictedFeature, SocialKYCCountryRestrictedFeature, QuizKYCCountryRestrictedFeature} {
+		restriction := r.cfg.live().countryRestrictions()[feature]
+		if restriction.unavailableIn(country) {
+			kycConfig.UnavailableFeatures = append(kycConfig.UnavailableFeatures, feature)
+		}
+		if restriction.mandatoryIn(country) {
+			kycConfig.MandatoryFeatures = append(kycConfig.MandatoryFeatures, feature)
+		}
+	}
+
+	return kycConfig, nil
+}
+
+func (r *repository) CheckFeatureAvailable(ctx context.Context, userID UserID, feature CountryRestrictedFeature) error {
+	restriction := r.cfg.live().countryRestrictions()[feature]
+	if len(restriction.Unavailable) == 0 {
+		return nil
+	}
+	country, err := r.getCountry(ctx, userID)
+	if err != nil || !restriction.unavailableIn(country) {
+		return err
+	}
+
+	return errors.Wrapf(ErrFeatureRestrictedInCountry, "%v is unavailable in %v, the country of userID:%v", feature, country, userID)
+}
+
+func (r *repository) CheckFeatureSkippable(ctx context.Context, userID UserID, feature CountryRestrictedFeature) error {
+	restriction := r.cfg.live().countryRestrictions()[feature]
+	if len(restriction.Mandatory) == 0 {
+		return nil
+	}
+	country, err := r.getCountry(ctx, userID)
+	if err != nil || !restriction.mandatoryIn(country) {
+		return err
+	}
+
+	return errors.Wrapf(ErrFeatureRestrictedInCountry, "%v is mandatory in %v, the country of userID:%v", feature, country, userID)
+}
+
+// getCountry returns the current country of the user. The changes that need to be verified aren't applied until they are, so it can be trusted.
+func (r *repository) getCountry(ctx context.Context, userID UserID) (string, error) {
+	usr, err := auditedGet[User](ctx, r.db, `SELECT country FROM users WHERE id = $1`, userID)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			err = ErrNotFound
+		}
+
+		return "", errors.Wrapf(err, "failed to get the country of userID:%v", userID)
+	}
+
+	return usr.Country, nil
+}
+
+func (c *config) countryRestrictions() map[CountryRestrictedFeature]*countryRestriction {
+	return map[CountryRestrictedFeature]*countryRestriction{
+		FaceKYCCountryRestrictedFeature:   &c.CountryRestrictions.FaceKYC,
+		SocialKYCCountryRestrictedFeature: &c.CountryRestrictions.SocialKYC,
+		QuizKYCCountryRestrictedFeature:   &c.CountryRestrictions.QuizKYC,
+	}
+}
+
+func (cr *countryRestriction) unavailableIn(country string) bool {
+	return containsCountry(cr.Unavailable, country)
+}
+
+func (cr *countryRestriction) mandatoryIn(country string) bool {
+	return containsCountry(cr.Mandatory, country)
+}
+
+func containsCountry(countries []string, country string) bool {
+	return country != "" && slices.ContainsFunc(countries, func(candidate string) bool { return strings.EqualFold(candidate, country) })
+}
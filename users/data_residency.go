@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+)
+
+// residencyOf is the residency of the users from the country, as per `dataResidency.countries`.
+func (c *config) residencyOf(country string) Residency {
+	for residency, countries := range c.DataResidency.Countries {
+		for _, residencyCountry := range countries {
+			if strings.EqualFold(residencyCountry, country) {
+				return residency
+			}
+		}
+	}
+
+	return OtherResidency
+}
+
+func mustConnectResidencyClusters(ctx context.Context, cfg *config) map[Residency]*storage.DB {
+	clusters := make(map[Residency]*storage.DB, len(cfg.DataResidency.Clusters))
+	for residency, clusterApplicationYAMLKey := range cfg.DataResidency.Clusters {
+		clusters[residency] = storage.MustConnect(ctx, residencyDDL, clusterApplicationYAMLKey)
+	}
+
+	return clusters
+}
+
+// dbFor routes the rows of the users with the residency to its cluster, if it has one, otherwise to the default one.
+// For now, only the dates of birth are routed. The users themselves can't be, yet, since they're referenced and queried across residencies.
+func (r *repository) dbFor(residency *Residency) *storage.DB {
+	if residency != nil {
+		if db, found := r.residencyClusters[*residency]; found {
+			return db
+		}
+	}
+
+	return r.db
+}
+
+// allDBs are the default cluster followed by the residency ones, for the jobs that go over all the routed rows.
+func (r *repository) allDBs() []*storage.DB {
+	dbs := make([]*storage.DB, 0, 1+len(r.residencyClusters))
+	dbs = append(dbs, r.db)
+	for _, db := range r.residencyClusters {
+		dbs = append(dbs, db)
+	}
+
+	return dbs
+}
+
+// deleteRoutedRows deletes the rows routed to the residency cluster of the user, since they aren't cascade deleted along with it.
+func (r *repository) deleteRoutedRows(ctx context.Context, usr *User) error {
+	db := r.dbFor(usr.Residency)
+	if db == r.db {
+		return nil
+	}
+	sql := `DELETE FROM user_dates_of_birth WHERE user_id = $1`
+	_, err := auditedExec(ctx, db, sql, usr.ID)
+
+	return errors.Wrapf(err, "failed to delete the date of birth of userID:%v from the %v cluster", usr.ID, *usr.Residency)
+}
+
+func (r *repository) closeResidencyClusters() error {
+	var mErr *multierror.Error
+	for residency, db := range r.residencyClusters {
+		mErr = multierror.Append(mErr, errors.Wrapf(db.Close(), "closing the %v cluster db connection failed", residency))
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // Not needed.
+}
@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// RevokeDevice deletes the metadata of the device, which revokes its push notification token, and its sign ins,
+// so its refresh tokens can't be used anymore. Its access tokens stay valid until they expire.
+func (r *repository) RevokeDevice(ctx context.Context, id *DeviceID) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	if err := r.DeleteDeviceMetadata(ctx, id); err != nil {
+		return errors.Wrapf(err, "failed to DeleteDeviceMetadata for %#v", id)
+	}
+	sql := `DELETE FROM device_metadata_ips WHERE user_id = $1 AND device_unique_id = $2`
+	if _, err := auditedExec(ctx, r.db, sql, id.UserID, id.DeviceUniqueID); err != nil {
+		return errors.Wrapf(err, "failed to delete the ips of device %#v", id)
+	}
+	sql = `DELETE FROM email_link_sign_ins WHERE user_id = $1 AND device_unique_id = $2`
+	_, err := auditedExec(ctx, r.db, sql, id.UserID, id.DeviceUniqueID)
+
+	return errors.Wrapf(err, "failed to delete the sign ins of device %#v", id)
+}
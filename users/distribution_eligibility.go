@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	stdlibtime "time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const distributionEligibilitySnapshotColumns = `as_of, requested_at, completed_at, id, requested_by, hash, restricted_countries, required_kyc_step,
+												users, eligible_users, parts`
+
+// distributionEligibilitySQL selects the DistributionEligibility, without the configurable criteria, of the users created until $1, ordered by id.
+// The kyc steps are the ones passed until $1 (kyc_steps_created_at is when they were first passed) and the address the first one verified until $1.
+// The kyc blocks aren't timestamped, so they're the current ones; so are the countries and the removed addresses are gone too.
+// Hence the snapshots are exported once, right after being requested, and their hash is what's audited, rather than recomputing them.
+const distributionEligibilitySQL = `
+	SELECT u.id AS user_id,
+		   u.country,
+		   (SELECT coalesce(max(step), 0)
+			FROM generate_subscripts(u.kyc_steps_created_at, 1) step
+			WHERE step <= u.kyc_step_passed
+			  AND u.kyc_steps_created_at[step] <= $1)::smallint AS kyc_step_passed,
+		   coalesce((SELECT a.chain || ':' || a.address
+					 FROM user_blockchain_addresses a
+					 WHERE a.user_id = u.id
+					   AND a.created_at <= $1
+					 ORDER BY a.created_at, a.chain, a.address
+					 LIMIT 1), '') AS blockchain_address,
+		   (u.kyc_step_blocked != 0 OR EXISTS (SELECT 1 FROM underage_users uu WHERE uu.user_id = u.id AND uu.detected_at <= $1)) AS banned
+	FROM users u
+	WHERE u.created_at <= $1`
+
+func (r *repository) GetDistributionEligibility(ctx context.Context, userID UserID, asOf *time.Time) (*DistributionEligibility, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := distributionEligibilitySQL + ` AND u.id = $2`
+	eligibility, err := auditedGet[DistributionEligibility](ctx, r.db, sql, asOf.Time, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the distribution eligibility of userID:%v as of %v", userID, asOf)
+	}
+	cfg := r.cfg.live()
+	eligibility.decide(asOf, cfg.DistributionEligibility.RequiredKYCStep, cfg.DistributionEligibility.RestrictedCountries)
+
+	return eligibility, nil
+}
+
+func (e *DistributionEligibility) decide(asOf *time.Time, requiredKYCStep KYCStep, restrictedCountries []string) {
+	e.AsOf = asOf
+	e.CountryRestricted = containsCountry(restrictedCountries, e.Country)
+	e.Eligible = e.KYCStepPassed >= requiredKYCStep && e.BlockchainAddress != "" && !e.Banned && !e.CountryRestricted
+}
+
+func (r *repository) CreateDistributionEligibilitySnapshot(ctx context.Context, snapshot *DistributionEligibilitySnapshot) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	cfg := r.cfg.live()
+	snapshot.ID = uuid.NewString()
+	snapshot.RequestedAt = time.Now()
+	snapshot.RequiredKYCStep = cfg.DistributionEligibility.RequiredKYCStep
+	snapshot.RestrictedCountries = append(make([]string, 0, len(cfg.DistributionEligibility.RestrictedCountries)), cfg.DistributionEligibility.RestrictedCountries...)
+	sql := `INSERT INTO distribution_eligibility_snapshots (as_of, requested_at, required_kyc_step, restricted_countries, id, requested_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (as_of) DO NOTHING`
+	inserted, err := auditedExec(ctx, r.db, sql,
+		snapshot.AsOf.Time, snapshot.RequestedAt.Time, snapshot.RequiredKYCStep, snapshot.RestrictedCountries, snapshot.ID, snapshot.RequestedBy)
+	if err != nil {
+		return errors.Wrapf(err, "failed to insert distribution eligibility snapshot %#v", snapshot)
+	}
+	if inserted != 0 {
+		return nil
+	}
+	sql = `SELECT ` + distributionEligibilitySnapshotColumns + ` FROM distribution_eligibility_snapshots WHERE as_of = $1`
+	existing, err := auditedGet[DistributionEligibilitySnapshot](ctx, r.db, sql, snapshot.AsOf.Time)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the distribution eligibility snapshot as of %v", snapshot.AsOf)
+	}
+	*snapshot = *existing
+
+	return errors.Wrapf(ErrDuplicate, "there's a distribution eligibility snapshot as of %v already", snapshot.AsOf)
+}
+
+func (r *repository) GetDistributionEligibilitySnapshot(ctx context.Context, snapshotID string) (*DistributionEligibilitySnapshot, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT ` + distributionEligibilitySnapshotColumns + ` FROM distribution_eligibility_snapshots WHERE id = $1`
+	snapshot, err := auditedGet[DistributionEligibilitySnapshot](ctx, r.db, sql, snapshotID)
+
+	return snapshot, errors.Wrapf(err, "failed to get distribution eligibility snapshot %v", snapshotID)
+}
+
+func (p *processor) startDistributionEligibilityExporter(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.DistributionEligibility.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reqCtx, cancel := context.WithTimeout(ctx, p.cfg.DistributionEligibility.ClaimTTL)
+			log.Error(errors.Wrap(p.exportDistributionEligibilitySnapshot(reqCtx), "failed to exportDistributionEligibilitySnapshot"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// exportDistributionEligibilitySnapshot claims the oldest pending snapshot and exports it, from scratch, as CSV parts of `partSize` users.
+// The snapshots claimed by a dead replica are claimed again after `distributionEligibility.claimTtl` and their parts are overwritten.
+func (p *processor) exportDistributionEligibilitySnapshot(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	now := time.Now()
+	sql := `UPDATE distribution_eligibility_snapshots
+			SET claimed_at = $1
+			WHERE id = (SELECT id
+						FROM distribution_eligibility_snapshots
+						WHERE completed_at IS NULL
+						  AND (claimed_at IS NULL OR claimed_at < $2)
+						ORDER BY requested_at
+						LIMIT 1
+						FOR UPDATE SKIP LOCKED)
+			RETURNING ` + distributionEligibilitySnapshotColumns
+	snapshot, err := auditedExecOne[DistributionEligibilitySnapshot](ctx, p.db, sql, now.Time, now.Add(-p.cfg.DistributionEligibility.ClaimTTL))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to claim a distribution eligibility snapshot")
+	}
+	snapshot.Users, snapshot.EligibleUsers, snapshot.Parts = 0, 0, 0
+	hasher := sha256.New()
+	for lastUserID := ""; ; snapshot.Parts++ {
+		page, pErr := p.distributionEligibilityPage(ctx, snapshot, lastUserID)
+		if pErr != nil {
+			return errors.Wrapf(pErr, "failed to get the distribution eligibility page after userID:%v for snapshot %v", lastUserID, snapshot.ID)
+		}
+		if len(page) == 0 {
+			break
+		}
+		if pErr = p.putDistributionEligibilityPart(ctx, snapshot, page, hasher); pErr != nil {
+			return errors.Wrapf(pErr, "failed to putDistributionEligibilityPart %v of snapshot %v", snapshot.Parts, snapshot.ID)
+		}
+		lastUserID = page[len(page)-1].UserID
+	}
+	snapshot.Hash = hex.EncodeToString(hasher.Sum(nil))
+	sql = `UPDATE distribution_eligibility_snapshots
+		   SET completed_at = $2, users = $3, eligible_users = $4, parts = $5, hash = $6
+		   WHERE id = $1 AND completed_at IS NULL`
+	_, err = auditedExec(ctx, p.db, sql, snapshot.ID, time.Now().Time, snapshot.Users, snapshot.EligibleUsers, snapshot.Parts, snapshot.Hash)
+
+	return errors.Wrapf(err, "failed to complete distribution eligibility snapshot %v", snapshot.ID)
+}
+
+func (p *processor) distributionEligibilityPage(
+	ctx context.Context, snapshot *DistributionEligibilitySnapshot, lastUserID UserID,
+) ([]*DistributionEligibility, error) {
+	sql := distributionEligibilitySQL + ` AND u.id > $2 ORDER BY u.id LIMIT $3`
+	page, err := auditedSelect[DistributionEligibility](ctx, p.db, sql, snapshot.AsOf.Time, lastUserID, p.cfg.DistributionEligibility.PartSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select the distribution eligibility of the users")
+	}
+	for _, eligibility := range page {
+		eligibility.decide(snapshot.AsOf, snapshot.RequiredKYCStep, snapshot.RestrictedCountries)
+	}
+
+	return page, nil
+}
+
+// putDistributionEligibilityPart uploads the part as CSV, named after the snapshot's asOf and its index, and adds its records to the hash.
+// The header isn't hashed, so that the hash doesn't depend on how the users are split into parts.
+func (p *processor) putDistributionEligibilityPart(
+	ctx context.Context, snapshot *DistributionEligibilitySnapshot, page []*DistributionEligibility, hasher hash.Hash,
+) error {
+	records := make([][]string, 0, len(page))
+	for _, eligibility := range page {
+		records = append(records, []string{
+			eligibility.UserID, eligibility.Country, strconv.Itoa(int(eligibility.KYCStepPassed)), eligibility.BlockchainAddress,
+			strconv.FormatBool(eligibility.Banned), strconv.FormatBool(eligibility.CountryRestricted), strconv.FormatBool(eligibility.Eligible),
+		})
+		snapshot.Users++
+		if eligibility.Eligible {
+			snapshot.EligibleUsers++
+		}
+	}
+	var buf bytes.Buffer
+	if err := csv.NewWriter(&buf).WriteAll(records); err != nil {
+		return errors.Wrap(err, "failed to write the csv records")
+	}
+	hasher.Write(buf.Bytes()) //nolint:errcheck // It never fails.
+	var part bytes.Buffer
+	header := []string{"user_id", "country", "kyc_step_passed", "blockchain_address", "banned", "country_restricted", "eligible"}
+	if err := csv.NewWriter(&part).WriteAll([][]string{header}); err != nil {
+		return errors.Wrap(err, "failed to write the csv header")
+	}
+	part.Write(buf.Bytes())
+	name := fmt.Sprintf("%v/as_of=%v/part-%05d.csv", distributionEligibilityExportTable, snapshot.AsOf.UTC().Format(stdlibtime.RFC3339Nano), snapshot.Parts)
+
+	return errors.Wrapf(p.eligibilityStorage.Put(ctx, name, "text/csv", part.Bytes()), "failed to put %v", name)
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DryRunContext makes the destructive admin operations run with it (DeleteUser, AnonymizeUser, CreateUserDeletionBatch,
+// CreateUserImportBatch and the repairs of CheckReferralIntegrity) do all their validations, but record what they'd change
+// in the returned preview, instead of committing it.
+func DryRunContext(ctx context.Context) (context.Context, *DryRunPreview) {
+	preview := &DryRunPreview{mx: new(sync.Mutex), AffectedRecords: make(map[string]uint64), Events: make(map[string]uint64), UserIDs: []UserID{}}
+
+	return context.WithValue(ctx, dryRunCtxValueKey, preview), preview //nolint:revive,staticcheck // .
+}
+
+func dryRun(ctx context.Context) *DryRunPreview {
+	preview, _ := ctx.Value(dryRunCtxValueKey).(*DryRunPreview) //nolint:errcheck // Not needed.
+
+	return preview
+}
+
+func (p *DryRunPreview) affect(userID UserID, records map[string]uint64, events ...UserSnapshotEvent) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	if len(p.UserIDs) < dryRunMaxListed {
+		p.UserIDs = append(p.UserIDs, userID)
+	}
+	for kind, count := range records {
+		if count != 0 {
+			p.AffectedRecords[kind] += count
+		}
+	}
+	for _, event := range events {
+		p.Events[string(event)]++
+	}
+}
+
+func (p *DryRunPreview) skip(record, reason string) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	if len(p.Skipped) < dryRunMaxListed {
+		p.Skipped = append(p.Skipped, &DryRunSkip{Record: record, Reason: reason})
+	}
+}
+
+// previewUserRemoval records what DeleteUser or AnonymizeUser would change for the user; only the deletion re-parents its referrals.
+func (r *repository) previewUserRemoval(ctx context.Context, preview *DryRunPreview, usr *User, event UserSnapshotEvent) error {
+	sql := `SELECT (SELECT count(1) FROM device_metadata WHERE user_id = $1) 	  AS devices,
+				   (SELECT count(1) FROM user_rectifications WHERE user_id = $1) AS rectifications,
+				   (SELECT count(1)
+					FROM users
+					WHERE referred_by = $1
+					  AND id != $1
+					  AND id != 'bogus'
+					  AND id != 'icenetwork') 								  AS t1_referrals`
+	counts, err := auditedGet[userRemovalCounts](ctx, r.db, sql, usr.ID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to count the records of userID:%v", usr.ID)
+	}
+	records := map[string]uint64{"users": 1, "devices": counts.Devices}
+	if hasKYCData(usr) {
+		records["kycData"] = 1
+	}
+	if event == DeletedUserSnapshotEvent {
+		records["t1Referrals"] = counts.T1Referrals
+		preview.affect(usr.ID, records, event, tombstonedDryRunEvent)
+	} else {
+		records["userRectifications"] = counts.Rectifications
+		preview.affect(usr.ID, records, event)
+	}
+
+	return nil
+}
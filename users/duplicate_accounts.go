@@ -0,0 +1,288 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetDuplicateAccountCandidates(ctx context.Context, minScore, limit, offset uint64) ([]*DuplicateAccountCandidate, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT *
+			FROM duplicate_account_candidates
+			WHERE score >= $1
+			ORDER BY score DESC, updated_at DESC
+			LIMIT $2 OFFSET $3`
+	res, err := auditedSelect[DuplicateAccountCandidate](ctx, r.db, sql, minScore, limit, offset)
+
+	return res, errors.Wrapf(err, "failed to select duplicate account candidates for minScore:%v", minScore)
+}
+
+func (r *repository) GetUserDuplicateAccountCandidates(ctx context.Context, userID UserID) ([]*DuplicateAccountCandidate, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT *
+			FROM duplicate_account_candidates
+			WHERE user_id = $1 OR duplicate_user_id = $1
+			ORDER BY score DESC`
+	res, err := auditedSelect[DuplicateAccountCandidate](ctx, r.db, sql, userID)
+
+	return res, errors.Wrapf(err, "failed to select duplicate account candidates for userID:%v", userID)
+}
+
+func (p *processor) startDuplicateAccountsDetector(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.DuplicateAccountsDetection.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 10 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(p.detectDuplicateAccounts(reqCtx), "failed to detectDuplicateAccounts"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// detectDuplicateAccounts pairs the accounts sharing a device, a phone number (formatted differently) or an IP
+// and scores them, adding up the weights of the signals found, of how much their agendas overlap
+// and of whether they share an email domain with anomalous signups.
+// The agenda overlap and the email domain are only checked for the pairs found via the other signals, because checking all pairs is too expensive.
+func (p *processor) detectDuplicateAccounts(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	now := time.Now()
+	ipWindowStart := now.Add(-p.cfg.DuplicateAccountsDetection.IPWindow)
+	if _, err := auditedExec(ctx, p.db, `DELETE FROM device_metadata_ips WHERE last_seen_at < $1`, ipWindowStart); err != nil {
+		return errors.Wrap(err, "failed to delete old device_metadata_ips")
+	}
+	signalQueries := map[DuplicateAccountSignal]string{
+		DeviceDuplicateAccountSignal: `
+			SELECT array_agg(DISTINCT user_id) AS user_ids
+			FROM device_metadata
+			WHERE device_unique_id != ''
+			GROUP BY device_unique_id
+			HAVING count(DISTINCT user_id) BETWEEN 2 AND $1`,
+		PhoneNumberDuplicateAccountSignal: `
+			SELECT array_agg(id) AS user_ids
+			FROM users
+			WHERE phone_number != id
+			  AND phone_number != ''
+			GROUP BY COALESCE(phone_number_index, regexp_replace(phone_number, '[^0-9]', '', 'g'))
+			HAVING count(1) BETWEEN 2 AND $1`,
+		IPDuplicateAccountSignal: `
+			SELECT array_agg(DISTINCT user_id) AS user_ids
+			FROM device_metadata_ips
+			GROUP BY ip
+			HAVING count(DISTINCT user_id) BETWEEN 2 AND $1`,
+	}
+	signals := make(map[[2]UserID]map[DuplicateAccountSignal]struct{})
+	for signal, sql := range signalQueries {
+		groups, err := auditedSelect[duplicateAccountsGroup](ctx, p.db, sql, p.cfg.DuplicateAccountsDetection.MaxGroupSize)
+		if err != nil {
+			return errors.Wrapf(err, "failed to select duplicate accounts groups for signal:%v", signal)
+		}
+		for _, group := range groups {
+			addDuplicateAccountsPairs(signals, group.UserIDs, signal)
+		}
+	}
+	if len(signals) == 0 {
+		return nil
+	}
+	candidates, err := p.scoreDuplicateAccountCandidates(ctx, now, signals)
+	if err != nil {
+		return errors.Wrap(err, "failed to scoreDuplicateAccountCandidates")
+	}
+	changed, err := p.upsertDuplicateAccountCandidates(ctx, candidates)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsertDuplicateAccountCandidates")
+	}
+	for _, candidate := range changed {
+		if err = p.sendDuplicateAccountCandidateMessage(ctx, candidate); err != nil {
+			return errors.Wrapf(err, "failed to sendDuplicateAccountCandidateMessage for %#v", candidate)
+		}
+	}
+
+	return nil
+}
+
+func addDuplicateAccountsPairs(signals map[[2]UserID]map[DuplicateAccountSignal]struct{}, userIDs []UserID, signal DuplicateAccountSignal) {
+	sort.Strings(userIDs)
+	for i := range userIDs {
+		for j := i + 1; j < len(userIDs); j++ {
+			pair := [2]UserID{userIDs[i], userIDs[j]}
+			if _, found := signals[pair]; !found {
+				signals[pair] = make(map[DuplicateAccountSignal]struct{}, 1)
+			}
+			signals[pair][signal] = struct{}{}
+		}
+	}
+}
+
+func (p *processor) scoreDuplicateAccountCandidates(
+	ctx context.Context, now *time.Time, signals map[[2]UserID]map[DuplicateAccountSignal]struct{},
+) ([]*DuplicateAccountCandidate, error) {
+	userIDs := make(map[UserID]struct{}, len(signals))
+	for pair := range signals {
+		userIDs[pair[0]], userIDs[pair[1]] = struct{}{}, struct{}{}
+	}
+	ids := make([]UserID, 0, len(userIDs))
+	for id := range userIDs {
+		ids = append(ids, id)
+	}
+	sql := `SELECT id, email, COALESCE(agenda_contact_user_ids, '{}') AS agenda_contact_user_ids FROM users WHERE id = ANY($1)`
+	agendas, err := auditedSelect[userAgenda](ctx, p.db, sql, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select users agendas")
+	}
+	agendaPerUser := make(map[UserID][]UserID, len(agendas))
+	emailDomainPerUser := make(map[UserID]string, len(agendas))
+	domains := make([]string, 0, len(agendas))
+	for _, agenda := range agendas {
+		agendaPerUser[agenda.ID] = agenda.AgendaContactUserIDs
+		if domain := emailDomain(agenda.ID, p.open("email", agenda.Email)); domain != "" {
+			emailDomainPerUser[agenda.ID] = domain
+			domains = append(domains, domain)
+		}
+	}
+	anomalousDomains, err := p.anomalousEmailDomains(ctx, domains)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get anomalousEmailDomains")
+	}
+	weights := p.cfg.DuplicateAccountsDetection.Weights
+	signalWeights := map[DuplicateAccountSignal]uint64{
+		DeviceDuplicateAccountSignal:      weights.Device,
+		PhoneNumberDuplicateAccountSignal: weights.PhoneNumber,
+		IPDuplicateAccountSignal:          weights.IP,
+	}
+	candidates := make([]*DuplicateAccountCandidate, 0, len(signals))
+	for pair, pairSignals := range signals {
+		candidate := &DuplicateAccountCandidate{
+			DetectedAt:      now,
+			UpdatedAt:       now,
+			UserID:          pair[0],
+			DuplicateUserID: pair[1],
+			Signals:         make([]string, 0, len(pairSignals)+2), //nolint:gomnd // Agenda and email domain.
+			AgendaOverlap:   agendaOverlap(agendaPerUser[pair[0]], agendaPerUser[pair[1]]),
+		}
+		for signal := range pairSignals {
+			candidate.Signals = append(candidate.Signals, string(signal))
+			candidate.Score += signalWeights[signal]
+		}
+		if candidate.AgendaOverlap > 0 {
+			candidate.Signals = append(candidate.Signals, string(AgendaDuplicateAccountSignal))
+			candidate.Score += uint64(candidate.AgendaOverlap * float64(weights.Agenda))
+		}
+		if domain := emailDomainPerUser[pair[0]]; domain != "" && domain == emailDomainPerUser[pair[1]] {
+			if _, anomalous := anomalousDomains[domain]; anomalous {
+				candidate.Signals = append(candidate.Signals, string(EmailDomainDuplicateAccountSignal))
+				candidate.Score += weights.EmailDomain
+			}
+		}
+		if candidate.Score > maxDuplicateAccountScore {
+			candidate.Score = maxDuplicateAccountScore
+		}
+		if candidate.Score < p.cfg.DuplicateAccountsDetection.MinScore {
+			continue
+		}
+		sort.Strings(candidate.Signals)
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// agendaOverlap is the Jaccard index of the two agendas.
+func agendaOverlap(agenda1, agenda2 []UserID) float64 {
+	if len(agenda1) == 0 || len(agenda2) == 0 {
+		return 0
+	}
+	contacts := make(map[UserID]struct{}, len(agenda1))
+	for _, contact := range agenda1 {
+		contacts[contact] = struct{}{}
+	}
+	var common int
+	union := len(contacts)
+	for _, contact := range agenda2 {
+		if _, found := contacts[contact]; found {
+			common++
+		} else {
+			union++
+		}
+	}
+
+	return float64(common) / float64(union)
+}
+
+// upsertDuplicateAccountCandidates returns only the candidates that are new or that changed.
+func (p *processor) upsertDuplicateAccountCandidates(ctx context.Context, candidates []*DuplicateAccountCandidate) ([]*DuplicateAccountCandidate, error) {
+	const batchSize, fields = 1000, 7
+	changed := make([]*DuplicateAccountCandidate, 0, len(candidates))
+	for start := 0; start < len(candidates); start += batchSize {
+		end := start + batchSize
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+		values := make([]string, 0, end-start)
+		params := make([]any, 0, fields*(end-start))
+		for ix, candidate := range candidates[start:end] {
+			values = append(values, fmt.Sprintf("($%v, $%v, $%v, $%v, $%v, $%v, $%v)", //nolint:gomnd // .
+				fields*ix+1, fields*ix+2, fields*ix+3, fields*ix+4, fields*ix+5, fields*ix+6, fields*ix+7))
+			params = append(params, candidate.DetectedAt.Time, candidate.UpdatedAt.Time, candidate.AgendaOverlap, candidate.Score,
+				candidate.Signals, candidate.UserID, candidate.DuplicateUserID)
+		}
+		sql := fmt.Sprintf(`INSERT INTO duplicate_account_candidates (detected_at, updated_at, agenda_overlap, score, signals, user_id, duplicate_user_id)
+							VALUES %v
+							ON CONFLICT (user_id, duplicate_user_id) DO UPDATE
+								SET updated_at = EXCLUDED.updated_at,
+									agenda_overlap = EXCLUDED.agenda_overlap,
+									score = EXCLUDED.score,
+									signals = EXCLUDED.signals
+								WHERE duplicate_account_candidates.score != EXCLUDED.score
+								   OR duplicate_account_candidates.signals != EXCLUDED.signals
+							RETURNING *`, strings.Join(values, ","))
+		res, err := auditedExecMany[DuplicateAccountCandidate](ctx, p.db, sql, params...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to upsert duplicate account candidates [%v:%v]", start, end)
+		}
+		changed = append(changed, res...)
+	}
+
+	return changed, nil
+}
+
+func (r *repository) sendDuplicateAccountCandidateMessage(ctx context.Context, candidate *DuplicateAccountCandidate) error {
+	valueBytes, err := json.MarshalContext(ctx, candidate)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", candidate)
+	}
+	msg := &messagebroker.Message{
+		Headers: map[string]string{"producer": "eskimo"},
+		Key:     candidate.UserID + "~" + candidate.DuplicateUserID,
+		Topic:   r.cfg.MessageBroker.Topics[6].Name,
+		Value:   valueBytes,
+	}
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+
+	return errors.Wrapf(<-responder, "failed to send duplicate account candidate message to broker")
+}
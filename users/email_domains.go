@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// GetEmailDomainStatistics returns, for each of the last `days` (UTC) days, the current one first,
+// the `limit` email domains with the most signups, flagging the ones with anomalous signups.
+func (r *repository) GetEmailDomainStatistics(ctx context.Context, days, limit uint64) (eds *EmailDomainStatistics, lastUpdatedAt *time.Time, err error) {
+	if ctx.Err() != nil {
+		return nil, nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	today := time.Now().Truncate(hoursInOneDay * stdlibtime.Hour)
+	from := today.Add(-stdlibtime.Duration(days-1) * hoursInOneDay * stdlibtime.Hour)
+	version, err := r.statisticsVersion(ctx, `SELECT max(updated_at) AS version FROM email_domain_signups WHERE day >= $1`, from)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get the version of the email domain statistics")
+	}
+	cacheKey := emailDomainStatisticsCacheKey(days, limit)
+	if cached, cachedLastUpdatedAt, found := r.statisticsCache.get(cacheKey, version); found {
+		return cached.(*EmailDomainStatistics), cachedLastUpdatedAt, nil //nolint:forcetypeassert // We know for sure.
+	}
+	rows, err := r.selectEmailDomainSignups(ctx, from, limit, nil)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to selectEmailDomainSignups since %v", from)
+	}
+	eds = &EmailDomainStatistics{TimeSeries: make([]*EmailDomainsDataPoint, 0, days)}
+	for day := uint64(0); day < days; day++ {
+		date := time.New(today.Add(-stdlibtime.Duration(day) * hoursInOneDay * stdlibtime.Hour))
+		dataPoint := &EmailDomainsDataPoint{Date: date, Domains: make([]*EmailDomainSignups, 0)}
+		for _, row := range rows {
+			if row.Day.Equal(*date.Time) {
+				dataPoint.Domains = append(dataPoint.Domains, &row.EmailDomainSignups)
+			}
+		}
+		eds.TimeSeries = append(eds.TimeSeries, dataPoint)
+	}
+	if len(rows) != 0 {
+		lastUpdatedAt = rows[0].LastUpdatedAt
+	}
+	r.statisticsCache.set(cacheKey, eds, lastUpdatedAt, version)
+
+	return eds, lastUpdatedAt, nil
+}
+
+// selectEmailDomainSignups returns the `limit` domains with the most signups of each day since `from`, most recent days first,
+// alongside their baseline, i.e. their daily average of signups in the previous `baselineDays` days. If `domains` are provided, only those.
+func (r *repository) selectEmailDomainSignups(
+	ctx context.Context, from stdlibtime.Time, limit uint64, domains []string,
+) ([]*emailDomainStatistics, error) {
+	sql := `SELECT d.day,
+				   d.domain,
+				   d.signups,
+				   (SELECT COALESCE(sum(b.signups), 0)
+					FROM email_domain_signups b
+					WHERE b.domain = d.domain
+					  AND b.day >= d.day - $3::int
+					  AND b.day < d.day)::double precision / $3::int AS baseline,
+				   max(d.updated_at) OVER () AS last_updated_at
+			FROM (SELECT *, row_number() OVER (PARTITION BY day ORDER BY signups DESC, domain) AS rank
+				  FROM email_domain_signups
+				  WHERE day >= $1
+					AND (cardinality($4::text[]) = 0 OR domain = ANY($4::text[]))) d
+			WHERE d.rank <= $2
+			ORDER BY d.day DESC, d.signups DESC, d.domain`
+	if domains == nil {
+		domains = []string{}
+	}
+	rows, err := auditedSelect[emailDomainStatistics](ctx, r.db, sql, from, limit, r.cfg.EmailDomainStatistics.BaselineDays, domains)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select email domain signups")
+	}
+	for _, row := range rows {
+		row.Anomalous = row.Signups >= r.cfg.EmailDomainStatistics.MinSignups &&
+			float64(row.Signups) > r.cfg.EmailDomainStatistics.SpikeFactor*row.Baseline
+	}
+
+	return rows, nil
+}
+
+// recordEmailDomainSignup counts the user in the signups of its email domain of the current day, if it just got its first email.
+func (r *repository) recordEmailDomainSignup(ctx context.Context, before, after *User) error {
+	domain := emailDomain(after.ID, after.Email)
+	if domain == "" || (before != nil && emailDomain(before.ID, before.Email) != "") {
+		return nil
+	}
+	now := time.Now()
+	sql := `INSERT INTO email_domain_signups (day, updated_at, domain, signups)
+			VALUES ($1, $2, $3, 1)
+			ON CONFLICT (day, domain) DO UPDATE
+				SET signups = email_domain_signups.signups + 1,
+					updated_at = EXCLUDED.updated_at`
+	if _, err := auditedExec(ctx, r.db, sql, now.Truncate(hoursInOneDay*stdlibtime.Hour), now.Time, domain); err != nil {
+		return errors.Wrapf(err, "failed to record email domain signup of %v for userID:%v", domain, after.ID)
+	}
+
+	return nil
+}
+
+// anomalousEmailDomains returns which of the `domains` had anomalous signups in the last `baselineDays` days.
+func (r *repository) anomalousEmailDomains(ctx context.Context, domains []string) (map[string]struct{}, error) {
+	anomalous := make(map[string]struct{})
+	if len(domains) == 0 {
+		return anomalous, nil
+	}
+	from := time.Now().Truncate(hoursInOneDay * stdlibtime.Hour).
+		Add(-stdlibtime.Duration(r.cfg.EmailDomainStatistics.BaselineDays) * hoursInOneDay * stdlibtime.Hour)
+	rows, err := r.selectEmailDomainSignups(ctx, from, uint64(len(domains)), domains)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to selectEmailDomainSignups for %#v", domains)
+	}
+	for _, row := range rows {
+		if row.Anomalous {
+			anomalous[row.Domain] = struct{}{}
+		}
+	}
+
+	return anomalous, nil
+}
+
+// emailDomain returns the domain of the email of the user, lowercased, or nothing if it has none (new users have their ID as a placeholder).
+func emailDomain(userID UserID, email string) string {
+	if email == "" || email == userID {
+		return ""
+	}
+	ix := strings.LastIndexByte(email, '@')
+	if ix < 0 {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(email[ix+1:]))
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (p *processor) startGlobalRanker(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.GlobalRank.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 10 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(p.rankUsers(reqCtx), "failed to rankUsers"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// rankUsers ranks, once per `globalRank.interval` across all replicas, the users with referrals, by `globalRank.metric`.
+// Only the ranks that changed are written and the users without referrals anymore lose theirs.
+func (p *processor) rankUsers(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	now := time.Now()
+	sql := `INSERT INTO global (key, value) VALUES ($1, $2)
+			ON CONFLICT (key) DO UPDATE
+				SET value = EXCLUDED.value,
+					updated_at = current_timestamp
+				WHERE global.value <= $3`
+	claimed, err := auditedExec(ctx, p.db, sql, globalRanksComputedAtGlobalKey, now.Unix(), now.Add(-p.cfg.GlobalRank.Interval).Unix())
+	if err != nil || claimed == 0 {
+		return errors.Wrapf(err, "failed to claim %v", globalRanksComputedAtGlobalKey)
+	}
+	metric := "refs.t1"
+	if p.cfg.GlobalRank.Metric == TotalReferralsGlobalRankMetric {
+		metric = "refs.t1 + refs.t2"
+	}
+	sql = `WITH ranked AS (
+				SELECT refs.user_id,
+					   rank() OVER (ORDER BY ` + metric + ` DESC) AS rank
+				FROM referral_acquisition_history refs
+					JOIN users u ON u.id = refs.user_id
+				WHERE ` + metric + ` > 0
+		   ), upserted AS (
+				INSERT INTO global_ranks (user_id, rank)
+				SELECT user_id, rank
+				FROM ranked
+				ON CONFLICT (user_id) DO UPDATE
+					SET rank = EXCLUDED.rank
+					WHERE global_ranks.rank != EXCLUDED.rank
+		   )
+		   DELETE FROM global_ranks gr
+		   WHERE NOT EXISTS (SELECT 1 FROM ranked WHERE ranked.user_id = gr.user_id)`
+	_, err = auditedExec(ctx, p.db, sql)
+
+	return errors.Wrapf(err, "failed to rank the users by %v", metric)
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// GetGlobalValues returns the values whose keys start with the prefix and cover a period (the date suffix of the aggregation keys, e.g. `_2022-01-22T16`,
+// which covers that hour) that overlaps with the time range, alongside the ones without a period, like TOTAL_USERS, regardless of it.
+func (r *repository) GetGlobalValues(ctx context.Context, keyPrefix string, from, to *time.Time, limit, offset uint64) ([]*GlobalUnsigned, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT key, value, updated_at
+			FROM (SELECT *,
+						 (CASE length(period)
+							  WHEN 13 THEN replace(period, 'T', ' ') || ':00'
+							  ELSE replace(period, 'T', ' ')
+						  END)::timestamp AS period_start,
+						 (CASE length(period)
+							  WHEN 10 THEN interval '1 day'
+							  WHEN 13 THEN interval '1 hour'
+							  ELSE interval '1 minute'
+						  END) AS period_length
+				  FROM (SELECT *,
+							   substring(key FROM '_(\d{4}-\d{2}-\d{2}(?:T\d{2}(?::\d{2})?)?)$') AS period
+						FROM global
+						WHERE starts_with(key, $1)) g) g
+			WHERE period IS NULL
+			   OR (period_start <= $3 AND period_start + period_length > $2)
+			ORDER BY key
+			LIMIT $4 OFFSET $5`
+	vals, err := auditedSelect[GlobalUnsigned](ctx, r.db, sql, keyPrefix, from.Time, to.Time, limit, offset)
+
+	return vals, errors.Wrapf(err, "failed to select global vals for keyPrefix:%v, from:%v, to:%v", keyPrefix, from, to)
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IsGuest tells if the user is a guest, i.e. a provisional account, without an email or a phone number, that can be upgraded later on.
+func IsGuest(userID UserID) bool {
+	return strings.HasPrefix(userID, GuestUserIDPrefix)
+}
+
+// UpgradeGuest is an account merge requested by the user itself, once it signed in with an email on the device of the guest.
+func (r *repository) UpgradeGuest(ctx context.Context, guestUserID, userID UserID) (*User, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	if !IsGuest(guestUserID) || IsGuest(userID) {
+		return nil, errors.Wrapf(ErrInvalidAccountMerge, "`%v` must be a guest and `%v` a registered user", guestUserID, userID)
+	}
+	merged, err := r.MergeAccounts(ctx, &AccountMerge{
+		SourceUserID: guestUserID,
+		TargetUserID: userID,
+		RequestedBy:  userID,
+		Reason:       guestUpgradeAccountMergeReason,
+	})
+
+	return merged, errors.Wrapf(err, "failed to merge guest userID:%v into userID:%v", guestUserID, userID)
+}
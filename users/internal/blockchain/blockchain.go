@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package blockchain
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ValidateAddress validates the address for the chain and returns its canonical form, the one to be stored and compared.
+func ValidateAddress(chain Chain, address string) (string, error) {
+	switch chain {
+	case EVMChain:
+		return validateEVMAddress(address)
+	case SolanaChain:
+		return validateSolanaAddress(address)
+	default:
+		return "", errors.Wrapf(ErrUnsupportedChain, "chain %v", chain)
+	}
+}
+
+// VerifySignature verifies that the message was signed by the owner of the address, the way the wallets of the chain sign messages:
+// EIP-191 `personal_sign` for the EVM chains and plain ed25519 for Solana. The address must be validated already.
+func VerifySignature(chain Chain, address, message, signature string) error {
+	switch chain {
+	case EVMChain:
+		return verifyEVMSignature(address, message, signature)
+	case SolanaChain:
+		return verifySolanaSignature(address, message, signature)
+	default:
+		return errors.Wrapf(ErrUnsupportedChain, "chain %v", chain)
+	}
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package blockchain
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAddress(t *testing.T) {
+	t.Parallel()
+	for _, checksummed := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		for _, address := range []string{checksummed, strings.ToLower(checksummed), evmAddressPrefix + strings.ToUpper(checksummed[2:])} {
+			actual, err := ValidateAddress(EVMChain, address)
+			require.NoError(t, err, address)
+			assert.Equal(t, checksummed, actual)
+		}
+	}
+	for _, address := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+		"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAedAA",
+		"0xZaAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	} {
+		_, err := ValidateAddress(EVMChain, address)
+		require.ErrorIs(t, err, ErrInvalidAddress, address)
+	}
+
+	for _, address := range []string{"11111111111111111111111111111111", "4Nd1mBQtrMJVYVfKf2PJy9NZUZdTAsp7D4xWLs4gDB4T"} {
+		actual, err := ValidateAddress(SolanaChain, address)
+		require.NoError(t, err, address)
+		assert.Equal(t, address, actual)
+	}
+	for _, address := range []string{
+		"",
+		"1111111111111111111111111111111",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"4Nd1mBQtrMJVYVfKf2PJy9NZUZdTAsp7D4xWLs4gDB4l",
+	} {
+		_, err := ValidateAddress(SolanaChain, address)
+		require.ErrorIs(t, err, ErrInvalidAddress, address)
+	}
+
+	_, err := ValidateAddress("bogus", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	require.ErrorIs(t, err, ErrUnsupportedChain)
+}
+
+func TestVerifySignatureEVM(t *testing.T) {
+	t.Parallel()
+	privateKey, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	address := checksumEVMAddress(keccak256(privateKey.PubKey().SerializeUncompressed()[1:])[32-evmAddressLength:])
+	const message = "some message"
+
+	signature := signEVM(privateKey, message)
+	require.NoError(t, VerifySignature(EVMChain, address, message, signature))
+	legacy, err := hex.DecodeString(signature[2:])
+	require.NoError(t, err)
+	legacy[evmSignatureBytes-1] += evmRecoveryIDOffset
+	require.NoError(t, VerifySignature(EVMChain, address, message, hex.EncodeToString(legacy)))
+
+	require.ErrorIs(t, VerifySignature(EVMChain, address, "another message", signature), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(EVMChain, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", message, signature), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(EVMChain, address, message, signature[:len(signature)-2]), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(EVMChain, address, message, signature[:len(signature)-2]+"05"), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(EVMChain, address, message, "bogus"), ErrInvalidSignature)
+}
+
+func TestVerifySignatureSolana(t *testing.T) {
+	t.Parallel()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	address := encodeBase58(publicKey)
+	const message = "some message"
+	signature := encodeBase58(ed25519.Sign(privateKey, []byte(message)))
+
+	require.NoError(t, VerifySignature(SolanaChain, address, message, signature))
+	require.ErrorIs(t, VerifySignature(SolanaChain, address, "another message", signature), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(SolanaChain, "11111111111111111111111111111111", message, signature), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(SolanaChain, address, message, signature[1:]), ErrInvalidSignature)
+	require.ErrorIs(t, VerifySignature(SolanaChain, address, message, "0x00"), ErrInvalidSignature)
+}
+
+func TestDecodeBase58(t *testing.T) {
+	t.Parallel()
+	for _, raw := range [][]byte{{0}, {0, 0, 1}, {0xFF, 0xFF}, []byte("hello world")} {
+		decoded, err := decodeBase58(encodeBase58(raw))
+		require.NoError(t, err)
+		assert.Equal(t, raw, decoded)
+	}
+	decoded, err := decodeBase58("StV1DL6CwTryKyV")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello world"), decoded)
+	_, err = decodeBase58("0")
+	require.Error(t, err)
+	_, err = decodeBase58("")
+	require.Error(t, err)
+}
+
+func signEVM(privateKey *secp256k1.PrivateKey, message string) string {
+	compact := ecdsa.SignCompact(privateKey, keccak256([]byte(evmSignedMessagePrefix+strconv.Itoa(len(message))+message)), false)
+
+	return evmAddressPrefix + hex.EncodeToString(append(compact[1:], compact[0]-evmRecoveryIDOffset))
+}
+
+func encodeBase58(raw []byte) string {
+	value, base, digit := new(big.Int).SetBytes(raw), big.NewInt(int64(len(base58Alphabet))), new(big.Int)
+	var encoded []byte
+	for value.Sign() > 0 {
+		value.DivMod(value, base, digit)
+		encoded = append([]byte{base58Alphabet[digit.Int64()]}, encoded...)
+	}
+	for _, b := range raw {
+		if b != 0 {
+			break
+		}
+		encoded = append([]byte{base58Alphabet[0]}, encoded...)
+	}
+
+	return string(encoded)
+}
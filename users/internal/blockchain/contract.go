@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package blockchain
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Public API.
+
+const (
+	// EVMChain covers all the EVM compatible chains: they share the address format and the signatures.
+	EVMChain    Chain = "evm"
+	SolanaChain Chain = "solana"
+)
+
+var (
+	ErrUnsupportedChain = errors.New("unsupported chain")
+	ErrInvalidAddress   = errors.New("invalid address")
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	//nolint:gochecknoglobals // It's just for more descriptive validation messages.
+	Chains = []Chain{EVMChain, SolanaChain}
+)
+
+type (
+	Chain string
+)
+
+// Private API.
+
+const (
+	evmAddressPrefix  = "0x"
+	evmAddressLength  = 20
+	evmSignatureBytes = 65
+	// evmSignedMessagePrefix is prepended to the messages signed by the wallets (EIP-191), so that they can't be valid transactions.
+	evmSignedMessagePrefix = "\x19Ethereum Signed Message:\n"
+	// evmRecoveryIDOffset is the offset of the legacy `v` of the signatures, instead of 0 or 1.
+	evmRecoveryIDOffset = 27
+
+	solanaAddressLength = 32
+
+	// base58Alphabet is the bitcoin one, without 0, O, I and l.
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
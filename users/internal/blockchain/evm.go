@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package blockchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+)
+
+// validateEVMAddress accepts the all lower or all upper case addresses as they are and the mixed case ones only if their EIP-55 checksum matches.
+func validateEVMAddress(address string) (string, error) {
+	if !strings.HasPrefix(address, evmAddressPrefix) {
+		return "", errors.Wrapf(ErrInvalidAddress, "evm address %v doesn't start with %v", address, evmAddressPrefix)
+	}
+	raw, err := hex.DecodeString(address[len(evmAddressPrefix):])
+	if err != nil || len(raw) != evmAddressLength {
+		return "", errors.Wrapf(ErrInvalidAddress, "evm address %v isn't %v hex bytes", address, evmAddressLength)
+	}
+	digits := address[len(evmAddressPrefix):]
+	checksummed := checksumEVMAddress(raw)
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && address != checksummed {
+		return "", errors.Wrapf(ErrInvalidAddress, "evm address %v has an invalid checksum", address)
+	}
+
+	return checksummed, nil
+}
+
+// checksumEVMAddress encodes the address as per EIP-55: the letters are upper case if the matching nibble of the keccak256 of the lower case hex is >= 8.
+func checksumEVMAddress(raw []byte) string {
+	digits := []byte(hex.EncodeToString(raw))
+	hash := keccak256(digits)
+	for ix, digit := range digits {
+		nibble := hash[ix/2] >> 4 //nolint:gomnd // The high nibble.
+		if ix%2 == 1 {
+			nibble = hash[ix/2] & 0x0f //nolint:gomnd // The low nibble.
+		}
+		if digit >= 'a' && nibble >= 8 {
+			digits[ix] = digit - 'a' + 'A'
+		}
+	}
+
+	return evmAddressPrefix + string(digits)
+}
+
+// verifyEVMSignature recovers the public key from the 65 bytes `r || s || v` signature and compares its address with the provided one.
+func verifyEVMSignature(address, message, signature string) error {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, evmAddressPrefix))
+	if err != nil || len(sig) != evmSignatureBytes {
+		return errors.Wrapf(ErrInvalidSignature, "evm signature isn't %v hex bytes", evmSignatureBytes)
+	}
+	recoveryID := sig[evmSignatureBytes-1]
+	if recoveryID >= evmRecoveryIDOffset {
+		recoveryID -= evmRecoveryIDOffset
+	}
+	if recoveryID > 1 {
+		return errors.Wrapf(ErrInvalidSignature, "invalid evm signature recovery id %v", sig[evmSignatureBytes-1])
+	}
+	compact := append([]byte{evmRecoveryIDOffset + recoveryID}, sig[:evmSignatureBytes-1]...)
+	hash := keccak256([]byte(evmSignedMessagePrefix + strconv.Itoa(len(message)) + message))
+	publicKey, _, err := ecdsa.RecoverCompact(compact, hash)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidSignature, "failed to recover the evm public key: %v", err)
+	}
+	raw, err := hex.DecodeString(address[len(evmAddressPrefix):])
+	if err != nil {
+		return errors.Wrapf(err, "invalid evm address %v", address)
+	}
+	if signer := keccak256(publicKey.SerializeUncompressed()[1:])[32-evmAddressLength:]; !bytes.Equal(signer, raw) {
+		return errors.Wrapf(ErrInvalidSignature, "evm message signed by %v, not by %v", checksumEVMAddress(signer), address)
+	}
+
+	return nil
+}
+
+func keccak256(data []byte) []byte {
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write(data) //nolint:errcheck // It never fails.
+
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package blockchain
+
+import (
+	"crypto/ed25519"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// validateSolanaAddress accepts the base58 encoded ed25519 public keys. The encoding is canonical already, so they're returned as they are.
+func validateSolanaAddress(address string) (string, error) {
+	if raw, err := decodeBase58(address); err != nil || len(raw) != solanaAddressLength {
+		return "", errors.Wrapf(ErrInvalidAddress, "solana address %v isn't %v base58 bytes", address, solanaAddressLength)
+	}
+
+	return address, nil
+}
+
+// verifySolanaSignature verifies the base58 encoded ed25519 signature of the message, the address being the public key.
+func verifySolanaSignature(address, message, signature string) error {
+	publicKey, err := decodeBase58(address)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return errors.Wrapf(ErrInvalidAddress, "solana address %v isn't %v base58 bytes", address, ed25519.PublicKeySize)
+	}
+	sig, err := decodeBase58(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return errors.Wrapf(ErrInvalidSignature, "solana signature isn't %v base58 bytes", ed25519.SignatureSize)
+	}
+	if !ed25519.Verify(publicKey, []byte(message), sig) {
+		return errors.Wrapf(ErrInvalidSignature, "solana message not signed by %v", address)
+	}
+
+	return nil
+}
+
+// decodeBase58 decodes the bitcoin flavour of base58, where each leading `1` is a leading zero byte.
+func decodeBase58(encoded string) ([]byte, error) {
+	if encoded == "" {
+		return nil, errors.New("empty base58")
+	}
+	value, base := new(big.Int), big.NewInt(int64(len(base58Alphabet)))
+	for _, char := range encoded {
+		digit := strings.IndexRune(base58Alphabet, char)
+		if digit < 0 {
+			return nil, errors.Errorf("invalid base58 character %q", char)
+		}
+		value.Mul(value, base).Add(value, big.NewInt(int64(digit)))
+	}
+	leadingZeros := len(encoded) - len(strings.TrimLeft(encoded, base58Alphabet[:1]))
+
+	return append(make([]byte, leadingZeros), value.Bytes()...), nil
+}
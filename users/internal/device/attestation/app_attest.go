@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package deviceattestation
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/ugorji/go/codec"
+
+	"github.com/ice-blockchain/wintr/log"
+)
+
+func newAppAttest(cfg *config) *appAttest {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(appAttestRootCA)) {
+		log.Panic(errors.New("failed to load the App Attest root CA"))
+	}
+
+	return &appAttest{
+		roots:       roots,
+		appID:       cfg.DeviceAttestation.AppAttest.AppID,
+		development: cfg.DeviceAttestation.AppAttest.Development,
+	}
+}
+
+func (a *appAttest) Verify(ctx context.Context, r *Request) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	rawObject, err := base64.StdEncoding.DecodeString(r.Token)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAttestation, "invalid attestation object encoding: %v", err)
+	}
+	keyID, err := base64.StdEncoding.DecodeString(r.KeyID)
+	if err != nil || len(keyID) == 0 {
+		return errors.Wrapf(ErrInvalidAttestation, "invalid key id `%v`", r.KeyID)
+	}
+	var obj appAttestObject
+	if err = codec.NewDecoderBytes(rawObject, new(codec.CborHandle)).Decode(&obj); err != nil {
+		return errors.Wrapf(ErrInvalidAttestation, "invalid attestation object: %v", err)
+	}
+	if obj.Fmt != appAttestFormat || len(obj.AttStmt.X5C) == 0 {
+		return errors.Wrapf(ErrInvalidAttestation, "unexpected attestation format `%v`", obj.Fmt)
+	}
+	credCert, err := a.verifyCertificates(obj.AttStmt.X5C)
+	if err != nil {
+		return err
+	}
+	clientDataHash := sha256.Sum256([]byte(r.Challenge))
+	nonce := sha256.Sum256(append(slices.Clone(obj.AuthData), clientDataHash[:]...))
+	if err = verifyAppAttestNonce(credCert, nonce[:]); err != nil {
+		return err
+	}
+	if err = verifyAppAttestKeyID(credCert, keyID); err != nil {
+		return err
+	}
+
+	return a.verifyAuthData(obj.AuthData, keyID)
+}
+
+func (a *appAttest) verifyCertificates(x5c [][]byte) (*x509.Certificate, error) {
+	certs := make([]*x509.Certificate, 0, len(x5c))
+	for _, raw := range x5c {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidAttestation, "invalid certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	opts := x509.VerifyOptions{Roots: a.roots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}
+	if _, err := certs[0].Verify(opts); err != nil {
+		return nil, errors.Wrapf(ErrInvalidAttestation, "untrusted certificate chain: %v", err)
+	}
+
+	return certs[0], nil
+}
+
+func verifyAppAttestNonce(credCert *x509.Certificate, nonce []byte) error {
+	for _, ext := range credCert.Extensions {
+		if !ext.Id.Equal(appAttestNonceExtensionOID) {
+			continue
+		}
+		var nonceExt appAttestNonceExtension
+		if _, err := asn1.Unmarshal(ext.Value, &nonceExt); err != nil {
+			return errors.Wrapf(ErrInvalidAttestation, "invalid nonce extension: %v", err)
+		}
+		if !bytes.Equal(nonceExt.Nonce, nonce) {
+			return errors.Wrap(ErrInvalidAttestation, "the attestation is not bound to the challenge")
+		}
+
+		return nil
+	}
+
+	return errors.Wrap(ErrInvalidAttestation, "nonce extension not found")
+}
+
+func verifyAppAttestKeyID(credCert *x509.Certificate, keyID []byte) error {
+	publicKey, ok := credCert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return errors.Wrapf(ErrInvalidAttestation, "unexpected public key type %T", credCert.PublicKey)
+	}
+	ecdhPublicKey, err := publicKey.ECDH()
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAttestation, "invalid public key: %v", err)
+	}
+	if publicKeyHash := sha256.Sum256(ecdhPublicKey.Bytes()); !bytes.Equal(publicKeyHash[:], keyID) {
+		return errors.Wrap(ErrInvalidAttestation, "the key id doesn't match the attested key")
+	}
+
+	return nil
+}
+
+// verifyAuthData checks the authenticator data: rpIdHash(32) | flags(1) | counter(4) | aaguid(16) | credentialIdLength(2) | credentialId | ...
+func (a *appAttest) verifyAuthData(authData, keyID []byte) error {
+	const (
+		rpIDHashEnd = 32
+		counterFrom = rpIDHashEnd + 1
+		aaguidFrom  = counterFrom + 4
+		credIDFrom  = aaguidFrom + 16 + 2
+	)
+	if len(authData) < credIDFrom {
+		return errors.Wrap(ErrInvalidAttestation, "authenticator data too short")
+	}
+	if appIDHash := sha256.Sum256([]byte(a.appID)); !bytes.Equal(authData[:rpIDHashEnd], appIDHash[:]) {
+		return errors.Wrap(ErrInvalidAttestation, "the attestation is for another app")
+	}
+	if counter := binary.BigEndian.Uint32(authData[counterFrom:aaguidFrom]); counter != 0 {
+		return errors.Wrapf(ErrInvalidAttestation, "unexpected counter %v", counter)
+	}
+	expectedAAGUID := appAttestProductionAAGUID
+	if a.development {
+		expectedAAGUID = appAttestDevelopmentAAGUID
+	}
+	if aaguid := string(authData[aaguidFrom : credIDFrom-2]); aaguid != expectedAAGUID {
+		return errors.Wrapf(ErrInvalidAttestation, "unexpected environment %q", aaguid)
+	}
+	credIDTo := credIDFrom + int(binary.BigEndian.Uint16(authData[credIDFrom-2:credIDFrom]))
+	if len(authData) < credIDTo || !bytes.Equal(authData[credIDFrom:credIDTo], keyID) {
+		return errors.Wrap(ErrInvalidAttestation, "the credential id doesn't match the key id")
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package deviceattestation
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// New returns a verifier dispatching to the configured providers, or nil if none is configured.
+func New(applicationYAMLKey string) Verifier {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+	if len(cfg.DeviceAttestation.Providers) == 0 {
+		return nil
+	}
+	log.Panic(cfg.validate(applicationYAMLKey)) //nolint:revive // That's intended.
+	v := &verifier{providers: make(map[ProviderType]Verifier, len(cfg.DeviceAttestation.Providers))}
+	for _, provider := range cfg.DeviceAttestation.Providers {
+		switch provider {
+		case StubProvider:
+			v.providers[provider] = &stub{validToken: cfg.DeviceAttestation.Stub.ValidToken}
+		case PlayIntegrityProvider:
+			v.providers[provider] = newPlayIntegrity(applicationYAMLKey, &cfg)
+		case AppAttestProvider:
+			v.providers[provider] = newAppAttest(&cfg)
+		}
+	}
+
+	return v
+}
+
+// ValidateConfig reports, at once, all the problems of the config of the providers that New would use.
+func ValidateConfig(applicationYAMLKey string) error {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+
+	return cfg.validate(applicationYAMLKey)
+}
+
+func (c *config) validate(applicationYAMLKey string) error {
+	var mErr *multierror.Error
+	for _, provider := range c.DeviceAttestation.Providers {
+		switch provider {
+		case StubProvider:
+			if c.DeviceAttestation.Stub.ValidToken == "" {
+				mErr = multierror.Append(mErr, errors.Errorf("`%v.deviceAttestation.stub.validToken` is missing", applicationYAMLKey))
+			}
+		case PlayIntegrityProvider:
+			if c.DeviceAttestation.PlayIntegrity.PackageName == "" {
+				mErr = multierror.Append(mErr, errors.Errorf("`%v.deviceAttestation.playIntegrity.packageName` is missing", applicationYAMLKey))
+			}
+			if _, err := playIntegrityCredentials(applicationYAMLKey, c); err != nil {
+				mErr = multierror.Append(mErr, err)
+			}
+		case AppAttestProvider:
+			if c.DeviceAttestation.AppAttest.AppID == "" {
+				mErr = multierror.Append(mErr, errors.Errorf("`%v.deviceAttestation.appAttest.appId` is missing", applicationYAMLKey))
+			}
+		default:
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.deviceAttestation.providers` has the unsupported provider `%v`", applicationYAMLKey, provider))
+		}
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+}
+
+func (v *verifier) Verify(ctx context.Context, req *Request) error {
+	provider, found := v.providers[req.Provider]
+	if !found {
+		return errors.Wrapf(ErrUnsupportedProvider, "provider `%v` is not enabled", req.Provider)
+	}
+
+	return provider.Verify(ctx, req) //nolint:wrapcheck // No need, we're just dispatching.
+}
+
+func (s *stub) Verify(ctx context.Context, r *Request) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	if r.Token != s.validToken {
+		return errors.Wrap(ErrInvalidAttestation, "stub rejected token")
+	}
+
+	return nil
+}
+
+func loadFromEnv(applicationYAMLKey, env string) string {
+	module := strings.ToUpper(strings.ReplaceAll(strings.ReplaceAll(applicationYAMLKey, "-", "_"), "/", "_"))
+	if val := os.Getenv(module + "_" + env); val != "" {
+		return val
+	}
+
+	return os.Getenv(env)
+}
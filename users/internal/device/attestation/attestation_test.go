@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package deviceattestation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"math/big"
+	"testing"
+	stdlibtime "time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/api/playintegrity/v1"
+)
+
+func TestStubVerify(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	v := &verifier{providers: map[ProviderType]Verifier{StubProvider: &stub{validToken: "valid"}}}
+
+	require.NoError(t, v.Verify(ctx, &Request{Provider: StubProvider, Token: "valid", Challenge: "c"}))
+	require.ErrorIs(t, v.Verify(ctx, &Request{Provider: StubProvider, Token: "bogus", Challenge: "c"}), ErrInvalidAttestation)
+	require.ErrorIs(t, v.Verify(ctx, &Request{Provider: AppAttestProvider, Token: "valid", Challenge: "c"}), ErrUnsupportedProvider)
+}
+
+func TestPlayIntegrityCheckVerdict(t *testing.T) {
+	t.Parallel()
+	p := &playIntegrity{packageName: "io.ice.app"}
+	payload := func() *playintegrity.TokenPayloadExternal {
+		return &playintegrity.TokenPayloadExternal{
+			RequestDetails:  &playintegrity.RequestDetails{RequestPackageName: "io.ice.app", Nonce: "challenge"},
+			AppIntegrity:    &playintegrity.AppIntegrity{AppRecognitionVerdict: playIntegrityRecognizedApp},
+			DeviceIntegrity: &playintegrity.DeviceIntegrity{DeviceRecognitionVerdict: []string{playIntegrityMeetsDeviceIntegrity}},
+		}
+	}
+
+	require.NoError(t, p.checkVerdict(payload(), "challenge"))
+	require.ErrorIs(t, p.checkVerdict(payload(), "another challenge"), ErrInvalidAttestation)
+	require.ErrorIs(t, p.checkVerdict(&playintegrity.TokenPayloadExternal{}, "challenge"), ErrInvalidAttestation)
+	wrongPackage := payload()
+	wrongPackage.RequestDetails.RequestPackageName = "io.ice.fake"
+	require.ErrorIs(t, p.checkVerdict(wrongPackage, "challenge"), ErrInvalidAttestation)
+	unrecognized := payload()
+	unrecognized.AppIntegrity.AppRecognitionVerdict = "UNRECOGNIZED_VERSION"
+	require.ErrorIs(t, p.checkVerdict(unrecognized, "challenge"), ErrInvalidAttestation)
+	p.requireStrongIntegrity = true
+	require.ErrorIs(t, p.checkVerdict(payload(), "challenge"), ErrInvalidAttestation)
+	strong := payload()
+	strong.DeviceIntegrity.DeviceRecognitionVerdict = append(strong.DeviceIntegrity.DeviceRecognitionVerdict, playIntegrityMeetsStrongIntegrity)
+	require.NoError(t, p.checkVerdict(strong, "challenge"))
+}
+
+func TestAppAttestVerify(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	const appID = "ABCDE12345.io.ice.app"
+	roots, issue := testAppAttestCA(t)
+	a := &appAttest{roots: roots, appID: appID}
+
+	token, keyID := issue("challenge", appID, appAttestProductionAAGUID)
+	require.NoError(t, a.Verify(ctx, &Request{Provider: AppAttestProvider, Challenge: "challenge", Token: token, KeyID: keyID}))
+	require.ErrorIs(t, a.Verify(ctx, &Request{Provider: AppAttestProvider, Challenge: "another challenge", Token: token, KeyID: keyID}), ErrInvalidAttestation)
+	otherToken, otherKeyID := issue("challenge", appID, appAttestProductionAAGUID)
+	require.ErrorIs(t, a.Verify(ctx, &Request{Provider: AppAttestProvider, Challenge: "challenge", Token: token, KeyID: otherKeyID}), ErrInvalidAttestation)
+	require.ErrorIs(t, a.Verify(ctx, &Request{Provider: AppAttestProvider, Challenge: "challenge", Token: otherToken, KeyID: keyID}), ErrInvalidAttestation)
+	token, keyID = issue("challenge", "ABCDE12345.io.ice.fake", appAttestProductionAAGUID)
+	require.ErrorIs(t, a.Verify(ctx, &Request{Provider: AppAttestProvider, Challenge: "challenge", Token: token, KeyID: keyID}), ErrInvalidAttestation)
+	token, keyID = issue("challenge", appID, appAttestDevelopmentAAGUID)
+	require.ErrorIs(t, a.Verify(ctx, &Request{Provider: AppAttestProvider, Challenge: "challenge", Token: token, KeyID: keyID}), ErrInvalidAttestation)
+	a.development = true
+	require.NoError(t, a.Verify(ctx, &Request{Provider: AppAttestProvider, Challenge: "challenge", Token: token, KeyID: keyID}))
+
+	untrusted := &appAttest{roots: x509.NewCertPool(), appID: appID, development: true}
+	require.ErrorIs(t, untrusted.Verify(ctx, &Request{Provider: AppAttestProvider, Challenge: "challenge", Token: token, KeyID: keyID}), ErrInvalidAttestation)
+	require.ErrorIs(t, a.Verify(ctx, &Request{Provider: AppAttestProvider, Challenge: "challenge", Token: "bogus", KeyID: keyID}), ErrInvalidAttestation)
+}
+
+func TestNewAppAttestLoadsAppleRoot(t *testing.T) {
+	t.Parallel()
+	var cfg config
+	cfg.DeviceAttestation.AppAttest.AppID = "ABCDE12345.io.ice.app"
+
+	assert.NotNil(t, newAppAttest(&cfg).roots)
+}
+
+func TestConfigValidateReportsAllProblems(t *testing.T) {
+	t.Parallel()
+	var cfg config
+	cfg.DeviceAttestation.Providers = []ProviderType{StubProvider, AppAttestProvider, "bogus"}
+	err := cfg.validate("users")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "`users.deviceAttestation.stub.validToken` is missing")
+	assert.Contains(t, err.Error(), "`users.deviceAttestation.appAttest.appId` is missing")
+	assert.Contains(t, err.Error(), "unsupported provider `bogus`")
+
+	cfg.DeviceAttestation.Providers = []ProviderType{StubProvider}
+	cfg.DeviceAttestation.Stub.ValidToken = "valid"
+	require.NoError(t, cfg.validate("users"))
+}
+
+//nolint:funlen // It's a test helper building the whole attestation object.
+func testAppAttestCA(t *testing.T) (*x509.CertPool, func(challenge, appID, aaguid string) (token, keyID string)) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test App Attestation Root CA"},
+		NotBefore:             stdlibtime.Now().Add(-stdlibtime.Hour),
+		NotAfter:              stdlibtime.Now().Add(stdlibtime.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+
+	return roots, func(challenge, appID, aaguid string) (string, string) { //nolint:gocritic // Not needed.
+		credKey, kErr := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, kErr)
+		ecdhKey, kErr := credKey.PublicKey.ECDH()
+		require.NoError(t, kErr)
+		keyID := sha256.Sum256(ecdhKey.Bytes())
+		appIDHash := sha256.Sum256([]byte(appID))
+		authData := append(append([]byte{}, appIDHash[:]...), 0x40, 0, 0, 0, 0)
+		authData = append(authData, aaguid...)
+		authData = binary.BigEndian.AppendUint16(authData, uint16(len(keyID)))
+		authData = append(authData, keyID[:]...)
+		clientDataHash := sha256.Sum256([]byte(challenge))
+		nonce := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+		nonceExt, kErr := asn1.Marshal(appAttestNonceExtension{Nonce: nonce[:]})
+		require.NoError(t, kErr)
+		leafTemplate := &x509.Certificate{
+			SerialNumber:    big.NewInt(2),
+			Subject:         pkix.Name{CommonName: "test"},
+			NotBefore:       stdlibtime.Now().Add(-stdlibtime.Hour),
+			NotAfter:        stdlibtime.Now().Add(stdlibtime.Hour),
+			ExtraExtensions: []pkix.Extension{{Id: appAttestNonceExtensionOID, Value: nonceExt}},
+		}
+		leafDER, kErr := x509.CreateCertificate(rand.Reader, leafTemplate, caCert, &credKey.PublicKey, caKey)
+		require.NoError(t, kErr)
+		obj := appAttestObject{Fmt: appAttestFormat, AuthData: authData}
+		obj.AttStmt.X5C = [][]byte{leafDER}
+		var raw []byte
+		require.NoError(t, codec.NewEncoderBytes(&raw, new(codec.CborHandle)).Encode(&obj))
+
+		return base64.StdEncoding.EncodeToString(raw), base64.StdEncoding.EncodeToString(keyID[:])
+	}
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package deviceattestation
+
+import (
+	"context"
+	"crypto/x509"
+	_ "embed"
+	"encoding/asn1"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/playintegrity/v1"
+)
+
+// Public API.
+
+const (
+	StubProvider          ProviderType = "stub"
+	PlayIntegrityProvider ProviderType = "playIntegrity"
+	AppAttestProvider     ProviderType = "appAttest"
+)
+
+var (
+	ErrInvalidAttestation  = errors.New("invalid attestation")
+	ErrUnsupportedProvider = errors.New("unsupported attestation provider")
+)
+
+type (
+	ProviderType string
+	Request      struct {
+		// Challenge is the one time challenge we issued for the device, which the token must be bound to.
+		Challenge string
+		// Token is the Play Integrity token or the base64 encoded App Attest attestation object.
+		Token string
+		// KeyID is the base64 encoded ID of the App Attest key. It's not used by the other providers.
+		KeyID    string
+		Provider ProviderType
+	}
+	Verifier interface {
+		// Verify returns ErrInvalidAttestation if the token is not valid or not bound to the challenge.
+		Verify(ctx context.Context, req *Request) error
+	}
+)
+
+// Private API.
+
+const (
+	playIntegrityRecognizedApp        = "PLAY_RECOGNIZED"
+	playIntegrityMeetsDeviceIntegrity = "MEETS_DEVICE_INTEGRITY"
+	playIntegrityMeetsStrongIntegrity = "MEETS_STRONG_INTEGRITY"
+	appAttestFormat                   = "apple-appattest"
+	appAttestProductionAAGUID         = "appattest\x00\x00\x00\x00\x00\x00\x00"
+	appAttestDevelopmentAAGUID        = "appattestdevelop"
+	requestDeadline                   = 25 * stdlibtime.Second
+	playIntegrityCredentialsEnv       = "PLAY_INTEGRITY_CREDENTIALS_JSON" //nolint:gosec // It's just the name.
+	playIntegrityCredentialsFileEnv   = "PLAY_INTEGRITY_CREDENTIALS_FILE" //nolint:gosec // It's just the name.
+)
+
+var (
+	//nolint:gochecknoglobals // It's a constant.
+	appAttestNonceExtensionOID = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 8, 2}
+	// appAttestRootCA is the Apple App Attestation Root CA, from https://www.apple.com/certificateauthority/private/.
+	//go:embed Apple_App_Attestation_Root_CA.pem
+	appAttestRootCA string
+)
+
+type (
+	verifier struct {
+		providers map[ProviderType]Verifier
+	}
+	// | stub accepts only the configured token. It's meant for tests and local environments.
+	stub struct {
+		validToken string
+	}
+	// | playIntegrity decodes the tokens using Google's Play Integrity API and checks their verdicts.
+	playIntegrity struct {
+		svc                    *playintegrity.Service
+		packageName            string
+		requireStrongIntegrity bool
+	}
+	// | appAttest verifies the App Attest attestation objects locally, as described by Apple at
+	// https://developer.apple.com/documentation/devicecheck/validating_apps_that_connect_to_your_server.
+	appAttest struct {
+		roots       *x509.CertPool
+		appID       string
+		development bool
+	}
+	appAttestObject struct {
+		Fmt     string `codec:"fmt"`
+		AttStmt struct {
+			X5C     [][]byte `codec:"x5c"`
+			Receipt []byte   `codec:"receipt"`
+		} `codec:"attStmt"`
+		AuthData []byte `codec:"authData"`
+	}
+	appAttestNonceExtension struct {
+		Nonce []byte `asn1:"tag:1,explicit"`
+	}
+	// | config holds the configuration of this package mounted from `application.yaml`.
+	config struct {
+		DeviceAttestation struct {
+			Stub struct {
+				ValidToken string `yaml:"validToken"`
+			} `yaml:"stub"`
+			PlayIntegrity struct {
+				PackageName            string `yaml:"packageName"`
+				CredentialsJSON        string `yaml:"credentialsJson"` //nolint:tagliatelle // Nope.
+				RequireStrongIntegrity bool   `yaml:"requireStrongIntegrity"`
+			} `yaml:"playIntegrity"`
+			AppAttest struct {
+				// AppID is the team ID and the bundle ID of the app, I.E. `ABCDE12345.io.ice.app`.
+				AppID       string `yaml:"appId"` //nolint:tagliatelle // Nope.
+				Development bool   `yaml:"development"`
+			} `yaml:"appAttest"`
+			Providers []ProviderType `yaml:"providers"`
+		} `yaml:"deviceAttestation"`
+	}
+)
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package deviceattestation
+
+import (
+	"context"
+	"os"
+	"slices"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/option"
+	"google.golang.org/api/playintegrity/v1"
+
+	"github.com/ice-blockchain/wintr/log"
+)
+
+func newPlayIntegrity(applicationYAMLKey string, cfg *config) *playIntegrity {
+	playIntegrityCfg := &cfg.DeviceAttestation.PlayIntegrity
+	credentials, err := playIntegrityCredentials(applicationYAMLKey, cfg)
+	log.Panic(err) //nolint:revive // That's intended.
+	svc, err := playintegrity.NewService(context.Background(), option.WithCredentialsJSON([]byte(credentials)))
+	log.Panic(errors.Wrap(err, "failed to create the play integrity service")) //nolint:revive // That's intended.
+
+	return &playIntegrity{
+		svc:                    svc,
+		packageName:            playIntegrityCfg.PackageName,
+		requireStrongIntegrity: playIntegrityCfg.RequireStrongIntegrity,
+	}
+}
+
+// playIntegrityCredentials prefers the config, then the env variable with the credentials, then the file from the env variable.
+func playIntegrityCredentials(applicationYAMLKey string, cfg *config) (string, error) {
+	if credentials := cfg.DeviceAttestation.PlayIntegrity.CredentialsJSON; credentials != "" {
+		return credentials, nil
+	}
+	if credentials := loadFromEnv(applicationYAMLKey, playIntegrityCredentialsEnv); credentials != "" {
+		return credentials, nil
+	}
+	if file := loadFromEnv(applicationYAMLKey, playIntegrityCredentialsFileEnv); file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read the play integrity credentials from %v", file)
+		}
+		if len(content) != 0 {
+			return string(content), nil
+		}
+	}
+
+	return "", errors.Errorf("`%v.deviceAttestation.playIntegrity.credentialsJson` (or the %v/%v env variables) is missing",
+		applicationYAMLKey, playIntegrityCredentialsEnv, playIntegrityCredentialsFileEnv)
+}
+
+func (p *playIntegrity) Verify(ctx context.Context, r *Request) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, requestDeadline)
+	defer cancel()
+	resp, err := p.svc.V1.DecodeIntegrityToken(p.packageName, &playintegrity.DecodeIntegrityTokenRequest{IntegrityToken: r.Token}).Context(reqCtx).Do()
+	if err != nil {
+		return errors.Wrap(err, "failed to decode play integrity token")
+	}
+
+	return errors.Wrap(p.checkVerdict(resp.TokenPayloadExternal, r.Challenge), "play integrity verdict check failed")
+}
+
+func (p *playIntegrity) checkVerdict(payload *playintegrity.TokenPayloadExternal, challenge string) error {
+	switch {
+	case payload == nil || payload.RequestDetails == nil || payload.AppIntegrity == nil || payload.DeviceIntegrity == nil:
+		return errors.Wrap(ErrInvalidAttestation, "incomplete token payload")
+	case payload.RequestDetails.RequestPackageName != p.packageName:
+		return errors.Wrapf(ErrInvalidAttestation, "unexpected package name `%v`", payload.RequestDetails.RequestPackageName)
+	case payload.RequestDetails.Nonce != challenge:
+		return errors.Wrap(ErrInvalidAttestation, "the token is not bound to the challenge")
+	case payload.AppIntegrity.AppRecognitionVerdict != playIntegrityRecognizedApp:
+		return errors.Wrapf(ErrInvalidAttestation, "app not recognized: %v", payload.AppIntegrity.AppRecognitionVerdict)
+	}
+	requiredDeviceVerdict := playIntegrityMeetsDeviceIntegrity
+	if p.requireStrongIntegrity {
+		requiredDeviceVerdict = playIntegrityMeetsStrongIntegrity
+	}
+	if !slices.Contains(payload.DeviceIntegrity.DeviceRecognitionVerdict, requiredDeviceVerdict) {
+		return errors.Wrapf(ErrInvalidAttestation, "device verdicts %v don't include %v", payload.DeviceIntegrity.DeviceRecognitionVerdict, requiredDeviceVerdict)
+	}
+
+	return nil
+}
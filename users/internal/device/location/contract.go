@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package devicelocation
+
+import (
+	"context"
+	"net"
+	"sync"
+	stdlibtime "time"
+
+	"github.com/ip2location/ip2location-go/v9"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// Public API.
+
+const (
+	IP2LocationProvider ProviderType = "ip2location"
+	MaxMindProvider     ProviderType = "maxMind"
+)
+
+const (
+	CityAccuracy    Accuracy = "city"
+	CountryAccuracy Accuracy = "country"
+)
+
+type (
+	ProviderType string
+	// Accuracy is the most precise level of the location that the provider could determine.
+	Accuracy string
+	Location struct {
+		Country  string
+		City     string
+		Accuracy Accuracy
+		Provider ProviderType
+	}
+	Provider interface {
+		// Locate returns the location of the IP. Its country is empty if the IP can't be located.
+		Locate(ctx context.Context, ip net.IP) (*Location, error)
+	}
+)
+
+// Private API.
+
+const (
+	defaultMaxMindURL            = "https://geoip.maxmind.com/geoip/v2.1/city"
+	maxMindIPAddressNotFoundCode = "IP_ADDRESS_NOT_FOUND"
+	maxMindIPAddressReservedCode = "IP_ADDRESS_RESERVED"
+	defaultRequestTimeout        = 2 * stdlibtime.Second
+	maxMindAccountIDEnv          = "MAXMIND_ACCOUNT_ID"
+	maxMindLicenseKeyEnv         = "MAXMIND_LICENSE_KEY" //nolint:gosec // It's just the name.
+)
+
+type (
+	// | fallback asks the providers in order, until one of them locates the IP, and caches the results.
+	fallback struct {
+		cache     *cache
+		providers []Provider
+	}
+	cache struct {
+		entries    map[string]*cachedLocation
+		ttl        stdlibtime.Duration
+		maxEntries int
+		mx         sync.Mutex
+	}
+	cachedLocation struct {
+		expiresAt *time.Time
+		*Location
+	}
+	// | ip2locationProvider uses the local ip2location database, so it works without any external vendor.
+	ip2locationProvider struct {
+		db *ip2location.DB
+	}
+	// | maxMind uses the GeoIP2 City web service of https://www.maxmind.com.
+	maxMind struct {
+		cfg *config
+	}
+	maxMindResponse struct {
+		City struct {
+			Names map[string]string `json:"names"`
+		} `json:"city"`
+		Country struct {
+			IsoCode string `json:"iso_code"` //nolint:tagliatelle // It's their API.
+		} `json:"country"`
+		Code  string `json:"code"`
+		Error string `json:"error"`
+	}
+	// | config holds the configuration of this package mounted from `application.yaml`.
+	config struct {
+		DeviceLocation struct {
+			MaxMind struct {
+				URL        string              `yaml:"url"`
+				AccountID  string              `yaml:"accountId"`  //nolint:tagliatelle // Nope.
+				LicenseKey string              `yaml:"licenseKey"` //nolint:tagliatelle // Nope.
+				Timeout    stdlibtime.Duration `yaml:"timeout"`
+			} `yaml:"maxMind"`
+			// Providers are asked in order, the next ones being the fallbacks of the previous ones.
+			Providers       []ProviderType      `yaml:"providers"`
+			CacheTTL        stdlibtime.Duration `yaml:"cacheTtl"`
+			CacheMaxEntries int                 `yaml:"cacheMaxEntries"`
+		} `yaml:"deviceLocation"`
+	}
+)
@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package devicelocation
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/ip2location/ip2location-go/v9"
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// New returns the configured providers, asked in order, with their results cached.
+// Without any configured provider, it defaults to ip2location, which is skipped if its database is not available.
+func New(applicationYAMLKey string, ip2LocationDB *ip2location.DB) Provider {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+	cfg.loadFromEnv(applicationYAMLKey)
+	log.Panic(cfg.validate(applicationYAMLKey)) //nolint:revive // That's intended.
+	providers := cfg.DeviceLocation.Providers
+	if len(providers) == 0 {
+		providers = []ProviderType{IP2LocationProvider}
+	}
+	f := &fallback{providers: make([]Provider, 0, len(providers))}
+	for _, provider := range providers {
+		switch provider {
+		case IP2LocationProvider:
+			if ip2LocationDB != nil {
+				f.providers = append(f.providers, &ip2locationProvider{db: ip2LocationDB})
+			}
+		case MaxMindProvider:
+			f.providers = append(f.providers, newMaxMind(&cfg))
+		}
+	}
+	if cfg.DeviceLocation.CacheTTL > 0 {
+		f.cache = &cache{
+			entries:    make(map[string]*cachedLocation, cfg.DeviceLocation.CacheMaxEntries),
+			ttl:        cfg.DeviceLocation.CacheTTL,
+			maxEntries: cfg.DeviceLocation.CacheMaxEntries,
+		}
+	}
+
+	return f
+}
+
+// ValidateConfig reports, at once, all the problems of the config of the providers that New would use.
+func ValidateConfig(applicationYAMLKey string) error {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+	cfg.loadFromEnv(applicationYAMLKey)
+
+	return cfg.validate(applicationYAMLKey)
+}
+
+func (c *config) validate(applicationYAMLKey string) error {
+	var mErr *multierror.Error
+	for _, provider := range c.DeviceLocation.Providers {
+		switch provider {
+		case IP2LocationProvider:
+		case MaxMindProvider:
+			if c.DeviceLocation.MaxMind.AccountID == "" || c.DeviceLocation.MaxMind.LicenseKey == "" {
+				mErr = multierror.Append(mErr, errors.Errorf("`%v.deviceLocation.maxMind` credentials are missing", applicationYAMLKey))
+			}
+		default:
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.deviceLocation.providers` has the unsupported provider `%v`", applicationYAMLKey, provider))
+		}
+	}
+	if c.DeviceLocation.CacheTTL > 0 && c.DeviceLocation.CacheMaxEntries <= 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.deviceLocation.cacheMaxEntries` must be positive when the cache is enabled", applicationYAMLKey))
+	}
+
+	return mErr.ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+}
+
+func (c *config) loadFromEnv(applicationYAMLKey string) {
+	if c.DeviceLocation.MaxMind.AccountID == "" {
+		c.DeviceLocation.MaxMind.AccountID = loadFromEnv(applicationYAMLKey, maxMindAccountIDEnv)
+	}
+	if c.DeviceLocation.MaxMind.LicenseKey == "" {
+		c.DeviceLocation.MaxMind.LicenseKey = loadFromEnv(applicationYAMLKey, maxMindLicenseKeyEnv)
+	}
+}
+
+// Locate returns the location of the first provider that can locate the IP.
+// It fails only if all the providers failed, otherwise, the failures of the providers before it are just logged.
+func (f *fallback) Locate(ctx context.Context, ip net.IP) (*Location, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	key := ip.String()
+	if loc := f.cache.get(key); loc != nil {
+		return loc, nil
+	}
+	var mErr *multierror.Error
+	for _, provider := range f.providers {
+		loc, err := provider.Locate(ctx, ip)
+		if err != nil {
+			mErr = multierror.Append(mErr, err)
+
+			continue
+		}
+		if loc.Country != "" {
+			if mErr.ErrorOrNil() != nil {
+				log.Error(errors.Wrapf(mErr, "located %v only with the fallback provider `%v`", key, loc.Provider))
+			}
+			f.cache.set(key, loc)
+
+			return loc, nil
+		}
+	}
+	if mErr != nil && len(mErr.Errors) == len(f.providers) {
+		return nil, errors.Wrapf(mErr, "all the providers failed to locate %v", key)
+	}
+
+	return new(Location), nil
+}
+
+func (c *cache) get(key string) *Location {
+	if c == nil {
+		return nil
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	entry, found := c.entries[key]
+	if !found {
+		return nil
+	}
+	if entry.expiresAt.Before(*time.Now().Time) {
+		delete(c.entries, key)
+
+		return nil
+	}
+
+	return entry.Location
+}
+
+// set drops the expired entries when the cache is full and, if it's still full, it doesn't cache the location.
+func (c *cache) set(key string, loc *Location) {
+	if c == nil {
+		return
+	}
+	now := time.Now()
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if len(c.entries) >= c.maxEntries {
+		for k, entry := range c.entries {
+			if entry.expiresAt.Before(*now.Time) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			return
+		}
+	}
+	c.entries[key] = &cachedLocation{Location: loc, expiresAt: time.New(now.Add(c.ttl))}
+}
+
+func (p *ip2locationProvider) Locate(ctx context.Context, ip net.IP) (*Location, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	rec, err := p.db.Get_all(ip.String()) //nolint:nosnakecase // External library.
+	if err != nil {
+		return nil, errors.Wrapf(err, "ip2location failed to locate %v", ip)
+	}
+
+	return newLocation(IP2LocationProvider, rec.Country_short, rec.City), nil //nolint:nosnakecase // External library.
+}
+
+// newLocation ignores the `-`, which the databases use for the unknown values.
+func newLocation(provider ProviderType, country, city string) *Location {
+	loc := &Location{Provider: provider}
+	if country = strings.TrimSpace(country); country == "" || country == "-" {
+		return loc
+	}
+	loc.Country, loc.Accuracy = strings.ToUpper(country), CountryAccuracy
+	if city = strings.TrimSpace(city); city != "" && city != "-" {
+		loc.City, loc.Accuracy = city, CityAccuracy
+	}
+
+	return loc
+}
+
+func loadFromEnv(applicationYAMLKey, env string) string {
+	module := strings.ToUpper(strings.ReplaceAll(strings.ReplaceAll(applicationYAMLKey, "-", "_"), "/", "_"))
+	if val := os.Getenv(module + "_" + env); val != "" {
+		return val
+	}
+
+	return os.Getenv(env)
+}
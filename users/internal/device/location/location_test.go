@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package devicelocation
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	fakeProvider struct {
+		loc   *Location
+		err   error
+		calls int
+	}
+)
+
+func (p *fakeProvider) Locate(context.Context, net.IP) (*Location, error) {
+	p.calls++
+
+	return p.loc, p.err
+}
+
+func TestFallbackLocate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	ip := net.ParseIP("1.1.1.1")
+
+	failing := &fakeProvider{err: errors.New("vendor down")}
+	unlocated := &fakeProvider{loc: &Location{Provider: MaxMindProvider}}
+	located := &fakeProvider{loc: newLocation(IP2LocationProvider, "us", "New York")}
+	f := &fallback{
+		providers: []Provider{failing, unlocated, located},
+		cache:     &cache{entries: make(map[string]*cachedLocation), ttl: stdlibtime.Hour, maxEntries: 1},
+	}
+	loc, err := f.Locate(ctx, ip)
+	require.NoError(t, err)
+	assert.Equal(t, &Location{Country: "US", City: "New York", Accuracy: CityAccuracy, Provider: IP2LocationProvider}, loc)
+	loc, err = f.Locate(ctx, ip)
+	require.NoError(t, err)
+	assert.Equal(t, "US", loc.Country)
+	assert.Equal(t, 1, located.calls)
+
+	loc, err = (&fallback{providers: []Provider{unlocated}}).Locate(ctx, ip)
+	require.NoError(t, err)
+	assert.Empty(t, loc.Country)
+
+	_, err = (&fallback{providers: []Provider{failing, failing}}).Locate(ctx, ip)
+	require.Error(t, err)
+}
+
+func TestNewLocation(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, &Location{Provider: IP2LocationProvider}, newLocation(IP2LocationProvider, "-", "-"))
+	assert.Equal(t, &Location{Country: "RO", Accuracy: CountryAccuracy, Provider: MaxMindProvider}, newLocation(MaxMindProvider, "ro", ""))
+}
+
+func TestMaxMindLocate(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "a" || pass != "b" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/1.1.1.1":
+			_, _ = w.Write([]byte(`{"city":{"names":{"en":"Berlin"}},"country":{"iso_code":"DE"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"IP_ADDRESS_NOT_FOUND","error":"not found"}`))
+		}
+	}))
+	defer srv.Close()
+	var cfg config
+	cfg.DeviceLocation.MaxMind.URL, cfg.DeviceLocation.MaxMind.AccountID, cfg.DeviceLocation.MaxMind.LicenseKey = srv.URL, "a", "b"
+	m := newMaxMind(&cfg)
+
+	loc, err := m.Locate(context.Background(), net.ParseIP("1.1.1.1"))
+	require.NoError(t, err)
+	assert.Equal(t, &Location{Country: "DE", City: "Berlin", Accuracy: CityAccuracy, Provider: MaxMindProvider}, loc)
+	loc, err = m.Locate(context.Background(), net.ParseIP("10.0.0.1"))
+	require.NoError(t, err)
+	assert.Empty(t, loc.Country)
+
+	cfg.DeviceLocation.MaxMind.LicenseKey = "wrong"
+	_, err = m.Locate(context.Background(), net.ParseIP("1.1.1.1"))
+	require.Error(t, err)
+}
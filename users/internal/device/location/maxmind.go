@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package devicelocation
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+)
+
+func newMaxMind(cfg *config) *maxMind {
+	maxMindCfg := &cfg.DeviceLocation.MaxMind
+	if maxMindCfg.URL == "" {
+		maxMindCfg.URL = defaultMaxMindURL
+	}
+	if maxMindCfg.Timeout <= 0 {
+		maxMindCfg.Timeout = defaultRequestTimeout
+	}
+
+	return &maxMind{cfg: cfg}
+}
+
+func (m *maxMind) Locate(ctx context.Context, ip net.IP) (*Location, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	maxMindCfg := &m.cfg.DeviceLocation.MaxMind
+	reqCtx, cancel := context.WithTimeout(ctx, maxMindCfg.Timeout)
+	defer cancel()
+	var result maxMindResponse
+	resp, err := req.
+		SetContext(reqCtx).
+		SetBasicAuth(maxMindCfg.AccountID, maxMindCfg.LicenseKey).
+		SetPathParam("ip", ip.String()).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Get(strings.TrimSuffix(maxMindCfg.URL, "/") + "/{ip}")
+	if err != nil {
+		return nil, errors.Wrapf(err, "maxMind request failed for %v", ip)
+	}
+	if result.Code == maxMindIPAddressNotFoundCode || result.Code == maxMindIPAddressReservedCode {
+		return &Location{Provider: MaxMindProvider}, nil
+	}
+	if !resp.IsSuccessState() {
+		return nil, errors.Errorf("maxMind request failed with status: %v, code: %v, error: %v", resp.GetStatusCode(), result.Code, result.Error)
+	}
+
+	return newLocation(MaxMindProvider, result.Country.IsoCode, result.City.Names["en"]), nil
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package devicemetadata
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
+
+	"github.com/ice-blockchain/eskimo/users/internal/device"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetAppVersionRequirements(ctx context.Context) ([]*AppVersionRequirement, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	if err := r.loadAppVersionRequirements(ctx, true); err != nil {
+		return nil, errors.Wrap(err, "failed to loadAppVersionRequirements")
+	}
+
+	return []*AppVersionRequirement{r.appVersionRequirement(AndroidPlatform), r.appVersionRequirement(IOSPlatform)}, nil
+}
+
+// SetAppVersionRequirement is applied right away by this instance, the other ones pick it up in at most `appVersionRequirements.cacheTtl`.
+func (r *repository) SetAppVersionRequirement(ctx context.Context, requirement *AppVersionRequirement) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	if requirement.Platform != AndroidPlatform && requirement.Platform != IOSPlatform {
+		return errors.Wrapf(ErrInvalidPlatform, "unsupported platform %v", requirement.Platform)
+	}
+	if !isValidRequiredAppVersion(requirement.MinVersion) {
+		return errors.Wrapf(ErrInvalidAppVersion, "invalid min version %v, expected vX.Y.Z or vX.Y.Z.N", requirement.MinVersion)
+	}
+	requirement.UpdatedAt = time.Now()
+	sql := `INSERT INTO app_version_requirements (updated_at, force_update, platform, min_version, updated_by) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (platform) DO UPDATE
+				SET updated_at   = EXCLUDED.updated_at,
+					force_update = EXCLUDED.force_update,
+					min_version  = EXCLUDED.min_version,
+					updated_by   = EXCLUDED.updated_by`
+	params := []any{requirement.UpdatedAt.Time, requirement.ForceUpdate, requirement.Platform, requirement.MinVersion, requirement.UpdatedBy}
+	if _, err := storage.Exec(ctx, r.db, sql, params...); err != nil {
+		return errors.Wrapf(err, "failed to upsert app version requirement %#v", requirement)
+	}
+
+	return errors.Wrap(r.loadAppVersionRequirements(ctx, true), "failed to reload the app version requirements")
+}
+
+// CheckDeviceAppVersion lets through the devices without metadata, because there's nothing to check yet
+// (and replacing the metadata already rejects the outdated apps).
+func (r *repository) CheckDeviceAppVersion(ctx context.Context, id *device.ID) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	if err := r.loadAppVersionRequirements(ctx, false); err != nil {
+		return errors.Wrap(err, "failed to loadAppVersionRequirements")
+	}
+	if !r.appVersionRequirement(AndroidPlatform).ForceUpdate && !r.appVersionRequirement(IOSPlatform).ForceUpdate {
+		return nil
+	}
+	sql := `SELECT readable_version, system_name FROM device_metadata WHERE user_id = $1 AND device_unique_id = $2`
+	md, err := storage.Get[struct{ ReadableVersion, SystemName string }](ctx, r.db, sql, id.UserID, id.DeviceUniqueID)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return nil
+		}
+
+		return errors.Wrapf(err, "failed to get device app version for %#v", id)
+	}
+	if !r.appVersionRequirement(platformOf(md.SystemName)).ForceUpdate {
+		return nil
+	}
+	if vErr := r.verifyDeviceAppVersion(&DeviceMetadata{ReadableVersion: md.ReadableVersion, SystemName: md.SystemName}); errors.Is(vErr, ErrOutdatedAppVersion) {
+		return errors.Wrapf(vErr, "device %#v must update its app", id)
+	}
+
+	return nil
+}
+
+func (r *repository) loadAppVersionRequirements(ctx context.Context, force bool) error {
+	now := time.Now()
+	r.appVersions.mx.RLock()
+	fresh := r.appVersions.loadedAt != nil && r.appVersions.loadedAt.Add(r.cfg.live().AppVersionRequirements.CacheTTL).After(*now.Time)
+	r.appVersions.mx.RUnlock()
+	if fresh && !force {
+		return nil
+	}
+	sql := `SELECT * FROM app_version_requirements`
+	requirements, err := storage.Select[AppVersionRequirement](ctx, r.db, sql)
+	if err != nil {
+		return errors.Wrap(err, "failed to select app version requirements")
+	}
+	byPlatform := make(map[Platform]*AppVersionRequirement, len(requirements))
+	for _, requirement := range requirements {
+		byPlatform[requirement.Platform] = requirement
+	}
+	r.appVersions.mx.Lock()
+	r.appVersions.loadedAt, r.appVersions.byPlatform = now, byPlatform
+	r.appVersions.mx.Unlock()
+
+	return nil
+}
+
+// appVersionRequirement returns the runtime requirement of the platform, if any, otherwise the configured one.
+func (r *repository) appVersionRequirement(platform Platform) *AppVersionRequirement {
+	if r.appVersions != nil {
+		r.appVersions.mx.RLock()
+		requirement, found := r.appVersions.byPlatform[platform]
+		r.appVersions.mx.RUnlock()
+		if found {
+			return requirement
+		}
+	}
+	cfg := r.cfg.live()
+	if platform == IOSPlatform {
+		return &AppVersionRequirement{Platform: platform, MinVersion: cfg.RequiredAppVersion.IOS, ForceUpdate: cfg.AppVersionRequirements.ForceUpdate.IOS}
+	}
+
+	return &AppVersionRequirement{Platform: platform, MinVersion: cfg.RequiredAppVersion.Android, ForceUpdate: cfg.AppVersionRequirements.ForceUpdate.Android}
+}
+
+func platformOf(systemName string) Platform {
+	switch strings.ReplaceAll(strings.ToLower(systemName), " ", "") {
+	case "ios", "iphoneos", "ipados":
+		return IOSPlatform
+	default:
+		return AndroidPlatform
+	}
+}
+
+// isValidRequiredAppVersion accepts the same format as `requiredAppVersion`: a semver without pre-release/build, optionally followed by the nanos.
+func isValidRequiredAppVersion(version string) bool {
+	parts := strings.Split(version, ".")
+	if len(parts) != 1+1+1 && len(parts) != 1+1+1+1 {
+		return false
+	}
+	majorMinorPatch := strings.Join(parts[:1+1+1], ".")
+	if semver.Canonical(majorMinorPatch) != majorMinorPatch || semver.Prerelease(majorMinorPatch) != "" {
+		return false
+	}
+	if len(parts) == 1+1+1+1 {
+		if _, err := strconv.ParseUint(parts[3], 10, 64); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package devicemetadata
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users/internal/device"
+	deviceattestation "github.com/ice-blockchain/eskimo/users/internal/device/attestation"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const (
+	deviceAttestationChallengeBytes = 32
+)
+
+// CreateDeviceAttestationChallenge replaces any previous challenge of the device, so only the latest one can be used.
+// The device must have metadata, because that's where we store the attestation result.
+func (r *repository) CreateDeviceAttestationChallenge(ctx context.Context, id *device.ID) (*DeviceAttestationChallenge, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	if r.attestationVerifier == nil {
+		return nil, errors.Wrap(ErrUnsupportedDeviceAttestationProvider, "device attestation is disabled")
+	}
+	rawChallenge := make([]byte, deviceAttestationChallengeBytes)
+	if _, err := rand.Read(rawChallenge); err != nil {
+		return nil, errors.Wrap(err, "failed to generate device attestation challenge")
+	}
+	now := time.Now()
+	challenge := &DeviceAttestationChallenge{
+		ExpiresAt: time.New(now.Add(r.cfg.DeviceAttestation.ChallengeTTL)),
+		Challenge: hex.EncodeToString(rawChallenge),
+	}
+	sql := `INSERT INTO device_attestation_challenges (created_at, user_id, device_unique_id, challenge)
+			SELECT $1, user_id, device_unique_id, $4
+			FROM device_metadata
+			WHERE user_id = $2 AND device_unique_id = $3
+			ON CONFLICT (user_id, device_unique_id) DO UPDATE
+				SET created_at = EXCLUDED.created_at,
+					challenge  = EXCLUDED.challenge`
+	rowsInserted, err := storage.Exec(ctx, r.db, sql, now.Time, id.UserID, id.DeviceUniqueID, challenge.Challenge)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to insert device attestation challenge for %#v", id)
+	}
+	if rowsInserted == 0 {
+		return nil, errors.Wrapf(storage.ErrNotFound, "no device metadata for %#v", id)
+	}
+
+	return challenge, nil
+}
+
+// VerifyDeviceAttestation stores the result whether the attestation passed or not, so a failed one overrides any previous pass.
+// The challenge is consumed regardless of the result.
+func (r *repository) VerifyDeviceAttestation(ctx context.Context, attestation *DeviceAttestation) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	if r.attestationVerifier == nil {
+		return errors.Wrap(ErrUnsupportedDeviceAttestationProvider, "device attestation is disabled")
+	}
+	sql := `DELETE FROM device_attestation_challenges WHERE user_id = $1 AND device_unique_id = $2 RETURNING *`
+	challenge, err := storage.ExecOne[deviceAttestationChallenge](ctx, r.db, sql, attestation.UserID, attestation.DeviceUniqueID)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return errors.Wrapf(ErrDeviceAttestationChallengeNotFound, "no challenge for %#v", &attestation.ID)
+		}
+
+		return errors.Wrapf(err, "failed to consume device attestation challenge for %#v", &attestation.ID)
+	}
+	now := time.Now()
+	if challenge.CreatedAt.Add(r.cfg.DeviceAttestation.ChallengeTTL).Before(*now.Time) {
+		return errors.Wrapf(ErrDeviceAttestationChallengeNotFound, "challenge expired for %#v", &attestation.ID)
+	}
+	vErr := r.attestationVerifier.Verify(ctx, &deviceattestation.Request{
+		Challenge: challenge.Challenge,
+		Token:     attestation.Token,
+		KeyID:     attestation.KeyID,
+		Provider:  attestation.Provider,
+	})
+	if vErr != nil && !errors.Is(vErr, ErrInvalidDeviceAttestation) {
+		return errors.Wrapf(vErr, "failed to verify device attestation for %#v", &attestation.ID)
+	}
+	sql = `UPDATE device_metadata
+		   SET attested_at = $3,
+			   attestation_provider = $4,
+			   attestation_passed = $5
+		   WHERE user_id = $1 AND device_unique_id = $2`
+	if _, err = storage.Exec(ctx, r.db, sql, attestation.UserID, attestation.DeviceUniqueID, now.Time, attestation.Provider, vErr == nil); err != nil {
+		return errors.Wrapf(err, "failed to store device attestation result for %#v", &attestation.ID)
+	}
+
+	return errors.Wrapf(vErr, "device attestation failed for %#v", &attestation.ID)
+}
+
+func (r *repository) CheckDeviceAttestation(ctx context.Context, id *device.ID, purpose DeviceAttestationPurpose) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	if !r.isDeviceAttestationRequired(purpose) {
+		return nil
+	}
+	if id.DeviceUniqueID == "" {
+		return errors.Wrapf(ErrDeviceAttestationRequired, "unknown device for userID:%v", id.UserID)
+	}
+	sql := `SELECT attested_at
+			FROM device_metadata
+			WHERE user_id = $1
+			  AND device_unique_id = $2
+			  AND attestation_passed = true
+			  AND attested_at > $3`
+	if _, err := storage.Get[struct{ AttestedAt *time.Time }](ctx, r.db, sql, id.UserID, id.DeviceUniqueID, time.Now().Add(-r.cfg.DeviceAttestation.MaxAge)); err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return errors.Wrapf(ErrDeviceAttestationRequired, "no recent valid attestation for %#v, for %v", id, purpose)
+		}
+
+		return errors.Wrapf(err, "failed to get device attestation for %#v", id)
+	}
+
+	return nil
+}
+
+func (r *repository) isDeviceAttestationRequired(purpose DeviceAttestationPurpose) bool {
+	switch purpose {
+	case KYCDeviceAttestationPurpose:
+		return r.cfg.DeviceAttestation.RequiredFor.KYC
+	case AuthDeviceAttestationPurpose:
+		return r.cfg.DeviceAttestation.RequiredFor.Auth
+	default:
+		return false
+	}
+}
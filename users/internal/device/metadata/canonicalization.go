@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package devicemetadata
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/log"
+)
+
+func loadGazetteer() map[Country]map[string]City {
+	var cities []*gazetteerCity
+	//nolint:revive // That's the point.
+	log.Panic(json.UnmarshalContext(context.Background(), []byte(citiesJSON), &cities))
+	gaz := make(map[Country]map[string]City, len(countries))
+	for _, city := range cities {
+		if _, found := countries[city.Country]; !found {
+			log.Panic(errors.Errorf("gazetteer city %#v has an invalid country", city))
+		}
+		if gaz[city.Country] == nil {
+			gaz[city.Country] = make(map[string]City)
+		}
+		for _, spelling := range append([]City{city.Name}, city.Aliases...) {
+			gaz[city.Country][foldCity(spelling)] = city.Name
+		}
+	}
+
+	return gaz
+}
+
+func (*repository) CanonicalCountry(co Country) (Country, bool) {
+	co = strings.TrimSpace(co)
+	if _, found := countries[strings.ToUpper(co)]; found {
+		return strings.ToUpper(co), true
+	}
+	for isoCode, c := range countries {
+		if strings.EqualFold(c.Name, co) {
+			return isoCode, true
+		}
+	}
+
+	return co, false
+}
+
+// CanonicalCity returns the gazetteer's name of the city, if it knows it, otherwise the city with its whitespace collapsed and,
+// if it's all in lower or upper case, in title case.
+func (*repository) CanonicalCity(co Country, ci City) City {
+	ci = strings.Join(strings.Fields(ci), " ")
+	if ci == "" {
+		return ""
+	}
+	if canonical, found := gazetteer[strings.ToUpper(co)][foldCity(ci)]; found {
+		return canonical
+	}
+	if ci != strings.ToLower(ci) && ci != strings.ToUpper(ci) {
+		return ci
+	}
+
+	return titleCase(ci)
+}
+
+// foldCity keeps only the lower case letters and digits, so that `St. Petersburg` and `st petersburg` are the same.
+func foldCity(ci City) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(ci) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+func titleCase(ci City) City {
+	runes := []rune(strings.ToLower(ci))
+	for ix := range runes {
+		if ix == 0 || runes[ix-1] == ' ' || runes[ix-1] == '-' {
+			runes[ix] = unicode.ToUpper(runes[ix])
+		}
+	}
+
+	return string(runes)
+}
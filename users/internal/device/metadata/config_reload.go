@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package devicemetadata
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+func (*repository) ReloadableConfigKeys() []string {
+	return []string{
+		applicationYamlKey + ".requiredAppVersion",
+		applicationYamlKey + ".appVersionRequirements",
+	}
+}
+
+// ReloadConfig copies only the reloadable values from the re-read config, so the rest keep the values they were started with.
+// The runtime app version requirements still take precedence over the reloaded ones.
+func (r *repository) ReloadConfig() error {
+	var loaded config
+	if err := viper.UnmarshalKey(applicationYamlKey, &loaded); err != nil {
+		return errors.Wrapf(err, "failed to load config by key %q", applicationYamlKey)
+	}
+	if err := loaded.validate().ErrorOrNil(); err != nil {
+		return errors.Wrapf(err, "invalid reloaded config of %v", applicationYamlKey)
+	}
+	latest := *r.cfg
+	latest.RequiredAppVersion = loaded.RequiredAppVersion
+	latest.AppVersionRequirements = loaded.AppVersionRequirements
+	r.cfg.reloaded.Store(&latest)
+
+	return nil
+}
+
+// live returns the config with the latest values of ReloadableConfigKeys, which must always be read through it.
+func (c *config) live() *config {
+	if c.reloaded != nil {
+		if latest := c.reloaded.Load(); latest != nil {
+			return latest
+		}
+	}
+
+	return c
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package devicemetadata
+
+import (
+	"os"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	deviceattestation "github.com/ice-blockchain/eskimo/users/internal/device/attestation"
+	devicelocation "github.com/ice-blockchain/eskimo/users/internal/device/location"
+	appcfg "github.com/ice-blockchain/wintr/config"
+)
+
+// ValidateConfig reports, at once, all the problems of the config needed by New.
+// The ip2location database and the attestation providers are needed only when New is used with a message broker (I.E. by the processor).
+func ValidateConfig(processor bool) error {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
+	mErr := cfg.validate()
+	if !processor {
+		return mErr.ErrorOrNil() //nolint:wrapcheck // They're already descriptive.
+	}
+	if !cfg.SkipIP2LocationBinary {
+		if cfg.IP2LocationBinaryPath == "" {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.ip2LocationBinaryPath` is missing", applicationYamlKey))
+		} else if _, err := os.Stat(cfg.IP2LocationBinaryPath); err != nil {
+			mErr = multierror.Append(mErr, errors.Wrapf(err, "`%v.ip2LocationBinaryPath` is not readable", applicationYamlKey))
+		}
+	}
+	if (cfg.DeviceAttestation.RequiredFor.KYC || cfg.DeviceAttestation.RequiredFor.Auth) && cfg.DeviceAttestation.ChallengeTTL <= 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.deviceAttestation.challengeTtl` must be positive when attestation is required", applicationYamlKey))
+	}
+
+	mErr = multierror.Append(mErr, deviceattestation.ValidateConfig(applicationYamlKey))
+
+	return multierror.Append(mErr, devicelocation.ValidateConfig(applicationYamlKey)).ErrorOrNil() //nolint:wrapcheck // .
+}
+
+func (c *config) validate() *multierror.Error {
+	var mErr *multierror.Error
+	for _, required := range []struct {
+		key, version string
+	}{
+		{key: "android", version: c.RequiredAppVersion.Android},
+		{key: "ios", version: c.RequiredAppVersion.IOS},
+	} {
+		if required.version != "" && !isValidRequiredAppVersion(required.version) {
+			mErr = multierror.Append(mErr, errors.Errorf("`%v.requiredAppVersion.%v` is not a valid version: `%v`", applicationYamlKey, required.key, required.version))
+		}
+	}
+	if c.AppVersionRequirements.CacheTTL < 0 {
+		mErr = multierror.Append(mErr, errors.Errorf("`%v.appVersionRequirements.cacheTtl` can't be negative", applicationYamlKey))
+	}
+
+	return mErr
+}
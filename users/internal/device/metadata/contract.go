@@ -7,11 +7,17 @@ import (
 	_ "embed"
 	"io"
 	"net"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	stdlibtime "time"
 
 	"github.com/ip2location/ip2location-go/v9"
 	"github.com/pkg/errors"
 
 	"github.com/ice-blockchain/eskimo/users/internal/device"
+	deviceattestation "github.com/ice-blockchain/eskimo/users/internal/device/attestation"
+	devicelocation "github.com/ice-blockchain/eskimo/users/internal/device/location"
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
 	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
 	"github.com/ice-blockchain/wintr/time"
@@ -22,6 +28,24 @@ import (
 var (
 	ErrInvalidAppVersion  = errors.New("invalid mobile app version")
 	ErrOutdatedAppVersion = errors.New("outdated mobile app version")
+	ErrInvalidPlatform    = errors.New("invalid mobile platform")
+	// ErrInvalidDeviceMetadata is a terror, with the names of the invalid properties in its `fields` data.
+	ErrInvalidDeviceMetadata = errors.New("invalid device metadata")
+
+	ErrDeviceAttestationRequired            = errors.New("device attestation required")
+	ErrDeviceAttestationChallengeNotFound   = errors.New("device attestation challenge not found")
+	ErrInvalidDeviceAttestation             = deviceattestation.ErrInvalidAttestation
+	ErrUnsupportedDeviceAttestationProvider = deviceattestation.ErrUnsupportedProvider
+)
+
+const (
+	KYCDeviceAttestationPurpose  DeviceAttestationPurpose = "kyc"
+	AuthDeviceAttestationPurpose DeviceAttestationPurpose = "auth"
+)
+
+const (
+	AndroidPlatform Platform = "android"
+	IOSPlatform     Platform = "ios"
 )
 
 type (
@@ -32,16 +56,84 @@ type (
 	DeviceMetadataRepository interface {
 		io.Closer
 		IsValid(co Country) bool
+		// CanonicalCountry returns the ISO 3166-1 alpha-2 code of the country, provided as a code, in any case, or as its english name.
+		// It returns false if the country is not a valid one.
+		CanonicalCountry(co Country) (Country, bool)
+		// CanonicalCity normalizes the free-form city of the country, against the bundled gazetteer, so that its spellings are counted as one.
+		CanonicalCity(co Country, ci City) City
 		LookupCountries(key Keyword) []Country
-		GetDeviceMetadataLocation(ctx context.Context, deviceID *device.ID, clientIP net.IP) *DeviceLocation
+		GetDeviceMetadataLocation(ctx context.Context, deviceID *device.ID, clientIP net.IP) *EstimatedDeviceLocation
 		GetDeviceMetadata(ctx context.Context, id *device.ID) (*DeviceMetadata, error)
 		ReplaceDeviceMetadata(ctx context.Context, deviceMetadata *DeviceMetadata, clientIP net.IP) error
 		DeleteAllDeviceMetadata(ctx context.Context, userID string) error
+		// GetDevices lists the devices of the user, the most recently seen first.
+		GetDevices(ctx context.Context, userID string) ([]*Device, error)
+		// DeleteDeviceMetadata deletes the metadata of the device, so its push notification token is revoked too.
+		DeleteDeviceMetadata(ctx context.Context, id *device.ID) error
+		// CreateDeviceAttestationChallenge issues a new one time challenge, for the device, that the next attestation must be bound to.
+		CreateDeviceAttestationChallenge(ctx context.Context, id *device.ID) (*DeviceAttestationChallenge, error)
+		// VerifyDeviceAttestation consumes the device's challenge, verifies the attestation and stores its result in the device's metadata.
+		VerifyDeviceAttestation(ctx context.Context, attestation *DeviceAttestation) error
+		// CheckDeviceAttestation returns ErrDeviceAttestationRequired if the purpose requires a recent valid attestation and the device has none.
+		CheckDeviceAttestation(ctx context.Context, id *device.ID, purpose DeviceAttestationPurpose) error
+		// GetAppVersionRequirements returns the effective requirements of every platform: the runtime ones, if set, otherwise the configured ones.
+		GetAppVersionRequirements(ctx context.Context) ([]*AppVersionRequirement, error)
+		// SetAppVersionRequirement overrides, at runtime, the configured requirement of the platform.
+		SetAppVersionRequirement(ctx context.Context, requirement *AppVersionRequirement) error
+		// CheckDeviceAppVersion returns ErrOutdatedAppVersion if the device's platform is force updated and the device's app is older than required.
+		CheckDeviceAppVersion(ctx context.Context, id *device.ID) error
+		// ReloadableConfigKeys are the keys of the config values applied by ReloadConfig.
+		ReloadableConfigKeys() []string
+		// ReloadConfig applies the latest values of ReloadableConfigKeys, without a restart.
+		ReloadConfig() error
+	}
+	Platform              string
+	AppVersionRequirement struct {
+		UpdatedAt *time.Time `json:"updatedAt,omitempty" example:"2022-01-03T16:20:52.156534Z"`
+		UpdatedBy string     `json:"updatedBy,omitempty" example:"did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"`
+		Platform  Platform   `json:"platform" example:"android" enums:"android,ios"`
+		// MinVersion is the earliest supported version. Devices with an older one can't update their metadata.
+		MinVersion string `json:"minVersion" example:"v1.2.3"`
+		// ForceUpdate makes every write call fail for the devices with an app version older than `MinVersion`.
+		ForceUpdate bool `json:"forceUpdate" example:"true"`
+	}
+	DeviceAttestationPurpose   string
+	DeviceAttestationChallenge struct {
+		ExpiresAt *time.Time `json:"expiresAt" example:"2022-01-03T16:20:52.156534Z"`
+		Challenge string     `json:"challenge" example:"6f2b8c3a1e9d4f7b8a0c5e2d1f3b4a6c"`
+	}
+	DeviceAttestation struct {
+		device.ID `json:"-" swaggerignore:"true"`
+		Provider  deviceattestation.ProviderType `json:"provider" required:"true" example:"playIntegrity" enums:"playIntegrity,appAttest,stub"`
+		// Token is the Play Integrity token or the base64 encoded App Attest attestation object.
+		Token string `json:"token" required:"true" example:"eyJhbGciOiJBMjU2S1ciLCJlbmMiOiJBMjU2R0NNIn0"`
+		// KeyID is the base64 encoded ID of the App Attest key. Required only for `appAttest`.
+		KeyID string `json:"keyId,omitempty" required:"false" example:"bXkga2V5IGlk"`
 	}
 	DeviceLocation struct {
 		Country Country `json:"country,omitempty" example:"US" db:"country"`
 		City    City    `json:"city,omitempty" example:"New York" db:"city"`
 	}
+	// EstimatedDeviceLocation is the DeviceLocation determined from the IP of the device, by one of the configured providers.
+	EstimatedDeviceLocation struct {
+		DeviceLocation
+		// Accuracy is the most precise level that could be determined. It's missing if the location couldn't be determined at all.
+		Accuracy devicelocation.Accuracy `json:"accuracy,omitempty" example:"city" enums:"city,country"`
+	}
+	// Device is the summary of the metadata of one of the devices of an user.
+	Device struct {
+		// When the device was last used, i.e. its last IP was seen or its metadata was updated.
+		LastSeenAt      *time.Time `json:"lastSeenAt,omitempty" example:"2022-01-03T16:20:52.156534Z" db:"last_seen_at"`
+		DeviceUniqueID  string     `json:"deviceUniqueId,omitempty" example:"FCDBD8EF-62FC-4ECB-B2F5-92C9E79AC7F9" db:"device_unique_id"`
+		Brand           string     `json:"brand,omitempty" example:"Apple" db:"brand"`
+		Model           string     `json:"model,omitempty" example:"iPhone14,2" db:"model"`
+		DeviceName      string     `json:"deviceName,omitempty" example:"John's iPhone" db:"device_name"`
+		SystemName      string     `json:"systemName,omitempty" example:"iOS" db:"system_name"`
+		SystemVersion   string     `json:"systemVersion,omitempty" example:"17.1" db:"system_version"`
+		ReadableVersion string     `json:"readableVersion,omitempty" example:"1.2.3.45" db:"readable_version"`
+		// Whether the device has a push notification token. The token itself isn't exposed.
+		PushNotificationsEnabled bool `json:"pushNotificationsEnabled,omitempty" example:"true" db:"push_notifications_enabled"`
+	}
 	//nolint:revive // We don't have a choice if we want to embed it, cuz it will clash with others named "snapshot".
 	DeviceMetadataSnapshot struct {
 		*DeviceMetadata
@@ -52,6 +144,11 @@ type (
 		UpdatedAt        *time.Time `json:"updatedAt,omitempty" swaggertype:"string" db:"updated_at"`
 		FirstInstallTime *time.Time `json:"firstInstallTime,omitempty" swaggertype:"integer" db:"first_install_time"`
 		LastUpdateTime   *time.Time `json:"lastUpdateTime,omitempty" swaggertype:"integer" db:"last_update_time"`
+		AttestedAt       *time.Time `json:"attestedAt,omitempty" swaggerignore:"true" db:"attested_at"`
+		// Read Only. The normalized semver of `readableVersion`, i.e. `v1.2.3`, for the min version gating and analytics.
+		AppVersion string `json:"appVersion,omitempty" swaggerignore:"true" db:"app_version"`
+		// Read Only. The normalized semver of `systemVersion`, i.e. `v17.1.0`, for analytics.
+		OSVersion string `json:"osVersion,omitempty" swaggerignore:"true" db:"os_version"`
 		device.ID
 		ReadableVersion       string `json:"readableVersion,omitempty" db:"readable_version"`
 		Fingerprint           string `json:"fingerprint,omitempty" db:"fingerprint"`
@@ -77,11 +174,19 @@ type (
 		InstallerPackageName  string `json:"installerPackageName,omitempty" db:"installer_package_name"`
 		PushNotificationToken string `json:"pushNotificationToken,omitempty" db:"push_notification_token"`
 		TZ                    string `json:"tz,omitempty" db:"device_timezone"`
+		AttestationProvider   string `json:"attestationProvider,omitempty" swaggerignore:"true" db:"attestation_provider"`
+		// The build number of the app, i.e. the `versionCode` on android or the `CFBundleVersion` on ios.
+		AppBuildNumber string `json:"appBuildNumber,omitempty" example:"45" db:"app_build_number"`
+		// The BCP 47 locale of the device. It's normalized, i.e. `en_us` becomes `en-US`.
+		Locale string `json:"locale,omitempty" example:"en-US" db:"locale"`
 		ip2LocationRecord
-		APILevel            uint64 `json:"apiLevel,omitempty" db:"api_level"`
-		Tablet              bool   `json:"tablet,omitempty" db:"tablet"`
-		PinOrFingerprintSet bool   `json:"pinOrFingerprintSet,omitempty" db:"pin_or_fingerprint_set"`
-		Emulator            bool   `json:"emulator,omitempty" db:"emulator"`
+		// The pixel ratio of the screen, i.e. `1`, `1.5`, `2`, `3`.
+		ScreenDensity       float64 `json:"screenDensity,omitempty" example:"3" db:"screen_density"`
+		APILevel            uint64  `json:"apiLevel,omitempty" db:"api_level"`
+		Tablet              bool    `json:"tablet,omitempty" db:"tablet"`
+		PinOrFingerprintSet bool    `json:"pinOrFingerprintSet,omitempty" db:"pin_or_fingerprint_set"`
+		Emulator            bool    `json:"emulator,omitempty" db:"emulator"`
+		AttestationPassed   bool    `json:"attestationPassed,omitempty" swaggerignore:"true" db:"attestation_passed"`
 	}
 )
 
@@ -89,13 +194,27 @@ type (
 
 const (
 	applicationYamlKey = "users"
+
+	maxScreenDensity = 10
 )
 
 var (
 	//nolint:gochecknoglobals // Because its loaded once, at runtime.
 	countries map[Country]*country
+	//nolint:gochecknoglobals // Stateless compiled regexes, used for validating and normalizing the device metadata.
+	readableVersionRegex = regexp.MustCompile(`^v?(\d{1,9})\.(\d{1,9})\.(\d{1,9})(?:\.\d{1,9})?$`)
+	//nolint:gochecknoglobals // Stateless compiled regexes, used for validating and normalizing the device metadata.
+	systemVersionRegex = regexp.MustCompile(`^(\d{1,9})(?:\.(\d{1,9}))?(?:\.(\d{1,9}))?(?:\.\d{1,9})?$`)
+	//nolint:gochecknoglobals // Stateless compiled regexes, used for validating and normalizing the device metadata.
+	appBuildNumberRegex = regexp.MustCompile(`^\d{1,9}(?:\.\d{1,9}){0,3}$`)
+	//nolint:gochecknoglobals // Stateless compiled regexes, used for validating and normalizing the device metadata.
+	localeRegex = regexp.MustCompile(`^[a-zA-Z]{2,3}(?:[-_][a-zA-Z0-9]{2,8}){0,3}$`)
 	//go:embed countries.json
 	countriesJSON string
+	//nolint:gochecknoglobals // Because its loaded once, at runtime.
+	gazetteer map[Country]map[string]City
+	//go:embed cities.json
+	citiesJSON string
 )
 
 type (
@@ -121,6 +240,23 @@ type (
 		Longitude          float64 `json:"-" swaggerignore:"true" db:"longitude"`
 		Elevation          float64 `json:"-" swaggerignore:"true" db:"elevation"`
 	}
+	appVersionRequirements struct {
+		loadedAt   *time.Time
+		byPlatform map[Platform]*AppVersionRequirement
+		mx         sync.RWMutex
+	}
+	deviceAttestationChallenge struct {
+		CreatedAt      *time.Time
+		UserID         string
+		DeviceUniqueID string
+		Challenge      string
+	}
+	// | gazetteerCity is a city of the bundled gazetteer, with the other spellings it's known by.
+	gazetteerCity struct {
+		Country Country `json:"country"`
+		Name    City    `json:"name"`
+		Aliases []City  `json:"aliases"`
+	}
 	country struct {
 		Name    string `json:"name"`
 		Flag    string `json:"flag"`
@@ -136,11 +272,36 @@ type (
 		IP2LocationBinaryPath string                   `yaml:"ip2LocationBinaryPath"`
 		messagebroker.Config  `mapstructure:",squash"` //nolint:tagliatelle // Nope.
 		SkipIP2LocationBinary bool                     `yaml:"skipIp2LocationBinary"`
+
+		DeviceAttestation struct {
+			RequiredFor struct {
+				KYC  bool `yaml:"kyc"`
+				Auth bool `yaml:"auth"`
+			} `yaml:"requiredFor"`
+			ChallengeTTL stdlibtime.Duration `yaml:"challengeTtl"`
+			// MaxAge is how long a valid attestation is considered recent.
+			MaxAge stdlibtime.Duration `yaml:"maxAge"`
+		} `yaml:"deviceAttestation"`
+
+		AppVersionRequirements struct {
+			ForceUpdate struct {
+				Android bool `yaml:"android"`
+				IOS     bool `yaml:"ios"`
+			} `yaml:"forceUpdate"`
+			// CacheTTL is how often the runtime requirements are reloaded from the database.
+			CacheTTL stdlibtime.Duration `yaml:"cacheTtl"`
+		} `yaml:"appVersionRequirements"`
+
+		// reloaded is the config with the latest values of the reloadable keys, once they're reloaded at runtime.
+		reloaded *atomic.Pointer[config]
 	}
 	repository struct {
-		cfg           *config
-		db            *storage.DB
-		mb            messagebroker.Client
-		ip2LocationDB *ip2location.DB
+		cfg                 *config
+		db                  *storage.DB
+		mb                  messagebroker.Client
+		ip2LocationDB       *ip2location.DB
+		attestationVerifier deviceattestation.Verifier
+		locator             devicelocation.Provider
+		appVersions         *appVersionRequirements
 	}
 )
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	stdlibtime "time"
 
 	"github.com/goccy/go-json"
@@ -20,6 +21,8 @@ import (
 	"golang.org/x/mod/semver"
 
 	"github.com/ice-blockchain/eskimo/users/internal/device"
+	deviceattestation "github.com/ice-blockchain/eskimo/users/internal/device/attestation"
+	devicelocation "github.com/ice-blockchain/eskimo/users/internal/device/location"
 	appcfg "github.com/ice-blockchain/wintr/config"
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
 	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
@@ -40,17 +43,23 @@ func init() {
 	if len(countries) != 250 { //nolint:gomnd // We have 250 countries in ip2location
 		log.Panic(errors.Errorf("invalid number of countries %v. Expected 250", len(countries)))
 	}
+	gazetteer = loadGazetteer()
 }
 
 func New(db *storage.DB, mb messagebroker.Client) DeviceMetadataRepository {
 	var cfg config
 	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
-	repo := &repository{db: db, mb: mb, cfg: &cfg}
+	cfg.reloaded = new(atomic.Pointer[config])
+	repo := &repository{db: db, mb: mb, cfg: &cfg, appVersions: new(appVersionRequirements)}
 	if mb != nil && !cfg.SkipIP2LocationBinary {
 		var err error
 		repo.ip2LocationDB, err = ip2location.OpenDB(cfg.IP2LocationBinaryPath)
 		log.Panic(errors.Wrap(err, "unable to open ip2location database"))
 	}
+	if mb != nil {
+		repo.attestationVerifier = deviceattestation.New(applicationYamlKey)
+		repo.locator = devicelocation.New(applicationYamlKey, repo.ip2LocationDB)
+	}
 
 	return repo
 }
@@ -86,6 +95,42 @@ func (r *repository) DeleteAllDeviceMetadata(ctx context.Context, userID string)
 	return multierror.Append(nil, errs...).ErrorOrNil() //nolint:wrapcheck // Not needed.
 }
 
+func (r *repository) GetDevices(ctx context.Context, userID string) ([]*Device, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sql := `SELECT GREATEST(dm.updated_at, (SELECT max(ips.last_seen_at)
+											FROM device_metadata_ips ips
+											WHERE ips.user_id = dm.user_id
+											  AND ips.device_unique_id = dm.device_unique_id)) AS last_seen_at,
+				   dm.device_unique_id,
+				   COALESCE(dm.brand, '') AS brand,
+				   COALESCE(dm.device_id, '') AS model,
+				   COALESCE(dm.device_name, '') AS device_name,
+				   COALESCE(dm.system_name, '') AS system_name,
+				   COALESCE(dm.system_version, '') AS system_version,
+				   COALESCE(dm.readable_version, '') AS readable_version,
+				   COALESCE(dm.push_notification_token, '') != '' AS push_notifications_enabled
+			FROM device_metadata dm
+			WHERE dm.user_id = $1
+			ORDER BY last_seen_at DESC NULLS LAST, dm.device_unique_id`
+	devices, err := storage.Select[Device](ctx, r.db, sql, userID)
+
+	return devices, errors.Wrapf(err, "failed to select the devices of userID:%v", userID)
+}
+
+func (r *repository) DeleteDeviceMetadata(ctx context.Context, id *device.ID) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	dm, err := r.GetDeviceMetadata(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to GetDeviceMetadata for %#v", id)
+	}
+
+	return errors.Wrapf(r.deleteDeviceMetadata(ctx, dm), "failed to deleteDeviceMetadata for %#v", dm)
+}
+
 func (r *repository) deleteDeviceMetadata(ctx context.Context, dm *DeviceMetadata) error {
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "context failed")
@@ -113,25 +158,27 @@ func (r *repository) Close() error {
 	return nil
 }
 
-func (r *repository) GetDeviceMetadataLocation(ctx context.Context, deviceID *device.ID, clientIP net.IP) *DeviceLocation {
+func (r *repository) GetDeviceMetadataLocation(ctx context.Context, deviceID *device.ID, clientIP net.IP) *EstimatedDeviceLocation {
 	if ctx.Err() != nil {
 		log.Error(errors.Wrapf(ctx.Err(), "context error for GetDeviceMetadataLocation for %#v", deviceID))
 
-		return new(DeviceLocation)
+		return new(EstimatedDeviceLocation)
 	}
 	//nolint:godox // .
 	// TODO: TBD if we need to use deviceID.DeviceUniqueID and/or deviceID.UserID to find some default/preferred value for the user.
-
-	result, err := r.ip2LocationDB.Get_all(clientIP.String()) //nolint:nosnakecase // External library.
+	if r.locator == nil {
+		return new(EstimatedDeviceLocation)
+	}
+	loc, err := r.locator.Locate(ctx, clientIP)
 	if err != nil {
 		log.Error(errors.Wrapf(err, "unable to get country&city for %#v, %v", deviceID, clientIP.String()))
 
-		return new(DeviceLocation)
+		return new(EstimatedDeviceLocation)
 	}
 
-	return &DeviceLocation{
-		Country: strings.ToUpper(result.Country_short), //nolint:nosnakecase // External library.
-		City:    result.City,
+	return &EstimatedDeviceLocation{
+		DeviceLocation: DeviceLocation{Country: loc.Country, City: loc.City},
+		Accuracy:       loc.Accuracy,
 	}
 }
 
@@ -155,6 +202,12 @@ func (r *repository) ReplaceDeviceMetadata(ctx context.Context, input *DeviceMet
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "context failed")
 	}
+	if err = r.loadAppVersionRequirements(ctx, false); err != nil {
+		return errors.Wrap(err, "failed to loadAppVersionRequirements")
+	}
+	if nErr := input.normalize(); nErr != nil {
+		return nErr
+	}
 	if vErr := r.verifyDeviceAppVersion(input); vErr != nil {
 		return vErr
 	}
@@ -167,10 +220,16 @@ func (r *repository) ReplaceDeviceMetadata(ctx context.Context, input *DeviceMet
 		return errors.Wrapf(err, "failed to get location information based on IP %v to replace device metadata", clientIP.String())
 	}
 	(&input.ip2LocationRecord).convertIP2Location(&ip2locationRecord)
+	if err = r.upsertDeviceIP(ctx, &input.ID, input.UpdatedAt, clientIP); err != nil {
+		return errors.Wrapf(err, "failed to upsertDeviceIP for %#v", input.ID)
+	}
 	before, err := r.GetDeviceMetadata(ctx, &input.ID)
 	if err != nil && !storage.IsErr(err, storage.ErrNotFound) {
 		return errors.Wrapf(err, "failed to get current device metadata for %#v", input.ID)
 	}
+	if before != nil { // They're not replaceable by the client, so we keep them as they are.
+		input.AttestedAt, input.AttestationProvider, input.AttestationPassed = before.AttestedAt, before.AttestationProvider, before.AttestationPassed
+	}
 
 	sql, args := input.replaceSQL()
 	if _, err = storage.Exec(ctx, r.db, sql, args...); err != nil {
@@ -195,19 +254,25 @@ func (r *repository) ReplaceDeviceMetadata(ctx context.Context, input *DeviceMet
 	return nil
 }
 
+// upsertDeviceIP keeps track of the IPs used by each device, so that we can detect accounts sharing them.
+func (r *repository) upsertDeviceIP(ctx context.Context, id *device.ID, now *time.Time, clientIP net.IP) error {
+	if clientIP == nil {
+		return nil
+	}
+	sql := `INSERT INTO device_metadata_ips (last_seen_at, user_id, device_unique_id, ip) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, device_unique_id, ip) DO UPDATE
+				SET last_seen_at = EXCLUDED.last_seen_at`
+	_, err := storage.Exec(ctx, r.db, sql, now.Time, id.UserID, id.DeviceUniqueID, clientIP.String())
+
+	return errors.Wrapf(err, "failed to upsert device ip %v for %#v", clientIP, id)
+}
+
 func (r *repository) verifyDeviceAppVersion(metadata *DeviceMetadata) error {
 	readableParts := strings.Split(metadata.ReadableVersion, ".")
 	if len(readableParts) < 1+1+1 {
 		return errors.Wrapf(ErrInvalidAppVersion, "invalid version %v", metadata.ReadableVersion)
 	}
-	var requiredAppVersion string
-	if os := strings.ReplaceAll(strings.ToLower(metadata.SystemName), " ", ""); os == "android" {
-		requiredAppVersion = r.cfg.RequiredAppVersion.Android
-	} else if os == "ios" || os == "iphoneos" || os == "ipados" {
-		requiredAppVersion = r.cfg.RequiredAppVersion.IOS
-	} else {
-		requiredAppVersion = r.cfg.RequiredAppVersion.Android
-	}
+	requiredAppVersion := r.appVersionRequirement(platformOf(metadata.SystemName)).MinVersion
 	if semver.Compare(strings.ReplaceAll(fmt.Sprintf("v%v.%v.%v", readableParts[0], readableParts[1], readableParts[2]), "vv", "v"), requiredAppVersion) < 0 {
 		return errors.Wrapf(ErrOutdatedAppVersion,
 			"mobile app version %v is older than the required one %v, please update", metadata.ReadableVersion, requiredAppVersion)
@@ -221,7 +286,7 @@ func (r *repository) verifyDeviceAppNanosVersion(requiredAppVersion string, read
 	requiredParts := strings.Split(requiredAppVersion, ".")
 	if len(requiredParts) > 1+1+1 && len(readableParts) == 1+1+1 {
 		return errors.Wrapf(ErrOutdatedAppVersion,
-			"mobile app version doesn't contain nanos that is required %v, please update", r.cfg.RequiredAppVersion)
+			"mobile app version doesn't contain nanos that is required %v, please update", requiredAppVersion)
 	}
 	if len(requiredParts) > 1+1+1 && len(readableParts) > 1+1+1 {
 		readableNano, err := strconv.Atoi(readableParts[3])
@@ -301,7 +366,12 @@ func (dm *DeviceMetadata) replaceSQL() (string, []any) {
 				api_level,
 				tablet,
 				pin_or_fingerprint_set,
-				emulator
+				emulator,
+				app_version,
+				os_version,
+				app_build_number,
+				locale,
+				screen_density
 			) VALUES (
 				$1,
 				$2,
@@ -355,7 +425,12 @@ func (dm *DeviceMetadata) replaceSQL() (string, []any) {
 				$50,
 				$51,
 				$52,
-				$53
+				$53,
+				$54,
+				$55,
+				$56,
+				$57,
+				$58
 			)
 			ON CONFLICT(user_id, device_unique_id)
 				DO UPDATE
@@ -410,7 +485,12 @@ func (dm *DeviceMetadata) replaceSQL() (string, []any) {
 						api_level 				= EXCLUDED.api_level,
 						tablet 					= EXCLUDED.tablet,
 						pin_or_fingerprint_set 	= EXCLUDED.pin_or_fingerprint_set,
-						emulator 				= EXCLUDED.emulator
+						emulator 				= EXCLUDED.emulator,
+						app_version 			= EXCLUDED.app_version,
+						os_version 				= EXCLUDED.os_version,
+						app_build_number 		= EXCLUDED.app_build_number,
+						locale 					= EXCLUDED.locale,
+						screen_density 			= EXCLUDED.screen_density
 				WHERE 	 COALESCE(DEVICE_METADATA.country_short, '') 				   != coalesce(EXCLUDED.country_short, '')
 					  OR COALESCE(DEVICE_METADATA.country_long, '') 			 	   != coalesce(EXCLUDED.country_long, '')
 					  OR COALESCE(DEVICE_METADATA.region, '') 					 	   != coalesce(EXCLUDED.region, '')
@@ -461,7 +541,12 @@ func (dm *DeviceMetadata) replaceSQL() (string, []any) {
 					  OR COALESCE(DEVICE_METADATA.api_level, 0) 				 	   != coalesce(EXCLUDED.api_level, 0)
 					  OR COALESCE(DEVICE_METADATA.tablet, FALSE) 				 	   != coalesce(EXCLUDED.tablet, FALSE)
 					  OR COALESCE(DEVICE_METADATA.pin_or_fingerprint_set, FALSE) 	   != coalesce(EXCLUDED.pin_or_fingerprint_set, FALSE)
-					  OR COALESCE(DEVICE_METADATA.emulator, FALSE) 				 	   != coalesce(EXCLUDED.emulator, FALSE)`
+					  OR COALESCE(DEVICE_METADATA.emulator, FALSE) 				 	   != coalesce(EXCLUDED.emulator, FALSE)
+					  OR COALESCE(DEVICE_METADATA.app_version, '') 				 	   != coalesce(EXCLUDED.app_version, '')
+					  OR COALESCE(DEVICE_METADATA.os_version, '') 				 	   != coalesce(EXCLUDED.os_version, '')
+					  OR COALESCE(DEVICE_METADATA.app_build_number, '') 			   != coalesce(EXCLUDED.app_build_number, '')
+					  OR COALESCE(DEVICE_METADATA.locale, '') 					 	   != coalesce(EXCLUDED.locale, '')
+					  OR COALESCE(DEVICE_METADATA.screen_density, 0) 			 	   != coalesce(EXCLUDED.screen_density, 0)`
 	args := []any{
 		dm.CountryShort,
 		dm.CountryLong,
@@ -516,6 +601,11 @@ func (dm *DeviceMetadata) replaceSQL() (string, []any) {
 		dm.Tablet,
 		dm.PinOrFingerprintSet,
 		dm.Emulator,
+		dm.AppVersion,
+		dm.OSVersion,
+		dm.AppBuildNumber,
+		dm.Locale,
+		dm.ScreenDensity,
 	}
 
 	return sql, args
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ice-blockchain/eskimo/users/internal/device"
+	"github.com/ice-blockchain/wintr/terror"
 	. "github.com/ice-blockchain/wintr/testing"
 	"github.com/ice-blockchain/wintr/time"
 )
@@ -56,9 +57,36 @@ func TestAPIContract(t *testing.T) { //nolint:funlen // You can collapse em.
 			Tablet:                true,
 			PinOrFingerprintSet:   true,
 			Emulator:              true,
+			AppVersion:            "v1.2.3",
+			OSVersion:             "v17.1.0",
+			AppBuildNumber:        "a24",
+			Locale:                "a25",
+			ScreenDensity:         1.5,
 		}
 		dmSnapshot = &DeviceMetadataSnapshot{DeviceMetadata: dm, Before: dm}
+		dev        = &Device{
+			LastSeenAt:               datetime,
+			DeviceUniqueID:           "b",
+			Brand:                    "a12",
+			Model:                    "a9",
+			DeviceName:               "a11",
+			SystemName:               "a16",
+			SystemVersion:            "a17",
+			ReadableVersion:          "a1",
+			PushNotificationsEnabled: true,
+		}
 	)
+	AssertSymmetricMarshallingUnmarshalling(t, dev, `{
+														"lastSeenAt": "2022-08-05T22:07:22.969Z",
+														"deviceUniqueId": "b",
+														"brand": "a12",
+														"model": "a9",
+														"deviceName": "a11",
+														"systemName": "a16",
+														"systemVersion": "a17",
+														"readableVersion": "a1",
+														"pushNotificationsEnabled": true
+													 }`)
 	AssertSymmetricMarshallingUnmarshalling(t, deviceID, `{
 															  "userId": "a",
 															  "deviceUniqueId": "b"
@@ -71,6 +99,8 @@ func TestAPIContract(t *testing.T) { //nolint:funlen // You can collapse em.
 													  "updatedAt": "2022-08-05T22:07:22.969Z",
 													  "firstInstallTime": "2022-08-05T22:07:22.969Z",
 													  "lastUpdateTime": "2022-08-05T22:07:22.969Z",
+													  "appVersion": "v1.2.3",
+													  "osVersion": "v17.1.0",
 													  "userId": "a",
 													  "deviceUniqueId": "b",
 													  "readableVersion": "a1",
@@ -97,6 +127,9 @@ func TestAPIContract(t *testing.T) { //nolint:funlen // You can collapse em.
 													  "installerPackageName": "a22",
 													  "pushNotificationToken": "a23",
 													  "tz": "-07:00",
+													  "appBuildNumber": "a24",
+													  "locale": "a25",
+													  "screenDensity": 1.5,
 													  "apiLevel": 1,
 													  "tablet": true,
 													  "pinOrFingerprintSet": true,
@@ -106,6 +139,8 @@ func TestAPIContract(t *testing.T) { //nolint:funlen // You can collapse em.
 													  			  "updatedAt": "2022-08-05T22:07:22.969Z",
 																  "firstInstallTime": 1659737242969,
 																  "lastUpdateTime": 1659737242969,
+																  "appVersion": "v1.2.3",
+																  "osVersion": "v17.1.0",
 																  "userId": "a",
 																  "deviceUniqueId": "b",
 																  "readableVersion": "a1",
@@ -132,6 +167,9 @@ func TestAPIContract(t *testing.T) { //nolint:funlen // You can collapse em.
 																  "installerPackageName": "a22",
 																  "pushNotificationToken": "a23",
 													  			  "tz": "-07:00",
+																  "appBuildNumber": "a24",
+																  "locale": "a25",
+																  "screenDensity": 1.5,
 																  "apiLevel": 1,
 																  "tablet": true,
 																  "pinOrFingerprintSet": true,
@@ -141,6 +179,8 @@ func TestAPIContract(t *testing.T) { //nolint:funlen // You can collapse em.
 															  "updatedAt": "2022-08-05T22:07:22.969Z",
 															  "firstInstallTime": "2022-08-05T22:07:22.969Z",
 															  "lastUpdateTime": "2022-08-05T22:07:22.969Z",
+															  "appVersion": "v1.2.3",
+															  "osVersion": "v17.1.0",
 															  "userId": "a",
 															  "deviceUniqueId": "b",
 															  "readableVersion": "a1",
@@ -167,6 +207,9 @@ func TestAPIContract(t *testing.T) { //nolint:funlen // You can collapse em.
 															  "installerPackageName": "a22",
 															  "pushNotificationToken": "a23",
 													  		  "tz": "-07:00",
+															  "appBuildNumber": "a24",
+															  "locale": "a25",
+															  "screenDensity": 1.5,
 															  "apiLevel": 1,
 															  "tablet": true,
 															  "pinOrFingerprintSet": true,
@@ -175,6 +218,8 @@ func TestAPIContract(t *testing.T) { //nolint:funlen // You can collapse em.
 																"updatedAt": "2022-08-05T22:07:22.969Z",
 																"firstInstallTime": "2022-08-05T22:07:22.969Z",
 																"lastUpdateTime": "2022-08-05T22:07:22.969Z",
+																"appVersion": "v1.2.3",
+																"osVersion": "v17.1.0",
 																"userId": "a",
 																"deviceUniqueId": "b",
 																"readableVersion": "a1",
@@ -201,6 +246,9 @@ func TestAPIContract(t *testing.T) { //nolint:funlen // You can collapse em.
 																"installerPackageName": "a22",
 																"pushNotificationToken": "a23",
 													  		    "tz": "-07:00",
+																"appBuildNumber": "a24",
+																"locale": "a25",
+																"screenDensity": 1.5,
 																"apiLevel": 1,
 																"tablet": true,
 																"pinOrFingerprintSet": true,
@@ -314,3 +362,76 @@ func TestVerifyDeviceAppVersion(t *testing.T) { //nolint:funlen // .
 	md.SystemName = "iOS"
 	require.Error(t, repo.verifyDeviceAppVersion(&md))
 }
+
+func TestVerifyDeviceAppVersionWithRuntimeRequirement(t *testing.T) {
+	t.Parallel()
+	repo := repository{cfg: new(config), appVersions: new(appVersionRequirements)}
+	repo.cfg.RequiredAppVersion.Android = "v0.0.1"
+	md := DeviceMetadata{SystemName: "Android", ReadableVersion: "v0.0.2"}
+	require.NoError(t, repo.verifyDeviceAppVersion(&md))
+
+	repo.appVersions.byPlatform = map[Platform]*AppVersionRequirement{AndroidPlatform: {Platform: AndroidPlatform, MinVersion: "v0.0.3"}}
+	require.ErrorIs(t, repo.verifyDeviceAppVersion(&md), ErrOutdatedAppVersion)
+
+	md.SystemName = "iPadOS"
+	require.NoError(t, repo.verifyDeviceAppVersion(&md))
+}
+
+func TestIsValidRequiredAppVersion(t *testing.T) {
+	t.Parallel()
+	for _, version := range []string{"v0.0.1", "v1.22.333", "v1.2.3.4", "v1.2.3.0"} {
+		assert.True(t, isValidRequiredAppVersion(version), version)
+	}
+	for _, version := range []string{"", "v1", "v1.2", "1.2.3", "v1.2.3.a", "v1.2.3-rc1", "v01.2.3", "v1.2.3.4.5", "v1.2.3.-1"} {
+		assert.False(t, isValidRequiredAppVersion(version), version)
+	}
+}
+
+func TestNormalizeDeviceMetadata(t *testing.T) {
+	t.Parallel()
+	dm := DeviceMetadata{
+		ReadableVersion: "v01.2.3.45",
+		SystemVersion:   " 17.1 ",
+		AppBuildNumber:  "45",
+		Locale:          "zh_hant_tw",
+		ScreenDensity:   2.75,
+	}
+	require.NoError(t, dm.normalize())
+	assert.Equal(t, "v1.2.3", dm.AppVersion)
+	assert.Equal(t, "17.1", dm.SystemVersion)
+	assert.Equal(t, "v17.1.0", dm.OSVersion)
+	assert.Equal(t, "zh-Hant-TW", dm.Locale)
+
+	dm = DeviceMetadata{ReadableVersion: "1.2.3", Locale: "es-419"}
+	require.NoError(t, dm.normalize())
+	assert.Equal(t, "v1.2.3", dm.AppVersion)
+	assert.Empty(t, dm.OSVersion)
+	assert.Equal(t, "es-419", dm.Locale)
+
+	dm = DeviceMetadata{ReadableVersion: "1.2.x", SystemVersion: "seventeen", AppBuildNumber: "45b", Locale: "english!", ScreenDensity: -1}
+	err := dm.normalize()
+	require.ErrorIs(t, err, ErrInvalidDeviceMetadata)
+	tErr := terror.As(err)
+	require.NotNil(t, tErr)
+	assert.EqualValues(t, []string{"readableVersion", "systemVersion", "appBuildNumber", "locale", "screenDensity"}, tErr.Data["fields"])
+}
+
+func TestCanonicalizeLocation(t *testing.T) {
+	t.Parallel()
+	r := new(repository)
+	for input, expected := range map[Country]Country{"ro": "RO", " Romania ": "RO", "RO": "RO"} {
+		actual, valid := r.CanonicalCountry(input)
+		assert.True(t, valid, input)
+		assert.Equal(t, expected, actual, input)
+	}
+	_, valid := r.CanonicalCountry("XX")
+	assert.False(t, valid)
+
+	assert.Equal(t, "Mumbai", r.CanonicalCity("in", "bombay"))
+	assert.Equal(t, "New York", r.CanonicalCity("US", "  nyc "))
+	assert.Equal(t, "New York", r.CanonicalCity("US", "new   york"))
+	assert.Equal(t, "Springfield", r.CanonicalCity("US", "SPRINGFIELD"))
+	assert.Equal(t, "Winston-Salem", r.CanonicalCity("US", "winston-salem"))
+	assert.Equal(t, "McAllen", r.CanonicalCity("US", "McAllen"))
+	assert.Empty(t, r.CanonicalCity("US", " "))
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package devicemetadata
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/terror"
+)
+
+// normalize validates the client provided properties, at once, and normalizes them in place,
+// so that the min version gating and analytics don't have to deal with all the formats out there.
+// Except `readableVersion`, they're all optional, for the older apps, so they're validated only if set.
+func (dm *DeviceMetadata) normalize() error {
+	var invalid []string
+	if appVersion, ok := semverOf(readableVersionRegex, dm.ReadableVersion); ok {
+		dm.AppVersion = appVersion
+	} else {
+		invalid = append(invalid, "readableVersion")
+	}
+	dm.OSVersion = ""
+	if dm.SystemVersion = strings.TrimSpace(dm.SystemVersion); dm.SystemVersion != "" {
+		if osVersion, ok := semverOf(systemVersionRegex, dm.SystemVersion); ok {
+			dm.OSVersion = osVersion
+		} else {
+			invalid = append(invalid, "systemVersion")
+		}
+	}
+	if dm.AppBuildNumber = strings.TrimSpace(dm.AppBuildNumber); dm.AppBuildNumber != "" && !appBuildNumberRegex.MatchString(dm.AppBuildNumber) {
+		invalid = append(invalid, "appBuildNumber")
+	}
+	if dm.Locale = strings.TrimSpace(dm.Locale); dm.Locale != "" {
+		if localeRegex.MatchString(dm.Locale) {
+			dm.Locale = normalizeLocale(dm.Locale)
+		} else {
+			invalid = append(invalid, "locale")
+		}
+	}
+	if dm.ScreenDensity < 0 || dm.ScreenDensity > maxScreenDensity || math.IsNaN(dm.ScreenDensity) {
+		invalid = append(invalid, "screenDensity")
+	}
+	if len(invalid) != 0 {
+		return errors.Wrapf(terror.New(ErrInvalidDeviceMetadata, map[string]any{"fields": invalid}), "invalid properties %v", invalid)
+	}
+
+	return nil
+}
+
+// semverOf returns the `vMAJOR.MINOR.PATCH` of the version, if it matches the regex, whose groups are the major, minor and patch.
+// The missing minor and patch default to 0 and the leading zeros are dropped, as semver doesn't allow them.
+func semverOf(regex *regexp.Regexp, version string) (string, bool) {
+	matches := regex.FindStringSubmatch(version)
+	if matches == nil {
+		return "", false
+	}
+	parts := make([]any, 0, 1+1+1)
+	for _, match := range matches[1:] {
+		var part uint64
+		if match != "" {
+			var err error
+			if part, err = strconv.ParseUint(match, 10, 64); err != nil {
+				return "", false
+			}
+		}
+		parts = append(parts, part)
+	}
+
+	return fmt.Sprintf("v%v.%v.%v", parts...), true
+}
+
+// normalizeLocale follows the BCP 47 casing conventions: `en_us` becomes `en-US` and `zh_hant_tw` becomes `zh-Hant-TW`.
+func normalizeLocale(locale string) string {
+	subtags := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	for ix, subtag := range subtags {
+		switch {
+		case ix == 0:
+			subtags[ix] = strings.ToLower(subtag)
+		case len(subtag) == 4: //nolint:gomnd // Scripts, like `Hant`.
+			subtags[ix] = strings.ToUpper(subtag[:1]) + strings.ToLower(subtag[1:])
+		case len(subtag) == 2 || (len(subtag) == 1+1+1 && subtag[0] >= '0' && subtag[0] <= '9'): // Regions, like `US` or `419`.
+			subtags[ix] = strings.ToUpper(subtag)
+		default:
+			subtags[ix] = strings.ToLower(subtag)
+		}
+	}
+
+	return strings.Join(subtags, "-")
+}
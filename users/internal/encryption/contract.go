@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package encryption
+
+import (
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+)
+
+// Public API.
+
+var (
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+	ErrUnknownKey        = errors.New("unknown key")
+)
+
+type (
+	// Cipher encrypts the PII that has to be encrypted at rest, with envelope encryption:
+	// every value is encrypted with its own data key, which is encrypted (wrapped) with the primary key encryption key.
+	Cipher interface {
+		Encrypt(plaintext string) (ciphertext string, err error)
+		// Decrypt fails with ErrUnknownKey if the key the ciphertext was encrypted with isn't configured anymore
+		// and with ErrInvalidCiphertext if it was tampered with.
+		Decrypt(ciphertext string) (plaintext string, err error)
+		// BlindIndex returns a deterministic keyed hash of the plaintext, so that the encrypted values can be looked up, or be unique.
+		BlindIndex(plaintext string) string
+		// PrimaryKeyPrefix is the prefix of the ciphertexts encrypted with the primary key,
+		// so that the ones that need to be re-encrypted, after it's rotated, can be found without decrypting them.
+		PrimaryKeyPrefix() string
+	}
+	// KeyEncryptionKey wraps the data keys. It can be backed by a KMS, or be a local key, sourced from it.
+	KeyEncryptionKey interface {
+		ID() string
+		Wrap(dataKey []byte) (wrapped []byte, err error)
+		Unwrap(wrapped []byte) (dataKey []byte, err error)
+	}
+	// VaultTransit is the transit secrets engine of a HashiCorp Vault (or OpenBao) server, used as the KMS.
+	VaultTransit struct {
+		URL string `yaml:"url"`
+		// Mount is the path the engine is mounted at. It defaults to `transit`.
+		Mount string `yaml:"mount"`
+		Token string `yaml:"token"`
+	}
+)
+
+// Private API.
+
+const (
+	keysEnv                  = "PII_ENCRYPTION_KEYS"            //nolint:gosec // It's the name of the env var.
+	primaryKeyIDEnv          = "PII_ENCRYPTION_PRIMARY_KEY_ID"  //nolint:gosec // It's the name of the env var.
+	blindIndexKeyEnv         = "PII_ENCRYPTION_BLIND_INDEX_KEY" //nolint:gosec // It's the name of the env var.
+	kmsTokenEnv              = "PII_ENCRYPTION_KMS_TOKEN"       //nolint:gosec // It's the name of the env var.
+	kmsDeadline              = 5 * stdlibtime.Second
+	defaultVaultTransitMount = "transit"
+	keySize                  = 32
+	// ciphertextVersion prefixes the ciphertexts, followed by the ID of the key encryption key, the wrapped data key and the sealed plaintext.
+	ciphertextVersion   = "pii1"
+	ciphertextSeparator = "."
+)
+
+type (
+	envelopeCipher struct {
+		keys          map[string]KeyEncryptionKey
+		primaryKey    KeyEncryptionKey
+		blindIndexKey []byte
+	}
+	localKey struct {
+		id  string
+		key []byte
+	}
+	// vaultTransitKey never leaves the KMS: the data keys are wrapped and unwrapped by its API.
+	vaultTransitKey struct {
+		transit *VaultTransit
+		id      string
+		name    string
+	}
+	vaultTransitRequest struct {
+		Plaintext  string `json:"plaintext,omitempty"`
+		Ciphertext string `json:"ciphertext,omitempty"`
+	}
+	vaultTransitResponse struct {
+		Data struct {
+			Plaintext  string `json:"plaintext"`
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	vaultError struct {
+		Errors []string `json:"errors"`
+	}
+	// | config holds the configuration of this package mounted from `application.yaml`.
+	config struct {
+		PIIEncryption struct {
+			// Keys are the base64 encoded AES-256 key encryption keys, by their ID.
+			// They can also be provided via the `PII_ENCRYPTION_KEYS` env var, as `id1:key1,id2:key2`.
+			Keys map[string]string `yaml:"keys"`
+			// PrimaryKeyID is the ID of the key the new values are encrypted with. The others are only used for decrypting.
+			PrimaryKeyID string `yaml:"primaryKeyId"` //nolint:tagliatelle // Nope.
+			KMS          struct {
+				// Keys are the names, in the KMS, of the key encryption keys that never leave it, by their ID.
+				Keys         map[string]string `yaml:"keys"`
+				VaultTransit VaultTransit      `yaml:"vaultTransit"`
+			} `yaml:"kms"`
+			// BlindIndexKey is the base64 encoded key of the blind indexes. It can't be rotated, since the indexes would change.
+			BlindIndexKey string `yaml:"blindIndexKey"`
+		} `yaml:"piiEncryption"`
+	}
+)
@@ -0,0 +1,340 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// New returns the cipher with the configured keys, or nil if there are none.
+func New(applicationYAMLKey string) Cipher {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+	if len(cfg.PIIEncryption.Keys) == 0 {
+		cfg.PIIEncryption.Keys = parseKeys(loadFromEnv(applicationYAMLKey, keysEnv))
+	}
+	if cfg.PIIEncryption.PrimaryKeyID == "" {
+		cfg.PIIEncryption.PrimaryKeyID = loadFromEnv(applicationYAMLKey, primaryKeyIDEnv)
+	}
+	if cfg.PIIEncryption.BlindIndexKey == "" {
+		cfg.PIIEncryption.BlindIndexKey = loadFromEnv(applicationYAMLKey, blindIndexKeyEnv)
+	}
+	if cfg.PIIEncryption.KMS.VaultTransit.Token == "" {
+		cfg.PIIEncryption.KMS.VaultTransit.Token = loadFromEnv(applicationYAMLKey, kmsTokenEnv)
+	}
+	if len(cfg.PIIEncryption.Keys) == 0 && len(cfg.PIIEncryption.KMS.Keys) == 0 {
+		return nil
+	}
+	keys := make([]KeyEncryptionKey, 0, len(cfg.PIIEncryption.Keys)+len(cfg.PIIEncryption.KMS.Keys))
+	for id, encodedKey := range cfg.PIIEncryption.Keys {
+		key, err := NewLocalKey(id, encodedKey)
+		log.Panic(errors.Wrapf(err, "invalid piiEncryption.keys.%v", id)) //nolint:revive // Intended.
+		keys = append(keys, key)
+	}
+	for id, name := range cfg.PIIEncryption.KMS.Keys {
+		key, err := NewVaultTransitKey(id, name, &cfg.PIIEncryption.KMS.VaultTransit)
+		log.Panic(errors.Wrapf(err, "invalid piiEncryption.kms.keys.%v", id)) //nolint:revive // Intended.
+		keys = append(keys, key)
+	}
+	blindIndexKey, err := decodeKey(cfg.PIIEncryption.BlindIndexKey)
+	log.Panic(errors.Wrap(err, "invalid piiEncryption.blindIndexKey")) //nolint:revive // Intended.
+	c, err := NewEnvelopeCipher(cfg.PIIEncryption.PrimaryKeyID, blindIndexKey, keys...)
+	log.Panic(errors.Wrap(err, "invalid piiEncryption")) //nolint:revive // Intended.
+
+	return c
+}
+
+// NewEnvelopeCipher returns the cipher that encrypts with the primary key and decrypts with any of the keys.
+// The old keys must be kept until all the values encrypted with them are re-encrypted with the new primary one.
+func NewEnvelopeCipher(primaryKeyID string, blindIndexKey []byte, keys ...KeyEncryptionKey) (Cipher, error) {
+	c := &envelopeCipher{keys: make(map[string]KeyEncryptionKey, len(keys)), blindIndexKey: blindIndexKey}
+	for _, key := range keys {
+		if key.ID() == "" || strings.Contains(key.ID(), ciphertextSeparator) {
+			return nil, errors.Errorf("key ID `%v` must be non empty and can't contain `%v`", key.ID(), ciphertextSeparator)
+		}
+		c.keys[strings.ToLower(key.ID())] = key
+	}
+	if c.primaryKey = c.keys[strings.ToLower(primaryKeyID)]; c.primaryKey == nil {
+		return nil, errors.Wrapf(ErrUnknownKey, "primary key `%v` isn't among the keys", primaryKeyID)
+	}
+	if len(blindIndexKey) != keySize {
+		return nil, errors.Errorf("the blind index key must have %v bytes, it has %v", keySize, len(blindIndexKey))
+	}
+
+	return c, nil
+}
+
+// NewLocalKey returns the key encryption key for the base64 encoded AES-256 key.
+func NewLocalKey(id, encodedKey string) (KeyEncryptionKey, error) {
+	key, err := decodeKey(encodedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localKey{id: id, key: key}, nil
+}
+
+// NewVaultTransitKey returns the key encryption key named `name` in the transit secrets engine.
+// Every Encrypt and Decrypt calls its API, and the key can be rotated in it, since the wrapped data keys hold its version.
+func NewVaultTransitKey(id, name string, transit *VaultTransit) (KeyEncryptionKey, error) {
+	if transit.URL == "" || name == "" {
+		return nil, errors.New("the url of the vault transit engine and the name of the key are required")
+	}
+	normalized := *transit
+	normalized.URL = strings.TrimSuffix(normalized.URL, "/")
+	if normalized.Mount = strings.Trim(normalized.Mount, "/"); normalized.Mount == "" {
+		normalized.Mount = defaultVaultTransitMount
+	}
+
+	return &vaultTransitKey{id: id, name: name, transit: &normalized}, nil
+}
+
+// IsEncrypted reports whether the value was encrypted by a Cipher, so that the plaintext values, stored before the encryption
+// was enabled, can still be read, until they're encrypted.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, ciphertextVersion+ciphertextSeparator)
+}
+
+// Reencrypt encrypts the values with the primary key, whether they're plaintext or they were encrypted with another key, and saves them one by one.
+// The ones that can never be decrypted, because they were tampered with or their key isn't configured anymore, are skipped,
+// so that they don't block the others, and their indexes are returned. Any other error stops it.
+func Reencrypt(c Cipher, values []string, save func(idx int, plaintext, ciphertext string) error) (undecryptable []int, err error) {
+	for idx, value := range values {
+		plaintext := value
+		if IsEncrypted(value) {
+			var dErr error
+			if plaintext, dErr = c.Decrypt(value); dErr != nil {
+				if errors.Is(dErr, ErrInvalidCiphertext) || errors.Is(dErr, ErrUnknownKey) {
+					undecryptable = append(undecryptable, idx)
+
+					continue
+				}
+
+				return undecryptable, errors.Wrapf(dErr, "failed to decrypt value #%v", idx)
+			}
+		}
+		ciphertext, eErr := c.Encrypt(plaintext)
+		if eErr != nil {
+			return undecryptable, errors.Wrapf(eErr, "failed to encrypt value #%v", idx)
+		}
+		if err = save(idx, plaintext, ciphertext); err != nil {
+			return undecryptable, err
+		}
+	}
+
+	return undecryptable, nil
+}
+
+func (c *envelopeCipher) Encrypt(plaintext string) (string, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", errors.Wrap(err, "failed to generate the data key")
+	}
+	sealed, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to seal the plaintext")
+	}
+	wrappedDataKey, err := c.primaryKey.Wrap(dataKey)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to wrap the data key with key `%v`", c.primaryKey.ID())
+	}
+
+	return strings.Join([]string{
+		ciphertextVersion,
+		strings.ToLower(c.primaryKey.ID()),
+		base64.RawURLEncoding.EncodeToString(wrappedDataKey),
+		base64.RawURLEncoding.EncodeToString(sealed),
+	}, ciphertextSeparator), nil
+}
+
+func (c *envelopeCipher) Decrypt(ciphertext string) (string, error) {
+	const parts = 4
+	split := strings.Split(ciphertext, ciphertextSeparator)
+	if len(split) != parts || split[0] != ciphertextVersion {
+		return "", errors.Wrap(ErrInvalidCiphertext, "unsupported format")
+	}
+	key, found := c.keys[split[1]]
+	if !found {
+		return "", errors.Wrapf(ErrUnknownKey, "key `%v` isn't configured", split[1])
+	}
+	wrappedDataKey, wErr := base64.RawURLEncoding.DecodeString(split[2])
+	sealed, sErr := base64.RawURLEncoding.DecodeString(split[3])
+	if wErr != nil || sErr != nil {
+		return "", errors.Wrap(ErrInvalidCiphertext, "it's not base64 encoded")
+	}
+	dataKey, err := key.Unwrap(wrappedDataKey)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to unwrap the data key with key `%v`", key.ID())
+	}
+	plaintext, err := open(dataKey, sealed)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open the sealed plaintext")
+	}
+
+	return string(plaintext), nil
+}
+
+func (c *envelopeCipher) BlindIndex(plaintext string) string {
+	mac := hmac.New(sha256.New, c.blindIndexKey)
+	mac.Write([]byte(plaintext)) //nolint:errcheck,revive // It never fails.
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *envelopeCipher) PrimaryKeyPrefix() string {
+	return ciphertextVersion + ciphertextSeparator + strings.ToLower(c.primaryKey.ID()) + ciphertextSeparator
+}
+
+func (k *localKey) ID() string {
+	return k.id
+}
+
+func (k *localKey) Wrap(dataKey []byte) ([]byte, error) {
+	return seal(k.key, dataKey)
+}
+
+func (k *localKey) Unwrap(wrapped []byte) ([]byte, error) {
+	return open(k.key, wrapped)
+}
+
+func (k *vaultTransitKey) ID() string {
+	return k.id
+}
+
+func (k *vaultTransitKey) Wrap(dataKey []byte) ([]byte, error) {
+	var result vaultTransitResponse
+	if err := k.do("encrypt", &vaultTransitRequest{Plaintext: base64.StdEncoding.EncodeToString(dataKey)}, &result); err != nil {
+		return nil, err
+	}
+
+	return []byte(result.Data.Ciphertext), nil
+}
+
+func (k *vaultTransitKey) Unwrap(wrapped []byte) ([]byte, error) {
+	var result vaultTransitResponse
+	if err := k.do("decrypt", &vaultTransitRequest{Ciphertext: string(wrapped)}, &result); err != nil {
+		return nil, err
+	}
+	dataKey, err := base64.StdEncoding.DecodeString(result.Data.Plaintext)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidCiphertext, "the unwrapped data key isn't base64 encoded")
+	}
+
+	return dataKey, nil
+}
+
+func (k *vaultTransitKey) do(operation string, body *vaultTransitRequest, result *vaultTransitResponse) error {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsDeadline)
+	defer cancel()
+	var failure vaultError
+	resp, err := req.
+		SetContext(ctx).
+		SetHeader("X-Vault-Token", k.transit.Token).
+		SetBodyJsonMarshal(body).
+		SetSuccessResult(result).
+		SetErrorResult(&failure).
+		Post(fmt.Sprintf("%v/v1/%v/%v/%v", k.transit.URL, k.transit.Mount, operation, k.name))
+	if err != nil {
+		return errors.Wrapf(err, "vault transit %v with key `%v` failed", operation, k.name)
+	}
+	if !resp.IsSuccessState() {
+		err = errors.Errorf("vault transit %v with key `%v` failed with status: %v, errors: %v", operation, k.name, resp.GetStatusCode(), failure.Errors)
+		if operation == "decrypt" && resp.GetStatusCode() == http.StatusBadRequest {
+			err = errors.Wrap(ErrInvalidCiphertext, err.Error())
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// seal returns the nonce followed by the sealed plaintext, so that equal plaintexts have different ciphertexts.
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.Wrap(ErrInvalidCiphertext, "it's too short")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidCiphertext, "failed to decrypt: %v", err)
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the aes cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+
+	return gcm, errors.Wrap(err, "failed to create the gcm")
+}
+
+func decodeKey(encodedKey string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "the key must be base64 encoded")
+	}
+	if len(key) != keySize {
+		return nil, errors.Errorf("the key must have %v bytes, it has %v", keySize, len(key))
+	}
+
+	return key, nil
+}
+
+func parseKeys(keys string) map[string]string {
+	parsed := make(map[string]string)
+	for _, idAndKey := range strings.Split(keys, ",") {
+		if id, key, found := strings.Cut(strings.TrimSpace(idAndKey), ":"); found {
+			parsed[id] = key
+		}
+	}
+
+	return parsed
+}
+
+func loadFromEnv(applicationYAMLKey, env string) string {
+	module := strings.ToUpper(strings.ReplaceAll(strings.ReplaceAll(applicationYAMLKey, "-", "_"), "/", "_"))
+	if val := os.Getenv(module + "_" + env); val != "" {
+		return val
+	}
+
+	return os.Getenv(env)
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package encryption
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher(t *testing.T) {
+	t.Parallel()
+	c := newTestCipher(t, "k1", "k1")
+
+	ciphertext, err := c.Encrypt("2000-01-31")
+	require.NoError(t, err)
+	assert.NotContains(t, ciphertext, "2000-01-31")
+	assert.True(t, IsEncrypted(ciphertext))
+	assert.True(t, strings.HasPrefix(ciphertext, c.PrimaryKeyPrefix()))
+	otherCiphertext, err := c.Encrypt("2000-01-31")
+	require.NoError(t, err)
+	assert.NotEqual(t, ciphertext, otherCiphertext)
+
+	plaintext, err := c.Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "2000-01-31", plaintext)
+
+	_, err = c.Decrypt("bogus")
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+	_, err = c.Decrypt(ciphertext[:len(ciphertext)-4] + "AAAA")
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+	_, err = newTestCipher(t, "k2", "k2").Decrypt(ciphertext)
+	require.ErrorIs(t, err, ErrUnknownKey)
+
+	assert.False(t, IsEncrypted("+12099216581"))
+	assert.Equal(t, c.BlindIndex("12099216581"), c.BlindIndex("12099216581"))
+	assert.NotEqual(t, c.BlindIndex("12099216581"), c.BlindIndex("12099216582"))
+}
+
+func TestCipherKeyRotation(t *testing.T) {
+	t.Parallel()
+	before := newTestCipher(t, "k1", "k1")
+	ciphertext, err := before.Encrypt("+12099216581")
+	require.NoError(t, err)
+
+	after := newTestCipher(t, "k2", "k1", "k2")
+	assert.False(t, strings.HasPrefix(ciphertext, after.PrimaryKeyPrefix()))
+	plaintext, err := after.Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "+12099216581", plaintext)
+	reencrypted, err := after.Encrypt(plaintext)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(reencrypted, after.PrimaryKeyPrefix()))
+	assert.Equal(t, before.BlindIndex(plaintext), after.BlindIndex(plaintext))
+
+	_, err = before.Decrypt(reencrypted)
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestReencrypt(t *testing.T) {
+	t.Parallel()
+	before := newTestCipher(t, "k1", "k1")
+	oldCiphertext, err := before.Encrypt("+12099216581")
+	require.NoError(t, err)
+	tampered, err := before.Encrypt("+12099216582")
+	require.NoError(t, err)
+	tampered = tampered[:len(tampered)-4] + "AAAA"
+
+	after := newTestCipher(t, "k2", "k1", "k2")
+	saved := make(map[int]string)
+	undecryptable, err := Reencrypt(after, []string{oldCiphertext, tampered, "+12099216583"}, func(idx int, plaintext, ciphertext string) error {
+		assert.True(t, strings.HasPrefix(ciphertext, after.PrimaryKeyPrefix()))
+		decrypted, dErr := after.Decrypt(ciphertext)
+		require.NoError(t, dErr)
+		assert.Equal(t, plaintext, decrypted)
+		saved[idx] = plaintext
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, undecryptable)
+	assert.Equal(t, map[int]string{0: "+12099216581", 2: "+12099216583"}, saved)
+
+	_, err = Reencrypt(after, []string{"+12099216581", "+12099216583"}, func(int, string, string) error { return ErrUnknownKey })
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestNewEnvelopeCipher(t *testing.T) {
+	t.Parallel()
+	_, err := NewLocalKey("k1", "not base64!")
+	require.Error(t, err)
+	_, err = NewLocalKey("k1", base64.StdEncoding.EncodeToString([]byte("short")))
+	require.Error(t, err)
+
+	key, err := NewLocalKey("k1", testKey("k1"))
+	require.NoError(t, err)
+	_, err = NewEnvelopeCipher("k2", []byte(strings.Repeat("b", keySize)), key)
+	require.ErrorIs(t, err, ErrUnknownKey)
+	_, err = NewEnvelopeCipher("k1", []byte("short"), key)
+	require.Error(t, err)
+	invalidKey, err := NewLocalKey("k.1", testKey("k1"))
+	require.NoError(t, err)
+	_, err = NewEnvelopeCipher("k.1", []byte(strings.Repeat("b", keySize)), invalidKey)
+	require.Error(t, err)
+}
+
+func TestVaultTransitKey(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "token", request.Header.Get("X-Vault-Token"))
+		var body vaultTransitRequest
+		assert.NoError(t, json.NewDecoder(request.Body).Decode(&body))
+		writer.Header().Set("Content-Type", "application/json")
+		switch request.URL.Path {
+		case "/v1/transit/encrypt/pii":
+			assert.NoError(t, json.NewEncoder(writer).Encode(map[string]any{"data": map[string]string{"ciphertext": "vault:v1:" + body.Plaintext}}))
+		case "/v1/transit/decrypt/pii":
+			if !strings.HasPrefix(body.Ciphertext, "vault:v1:") {
+				writer.WriteHeader(http.StatusBadRequest)
+				assert.NoError(t, json.NewEncoder(writer).Encode(map[string]any{"errors": []string{"invalid ciphertext"}}))
+
+				return
+			}
+			assert.NoError(t, json.NewEncoder(writer).Encode(map[string]any{"data": map[string]string{"plaintext": strings.TrimPrefix(body.Ciphertext, "vault:v1:")}}))
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	key, err := NewVaultTransitKey("kms1", "pii", &VaultTransit{URL: server.URL + "/", Token: "token"})
+	require.NoError(t, err)
+	c, err := NewEnvelopeCipher("kms1", []byte(strings.Repeat("b", keySize)), key)
+	require.NoError(t, err)
+
+	ciphertext, err := c.Encrypt("+12099216581")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ciphertext, c.PrimaryKeyPrefix()))
+	plaintext, err := c.Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "+12099216581", plaintext)
+
+	_, err = key.Unwrap([]byte("bogus"))
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+	_, err = NewVaultTransitKey("kms1", "pii", &VaultTransit{})
+	require.Error(t, err)
+}
+
+func TestParseKeys(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, map[string]string{"k1": "a", "k2": "b=="}, parseKeys("k1:a, k2:b==,bogus"))
+	assert.Empty(t, parseKeys(""))
+}
+
+func newTestCipher(t *testing.T, primaryKeyID string, keyIDs ...string) Cipher {
+	t.Helper()
+	keys := make([]KeyEncryptionKey, 0, len(keyIDs))
+	for _, id := range keyIDs {
+		key, err := NewLocalKey(id, testKey(id))
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	c, err := NewEnvelopeCipher(primaryKeyID, []byte(strings.Repeat("b", keySize)), keys...)
+	require.NoError(t, err)
+
+	return c
+}
+
+func testKey(id string) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(id[len(id)-1:], keySize)))
+}
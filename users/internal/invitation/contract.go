@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package invitation
+
+import (
+	"context"
+	"embed"
+	htmltemplate "html/template"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/email"
+	"github.com/ice-blockchain/wintr/sms"
+)
+
+// Public API.
+
+const (
+	EmailChannel Channel = "email"
+	SMSChannel   Channel = "sms"
+)
+
+var ErrUnsupportedChannel = errors.New("unsupported channel")
+
+type (
+	Channel    string
+	Invitation struct {
+		// The email address or the phone number, depending on the channel.
+		To       string
+		Language string
+		// The name of the inviter, as shown to the invitee.
+		InviterName string
+		// The username of the inviter, because that's what the invitee provides as referredBy.
+		ReferralCode     string
+		UnsubscribeToken string
+		Channel          Channel
+	}
+	Sender interface {
+		// Send sends the templated invitation, in the language of the inviter, falling back to english.
+		Send(ctx context.Context, inv *Invitation) error
+	}
+)
+
+// Private API.
+
+const (
+	defaultLanguage = "en"
+	htmlExtension   = "html"
+	textExtension   = "txt"
+)
+
+// .
+var (
+	//go:embed translations
+	translations embed.FS
+)
+
+type (
+	sender struct {
+		emailClient email.Client
+		smsClient   sms.Client
+		cfg         *config
+		templates   map[Channel]map[string]*invitationTemplate
+	}
+	invitationTemplate struct {
+		subject *template.Template
+		body    *template.Template
+		html    *htmltemplate.Template
+		Subject string `json:"subject"` //nolint:revive // That's intended.
+	}
+	templateData struct {
+		InviterName     string
+		ReferralCode    string
+		ReferralLink    string
+		UnsubscribeLink string
+	}
+	// | config holds the configuration of this package mounted from `application.yaml`.
+	config struct {
+		ReferralInvitations struct {
+			FromEmailAddress string `yaml:"fromEmailAddress"`
+			FromEmailName    string `yaml:"fromEmailName"`
+			// The link shared with the invitee, `%v` being the referral code.
+			ReferralLink string `yaml:"referralLink"`
+			// The link to unsubscribe from invitations, `%v` being the unsubscribe token.
+			UnsubscribeLink string `yaml:"unsubscribeLink"`
+			Enabled         bool   `yaml:"enabled"`
+		} `yaml:"referralInvitations"`
+	}
+)
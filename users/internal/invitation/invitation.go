@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package invitation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"net/url"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/email"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/sms"
+)
+
+// New returns the invitations sender, or nil if invitations are disabled.
+func New(applicationYAMLKey string) Sender {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+	if !cfg.ReferralInvitations.Enabled {
+		return nil
+	}
+	if cfg.ReferralInvitations.ReferralLink == "" || cfg.ReferralInvitations.UnsubscribeLink == "" || cfg.ReferralInvitations.FromEmailAddress == "" {
+		log.Panic(errors.New("referralInvitations.referralLink, referralInvitations.unsubscribeLink and referralInvitations.fromEmailAddress are required"))
+	}
+	templates, err := loadTemplates()
+	log.Panic(errors.Wrap(err, "failed to load the referral invitation templates")) //nolint:revive // Intended.
+
+	return &sender{
+		emailClient: email.New(applicationYAMLKey),
+		smsClient:   sms.New(applicationYAMLKey),
+		cfg:         &cfg,
+		templates:   templates,
+	}
+}
+
+func (s *sender) Send(ctx context.Context, inv *Invitation) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	data := &templateData{
+		InviterName:     inv.InviterName,
+		ReferralCode:    inv.ReferralCode,
+		ReferralLink:    fmt.Sprintf(s.cfg.ReferralInvitations.ReferralLink, url.PathEscape(inv.ReferralCode)),
+		UnsubscribeLink: fmt.Sprintf(s.cfg.ReferralInvitations.UnsubscribeLink, url.QueryEscape(inv.UnsubscribeToken)),
+	}
+	subject, body, err := render(s.templates, inv.Channel, inv.Language, data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render the %v invitation", inv.Channel)
+	}
+	switch inv.Channel {
+	case EmailChannel:
+		return errors.Wrapf(s.emailClient.Send(ctx, &email.Parcel{
+			Body:    &email.Body{Type: email.TextHTML, Data: body},
+			Subject: subject,
+			From:    email.Participant{Name: s.cfg.ReferralInvitations.FromEmailName, Email: s.cfg.ReferralInvitations.FromEmailAddress},
+		}, email.Participant{Email: inv.To}), "failed to send invitation email to %v", inv.To)
+	case SMSChannel:
+		return errors.Wrapf(s.smsClient.Send(ctx, &sms.Parcel{ToNumber: inv.To, Message: body}), "failed to send invitation sms to %v", inv.To)
+	default:
+		return errors.Wrapf(ErrUnsupportedChannel, "channel `%v`", inv.Channel)
+	}
+}
+
+func render(templates map[Channel]map[string]*invitationTemplate, channel Channel, language string, data *templateData) (subject, body string, err error) {
+	if templates[channel] == nil {
+		return "", "", errors.Wrapf(ErrUnsupportedChannel, "channel `%v`", channel)
+	}
+	tmpl, found := templates[channel][language]
+	if !found {
+		tmpl = templates[channel][defaultLanguage]
+	}
+	bf := new(bytes.Buffer)
+	if tmpl.subject != nil {
+		if err = tmpl.subject.Execute(bf, data); err != nil {
+			return "", "", errors.Wrapf(err, "failed to execute subject template for %#v", data)
+		}
+		subject = bf.String()
+		bf.Reset()
+	}
+	if tmpl.html != nil {
+		err = tmpl.html.Execute(bf, data)
+	} else {
+		err = tmpl.body.Execute(bf, data)
+	}
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to execute body template for %#v", data)
+	}
+
+	return subject, strings.TrimSpace(bf.String()), nil
+}
+
+// loadTemplates loads the embedded translations: for emails, `<language>.txt` holds the subject and `<language>.html` the body;
+// for sms, `<language>.txt` is the message. The email bodies are html escaped, because they include user provided names.
+func loadTemplates() (map[Channel]map[string]*invitationTemplate, error) { //nolint:funlen // .
+	templates := make(map[Channel]map[string]*invitationTemplate, 1+1)
+	for _, channel := range []Channel{EmailChannel, SMSChannel} {
+		dir := path.Join("translations", string(channel))
+		files, err := translations.ReadDir(dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %v", dir)
+		}
+		templates[channel] = make(map[string]*invitationTemplate, len(files))
+		for _, file := range files {
+			content, rErr := translations.ReadFile(path.Join(dir, file.Name()))
+			if rErr != nil {
+				return nil, errors.Wrapf(rErr, "failed to read %v/%v", dir, file.Name())
+			}
+			language, ext, _ := strings.Cut(file.Name(), ".")
+			if templates[channel][language] == nil {
+				templates[channel][language] = new(invitationTemplate)
+			}
+			tmpl, name := templates[channel][language], fmt.Sprintf("referral_invitation_%v_%v", channel, language)
+			switch {
+			case channel == SMSChannel && ext == textExtension:
+				if tmpl.body, err = template.New(name + "_body").Parse(string(content)); err != nil {
+					return nil, errors.Wrapf(err, "failed to parse %v", name)
+				}
+			case channel == EmailChannel && ext == textExtension:
+				if err = json.Unmarshal(content, tmpl); err != nil {
+					return nil, errors.Wrapf(err, "failed to unmarshal %v", name)
+				}
+				if tmpl.subject, err = template.New(name + "_subject").Parse(tmpl.Subject); err != nil {
+					return nil, errors.Wrapf(err, "failed to parse %v subject", name)
+				}
+			case channel == EmailChannel && ext == htmlExtension:
+				if tmpl.html, err = htmltemplate.New(name + "_body").Parse(string(content)); err != nil {
+					return nil, errors.Wrapf(err, "failed to parse %v body", name)
+				}
+			default:
+				return nil, errors.Errorf("unexpected translation file %v/%v", dir, file.Name())
+			}
+		}
+		if templates[channel][defaultLanguage] == nil {
+			return nil, errors.Errorf("missing the %v translations for %v", defaultLanguage, channel)
+		}
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package invitation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+	templates, err := loadTemplates()
+	require.NoError(t, err)
+	data := &templateData{
+		InviterName:     "<b>John</b>",
+		ReferralCode:    "jdoe",
+		ReferralLink:    "https://ice.io/@jdoe",
+		UnsubscribeLink: "https://ice.io/unsubscribe?token=abc",
+	}
+
+	subject, body, err := render(templates, EmailChannel, "en", data)
+	require.NoError(t, err)
+	assert.Equal(t, "<b>John</b> invited you to ice", subject)
+	assert.Contains(t, body, "&lt;b&gt;John&lt;/b&gt; invited you to join ice")
+	assert.Contains(t, body, `<a href="https://ice.io/@jdoe"`)
+	assert.Contains(t, body, `<a href="https://ice.io/unsubscribe?token=abc"`)
+
+	subject, body, err = render(templates, EmailChannel, "es", data)
+	require.NoError(t, err)
+	assert.Equal(t, "<b>John</b> te invitó a ice", subject)
+	assert.Contains(t, body, "jdoe")
+
+	subject, body, err = render(templates, SMSChannel, "xx", data)
+	require.NoError(t, err)
+	assert.Empty(t, subject)
+	assert.Equal(t, "<b>John</b> invited you to ice, use the referral code jdoe: https://ice.io/@jdoe . Stop invitations: https://ice.io/unsubscribe?token=abc", body)
+
+	_, _, err = render(templates, Channel("push"), "en", data)
+	require.ErrorIs(t, err, ErrUnsupportedChannel)
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package objectstorage
+
+import (
+	"context"
+	"net/url"
+	stdlibtime "time"
+)
+
+// Public API.
+
+type (
+	// Client writes to S3 compatible object stores (S3, GCS in interoperability mode, MinIO, etc).
+	Client interface {
+		// Put uploads the object, overwriting it if it already exists.
+		Put(ctx context.Context, name, contentType string, data []byte) error
+	}
+	Config struct {
+		Credentials struct {
+			AccessKeyID     string `yaml:"accessKeyId"`
+			SecretAccessKey string `yaml:"secretAccessKey"`
+		} `yaml:"credentials"`
+		Endpoint   string `yaml:"endpoint"`
+		Region     string `yaml:"region"`
+		Bucket     string `yaml:"bucket"`
+		PathPrefix string `yaml:"pathPrefix"`
+	}
+	// PresignRequest is what's signed, with AWS Signature Version 4, in a pre-signed URL.
+	PresignRequest struct {
+		Now        stdlibtime.Time
+		Headers    map[string]string
+		Method     string
+		Scheme     string
+		Host       string
+		Path       string
+		Region     string
+		AccessKey  string
+		SecretKey  string
+		Expiration stdlibtime.Duration
+	}
+)
+
+// Private API.
+
+const (
+	signingAlgorithm                  = "AWS4-HMAC-SHA256"
+	signingService                    = "s3"
+	unsignedPayload                   = "UNSIGNED-PAYLOAD"
+	amzDateFormat, amzShortDateFormat = "20060102T150405Z", "20060102"
+	requestsSignedURLExpiration       = 5 * stdlibtime.Minute
+	accessKeyIDEnv                    = "ACCESS_KEY_ID"
+	secretAccessKeyEnv                = "SECRET_ACCESS_KEY" //nolint:gosec // It's just the name.
+)
+
+type (
+	objectStorage struct {
+		cfg      *Config
+		endpoint *url.URL
+	}
+)
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package objectstorage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// New returns the client for the object store configured at `configKey`. Its credentials can also be provided
+// via the (<applicationYAMLKey>_)<envPrefix>_ACCESS_KEY_ID and (<applicationYAMLKey>_)<envPrefix>_SECRET_ACCESS_KEY env vars.
+func New(applicationYAMLKey, configKey, envPrefix string, cfg *Config) Client {
+	if cfg.Credentials.AccessKeyID == "" {
+		cfg.Credentials.AccessKeyID = loadFromEnv(applicationYAMLKey, envPrefix+"_"+accessKeyIDEnv)
+	}
+	if cfg.Credentials.SecretAccessKey == "" {
+		cfg.Credentials.SecretAccessKey = loadFromEnv(applicationYAMLKey, envPrefix+"_"+secretAccessKeyEnv)
+	}
+	if cfg.Endpoint == "" || cfg.Region == "" || cfg.Bucket == "" {
+		log.Panic(errors.Errorf("%v endpoint, region and bucket are required", configKey))
+	}
+	if cfg.Credentials.AccessKeyID == "" || cfg.Credentials.SecretAccessKey == "" {
+		log.Panic(errors.Errorf("%v credentials are required", configKey))
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	log.Panic(errors.Wrapf(err, "invalid %v.endpoint `%v`", configKey, cfg.Endpoint)) //nolint:revive // It's intended.
+
+	return &objectStorage{cfg: cfg, endpoint: endpoint}
+}
+
+func (o *objectStorage) Put(ctx context.Context, name, contentType string, data []byte) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	headers := map[string]string{"Content-Type": contentType}
+	signedURL := PresignURL(&PresignRequest{
+		Now:        stdlibtime.Now(),
+		Headers:    headers,
+		Method:     http.MethodPut,
+		Scheme:     o.endpoint.Scheme,
+		Host:       o.endpoint.Host,
+		Path:       "/" + path.Join(o.cfg.Bucket, o.cfg.PathPrefix, name),
+		Region:     o.cfg.Region,
+		AccessKey:  o.cfg.Credentials.AccessKeyID,
+		SecretKey:  o.cfg.Credentials.SecretAccessKey,
+		Expiration: requestsSignedURLExpiration,
+	})
+	resp, err := storageReq(ctx).SetHeaders(headers).SetBodyBytes(data).Put(signedURL)
+	if err == nil && !resp.IsSuccessState() {
+		body, rErr := resp.ToString()
+		log.Error(rErr)
+		err = errors.Errorf("request failed with status: %v,body: %v", resp.GetStatusCode(), body)
+	}
+
+	return errors.Wrapf(err, "failed to put object %v", name)
+}
+
+//nolint:gomnd // Static config.
+func storageReq(ctx context.Context) *req.Request {
+	return req.
+		SetContext(ctx).
+		SetRetryBackoffInterval(10*stdlibtime.Millisecond, 1*stdlibtime.Second).
+		SetRetryHook(func(resp *req.Response, err error) {
+			switch {
+			case err != nil:
+				log.Error(errors.Wrapf(err, "object storage request failed, retrying... "))
+			case resp.GetStatusCode() == http.StatusTooManyRequests:
+				log.Error(errors.New("rate limit for object storage reached, retrying... "))
+			case resp.GetStatusCode() >= http.StatusInternalServerError:
+				log.Error(errors.New("object storage request failed[internal server error], retrying... "))
+			}
+		}).
+		SetRetryCount(25).
+		SetRetryCondition(func(resp *req.Response, err error) bool {
+			return err != nil || resp.GetStatusCode() == http.StatusTooManyRequests || resp.GetStatusCode() >= http.StatusInternalServerError
+		})
+}
+
+// PresignURL generates an AWS Signature Version 4 query string pre-signed URL,
+// which is supported by S3 and every S3 compatible object store, GCS included (with HMAC keys).
+func PresignURL(pr *PresignRequest) string {
+	amzDate, shortDate := pr.Now.UTC().Format(amzDateFormat), pr.Now.UTC().Format(amzShortDateFormat)
+	scope := strings.Join([]string{shortDate, pr.Region, signingService, "aws4_request"}, "/")
+	headers := make(map[string]string, len(pr.Headers)+1)
+	for name, value := range pr.Headers {
+		headers[strings.ToLower(name)] = strings.TrimSpace(value)
+	}
+	headers["host"] = pr.Host
+	headerNames := make([]string, 0, len(headers))
+	for name := range headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+	query := map[string]string{
+		"X-Amz-Algorithm":     signingAlgorithm,
+		"X-Amz-Credential":    pr.AccessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.FormatInt(int64(pr.Expiration/stdlibtime.Second), 10), //nolint:gomnd // Decimal.
+		"X-Amz-SignedHeaders": signedHeaders,
+	}
+	queryNames := make([]string, 0, len(query))
+	for name := range query {
+		queryNames = append(queryNames, name)
+	}
+	sort.Strings(queryNames)
+	queryParams := make([]string, 0, len(queryNames))
+	for _, name := range queryNames {
+		queryParams = append(queryParams, uriEncode(name, true)+"="+uriEncode(query[name], true))
+	}
+	canonicalQuery, canonicalURI := strings.Join(queryParams, "&"), uriEncode(pr.Path, false)
+	canonicalRequest := strings.Join([]string{pr.Method, canonicalURI, canonicalQuery, canonicalHeaders.String(), signedHeaders, unsignedPayload}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{signingAlgorithm, amzDate, scope, hex.EncodeToString(canonicalRequestHash[:])}, "\n")
+	signingKey := hmacSHA256([]byte("AWS4"+pr.SecretKey), shortDate)
+	for _, part := range []string{pr.Region, signingService, "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	return fmt.Sprintf("%v://%v%v?%v&X-Amz-Signature=%v", pr.Scheme, pr.Host, canonicalURI, canonicalQuery, signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data)) //nolint:errcheck,revive // It never fails.
+
+	return mac.Sum(nil)
+}
+
+func uriEncode(val string, encodeSlash bool) string {
+	var res strings.Builder
+	for _, char := range []byte(val) {
+		switch {
+		case (char >= 'A' && char <= 'Z') || (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9'),
+			char == '-', char == '_', char == '.', char == '~', char == '/' && !encodeSlash:
+			res.WriteByte(char)
+		default:
+			res.WriteString(fmt.Sprintf("%%%02X", char))
+		}
+	}
+
+	return res.String()
+}
+
+func loadFromEnv(applicationYAMLKey, env string) string {
+	module := strings.ToUpper(strings.ReplaceAll(strings.ReplaceAll(applicationYAMLKey, "-", "_"), "/", "_"))
+	if val := os.Getenv(module + "_" + env); val != "" {
+		return val
+	}
+
+	return os.Getenv(env)
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package picturemoderation
+
+import (
+	"context"
+	"regexp"
+	stdlibtime "time"
+)
+
+// Public API.
+
+const (
+	NSFWViolation         Violation = "nsfw"
+	FaceMismatchViolation Violation = "faceMismatch"
+)
+
+const (
+	StubProvider        ProviderType = "stub"
+	SightengineProvider ProviderType = "sightengine"
+)
+
+type (
+	Violation    string
+	ProviderType string
+	Request      struct {
+		UserID     string
+		PictureURL string
+	}
+	Verdict struct {
+		Provider   ProviderType
+		Violations []Violation
+	}
+	Provider interface {
+		// Moderate checks the picture and returns the violations found, if any.
+		Moderate(ctx context.Context, req *Request) (*Verdict, error)
+	}
+)
+
+// Private API.
+
+const (
+	sightengineURL                  = "https://api.sightengine.com/1.0/check.json"
+	sightengineModels               = "nudity-2.1"
+	sightengineSuccessStatus        = "success"
+	defaultSightengineNSFWThreshold = 0.8
+	requestDeadline                 = 25 * stdlibtime.Second
+	apiUserEnv                      = "PROFILE_PICTURE_MODERATION_API_USER"
+	apiSecretEnv                    = "PROFILE_PICTURE_MODERATION_API_SECRET" //nolint:gosec // It's just the name.
+)
+
+type (
+	// | stub flags the pictures matching a configured regex. It's meant for tests and local environments.
+	stub struct {
+		violatingPictureRegex *regexp.Regexp
+		violations            []Violation
+	}
+	// | sightengine uses https://sightengine.com to detect NSFW pictures.
+	// It doesn't support face mismatch detection, because it needs a reference face to compare with.
+	sightengine struct {
+		cfg *config
+	}
+	sightengineResponse struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Nudity struct {
+			SexualActivity float64 `json:"sexual_activity"` //nolint:tagliatelle // It's their API.
+			SexualDisplay  float64 `json:"sexual_display"`  //nolint:tagliatelle // It's their API.
+			Erotica        float64 `json:"erotica"`
+		} `json:"nudity"`
+		Status string `json:"status"`
+	}
+	// | config holds the configuration of this package mounted from `application.yaml`.
+	config struct {
+		ProfilePictureModeration struct {
+			Stub struct {
+				ViolatingPictureRegex string      `yaml:"violatingPictureRegex"`
+				Violations            []Violation `yaml:"violations"`
+			} `yaml:"stub"`
+			Sightengine struct {
+				APIUser       string  `yaml:"apiUser"`
+				APISecret     string  `yaml:"apiSecret"`
+				NSFWThreshold float64 `yaml:"nsfwThreshold"`
+			} `yaml:"sightengine"`
+			Provider ProviderType `yaml:"provider"`
+		} `yaml:"profilePictureModeration"`
+	}
+)
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package picturemoderation
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// New returns the configured moderation provider, or nil if moderation is disabled.
+func New(applicationYAMLKey string) Provider {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+
+	switch cfg.ProfilePictureModeration.Provider {
+	case "":
+		return nil
+	case StubProvider:
+		return newStub(cfg.ProfilePictureModeration.Stub.ViolatingPictureRegex, cfg.ProfilePictureModeration.Stub.Violations)
+	case SightengineProvider:
+		return newSightengine(applicationYAMLKey, &cfg)
+	default:
+		log.Panic(errors.Errorf("unsupported profile picture moderation provider `%v`", cfg.ProfilePictureModeration.Provider))
+
+		return nil
+	}
+}
+
+func newStub(violatingPictureRegex string, violations []Violation) *stub {
+	if len(violations) == 0 {
+		violations = []Violation{NSFWViolation}
+	}
+	s := &stub{violations: violations}
+	if violatingPictureRegex != "" {
+		s.violatingPictureRegex = regexp.MustCompile(violatingPictureRegex)
+	}
+
+	return s
+}
+
+func (s *stub) Moderate(ctx context.Context, r *Request) (*Verdict, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	verdict := &Verdict{Provider: StubProvider}
+	if s.violatingPictureRegex != nil && s.violatingPictureRegex.MatchString(r.PictureURL) {
+		verdict.Violations = s.violations
+	}
+
+	return verdict, nil
+}
+
+func newSightengine(applicationYAMLKey string, cfg *config) *sightengine {
+	sightengineCfg := &cfg.ProfilePictureModeration.Sightengine
+	if sightengineCfg.APIUser == "" {
+		sightengineCfg.APIUser = loadFromEnv(applicationYAMLKey, apiUserEnv)
+	}
+	if sightengineCfg.APISecret == "" {
+		sightengineCfg.APISecret = loadFromEnv(applicationYAMLKey, apiSecretEnv)
+	}
+	if sightengineCfg.APIUser == "" || sightengineCfg.APISecret == "" {
+		log.Panic(errors.New("profilePictureModeration.sightengine credentials are required"))
+	}
+	if sightengineCfg.NSFWThreshold == 0 {
+		sightengineCfg.NSFWThreshold = defaultSightengineNSFWThreshold
+	}
+
+	return &sightengine{cfg: cfg}
+}
+
+func (s *sightengine) Moderate(ctx context.Context, r *Request) (*Verdict, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, requestDeadline)
+	defer cancel()
+	var result sightengineResponse
+	resp, err := req.
+		SetContext(reqCtx).
+		SetQueryParams(map[string]string{
+			"models":     sightengineModels,
+			"api_user":   s.cfg.ProfilePictureModeration.Sightengine.APIUser,
+			"api_secret": s.cfg.ProfilePictureModeration.Sightengine.APISecret,
+			"url":        r.PictureURL,
+		}).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Get(sightengineURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "sightengine request failed for %#v", r)
+	}
+	if !resp.IsSuccessState() || result.Status != sightengineSuccessStatus {
+		var message string
+		if result.Error != nil {
+			message = result.Error.Message
+		}
+
+		return nil, errors.Errorf("sightengine request failed with status: %v, message: %v", resp.GetStatusCode(), message)
+	}
+	verdict := &Verdict{Provider: SightengineProvider}
+	threshold := s.cfg.ProfilePictureModeration.Sightengine.NSFWThreshold
+	if result.Nudity.SexualActivity >= threshold || result.Nudity.SexualDisplay >= threshold || result.Nudity.Erotica >= threshold {
+		verdict.Violations = append(verdict.Violations, NSFWViolation)
+	}
+
+	return verdict, nil
+}
+
+func loadFromEnv(applicationYAMLKey, env string) string {
+	module := strings.ToUpper(strings.ReplaceAll(strings.ReplaceAll(applicationYAMLKey, "-", "_"), "/", "_"))
+	if val := os.Getenv(module + "_" + env); val != "" {
+		return val
+	}
+
+	return os.Getenv(env)
+}
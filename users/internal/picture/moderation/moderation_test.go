@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package picturemoderation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStubModerate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	verdict, err := newStub("", nil).Moderate(ctx, &Request{UserID: "a", PictureURL: "https://somecdn.com/nsfw.jpg"})
+	require.NoError(t, err)
+	assert.Equal(t, &Verdict{Provider: StubProvider}, verdict)
+
+	provider := newStub("nsfw", []Violation{NSFWViolation, FaceMismatchViolation})
+	verdict, err = provider.Moderate(ctx, &Request{UserID: "a", PictureURL: "https://somecdn.com/p1.jpg"})
+	require.NoError(t, err)
+	assert.Empty(t, verdict.Violations)
+	verdict, err = provider.Moderate(ctx, &Request{UserID: "a", PictureURL: "https://somecdn.com/nsfw.jpg"})
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{NSFWViolation, FaceMismatchViolation}, verdict.Violations)
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package picturestorage
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/multimedia/picture"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// Public API.
+
+const (
+	BunnyBackend Backend = "bunny"
+	S3Backend    Backend = "s3"
+	GCSBackend   Backend = "gcs"
+)
+
+var ErrSignedUploadNotSupported = errors.New("signed uploads are not supported by the configured picture storage backend")
+
+type (
+	Backend string
+	// Client is the wintr picture client, extended with what's needed for clients to upload pictures directly to the storage.
+	Client interface {
+		picture.Client
+		// SignedUploadURL generates a pre-signed URL that can be used by anyone, until it expires, to upload `pictureName`.
+		SignedUploadURL(ctx context.Context, pictureName, contentType string) (*SignedUpload, error)
+		// PictureExists checks if `pictureName` was uploaded.
+		PictureExists(ctx context.Context, pictureName string) (bool, error)
+	}
+	SignedUpload struct {
+		ExpiresAt   *time.Time        `json:"expiresAt" example:"2022-01-03T16:20:52.156534Z"`
+		Headers     map[string]string `json:"headers,omitempty" example:"Content-Type:image/jpeg"`
+		URL         string            `json:"url" example:"https://storage.googleapis.com/some-bucket/profile/1_1672762852156534.jpg?X-Amz-Signature=..."`
+		Method      string            `json:"method" example:"PUT"`
+		PictureName string            `json:"pictureName" example:"1_1672762852156534.jpg"`
+	}
+)
+
+// Private API.
+
+const (
+	gcsEndpoint                         = "https://storage.googleapis.com"
+	gcsRegion                           = "auto"
+	requestDeadline                     = 25 * stdlibtime.Second
+	defaultSignedUploadURLExpiration    = 15 * stdlibtime.Minute
+	maxSignedURLExpiration              = 7 * 24 * stdlibtime.Hour
+	internalRequestsSignedURLExpiration = 5 * stdlibtime.Minute
+	accessKeyIDEnv                      = "PROFILE_PICTURE_STORAGE_ACCESS_KEY_ID"
+	secretAccessKeyEnv                  = "PROFILE_PICTURE_STORAGE_SECRET_ACCESS_KEY" //nolint:gosec // It's just the name.
+)
+
+type (
+	// | bunny is the default backend, that uploads through the wintr picture client and doesn't support signed uploads.
+	bunny struct {
+		picture.Client
+	}
+	// | objectStorage is the backend for S3 compatible object stores (S3, GCS in interoperability mode, MinIO, etc).
+	objectStorage struct {
+		cfg              *config
+		endpoint         *url.URL
+		ignoreFilesRegex *regexp.Regexp
+	}
+	// | config holds the configuration of this package mounted from `application.yaml`.
+	config struct {
+		ProfilePictureStorage struct {
+			Credentials struct {
+				AccessKeyID     string `yaml:"accessKeyId"`
+				SecretAccessKey string `yaml:"secretAccessKey"`
+			} `yaml:"credentials"`
+			Backend                   Backend             `yaml:"backend"`
+			Endpoint                  string              `yaml:"endpoint"`
+			Region                    string              `yaml:"region"`
+			Bucket                    string              `yaml:"bucket"`
+			PathPrefix                string              `yaml:"pathPrefix"`
+			URLDownload               string              `yaml:"urlDownload"`
+			SignedUploadURLExpiration stdlibtime.Duration `yaml:"signedUploadUrlExpiration"`
+		} `yaml:"profilePictureStorage"`
+	}
+)
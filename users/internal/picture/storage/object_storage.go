@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package picturestorage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users/internal/objectstorage"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+//nolint:funlen // Mostly defaults and validations.
+func newObjectStorage(applicationYAMLKey string, cfg *config, ignoreFilesRegex ...string) *objectStorage {
+	storageCfg := &cfg.ProfilePictureStorage
+	if storageCfg.Backend == GCSBackend {
+		if storageCfg.Endpoint == "" {
+			storageCfg.Endpoint = gcsEndpoint
+		}
+		if storageCfg.Region == "" {
+			storageCfg.Region = gcsRegion
+		}
+	}
+	if storageCfg.Credentials.AccessKeyID == "" {
+		storageCfg.Credentials.AccessKeyID = loadFromEnv(applicationYAMLKey, accessKeyIDEnv)
+	}
+	if storageCfg.Credentials.SecretAccessKey == "" {
+		storageCfg.Credentials.SecretAccessKey = loadFromEnv(applicationYAMLKey, secretAccessKeyEnv)
+	}
+	if storageCfg.SignedUploadURLExpiration == 0 {
+		storageCfg.SignedUploadURLExpiration = defaultSignedUploadURLExpiration
+	}
+	if storageCfg.SignedUploadURLExpiration > maxSignedURLExpiration {
+		log.Panic(errors.Errorf("profilePictureStorage.signedUploadUrlExpiration can't be more than %v", maxSignedURLExpiration))
+	}
+	if storageCfg.Endpoint == "" || storageCfg.Region == "" || storageCfg.Bucket == "" || storageCfg.URLDownload == "" {
+		log.Panic(errors.Errorf("profilePictureStorage endpoint, region, bucket and urlDownload are required for the `%v` backend", storageCfg.Backend))
+	}
+	if storageCfg.Credentials.AccessKeyID == "" || storageCfg.Credentials.SecretAccessKey == "" {
+		log.Panic(errors.Errorf("profilePictureStorage credentials are required for the `%v` backend", storageCfg.Backend))
+	}
+	endpoint, err := url.Parse(storageCfg.Endpoint)
+	log.Panic(errors.Wrapf(err, "invalid profilePictureStorage.endpoint `%v`", storageCfg.Endpoint)) //nolint:revive // It's intended.
+	var ignFileRegx *regexp.Regexp
+	if len(ignoreFilesRegex) == 1 {
+		ignFileRegx = regexp.MustCompile(ignoreFilesRegex[0])
+	}
+
+	return &objectStorage{cfg: cfg, endpoint: endpoint, ignoreFilesRegex: ignFileRegx}
+}
+
+func (o *objectStorage) DownloadURL(name string) string {
+	if name == "" || strings.HasPrefix(name, o.cfg.ProfilePictureStorage.URLDownload) {
+		return name
+	}
+
+	return fmt.Sprintf("%s/%s", o.cfg.ProfilePictureStorage.URLDownload, name)
+}
+
+func (o *objectStorage) StripDownloadURL(url string) string {
+	if url == "" || !strings.HasPrefix(url, o.cfg.ProfilePictureStorage.URLDownload) {
+		return url
+	}
+
+	return strings.Replace(url, o.cfg.ProfilePictureStorage.URLDownload+"/", "", 1)
+}
+
+func (o *objectStorage) SQLAliasDownloadURL(name string) string {
+	return fmt.Sprintf("'%s/' || %s", o.cfg.ProfilePictureStorage.URLDownload, name)
+}
+
+func (o *objectStorage) SignedUploadURL(ctx context.Context, pictureName, contentType string) (*SignedUpload, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	now := time.Now()
+	expiration := o.cfg.ProfilePictureStorage.SignedUploadURLExpiration
+	headers := map[string]string{"Content-Type": contentType}
+
+	return &SignedUpload{
+		ExpiresAt:   time.New(now.Add(expiration)),
+		Headers:     headers,
+		URL:         o.signedURL(now, http.MethodPut, pictureName, headers, expiration),
+		Method:      http.MethodPut,
+		PictureName: pictureName,
+	}, nil
+}
+
+func (o *objectStorage) PictureExists(ctx context.Context, pictureName string) (bool, error) {
+	return pictureExists(ctx, o.signedURL(time.Now(), http.MethodHead, pictureName, nil, internalRequestsSignedURLExpiration))
+}
+
+func (o *objectStorage) UploadPicture(ctx context.Context, data *multipart.FileHeader, oldFileName string) (err error) {
+	defer func() {
+		if err == nil {
+			err = errors.Wrapf(o.deletePicture(ctx, oldFileName), "failed to delete old picture %v", oldFileName)
+		}
+	}()
+	if data == nil || data.Size == 0 {
+		return nil
+	}
+	file, err := data.Open()
+	if err != nil {
+		return errors.Wrapf(err, "error opening file %v", data.Filename)
+	}
+	defer func() {
+		log.Error(errors.Wrapf(file.Close(), "error closing file %v", data.Filename))
+	}()
+	fileData, err := io.ReadAll(file)
+	if err != nil {
+		return errors.Wrapf(err, "error reading file %v", data.Filename)
+	}
+	var headers map[string]string
+	if contentType := data.Header.Get("Content-Type"); contentType != "" {
+		headers = map[string]string{"Content-Type": contentType}
+	}
+	resp, err := storageReq(ctx).
+		SetHeaders(headers).
+		SetBodyBytes(fileData).
+		Put(o.signedURL(time.Now(), http.MethodPut, data.Filename, headers, internalRequestsSignedURLExpiration))
+
+	return errors.Wrapf(checkResponse(resp, err), "error uploading picture %v", data.Filename)
+}
+
+func (o *objectStorage) deletePicture(ctx context.Context, name string) error {
+	filename := o.StripDownloadURL(name)
+	if filename == "" || (o.ignoreFilesRegex != nil && o.ignoreFilesRegex.MatchString(filename)) {
+		return nil
+	}
+	resp, err := storageReq(ctx).Delete(o.signedURL(time.Now(), http.MethodDelete, filename, nil, internalRequestsSignedURLExpiration))
+	if err == nil && resp.GetStatusCode() == http.StatusNotFound {
+		return nil
+	}
+
+	return errors.Wrap(checkResponse(resp, err), "delete picture request failed")
+}
+
+func (o *objectStorage) signedURL(now *time.Time, method, pictureName string, headers map[string]string, expiration stdlibtime.Duration) string {
+	return objectstorage.PresignURL(&objectstorage.PresignRequest{
+		Now:        *now.Time,
+		Headers:    headers,
+		Method:     method,
+		Scheme:     o.endpoint.Scheme,
+		Host:       o.endpoint.Host,
+		Path:       "/" + path.Join(o.cfg.ProfilePictureStorage.Bucket, o.cfg.ProfilePictureStorage.PathPrefix, pictureName),
+		Region:     o.cfg.ProfilePictureStorage.Region,
+		AccessKey:  o.cfg.ProfilePictureStorage.Credentials.AccessKeyID,
+		SecretKey:  o.cfg.ProfilePictureStorage.Credentials.SecretAccessKey,
+		Expiration: expiration,
+	})
+}
+
+func checkResponse(resp *req.Response, err error) error {
+	if err == nil && !resp.IsSuccessState() {
+		body, rErr := resp.ToString()
+		log.Error(rErr)
+
+		err = errors.Errorf("request failed with status: %v,body: %v", resp.GetStatusCode(), body)
+	}
+
+	return err
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package picturestorage
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/multimedia/picture"
+)
+
+func New(applicationYAMLKey string, ignoreFilesRegex ...string) Client {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+
+	switch cfg.ProfilePictureStorage.Backend {
+	case "", BunnyBackend:
+		return &bunny{Client: picture.New(applicationYAMLKey, ignoreFilesRegex...)}
+	case S3Backend, GCSBackend:
+		return newObjectStorage(applicationYAMLKey, &cfg, ignoreFilesRegex...)
+	default:
+		log.Panic(errors.Errorf("unsupported profile picture storage backend `%v`", cfg.ProfilePictureStorage.Backend))
+
+		return nil
+	}
+}
+
+func (*bunny) SignedUploadURL(context.Context, string, string) (*SignedUpload, error) {
+	return nil, ErrSignedUploadNotSupported
+}
+
+func (b *bunny) PictureExists(ctx context.Context, pictureName string) (bool, error) {
+	return pictureExists(ctx, b.DownloadURL(pictureName))
+}
+
+func pictureExists(ctx context.Context, url string) (bool, error) {
+	if ctx.Err() != nil {
+		return false, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	resp, err := storageReq(ctx).Head(url)
+	if err != nil {
+		return false, errors.Wrap(err, "picture exists request failed")
+	}
+	switch {
+	case resp.IsSuccessState():
+		return true, nil
+	case resp.GetStatusCode() == http.StatusNotFound:
+		return false, nil
+	default:
+		return false, errors.Errorf("picture exists request failed with status: %v", resp.GetStatusCode())
+	}
+}
+
+//nolint:gomnd // Static config.
+func storageReq(ctx context.Context) *req.Request {
+	return req.
+		SetContext(ctx).
+		SetRetryBackoffInterval(10*stdlibtime.Millisecond, 1*stdlibtime.Second).
+		SetRetryHook(func(resp *req.Response, err error) {
+			switch {
+			case err != nil:
+				log.Error(errors.Wrapf(err, "picture storage request failed, retrying... "))
+			case resp.GetStatusCode() == http.StatusTooManyRequests:
+				log.Error(errors.New("rate limit for picture storage reached, retrying... "))
+			case resp.GetStatusCode() >= http.StatusInternalServerError:
+				log.Error(errors.New("picture storage request failed[internal server error], retrying... "))
+			}
+		}).
+		SetRetryCount(25).
+		SetRetryCondition(func(resp *req.Response, err error) bool {
+			return err != nil || resp.GetStatusCode() == http.StatusTooManyRequests || resp.GetStatusCode() >= http.StatusInternalServerError
+		})
+}
+
+func loadFromEnv(applicationYAMLKey, env string) string {
+	module := strings.ToUpper(strings.ReplaceAll(strings.ReplaceAll(applicationYAMLKey, "-", "_"), "/", "_"))
+	if val := os.Getenv(module + "_" + env); val != "" {
+		return val
+	}
+
+	return os.Getenv(env)
+}
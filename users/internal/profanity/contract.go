@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package profanity
+
+import (
+	"embed"
+	"strings"
+)
+
+// Public API.
+
+type (
+	Screener interface {
+		// Screen returns the first blocklisted word found in the text, using the dictionary of the language
+		// alongside the one of the default language, because names are often written in it, regardless of the user's language.
+		Screen(language, text string) (word string, found bool)
+	}
+)
+
+// Private API.
+
+const (
+	dictionariesDir         = "dictionaries"
+	dictionaryFileExtension = ".txt"
+	dictionaryComment       = "#"
+	wholeWordPrefix         = "="
+	defaultDefaultLanguage  = "en"
+)
+
+var (
+	//go:embed dictionaries
+	dictionariesFS embed.FS
+
+	//nolint:gochecknoglobals // It's stateless.
+	// leetReplacer undoes the usual character substitutions (ex. `sh1t`, `@ss`) and strips the accents, because dictionaries don't have them.
+	leetReplacer = strings.NewReplacer(
+		"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "8", "b", "@", "a", "$", "s", "!", "i",
+		"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+		"é", "e", "è", "e", "ê", "e", "ë", "e",
+		"í", "i", "ì", "i", "î", "i", "ï", "i",
+		"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o",
+		"ú", "u", "ù", "u", "û", "u", "ü", "u",
+		"ñ", "n", "ç", "c", "ß", "ss",
+	)
+)
+
+type (
+	screener struct {
+		dictionaries    map[string]*dictionary
+		allowlist       map[string]struct{}
+		defaultLanguage string
+	}
+	dictionary struct {
+		wholeWords map[string]struct{}
+		substrings []string
+	}
+	// | config holds the configuration of this package mounted from `application.yaml`.
+	config struct {
+		ProfanityScreening struct {
+			// Dictionaries extend the embedded ones, per language, using the same format.
+			Dictionaries    map[string][]string `yaml:"dictionaries"`
+			DefaultLanguage string              `yaml:"defaultLanguage"`
+			// Allowlist holds the words that must not be flagged, even if they contain blocklisted ones (ex. `scunthorpe`).
+			Allowlist []string `yaml:"allowlist"`
+			Enabled   bool     `yaml:"enabled"`
+		} `yaml:"profanityScreening"`
+	}
+)
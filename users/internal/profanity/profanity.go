@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package profanity
+
+import (
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// New returns the configured screener, or nil if screening is disabled.
+func New(applicationYAMLKey string) Screener {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+	if !cfg.ProfanityScreening.Enabled {
+		return nil
+	}
+	s, err := newScreener(cfg.ProfanityScreening.DefaultLanguage, cfg.ProfanityScreening.Dictionaries, cfg.ProfanityScreening.Allowlist)
+	log.Panic(errors.Wrap(err, "failed to build the profanity screener")) //nolint:revive // Intended.
+
+	return s
+}
+
+func newScreener(defaultLanguage string, extraDictionaries map[string][]string, allowlist []string) (*screener, error) {
+	if defaultLanguage == "" {
+		defaultLanguage = defaultDefaultLanguage
+	}
+	s := &screener{
+		dictionaries:    make(map[string]*dictionary),
+		allowlist:       make(map[string]struct{}, len(allowlist)),
+		defaultLanguage: normalizeLanguage(defaultLanguage),
+	}
+	entries, err := dictionariesFS.ReadDir(dictionariesDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the embedded dictionaries")
+	}
+	for _, entry := range entries {
+		content, rErr := dictionariesFS.ReadFile(path.Join(dictionariesDir, entry.Name()))
+		if rErr != nil {
+			return nil, errors.Wrapf(rErr, "failed to read the embedded dictionary %v", entry.Name())
+		}
+		s.dictionary(strings.TrimSuffix(entry.Name(), dictionaryFileExtension)).add(strings.Split(string(content), "\n")...)
+	}
+	for language, words := range extraDictionaries {
+		s.dictionary(language).add(words...)
+	}
+	for _, word := range allowlist {
+		s.allowlist[normalize(word)] = struct{}{}
+	}
+
+	return s, nil
+}
+
+func (s *screener) dictionary(language string) *dictionary {
+	language = normalizeLanguage(language)
+	if s.dictionaries[language] == nil {
+		s.dictionaries[language] = &dictionary{wholeWords: make(map[string]struct{})}
+	}
+
+	return s.dictionaries[language]
+}
+
+func (d *dictionary) add(lines ...string) {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, dictionaryComment) {
+			continue
+		}
+		if word, wholeWord := strings.CutPrefix(line, wholeWordPrefix); wholeWord {
+			if word = normalize(word); word != "" {
+				d.wholeWords[word] = struct{}{}
+			}
+
+			continue
+		}
+		if word := normalize(line); word != "" {
+			d.substrings = append(d.substrings, word)
+		}
+	}
+}
+
+func (s *screener) Screen(language, text string) (word string, found bool) {
+	words := strings.FieldsFunc(leetReplacer.Replace(strings.ToLower(text)), func(r rune) bool { return !unicode.IsLetter(r) })
+	screened := words[:0]
+	for _, w := range words {
+		if _, allowed := s.allowlist[w]; !allowed {
+			screened = append(screened, w)
+		}
+	}
+	// Separators are ignored for the substrings, so that `f.u.c.k` or `fu_ck` are found as well.
+	compact := strings.Join(screened, "")
+	for _, dict := range []*dictionary{s.dictionaries[normalizeLanguage(language)], s.dictionaries[s.defaultLanguage]} {
+		if dict == nil {
+			continue
+		}
+		for _, w := range screened {
+			if _, blocked := dict.wholeWords[w]; blocked {
+				return w, true
+			}
+		}
+		for _, substring := range dict.substrings {
+			if strings.Contains(compact, substring) {
+				return substring, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func normalize(word string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return r
+		}
+
+		return -1
+	}, leetReplacer.Replace(strings.ToLower(strings.TrimSpace(word))))
+}
+
+// normalizeLanguage maps locales like `pt-BR` or `pt_BR` to their language, i.e. `pt`.
+func normalizeLanguage(language string) string {
+	language, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(language)), "-")
+	language, _, _ = strings.Cut(language, "_")
+
+	return language
+}
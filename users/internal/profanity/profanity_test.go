@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package profanity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScreen(t *testing.T) {
+	t.Parallel()
+	s, err := newScreener("", map[string][]string{"es": {"=tonto", "imbecil"}}, []string{"Scunthorpe"})
+	require.NoError(t, err)
+
+	for _, text := range []string{"John", "Doe", "jdoe.1990", "Dickens", "cocktail", "computer", "Scunthorpe", "", "..."} {
+		word, found := s.Screen("en", text)
+		assert.False(t, found, text)
+		assert.Empty(t, word, text)
+	}
+	for text, expected := range map[string]string{
+		"fuck":         "fuck",
+		"xxFuCkxx":     "fuck",
+		"f.u.c.k":      "fuck",
+		"sh1t.happens": "shit",
+		"john.dick":    "dick",
+		"big@ss":       "",
+		"big.@ss":      "ass",
+		"Mierda":       "",
+	} {
+		word, found := s.Screen("en", text)
+		assert.Equal(t, expected != "", found, text)
+		assert.Equal(t, expected, word, text)
+	}
+	for text, expected := range map[string]string{
+		"Mierda":   "mierda",
+		"Imbécil":  "imbecil",
+		"tonto":    "tonto",
+		"tontorro": "",
+		"fuck":     "fuck",
+	} {
+		word, found := s.Screen("es-AR", text)
+		assert.Equal(t, expected != "", found, text)
+		assert.Equal(t, expected, word, text)
+	}
+	word, found := s.Screen("fr", "Scheiße")
+	assert.False(t, found)
+	assert.Empty(t, word)
+	word, found = s.Screen("de_DE", "Scheiße")
+	assert.True(t, found)
+	assert.Equal(t, "scheisse", word)
+}
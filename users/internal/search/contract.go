@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package search
+
+import (
+	"context"
+	stdlibtime "time"
+)
+
+// Public API.
+
+const (
+	MeilisearchProvider ProviderType = "meilisearch"
+)
+
+type (
+	ProviderType string
+	// Document is what's indexed for an user: the username is searched, the rest are only for filtering.
+	Document struct {
+		ID         string `json:"id"`
+		Username   string `json:"username"`
+		ReferredBy string `json:"referredBy"`
+		// The referrer of the referrer, so that the T2 referrals of an user can be found too.
+		ReferrerReferredBy string `json:"referrerReferredBy"`
+	}
+	// Query matches the documents whose username is similar to the keyword, typos included, and that are either
+	// T1 or T2 referrals of RelatedTo, or have one of the IDs.
+	Query struct {
+		Keyword   string
+		RelatedTo string
+		IDs       []string
+		Limit     uint64
+	}
+	Index interface {
+		// Name identifies the index, so that it's backfilled once, even if the provider or its config changes.
+		Name() string
+		// Upsert indexes the documents, replacing the previous ones of the same users.
+		Upsert(ctx context.Context, docs ...*Document) error
+		// Delete removes the document of the user, if any.
+		Delete(ctx context.Context, id string) error
+		// Search returns the IDs of the matching documents, the most relevant first.
+		Search(ctx context.Context, q *Query) ([]string, error)
+	}
+)
+
+// Private API.
+
+const (
+	requestDeadline = 5 * stdlibtime.Second
+	apiKeyEnv       = "USER_SEARCH_INDEX_API_KEY" //nolint:gosec // It's just the name.
+)
+
+type (
+	// | meilisearch uses a https://www.meilisearch.com index. Its document IDs can't have `:`, so the user IDs are encoded into `key`.
+	meilisearch struct {
+		cfg *config
+	}
+	meilisearchDocument struct {
+		Key string `json:"key"`
+		*Document
+	}
+	meilisearchSearchRequest struct {
+		Q                    string   `json:"q"`
+		Filter               string   `json:"filter"`
+		AttributesToRetrieve []string `json:"attributesToRetrieve"`
+		Limit                uint64   `json:"limit"`
+	}
+	meilisearchSearchResponse struct {
+		Hits []struct {
+			ID string `json:"id"`
+		} `json:"hits"`
+	}
+	meilisearchSettings struct {
+		SearchableAttributes []string `json:"searchableAttributes"`
+		FilterableAttributes []string `json:"filterableAttributes"`
+	}
+	meilisearchError struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	}
+	// | config holds the configuration of this package mounted from `application.yaml`.
+	config struct {
+		UserSearchIndex struct {
+			Meilisearch struct {
+				URL    string `yaml:"url"`
+				Index  string `yaml:"index"`
+				APIKey string `yaml:"apiKey"`
+			} `yaml:"meilisearch"`
+			Provider ProviderType `yaml:"provider"`
+		} `yaml:"userSearchIndex"`
+	}
+)
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package search
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// New returns the configured index, or nil if there's none, in which case the users are searched in the database.
+func New(applicationYAMLKey string) Index {
+	var cfg config
+	appcfg.MustLoadFromKey(applicationYAMLKey, &cfg)
+	switch cfg.UserSearchIndex.Provider {
+	case "":
+		return nil
+	case MeilisearchProvider:
+		return newMeilisearch(applicationYAMLKey, &cfg)
+	default:
+		log.Panic(errors.Errorf("unsupported user search index provider `%v`", cfg.UserSearchIndex.Provider))
+
+		return nil
+	}
+}
+
+func newMeilisearch(applicationYAMLKey string, cfg *config) *meilisearch {
+	meilisearchCfg := &cfg.UserSearchIndex.Meilisearch
+	if meilisearchCfg.APIKey == "" {
+		meilisearchCfg.APIKey = loadFromEnv(applicationYAMLKey, apiKeyEnv)
+	}
+	if meilisearchCfg.URL == "" || meilisearchCfg.Index == "" {
+		log.Panic(errors.New("userSearchIndex.meilisearch.url and userSearchIndex.meilisearch.index are required"))
+	}
+	meilisearchCfg.URL = strings.TrimSuffix(meilisearchCfg.URL, "/")
+	m := &meilisearch{cfg: cfg}
+	ctx, cancel := context.WithTimeout(context.Background(), requestDeadline)
+	defer cancel()
+	settings := &meilisearchSettings{
+		SearchableAttributes: []string{"username"},
+		FilterableAttributes: []string{"id", "referredBy", "referrerReferredBy"},
+	}
+	// It's applied asynchronously by meilisearch, so the searches fall back to the database until it is.
+	log.Error(errors.Wrap(m.do(ctx, http.MethodPatch, "/settings", settings, nil), "failed to update the meilisearch index settings"))
+
+	return m
+}
+
+func (m *meilisearch) Name() string {
+	return fmt.Sprintf("%v:%v/%v", MeilisearchProvider, m.cfg.UserSearchIndex.Meilisearch.URL, m.cfg.UserSearchIndex.Meilisearch.Index)
+}
+
+func (m *meilisearch) Upsert(ctx context.Context, docs ...*Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	meilisearchDocs := make([]*meilisearchDocument, 0, len(docs))
+	for _, doc := range docs {
+		meilisearchDocs = append(meilisearchDocs, &meilisearchDocument{Key: documentKey(doc.ID), Document: doc})
+	}
+
+	return errors.Wrapf(m.do(ctx, http.MethodPut, "/documents?primaryKey=key", meilisearchDocs, nil), "failed to upsert %v documents", len(docs))
+}
+
+func (m *meilisearch) Delete(ctx context.Context, id string) error {
+	return errors.Wrapf(m.do(ctx, http.MethodDelete, "/documents/"+documentKey(id), nil, nil), "failed to delete the document of userID:%v", id)
+}
+
+func (m *meilisearch) Search(ctx context.Context, q *Query) ([]string, error) {
+	filters := make([]string, 0, 1+1+1)
+	if q.RelatedTo != "" {
+		filters = append(filters, fmt.Sprintf("referredBy = %[1]v OR referrerReferredBy = %[1]v", strconv.Quote(q.RelatedTo)))
+	}
+	if len(q.IDs) != 0 {
+		ids := make([]string, 0, len(q.IDs))
+		for _, id := range q.IDs {
+			ids = append(ids, strconv.Quote(id))
+		}
+		filters = append(filters, fmt.Sprintf("id IN [%v]", strings.Join(ids, ",")))
+	}
+	if len(filters) == 0 {
+		return []string{}, nil
+	}
+	search := &meilisearchSearchRequest{Q: q.Keyword, Filter: strings.Join(filters, " OR "), AttributesToRetrieve: []string{"id"}, Limit: q.Limit}
+	var result meilisearchSearchResponse
+	if err := m.do(ctx, http.MethodPost, "/search", search, &result); err != nil {
+		return nil, errors.Wrapf(err, "failed to search for `%v`", q.Keyword)
+	}
+	ids := make([]string, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ID)
+	}
+
+	return ids, nil
+}
+
+func (m *meilisearch) do(ctx context.Context, method, path string, body, result any) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, requestDeadline)
+	defer cancel()
+	var failure meilisearchError
+	request := req.
+		SetContext(reqCtx).
+		SetBearerAuthToken(m.cfg.UserSearchIndex.Meilisearch.APIKey).
+		SetErrorResult(&failure)
+	if body != nil {
+		request = request.SetBodyJsonMarshal(body)
+	}
+	if result != nil {
+		request = request.SetSuccessResult(result)
+	}
+	url := fmt.Sprintf("%v/indexes/%v%v", m.cfg.UserSearchIndex.Meilisearch.URL, m.cfg.UserSearchIndex.Meilisearch.Index, path)
+	resp, err := request.Send(method, url)
+	if err != nil {
+		return errors.Wrapf(err, "meilisearch %v %v failed", method, path)
+	}
+	if !resp.IsSuccessState() {
+		return errors.Errorf("meilisearch %v %v failed with status: %v, code: %v, message: %v", method, path, resp.GetStatusCode(), failure.Code, failure.Message)
+	}
+
+	return nil
+}
+
+func documentKey(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+func loadFromEnv(applicationYAMLKey, env string) string {
+	module := strings.ToUpper(strings.ReplaceAll(strings.ReplaceAll(applicationYAMLKey, "-", "_"), "/", "_"))
+	if val := os.Getenv(module + "_" + env); val != "" {
+		return val
+	}
+
+	return os.Getenv(env)
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package search
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeilisearchSearch(t *testing.T) {
+	t.Parallel()
+	var received meilisearchSearchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/indexes/users/search", request.URL.Path)
+		assert.Equal(t, "Bearer key", request.Header.Get("Authorization"))
+		body, err := io.ReadAll(request.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(body, &received))
+		writer.Header().Set("Content-Type", "application/json")
+		_, err = writer.Write([]byte(`{"hits":[{"id":"did:ethr:b"},{"id":"a"}]}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	var cfg config
+	cfg.UserSearchIndex.Meilisearch.URL, cfg.UserSearchIndex.Meilisearch.Index, cfg.UserSearchIndex.Meilisearch.APIKey = server.URL, "users", "key"
+	index := &meilisearch{cfg: &cfg}
+
+	ids, err := index.Search(context.Background(), &Query{Keyword: "jonh", RelatedTo: "r", IDs: []string{"a", `b"`}, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"did:ethr:b", "a"}, ids)
+	assert.Equal(t, meilisearchSearchRequest{
+		Q:                    "jonh",
+		Filter:               `referredBy = "r" OR referrerReferredBy = "r" OR id IN ["a","b\""]`,
+		AttributesToRetrieve: []string{"id"},
+		Limit:                10,
+	}, received)
+
+	ids, err = index.Search(context.Background(), &Query{Keyword: "jonh", Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestDocumentKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "ZGlkOmV0aHI6MHgx", documentKey("did:ethr:0x1"))
+	assert.Regexp(t, `^[a-zA-Z0-9_-]+$`, documentKey("did:ethr:0x4B73C58370AEfcEf86A6021afCDe5673511376B2"))
+}
@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package ulid
+
+// Private API.
+
+const (
+	// crockfordAlphabet is the base32 alphabet of the ULIDs: without I, L, O and U, so that they can't be misread.
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	encodedLength     = 26
+	timestampBytes    = 6
+	randomnessBytes   = 10
+	maxTimestamp      = 1<<(8*timestampBytes) - 1
+)
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package ulid
+
+import (
+	"crypto/rand"
+	"io"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+)
+
+// New returns a new ULID (https://github.com/ulid/spec): the millisecond timestamp followed by 80 random bits, Crockford base32 encoded,
+// so that they're sortable by the time they were created at and collisions are practically impossible, even across replicas.
+func New(now stdlibtime.Time) (string, error) {
+	return newWithEntropy(now, rand.Reader)
+}
+
+func newWithEntropy(now stdlibtime.Time, entropy io.Reader) (string, error) {
+	millis := now.UnixMilli()
+	if millis < 0 || millis > maxTimestamp {
+		return "", errors.Errorf("%v can't be encoded in a ulid", now)
+	}
+	var id [timestampBytes + randomnessBytes]byte
+	for ix := timestampBytes - 1; ix >= 0; ix-- {
+		id[ix] = byte(millis)
+		millis >>= 8
+	}
+	if _, err := io.ReadFull(entropy, id[timestampBytes:]); err != nil {
+		return "", errors.Wrap(err, "failed to read the randomness of the ulid")
+	}
+
+	return encode(&id), nil
+}
+
+// encode encodes the 128 bits in 26 characters of 5 bits each, the first one having only the 3 most significant bits.
+func encode(id *[timestampBytes + randomnessBytes]byte) string {
+	var encoded [encodedLength]byte
+	var buffer uint16
+	var bits uint
+	ix := encodedLength - 1
+	for byteIx := len(id) - 1; byteIx >= 0; byteIx-- {
+		buffer |= uint16(id[byteIx]) << bits
+		bits += 8
+		for bits >= 5 && ix >= 0 {
+			encoded[ix] = crockfordAlphabet[buffer&0x1F]
+			buffer >>= 5
+			bits -= 5
+			ix--
+		}
+	}
+	if ix >= 0 {
+		encoded[ix] = crockfordAlphabet[buffer&0x1F]
+	}
+
+	return string(encoded[:])
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package ulid
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	stdlibtime "time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	id, err := newWithEntropy(stdlibtime.UnixMilli(0), bytes.NewReader(make([]byte, randomnessBytes)))
+	require.NoError(t, err)
+	assert.Equal(t, "00000000000000000000000000", id)
+
+	id, err = newWithEntropy(stdlibtime.UnixMilli(maxTimestamp), bytes.NewReader(bytes.Repeat([]byte{0xFF}, randomnessBytes)))
+	require.NoError(t, err)
+	assert.Equal(t, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", id)
+
+	id, err = New(stdlibtime.UnixMilli(1469918176385))
+	require.NoError(t, err)
+	assert.Len(t, id, encodedLength)
+	assert.True(t, strings.HasPrefix(id, "01ARYZ6S41"), id)
+
+	_, err = newWithEntropy(stdlibtime.UnixMilli(0), bytes.NewReader(nil))
+	require.Error(t, err)
+	_, err = New(stdlibtime.UnixMilli(-1))
+	require.Error(t, err)
+}
+
+func TestNewIsSortable(t *testing.T) {
+	t.Parallel()
+	now := stdlibtime.Now()
+	earlier, err := New(now)
+	require.NoError(t, err)
+	later, err := New(now.Add(stdlibtime.Millisecond))
+	require.NoError(t, err)
+	assert.Less(t, earlier, later)
+	other, err := New(now)
+	require.NoError(t, err)
+	assert.NotEqual(t, earlier, other)
+}
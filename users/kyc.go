@@ -24,7 +24,7 @@ func (r *repository) TryResetKYCSteps(ctx context.Context, userID string) (*User
 				LEFT JOIN kyc_steps_reset_requests r
 					   ON r.user_id = u.id
 			WHERE u.id = $1`
-	if resp, err := storage.ExecOne[struct {
+	if resp, err := auditedExecOne[struct {
 		KYCStepsToReset []KYCStep `db:"kyc_steps_to_reset"`
 		User
 	}](ctx, r.db, sql, userID); err != nil {
@@ -68,7 +68,7 @@ func (r *repository) resetKYCSteps(ctx context.Context, userID string, kycStepsT
 	if err := multierror.Append(nil, responses...).ErrorOrNil(); err != nil {
 		return errors.Wrapf(err, "atleast one resetKYCStep failed for userID:%v", userID)
 	}
-	_, err := storage.Exec(ctx, r.db, `DELETE FROM kyc_steps_reset_requests WHERE user_id = $1`, userID)
+	_, err := auditedExec(ctx, r.db, `DELETE FROM kyc_steps_reset_requests WHERE user_id = $1`, userID)
 
 	return errors.Wrapf(err, "failed to delete kyc step reset request for userID:%v", userID)
 }
@@ -88,7 +88,7 @@ func (r *repository) resetKYCStep(ctx context.Context, userID string, step KYCSt
 	sql := `UPDATE kyc_steps_reset_requests 
 			SET kyc_steps_to_reset = array_remove(kyc_steps_to_reset, $2::smallint)
 			WHERE user_id = $1`
-	if updated, err := storage.Exec(ctx, r.db, sql, userID, step); err != nil || updated == 0 {
+	if updated, err := auditedExec(ctx, r.db, sql, userID, step); err != nil || updated == 0 {
 		if updated == 0 {
 			err = errors.Wrapf(ErrNotFound, "failed to remove step[%v] from kyc_steps_reset_requests for userID:%v", step, userID)
 		}
@@ -111,9 +111,18 @@ func init() { //nolint:gochecknoinits // It's the only way to tweak the client.
 	req.DefaultClient().GetClient().Timeout = requestDeadline
 }
 
-//nolint:gomnd,funlen // Specific config.
 func (r *repository) resetFacialRecognitionKYCStep(ctx context.Context, userID string) error {
-	if resp, err := req.
+	_, _, err := r.deleteFaceAuthState(ctx, userID)
+
+	return err
+}
+
+// deleteFaceAuthState deletes everything the face auth service (and, through it, the provider) holds about the user.
+// It also returns the status code and the body of the response, to be kept as a proof of deletion, if needed.
+//
+//nolint:gomnd,funlen // Specific config.
+func (r *repository) deleteFaceAuthState(ctx context.Context, userID string) (statusCode int, body string, err error) {
+	resp, err := req.
 		SetContext(ctx).
 		SetRetryCount(25).
 		SetRetryBackoffInterval(10*stdlibtime.Millisecond, 1*stdlibtime.Second).
@@ -136,13 +145,14 @@ func (r *repository) resetFacialRecognitionKYCStep(ctx context.Context, userID s
 		SetBodyJsonMarshal(&struct {
 			UserID string `json:"userId"`
 		}{UserID: userID}).
-		Delete(r.cfg.KYC.KYCStep1ResetURL); err != nil {
-		return errors.Wrapf(err, "failed to delete face auth state for userID:%v", userID)
-	} else if statusCode := resp.GetStatusCode(); statusCode != http.StatusOK && statusCode != http.StatusNoContent {
-		return errors.Errorf("[%v]failed to delete face auth state for userID:%v", statusCode, userID)
-	} else if _, err2 := resp.ToBytes(); err2 != nil {
-		return errors.Wrapf(err2, "failed to read body of delete face auth state request for userID:%v", userID)
+		Delete(r.cfg.KYC.KYCStep1ResetURL)
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "failed to delete face auth state for userID:%v", userID)
+	} else if statusCode = resp.GetStatusCode(); statusCode != http.StatusOK && statusCode != http.StatusNoContent {
+		return statusCode, "", errors.Errorf("[%v]failed to delete face auth state for userID:%v", statusCode, userID)
+	} else if body, err = resp.ToString(); err != nil {
+		return statusCode, "", errors.Wrapf(err, "failed to read body of delete face auth state request for userID:%v", userID)
 	} else { //nolint:revive // .
-		return nil
+		return statusCode, body, nil
 	}
 }
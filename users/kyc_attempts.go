@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// CheckKYCStepAttempt returns ErrKYCStepAttemptsExceeded, alongside the attempts (to know when to retry), if the user can't attempt the step now.
+func (r *repository) CheckKYCStepAttempt(ctx context.Context, userID UserID, step KYCStep) (*KYCStepAttempts, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	attempts, err := r.getKYCStepAttempts(ctx, userID, step)
+	if err != nil || attempts == nil || attempts.NextAllowedAt == nil {
+		return attempts, err
+	}
+
+	return attempts, errors.Wrapf(ErrKYCStepAttemptsExceeded, "kyc step %v can be attempted again at %v by userID:%v", step, attempts.NextAllowedAt, userID)
+}
+
+func (r *repository) RecordKYCStepAttempt(ctx context.Context, userID UserID, step KYCStep, successful bool) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	if r.kycAttemptLimit(step) == nil {
+		return nil
+	}
+	if step == LivenessDetectionKYCStep {
+		step = FacialRecognitionKYCStep
+	}
+	sql := `INSERT INTO kyc_step_attempts (attempted_at, kyc_step, successful, user_id) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`
+	_, err := auditedExec(ctx, r.db, sql, time.Now().Time, step, successful, userID)
+
+	return errors.Wrapf(err, "failed to record kyc step %v attempt for userID:%v", step, userID)
+}
+
+func (r *repository) kycAttemptLimit(step KYCStep) *kycAttemptLimit {
+	var limit *kycAttemptLimit
+	switch step { //nolint:exhaustive // Only these have limits.
+	case FacialRecognitionKYCStep, LivenessDetectionKYCStep:
+		limit = &r.cfg.live().KYCAttemptLimits.FacialRecognition
+	case QuizKYCStep:
+		limit = &r.cfg.live().KYCAttemptLimits.Quiz
+	default:
+		return nil
+	}
+	if limit.MaxAttempts == 0 {
+		return nil
+	}
+
+	return limit
+}
+
+// getKYCStepAttempts counts only the failed attempts since the last successful one, in the last `Cooldown`.
+// If there are too many of them, the step can be attempted again when the oldest of the last `MaxAttempts` leaves the window.
+func (r *repository) getKYCStepAttempts(ctx context.Context, userID UserID, step KYCStep) (*KYCStepAttempts, error) {
+	limit := r.kycAttemptLimit(step)
+	if limit == nil {
+		return nil, nil //nolint:nilnil // Nope.
+	}
+	if step == LivenessDetectionKYCStep {
+		step = FacialRecognitionKYCStep
+	}
+	now := time.Now()
+	sql := `SELECT attempted_at
+			FROM kyc_step_attempts
+			WHERE user_id = $1
+			  AND kyc_step = $2
+			  AND successful = false
+			  AND attempted_at > $3
+			  AND attempted_at > COALESCE((SELECT max(attempted_at)
+										   FROM kyc_step_attempts
+										   WHERE user_id = $1
+											 AND kyc_step = $2
+											 AND successful = true), '-infinity'::timestamp)
+			ORDER BY attempted_at DESC
+			LIMIT $4`
+	failed, err := auditedSelect[struct{ AttemptedAt *time.Time }](ctx, r.db, sql, userID, step, now.Add(-limit.Cooldown), limit.MaxAttempts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select failed kyc step %v attempts for userID:%v", step, userID)
+	}
+	attempts := &KYCStepAttempts{MaxAttempts: limit.MaxAttempts}
+	if failedCount := uint64(len(failed)); failedCount < limit.MaxAttempts {
+		attempts.RemainingAttempts = limit.MaxAttempts - failedCount
+	} else {
+		attempts.NextAllowedAt = time.New(failed[len(failed)-1].AttemptedAt.Add(limit.Cooldown))
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// GetKYCFunnel returns the KYC funnel of the last `days` (UTC) days, the current one first, alongside the totals of all of them.
+func (r *repository) GetKYCFunnel(ctx context.Context, days uint64) (kfs *KYCFunnelStatistics, lastUpdatedAt *time.Time, err error) {
+	if ctx.Err() != nil {
+		return nil, nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	today := time.Now().Truncate(hoursInOneDay * stdlibtime.Hour)
+	from := today.Add(-stdlibtime.Duration(days-1) * hoursInOneDay * stdlibtime.Hour)
+	version, err := r.statisticsVersion(ctx, `SELECT max(updated_at) AS version FROM kyc_funnel_statistics WHERE day >= $1`, from)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get the version of the kyc funnel")
+	}
+	cacheKey := kycFunnelStatisticsCacheKey(days)
+	if cached, cachedLastUpdatedAt, found := r.statisticsCache.get(cacheKey, version); found {
+		return cached.(*KYCFunnelStatistics), cachedLastUpdatedAt, nil //nolint:forcetypeassert // We know for sure.
+	}
+	sql := `SELECT day, kyc_step, entered, passed, failed, blocked,
+				   max(updated_at) OVER () AS last_updated_at
+			FROM kyc_funnel_statistics
+			WHERE day >= $1
+			ORDER BY day DESC, kyc_step`
+	rows, err := auditedSelect[kycFunnelStatistics](ctx, r.db, sql, from)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to select kyc funnel statistics since %v", from)
+	}
+	kfs = &KYCFunnelStatistics{TimeSeries: make([]*KYCFunnelDataPoint, 0, days), Steps: make([]*KYCStepFunnel, 0)}
+	totals := make(map[KYCStep]*KYCStepFunnel)
+	for day := uint64(0); day < days; day++ {
+		date := time.New(today.Add(-stdlibtime.Duration(day) * hoursInOneDay * stdlibtime.Hour))
+		dataPoint := &KYCFunnelDataPoint{Date: date, Steps: make([]*KYCStepFunnel, 0)}
+		for _, row := range rows {
+			if !row.Day.Equal(*date.Time) {
+				continue
+			}
+			dataPoint.Steps = append(dataPoint.Steps, &row.KYCStepFunnel)
+			if totals[row.Step] == nil {
+				totals[row.Step] = &KYCStepFunnel{Step: row.Step}
+				kfs.Steps = append(kfs.Steps, totals[row.Step])
+			}
+			totals[row.Step].add(&row.KYCStepFunnel)
+			lastUpdatedAt = row.LastUpdatedAt
+		}
+		kfs.TimeSeries = append(kfs.TimeSeries, dataPoint)
+	}
+	sort.Slice(kfs.Steps, func(i, j int) bool { return kfs.Steps[i].Step < kfs.Steps[j].Step })
+	r.statisticsCache.set(cacheKey, kfs, lastUpdatedAt, version)
+
+	return kfs, lastUpdatedAt, nil
+}
+
+func (f *KYCStepFunnel) add(other *KYCStepFunnel) {
+	f.Entered += other.Entered
+	f.Passed += other.Passed
+	f.Failed += other.Failed
+	f.Blocked += other.Blocked
+}
+
+// recordKYCFunnel counts the KYC transitions of the user, from before to after, in the funnel of the current day.
+func (r *repository) recordKYCFunnel(ctx context.Context, before, after *User) error {
+	transitions := kycFunnelTransitions(before, after)
+	if len(transitions) == 0 {
+		return nil
+	}
+	now := time.Now()
+	values := make([]string, 0, len(transitions))
+	params := []any{now.Truncate(hoursInOneDay * stdlibtime.Hour), now.Time}
+	for _, transition := range transitions {
+		ix := len(params) + 1
+		values = append(values, fmt.Sprintf("($1, $2, $%v, $%v, $%v, $%v, $%v)", ix, ix+1, ix+2, ix+3, ix+4)) //nolint:gomnd // The columns.
+		params = append(params, transition.Step, transition.Entered, transition.Passed, transition.Failed, transition.Blocked)
+	}
+	sql := fmt.Sprintf(`INSERT INTO kyc_funnel_statistics (day, updated_at, kyc_step, entered, passed, failed, blocked)
+						VALUES %v
+						ON CONFLICT (day, kyc_step) DO UPDATE
+							SET entered = kyc_funnel_statistics.entered + EXCLUDED.entered,
+								passed = kyc_funnel_statistics.passed + EXCLUDED.passed,
+								failed = kyc_funnel_statistics.failed + EXCLUDED.failed,
+								blocked = kyc_funnel_statistics.blocked + EXCLUDED.blocked,
+								updated_at = EXCLUDED.updated_at`, strings.Join(values, ","))
+	if _, err := auditedExec(ctx, r.db, sql, params...); err != nil {
+		return errors.Wrapf(err, "failed to record kyc funnel transitions %#v for userID:%v", transitions, after.ID)
+	}
+
+	return nil
+}
+
+// kycFunnelTransitions derives the transitions from the kycSteps* fields:
+//   - a step is entered when it gets its first kycStepsLastUpdatedAt, i.e. when it's attempted for the first time;
+//   - it's passed when kycStepPassed reaches it;
+//   - it's blocked when kycStepBlocked becomes it;
+//   - it's failed when the last attempted step gets a new kycStepsLastUpdatedAt, but it's neither passed, nor blocked.
+func kycFunnelTransitions(before, after *User) []*KYCStepFunnel {
+	transitions := make([]*KYCStepFunnel, 0)
+	transition := func(step KYCStep) *KYCStepFunnel {
+		for _, t := range transitions {
+			if t.Step == step {
+				return t
+			}
+		}
+		transitions = append(transitions, &KYCStepFunnel{Step: step})
+
+		return transitions[len(transitions)-1]
+	}
+	attemptedBefore, attemptedAfter := kycStepsLastUpdatedAt(before), kycStepsLastUpdatedAt(after)
+	for step := KYCStep(len(attemptedBefore)) + 1; step <= KYCStep(len(attemptedAfter)); step++ {
+		transition(step).Entered++
+	}
+	passedAfter := kycStepOrNone(after.KYCStepPassed)
+	for step := kycStepOrNone(before.KYCStepPassed) + 1; step <= passedAfter; step++ {
+		transition(step).Passed++
+	}
+	blockedAfter := kycStepOrNone(after.KYCStepBlocked)
+	if blockedAfter != NoneKYCStep && blockedAfter != kycStepOrNone(before.KYCStepBlocked) {
+		transition(blockedAfter).Blocked++
+	}
+	if last := KYCStep(len(attemptedAfter)); last > passedAfter && last != blockedAfter {
+		if int(last) > len(attemptedBefore) || attemptedBefore[last-1].IsNil() ||
+			(!attemptedAfter[last-1].IsNil() && !attemptedAfter[last-1].Equal(*attemptedBefore[last-1].Time)) {
+			transition(last).Failed++
+		}
+	}
+
+	return transitions
+}
+
+func kycStepsLastUpdatedAt(usr *User) []*time.Time {
+	if usr == nil || usr.KYCStepsLastUpdatedAt == nil {
+		return nil
+	}
+
+	return *usr.KYCStepsLastUpdatedAt
+}
+
+func kycStepOrNone(step *KYCStep) KYCStep {
+	if step == nil {
+		return NoneKYCStep
+	}
+
+	return *step
+}
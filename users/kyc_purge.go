@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// PurgeKYCData deletes the user's applicant data at the provider (the same way a facial recognition reset does)
+// and the local KYC artifacts, and records the provider's response as a proof of deletion.
+func (r *repository) PurgeKYCData(ctx context.Context, userID, requestedBy UserID) (*KYCDataPurge, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	if _, err := r.getUserByID(ctx, userID); err != nil {
+		return nil, errors.Wrapf(err, "failed to get user for userID:%v", userID)
+	}
+
+	return r.purgeKYCData(ctx, userID, requestedBy)
+}
+
+func (r *repository) purgeKYCData(ctx context.Context, userID, requestedBy UserID) (*KYCDataPurge, error) {
+	statusCode, body, err := r.deleteFaceAuthState(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to delete the applicant data at the provider for userID:%v", userID)
+	}
+	purge := &KYCDataPurge{
+		PurgedAt:           time.Now(),
+		UserID:             userID,
+		RequestedBy:        requestedBy,
+		ProviderResponse:   body,
+		ProviderStatusCode: statusCode,
+	}
+	sql := `WITH deleted_reset_requests AS (
+				DELETE FROM kyc_steps_reset_requests WHERE user_id = $2
+			)
+			INSERT INTO kyc_data_purges (purged_at, provider_status_code, user_id, requested_by, provider_response)
+			VALUES ($1, $3, $2, $4, $5)`
+	if _, err = auditedExec(ctx, r.db, sql, purge.PurgedAt.Time, userID, statusCode, requestedBy, body); err != nil {
+		return nil, errors.Wrapf(err, "failed to record kyc data purge %#v", purge)
+	}
+
+	return purge, nil
+}
+
+// hasKYCData is true if the user ever started the facial recognition, which is the only KYC step with data at a provider.
+func hasKYCData(usr *User) bool {
+	return usr.KYCStepsCreatedAt != nil && len(*usr.KYCStepsCreatedAt) > 0
+}
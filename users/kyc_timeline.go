@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// getKYCTimeline builds the progress of every KYC step the user ever reached, in order.
+// The face steps are handled by the provider, so we know about their attempts only if they're limited (they're recorded just for that).
+func (r *repository) getKYCTimeline(ctx context.Context, usr *User) ([]*KYCStepProgress, error) {
+	sql := `SELECT kyc_step, count(1) AS attempts
+			FROM (SELECT $2::smallint AS kyc_step FROM failed_quiz_sessions WHERE user_id = $1
+				  UNION ALL
+				  SELECT $2::smallint AS kyc_step FROM failed_quiz_sessions_history WHERE user_id = $1
+				  UNION ALL
+				  SELECT $2::smallint AS kyc_step FROM quiz_sessions WHERE user_id = $1 AND ended_successfully = true
+				  UNION ALL
+				  SELECT kyc_step FROM social_kyc_unsuccessful_attempts WHERE user_id = $1
+				  UNION ALL
+				  SELECT kyc_step FROM social_kyc_steps WHERE user_id = $1
+				  UNION ALL
+				  SELECT kyc_step FROM kyc_step_attempts WHERE user_id = $1 AND kyc_step = $3) attempts
+			GROUP BY kyc_step`
+	stepAttempts, err := auditedSelect[struct {
+		KYCStep  KYCStep
+		Attempts uint64
+	}](ctx, r.db, sql, usr.ID, QuizKYCStep, FacialRecognitionKYCStep)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to count kyc attempts for userID:%v", usr.ID)
+	}
+	attempts := make(map[KYCStep]uint64, len(stepAttempts))
+	lastStep := NoneKYCStep
+	for _, sa := range stepAttempts {
+		attempts[sa.KYCStep] = sa.Attempts
+		lastStep = max(lastStep, sa.KYCStep)
+	}
+	if usr.KYCStepsCreatedAt != nil {
+		lastStep = max(lastStep, KYCStep(len(*usr.KYCStepsCreatedAt)))
+	}
+	if usr.KYCStepPassed != nil {
+		lastStep = max(lastStep, *usr.KYCStepPassed)
+	}
+	if usr.KYCStepBlocked != nil {
+		lastStep = max(lastStep, *usr.KYCStepBlocked)
+	}
+	timeline := make([]*KYCStepProgress, 0, lastStep)
+	for step := FacialRecognitionKYCStep; step <= lastStep; step++ {
+		progress := &KYCStepProgress{
+			Step:     step,
+			Attempts: attempts[step],
+			Passed:   usr.KYCStepPassed != nil && step <= *usr.KYCStepPassed,
+			Blocked:  usr.KYCStepBlocked != nil && step == *usr.KYCStepBlocked,
+		}
+		if usr.KYCStepsCreatedAt != nil && int(step) <= len(*usr.KYCStepsCreatedAt) {
+			progress.CreatedAt = (*usr.KYCStepsCreatedAt)[step-1]
+		}
+		if usr.KYCStepsLastUpdatedAt != nil && int(step) <= len(*usr.KYCStepsLastUpdatedAt) {
+			progress.LastUpdatedAt = (*usr.KYCStepsLastUpdatedAt)[step-1]
+		}
+		if step != LivenessDetectionKYCStep { // It shares the attempts with the facial recognition.
+			if progress.KYCStepAttempts, err = r.getKYCStepAttempts(ctx, usr.ID, step); err != nil {
+				return nil, errors.Wrapf(err, "failed to getKYCStepAttempts(%v) for userID:%v", step, usr.ID)
+			}
+		}
+		timeline = append(timeline, progress)
+	}
+
+	return timeline, nil
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// startLocationCanonicalizer goes through all the users, one batch per tick, and starts over once it reaches the end.
+func (p *processor) startLocationCanonicalizer(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.LocationCanonicalization.Interval)
+	defer ticker.Stop()
+
+	var after UserID
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 5 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			next, err := p.canonicalizeLocations(reqCtx, after)
+			if err != nil {
+				log.Error(errors.Wrapf(err, "failed to canonicalizeLocations after userID:%v", after))
+			} else {
+				after = next
+			}
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// canonicalizeLocations canonicalizes the country and city of the next batch of users, after the provided one,
+// and returns the last user of the batch, or nothing, if it was the last batch.
+// The invalid countries are left as they are, as there's nothing to canonicalize them to.
+func (p *processor) canonicalizeLocations(ctx context.Context, after UserID) (UserID, error) {
+	if ctx.Err() != nil {
+		return "", errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sql := `SELECT id, country, city FROM users WHERE id > $1 ORDER BY id LIMIT $2`
+	usrs, err := auditedSelect[User](ctx, p.db, sql, after, p.cfg.LocationCanonicalization.BatchSize)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to select the users after userID:%v", after)
+	}
+	var canonicalized uint64
+	for _, usr := range usrs {
+		country, valid := p.CanonicalCountry(usr.Country)
+		if !valid {
+			country = usr.Country
+		}
+		city := p.CanonicalCity(country, usr.City)
+		if country == usr.Country && city == usr.City {
+			continue
+		}
+		if err = p.canonicalizeLocation(ctx, usr, &DeviceLocation{Country: country, City: city}); err != nil {
+			return "", errors.Wrapf(err, "failed to canonicalizeLocation for userID:%v", usr.ID)
+		}
+		canonicalized++
+	}
+	if canonicalized != 0 {
+		log.Info(fmt.Sprintf("canonicalized the location of %v users, after userID:%v", canonicalized, after))
+	}
+	if uint64(len(usrs)) < p.cfg.LocationCanonicalization.BatchSize {
+		return "", nil
+	}
+
+	return usrs[len(usrs)-1].ID, nil
+}
+
+func (p *processor) canonicalizeLocation(ctx context.Context, current *User, canonical *DeviceLocation) error {
+	usr, err := p.getUserByID(ctx, current.ID)
+	if err != nil {
+		if storage.IsErr(err, storage.ErrNotFound) {
+			return nil
+		}
+
+		return errors.Wrapf(err, "failed to get user for userID:%v", current.ID)
+	}
+	canonicalized := *usr
+	canonicalized.UpdatedAt = time.Now()
+	canonicalized.DeviceLocation = *canonical
+	sql := `UPDATE users
+			SET country = $4,
+				city = $5,
+				updated_at = $6
+			WHERE id = $1
+			  AND country = $2
+			  AND city = $3`
+	updated, err := auditedExec(ctx, p.db, sql, usr.ID, current.Country, current.City, canonical.Country, canonical.City, canonicalized.UpdatedAt.Time)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the location of userID:%v to %#v", usr.ID, canonical)
+	} else if updated == 0 { // It changed in the meantime.
+		return nil
+	}
+	us := &UserSnapshot{User: p.sanitizeUser(&canonicalized), Before: p.sanitizeUser(usr)}
+
+	return errors.Wrapf(p.sendUserSnapshotMessage(ctx, us), "failed to send canonicalized user message for %#v", us)
+}
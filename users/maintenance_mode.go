@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"slices"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// GetMaintenanceMode is checked on every API call, so it's reloaded only every `maintenanceMode.cacheTtl`.
+// The other replicas pick up the changes made at runtime in at most that long.
+func (r *repository) GetMaintenanceMode(ctx context.Context) (*MaintenanceMode, error) {
+	now := time.Now()
+	r.maintenanceMode.mx.RLock()
+	mode, loadedAt := r.maintenanceMode.mode, r.maintenanceMode.loadedAt
+	r.maintenanceMode.mx.RUnlock()
+	if loadedAt != nil && loadedAt.Add(r.cfg.live().MaintenanceMode.CacheTTL).After(*now.Time) {
+		return mode, nil
+	}
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+
+	return r.loadMaintenanceMode(ctx, now)
+}
+
+func (r *repository) SetMaintenanceMode(ctx context.Context, mode *MaintenanceMode) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	mode.UpdatedAt = time.Now()
+	if mode.Messages == nil {
+		mode.Messages = map[string]string{}
+	}
+	if mode.Allowlist == nil {
+		mode.Allowlist = []UserID{}
+	}
+	var eta *stdlibtime.Time
+	if mode.ETA != nil {
+		eta = mode.ETA.Time
+	}
+	sql := `INSERT INTO maintenance_mode (updated_at, eta, enabled, updated_by, messages, allowlist) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (id) DO UPDATE
+				SET updated_at = EXCLUDED.updated_at,
+					eta        = EXCLUDED.eta,
+					enabled    = EXCLUDED.enabled,
+					updated_by = EXCLUDED.updated_by,
+					messages   = EXCLUDED.messages,
+					allowlist  = EXCLUDED.allowlist`
+	if _, err := auditedExec(ctx, r.db, sql, mode.UpdatedAt.Time, eta, mode.Enabled, mode.UpdatedBy, mode.Messages, mode.Allowlist); err != nil {
+		return errors.Wrapf(err, "failed to upsert maintenance mode %#v", mode)
+	}
+	effective, err := r.loadMaintenanceMode(ctx, mode.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to reload the maintenance mode")
+	}
+	*mode = *effective
+
+	return nil
+}
+
+// loadMaintenanceMode merges the configured maintenance mode with the one set at runtime:
+// it's enabled if any of them is, the runtime messages replace the configured ones and the allowlists are combined.
+func (r *repository) loadMaintenanceMode(ctx context.Context, now *time.Time) (*MaintenanceMode, error) {
+	cfg := r.cfg.live()
+	mode := &MaintenanceMode{
+		Messages:  cfg.MaintenanceMode.Messages,
+		Allowlist: cfg.MaintenanceMode.Allowlist,
+		Enabled:   cfg.MaintenanceMode.Enabled,
+	}
+	sql := `SELECT updated_at, eta, updated_by, messages, allowlist, enabled FROM maintenance_mode`
+	stored, err := auditedGet[MaintenanceMode](ctx, r.db, sql)
+	if err != nil && !storage.IsErr(err, storage.ErrNotFound) {
+		return nil, errors.Wrap(err, "failed to get maintenance mode")
+	}
+	if stored != nil {
+		mode.UpdatedAt, mode.ETA, mode.UpdatedBy = stored.UpdatedAt, stored.ETA, stored.UpdatedBy
+		mode.Enabled = mode.Enabled || stored.Enabled
+		if len(stored.Messages) != 0 {
+			mode.Messages = stored.Messages
+		}
+		mode.Allowlist = append(slices.Clone(mode.Allowlist), stored.Allowlist...)
+	}
+	r.maintenanceMode.mx.Lock()
+	r.maintenanceMode.mode, r.maintenanceMode.loadedAt = mode, now
+	r.maintenanceMode.mx.Unlock()
+
+	return mode, nil
+}
@@ -33,6 +33,7 @@ func (s *miningSessionSource) Process(ctx context.Context, msg *messagebroker.Me
 		errors.Wrap(s.incrementTotalActiveUsersCount(ctx, ses), "failed to incrementTotalActiveUsersCount"),
 		errors.Wrap(s.updateTotalUsersCount(ctx, &UserSnapshot{User: usr}), "failed to updateTotalUsersCount"),
 		errors.Wrap(s.updateTotalUsersPerCountryCount(ctx, &UserSnapshot{User: usr}), "failed to updateTotalUsersPerCountryCount"),
+		errors.Wrap(s.updateLevels(ctx, ses.UserID), "failed to updateLevels"),
 	).ErrorOrNil(), "failed to process miningSession after LivenessDetectionKYCStep: %#v, user: %#v", ses, usr)
 }
 
@@ -74,18 +75,27 @@ func (u *User) IsHuman() bool {
 		!(*u.KYCStepsLastUpdatedAt)[LivenessDetectionKYCStep-1].IsNil()
 }
 
+// updateMiningSession counts the mining sessions of the user too, for its level, in the same statement, so that every one is counted once.
 func (s *miningSessionSource) updateMiningSession(ctx context.Context, ses *miningSession) (*User, error) {
 	sql := fmt.Sprintf(`
-		UPDATE users
-		SET updated_at = $1,
-			last_mining_started_at = $2,
-			last_mining_ended_at = $3
-		WHERE id = $4
-		  AND (last_mining_started_at IS NULL OR (extract(epoch from last_mining_started_at)::bigint/%[1]v) != (extract(epoch from $2::timestamp)::bigint/%[1]v))
-		  AND (last_mining_ended_at IS NULL OR (extract(epoch from last_mining_ended_at)::bigint/%[1]v) != (extract(epoch from $3::timestamp)::bigint/%[1]v))
-	    RETURNING *`,
+		WITH updated AS (
+			UPDATE users
+			SET updated_at = $1,
+				last_mining_started_at = $2,
+				last_mining_ended_at = $3
+			WHERE id = $4
+			  AND (last_mining_started_at IS NULL OR (extract(epoch from last_mining_started_at)::bigint/%[1]v) != (extract(epoch from $2::timestamp)::bigint/%[1]v))
+			  AND (last_mining_ended_at IS NULL OR (extract(epoch from last_mining_ended_at)::bigint/%[1]v) != (extract(epoch from $3::timestamp)::bigint/%[1]v))
+			RETURNING *
+		), counted AS (
+			INSERT INTO user_levels (user_id, mining_sessions)
+			SELECT id, 1 FROM updated
+			ON CONFLICT (user_id) DO UPDATE
+				SET mining_sessions = user_levels.mining_sessions + 1
+		)
+		SELECT * FROM updated`,
 		uint64(s.cfg.GlobalAggregationInterval.MinMiningSessionDuration/stdlibtime.Second))
-	usr, err := storage.ExecOne[User](ctx, s.db, sql,
+	usr, err := auditedExecOne[User](ctx, s.db, sql,
 		time.Now().Time,
 		ses.LastNaturalMiningStartedAt.Time,
 		ses.EndedAt.Time,
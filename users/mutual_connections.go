@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// GetMutualConnections reveals only what both users already share: a mutual contact has to have both of them in its agenda too,
+// so that the one-sided entries of the agenda of the other user aren't disclosed. The users blocked by any of them are left out.
+func (r *repository) GetMutualConnections(ctx context.Context, userID, otherUserID UserID) (*MutualConnections, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	type connections struct {
+		ID                   UserID   `db:"id"`
+		ReferredBy           UserID   `db:"referred_by"`
+		AgendaContactUserIDs []UserID `db:"agenda_contact_user_ids"`
+		Blocked              bool     `db:"blocked"`
+	}
+	sql := `SELECT u.id,
+				   u.referred_by,
+				   COALESCE(u.agenda_contact_user_ids, '{}'::TEXT[]) AS agenda_contact_user_ids,
+				   EXISTS (SELECT 1 FROM user_blocks b WHERE b.user_id = u.id AND b.blocked_user_id = ANY($1)) AS blocked
+			FROM users u
+			WHERE u.id = ANY($1)`
+	pair, err := auditedSelect[connections](ctx, r.db, sql, []UserID{userID, otherUserID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select the connections of userIDs:%v,%v", userID, otherUserID)
+	}
+	if len(pair) != 2 { //nolint:gomnd // The pair.
+		return nil, errors.Wrapf(ErrNotFound, "userIDs:%v,%v", userID, otherUserID)
+	}
+	mutual := &MutualConnections{Contacts: []*PublicUserInformation{}}
+	if pair[0].Blocked || pair[1].Blocked {
+		return mutual, nil
+	}
+	contactIDs := make([]UserID, 0, len(pair[0].AgendaContactUserIDs))
+	for _, contactID := range pair[0].AgendaContactUserIDs {
+		if slices.Contains(pair[1].AgendaContactUserIDs, contactID) {
+			contactIDs = append(contactIDs, contactID)
+		}
+	}
+	if referrerID := pair[0].ReferredBy; referrerID == pair[1].ReferredBy && referrerID != pair[0].ID && referrerID != pair[1].ID {
+		if mutual.SharedReferrer, err = r.getMutualConnection(ctx, userID, otherUserID, referrerID); err != nil {
+			return nil, errors.Wrapf(err, "failed to get the shared referrer of userIDs:%v,%v", userID, otherUserID)
+		}
+	}
+	if len(contactIDs) == 0 {
+		return mutual, nil
+	}
+	type contact struct {
+		PublicUserInformation
+		Total uint64 `db:"total"`
+	}
+	sql = `SELECT x.id,
+				  x.username,
+				  x.profile_picture_name,
+				  count(1) OVER () AS total
+		   FROM users x
+		   WHERE x.id = ANY($3)
+			 AND x.username != x.id
+			 AND NULLIF(x.phone_number_hash, '') IS NOT NULL
+			 AND x.agenda_contact_user_ids @> ARRAY[$1, $2]::TEXT[]
+			 AND NOT EXISTS (SELECT 1
+							 FROM user_blocks b
+							 WHERE (b.user_id = x.id AND b.blocked_user_id IN ($1, $2))
+								OR (b.blocked_user_id = x.id AND b.user_id IN ($1, $2)))
+		   ORDER BY x.username, x.id
+		   LIMIT $4`
+	contacts, err := auditedSelect[contact](ctx, r.db, sql, userID, otherUserID, contactIDs, maxMutualContacts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select the mutual contacts of userIDs:%v,%v", userID, otherUserID)
+	}
+	for _, mutualContact := range contacts {
+		mutualContact.ProfilePictureURL = r.pictureClient.DownloadURL(mutualContact.ProfilePictureURL)
+		mutual.Contacts = append(mutual.Contacts, &mutualContact.PublicUserInformation)
+		mutual.TotalContacts = mutualContact.Total
+	}
+
+	return mutual, nil
+}
+
+// getMutualConnection returns the public information of the user, unless it's blocked by, or it blocked, any of the pair.
+func (r *repository) getMutualConnection(ctx context.Context, userID, otherUserID, connectionID UserID) (*PublicUserInformation, error) {
+	sql := `SELECT x.id,
+				   x.username,
+				   x.profile_picture_name
+			FROM users x
+			WHERE x.id = $3
+			  AND x.username != x.id
+			  AND NOT EXISTS (SELECT 1
+							  FROM user_blocks b
+							  WHERE (b.user_id = x.id AND b.blocked_user_id IN ($1, $2))
+								 OR (b.blocked_user_id = x.id AND b.user_id IN ($1, $2)))`
+	connection, err := auditedGet[PublicUserInformation](ctx, r.db, sql, userID, otherUserID, connectionID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil //nolint:nilnil // Nope.
+		}
+
+		return nil, errors.Wrapf(err, "failed to get userID:%v", connectionID)
+	}
+	connection.ProfilePictureURL = r.pictureClient.DownloadURL(connection.ProfilePictureURL)
+
+	return connection, nil
+}
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	stdlibtime "time"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users/internal/encryption"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+// sealSensitiveUserInformation encrypts the phone number and the email, if the encryption is enabled, and sets their blind indexes,
+// for them to be looked up and be unique.
+// The placeholders (the user ID) are left as they are, so that the checks against them keep working.
+func (r *repository) sealSensitiveUserInformation(usr *User) error {
+	if r.piiCipher == nil {
+		return nil
+	}
+	if usr.PhoneNumber != "" && usr.PhoneNumber != usr.ID && !encryption.IsEncrypted(usr.PhoneNumber) {
+		encrypted, err := r.piiCipher.Encrypt(usr.PhoneNumber)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encrypt the phone number of userID:%v", usr.ID)
+		}
+		usr.PhoneNumberIndex = r.PhoneNumberIndex(usr.PhoneNumber)
+		usr.PhoneNumber = encrypted
+	}
+	if usr.Email != "" && usr.Email != usr.ID && !encryption.IsEncrypted(usr.Email) {
+		encrypted, err := r.piiCipher.Encrypt(usr.Email)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encrypt the email of userID:%v", usr.ID)
+		}
+		usr.EmailIndex = r.EmailIndex(usr.Email)
+		usr.Email = encrypted
+	}
+
+	return nil
+}
+
+// OpenSensitiveUserInformation decrypts the phone number and the email, if they're encrypted. If they can't be, they're blanked, since they're useless anyway.
+func (r *repository) OpenSensitiveUserInformation(info *SensitiveUserInformation) {
+	info.PhoneNumber = r.open("phone number", info.PhoneNumber)
+	info.Email = r.open("email", info.Email)
+}
+
+func (r *repository) open(what, value string) string {
+	if !encryption.IsEncrypted(value) {
+		return value
+	}
+	if r.piiCipher == nil {
+		log.Error(errors.Errorf("piiEncryption.keys are required for decrypting the %vs", what))
+
+		return ""
+	}
+	plaintext, err := r.piiCipher.Decrypt(value)
+	if err != nil {
+		log.Error(errors.Wrapf(err, "failed to decrypt %v", what))
+	}
+
+	return plaintext
+}
+
+func (r *repository) openMinimalUserProfiles(profiles []*MinimalUserProfile) {
+	for _, profile := range profiles {
+		r.OpenSensitiveUserInformation(&profile.SensitiveUserInformation)
+	}
+}
+
+// PhoneNumberIndex is the blind index of the digits of the phone number, so that it matches however it's formatted.
+// It's nil if the PII encryption is disabled.
+func (r *repository) PhoneNumberIndex(phoneNumber string) *string {
+	if r.piiCipher == nil {
+		return nil
+	}
+	index := r.piiCipher.BlindIndex(strings.Map(func(char rune) rune {
+		if unicode.IsDigit(char) {
+			return char
+		}
+
+		return -1
+	}, phoneNumber))
+
+	return &index
+}
+
+// EmailIndex is the blind index of the email, as it is, since it's looked up exactly.
+// It's nil if the PII encryption is disabled.
+func (r *repository) EmailIndex(email string) *string {
+	if r.piiCipher == nil {
+		return nil
+	}
+	index := r.piiCipher.BlindIndex(email)
+
+	return &index
+}
+
+// startPIIReencryptor encrypts the PII stored before the encryption was enabled and re-encrypts the one encrypted with other keys
+// than the primary one, so that the old keys can be removed once they're rotated.
+func (p *processor) startPIIReencryptor(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.PIIReencryption.Interval)
+	defer ticker.Stop()
+
+	p.undecryptablePII = make([]string, 0)
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 5 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(p.reencryptPII(reqCtx), "failed to reencryptPII"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *processor) reencryptPII(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	phoneNumbers, skippedPhoneNumbers, err := p.reencryptUserPII(ctx, "phone number", "phone_number", p.PhoneNumberIndex)
+	if err != nil {
+		return err
+	}
+	emails, skippedEmails, err := p.reencryptUserPII(ctx, "email", "email", p.EmailIndex)
+	if err != nil {
+		return err
+	}
+	reencrypted, skipped := phoneNumbers+emails, skippedPhoneNumbers+skippedEmails
+	for _, db := range p.allDBs() { // The dates of birth are routed to the residency clusters.
+		datesOfBirth, skippedDatesOfBirth, rErr := p.reencryptDatesOfBirth(ctx, db)
+		if rErr != nil {
+			return rErr
+		}
+		reencrypted, skipped = reencrypted+datesOfBirth, skipped+skippedDatesOfBirth
+	}
+	agendaContactNames, skippedAgendaContactNames, err := p.reencryptAgendaContactNames(ctx)
+	if err != nil {
+		return err
+	}
+	reencrypted, skipped = reencrypted+agendaContactNames, skipped+skippedAgendaContactNames
+	if reencrypted != 0 || skipped != 0 {
+		log.Info(fmt.Sprintf("reencrypted %v phone numbers, emails, dates of birth and agenda contact names and skipped %v undecryptable ones",
+			reencrypted, skipped))
+	}
+
+	return nil
+}
+
+// reencryptUserPII reencrypts the column of the users, which has its blind index in `<column>_index`, except for its placeholders (the user ID).
+func (p *processor) reencryptUserPII(
+	ctx context.Context, what, column string, index func(string) *string,
+) (reencrypted, skipped int, err error) {
+	sql := fmt.Sprintf(`SELECT id, %[1]v AS value
+			FROM users
+			WHERE %[1]v != id
+			  AND %[1]v != ''
+			  AND NOT starts_with(%[1]v, $1)
+			  AND %[1]v != ALL($3::text[])
+			LIMIT $2`, column)
+	usrs, err := auditedSelect[struct {
+		ID    UserID
+		Value string
+	}](ctx, p.db, sql, p.piiCipher.PrimaryKeyPrefix(), p.cfg.PIIReencryption.BatchSize, p.undecryptablePII)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to select the %vs to reencrypt", what)
+	}
+	userIDs, values := make([]UserID, 0, len(usrs)), make([]string, 0, len(usrs))
+	for _, usr := range usrs {
+		userIDs, values = append(userIDs, usr.ID), append(values, usr.Value)
+	}
+	undecryptable, err := encryption.Reencrypt(p.piiCipher, values, func(idx int, plaintext, ciphertext string) error {
+		// It's updated only if it wasn't changed in the meantime.
+		sql = fmt.Sprintf(`UPDATE users SET %[1]v = $3, %[1]v_index = $4 WHERE id = $1 AND %[1]v = $2`, column)
+		_, uErr := auditedExec(ctx, p.db, sql, userIDs[idx], values[idx], ciphertext, index(plaintext))
+
+		return errors.Wrapf(uErr, "failed to update the %v of userID:%v", what, userIDs[idx])
+	})
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to reencrypt the %vs", what)
+	}
+
+	return len(values) - len(undecryptable), p.skipUndecryptablePII(what, userIDs, values, undecryptable), nil
+}
+
+func (p *processor) reencryptDatesOfBirth(ctx context.Context, db *storage.DB) (reencrypted, skipped int, err error) {
+	sql := `SELECT user_id, date_of_birth FROM user_dates_of_birth WHERE NOT starts_with(date_of_birth, $1) AND date_of_birth != ALL($3::text[]) LIMIT $2`
+	datesOfBirth, err := auditedSelect[struct {
+		UserID      UserID
+		DateOfBirth string
+	}](ctx, db, sql, p.piiCipher.PrimaryKeyPrefix(), p.cfg.PIIReencryption.BatchSize, p.undecryptablePII)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to select the dates of birth to reencrypt")
+	}
+	userIDs, values := make([]UserID, 0, len(datesOfBirth)), make([]string, 0, len(datesOfBirth))
+	for _, dateOfBirth := range datesOfBirth {
+		userIDs, values = append(userIDs, dateOfBirth.UserID), append(values, dateOfBirth.DateOfBirth)
+	}
+	undecryptable, err := encryption.Reencrypt(p.piiCipher, values, func(idx int, _, reencrypted string) error {
+		sql = `UPDATE user_dates_of_birth SET date_of_birth = $3 WHERE user_id = $1 AND date_of_birth = $2`
+		_, uErr := auditedExec(ctx, db, sql, userIDs[idx], values[idx], reencrypted)
+
+		return errors.Wrapf(uErr, "failed to update the date of birth of userID:%v", userIDs[idx])
+	})
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to reencrypt the dates of birth")
+	}
+
+	return len(values) - len(undecryptable), p.skipUndecryptablePII("date of birth", userIDs, values, undecryptable), nil
+}
+
+// skipUndecryptablePII logs the values that can never be decrypted and excludes them from the next batches, returning how many they are.
+func (p *processor) skipUndecryptablePII(what string, userIDs []UserID, values []string, undecryptable []int) int {
+	for _, idx := range undecryptable {
+		log.Error(errors.Errorf("skipped the undecryptable %v of userID:%v", what, userIDs[idx]))
+		p.undecryptablePII = append(p.undecryptablePII, values[idx])
+	}
+
+	return len(undecryptable)
+}
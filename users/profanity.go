@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/terror"
+)
+
+// screenProfanity checks the user provided names against the blocklists of the language.
+// The field found to be profane is provided in the data of the terror, so that clients can highlight it.
+func (r *repository) screenProfanity(ctx context.Context, language string, usr, oldUsr *User) error {
+	if r.profanityScreener == nil || profanityOverridden(ctx) {
+		return nil
+	}
+	fields := []struct {
+		name, value string
+		changed     bool
+	}{
+		{"username", usr.Username, usr.Username != "" && (oldUsr == nil || usr.Username != oldUsr.Username)},
+		{"firstName", stringValue(usr.FirstName), usr.FirstName != nil && (oldUsr == nil || *usr.FirstName != stringValue(oldUsr.FirstName))},
+		{"lastName", stringValue(usr.LastName), usr.LastName != nil && (oldUsr == nil || *usr.LastName != stringValue(oldUsr.LastName))},
+	}
+	for _, field := range fields {
+		if !field.changed {
+			continue
+		}
+		if word, found := r.profanityScreener.Screen(language, field.value); found {
+			return errors.Wrapf(terror.New(ErrProfanity, map[string]any{"field": field.name}),
+				"%v `%v` of userID:%v contains the blocklisted `%v`", field.name, field.value, usr.ID, word)
+		}
+	}
+
+	return nil
+}
+
+// profanityOverridden is true when admins set the values themselves, ex. via rectifications.
+func profanityOverridden(ctx context.Context) bool {
+	overridden, _ := ctx.Value(profanityOverriddenCtxValueKey).(bool) //nolint:errcheck // Not needed.
+
+	return overridden
+}
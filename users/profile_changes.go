@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	stdlibtime "time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetProfileChanges(
+	ctx context.Context, userID UserID, sinceChecksum string, wait stdlibtime.Duration,
+) (*ProfileChanges, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	changed, err := r.waitForChecksumChange(ctx, userID, sinceChecksum, wait)
+	if err != nil || !changed {
+		return nil, errors.Wrapf(err, "failed to waitForChecksumChange for userID:%v", userID)
+	}
+	profile, err := r.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to GetUserByID for userID:%v", userID)
+	}
+	current, err := profileFields(ctx, profile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the profile fields of userID:%v", userID)
+	}
+	changes := &ProfileChanges{Checksum: profile.Checksum(), Changes: current, Full: true}
+	base, err := auditedGet[profileSyncBase](ctx, r.db, `SELECT checksum, profile FROM profile_sync_bases WHERE user_id = $1`, userID)
+	if err != nil && !storage.IsErr(err, storage.ErrNotFound) {
+		return nil, errors.Wrapf(err, "failed to get the profile sync base of userID:%v", userID)
+	}
+	if base != nil && base.Profile != nil && sinceChecksum != "" && base.Checksum == sinceChecksum {
+		changes.Changes, changes.Removed = diffProfileFields(*base.Profile, current)
+		changes.Full = false
+	}
+	sql := `INSERT INTO profile_sync_bases (updated_at, user_id, checksum, profile) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id) DO UPDATE
+				SET updated_at = EXCLUDED.updated_at,
+					checksum   = EXCLUDED.checksum,
+					profile    = EXCLUDED.profile`
+	if _, err = auditedExec(ctx, r.db, sql, time.Now().Time, userID, changes.Checksum, JSON(current)); err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert the profile sync base of userID:%v", userID)
+	}
+
+	return changes, nil
+}
+
+// waitForChecksumChange polls the checksum of the user, which is cheap, until it differs from sinceChecksum or `wait` passes.
+func (r *repository) waitForChecksumChange(ctx context.Context, userID UserID, sinceChecksum string, wait stdlibtime.Duration) (bool, error) {
+	deadline := stdlibtime.Now().Add(wait)
+	for {
+		usr, err := auditedGet[User](ctx, r.db, `SELECT updated_at FROM users WHERE id = $1`, userID)
+		if err != nil {
+			if storage.IsErr(err, storage.ErrNotFound) {
+				err = ErrNotFound
+			}
+
+			return false, errors.Wrapf(err, "failed to get the checksum of userID:%v", userID)
+		}
+		if usr.Checksum() != sinceChecksum {
+			return true, nil
+		}
+		if !stdlibtime.Now().Add(profileChangesPollInterval).Before(deadline) {
+			return false, nil
+		}
+		select {
+		case <-ctx.Done():
+			return false, errors.Wrap(ctx.Err(), "context failed while waiting for profile changes")
+		case <-stdlibtime.After(profileChangesPollInterval):
+		}
+	}
+}
+
+func profileFields(ctx context.Context, profile *UserProfile) (map[string]any, error) {
+	valueBytes, err := json.MarshalContext(ctx, profile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal %#v", profile)
+	}
+	fields := make(map[string]any)
+
+	return fields, errors.Wrapf(json.UnmarshalContext(ctx, valueBytes, &fields), "failed to unmarshal %v", string(valueBytes))
+}
+
+// diffProfileFields returns the top level fields that were added or changed and the ones that were removed.
+func diffProfileFields(base, current map[string]any) (changes map[string]any, removed []string) {
+	changes = make(map[string]any)
+	for field, value := range current {
+		if baseValue, found := base[field]; !found || !reflect.DeepEqual(baseValue, value) {
+			changes[field] = value
+		}
+	}
+	for field := range base {
+		if _, found := current[field]; !found {
+			removed = append(removed, field)
+		}
+	}
+	sort.Strings(removed)
+
+	return changes, removed
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// profileCompleteness is the percentage of the configured weights earned by the user.
+// It works both with the users from the DB and with the sanitized ones from the snapshots.
+func (c *config) profileCompleteness(usr *User) (percentage uint64, missing []ProfileElement) {
+	if !c.profileCompletenessEnabled() {
+		return 0, nil
+	}
+	weights := c.ProfileCompleteness.Weights
+	total := weights.ProfilePicture + weights.Country + weights.Email + weights.KYCSteps
+	var earned uint64
+	if usr.ProfilePictureURL != "" && !compiledDefaultProfilePictureNameRegex.MatchString(usr.ProfilePictureURL) {
+		earned += weights.ProfilePicture
+	} else if weights.ProfilePicture > 0 {
+		missing = append(missing, ProfilePictureProfileElement)
+	}
+	if usr.Country != "" {
+		earned += weights.Country
+	} else if weights.Country > 0 {
+		missing = append(missing, CountryProfileElement)
+	}
+	if usr.Email != "" && usr.Email != usr.ID {
+		earned += weights.Email
+	} else if weights.Email > 0 {
+		missing = append(missing, EmailProfileElement)
+	}
+	var passed KYCStep
+	if usr.KYCStepPassed != nil {
+		passed = min(*usr.KYCStepPassed, c.ProfileCompleteness.KYCStepsTarget)
+	}
+	if target := c.ProfileCompleteness.KYCStepsTarget; target > 0 {
+		earned += weights.KYCSteps * uint64(passed) / uint64(target)
+	}
+	if passed < c.ProfileCompleteness.KYCStepsTarget && weights.KYCSteps > 0 {
+		missing = append(missing, KYCStepsProfileElement)
+	}
+
+	return earned * 100 / total, missing //nolint:gomnd // It's a percentage.
+}
+
+func (c *config) profileCompletenessEnabled() bool {
+	weights := c.ProfileCompleteness.Weights
+
+	return weights.ProfilePicture+weights.Country+weights.Email+weights.KYCSteps > 0
+}
+
+// profileCompletenessThresholdsCrossed counts the configured thresholds reached by the percentage.
+// Without thresholds, every percentage is its own threshold.
+func (c *config) profileCompletenessThresholdsCrossed(percentage uint64) uint64 {
+	if len(c.ProfileCompleteness.Thresholds) == 0 {
+		return percentage
+	}
+	var crossed uint64
+	for _, threshold := range c.ProfileCompleteness.Thresholds {
+		if percentage >= threshold {
+			crossed++
+		}
+	}
+
+	return crossed
+}
+
+func (s *userSnapshotSource) sendProfileCompletenessChange(ctx context.Context, us *UserSnapshot) error {
+	if us.User == nil {
+		return nil
+	}
+	after, missing := s.cfg.profileCompleteness(us.User)
+	var before uint64
+	if us.Before != nil {
+		before, _ = s.cfg.profileCompleteness(us.Before)
+	}
+	if s.cfg.profileCompletenessThresholdsCrossed(before) == s.cfg.profileCompletenessThresholdsCrossed(after) {
+		return nil
+	}
+	change := &ProfileCompletenessChange{
+		CreatedAt: time.Now(),
+		UserID:    us.User.ID,
+		Missing:   missing,
+		Before:    before,
+		After:     after,
+	}
+
+	return errors.Wrapf(s.sendProfileCompletenessChangeMessage(ctx, change), "failed to sendProfileCompletenessChangeMessage for %#v", change)
+}
+
+func (r *repository) sendProfileCompletenessChangeMessage(ctx context.Context, change *ProfileCompletenessChange) error {
+	valueBytes, err := json.MarshalContext(ctx, change)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", change)
+	}
+	msg := &messagebroker.Message{
+		Headers: map[string]string{"producer": "eskimo"},
+		Key:     change.UserID,
+		Topic:   r.cfg.MessageBroker.Topics[9].Name,
+		Value:   valueBytes,
+	}
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+
+	return errors.Wrapf(<-responder, "failed to send profile completeness change message to broker")
+}
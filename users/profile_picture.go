@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GenerateProfilePictureUploadURL(ctx context.Context, userID UserID, contentType string) (*ProfilePictureUpload, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "generate profile picture upload url failed because context failed")
+	}
+	ext, supported := profilePictureExtensions[strings.ToLower(contentType)]
+	if !supported {
+		return nil, errors.Wrapf(ErrInvalidProfilePicture, "unsupported content type `%v`", contentType)
+	}
+	usr, err := r.getUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get user %v failed", userID)
+	}
+	pictureName := profilePictureName(usr, time.Now(), ext)
+	upload, err := r.pictureClient.SignedUploadURL(ctx, pictureName, strings.ToLower(contentType))
+
+	return upload, errors.Wrapf(err, "failed to generate signed upload url for userID:%v", userID)
+}
+
+// verifyUploadedProfilePicture makes sure that the picture was uploaded via GenerateProfilePictureUploadURL, by this user.
+func (r *repository) verifyUploadedProfilePicture(ctx context.Context, oldUsr *User, profilePicture *multipart.FileHeader) error {
+	if profilePicture.Filename == oldUsr.ProfilePictureURL ||
+		!strings.HasPrefix(profilePicture.Filename, fmt.Sprintf("%v_", oldUsr.HashCode)) ||
+		strings.Contains(profilePicture.Filename, "/") {
+		return errors.Wrapf(ErrInvalidProfilePicture, "picture `%v` doesn't belong to userID:%v", profilePicture.Filename, oldUsr.ID)
+	}
+	exists, err := r.pictureClient.PictureExists(ctx, profilePicture.Filename)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if picture `%v` exists", profilePicture.Filename)
+	}
+	if !exists {
+		return errors.Wrapf(ErrInvalidProfilePicture, "picture `%v` wasn't uploaded", profilePicture.Filename)
+	}
+
+	return nil
+}
+
+func profilePictureName(usr *User, now *time.Time, ext string) string {
+	return fmt.Sprintf("%v_%v%v", usr.HashCode, now.UnixNano(), ext)
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	picturemoderation "github.com/ice-blockchain/eskimo/users/internal/picture/moderation"
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// moderateProfilePicture checks every newly uploaded profile picture and, if it violates our policies, it reverts it.
+// Since the previous picture is deleted when a new one is uploaded, we can revert only to a previous default picture,
+// otherwise we fall back to a random default one.
+func (s *userSnapshotSource) moderateProfilePicture(ctx context.Context, us *UserSnapshot) error {
+	if s.pictureModerator == nil || us.User == nil || us.User.ProfilePictureURL == "" {
+		return nil
+	}
+	pictureName := s.pictureClient.StripDownloadURL(us.User.ProfilePictureURL)
+	var previousPictureName string
+	if us.Before != nil {
+		previousPictureName = s.pictureClient.StripDownloadURL(us.Before.ProfilePictureURL)
+	}
+	if pictureName == previousPictureName || compiledDefaultProfilePictureNameRegex.MatchString(pictureName) {
+		return nil
+	}
+	verdict, err := s.pictureModerator.Moderate(ctx, &picturemoderation.Request{UserID: us.User.ID, PictureURL: s.pictureClient.DownloadURL(pictureName)})
+	if err != nil {
+		return errors.Wrapf(err, "failed to moderate profile picture %v for userID:%v", pictureName, us.User.ID)
+	}
+	if len(verdict.Violations) == 0 {
+		return nil
+	}
+	revertTo := previousPictureName
+	if !compiledDefaultProfilePictureNameRegex.MatchString(revertTo) {
+		revertTo = RandomDefaultProfilePictureName()
+	}
+	event := &ProfilePictureModerationEvent{
+		CreatedAt:             time.Now(),
+		UserID:                us.User.ID,
+		PictureName:           pictureName,
+		RevertedToPictureName: revertTo,
+		Provider:              string(verdict.Provider),
+		Violations:            make([]string, 0, len(verdict.Violations)),
+	}
+	for _, violation := range verdict.Violations {
+		event.Violations = append(event.Violations, string(violation))
+	}
+	if event.Reverted, err = s.revertProfilePicture(ctx, event); err != nil {
+		return errors.Wrapf(err, "failed to revertProfilePicture for %#v", event)
+	}
+	if err = s.insertProfilePictureModerationEvent(ctx, event); err != nil {
+		return errors.Wrapf(err, "failed to insertProfilePictureModerationEvent for %#v", event)
+	}
+	if !s.cfg.ProfilePictureModeration.NotifyUser {
+		return nil
+	}
+
+	return errors.Wrapf(s.sendProfilePictureModerationEventMessage(ctx, event), "failed to sendProfilePictureModerationEventMessage for %#v", event)
+}
+
+func (s *userSnapshotSource) revertProfilePicture(ctx context.Context, event *ProfilePictureModerationEvent) (bool, error) {
+	sql := `UPDATE users
+			SET profile_picture_name = $3,
+				updated_at = $4
+			WHERE id = $1
+			  AND profile_picture_name = $2`
+	updatedRows, err := auditedExec(ctx, s.db, sql, event.UserID, event.PictureName, event.RevertedToPictureName, event.CreatedAt.Time)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to update profile picture for userID:%v", event.UserID)
+	}
+	if updatedRows == 0 { // It was already changed in the meantime, or the user was deleted.
+		return false, nil
+	}
+	if err = s.pictureClient.UploadPicture(ctx, nil, event.PictureName); err != nil {
+		return false, errors.Wrapf(err, "failed to delete violating profile picture %v", event.PictureName)
+	}
+	usr, err := s.getUserByID(ctx, event.UserID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get user by id %v", event.UserID)
+	}
+	before := *usr
+	before.ProfilePictureURL = event.PictureName
+	us := &UserSnapshot{User: s.sanitizeUser(usr), Before: s.sanitizeUser(&before)}
+
+	return true, errors.Wrapf(s.sendUserSnapshotMessage(ctx, us), "failed to send updated user snapshot message %#v", us)
+}
+
+func (s *userSnapshotSource) insertProfilePictureModerationEvent(ctx context.Context, event *ProfilePictureModerationEvent) error {
+	sql := `INSERT INTO profile_picture_moderation_events
+				(created_at, user_id, picture_name, reverted_to_picture_name, provider, violations, reverted)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (user_id, picture_name) DO NOTHING`
+	_, err := auditedExec(ctx, s.db, sql,
+		event.CreatedAt.Time, event.UserID, event.PictureName, event.RevertedToPictureName, event.Provider, event.Violations, event.Reverted)
+
+	return errors.Wrapf(err, "failed to insert profile picture moderation event %#v", event)
+}
+
+func (r *repository) sendProfilePictureModerationEventMessage(ctx context.Context, event *ProfilePictureModerationEvent) error {
+	valueBytes, err := json.MarshalContext(ctx, event)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", event)
+	}
+	msg := &messagebroker.Message{
+		Headers: map[string]string{"producer": "eskimo"},
+		Key:     event.UserID,
+		Topic:   r.cfg.MessageBroker.Topics[5].Name,
+		Value:   valueBytes,
+	}
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+
+	return errors.Wrapf(<-responder, "failed to send profile picture moderation event message to broker")
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"slices"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+	"github.com/zeebo/xxh3"
+
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// recordProfileView buffers the view of the profile of the user by the requesting one, unless the user hid its profile views.
+// The viewers are kept only as hashes seeded with the day, and only for as long as it takes to count them once per day.
+func (r *repository) recordProfileView(ctx context.Context, usr *User) {
+	viewerID := requestingUserID(ctx)
+	if r.cfg.ProfileViews.FlushInterval == 0 || viewerID == "" || viewerID == usr.ID ||
+		(usr.HiddenProfileElements != nil && slices.Contains(*usr.HiddenProfileElements, ProfileViewsHiddenProfileElement)) {
+		return
+	}
+	day := time.Now().Truncate(hoursInOneDay * stdlibtime.Hour)
+	view := profileView{
+		Day:        day.Format(stdlibtime.DateOnly),
+		UserID:     usr.ID,
+		ViewerHash: int64(xxh3.HashStringSeed(viewerID, uint64(day.Unix()))), //nolint:gosec // It's just a hash.
+	}
+	r.profileViews.mx.Lock()
+	defer r.profileViews.mx.Unlock()
+	if r.profileViews.views == nil {
+		r.profileViews.views = make(map[profileView]struct{})
+	}
+	if len(r.profileViews.views) < maxBufferedProfileViews {
+		r.profileViews.views[view] = struct{}{}
+	}
+}
+
+func (r *repository) startProfileViewsFlusher(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(r.cfg.ProfileViews.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reqCtx, cancel := context.WithTimeout(ctx, requestDeadline)
+			log.Error(errors.Wrap(r.flushProfileViews(reqCtx), "failed to flushProfileViews"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// flushProfileViews counts the buffered views whose viewers weren't counted yet that day, by any replica, in a single statement,
+// so every profile is updated at most once per flush, no matter how many views it got. It drops the viewers of the previous days too.
+func (r *repository) flushProfileViews(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	r.profileViews.mx.Lock()
+	views := r.profileViews.views
+	r.profileViews.views = nil
+	r.profileViews.mx.Unlock()
+	today := time.Now().Truncate(hoursInOneDay * stdlibtime.Hour)
+	if len(views) != 0 {
+		days, userIDs, viewerHashes := make([]string, 0, len(views)), make([]UserID, 0, len(views)), make([]int64, 0, len(views))
+		for view := range views {
+			days, userIDs, viewerHashes = append(days, view.Day), append(userIDs, view.UserID), append(viewerHashes, view.ViewerHash)
+		}
+		sql := `WITH viewers AS (
+					INSERT INTO profile_viewers (day, user_id, viewer_hash)
+					SELECT v.day::date, v.user_id, v.viewer_hash
+					FROM unnest($1::text[], $2::text[], $3::bigint[]) AS v(day, user_id, viewer_hash)
+						JOIN users u ON u.id = v.user_id
+					ON CONFLICT DO NOTHING
+					RETURNING day, user_id
+				)
+				INSERT INTO profile_views (user_id, day, views)
+				SELECT user_id, day, count(1)
+				FROM viewers
+				GROUP BY user_id, day
+				ON CONFLICT (user_id, day) DO UPDATE
+					SET views = profile_views.views + EXCLUDED.views`
+		if _, err := auditedExec(ctx, r.db, sql, days, userIDs, viewerHashes); err != nil {
+			return errors.Wrapf(err, "failed to count %v profile views", len(views))
+		}
+	}
+	if _, err := auditedExec(ctx, r.db, `DELETE FROM profile_viewers WHERE day < $1`, today.Add(-hoursInOneDay*stdlibtime.Hour)); err != nil {
+		return errors.Wrap(err, "failed to delete the profile viewers of the previous days")
+	}
+	_, err := auditedExec(ctx, r.db, `DELETE FROM profile_views WHERE day <= $1`, today.Add(-profileViewsDays*hoursInOneDay*stdlibtime.Hour))
+
+	return errors.Wrapf(err, "failed to delete the profile views older than %v days", profileViewsDays)
+}
+
+func (r *repository) getProfileViews(ctx context.Context, usr *User) (*ProfileViews, error) {
+	if usr.HiddenProfileElements != nil && slices.Contains(*usr.HiddenProfileElements, ProfileViewsHiddenProfileElement) {
+		return nil, nil //nolint:nilnil // Nope.
+	}
+	today := time.Now().Truncate(hoursInOneDay * stdlibtime.Hour)
+	sql := `SELECT coalesce(sum(views) FILTER (WHERE day = $2), 0)::bigint                 AS today,
+				   coalesce(sum(views) FILTER (WHERE day > $2 - interval '7 days'), 0)::bigint AS last_7_days,
+				   coalesce(sum(views), 0)::bigint                                            AS last_30_days
+			FROM profile_views
+			WHERE user_id = $1
+			  AND day > $2 - interval '30 days'`
+	views, err := auditedGet[ProfileViews](ctx, r.db, sql, usr.ID, today)
+
+	return views, errors.Wrapf(err, "failed to get the profile views of userID:%v", usr.ID)
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// GetPublicStatistics is served to anybody, so it's reloaded at most every `publicStatistics.cacheTtl`,
+// independently of the other statistics.
+func (r *repository) GetPublicStatistics(ctx context.Context) (*PublicStatistics, error) {
+	now := time.Now()
+	r.publicStatistics.mx.RLock()
+	stats := r.publicStatistics.stats
+	r.publicStatistics.mx.RUnlock()
+	if stats != nil && stats.UpdatedAt.Add(r.cfg.PublicStatistics.CacheTTL).After(*now.Time) {
+		return stats, nil
+	}
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT (SELECT COALESCE(MAX(value), 0) FROM global WHERE key = $1) AS total_users,
+				   (SELECT count(1) FROM users_per_country WHERE user_count > 0) AS total_countries`
+	loaded, err := auditedGet[PublicStatistics](ctx, r.db, sql, totalUsersGlobalKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get public statistics")
+	}
+	loaded.UpdatedAt = now
+	r.publicStatistics.mx.Lock()
+	r.publicStatistics.stats = loaded
+	r.publicStatistics.mx.Unlock()
+
+	return loaded, nil
+}
@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// The audited* wrap the storage ones, so that all the queries of the repository are audited, if the query audit is enabled.
+
+func auditedGet[T any](ctx context.Context, db storage.Querier, sql string, args ...any) (*T, error) {
+	defer auditQuery(db, sql, args, stdlibtime.Now())
+
+	return storage.Get[T](ctx, db, sql, args...) //nolint:wrapcheck // The callers wrap it.
+}
+
+func auditedSelect[T any](ctx context.Context, db storage.Querier, sql string, args ...any) ([]*T, error) {
+	defer auditQuery(db, sql, args, stdlibtime.Now())
+
+	return storage.Select[T](ctx, db, sql, args...) //nolint:wrapcheck // The callers wrap it.
+}
+
+func auditedExec(ctx context.Context, db storage.Execer, sql string, args ...any) (uint64, error) {
+	defer auditQuery(db, sql, args, stdlibtime.Now())
+
+	return storage.Exec(ctx, db, sql, args...) //nolint:wrapcheck // The callers wrap it.
+}
+
+func auditedExecOne[T any](ctx context.Context, db storage.Querier, sql string, args ...any) (*T, error) {
+	defer auditQuery(db, sql, args, stdlibtime.Now())
+
+	return storage.ExecOne[T](ctx, db, sql, args...) //nolint:wrapcheck // The callers wrap it.
+}
+
+func auditedExecMany[T any](ctx context.Context, db storage.Querier, sql string, args ...any) ([]*T, error) {
+	defer auditQuery(db, sql, args, stdlibtime.Now())
+
+	return storage.ExecMany[T](ctx, db, sql, args...) //nolint:wrapcheck // The callers wrap it.
+}
+
+// configureQueryAudit starts the query audit, if enabled. It's started only once per replica, so that the statistics are kept
+// when both the repository and the processor are started.
+func (c *config) configureQueryAudit() {
+	if !c.QueryAudit.Enabled {
+		return
+	}
+	queryAudit.CompareAndSwap(nil, &queryAuditor{
+		startedAt:          time.Now(),
+		queries:            make(map[string]*QueryStatistics),
+		slowQueryThreshold: c.QueryAudit.SlowQueryThreshold,
+		explainSlowQueries: c.QueryAudit.ExplainSlowQueries,
+	})
+}
+
+func (r *repository) GetQueryAudit(ctx context.Context) (*QueryAudit, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	auditor := queryAudit.Load()
+	if auditor == nil {
+		return &QueryAudit{Queries: []*QueryStatistics{}}, nil
+	}
+	auditor.mx.RLock()
+	defer auditor.mx.RUnlock()
+	res := &QueryAudit{
+		StartedAt:          auditor.startedAt,
+		Queries:            make([]*QueryStatistics, 0, len(auditor.queries)),
+		SlowQueryThreshold: auditor.slowQueryThreshold.String(),
+		Enabled:            true,
+	}
+	for _, stats := range auditor.queries {
+		cpy := *stats
+		cpy.Histogram = make(map[string]uint64, len(stats.Histogram))
+		for bucket, count := range stats.Histogram {
+			cpy.Histogram[bucket] = count
+		}
+		res.Queries = append(res.Queries, &cpy)
+	}
+	slices.SortFunc(res.Queries, func(a, b *QueryStatistics) int {
+		switch {
+		case a.SlowestMillis > b.SlowestMillis:
+			return -1
+		case a.SlowestMillis < b.SlowestMillis:
+			return 1
+		default:
+			return strings.Compare(a.Query, b.Query)
+		}
+	})
+
+	return res, nil
+}
+
+func auditQuery(db any, sql string, args []any, startedAt stdlibtime.Time) {
+	auditor := queryAudit.Load()
+	if auditor == nil {
+		return
+	}
+	took := stdlibtime.Since(startedAt)
+	query := strings.Join(strings.Fields(sql), " ")
+	slowest := auditor.observe(query, args, took)
+	if took < auditor.slowQueryThreshold {
+		return
+	}
+	log.Warn(fmt.Sprintf("slow query took %v: %v, args: %v", took, query, redactQueryArgs(args)))
+	// Only the worst offenders are explained, when they get slower, and only outside transactions, since they can't be reused.
+	if pool, isPool := db.(*storage.DB); slowest && isPool && auditor.explainSlowQueries {
+		go auditor.explain(pool, query, args)
+	}
+}
+
+// observe records the duration of the query and reports whether it's its slowest execution so far.
+func (a *queryAuditor) observe(query string, args []any, took stdlibtime.Duration) (slowest bool) {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+	stats, found := a.queries[query]
+	if !found {
+		if len(a.queries) >= maxAuditedQueries {
+			return false
+		}
+		stats = &QueryStatistics{Query: query, Histogram: make(map[string]uint64, len(queryDurationBuckets)+1)}
+		a.queries[query] = stats
+	}
+	bucket := "+Inf"
+	for _, upperBound := range queryDurationBuckets {
+		if took <= upperBound {
+			bucket = upperBound.String()
+
+			break
+		}
+	}
+	stats.Histogram[bucket]++
+	stats.Count++
+	stats.TotalMillis += float64(took) / float64(stdlibtime.Millisecond)
+	if millis := float64(took) / float64(stdlibtime.Millisecond); millis > stats.SlowestMillis {
+		stats.SlowestMillis, stats.SlowestAt, stats.SlowestArgs = millis, time.Now(), redactQueryArgs(args)
+
+		return true
+	}
+
+	return false
+}
+
+func (a *queryAuditor) explain(db *storage.DB, query string, args []any) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestDeadline)
+	defer cancel()
+	plan, err := storage.Select[struct {
+		QueryPlan string `db:"QUERY PLAN"`
+	}](ctx, db, "EXPLAIN "+query, args...)
+	if err != nil {
+		log.Error(errors.Wrapf(err, "failed to explain query: %v", query))
+
+		return
+	}
+	lines := make([]string, 0, len(plan))
+	for _, line := range plan {
+		lines = append(lines, line.QueryPlan)
+	}
+	a.mx.Lock()
+	a.queries[query].Plan = strings.Join(lines, "\n")
+	a.mx.Unlock()
+}
+
+// redactQueryArgs keeps only the numbers, the booleans and the dates, unless the unredacted logs are enabled, since the rest can be PII.
+func redactQueryArgs(args []any) []string {
+	redacted := make([]string, 0, len(args))
+	for _, arg := range args {
+		val := reflect.Indirect(reflect.ValueOf(arg))
+		if !val.IsValid() {
+			redacted = append(redacted, "NULL")
+
+			continue
+		}
+		switch val.Kind() { //nolint:exhaustive // The rest are redacted.
+		case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+			redacted = append(redacted, fmt.Sprint(val.Interface()))
+		default:
+			if _, isTime := val.Interface().(stdlibtime.Time); isTime || unredactedLogs.Load() {
+				redacted = append(redacted, fmt.Sprint(val.Interface()))
+			} else {
+				redacted = append(redacted, redact("", fmt.Sprint(val.Interface())))
+			}
+		}
+	}
+
+	return redacted
+}
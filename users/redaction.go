@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	appcfg "github.com/ice-blockchain/wintr/config"
+	"github.com/ice-blockchain/wintr/log"
+)
+
+func (e RedactedEmail) String() string {
+	if unredactedLogs.Load() {
+		return string(e)
+	}
+
+	return redact(redactionEmail, string(e))
+}
+
+func (e RedactedEmail) GoString() string {
+	return fmt.Sprintf("%q", e.String())
+}
+
+func (p RedactedPhoneNumber) String() string {
+	if unredactedLogs.Load() {
+		return string(p)
+	}
+
+	return redact(redactionPhoneNumber, string(p))
+}
+
+func (p RedactedPhoneNumber) GoString() string {
+	return fmt.Sprintf("%q", p.String())
+}
+
+// FormatRedacted formats the struct like fmt does, but with the values of its fields tagged with `redact` redacted,
+// unless the unredacted logs are enabled. It's meant for implementing the fmt.Formatter of the types with PII.
+// The nested types are formatted by fmt, so the ones with PII have to implement it as well. So do the ones embedding them,
+// otherwise the promoted Format of the embedded one is used, and only it is formatted.
+func FormatRedacted(state fmt.State, verb rune, value any) {
+	val := reflect.Indirect(reflect.ValueOf(value))
+	if val.Kind() != reflect.Struct {
+		fmt.Fprintf(state, fmt.FormatString(state, verb), val)
+
+		return
+	}
+	goSyntax, withFieldNames := state.Flag('#'), state.Flag('+')
+	separator := " "
+	if goSyntax {
+		separator = ", "
+		io.WriteString(state, val.Type().String()) //nolint:errcheck,revive // It's just logging.
+	}
+	io.WriteString(state, "{") //nolint:errcheck,revive // It's just logging.
+	for ix := range val.NumField() {
+		if ix > 0 {
+			io.WriteString(state, separator) //nolint:errcheck,revive // It's just logging.
+		}
+		field := val.Type().Field(ix)
+		if goSyntax || withFieldNames {
+			io.WriteString(state, field.Name+":") //nolint:errcheck,revive // It's just logging.
+		}
+		if redaction, tagged := field.Tag.Lookup(redactionTag); tagged && !unredactedLogs.Load() {
+			formatRedactedField(state, verb, redaction, val.Field(ix))
+
+			continue
+		}
+		fmt.Fprintf(state, fmt.FormatString(state, verb), val.Field(ix))
+	}
+	io.WriteString(state, "}") //nolint:errcheck,revive // It's just logging.
+}
+
+func formatRedactedField(state fmt.State, verb rune, redaction string, field reflect.Value) {
+	if field.Kind() == reflect.Pointer && !field.IsNil() {
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.String {
+		fmt.Fprintf(state, fmt.FormatString(state, verb), field)
+
+		return
+	}
+	if state.Flag('#') {
+		fmt.Fprintf(state, "%q", redact(redaction, field.String()))
+	} else {
+		io.WriteString(state, redact(redaction, field.String())) //nolint:errcheck,revive // It's just logging.
+	}
+}
+
+// redact keeps the first character and the domain of the emails and the last digits of the phone numbers, so the logs can still be correlated.
+func redact(redaction, value string) string {
+	const redacted, phoneNumberDigitsKept = "***", 2
+	switch {
+	case value == "":
+		return ""
+	case redaction == redactionEmail && strings.Contains(value, "@"):
+		localPart, domain, _ := strings.Cut(value, "@")
+		if localPart == "" {
+			return redacted + "@" + domain
+		}
+
+		return localPart[:1] + redacted + "@" + domain
+	case redaction == redactionPhoneNumber && len(value) > phoneNumberDigitsKept:
+		return redacted + value[len(value)-phoneNumberDigitsKept:]
+	default:
+		return redacted
+	}
+}
+
+// configureRedaction enables the unredacted logs, if configured so, only in development, so that the PII is never dumped in production.
+func (c *config) configureRedaction() {
+	var development bool
+	appcfg.MustLoadFromKey("development", &development)
+	if c.UnredactedLogs && !development {
+		log.Warn("`unredactedLogs` is ignored outside development")
+	}
+	unredactedLogs.Store(c.UnredactedLogs && development)
+}
+
+func (u SensitiveUserInformation) Format(state fmt.State, verb rune) {
+	FormatRedacted(state, verb, u)
+}
+
+func (u PrivateUserInformation) Format(state fmt.State, verb rune) { //nolint:gocritic // It has to be a value receiver, for the values to use it.
+	FormatRedacted(state, verb, u)
+}
+
+func (u User) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	FormatRedacted(state, verb, u)
+}
+
+func (u MinimalUserProfile) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	FormatRedacted(state, verb, u)
+}
+
+func (u UserProfile) Format(state fmt.State, verb rune) {
+	FormatRedacted(state, verb, u)
+}
+
+func (u UserSnapshot) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	FormatRedacted(state, verb, u)
+}
+
+func (c ReferralInvitationContact) Format(state fmt.State, verb rune) {
+	FormatRedacted(state, verb, c)
+}
+
+func (r UserImportRecord) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	FormatRedacted(state, verb, r)
+}
+
+func (e AuthEvent) Format(state fmt.State, verb rune) { //nolint:gocritic // Same.
+	FormatRedacted(state, verb, e)
+}
+
+func (a userAgenda) Format(state fmt.State, verb rune) {
+	FormatRedacted(state, verb, a)
+}
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (p *processor) startReferralIntegrityChecker(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.ReferralIntegrity.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 30 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			_, err := p.CheckReferralIntegrity(reqCtx, p.cfg.ReferralIntegrity.Policy == ReparentReferralRepairPolicy)
+			log.Error(errors.Wrap(err, "failed to CheckReferralIntegrity"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// CheckReferralIntegrity finds the users with inconsistent referrals, mostly left by the random re-parenting of the delete path,
+// and, if `repair`, re-parents them. Every check is audited, with its anomalies, even if they're not repaired.
+func (r *repository) CheckReferralIntegrity(ctx context.Context, repair bool) (*ReferralIntegrityReport, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	report := &ReferralIntegrityReport{CheckedAt: time.Now(), DryRun: dryRun(ctx)}
+	anomalies, err := r.detectReferralAnomalies(ctx, report.CheckedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to detectReferralAnomalies")
+	}
+	report.Anomalies = anomalies
+	if len(anomalies) == 0 {
+		return report, nil
+	}
+	for _, anomaly := range anomalies {
+		log.Info(fmt.Sprintf("referral anomaly `%v` detected for userID:%v, referred by `%v`", anomaly.Type, anomaly.UserID, anomaly.ReferredBy))
+		if !repair {
+			continue
+		}
+		if err = r.repairReferralAnomaly(ctx, anomaly); err != nil {
+			return nil, errors.Wrapf(err, "failed to repairReferralAnomaly for %#v", anomaly)
+		}
+		if anomaly.RepairedReferredBy != nil {
+			report.Repaired++
+		}
+	}
+
+	if report.DryRun != nil {
+		return report, nil
+	}
+
+	return report, errors.Wrapf(r.insertReferralIntegrityAudit(ctx, report), "failed to insertReferralIntegrityAudit for %#v", report)
+}
+
+//nolint:funlen // A lot of SQL.
+func (r *repository) detectReferralAnomalies(ctx context.Context, now *time.Time) ([]*ReferralAnomaly, error) {
+	sql := `SELECT id AS user_id, referred_by, $1 AS type
+			FROM users
+			WHERE referred_by = id
+			  AND id != 'bogus'
+			  AND id != 'icenetwork'
+			  AND created_at < $2
+			UNION ALL
+			SELECT u.id AS user_id, u.referred_by, (CASE WHEN n.id IS NULL THEN $3 ELSE $4 END) AS type
+			FROM users u
+				LEFT JOIN users n ON n.username = u.referred_by
+			WHERE NOT EXISTS (SELECT 1 FROM users r WHERE r.id = u.referred_by)`
+	anomalies, err := auditedSelect[ReferralAnomaly](ctx, r.db, sql,
+		SelfReferralAnomalyType, now.Add(-r.cfg.ReferralIntegrity.SelfReferralGracePeriod),
+		DanglingReferralAnomalyType, UsernameReferralAnomalyType)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select self and dangling referrals")
+	}
+	if r.cfg.ReferralIntegrity.MaxCycleLength == 0 {
+		return anomalies, nil
+	}
+	// Every user of a cycle finds it, so only its newest user is kept, as re-parenting it is enough to break the cycle.
+	sql = `WITH RECURSIVE chain(start_id, id, path) AS (
+				SELECT id, referred_by, ARRAY[id]
+				FROM users
+				WHERE referred_by != id
+			  UNION ALL
+				SELECT c.start_id, u.referred_by, c.path || u.id
+				FROM chain c
+					JOIN users u ON u.id = c.id
+				WHERE c.id != c.start_id
+				  AND u.referred_by != u.id
+				  AND cardinality(c.path) < $1
+			)
+			SELECT DISTINCT ON (cycle) user_id, referred_by, $2 AS type
+			FROM (SELECT (SELECT array_agg(x ORDER BY x) FROM unnest(c.path) x) AS cycle,
+						 u.id AS user_id,
+						 u.referred_by,
+						 u.created_at
+				  FROM chain c
+					  JOIN users u ON u.id = c.start_id
+				  WHERE c.id = c.start_id) cycles
+			ORDER BY cycle, created_at DESC`
+	cycles, err := auditedSelect[ReferralAnomaly](ctx, r.db, sql, r.cfg.ReferralIntegrity.MaxCycleLength, CycleReferralAnomalyType)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select referral cycles")
+	}
+
+	return append(anomalies, cycles...), nil
+}
+
+func (r *repository) repairReferralAnomaly(ctx context.Context, anomaly *ReferralAnomaly) error {
+	referredBy, random := UserID(icenetwork), true
+	if anomaly.Type == UsernameReferralAnomalyType {
+		referrer, err := auditedGet[User](ctx, r.db, `SELECT id FROM users WHERE username = $1`, anomaly.ReferredBy)
+		if err != nil && !storage.IsErr(err, storage.ErrNotFound) {
+			return errors.Wrapf(err, "failed to get user by username %v", anomaly.ReferredBy)
+		}
+		if referrer != nil && referrer.ID != anomaly.UserID {
+			referredBy, random = referrer.ID, false
+		}
+	}
+	usr, err := r.getUserByID(ctx, anomaly.UserID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get user for userID:%v", anomaly.UserID)
+	}
+	if preview := dryRun(ctx); preview != nil {
+		anomaly.RepairedReferredBy = &referredBy
+		preview.affect(anomaly.UserID, map[string]uint64{"users": 1}, UpdatedUserSnapshotEvent)
+
+		return nil
+	}
+	repaired := *usr
+	repaired.UpdatedAt = time.Now()
+	repaired.ReferredBy, repaired.RandomReferredBy = referredBy, &random
+	sql := `UPDATE users
+			SET referred_by = $3,
+				random_referred_by = $4,
+				updated_at = $5
+			WHERE id = $1
+			  AND referred_by = $2`
+	if updated, uErr := auditedExec(ctx, r.db, sql, anomaly.UserID, anomaly.ReferredBy, referredBy, random, repaired.UpdatedAt.Time); uErr != nil {
+		return errors.Wrapf(uErr, "failed to re-parent userID:%v to %v", anomaly.UserID, referredBy)
+	} else if updated == 0 { // It changed in the meantime.
+		return nil
+	}
+	anomaly.RepairedReferredBy = &referredBy
+	us := &UserSnapshot{User: r.sanitizeUser(&repaired), Before: r.sanitizeUser(usr)}
+
+	return errors.Wrapf(r.sendUserSnapshotMessage(ctx, us), "failed to send re-parented user message for %#v", us)
+}
+
+func (r *repository) insertReferralIntegrityAudit(ctx context.Context, report *ReferralIntegrityReport) error {
+	values := make([]string, 0, len(report.Anomalies))
+	const fields = 4
+	params := make([]any, 0, fields*len(report.Anomalies)+1)
+	params = append(params, report.CheckedAt.Time)
+	for ix, anomaly := range report.Anomalies {
+		values = append(values, fmt.Sprintf("($1, $%v, $%v, $%v, $%v)", fields*ix+2, fields*ix+3, fields*ix+4, fields*ix+5)) //nolint:gomnd // .
+		params = append(params, anomaly.UserID, anomaly.ReferredBy, anomaly.RepairedReferredBy, anomaly.Type)
+	}
+	sql := fmt.Sprintf(`INSERT INTO referral_integrity_audit (checked_at, user_id, referred_by, repaired_referred_by, type)
+						VALUES %v
+						ON CONFLICT DO NOTHING`, strings.Join(values, ","))
+	_, err := auditedExec(ctx, r.db, sql, params...)
+
+	return errors.Wrapf(err, "failed to insert referral integrity audit for %#v", report.Anomalies)
+}
@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users/internal/invitation"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetReferralInvitations(ctx context.Context, userID UserID, limit, offset uint64) ([]*ReferralInvitation, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT *
+			FROM referral_invitations
+			WHERE user_id = $1
+			ORDER BY created_at DESC
+			LIMIT $2 OFFSET $3`
+	res, err := auditedSelect[ReferralInvitation](ctx, r.db, sql, userID, limit, offset)
+
+	return res, errors.Wrapf(err, "failed to select referral invitations for userID:%v", userID)
+}
+
+func (r *repository) SendReferralInvitations(
+	ctx context.Context, userID UserID, contacts []*ReferralInvitationContact,
+) (*ReferralInvitationsResult, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	if r.invitationSender == nil {
+		return nil, ErrReferralInvitationsDisabled
+	}
+	usr, err := r.getUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get user for userID:%v", userID)
+	}
+	remaining, err := r.remainingDailyReferralInvitations(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the remaining daily referral invitations for userID:%v", userID)
+	}
+	invitations := make([]*ReferralInvitation, 0, len(contacts))
+	contactHashes := make([]string, 0, len(contacts))
+	for _, contact := range contacts {
+		inv := newReferralInvitation(userID, contact)
+		invitations, contactHashes = append(invitations, inv), append(contactHashes, inv.ContactHash)
+	}
+	unsubscribed, err := r.unsubscribedFromReferralInvitations(ctx, contactHashes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check the unsubscribed contacts for userID:%v", userID)
+	}
+	res := &ReferralInvitationsResult{Results: make([]*ReferralInvitationResult, 0, len(contacts))}
+	for ix, inv := range invitations {
+		result := &ReferralInvitationResult{ContactHint: inv.ContactHint, Channel: inv.Channel}
+		res.Results = append(res.Results, result)
+		_, isUnsubscribed := unsubscribed[inv.ContactHash]
+		switch {
+		case !contacts[ix].Consent:
+			result.Status = NoConsentReferralInvitationStatus
+		case isUnsubscribed:
+			result.Status = UnsubscribedReferralInvitationStatus
+		case remaining == 0:
+			result.Status = QuotaExceededReferralInvitationStatus
+		default:
+			if result.Status, err = r.sendReferralInvitation(ctx, usr, inv, contacts[ix]); err != nil {
+				return nil, errors.Wrapf(err, "failed to sendReferralInvitation for userID:%v", userID)
+			}
+			if result.Status == SentReferralInvitationStatus {
+				remaining--
+			}
+		}
+	}
+	res.RemainingDailyQuota = remaining
+
+	return res, nil
+}
+
+func (r *repository) remainingDailyReferralInvitations(ctx context.Context, userID UserID) (uint64, error) {
+	sql := `SELECT count(1) AS count FROM referral_invitations WHERE user_id = $1 AND created_at >= $2`
+	sent, err := auditedGet[struct {
+		Count uint64 `db:"count"`
+	}](ctx, r.db, sql, userID, time.Now().Truncate(hoursInOneDay*stdlibtime.Hour))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to count today's referral invitations of userID:%v", userID)
+	}
+	if sent.Count >= r.cfg.ReferralInvitations.DailyQuota {
+		return 0, nil
+	}
+
+	return r.cfg.ReferralInvitations.DailyQuota - sent.Count, nil
+}
+
+func (r *repository) unsubscribedFromReferralInvitations(ctx context.Context, contactHashes []string) (map[string]struct{}, error) {
+	sql := `SELECT contact_hash FROM referral_invitation_unsubscriptions WHERE contact_hash = ANY($1)`
+	rows, err := auditedSelect[struct {
+		ContactHash string `db:"contact_hash"`
+	}](ctx, r.db, sql, contactHashes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select referral invitation unsubscriptions")
+	}
+	unsubscribed := make(map[string]struct{}, len(rows))
+	for _, row := range rows {
+		unsubscribed[row.ContactHash] = struct{}{}
+	}
+
+	return unsubscribed, nil
+}
+
+// sendReferralInvitation records the invitation and sends it. If sending fails, it's forgotten, so that it can be retried.
+func (r *repository) sendReferralInvitation(
+	ctx context.Context, inviter *User, inv *ReferralInvitation, contact *ReferralInvitationContact,
+) (ReferralInvitationStatus, error) {
+	inv.CreatedAt, inv.Status, inv.UnsubscribeToken = time.Now(), SentReferralInvitationStatus, uuid.NewString()
+	sql := `INSERT INTO referral_invitations (created_at, user_id, contact_hash, contact_hint, unsubscribe_token, channel, status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (user_id, contact_hash) DO NOTHING`
+	if inserted, err := auditedExec(ctx, r.db, sql,
+		inv.CreatedAt.Time, inv.UserID, inv.ContactHash, inv.ContactHint, inv.UnsubscribeToken, inv.Channel, inv.Status); err != nil || inserted == 0 {
+		if err == nil {
+			return AlreadyInvitedReferralInvitationStatus, nil
+		}
+
+		return "", errors.Wrapf(err, "failed to insert referral invitation %#v", inv)
+	}
+	to := contact.Email
+	if inv.Channel == SMSReferralInvitationChannel {
+		to = contact.PhoneNumber
+	}
+	inviterName := inviter.Username
+	if inviter.FirstName != nil && *inviter.FirstName != "" {
+		inviterName = *inviter.FirstName
+	}
+	if err := r.invitationSender.Send(ctx, &invitation.Invitation{
+		To:               to,
+		Language:         inviter.Language,
+		InviterName:      inviterName,
+		ReferralCode:     inviter.Username,
+		UnsubscribeToken: inv.UnsubscribeToken,
+		Channel:          inv.Channel,
+	}); err != nil {
+		log.Error(errors.Wrapf(err, "failed to send referral invitation %#v", inv))
+		sql = `DELETE FROM referral_invitations WHERE user_id = $1 AND contact_hash = $2`
+		if _, dErr := auditedExec(ctx, r.db, sql, inv.UserID, inv.ContactHash); dErr != nil {
+			return "", errors.Wrapf(dErr, "failed to delete the unsent referral invitation %#v", inv)
+		}
+
+		return FailedReferralInvitationStatus, nil
+	}
+
+	return SentReferralInvitationStatus, nil
+}
+
+func (r *repository) UnsubscribeFromReferralInvitations(ctx context.Context, unsubscribeToken string) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT * FROM referral_invitations WHERE unsubscribe_token = $1`
+	inv, err := auditedGet[ReferralInvitation](ctx, r.db, sql, unsubscribeToken)
+	if err != nil {
+		return errors.Wrap(err, "failed to get referral invitation by unsubscribe token")
+	}
+	sql = `INSERT INTO referral_invitation_unsubscriptions (unsubscribed_at, contact_hash) VALUES ($1, $2) ON CONFLICT (contact_hash) DO NOTHING`
+	_, err = auditedExec(ctx, r.db, sql, time.Now().Time, inv.ContactHash)
+
+	return errors.Wrapf(err, "failed to unsubscribe the contact of the referral invitation of userID:%v", inv.UserID)
+}
+
+// attributeReferralInvitations marks the invitations to the email or phone number the user just got as joined, attributing it to its inviters.
+func (r *repository) attributeReferralInvitations(ctx context.Context, before, after *User) error {
+	contactHashes := make([]string, 0, 1+1)
+	if emailDomain(after.ID, after.Email) != "" && (before == nil || !strings.EqualFold(before.Email, after.Email)) {
+		contactHashes = append(contactHashes, referralInvitationEmailHash(after.Email))
+	}
+	if after.PhoneNumberHash != "" && after.PhoneNumberHash != after.ID && (before == nil || before.PhoneNumberHash != after.PhoneNumberHash) {
+		contactHashes = append(contactHashes, after.PhoneNumberHash)
+	}
+	if len(contactHashes) == 0 {
+		return nil
+	}
+	sql := `UPDATE referral_invitations
+			SET status = 'joined',
+				joined_at = $1,
+				invitee_user_id = $2
+			WHERE contact_hash = ANY($3)
+			  AND invitee_user_id IS NULL
+			  AND user_id != $2`
+	_, err := auditedExec(ctx, r.db, sql, time.Now().Time, after.ID, contactHashes)
+
+	return errors.Wrapf(err, "failed to attribute the referral invitations of userID:%v", after.ID)
+}
+
+func newReferralInvitation(userID UserID, contact *ReferralInvitationContact) *ReferralInvitation {
+	if contact.Email != "" {
+		return &ReferralInvitation{
+			UserID:      userID,
+			ContactHash: referralInvitationEmailHash(contact.Email),
+			ContactHint: emailHint(contact.Email),
+			Channel:     EmailReferralInvitationChannel,
+		}
+	}
+
+	return &ReferralInvitation{
+		UserID:      userID,
+		ContactHash: contact.PhoneNumberHash,
+		ContactHint: phoneNumberHint(contact.PhoneNumber),
+		Channel:     SMSReferralInvitationChannel,
+	}
+}
+
+// referralInvitationEmailHash is what's stored instead of the email. Phone numbers have their hash already.
+func referralInvitationEmailHash(email string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func emailHint(email string) string {
+	local, domain, _ := strings.Cut(strings.TrimSpace(email), "@")
+	if local == "" {
+		return "***@" + domain
+	}
+
+	return local[:1] + "***@" + domain
+}
+
+func phoneNumberHint(phoneNumber string) string {
+	const visibleBeginning, visibleEnd = 2, 3
+	if len(phoneNumber) <= visibleBeginning+visibleEnd {
+		return strings.Repeat("*", len(phoneNumber))
+	}
+
+	return phoneNumber[:visibleBeginning] + strings.Repeat("*", len(phoneNumber)-visibleBeginning-visibleEnd) + phoneNumber[len(phoneNumber)-visibleEnd:]
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetPingableReferrals(ctx context.Context, userID UserID, limit, offset uint64) (*PingableReferrals, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	now := time.Now()
+	sql := fmt.Sprintf(`SELECT r.id,
+							   r.username,
+							   %[1]v 												  AS profile_picture_name,
+							   r.country,
+							   ''													  AS city,
+							   (CASE WHEN r.referred_by = $1 THEN 'T1' ELSE 'T0' END) AS referral_type,
+							   COALESCE(r.last_mining_ended_at, to_timestamp(0))	  AS active,
+							   COALESCE(r.last_ping_cooldown_ended_at, to_timestamp(0)) AS pinged,
+							   count(1) OVER () 									  AS total
+						FROM users r
+						WHERE %[2]v
+						ORDER BY r.created_at DESC
+						LIMIT $4 OFFSET $5`, r.pictureClient.SQLAliasDownloadURL(`r.profile_picture_name`), pingableReferralsSQLCondition())
+	rows, err := auditedSelect[pingableReferral](ctx, r.db, sql, userID, now.Time, now.Time, limit, offset)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select pingable referrals of userID:%v", userID)
+	}
+	res := &PingableReferrals{Referrals: make([]*MinimalUserProfile, 0, len(rows)), SelectAllToken: selectAllReferralsToken(userID, now)}
+	for _, row := range rows {
+		res.Referrals, res.Total = append(res.Referrals, row.MinimalUserProfile), row.Total
+	}
+
+	return res, nil
+}
+
+func (r *repository) PingReferrals(ctx context.Context, userID UserID, ping *ReferralPing) (*ReferralPingResult, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	now := time.Now()
+	asOf := now
+	var userIDs []UserID
+	switch {
+	case ping.SelectAllToken != "" && len(ping.UserIDs) == 0:
+		var err error
+		if asOf, err = parseSelectAllReferralsToken(userID, ping.SelectAllToken); err != nil {
+			return nil, err
+		}
+	case ping.SelectAllToken == "" && len(ping.UserIDs) > 0 && uint64(len(ping.UserIDs)) <= r.cfg.ReferralPings.MaxBatchSize:
+		userIDs = ping.UserIDs
+	default:
+		return nil, errors.Wrapf(ErrInvalidReferralPing, "either the selectAllToken or at most %v userIds are required", r.cfg.ReferralPings.MaxBatchSize)
+	}
+	cooldownEndedAt := time.New(now.Add(r.cfg.ReferralPings.Cooldown))
+	// The pinged referrals are locked, and their cooldown is checked again, so that concurrent pings don't ping them twice.
+	sql := fmt.Sprintf(`WITH pingable AS (
+							SELECT r.id
+							FROM users r
+							WHERE %v
+							  AND ($5::text[] IS NULL OR r.id = ANY($5))
+							ORDER BY r.created_at DESC
+							LIMIT $4
+							FOR UPDATE)
+						UPDATE users u
+						SET last_ping_cooldown_ended_at = $6
+						FROM pingable
+						WHERE u.id = pingable.id
+						RETURNING u.id`, pingableReferralsSQLCondition())
+	pinged, err := auditedSelect[User](ctx, r.db, sql, userID, now.Time, asOf.Time, r.cfg.ReferralPings.MaxBatchSize, userIDs, cooldownEndedAt.Time)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to ping referrals of userID:%v for %#v", userID, ping)
+	}
+	res := &ReferralPingResult{CooldownEndedAt: cooldownEndedAt, UserIDs: make([]UserID, 0, len(pinged))}
+	for _, usr := range pinged {
+		res.UserIDs = append(res.UserIDs, usr.ID)
+	}
+
+	return res, nil
+}
+
+// pingableReferralsSQLCondition matches the T1 referrals and the referrer of $1 that aren't mining and weren't pinged recently, as of $2,
+// among the ones created before $3.
+func pingableReferralsSQLCondition() string {
+	return `(r.referred_by = $1 OR r.id = (SELECT referred_by FROM users WHERE id = $1))
+			  AND r.id != $1
+			  AND r.username != r.id
+			  AND r.referred_by != r.id
+			  AND r.created_at <= $3
+			  AND COALESCE(r.last_mining_ended_at, to_timestamp(0)) < $2
+			  AND COALESCE(r.last_ping_cooldown_ended_at, to_timestamp(0)) < $2`
+}
+
+// selectAllReferralsToken selects the referrals that were pingable when it was issued, so that the ones that joined after are not pinged.
+// It's not a secret, since it can only be used by the user it was issued to, for its own referrals, which are checked again anyway.
+func selectAllReferralsToken(userID UserID, asOf *time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%v:%v", userID, asOf.UnixNano())))
+}
+
+func parseSelectAllReferralsToken(userID UserID, token string) (*time.Time, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidReferralPing, "invalid selectAllToken: %v", err)
+	}
+	separator := strings.LastIndex(string(decoded), ":")
+	if separator < 0 || string(decoded[:separator]) != userID {
+		return nil, errors.Wrapf(ErrInvalidReferralPing, "selectAllToken wasn't issued to userID:%v", userID)
+	}
+	asOf, err := strconv.ParseInt(string(decoded[separator+1:]), 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidReferralPing, "invalid selectAllToken date: %v", err)
+	}
+
+	return time.New(stdlibtime.Unix(0, asOf).UTC()), nil
+}
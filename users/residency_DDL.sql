@@ -0,0 +1,6 @@
+-- SPDX-License-Identifier: ice License 1.0
+-- | The residency clusters hold only the rows that are routed to them, so they don't reference the users, which stay in the default cluster.
+CREATE TABLE IF NOT EXISTS user_dates_of_birth (
+                    updated_at    timestamp NOT NULL,
+                    user_id       text NOT NULL primary key,
+                    date_of_birth text NOT NULL);
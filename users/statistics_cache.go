@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func newStatisticsCache(cfg *config) *statisticsCache {
+	return &statisticsCache{
+		entries: make(map[string]*statisticsCacheEntry),
+		cfg:     cfg,
+	}
+}
+
+func topCountriesStatisticsCacheKey(keyword, ownCountry string, limit, offset uint64) string {
+	return fmt.Sprintf("%v:%v:%v:%v:%v", topCountriesStatisticsCachePrefix, strings.ToLower(keyword), ownCountry, limit, offset)
+}
+
+func userGrowthStatisticsCacheKey(days uint64, tzOffset int) string {
+	return fmt.Sprintf("%v:%v:%v", userGrowthStatisticsCachePrefix, days, tzOffset)
+}
+
+func rollingActiveUsersStatisticsCacheKey() string {
+	return fmt.Sprintf("%v:rolling-active-users", userGrowthStatisticsCachePrefix)
+}
+
+func kycFunnelStatisticsCacheKey(days uint64) string {
+	return fmt.Sprintf("%v:%v", kycFunnelStatisticsCachePrefix, days)
+}
+
+func emailDomainStatisticsCacheKey(days, limit uint64) string {
+	return fmt.Sprintf("%v:%v:%v", emailDomainStatisticsCachePrefix, days, limit)
+}
+
+// get returns the cached value only if it was computed from the same version of the statistics, i.e. the last time they were updated,
+// since they're updated by the processor, not by the replica that caches them, so it's the only way to know that they're fresh.
+func (c *statisticsCache) get(key string, version *time.Time) (value any, lastUpdatedAt *time.Time, found bool) {
+	if c == nil || c.cfg.StatisticsCacheTTL == 0 {
+		return nil, nil, false
+	}
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	entry, found := c.entries[key]
+	if !found || entry.expiresAt.Before(*time.Now().Time) || !sameVersion(entry.version, version) {
+		return nil, nil, false
+	}
+
+	return entry.value, entry.lastUpdatedAt, true
+}
+
+func sameVersion(cached, current *time.Time) bool {
+	if cached.IsNil() || current.IsNil() {
+		return cached.IsNil() && current.IsNil()
+	}
+
+	return cached.Equal(*current.Time)
+}
+
+func (c *statisticsCache) set(key string, value any, lastUpdatedAt, version *time.Time) {
+	if c == nil || c.cfg.StatisticsCacheTTL == 0 {
+		return
+	}
+	now := time.Now()
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if len(c.entries) >= maxStatisticsCacheEntries {
+		for k, entry := range c.entries {
+			if entry.expiresAt.Before(*now.Time) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxStatisticsCacheEntries {
+			return
+		}
+	}
+	c.entries[key] = &statisticsCacheEntry{
+		value:         value,
+		lastUpdatedAt: lastUpdatedAt,
+		version:       version,
+		expiresAt:     time.New(now.Add(c.cfg.StatisticsCacheTTL)),
+	}
+}
+
+// statisticsVersion returns the last time any of the rows the statistics are computed from were updated, as their version.
+// The sql must select it as `version`.
+func (r *repository) statisticsVersion(ctx context.Context, sql string, args ...any) (*time.Time, error) {
+	res, err := auditedGet[struct {
+		Version *time.Time `db:"version"`
+	}](ctx, r.db, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the version of the statistics")
+	}
+
+	return res.Version, nil
+}
+
+func maxLastUpdatedAt(values []*GlobalUnsigned) *time.Time {
+	var lastUpdatedAt *time.Time
+	for _, val := range values {
+		if val.UpdatedAt.IsNil() {
+			continue
+		}
+		if lastUpdatedAt.IsNil() || val.UpdatedAt.After(*lastUpdatedAt.Time) {
+			lastUpdatedAt = val.UpdatedAt
+		}
+	}
+
+	return lastUpdatedAt
+}
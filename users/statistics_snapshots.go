@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (p *processor) startStatisticsSnapshotsExporter(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.StatisticsSnapshots.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 10 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(p.exportStatisticsSnapshots(reqCtx), "failed to exportStatisticsSnapshots"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// exportStatisticsSnapshots exports the snapshots of the complete (UTC) days after the last exported one, up to the previous one, oldest first.
+// At most maxStatisticsSnapshotDaysPerRun days are exported per run, so that long backfills are spread across runs.
+// The objects are named after their day, so exporting a day again, by another replica or after deleting the progress key, just overwrites it.
+func (p *processor) exportStatisticsSnapshots(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	const day = hoursInOneDay * stdlibtime.Hour
+	lastDay := time.Now().Truncate(day).Add(-day)
+	values, err := p.getGlobalValues(ctx, statisticsSnapshotsExportedUntilGlobalKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed to getGlobalValues for key:%v", statisticsSnapshotsExportedUntilGlobalKey)
+	}
+	from := lastDay
+	switch {
+	case len(values) != 0:
+		from = stdlibtime.Unix(int64(values[0].Value), 0).UTC().Add(day)
+	case p.cfg.StatisticsSnapshots.BackfillFrom != "":
+		from, _ = stdlibtime.Parse(stdlibtime.DateOnly, p.cfg.StatisticsSnapshots.BackfillFrom) //nolint:errcheck // It's validated.
+	}
+	for current, exported := from, 0; !current.After(lastDay) && exported < maxStatisticsSnapshotDaysPerRun; current, exported = current.Add(day), exported+1 {
+		// There's no history of the users per country, so they're exported only for the last day, as of now.
+		if err = p.exportStatisticsSnapshot(ctx, current, current.Equal(lastDay)); err != nil {
+			return errors.Wrapf(err, "failed to exportStatisticsSnapshot for %v", current.Format(stdlibtime.DateOnly))
+		}
+		sql := `INSERT INTO global (key, value) VALUES ($1, $2)
+				ON CONFLICT (key) DO UPDATE
+					SET value = GREATEST(global.value, EXCLUDED.value),
+						updated_at = current_timestamp`
+		if _, err = auditedExec(ctx, p.db, sql, statisticsSnapshotsExportedUntilGlobalKey, current.Unix()); err != nil {
+			return errors.Wrapf(err, "failed to update %v to %v", statisticsSnapshotsExportedUntilGlobalKey, current.Format(stdlibtime.DateOnly))
+		}
+	}
+
+	return nil
+}
+
+func (p *processor) exportStatisticsSnapshot(ctx context.Context, day stdlibtime.Time, withTopCountries bool) error {
+	date := day.Format(stdlibtime.DateOnly)
+	growth, err := p.userGrowthSnapshot(ctx, day)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the user growth snapshot for %v", date)
+	}
+	if err = p.putStatisticsSnapshot(ctx, "user_growth", date, []string{"date", "total_users", "active_users"}, [][]string{
+		{date, strconv.FormatUint(growth.Total, 10), strconv.FormatUint(growth.Active, 10)},
+	}); err != nil {
+		return err
+	}
+	sql := `SELECT day, kyc_step, entered, passed, failed, blocked, updated_at AS last_updated_at
+			FROM kyc_funnel_statistics
+			WHERE day = $1
+			ORDER BY kyc_step`
+	funnel, err := auditedSelect[kycFunnelStatistics](ctx, p.db, sql, day)
+	if err != nil {
+		return errors.Wrapf(err, "failed to select the kyc funnel statistics of %v", date)
+	}
+	records := make([][]string, 0, len(funnel))
+	for _, row := range funnel {
+		records = append(records, []string{
+			date, strconv.Itoa(int(row.Step)),
+			strconv.FormatUint(row.Entered, 10), strconv.FormatUint(row.Passed, 10),
+			strconv.FormatUint(row.Failed, 10), strconv.FormatUint(row.Blocked, 10),
+		})
+	}
+	if err = p.putStatisticsSnapshot(ctx, "kyc_funnel", date, []string{"date", "kyc_step", "entered", "passed", "failed", "blocked"}, records); err != nil {
+		return err
+	}
+	if !withTopCountries {
+		return nil
+	}
+	countries, err := auditedSelect[countryCount](ctx, p.db, `SELECT country, user_count FROM users_per_country ORDER BY user_count DESC, country`)
+	if err != nil {
+		return errors.Wrap(err, "failed to select users_per_country")
+	}
+	records = make([][]string, 0, len(countries))
+	for _, row := range countries {
+		records = append(records, []string{date, row.Country, strconv.FormatInt(row.UserCount, 10)})
+	}
+
+	return p.putStatisticsSnapshot(ctx, "top_countries", date, []string{"date", "country", "user_count"}, records)
+}
+
+// userGrowthSnapshot is the total users at the end of the day and the maximum of its active users, like GetUserGrowth reads them.
+func (p *processor) userGrowthSnapshot(ctx context.Context, day stdlibtime.Time) (*UserCount, error) {
+	endOfDay := day.Add(hoursInOneDay*stdlibtime.Hour - 1)
+	keys := append([]string{p.totalUsersGlobalParentKey(&endOfDay)}, p.totalActiveUsersGlobalChildrenKeys(&endOfDay)...)
+	values, err := p.getGlobalValues(ctx, keys...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to getGlobalValues for keys:%#v", keys)
+	}
+	count := new(UserCount)
+	for _, row := range values {
+		if row.Key == keys[0] {
+			count.Total = row.Value
+		} else if row.Value > count.Active {
+			count.Active = row.Value
+		}
+	}
+
+	return count, nil
+}
+
+// putStatisticsSnapshot uploads the snapshot as CSV, partitioned by table and day, the way the data warehouse loads it.
+func (p *processor) putStatisticsSnapshot(ctx context.Context, table, date string, header []string, records [][]string) error {
+	var buf bytes.Buffer
+	if err := csv.NewWriter(&buf).WriteAll(append([][]string{header}, records...)); err != nil {
+		return errors.Wrapf(err, "failed to write the %v csv of %v", table, date)
+	}
+	name := fmt.Sprintf("%[1]v/date=%[2]v/%[1]v.csv", table, date)
+
+	return errors.Wrapf(p.snapshotStorage.Put(ctx, name, "text/csv", buf.Bytes()), "failed to put %v", name)
+}
@@ -9,35 +9,109 @@ import (
 
 	"github.com/pkg/errors"
 
-	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	devicemetadata "github.com/ice-blockchain/eskimo/users/internal/device/metadata"
+	"github.com/ice-blockchain/wintr/time"
 )
 
-func (r *repository) GetTopCountries(ctx context.Context, keyword string, limit, offset uint64) (cs []*CountryStatistics, err error) {
+// GetTopCountries ranks and counts all the countries in the same query that pages through them,
+// so that the rank of the own one and the total come for free.
+func (r *repository) GetTopCountries(
+	ctx context.Context, keyword string, userID UserID, limit, offset uint64,
+) (cs []*CountryStatistics, total uint64, lastUpdatedAt *time.Time, err error) {
 	if ctx.Err() != nil {
-		return nil, errors.Wrap(ctx.Err(), "get top countries failed because context failed")
+		return nil, 0, nil, errors.Wrap(ctx.Err(), "get top countries failed because context failed")
+	}
+	ownCountry, err := r.getOwnCountry(ctx, userID)
+	if err != nil {
+		return nil, 0, nil, errors.Wrapf(err, "failed to get the country of userID:%v", userID)
+	}
+	version, err := r.statisticsVersion(ctx, `SELECT max(updated_at) AS version FROM users_per_country`)
+	if err != nil {
+		return nil, 0, nil, errors.Wrap(err, "failed to get the version of the top countries")
+	}
+	cacheKey := topCountriesStatisticsCacheKey(keyword, ownCountry, limit, offset)
+	if cached, cachedLastUpdatedAt, found := r.statisticsCache.get(cacheKey, version); found {
+		tc := cached.(*topCountries) //nolint:forcetypeassert // We know for sure.
+
+		return tc.Countries, tc.Total, cachedLastUpdatedAt, nil
 	}
 	countries, countryParams := r.getTopCountriesParams(keyword)
-	params := []any{limit, offset}
+	params := []any{limit, offset, ownCountry}
 	params = append(params, countryParams...)
 	sql := fmt.Sprintf(`
-						SELECT  country, 
-								user_count 
-						FROM users_per_country
-						WHERE lower(country) in (%v)
-						ORDER BY user_count desc 
-						LIMIT $1 OFFSET $2`, countries)
-	cs, err = storage.Select[CountryStatistics](ctx, r.db, sql, params...)
+						WITH ranked AS (
+							SELECT  country,
+									user_count,
+									updated_at,
+									rank() OVER (ORDER BY user_count DESC) AS rank,
+									round((100 * percent_rank() OVER (ORDER BY user_count))::numeric, 2)::double precision AS percentile
+							FROM users_per_country
+						), matching AS (
+							SELECT  *,
+									count(1) OVER () AS total
+							FROM ranked
+							WHERE lower(country) in (%v)
+						), page AS (
+							SELECT  *,
+									max(updated_at) OVER () AS last_updated_at
+							FROM matching
+							ORDER BY user_count desc
+							LIMIT $1 OFFSET $2
+						)
+						SELECT  country,
+								user_count,
+								rank,
+								percentile,
+								own,
+								last_updated_at,
+								total
+						FROM (SELECT *, country = $3 AS own, 0 AS part FROM page
+							  UNION ALL
+							  SELECT *, (SELECT count(1) FROM matching), updated_at, true, 1
+							  FROM ranked
+							  WHERE country = $3
+								AND $3 != ''
+								AND country NOT IN (SELECT country FROM page)) t
+						ORDER BY part, user_count desc`, countries)
+	res, err := auditedSelect[topCountryStatistics](ctx, r.db, sql, params...)
 	if err != nil {
-		return nil, errors.Wrapf(err, "get top countries failed for %v %v %v", keyword, limit, offset)
+		return nil, 0, nil, errors.Wrapf(err, "get top countries failed for %v %v %v %v", keyword, ownCountry, limit, offset)
+	}
+	cs = make([]*CountryStatistics, 0, len(res))
+	for _, row := range res {
+		cs, total = append(cs, &row.CountryStatistics), row.Total
+		if lastUpdatedAt == nil || (row.LastUpdatedAt != nil && row.LastUpdatedAt.After(*lastUpdatedAt.Time)) {
+			lastUpdatedAt = row.LastUpdatedAt
+		}
 	}
+	r.statisticsCache.set(cacheKey, &topCountries{Countries: cs, Total: total}, lastUpdatedAt, version)
 
-	return
+	return cs, total, lastUpdatedAt, nil
+}
+
+func (r *repository) getOwnCountry(ctx context.Context, userID UserID) (devicemetadata.Country, error) {
+	if userID == "" {
+		return "", nil
+	}
+	type own struct {
+		Country devicemetadata.Country `db:"country"`
+	}
+	usr, err := auditedGet[own](ctx, r.db, `SELECT country FROM users WHERE id = $1`, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "", nil
+		}
+
+		return "", errors.Wrapf(err, "failed to select the country of userID:%v", userID)
+	}
+
+	return usr.Country, nil
 }
 
 func (r *repository) getTopCountriesParams(countryKeyword string) (countriesSQLEnumeration string, params []any) {
 	countriesSQLEnumeration = "''"
 	params = make([]any, 0)
-	const initialParamIdx = 3 // 1 and 2 are limit and offset.
+	const initialParamIdx = 4 // 1, 2 and 3 are limit, offset and the own country.
 	keyword := strings.ToLower(countryKeyword)
 	if keyword == "" {
 		countriesSQLEnumeration = "lower(country)"
@@ -76,7 +150,8 @@ func (r *repository) updateTotalUsersPerCountryCount(ctx context.Context, usr *U
 		INSERT INTO users_per_country (country, user_count) 
 		VALUES %[1]v
 		ON CONFLICT (country) DO UPDATE
-		  SET user_count = (CASE WHEN %[2]v THEN GREATEST(users_per_country.user_count + 1, 0) ELSE GREATEST(users_per_country.user_count - 1, 0) END)`
+		  SET user_count = (CASE WHEN %[2]v THEN GREATEST(users_per_country.user_count + 1, 0) ELSE GREATEST(users_per_country.user_count - 1, 0) END),
+		      updated_at = current_timestamp`
 	if usr.User != nil {
 		values = append(values, fmt.Sprintf("($%v,1)", nextIndex))
 		params = append(params, usr.User.Country)
@@ -88,7 +163,9 @@ func (r *repository) updateTotalUsersPerCountryCount(ctx context.Context, usr *U
 		params = append(params, usr.Before.Country)
 	}
 	sql := fmt.Sprintf(sqlTemplate, strings.Join(values, ","), incrementCondition)
-	_, err := storage.Exec(ctx, r.db, sql, params...)
+	if _, err := auditedExec(ctx, r.db, sql, params...); err != nil {
+		return errors.Wrapf(err, "error changing country count for params:%#v", params...)
+	}
 
-	return errors.Wrapf(err, "error changing country count for params:%#v", params...)
+	return nil
 }
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetUserBlocks(ctx context.Context, userID UserID, limit, offset uint64) ([]*UserBlock, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT *
+			FROM user_blocks
+			WHERE user_id = $1
+			ORDER BY created_at DESC
+			LIMIT $2 OFFSET $3`
+	res, err := auditedSelect[UserBlock](ctx, r.db, sql, userID, limit, offset)
+
+	return res, errors.Wrapf(err, "failed to select user blocks for userID:%v", userID)
+}
+
+func (r *repository) BlockUser(ctx context.Context, userID, blockedUserID UserID) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `INSERT INTO user_blocks (created_at, user_id, blocked_user_id) VALUES ($1, $2, $3)`
+	if _, err := auditedExec(ctx, r.db, sql, time.Now().Time, userID, blockedUserID); err != nil {
+		return errors.Wrapf(err, "failed to insert user block of %v for userID:%v", blockedUserID, userID)
+	}
+
+	return errors.Wrapf(r.sendBlocksChangedUserSnapshotMessage(ctx, userID), "failed to sendBlocksChangedUserSnapshotMessage for userID:%v", userID)
+}
+
+func (r *repository) UnblockUser(ctx context.Context, userID, blockedUserID UserID) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `DELETE FROM user_blocks WHERE user_id = $1 AND blocked_user_id = $2`
+	if deleted, err := auditedExec(ctx, r.db, sql, userID, blockedUserID); err != nil || deleted == 0 {
+		if err == nil {
+			err = ErrNotFound
+		}
+
+		return errors.Wrapf(err, "failed to delete user block of %v for userID:%v", blockedUserID, userID)
+	}
+
+	return errors.Wrapf(r.sendBlocksChangedUserSnapshotMessage(ctx, userID), "failed to sendBlocksChangedUserSnapshotMessage for userID:%v", userID)
+}
+
+func (r *repository) sendBlocksChangedUserSnapshotMessage(ctx context.Context, userID UserID) error {
+	usr, err := r.getUserByID(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get user for userID:%v", userID)
+	}
+	usr = r.sanitizeUser(usr)
+	us := &UserSnapshot{User: usr, Before: usr, Event: BlocksChangedUserSnapshotEvent}
+
+	return errors.Wrapf(r.sendUserSnapshotMessage(ctx, us), "failed to send blocks changed user message for %#v", us)
+}
+
+// blockedUserIDs returns the users blocked by userID, to be included in its snapshots.
+func (r *repository) blockedUserIDs(ctx context.Context, userID UserID) ([]UserID, error) {
+	type blockedUser struct {
+		BlockedUserID UserID `db:"blocked_user_id"`
+	}
+	sql := `SELECT blocked_user_id FROM user_blocks WHERE user_id = $1 ORDER BY created_at`
+	res, err := auditedSelect[blockedUser](ctx, r.db, sql, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select blocked users of userID:%v", userID)
+	}
+	blockedUserIDs := make([]UserID, 0, len(res))
+	for _, blocked := range res {
+		blockedUserIDs = append(blockedUserIDs, blocked.BlockedUserID)
+	}
+
+	return blockedUserIDs, nil
+}
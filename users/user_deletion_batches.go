@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	stdlibtime "time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// CreateUserDeletionBatch only stores the batch; its users are deleted (or anonymized) in the background, by the processor,
+// in chunks of `userDeletionBatches.chunkSize` every `userDeletionBatches.interval`, so that the message broker isn't overwhelmed.
+func (r *repository) CreateUserDeletionBatch(ctx context.Context, batch *UserDeletionBatch) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	batch.ID = uuid.NewString()
+	batch.CreatedAt = time.Now()
+	batch.FinishedAt = nil
+	slices.Sort(batch.UserIDs)
+	batch.UserIDs = slices.Compact(batch.UserIDs)
+	if preview := dryRun(ctx); preview != nil {
+		batch.ID, batch.DryRun = "", preview
+
+		return errors.Wrapf(r.previewUserDeletionBatch(ctx, batch), "failed to preview user deletion batch %#v", batch)
+	}
+
+	return errors.Wrapf(storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		sql := `INSERT INTO user_deletion_batches (created_at, id, requested_by, reason, mode) VALUES ($1, $2, $3, $4, $5)`
+		if _, err := auditedExec(ctx, conn, sql, batch.CreatedAt.Time, batch.ID, batch.RequestedBy, batch.Reason, batch.Mode); err != nil {
+			return errors.Wrapf(err, "failed to insert user deletion batch %#v", batch)
+		}
+		sql = `INSERT INTO user_deletion_batch_items (batch_id, user_id) SELECT $1, unnest($2::text[])`
+		_, err := auditedExec(ctx, conn, sql, batch.ID, batch.UserIDs)
+
+		return errors.Wrapf(err, "failed to insert the items of user deletion batch %v", batch.ID)
+	}), "failed to create user deletion batch %#v", batch)
+}
+
+// previewUserDeletionBatch previews the deletion of all the users concurrently, the same way the processor would process them.
+func (r *repository) previewUserDeletionBatch(ctx context.Context, batch *UserDeletionBatch) error {
+	var (
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, max(r.cfg.UserDeletionBatches.Concurrency, 1))
+		errs    = make([]error, len(batch.UserIDs))
+		process = r.DeleteUser
+	)
+	if batch.Mode == AnonymizeUserDeletionBatchMode {
+		process = r.AnonymizeUser
+	}
+	for ix, userID := range batch.UserIDs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := process(ctx, userID); err != nil {
+				if errors.Is(err, ErrNotFound) {
+					batch.DryRun.skip(userID, "not found")
+
+					return
+				}
+				errs[ix] = err
+			}
+		}()
+	}
+	wg.Wait()
+
+	return multierror.Append(nil, errs...).ErrorOrNil() //nolint:wrapcheck // Not needed.
+}
+
+func (r *repository) GetUserDeletionBatch(ctx context.Context, batchID string) (*UserDeletionBatchReport, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT created_at, finished_at, id, requested_by, reason, mode FROM user_deletion_batches WHERE id = $1`
+	batch, err := auditedGet[UserDeletionBatch](ctx, r.db, sql, batchID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get user deletion batch %v", batchID)
+	}
+	sql = `SELECT outcome, count(1) AS count FROM user_deletion_batch_items WHERE batch_id = $1 GROUP BY outcome`
+	counts, err := auditedSelect[userDeletionBatchCount](ctx, r.db, sql, batchID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to count the items of user deletion batch %v", batchID)
+	}
+	sql = `SELECT user_id, error FROM user_deletion_batch_items WHERE batch_id = $1 AND outcome = $2 ORDER BY user_id`
+	failures, err := auditedSelect[UserDeletionFailure](ctx, r.db, sql, batchID, FailedUserDeletionOutcome)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select the failed items of user deletion batch %v", batchID)
+	}
+	report := &UserDeletionBatchReport{UserDeletionBatch: batch, Failures: failures}
+	for _, count := range counts {
+		report.Total += count.Count
+		if count.Outcome == nil {
+			continue
+		}
+		report.Processed += count.Count
+		switch *count.Outcome {
+		case DeletedUserDeletionOutcome:
+			report.Deleted += count.Count
+		case AnonymizedUserDeletionOutcome:
+			report.Anonymized += count.Count
+		case NotFoundUserDeletionOutcome:
+			report.NotFound += count.Count
+		case FailedUserDeletionOutcome:
+			report.Failed += count.Count
+		}
+	}
+
+	return report, nil
+}
+
+func (p *processor) startUserDeletionBatchesProcessor(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.UserDeletionBatches.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reqCtx, cancel := context.WithTimeout(ctx, p.cfg.UserDeletionBatches.ClaimTTL)
+			log.Error(errors.Wrap(p.processUserDeletionBatches(reqCtx), "failed to processUserDeletionBatches"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// processUserDeletionBatches claims the next chunk of pending items, of any batch, and processes them.
+// The items are claimed, rather than locked, so that the replicas share the work and the ones claimed by a dead replica
+// are picked up again after `userDeletionBatches.claimTtl`; that makes the batches resumable.
+func (p *processor) processUserDeletionBatches(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	items, err := p.claimUserDeletionBatchItems(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to claimUserDeletionBatchItems")
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, p.cfg.UserDeletionBatches.Concurrency)
+	)
+	errs := make([]error, len(items))
+	for ix, item := range items {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[ix] = p.processUserDeletionBatchItem(ctx, item)
+		}()
+	}
+	wg.Wait()
+	for _, err = range errs {
+		log.Error(err)
+	}
+
+	return errors.Wrap(p.finishUserDeletionBatches(ctx), "failed to finishUserDeletionBatches")
+}
+
+func (p *processor) claimUserDeletionBatchItems(ctx context.Context) ([]*userDeletionBatchItem, error) {
+	now := time.Now()
+	sql := `WITH claimed AS (
+				UPDATE user_deletion_batch_items
+				SET claimed_at = $1
+				WHERE (batch_id, user_id) IN (SELECT batch_id, user_id
+											  FROM user_deletion_batch_items
+											  WHERE processed_at IS NULL
+												AND (claimed_at IS NULL OR claimed_at < $2)
+											  ORDER BY batch_id, user_id
+											  LIMIT $3
+											  FOR UPDATE SKIP LOCKED)
+				RETURNING batch_id, user_id
+			)
+			SELECT claimed.batch_id, claimed.user_id, b.requested_by, b.mode
+			FROM claimed
+				JOIN user_deletion_batches b ON b.id = claimed.batch_id`
+	items, err := auditedExecMany[userDeletionBatchItem](ctx, p.db, sql,
+		now.Time, now.Add(-p.cfg.UserDeletionBatches.ClaimTTL), p.cfg.UserDeletionBatches.ChunkSize)
+
+	return items, errors.Wrap(err, "failed to claim user deletion batch items")
+}
+
+func (p *processor) processUserDeletionBatchItem(ctx context.Context, item *userDeletionBatchItem) error {
+	var (
+		outcome = DeletedUserDeletionOutcome
+		errMsg  *string
+	)
+	ctx = context.WithValue(ctx, RequestingUserIDCtxValueKey, item.RequestedBy) //nolint:revive,staticcheck // .
+	process := p.DeleteUser
+	if item.Mode == AnonymizeUserDeletionBatchMode {
+		process, outcome = p.AnonymizeUser, AnonymizedUserDeletionOutcome
+	}
+	if err := process(ctx, item.UserID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			outcome = NotFoundUserDeletionOutcome
+		} else {
+			if ctx.Err() != nil {
+				return errors.Wrapf(ctx.Err(), "deadline exceeded, item %#v will be retried", item)
+			}
+			outcome = FailedUserDeletionOutcome
+			msg := err.Error()
+			errMsg = &msg
+		}
+	}
+	sql := `UPDATE user_deletion_batch_items SET processed_at = $1, outcome = $2, error = $3 WHERE batch_id = $4 AND user_id = $5`
+	_, err := auditedExec(ctx, p.db, sql, time.Now().Time, outcome, errMsg, item.BatchID, item.UserID)
+
+	return errors.Wrapf(err, "failed to mark user deletion batch item %#v as %v", item, outcome)
+}
+
+func (p *processor) finishUserDeletionBatches(ctx context.Context) error {
+	sql := `UPDATE user_deletion_batches b
+			SET finished_at = $1
+			WHERE finished_at IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM user_deletion_batch_items i WHERE i.batch_id = b.id AND i.processed_at IS NULL)
+			RETURNING b.id`
+	finished, err := auditedExecMany[UserDeletionBatch](ctx, p.db, sql, time.Now().Time)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark the finished user deletion batches")
+	}
+	for _, batch := range finished {
+		log.Info(fmt.Sprintf("user deletion batch `%v` finished", batch.ID))
+	}
+
+	return nil
+}
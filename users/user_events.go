@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetUserEvents(
+	ctx context.Context, userID UserID, eventType UserEventType, from, to *time.Time, limit, offset uint64,
+) ([]*UserEvent, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT *
+			FROM user_events
+			WHERE created_at >= $1
+			  AND created_at <= $2
+			  AND ($3 = '' OR user_id = $3)
+			  AND ($4 = '' OR event_type = $4)
+			ORDER BY created_at DESC, user_id, event_type
+			LIMIT $5 OFFSET $6`
+	res, err := auditedSelect[UserEvent](ctx, r.db, sql, from.Time, to.Time, userID, eventType, limit, offset)
+
+	return res, errors.Wrapf(err, "failed to select user events for userID:%v, eventType:%v, from:%v, to:%v", userID, eventType, from, to)
+}
+
+// recordUserEvents appends the lifecycle events of the snapshot to the log. The snapshots of the blocks changes aren't lifecycle events.
+func (r *repository) recordUserEvents(ctx context.Context, us *UserSnapshot) error {
+	events, err := userEvents(ctx, us)
+	if err != nil || len(events) == 0 {
+		return errors.Wrap(err, "failed to build the user events")
+	}
+	values := make([]string, 0, len(events))
+	const fields = 4
+	params := make([]any, 0, fields*len(events)+1)
+	params = append(params, time.Now().Time)
+	for ix, event := range events {
+		values = append(values, fmt.Sprintf("($1, $%v, $%v, $%v, $%v)", fields*ix+2, fields*ix+3, fields*ix+4, fields*ix+5)) //nolint:gomnd // .
+		params = append(params, event.UserID, event.EventType, event.Fields, event.KYCStep)
+	}
+	sql := fmt.Sprintf(`INSERT INTO user_events (created_at, user_id, event_type, fields, kyc_step)
+						VALUES %v
+						ON CONFLICT DO NOTHING`, strings.Join(values, ","))
+	_, err = auditedExec(ctx, r.db, sql, params...)
+
+	return errors.Wrapf(err, "failed to insert user events %#v", events)
+}
+
+//nolint:exhaustive // The blocks changes aren't recorded.
+func userEvents(ctx context.Context, us *UserSnapshot) ([]*UserEvent, error) {
+	switch us.eventType() {
+	case CreatedUserSnapshotEvent:
+		return []*UserEvent{{UserID: us.User.ID, EventType: CreatedUserEventType}}, nil
+	case DeletedUserSnapshotEvent:
+		return []*UserEvent{{UserID: us.Before.ID, EventType: DeletedUserEventType}}, nil
+	case MergedUserSnapshotEvent:
+		return []*UserEvent{{UserID: us.Before.ID, EventType: MergedUserEventType}}, nil
+	case AnonymizedUserSnapshotEvent:
+		return []*UserEvent{{UserID: us.User.ID, EventType: AnonymizedUserEventType}}, nil
+	case UpdatedUserSnapshotEvent:
+		modified, err := modifiedFields(ctx, us.Before, us.User)
+		if err != nil || len(modified) == 0 {
+			return nil, errors.Wrapf(err, "failed to get the modified fields of userID:%v", us.User.ID)
+		}
+		events := []*UserEvent{{UserID: us.User.ID, EventType: ModifiedUserEventType, Fields: modified}}
+		if step := kycStepOrNone(us.User.KYCStepPassed); step != kycStepOrNone(us.Before.KYCStepPassed) {
+			events = append(events, &UserEvent{UserID: us.User.ID, EventType: KYCStepPassedUserEventType, KYCStep: &step})
+		}
+		if step := kycStepOrNone(us.User.KYCStepBlocked); step != kycStepOrNone(us.Before.KYCStepBlocked) {
+			events = append(events, &UserEvent{UserID: us.User.ID, EventType: KYCStepBlockedUserEventType, KYCStep: &step})
+		}
+
+		return events, nil
+	default:
+		return nil, nil
+	}
+}
+
+// modifiedFields returns the names of the top level fields that differ between the two versions of the user, sorted. `updatedAt` always does.
+func modifiedFields(ctx context.Context, before, after *User) ([]string, error) {
+	beforeFields, err := profileFields(ctx, &UserProfile{User: before})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the fields of %#v", before)
+	}
+	afterFields, err := profileFields(ctx, &UserProfile{User: after})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the fields of %#v", after)
+	}
+	changes, removed := diffProfileFields(beforeFields, afterFields)
+	modified := removed
+	for field := range changes {
+		if field != "updatedAt" {
+			modified = append(modified, field)
+		}
+	}
+	slices.Sort(modified)
+
+	return modified, nil
+}
+
+func (p *processor) startUserEventsCleaner(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(userEventsCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reqCtx, cancel := context.WithTimeout(ctx, requestDeadline)
+			log.Error(errors.Wrap(p.deleteExpiredUserEvents(reqCtx), "failed to deleteExpiredUserEvents"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deleteExpiredUserEvents deletes the events older than the retention. The replicas can race, it's idempotent.
+func (p *processor) deleteExpiredUserEvents(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	before := time.Now().Add(-p.cfg.UserEvents.Retention)
+	_, err := auditedExec(ctx, p.db, `DELETE FROM user_events WHERE created_at < $1`, before)
+
+	return errors.Wrapf(err, "failed to delete the user events before %v", before)
+}
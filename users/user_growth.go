@@ -14,21 +14,61 @@ import (
 
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
 	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
 	"github.com/ice-blockchain/wintr/time"
 )
 
-func (r *repository) GetUserGrowth(ctx context.Context, days uint64, tz *stdlibtime.Location) (*UserGrowthStatistics, error) {
+func (r *repository) GetUserGrowth(
+	ctx context.Context, days uint64, tz *stdlibtime.Location,
+) (ugs *UserGrowthStatistics, lastUpdatedAt *time.Time, err error) {
 	if ctx.Err() != nil {
-		return nil, errors.Wrap(ctx.Err(), "context failed")
+		return nil, nil, errors.Wrap(ctx.Err(), "context failed")
 	}
 	now := time.Now()
+	_, tzOffset := now.In(tz).Zone()
 	keys := r.generateUserGrowthKeys(now, days)
+	version, err := r.statisticsVersion(ctx, `SELECT max(updated_at) AS version FROM global WHERE key = ANY($1)`, keys)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get the version of the user growth")
+	}
+	cacheKey := userGrowthStatisticsCacheKey(days, tzOffset)
+	if cached, cachedLastUpdatedAt, found := r.statisticsCache.get(cacheKey, version); found {
+		return cached.(*UserGrowthStatistics), cachedLastUpdatedAt, nil //nolint:forcetypeassert // We know for sure.
+	}
 	values, err := r.getGlobalValues(ctx, keys...)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to getGlobalValues for keys:%#v", keys)
+		return nil, nil, errors.Wrapf(err, "failed to getGlobalValues for keys:%#v", keys)
 	}
+	ugs, lastUpdatedAt = r.aggregateGlobalValuesToGrowth(days, now, values, keys, tz), maxLastUpdatedAt(values)
+	r.statisticsCache.set(cacheKey, ugs, lastUpdatedAt, version)
 
-	return r.aggregateGlobalValuesToGrowth(days, now, values, keys, tz), nil
+	return ugs, lastUpdatedAt, nil
+}
+
+// GetRollingActiveUsers counts the distinct users that mined in the rolling windows at read time, since the active users counters
+// are per aggregation interval, so they can't be merged without counting the same users more than once.
+func (r *repository) GetRollingActiveUsers(ctx context.Context) (*RollingActiveUsers, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	cacheKey := rollingActiveUsersStatisticsCacheKey()
+	if cached, _, found := r.statisticsCache.get(cacheKey, nil); found {
+		return cached.(*RollingActiveUsers), nil //nolint:forcetypeassert // We know for sure.
+	}
+	now := time.Now()
+	weekAgo := now.Add(-weeklyActiveUsersWindow * r.cfg.GlobalAggregationInterval.Parent)
+	monthAgo := now.Add(-monthlyActiveUsersWindow * r.cfg.GlobalAggregationInterval.Parent)
+	sql := `SELECT count(1) FILTER (WHERE last_mining_ended_at >= $1) AS weekly,
+				   count(1) 										   AS monthly
+			FROM users
+			WHERE last_mining_ended_at >= $2`
+	res, err := auditedGet[RollingActiveUsers](ctx, r.db, sql, weekAgo, monthAgo)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count the rolling active users")
+	}
+	r.statisticsCache.set(cacheKey, res, now, nil)
+
+	return res, nil
 }
 
 func (r *repository) generateUserGrowthKeys(now *time.Time, days uint64) []string {
@@ -133,7 +173,7 @@ func (r *repository) getGlobalValues(ctx context.Context, keys ...string) ([]*Gl
 						FROM global
 						WHERE key in (%v)
 						ORDER BY POSITION(key in $1)`, strings.Join(placeholders, ","))
-	vals, err := storage.Select[GlobalUnsigned](ctx, r.db, sql, params...)
+	vals, err := auditedSelect[GlobalUnsigned](ctx, r.db, sql, params...)
 
 	return vals, errors.Wrapf(err, "failed to select global vals for keys:%#v", keys)
 }
@@ -141,7 +181,13 @@ func (r *repository) getGlobalValues(ctx context.Context, keys ...string) ([]*Gl
 func (r *repository) updateTotalUsersCount(ctx context.Context, usr *UserSnapshot) error {
 	if isFirstMiningAfterHumanVerification := (usr.Before == nil || usr.Before.ID == "") && usr.User != nil && usr.User.ID != "" &&
 		usr.User.isFirstMiningAfterHumanVerification(r); isFirstMiningAfterHumanVerification {
-		return r.incrementOrDecrementTotalUsers(ctx, time.Now(), true)
+		if err := r.incrementOrDecrementTotalUsers(ctx, time.Now(), true); err != nil {
+			return err
+		}
+		// It's not retried with this snapshot, since it would be counted again, but with the next one.
+		log.Error(errors.Wrap(r.emitUserMilestones(ctx), "failed to emitUserMilestones"))
+
+		return nil
 	}
 
 	if isDeleteAfterHumanVerification := (usr.User == nil || usr.User.ID == "") && usr.Before != nil && usr.Before.ID != "" &&
@@ -174,8 +220,9 @@ func (r *repository) incrementOrDecrementTotalUsers(ctx context.Context, date *t
 	}
 	sql := fmt.Sprintf(`INSERT INTO global (key, value) VALUES %[2]v
 								ON CONFLICT (key) DO UPDATE    
-						SET value = (select GREATEST(total.value %[1]v 1,0) FROM global total WHERE total.key = '%[3]v')`, operation, strings.Join(sqlParams, ","), params[0])
-	if _, err := storage.Exec(ctx, r.db, sql, params...); err != nil && !storage.IsErr(err, storage.ErrNotFound) {
+						SET value = (select GREATEST(total.value %[1]v 1,0) FROM global total WHERE total.key = '%[3]v'),
+						    updated_at = current_timestamp`, operation, strings.Join(sqlParams, ","), params[0])
+	if _, err := auditedExec(ctx, r.db, sql, params...); err != nil && !storage.IsErr(err, storage.ErrNotFound) {
 		return errors.Wrapf(err, "failed to update global.value to global.value%v1 of key='%v', for params:%#v ", operation, totalUsersGlobalKey, params)
 	}
 	keys := make([]string, 0, len(params))
@@ -202,9 +249,10 @@ func (r *repository) incrementTotalActiveUsersCount(ctx context.Context, ms *min
 				INSERT INTO global (key, value) VALUES 
 					%v
 				ON CONFLICT (key) DO UPDATE   
-						SET value = global.value + 1`, strings.Join(sqlParams, ","))
+						SET value = global.value + 1,
+						    updated_at = current_timestamp`, strings.Join(sqlParams, ","))
 
-	if _, err := storage.Exec(ctx, r.db, sql, keys...); err != nil && !storage.IsErr(err, storage.ErrNotFound) {
+	if _, err := auditedExec(ctx, r.db, sql, keys...); err != nil && !storage.IsErr(err, storage.ErrNotFound) {
 		return errors.Wrapf(err, "failed to update global.value to global.value+1 for keys:%#v", keys) //nolint:asasalint // Wrong.
 	}
 
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"net"
+	stdlibtime "time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users/internal/ulid"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/terror"
+)
+
+// createUserWithGeneratedID creates the user with an ID generated as per `userIds.scheme`. If it collides with an existing one,
+// which is very unlikely, but not impossible, the user is created again with a new one, from scratch, because its defaults are derived from it.
+func (r *repository) createUserWithGeneratedID(ctx context.Context, usr *User, clientIP net.IP) error {
+	requested := *usr
+	for attempt := 1; ; attempt++ {
+		userID, err := generateUserID(r.cfg.UserIDs.Scheme)
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate an userID with the scheme `%v`", r.cfg.UserIDs.Scheme)
+		}
+		usr.ID = userID
+		if err = r.CreateUser(ctx, usr, clientIP); err == nil || !isUserIDCollision(err) || attempt == maxUserIDGenerationAttempts {
+			return errors.Wrapf(err, "failed to create the user with the generated userID:%v", userID)
+		}
+		log.Warn(fmt.Sprintf("generated userID:%v collided with an existing one (attempt %v), generating another", userID, attempt))
+		*usr = requested
+	}
+}
+
+func generateUserID(scheme UserIDScheme) (UserID, error) {
+	if scheme == UUIDUserIDScheme {
+		return IceUserIDPrefix + uuid.NewString(), nil
+	}
+	id, err := ulid.New(stdlibtime.Now())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate ulid")
+	}
+
+	return IceUserIDPrefix + id, nil
+}
+
+func isUserIDCollision(err error) bool {
+	if !errors.Is(err, ErrDuplicate) {
+		return false
+	}
+	tErr := terror.As(err)
+
+	return tErr != nil && tErr.Data["field"] == "id"
+}
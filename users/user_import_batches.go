@@ -0,0 +1,493 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"sync"
+	stdlibtime "time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// CreateUserImportBatch only stores the batch, with a processed item for each invalid or duplicate record and a pending one for the rest;
+// the users are imported in the background, by the processor, in chunks of `userImportBatches.chunkSize` every `userImportBatches.interval`.
+func (r *repository) CreateUserImportBatch(ctx context.Context, batch *UserImportBatch, records io.Reader) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	batch.ID = uuid.NewString()
+	batch.CreatedAt = time.Now()
+	batch.FinishedAt = nil
+	items, err := parseUserImportRecords(records, batch.CreatedAt)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the records of user import batch %#v", batch)
+	}
+	if preview := dryRun(ctx); preview != nil {
+		batch.ID, batch.DryRun = "", preview
+
+		return errors.Wrapf(r.previewUserImportBatch(ctx, batch, items), "failed to preview user import batch %#v", batch)
+	}
+	var (
+		lines    = make([]uint64, 0, len(items))
+		userIDs  = make([]*string, 0, len(items))
+		recs     = make([]*string, 0, len(items))
+		outcomes = make([]*string, 0, len(items))
+		errs     = make([]*string, 0, len(items))
+	)
+	for _, item := range items {
+		lines, userIDs, recs, outcomes, errs = append(lines, item.line), append(userIDs, item.userID), append(recs, item.record), append(outcomes, item.outcome), append(errs, item.err) //nolint:lll // .
+	}
+
+	return errors.Wrapf(storage.DoInTransaction(ctx, r.db, func(conn storage.QueryExecer) error {
+		sql := `INSERT INTO user_import_batches (created_at, id, requested_by, reason, snapshots) VALUES ($1, $2, $3, $4, $5)`
+		if _, iErr := auditedExec(ctx, conn, sql, batch.CreatedAt.Time, batch.ID, batch.RequestedBy, batch.Reason, batch.Snapshots); iErr != nil {
+			return errors.Wrapf(iErr, "failed to insert user import batch %#v", batch)
+		}
+		sql = `INSERT INTO user_import_batch_items (processed_at, batch_id, line, user_id, record, outcome, error)
+				SELECT (CASE WHEN t.outcome IS NULL THEN NULL ELSE $2::timestamp END), $1, t.line, t.user_id, t.record::jsonb, t.outcome, t.error
+				FROM unnest($3::bigint[], $4::text[], $5::text[], $6::text[], $7::text[]) AS t(line, user_id, record, outcome, error)`
+		_, iErr := auditedExec(ctx, conn, sql, batch.ID, batch.CreatedAt.Time, lines, userIDs, recs, outcomes, errs)
+
+		return errors.Wrapf(iErr, "failed to insert the items of user import batch %v", batch.ID)
+	}), "failed to create user import batch %#v", batch)
+}
+
+type (
+	parsedUserImportRecord struct {
+		rec                          *UserImportRecord
+		userID, record, outcome, err *string
+		line                         uint64
+	}
+)
+
+//nolint:funlen,revive // It's easier to follow in one place.
+func parseUserImportRecords(records io.Reader, now *time.Time) ([]*parsedUserImportRecord, error) {
+	const maxLineLength = 64 * 1024
+	var (
+		scanner                = bufio.NewScanner(records)
+		res                    = make([]*parsedUserImportRecord, 0, MaxUserImportBatchSize/100) //nolint:mnd // Just a hint.
+		ids, emails, usernames = make(map[string]uint64), make(map[string]uint64), make(map[string]uint64)
+		line                   uint64
+		invalid, duplicate     = string(InvalidUserImportOutcome), string(DuplicateUserImportOutcome)
+	)
+	scanner.Buffer(make([]byte, 0, maxLineLength), maxLineLength)
+	for scanner.Scan() {
+		line++
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+		if len(res) == MaxUserImportBatchSize {
+			return nil, errors.Wrapf(ErrInvalidUserImportFile, "more than %v records", MaxUserImportBatchSize)
+		}
+		item := &parsedUserImportRecord{line: line}
+		res = append(res, item)
+		var rec UserImportRecord
+		if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+			item.outcome, item.err = &invalid, stringPtr(fmt.Sprintf("invalid json: %v", err))
+
+			continue
+		}
+		if err := rec.normalizeAndValidate(now); err != nil {
+			item.outcome, item.err = &invalid, stringPtr(err.Error())
+			if rec.ID != "" {
+				item.userID = &rec.ID
+			}
+
+			continue
+		}
+		item.userID = &rec.ID
+		if firstLine, found := ids[rec.ID]; found {
+			item.outcome, item.err = &duplicate, stringPtr(fmt.Sprintf("the id is the same as the one of line %v", firstLine))
+
+			continue
+		}
+		if firstLine, found := emails[rec.Email]; found {
+			item.outcome, item.err = &duplicate, stringPtr(fmt.Sprintf("the email is the same as the one of line %v", firstLine))
+
+			continue
+		}
+		if firstLine, found := usernames[strings.ToLower(rec.Username)]; found && rec.Username != "" {
+			item.outcome, item.err = &duplicate, stringPtr(fmt.Sprintf("the username is the same as the one of line %v", firstLine))
+
+			continue
+		}
+		ids[rec.ID], emails[rec.Email] = line, line
+		if rec.Username != "" {
+			usernames[strings.ToLower(rec.Username)] = line
+		}
+		val, err := json.Marshal(&rec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal %#v", &rec)
+		}
+		item.rec, item.record = &rec, stringPtr(string(val))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(ErrInvalidUserImportFile, "failed to read line %v: %v", line+1, err)
+	}
+	if len(res) == 0 {
+		return nil, errors.Wrap(ErrInvalidUserImportFile, "no records")
+	}
+
+	return res, nil
+}
+
+// previewUserImportBatch skips, besides the invalid and duplicate lines, the ones that the processor would fail to import:
+// the users that exist already, the ones whose email or username is taken, whose referrer can't be found or whose username is profane.
+//
+//nolint:funlen,gocognit,revive // .
+func (r *repository) previewUserImportBatch(ctx context.Context, batch *UserImportBatch, items []*parsedUserImportRecord) error {
+	ids, emails, usernames := make([]string, 0, len(items)), make([]string, 0, len(items)), make([]string, 0, len(items))
+	emailIndexes := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.rec == nil {
+			continue
+		}
+		ids, emails = append(ids, item.rec.ID), append(emails, item.rec.Email)
+		if emailIndex := r.EmailIndex(item.rec.Email); emailIndex != nil {
+			emailIndexes = append(emailIndexes, *emailIndex)
+		}
+		if item.rec.Username != "" {
+			usernames = append(usernames, item.rec.Username)
+		}
+		if item.rec.ReferredBy != "" {
+			ids = append(ids, item.rec.ReferredBy)
+		}
+	}
+	sql := `SELECT id, email, username FROM users WHERE id = ANY($1) OR email = ANY($2) OR username = ANY($3) OR email_index = ANY($4)`
+	existing, err := auditedSelect[User](ctx, r.db, sql, ids, emails, usernames, emailIndexes)
+	if err != nil {
+		return errors.Wrap(err, "failed to select the existing users")
+	}
+	existingIDs, takenEmails, takenUsernames := make(map[string]bool, len(existing)), make(map[string]bool, len(existing)), make(map[string]bool, len(existing))
+	for _, usr := range existing {
+		existingIDs[usr.ID], takenEmails[r.open("email", usr.Email)], takenUsernames[usr.Username] = true, true, true
+	}
+	var events []UserSnapshotEvent
+	if batch.Snapshots != SuppressUserImportSnapshotsMode {
+		events = append(events, CreatedUserSnapshotEvent)
+	}
+	imported := make(map[string]bool, len(items))
+	for _, item := range items {
+		record := fmt.Sprintf("line %v", item.line)
+		if item.rec == nil {
+			batch.DryRun.skip(record, *item.err)
+
+			continue
+		}
+		switch {
+		case existingIDs[item.rec.ID]:
+			batch.DryRun.skip(record, "the user exists already")
+		case takenEmails[item.rec.Email]:
+			batch.DryRun.skip(record, "the email is used by another user")
+		case item.rec.Username != "" && takenUsernames[item.rec.Username]:
+			batch.DryRun.skip(record, "the username is used by another user")
+		case item.rec.ReferredBy != "" && !existingIDs[item.rec.ReferredBy] && !imported[item.rec.ReferredBy]:
+			batch.DryRun.skip(record, "referredBy not found, referrers have to be existing users or come before their referrals")
+		default:
+			usr := item.rec.user()
+			if pErr := r.screenProfanity(ctx, usr.Language, usr, nil); pErr != nil {
+				if !errors.Is(pErr, ErrProfanity) {
+					return errors.Wrapf(pErr, "failed to screenProfanity for %#v", item.rec)
+				}
+				batch.DryRun.skip(record, pErr.Error())
+
+				continue
+			}
+			imported[item.rec.ID] = true
+			batch.DryRun.affect(item.rec.ID, map[string]uint64{"users": 1}, events...)
+		}
+	}
+
+	return nil
+}
+
+func (rec *UserImportRecord) normalizeAndValidate(now *time.Time) error {
+	rec.ID = strings.TrimSpace(rec.ID)
+	rec.Email = strings.ToLower(strings.TrimSpace(rec.Email))
+	rec.Username = strings.TrimSpace(rec.Username)
+	rec.ReferredBy = strings.TrimSpace(rec.ReferredBy)
+	if rec.ReferredBy == rec.ID {
+		rec.ReferredBy = ""
+	}
+	if rec.ID == "" || strings.ContainsAny(rec.ID, " \t") {
+		return errors.New("invalid id")
+	}
+	if addr, err := mail.ParseAddress(rec.Email); err != nil || addr.Address != rec.Email {
+		return errors.New("invalid email")
+	}
+	if rec.Username != "" && !CompiledUsernameRegex.MatchString(rec.Username) {
+		return errors.Errorf("invalid username, it should match regex: %v", UsernameRegex)
+	}
+	if rec.CreatedAt != nil && (rec.CreatedAt.IsZero() || rec.CreatedAt.After(*now.Time)) {
+		return errors.New("invalid createdAt, it can't be in the future")
+	}
+
+	return nil
+}
+
+func stringPtr(val string) *string {
+	return &val
+}
+
+func (r *repository) GetUserImportBatch(ctx context.Context, batchID string) (*UserImportBatchReport, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT created_at, finished_at, id, requested_by, reason, snapshots FROM user_import_batches WHERE id = $1`
+	batch, err := auditedGet[UserImportBatch](ctx, r.db, sql, batchID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get user import batch %v", batchID)
+	}
+	sql = `SELECT outcome, count(1) AS count FROM user_import_batch_items WHERE batch_id = $1 GROUP BY outcome`
+	counts, err := auditedSelect[userImportBatchCount](ctx, r.db, sql, batchID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to count the items of user import batch %v", batchID)
+	}
+	sql = `SELECT line, user_id, outcome, error FROM user_import_batch_items WHERE batch_id = $1 AND outcome = ANY($2::text[]) ORDER BY line`
+	failures, err := auditedSelect[UserImportFailure](ctx, r.db, sql, batchID, []string{string(InvalidUserImportOutcome), string(FailedUserImportOutcome)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select the failed items of user import batch %v", batchID)
+	}
+	report := &UserImportBatchReport{UserImportBatch: batch, Failures: failures}
+	for _, count := range counts {
+		report.Total += count.Count
+		if count.Outcome == nil {
+			continue
+		}
+		report.Processed += count.Count
+		switch *count.Outcome {
+		case ImportedUserImportOutcome:
+			report.Imported += count.Count
+		case DuplicateUserImportOutcome:
+			report.Duplicate += count.Count
+		case InvalidUserImportOutcome:
+			report.Invalid += count.Count
+		case FailedUserImportOutcome:
+			report.Failed += count.Count
+		}
+	}
+
+	return report, nil
+}
+
+func (p *processor) startUserImportBatchesProcessor(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.UserImportBatches.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reqCtx, cancel := context.WithTimeout(ctx, p.cfg.UserImportBatches.ClaimTTL)
+			log.Error(errors.Wrap(p.processUserImportBatches(reqCtx), "failed to processUserImportBatches"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// processUserImportBatches claims the next chunk of pending items, of any batch, and imports them, the same way processUserDeletionBatches does.
+// The snapshots of the `batch` batches are sent together, once the chunk is imported, instead of one by one.
+func (p *processor) processUserImportBatches(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	items, err := p.claimUserImportBatchItems(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to claimUserImportBatchItems")
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, p.cfg.UserImportBatches.Concurrency)
+	)
+	errs := make([]error, len(items))
+	snapshots := make([]*UserSnapshot, len(items))
+	for ix, item := range items {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			snapshots[ix], errs[ix] = p.processUserImportBatchItem(ctx, item)
+		}()
+	}
+	wg.Wait()
+	for _, err = range errs {
+		log.Error(err)
+	}
+	p.sendUserImportSnapshots(ctx, snapshots)
+
+	return errors.Wrap(p.finishUserImportBatches(ctx), "failed to finishUserImportBatches")
+}
+
+func (p *processor) claimUserImportBatchItems(ctx context.Context) ([]*userImportBatchItem, error) {
+	now := time.Now()
+	sql := `WITH claimed AS (
+				UPDATE user_import_batch_items
+				SET claimed_at = $1
+				WHERE (batch_id, line) IN (SELECT batch_id, line
+										   FROM user_import_batch_items
+										   WHERE processed_at IS NULL
+											 AND (claimed_at IS NULL OR claimed_at < $2)
+										   ORDER BY batch_id, line
+										   LIMIT $3
+										   FOR UPDATE SKIP LOCKED)
+				RETURNING batch_id, line, record
+			)
+			SELECT claimed.batch_id, claimed.line, claimed.record, b.requested_by, b.snapshots
+			FROM claimed
+				JOIN user_import_batches b ON b.id = claimed.batch_id`
+	items, err := auditedExecMany[userImportBatchItem](ctx, p.db, sql,
+		now.Time, now.Add(-p.cfg.UserImportBatches.ClaimTTL), p.cfg.UserImportBatches.ChunkSize)
+
+	return items, errors.Wrap(err, "failed to claim user import batch items")
+}
+
+// processUserImportBatchItem returns the snapshot of the imported user, if it has to be sent later, with the rest of the chunk.
+//
+//nolint:funlen // .
+func (p *processor) processUserImportBatchItem(ctx context.Context, item *userImportBatchItem) (*UserSnapshot, error) {
+	var (
+		outcome  = ImportedUserImportOutcome
+		errMsg   *string
+		snapshot *UserSnapshot
+		usr      = item.Record.user()
+	)
+	ctx = context.WithValue(ctx, RequestingUserIDCtxValueKey, item.RequestedBy) //nolint:revive,staticcheck // .
+	err := p.screenProfanity(ctx, usr.Language, usr, nil)
+	if err == nil {
+		err = p.insertUser(ctx, usr)
+	}
+	if err == nil {
+		snapshot = &UserSnapshot{User: p.sanitizeUser(usr), BlockedUserIDs: []UserID{}, BlockchainAddresses: []*BlockchainAddress{}}
+		if item.Snapshots == SendUserImportSnapshotsMode {
+			if err = p.sendUserSnapshotMessage(ctx, snapshot); err != nil {
+				err = multierror.Append(errors.Wrap(err, "failed to send user created message"), //nolint:wrapcheck // Not needed.
+					errors.Wrapf(p.deleteUser(ctx, usr), "failed to delete user due to rollback, for userID:%v", usr.ID)).ErrorOrNil()
+			}
+			snapshot = nil
+		}
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.Wrapf(ctx.Err(), "deadline exceeded, item %#v will be retried", item)
+		}
+		snapshot, outcome = nil, FailedUserImportOutcome
+		field, tErr := detectAndParseDuplicateDatabaseError(err)
+		switch {
+		case field == "id":
+			outcome, errMsg = DuplicateUserImportOutcome, stringPtr("the user exists already")
+		case field != "":
+			errMsg = stringPtr(fmt.Sprintf("the %v is used by another user", field))
+		case storage.IsErr(tErr, storage.ErrRelationNotFound):
+			if released, rErr := p.releaseUserImportBatchItemWaitingForReferrer(ctx, item); rErr != nil || released {
+				return nil, errors.Wrapf(rErr, "failed to releaseUserImportBatchItemWaitingForReferrer for %#v", item)
+			}
+			errMsg = stringPtr("referredBy not found, referrers have to be existing users or come before their referrals")
+		default:
+			errMsg = stringPtr(err.Error())
+		}
+	}
+	sql := `UPDATE user_import_batch_items SET processed_at = $1, outcome = $2, error = $3 WHERE batch_id = $4 AND line = $5`
+	_, err = auditedExec(ctx, p.db, sql, time.Now().Time, outcome, errMsg, item.BatchID, item.Line)
+
+	return snapshot, errors.Wrapf(err, "failed to mark user import batch item %#v as %v", item, outcome)
+}
+
+// releaseUserImportBatchItemWaitingForReferrer unclaims the item if its referrer is imported by an earlier, pending line of the same batch,
+// possibly of the same chunk, so that it's retried with the next chunk.
+func (p *processor) releaseUserImportBatchItemWaitingForReferrer(ctx context.Context, item *userImportBatchItem) (bool, error) {
+	sql := `UPDATE user_import_batch_items
+			SET claimed_at = NULL
+			WHERE batch_id = $1
+			  AND line = $2
+			  AND EXISTS (SELECT 1
+						  FROM user_import_batch_items
+						  WHERE batch_id = $1
+							AND user_id = $3
+							AND line < $2
+							AND processed_at IS NULL)`
+	rows, err := auditedExec(ctx, p.db, sql, item.BatchID, item.Line, item.Record.ReferredBy)
+
+	return rows == 1, errors.Wrapf(err, "failed to release user import batch item %#v", item)
+}
+
+// sendUserImportSnapshots sends the snapshots of the chunk concurrently. The users are imported already, so the failures are only logged.
+func (p *processor) sendUserImportSnapshots(ctx context.Context, snapshots []*UserSnapshot) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, p.cfg.UserImportBatches.Concurrency)
+	for _, snapshot := range snapshots {
+		if snapshot == nil {
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			log.Error(errors.Wrapf(p.sendUserSnapshotMessage(ctx, snapshot), "failed to send user created message for imported userID:%v", snapshot.ID))
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *processor) finishUserImportBatches(ctx context.Context) error {
+	sql := `UPDATE user_import_batches b
+			SET finished_at = $1
+			WHERE finished_at IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM user_import_batch_items i WHERE i.batch_id = b.id AND i.processed_at IS NULL)
+			RETURNING b.id`
+	finished, err := auditedExecMany[UserImportBatch](ctx, p.db, sql, time.Now().Time)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark the finished user import batches")
+	}
+	for _, batch := range finished {
+		log.Info(fmt.Sprintf("user import batch `%v` finished", batch.ID))
+	}
+
+	return nil
+}
+
+func (rec *UserImportRecord) user() *User {
+	usr := new(User)
+	usr.ID = rec.ID
+	usr.Email = rec.Email
+	usr.Username = rec.Username
+	if usr.Username == "" {
+		usr.Username = usr.ID
+	}
+	usr.ReferredBy = rec.ReferredBy
+	if usr.ReferredBy == "" {
+		usr.ReferredBy = usr.ID
+	}
+	usr.UpdatedAt = time.Now()
+	usr.CreatedAt = rec.CreatedAt
+	if usr.CreatedAt == nil {
+		usr.CreatedAt = usr.UpdatedAt
+	}
+	usr.ProfilePictureURL = RandomDefaultProfilePictureName()
+	usr.MiningBlockchainAccountAddress, usr.BlockchainAccountAddress = usr.ID, usr.ID
+	usr.PhoneNumber, usr.PhoneNumberHash = usr.ID, usr.ID
+	usr.Language = "en"
+	randomReferredBy := false
+	usr.RandomReferredBy = &randomReferredBy
+
+	return usr
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"slices"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+const levelProgressSQL = `
+	SELECT u.id AS user_id,
+		   u.hidden_profile_elements,
+		   COALESCE(refs.t1, 0) AS t1_referrals,
+		   COALESCE(ul.mining_sessions, 0) AS mining_sessions,
+		   COALESCE(ul.level, 0) AS level,
+		   COALESCE(u.kyc_step_passed, 0) AS kyc_step_passed
+	FROM users u
+		LEFT JOIN referral_acquisition_history refs
+			   ON refs.user_id = u.id
+		LEFT JOIN user_levels ul
+			   ON ul.user_id = u.id
+	WHERE u.id = ANY($1)`
+
+func (r *repository) GetUserLevel(ctx context.Context, userID UserID) (*UserLevel, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	progress, err := auditedGet[levelProgress](ctx, r.db, levelProgressSQL, []UserID{userID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the level progress of userID:%v", userID)
+	}
+	if userID != requestingUserID(ctx) {
+		if progress.HiddenProfileElements != nil && slices.Contains(*progress.HiddenProfileElements, LevelHiddenProfileElement) {
+			return new(UserLevel), nil
+		}
+
+		return &UserLevel{Level: &progress.Level}, nil
+	}
+
+	return &UserLevel{Level: &progress.Level, Next: r.cfg.nextLevelProgress(progress)}, nil
+}
+
+func (c *config) nextLevelProgress(progress *levelProgress) *LevelProgress {
+	if progress.Level >= uint64(len(c.Levels)) {
+		return nil
+	}
+	next := c.Levels[progress.Level]
+	res := &LevelProgress{Level: progress.Level + 1, Criteria: make([]*LevelCriterion, 0, 1+1+1)}
+	for _, criterion := range []*LevelCriterion{
+		{Name: T1ReferralsLevelCriterion, Current: progress.T1Referrals, Required: next.T1Referrals},
+		{Name: KYCStepPassedLevelCriterion, Current: uint64(progress.KYCStepPassed), Required: uint64(next.KYCStepPassed)},
+		{Name: MiningSessionsLevelCriterion, Current: progress.MiningSessions, Required: next.MiningSessions},
+	} {
+		if criterion.Required == 0 {
+			continue
+		}
+		res.Criteria = append(res.Criteria, criterion)
+		res.Percentage += min(criterion.Current, criterion.Required) * 100 / criterion.Required //nolint:gomnd // It's a percentage.
+	}
+	res.Percentage /= uint64(len(res.Criteria))
+
+	return res
+}
+
+// reachedLevel is the highest level whose criteria, and the ones of all the levels before it, are met.
+func (c *config) reachedLevel(progress *levelProgress) uint64 {
+	for ix, level := range c.Levels {
+		if progress.T1Referrals < level.T1Referrals || progress.MiningSessions < level.MiningSessions || progress.KYCStepPassed < level.KYCStepPassed {
+			return uint64(ix)
+		}
+	}
+
+	return uint64(len(c.Levels))
+}
+
+// levelUp checks the levels of the user and of its referrer, whose T1 referrals might have just changed.
+func (s *userSnapshotSource) levelUp(ctx context.Context, us *UserSnapshot) error {
+	if us.User == nil {
+		return nil
+	}
+	if us.ReferredBy != "" && us.ReferredBy != us.ID {
+		return errors.Wrapf(s.updateLevels(ctx, us.ID, us.ReferredBy), "failed to updateLevels for userID:%v and its referrer", us.ID)
+	}
+
+	return errors.Wrapf(s.updateLevels(ctx, us.ID), "failed to updateLevels for userID:%v", us.ID)
+}
+
+// updateLevels raises the levels of the users that reached higher ones and announces them. The levels are never lowered.
+// Each level up is stored only if the level didn't change in the meantime, so that it's announced exactly once.
+func (r *repository) updateLevels(ctx context.Context, userIDs ...UserID) error {
+	if len(r.cfg.Levels) == 0 {
+		return nil
+	}
+	candidates, err := auditedSelect[levelProgress](ctx, r.db, levelProgressSQL, userIDs)
+	if err != nil {
+		return errors.Wrapf(err, "failed to select the level progress of userIDs:%v", userIDs)
+	}
+	for _, candidate := range candidates {
+		reached := r.cfg.reachedLevel(candidate)
+		if reached <= candidate.Level {
+			continue
+		}
+		sql := `INSERT INTO user_levels (user_id, level) VALUES ($1, $2)
+				ON CONFLICT (user_id) DO UPDATE
+					SET level = EXCLUDED.level
+					WHERE user_levels.level = $3`
+		updated, uErr := auditedExec(ctx, r.db, sql, candidate.UserID, reached, candidate.Level)
+		if uErr != nil {
+			if errors.Is(uErr, ErrRelationNotFound) {
+				continue
+			}
+
+			return errors.Wrapf(uErr, "failed to raise the level of userID:%v to %v", candidate.UserID, reached)
+		}
+		if updated == 0 {
+			continue
+		}
+		levelUp := &UserLevelUp{ReachedAt: time.Now(), UserID: candidate.UserID, Level: reached, PreviousLevel: candidate.Level}
+		if err = r.sendUserLevelUpMessage(ctx, levelUp); err != nil {
+			return errors.Wrapf(err, "failed to sendUserLevelUpMessage for %#v", levelUp)
+		}
+	}
+
+	return nil
+}
+
+func (r *repository) sendUserLevelUpMessage(ctx context.Context, levelUp *UserLevelUp) error {
+	valueBytes, err := json.MarshalContext(ctx, levelUp)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", levelUp)
+	}
+	msg := &messagebroker.Message{
+		Headers: map[string]string{"producer": "eskimo"},
+		Key:     levelUp.UserID,
+		Topic:   r.cfg.MessageBroker.Topics[14].Name,
+		Value:   valueBytes,
+	}
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+
+	return errors.Wrapf(<-responder, "failed to send `%v` message to broker", msg.Topic)
+}
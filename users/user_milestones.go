@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-json"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// emitUserMilestones emits the `userMilestones` the total users crossed. Every one is claimed in the global table first,
+// so that it's emitted exactly once, by whichever replica claims it. If it can't be sent, the claim is reverted, to be retried with the next user.
+func (r *repository) emitUserMilestones(ctx context.Context) error {
+	milestones := r.cfg.UserMilestones
+	if len(milestones) == 0 || r.lastUserMilestone.Load() >= milestones[len(milestones)-1] {
+		return nil
+	}
+	total, err := auditedGet[globalCount](ctx, r.db, `SELECT value FROM global WHERE key = $1`, totalUsersGlobalKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get global value for key:%v", totalUsersGlobalKey)
+	}
+	for _, milestone := range milestones {
+		if milestone <= r.lastUserMilestone.Load() {
+			continue
+		}
+		if total.Value < 0 || milestone > uint64(total.Value) {
+			return nil
+		}
+		key := fmt.Sprintf("%v_%v", userMilestoneGlobalKeyPrefix, milestone)
+		sql := `INSERT INTO global (key, value) VALUES ($1, $2) ON CONFLICT (key) DO NOTHING`
+		claimed, cErr := auditedExec(ctx, r.db, sql, key, total.Value)
+		if cErr != nil {
+			return errors.Wrapf(cErr, "failed to claim the user milestone %v", milestone)
+		}
+		if claimed == 1 {
+			um := &UserMilestone{ReachedAt: time.Now(), Milestone: milestone, TotalUsers: uint64(total.Value)}
+			if sErr := r.sendUserMilestoneMessage(ctx, um); sErr != nil {
+				_, dErr := auditedExec(ctx, r.db, `DELETE FROM global WHERE key = $1`, key)
+
+				return multierror.Append(errors.Wrapf(sErr, "failed to sendUserMilestoneMessage for %#v", um), //nolint:wrapcheck // Not needed.
+					errors.Wrapf(dErr, "[revert] failed to unclaim the user milestone %v", milestone)).ErrorOrNil()
+			}
+		}
+		r.lastUserMilestone.Store(milestone)
+	}
+
+	return nil
+}
+
+func (r *repository) sendUserMilestoneMessage(ctx context.Context, um *UserMilestone) error {
+	valueBytes, err := json.MarshalContext(ctx, um)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", um)
+	}
+	msg := &messagebroker.Message{
+		Headers: map[string]string{"producer": "eskimo"},
+		Key:     fmt.Sprint(um.Milestone),
+		Topic:   r.cfg.MessageBroker.Topics[12].Name,
+		Value:   valueBytes,
+	}
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+
+	return errors.Wrapf(<-responder, "failed to send `%v` message to broker", msg.Topic)
+}
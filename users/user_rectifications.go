@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// RectifyUser applies the personal data of usr (first name, last name, username, country and city) on behalf of its user,
+// for legal rectification requests. Unlike ModifyUser, it fails on a checksum conflict only if it's not explicitly overridden,
+// and it bypasses the country change limits. The changes are audited, with their values before and after, and the user is notified.
+func (r *repository) RectifyUser(ctx context.Context, rectification *UserRectification, usr *User) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	before, err := r.getUserByID(ctx, rectification.UserID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get user for userID:%v", rectification.UserID)
+	}
+	if rectification.Checksum != before.Checksum() {
+		if !rectification.Override {
+			return errors.Wrapf(ErrRaceCondition, "userID:%v was changed since checksum `%v`", rectification.UserID, rectification.Checksum)
+		}
+		rectification.Overridden = true
+	}
+	checksum := before.Checksum()
+	usr.ID = rectification.UserID
+	if len(rectificationChanges(r.sanitizeUser(before), before.override(usr))) == 0 {
+		return errors.Wrapf(ErrNothingToRectify, "userID:%v already has the provided values", rectification.UserID)
+	}
+	// The changes are diffed against before, so nothing must change in between, unnoticed.
+	modifyCtx := context.WithValue(ctx, checksumCtxValueKey, checksum)              //nolint:revive,staticcheck // Not an issue.
+	modifyCtx = context.WithValue(modifyCtx, countryChangeDecidedCtxValueKey, true) //nolint:revive,staticcheck // Not an issue.
+	modifyCtx = context.WithValue(modifyCtx, profanityOverriddenCtxValueKey, true)  //nolint:revive,staticcheck // Not an issue.
+	if err = r.ModifyUser(modifyCtx, usr, nil); err != nil {
+		return errors.Wrapf(err, "failed to ModifyUser for %#v", rectification)
+	}
+	if rectification.Changes = rectificationChanges(before, usr); len(rectification.Changes) == 0 { // They were the same, once canonicalized.
+		return errors.Wrapf(ErrNothingToRectify, "userID:%v already had the provided values", rectification.UserID)
+	}
+	rectification.RectifiedAt = time.Now()
+	sql := `INSERT INTO user_rectifications (rectified_at, changes, user_id, rectified_by, reason_code, justification, overridden)
+			VALUES ($1, $2::jsonb, $3, $4, $5, $6, $7)`
+	changes, err := json.MarshalContext(ctx, rectification.Changes)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", rectification.Changes)
+	}
+	if _, err = auditedExec(ctx, r.db, sql,
+		rectification.RectifiedAt.Time,
+		string(changes),
+		rectification.UserID,
+		rectification.RectifiedBy,
+		rectification.ReasonCode,
+		rectification.Justification,
+		rectification.Overridden,
+	); err != nil {
+		return errors.Wrapf(err, "failed to insert user rectification %#v", rectification)
+	}
+
+	return errors.Wrapf(r.sendUserRectificationMessage(ctx, rectification), "failed to sendUserRectificationMessage for %#v", rectification)
+}
+
+func rectificationChanges(before, after *User) map[string]*UserRectificationChange {
+	changes := make(map[string]*UserRectificationChange)
+	for field, values := range map[string][2]string{
+		"firstName": {stringValue(before.FirstName), stringValue(after.FirstName)},
+		"lastName":  {stringValue(before.LastName), stringValue(after.LastName)},
+		"username":  {before.Username, after.Username},
+		"country":   {before.Country, after.Country},
+		"city":      {before.City, after.City},
+	} {
+		if values[0] != values[1] {
+			changes[field] = &UserRectificationChange{Before: values[0], After: values[1]}
+		}
+	}
+
+	return changes
+}
+
+func stringValue(val *string) string {
+	if val == nil {
+		return ""
+	}
+
+	return *val
+}
+
+func (r *repository) sendUserRectificationMessage(ctx context.Context, rectification *UserRectification) error {
+	valueBytes, err := json.MarshalContext(ctx, rectification)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", rectification)
+	}
+	msg := &messagebroker.Message{
+		Headers: map[string]string{"producer": "eskimo"},
+		Key:     rectification.UserID,
+		Topic:   r.cfg.MessageBroker.Topics[10].Name,
+		Value:   valueBytes,
+	}
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+
+	return errors.Wrapf(<-responder, "failed to send user rectification message to broker")
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// GetReportedUsers returns the moderation queue: the users with pending reports, the flagged ones first, then the most reported ones.
+func (r *repository) GetReportedUsers(ctx context.Context, flaggedOnly bool, limit, offset uint64) ([]*ReportedUser, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT * FROM (
+				SELECT user_id,
+					   min(created_at) 							AS first_reported_at,
+					   max(created_at) 							AS last_reported_at,
+					   array_agg(DISTINCT reason ORDER BY reason) AS reasons,
+					   count(1) 								AS pending_reports,
+					   count(1) >= $1 							AS flagged_for_review
+				FROM user_reports
+				WHERE status = 'pending'
+				GROUP BY user_id
+			) reported
+			WHERE flagged_for_review OR NOT $2
+			ORDER BY flagged_for_review DESC, pending_reports DESC, first_reported_at, user_id
+			LIMIT $3 OFFSET $4`
+	res, err := auditedSelect[ReportedUser](ctx, r.db, sql, r.cfg.UserReports.FlagThreshold, flaggedOnly, limit, offset)
+
+	return res, errors.Wrap(err, "failed to select reported users")
+}
+
+func (r *repository) GetUserReports(ctx context.Context, userID UserID, limit, offset uint64) ([]*UserReport, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT *
+			FROM user_reports
+			WHERE user_id = $1
+			ORDER BY status != 'pending', created_at DESC
+			LIMIT $2 OFFSET $3`
+	res, err := auditedSelect[UserReport](ctx, r.db, sql, userID, limit, offset)
+
+	return res, errors.Wrapf(err, "failed to select user reports for userID:%v", userID)
+}
+
+func (r *repository) ReportUser(ctx context.Context, report *UserReport) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	report.CreatedAt, report.Status = time.Now(), PendingUserReportStatus
+	sql := `INSERT INTO user_reports (created_at, user_id, reported_by, reason, text, status) VALUES ($1, $2, $3, $4, $5, $6)`
+	if _, err := auditedExec(ctx, r.db, sql, report.CreatedAt.Time, report.UserID, report.ReportedBy, report.Reason, report.Text, report.Status); err != nil {
+		return errors.Wrapf(err, "failed to insert user report %#v", report)
+	}
+
+	return errors.Wrapf(r.sendReportedUserMessage(ctx, report.UserID, ""), "failed to sendReportedUserMessage for %#v", report)
+}
+
+func (r *repository) ResolveUserReports(
+	ctx context.Context, userID, adminUserID UserID, resolution UserReportStatus,
+) ([]*UserReport, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	if resolution != ActionedUserReportStatus && resolution != DismissedUserReportStatus {
+		return nil, errors.Wrapf(ErrInvalidUserReportResolution, "resolution `%v`", resolution)
+	}
+	sql := `UPDATE user_reports
+			SET status = $2,
+				resolved_at = $3,
+				resolved_by = $4
+			WHERE user_id = $1
+			  AND status = 'pending'
+			RETURNING *`
+	resolved, err := auditedExecMany[UserReport](ctx, r.db, sql, userID, resolution, time.Now().Time, adminUserID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve the reports of userID:%v as %v", userID, resolution)
+	}
+	if len(resolved) == 0 {
+		return nil, errors.Wrapf(ErrNotFound, "no pending reports for userID:%v", userID)
+	}
+
+	return resolved, errors.Wrapf(r.sendReportedUserMessage(ctx, userID, resolution), "failed to sendReportedUserMessage for userID:%v", userID)
+}
+
+// sendReportedUserMessage sends the current state of the pending reports of the user, so that it can be acted upon,
+// for ex. when it gets flagged for review.
+func (r *repository) sendReportedUserMessage(ctx context.Context, userID UserID, resolution UserReportStatus) error {
+	sql := `SELECT $1::text 							  AS user_id,
+				   min(created_at) 						  AS first_reported_at,
+				   max(created_at) 						  AS last_reported_at,
+				   COALESCE(array_agg(DISTINCT reason ORDER BY reason) FILTER (WHERE reason IS NOT NULL), '{}') AS reasons,
+				   count(1) 							  AS pending_reports,
+				   count(1) >= $2 						  AS flagged_for_review
+			FROM user_reports
+			WHERE user_id = $1
+			  AND status = 'pending'`
+	reported, err := auditedGet[ReportedUser](ctx, r.db, sql, userID, r.cfg.UserReports.FlagThreshold)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the pending reports of userID:%v", userID)
+	}
+	reported.Resolution = resolution
+	valueBytes, err := json.MarshalContext(ctx, reported)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", reported)
+	}
+	msg := &messagebroker.Message{
+		Headers: map[string]string{"producer": "eskimo"},
+		Key:     userID,
+		Topic:   r.cfg.MessageBroker.Topics[11].Name,
+		Value:   valueBytes,
+	}
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+
+	return errors.Wrapf(<-responder, "failed to send reported user message to broker")
+}
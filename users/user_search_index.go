@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	stdlibtime "time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/eskimo/users/internal/search"
+	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// searchIndexedUsersSQL selects the users, and their referrers' referrers, as they're indexed.
+const searchIndexedUsersSQL = `SELECT u.id,
+									  u.username,
+									  u.referred_by,
+									  (CASE WHEN referrer.referred_by != referrer.id THEN referrer.referred_by ELSE '' END) AS referrer_referred_by
+							   FROM users u
+									LEFT JOIN users referrer
+										   ON referrer.id = u.referred_by`
+
+// indexUser keeps the search index in sync with the users that can be found by GetUsers.
+// When the referrer of an user changes, its T1 referrals are reindexed too, because they're found as T2 referrals of its new referrer.
+func (s *userSnapshotSource) indexUser(ctx context.Context, us *UserSnapshot) error {
+	if s.searchIndex == nil {
+		return nil
+	}
+	if us.User == nil {
+		if us.Before == nil {
+			return nil
+		}
+
+		return errors.Wrapf(s.searchIndex.Delete(ctx, us.Before.ID), "failed to delete the deleted userID:%v from the search index", us.Before.ID)
+	}
+	if us.Before != nil && us.Before.ReferredBy != us.ReferredBy {
+		if err := s.reindexT1Referrals(ctx, us.ID); err != nil {
+			return errors.Wrapf(err, "failed to reindex the T1 referrals of userID:%v", us.ID)
+		}
+	}
+	if us.Before != nil && us.Before.Username == us.Username && us.Before.ReferredBy == us.ReferredBy {
+		return nil
+	}
+	usr := &searchIndexedUser{ID: us.ID, Username: us.Username, ReferredBy: us.ReferredBy}
+	if usr.ReferredBy != "" && usr.ReferredBy != usr.ID {
+		sql := `SELECT referred_by FROM users WHERE id = $1`
+		referrer, err := auditedGet[struct{ ReferredBy UserID }](ctx, s.db, sql, us.ReferredBy)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return errors.Wrapf(err, "failed to get the referrer of userID:%v", us.ID)
+		}
+		if referrer != nil && referrer.ReferredBy != us.ReferredBy {
+			usr.ReferrerReferredBy = referrer.ReferredBy
+		}
+	}
+	if doc := usr.searchDocument(); doc != nil {
+		return errors.Wrapf(s.searchIndex.Upsert(ctx, doc), "failed to index userID:%v", us.ID)
+	}
+
+	return errors.Wrapf(s.searchIndex.Delete(ctx, us.ID), "failed to delete the incomplete userID:%v from the search index", us.ID)
+}
+
+func (s *userSnapshotSource) reindexT1Referrals(ctx context.Context, userID UserID) error {
+	sql := searchIndexedUsersSQL + `
+			WHERE u.referred_by = $1
+			  AND u.id != $1
+			  AND u.id > $2
+			ORDER BY u.id
+			LIMIT $3`
+	for lastUserID := ""; ; {
+		usrs, err := auditedSelect[searchIndexedUser](ctx, s.db, sql, userID, lastUserID, userSearchIndexReferralsBatchSize)
+		if err != nil {
+			return errors.Wrapf(err, "failed to select the T1 referrals after userID:%v", lastUserID)
+		}
+		if err = s.searchIndex.Upsert(ctx, searchDocuments(usrs)...); err != nil {
+			return errors.Wrapf(err, "failed to index the T1 referrals after userID:%v", lastUserID)
+		}
+		if len(usrs) < userSearchIndexReferralsBatchSize {
+			return nil
+		}
+		lastUserID = usrs[len(usrs)-1].ID
+	}
+}
+
+func (p *processor) startUserSearchIndexBackfiller(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.UserSearchIndex.BackfillInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 5 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			completed, err := p.backfillUserSearchIndex(reqCtx)
+			log.Error(errors.Wrap(err, "failed to backfillUserSearchIndex"))
+			cancel()
+			if completed {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// backfillUserSearchIndex indexes the next batch of users, in the order of their IDs, and returns whether they all are.
+// The replicas might index the same batch concurrently, but only one of them moves the backfill past it.
+func (p *processor) backfillUserSearchIndex(ctx context.Context) (completed bool, err error) {
+	if ctx.Err() != nil {
+		return false, errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	sql := `INSERT INTO user_search_index_backfills (index_name) VALUES ($1) ON CONFLICT DO NOTHING`
+	if _, err = auditedExec(ctx, p.db, sql, p.searchIndex.Name()); err != nil {
+		return false, errors.Wrapf(err, "failed to insert the backfill of the search index %v", p.searchIndex.Name())
+	}
+	sql = `SELECT completed_at, last_user_id FROM user_search_index_backfills WHERE index_name = $1`
+	backfill, err := auditedGet[userSearchIndexBackfill](ctx, p.db, sql, p.searchIndex.Name())
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the backfill of the search index %v", p.searchIndex.Name())
+	}
+	if backfill.CompletedAt != nil {
+		return true, nil
+	}
+	sql = searchIndexedUsersSQL + `
+			WHERE u.id > $1
+			ORDER BY u.id
+			LIMIT $2`
+	usrs, err := auditedSelect[searchIndexedUser](ctx, p.db, sql, backfill.LastUserID, p.cfg.UserSearchIndex.BackfillBatchSize)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to select the users to index after userID:%v", backfill.LastUserID)
+	}
+	if err = p.searchIndex.Upsert(ctx, searchDocuments(usrs)...); err != nil {
+		return false, errors.Wrapf(err, "failed to index the users after userID:%v", backfill.LastUserID)
+	}
+	lastUserID, completedAt := backfill.LastUserID, (*stdlibtime.Time)(nil)
+	if len(usrs) != 0 {
+		lastUserID = usrs[len(usrs)-1].ID
+	}
+	if uint64(len(usrs)) < p.cfg.UserSearchIndex.BackfillBatchSize {
+		completedAt = time.Now().Time
+	}
+	sql = `UPDATE user_search_index_backfills SET last_user_id = $3, completed_at = $4 WHERE index_name = $1 AND last_user_id = $2 AND completed_at IS NULL`
+	if _, err = auditedExec(ctx, p.db, sql, p.searchIndex.Name(), backfill.LastUserID, lastUserID, completedAt); err != nil {
+		return false, errors.Wrapf(err, "failed to update the backfill of the search index %v", p.searchIndex.Name())
+	}
+	if completedAt != nil {
+		log.Info(fmt.Sprintf("backfilled the search index %v", p.searchIndex.Name()))
+	}
+
+	return completedAt != nil, nil
+}
+
+// isSearchIndexBackfilled returns whether the search index was backfilled, in which case it's used by GetUsers.
+func (r *repository) isSearchIndexBackfilled(ctx context.Context) bool {
+	if r.searchIndexBackfilled.Load() {
+		return true
+	}
+	sql := `SELECT completed_at, last_user_id FROM user_search_index_backfills WHERE index_name = $1`
+	backfill, err := auditedGet[userSearchIndexBackfill](ctx, r.db, sql, r.searchIndex.Name())
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Error(errors.Wrapf(err, "failed to get the backfill of the search index %v", r.searchIndex.Name()))
+		}
+
+		return false
+	}
+	if backfill.CompletedAt != nil {
+		r.searchIndexBackfilled.Store(true)
+	}
+
+	return backfill.CompletedAt != nil
+}
+
+// searchUserIDs returns the IDs of the users, related to the one requesting this, whose username is similar to the keyword.
+func (r *repository) searchUserIDs(ctx context.Context, keyword string) ([]UserID, error) {
+	sql := `SELECT id, referred_by, COALESCE(agenda_contact_user_ids, '{}') AS agenda_contact_user_ids FROM users WHERE id = $1`
+	requester, err := auditedGet[struct {
+		ID                   UserID
+		ReferredBy           UserID
+		AgendaContactUserIDs []UserID `db:"agenda_contact_user_ids"`
+	}](ctx, r.db, sql, requestingUserID(ctx))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return []UserID{}, nil
+		}
+
+		return nil, errors.Wrapf(err, "failed to get the user requesting this:%v", requestingUserID(ctx))
+	}
+	ids := append(make([]UserID, 0, len(requester.AgendaContactUserIDs)+1), requester.AgendaContactUserIDs...)
+	if requester.ReferredBy != "" && requester.ReferredBy != requester.ID {
+		ids = append(ids, requester.ReferredBy)
+	}
+	userIDs, err := r.searchIndex.Search(ctx, &search.Query{Keyword: keyword, RelatedTo: requester.ID, IDs: ids, Limit: maxUserSearchIndexCandidates})
+
+	return userIDs, errors.Wrapf(err, "failed to search the index for `%v`", keyword)
+}
+
+// searchDocument returns what's indexed for the user, or nil if it can't be found by GetUsers yet.
+func (u *searchIndexedUser) searchDocument() *search.Document {
+	if u.Username == u.ID || u.ReferredBy == "" || u.ReferredBy == u.ID {
+		return nil
+	}
+
+	return &search.Document{ID: u.ID, Username: u.Username, ReferredBy: u.ReferredBy, ReferrerReferredBy: u.ReferrerReferredBy}
+}
+
+func searchDocuments(usrs []*searchIndexedUser) []*search.Document {
+	docs := make([]*search.Document, 0, len(usrs))
+	for _, usr := range usrs {
+		if doc := usr.searchDocument(); doc != nil {
+			docs = append(docs, doc)
+		}
+	}
+
+	return docs
+}
@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	stdlibtime "time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/log"
+	"github.com/ice-blockchain/wintr/time"
+)
+
+func (r *repository) GetSquattedUsernames(ctx context.Context, limit, offset uint64) ([]*SquattedUsername, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	sql := `SELECT *
+			FROM squatted_usernames
+			ORDER BY released_at IS NOT NULL OR exempted_at IS NOT NULL, expires_at, user_id
+			LIMIT $1 OFFSET $2`
+	res, err := auditedSelect[SquattedUsername](ctx, r.db, sql, limit, offset)
+
+	return res, errors.Wrap(err, "failed to select squatted usernames")
+}
+
+// DecideSquattedUsername lets admins override the worker: exempted users are never flagged again,
+// while releasing frees the username right away, regardless of its expiry and of the activity of its user.
+func (r *repository) DecideSquattedUsername(ctx context.Context, userID, adminUserID UserID, decision SquattedUsernameDecision) (*SquattedUsername, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "context failed")
+	}
+	switch decision {
+	case ExemptSquattedUsernameDecision:
+		now := time.Now()
+		sql := `INSERT INTO squatted_usernames (flagged_at, expires_at, exempted_at, user_id, username, exempted_by)
+					SELECT $2, $2, $2, id, username, $3
+					FROM users
+					WHERE id = $1
+				ON CONFLICT (user_id) DO UPDATE
+					SET exempted_at = EXCLUDED.exempted_at,
+						exempted_by = EXCLUDED.exempted_by
+				RETURNING *`
+		squatted, err := auditedExecOne[SquattedUsername](ctx, r.db, sql, userID, now.Time, adminUserID)
+
+		return squatted, errors.Wrapf(err, "failed to exempt the username of userID:%v", userID)
+	case ReleaseSquattedUsernameDecision:
+		sql := `SELECT * FROM squatted_usernames WHERE user_id = $1 AND released_at IS NULL`
+		squatted, err := auditedExecOne[SquattedUsername](ctx, r.db, sql, userID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the squatted username of userID:%v", userID)
+		}
+		if err = r.releaseSquattedUsername(ctx, squatted, false); err != nil {
+			return nil, errors.Wrapf(err, "failed to releaseSquattedUsername for %#v", squatted)
+		}
+
+		return squatted, nil
+	default:
+		return nil, errors.Wrapf(ErrInvalidSquattedUsernameDecision, "decision `%v`", decision)
+	}
+}
+
+func (p *processor) startUsernameSquattingDetector(ctx context.Context) {
+	ticker := stdlibtime.NewTicker(p.cfg.UsernameSquatting.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			const deadline = 10 * stdlibtime.Minute
+			reqCtx, cancel := context.WithTimeout(ctx, deadline)
+			log.Error(errors.Wrap(p.detectUsernameSquatting(reqCtx), "failed to detectUsernameSquatting"))
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// detectUsernameSquatting flags the dormant users (never verified, never mined) holding desirable usernames for longer than
+// `usernameSquatting.dormancyPeriod` and notifies them. If they're still dormant `usernameSquatting.gracePeriod` later,
+// their username is released, i.e. reset to their ID, like for the users that never set one.
+func (p *processor) detectUsernameSquatting(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "unexpected deadline")
+	}
+	if err := p.clearSquattedUsernames(ctx); err != nil {
+		return errors.Wrap(err, "failed to clearSquattedUsernames")
+	}
+	flagged, err := p.flagSquattedUsernames(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to flagSquattedUsernames")
+	}
+	for _, squatted := range flagged {
+		if err = p.sendUsernameSquattingEventMessage(ctx, FlaggedUsernameSquattingEventType, squatted); err != nil {
+			return errors.Wrapf(err, "failed to sendUsernameSquattingEventMessage for %#v", squatted)
+		}
+	}
+	sql := `SELECT * FROM squatted_usernames WHERE released_at IS NULL AND exempted_at IS NULL AND expires_at < $1`
+	expired, err := auditedSelect[SquattedUsername](ctx, p.db, sql, time.Now().Time)
+	if err != nil {
+		return errors.Wrap(err, "failed to select expired squatted usernames")
+	}
+	for _, squatted := range expired {
+		if err = p.releaseSquattedUsername(ctx, squatted, true); err != nil {
+			return errors.Wrapf(err, "failed to releaseSquattedUsername for %#v", squatted)
+		}
+	}
+
+	return nil
+}
+
+// clearSquattedUsernames forgets the flags of the users that became active or changed their username in the meantime.
+func (p *processor) clearSquattedUsernames(ctx context.Context) error {
+	sql := `DELETE FROM squatted_usernames s
+			USING users u
+			WHERE s.user_id = u.id
+			  AND s.released_at IS NULL
+			  AND s.exempted_at IS NULL
+			  AND (u.username != s.username OR u.kyc_step_passed != $1 OR u.last_mining_started_at IS NOT NULL)`
+	_, err := auditedExec(ctx, p.db, sql, NoneKYCStep)
+
+	return errors.Wrap(err, "failed to delete squatted usernames of active users")
+}
+
+func (p *processor) flagSquattedUsernames(ctx context.Context) ([]*SquattedUsername, error) {
+	now := time.Now()
+	cfg := p.cfg.UsernameSquatting
+	sql := `INSERT INTO squatted_usernames (flagged_at, expires_at, user_id, username)
+				SELECT $1, $2, id, username
+				FROM users
+				WHERE created_at < $3
+				  AND kyc_step_passed = $4
+				  AND last_mining_started_at IS NULL
+				  AND username != id
+				  AND username ~ $5
+				  AND id != 'bogus'
+				  AND id != 'icenetwork'
+			ON CONFLICT (user_id) DO UPDATE
+				SET flagged_at = EXCLUDED.flagged_at,
+					expires_at = EXCLUDED.expires_at,
+					username = EXCLUDED.username,
+					released_at = NULL
+				WHERE squatted_usernames.released_at IS NOT NULL
+				  AND squatted_usernames.exempted_at IS NULL
+			RETURNING *`
+	flagged, err := auditedExecMany[SquattedUsername](ctx, p.db, sql,
+		now.Time, now.Add(cfg.GracePeriod), now.Add(-cfg.DormancyPeriod), NoneKYCStep, cfg.DesirableUsernameRegex)
+
+	return flagged, errors.Wrap(err, "failed to flag squatted usernames")
+}
+
+func (r *repository) releaseSquattedUsername(ctx context.Context, squatted *SquattedUsername, onlyIfDormant bool) error {
+	now := time.Now()
+	withoutUsername := &User{PublicUserInformation: PublicUserInformation{ID: squatted.UserID, Username: squatted.UserID}}
+	sql := `UPDATE users
+			SET username = id,
+				lookup = $4::tsvector,
+				updated_at = $3
+			WHERE id = $1
+			  AND username = $2
+			  AND (NOT $5 OR (kyc_step_passed = $6 AND last_mining_started_at IS NULL))`
+	released, err := auditedExec(ctx, r.db, sql,
+		squatted.UserID, squatted.Username, now.Time, withoutUsername.lookup(), onlyIfDormant, NoneKYCStep)
+	if err != nil {
+		return errors.Wrapf(err, "failed to release username %v of userID:%v", squatted.Username, squatted.UserID)
+	}
+	if released == 0 { // The user became active or changed the username in the meantime, so it'll be cleared.
+		return nil
+	}
+	sql = `UPDATE squatted_usernames SET released_at = $2 WHERE user_id = $1 RETURNING *`
+	updated, err := auditedExecOne[SquattedUsername](ctx, r.db, sql, squatted.UserID, now.Time)
+	if err != nil {
+		return errors.Wrapf(err, "failed to mark the username of userID:%v as released", squatted.UserID)
+	}
+	*squatted = *updated
+	usr, err := r.getUserByID(ctx, squatted.UserID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get user by id %v", squatted.UserID)
+	}
+	before := *usr
+	before.Username = squatted.Username
+	us := &UserSnapshot{User: r.sanitizeUser(usr), Before: r.sanitizeUser(&before)}
+	if err = r.sendUserSnapshotMessage(ctx, us); err != nil {
+		return errors.Wrapf(err, "failed to send updated user message for %#v", us)
+	}
+
+	return errors.Wrapf(r.sendUsernameSquattingEventMessage(ctx, ReleasedUsernameSquattingEventType, squatted),
+		"failed to sendUsernameSquattingEventMessage for %#v", squatted)
+}
+
+func (r *repository) sendUsernameSquattingEventMessage(ctx context.Context, eventType UsernameSquattingEventType, squatted *SquattedUsername) error {
+	event := &UsernameSquattingEvent{SquattedUsername: squatted, Type: eventType}
+	valueBytes, err := json.MarshalContext(ctx, event)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %#v", event)
+	}
+	msg := &messagebroker.Message{
+		Headers: map[string]string{"producer": "eskimo"},
+		Key:     squatted.UserID,
+		Topic:   r.cfg.MessageBroker.Topics[8].Name,
+		Value:   valueBytes,
+	}
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+
+	return errors.Wrapf(<-responder, "failed to send username squatting event message to broker")
+}
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	stdlibtime "time"
 
 	"github.com/goccy/go-json"
@@ -19,33 +20,62 @@ import (
 	"github.com/pkg/errors"
 
 	devicemetadata "github.com/ice-blockchain/eskimo/users/internal/device/metadata"
+	"github.com/ice-blockchain/eskimo/users/internal/encryption"
+	"github.com/ice-blockchain/eskimo/users/internal/invitation"
+	"github.com/ice-blockchain/eskimo/users/internal/objectstorage"
+	picturemoderation "github.com/ice-blockchain/eskimo/users/internal/picture/moderation"
+	picturestorage "github.com/ice-blockchain/eskimo/users/internal/picture/storage"
+	"github.com/ice-blockchain/eskimo/users/internal/profanity"
+	"github.com/ice-blockchain/eskimo/users/internal/search"
 	"github.com/ice-blockchain/wintr/analytics/tracking"
 	appcfg "github.com/ice-blockchain/wintr/config"
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
 	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
 	"github.com/ice-blockchain/wintr/log"
-	"github.com/ice-blockchain/wintr/multimedia/picture"
 	"github.com/ice-blockchain/wintr/time"
 )
 
 func New(ctx context.Context, _ context.CancelFunc) Repository {
 	var cfg config
 	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
+	cfg.reloaded = new(atomic.Pointer[config])
+	cfg.configureRedaction()
+	cfg.configureQueryAudit()
 
 	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
-
-	return &repository{
+	repo := &repository{
 		cfg:                      &cfg,
-		shutdown:                 db.Close,
 		db:                       db,
 		DeviceMetadataRepository: devicemetadata.New(db, nil),
-		pictureClient:            picture.New(applicationYamlKey),
+		pictureClient:            picturestorage.New(applicationYamlKey),
+		piiCipher:                encryption.New(applicationYamlKey),
+		residencyClusters:        mustConnectResidencyClusters(ctx, &cfg),
+		statisticsCache:          newStatisticsCache(&cfg),
+		searchIndex:              search.New(applicationYamlKey),
+	}
+	if cfg.ProfileViews.FlushInterval > 0 {
+		go repo.startProfileViewsFlusher(ctx)
 	}
+	repo.shutdown = func() error {
+		flushCtx, cancel := context.WithTimeout(context.Background(), requestDeadline)
+		defer cancel()
+
+		return multierror.Append( //nolint:wrapcheck // Not needed.
+			errors.Wrap(repo.flushProfileViews(flushCtx), "failed to flush the profile views"),
+			errors.Wrap(db.Close(), "closing db connection failed"),
+			repo.closeResidencyClusters(),
+		).ErrorOrNil()
+	}
+
+	return repo
 }
 
 func StartProcessor(ctx context.Context, cancel context.CancelFunc) Processor {
 	var cfg config
 	appcfg.MustLoadFromKey(applicationYamlKey, &cfg)
+	cfg.reloaded = new(atomic.Pointer[config])
+	cfg.configureRedaction()
+	cfg.configureQueryAudit()
 
 	var mbConsumer messagebroker.Client
 	db := storage.MustConnect(ctx, ddl, applicationYamlKey)
@@ -55,18 +85,72 @@ func StartProcessor(ctx context.Context, cancel context.CancelFunc) Processor {
 		db:                       db,
 		mb:                       mbProducer,
 		DeviceMetadataRepository: devicemetadata.New(db, mbProducer),
-		pictureClient:            picture.New(applicationYamlKey, defaultProfilePictureNameRegex),
+		pictureClient:            picturestorage.New(applicationYamlKey, defaultProfilePictureNameRegex),
+		profanityScreener:        profanity.New(applicationYamlKey),
+		invitationSender:         invitation.New(applicationYamlKey),
+		piiCipher:                encryption.New(applicationYamlKey),
+		residencyClusters:        mustConnectResidencyClusters(ctx, &cfg),
+		statisticsCache:          newStatisticsCache(&cfg),
+		searchIndex:              search.New(applicationYamlKey),
 	}}
 	if !cfg.DisableConsumer {
 		prc.trackingClient = tracking.New(applicationYamlKey)
+		prc.pictureModerator = picturemoderation.New(applicationYamlKey)
 		mbConsumer = messagebroker.MustConnectAndStartConsuming(context.Background(), cancel, applicationYamlKey, //nolint:contextcheck // It's intended.
 			&userSnapshotSource{processor: prc},
 			&miningSessionSource{processor: prc},
 			&userPingSource{processor: prc},
+			&accountMergeSource{processor: prc},
 		)
 		go prc.startOldProcessedReferralsCleaner(ctx)
+		if cfg.CountersReconciliation.Interval > 0 {
+			go prc.startCountersReconciler(ctx)
+		}
+		if cfg.DuplicateAccountsDetection.Interval > 0 {
+			go prc.startDuplicateAccountsDetector(ctx)
+		}
+		if cfg.UserDeletionBatches.Interval > 0 {
+			go prc.startUserDeletionBatchesProcessor(ctx)
+		}
+		if cfg.UserImportBatches.Interval > 0 {
+			go prc.startUserImportBatchesProcessor(ctx)
+		}
+		if cfg.UsernameSquatting.Interval > 0 {
+			go prc.startUsernameSquattingDetector(ctx)
+		}
+		if cfg.ReferralIntegrity.Interval > 0 {
+			go prc.startReferralIntegrityChecker(ctx)
+		}
+		if cfg.GlobalRank.Interval > 0 {
+			go prc.startGlobalRanker(ctx)
+		}
+		if cfg.LocationCanonicalization.Interval > 0 {
+			go prc.startLocationCanonicalizer(ctx)
+		}
+		if cfg.AgeVerification.DeletionInterval > 0 {
+			go prc.startUnderageUsersDeleter(ctx)
+		}
+		if cfg.UserSearchIndex.BackfillInterval > 0 && prc.searchIndex != nil {
+			go prc.startUserSearchIndexBackfiller(ctx)
+		}
+		if cfg.PIIReencryption.Interval > 0 && prc.piiCipher != nil {
+			go prc.startPIIReencryptor(ctx)
+		}
+		if cfg.UserEvents.Retention > 0 {
+			go prc.startUserEventsCleaner(ctx)
+		}
+		if cfg.StatisticsSnapshots.Interval > 0 {
+			prc.snapshotStorage = objectstorage.New(applicationYamlKey, "statisticsSnapshots.storage", "STATISTICS_SNAPSHOTS_STORAGE",
+				&cfg.StatisticsSnapshots.Storage)
+			go prc.startStatisticsSnapshotsExporter(ctx)
+		}
+		if cfg.DistributionEligibility.Interval > 0 {
+			prc.eligibilityStorage = objectstorage.New(applicationYamlKey, "distributionEligibility.storage", "DISTRIBUTION_ELIGIBILITY_STORAGE",
+				&cfg.DistributionEligibility.Storage)
+			go prc.startDistributionEligibilityExporter(ctx)
+		}
 	}
-	prc.shutdown = closeAll(mbConsumer, prc.mb, prc.db, prc.DeviceMetadataRepository.Close)
+	prc.shutdown = closeAll(mbConsumer, prc.mb, prc.db, prc.DeviceMetadataRepository.Close, prc.closeResidencyClusters)
 
 	return prc
 }
@@ -316,6 +400,8 @@ func (r *repository) buildRepeatableKYCSteps(usr *User) {
 
 func (r *repository) sanitizeUser(usr *User) *User {
 	usr.LastPingCooldownEndedAt = nil
+	usr.DateOfBirth = nil
+	r.OpenSensitiveUserInformation(&usr.SensitiveUserInformation)
 	if usr.BlockchainAccountAddress == usr.ID {
 		usr.BlockchainAccountAddress = ""
 	}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// SearchUsers is meant only for admins/support, so every search is audited before it's executed,
+// and it fails if the audit can't be recorded.
+//
+//nolint:funlen // Big sql.
+func (r *repository) SearchUsers(ctx context.Context, search *UserSearch, limit, offset uint64) ([]*MinimalUserProfile, error) {
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "search users failed because context failed")
+	}
+	var condition string
+	switch search.Field {
+	case EmailUserSearchField:
+		search.Keyword = strings.ToLower(search.Keyword)
+		// The encrypted emails, like the phone numbers, can only be matched exactly, by their blind index ($4).
+		if search.Mode == PrefixUserSearchMode {
+			condition = `(starts_with(u.email, $1) OR u.email_index = $4) AND u.email != u.id`
+		} else {
+			condition = `(u.email = $1 OR u.email_index = $4) AND u.email != u.id`
+		}
+	case PhoneNumberUserSearchField:
+		if search.Mode == PrefixUserSearchMode {
+			condition = `(starts_with(u.phone_number, $1) OR starts_with(u.phone_number_hash, $1) OR u.phone_number_index = $4) AND u.phone_number != u.id`
+		} else {
+			condition = `(u.phone_number = $1 OR u.phone_number_hash = $1 OR u.phone_number_index = $4) AND u.phone_number != u.id`
+		}
+	default:
+		return nil, errors.Errorf("unsupported search field `%v`", search.Field)
+	}
+	if search.Mode != ExactUserSearchMode && search.Mode != PrefixUserSearchMode {
+		return nil, errors.Errorf("unsupported search mode `%v`", search.Mode)
+	}
+	if err := r.insertUserSearchAudit(ctx, search); err != nil {
+		return nil, errors.Wrapf(err, "failed to insertUserSearchAudit for %#v", search)
+	}
+	sql := fmt.Sprintf(`
+			SELECT
+			    (u.kyc_step_passed >= %[2]v AND qs.user_id IS NOT NULL AND qs.ended_at IS NOT NULL AND qs.ended_successfully = true) AS verified,
+			    u.last_mining_ended_at 									 	 	  AS active,
+			    NULL::timestamp 							 	 	  		  AS pinged,
+			    (CASE WHEN u.phone_number = u.id THEN '' ELSE u.phone_number END) AS phone_number,
+			    (CASE WHEN u.email = u.id THEN '' ELSE u.email END)  		  AS email,
+			    u.id 												 	  		  AS id,
+				(CASE WHEN u.username = u.id THEN '' ELSE u.username END) 	  AS username,
+				%[1]v 									  		 				  AS profile_picture_name,
+				u.country 											  	  		  AS country,
+				u.city 													  		  AS city,
+			    '' 										  		  				  AS referral_type
+			FROM users u
+				 LEFT JOIN quiz_sessions qs
+					    ON qs.user_id = u.id
+			WHERE %[3]v
+			ORDER BY u.created_at DESC
+			LIMIT $2 OFFSET $3`, r.pictureClient.SQLAliasDownloadURL(`u.profile_picture_name`), LivenessDetectionKYCStep, condition)
+	args := []any{search.Keyword, limit, offset}
+	if search.Field == PhoneNumberUserSearchField {
+		args = append(args, r.PhoneNumberIndex(search.Keyword))
+	} else {
+		args = append(args, r.EmailIndex(search.Keyword))
+	}
+	result, err := auditedSelect[MinimalUserProfile](ctx, r.db, sql, args...)
+	if result == nil {
+		result = []*MinimalUserProfile{}
+	}
+	r.openMinimalUserProfiles(result)
+
+	return result, errors.Wrapf(err, "failed to search users for %#v", search)
+}
+
+func (r *repository) insertUserSearchAudit(ctx context.Context, search *UserSearch) error {
+	sql := `INSERT INTO user_search_audit (searched_at, admin_user_id, field, mode, keyword, reason) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := auditedExec(ctx, r.db, sql, time.Now().Time, search.AdminUserID, search.Field, search.Mode, search.Keyword, search.Reason)
+
+	return errors.Wrapf(err, "failed to insert user search audit for %#v", search)
+}
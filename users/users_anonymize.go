@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: ice License 1.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ice-blockchain/wintr/time"
+)
+
+// AnonymizeUser strips all the PII of the user, but, unlike DeleteUser, it keeps the user, with its ID, country and referrals,
+// so that the tokenomics and the statistics stay intact.
+// The PII columns are reset to the same placeholders new users have, so the user looks like one that never set them.
+func (r *repository) AnonymizeUser(ctx context.Context, userID UserID) error {
+	if ctx.Err() != nil {
+		return errors.Wrap(ctx.Err(), "context failed")
+	}
+	gUser, err := r.getUserByID(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get user for userID:%v", userID)
+	}
+	if preview := dryRun(ctx); preview != nil {
+		return errors.Wrapf(r.previewUserRemoval(ctx, preview, gUser, AnonymizedUserSnapshotEvent), "failed to previewUserRemoval for userID:%v", userID)
+	}
+	if hasKYCData(gUser) {
+		requestedBy := requestingUserID(ctx)
+		if requestedBy == "" {
+			requestedBy = userID
+		}
+		if _, err = r.purgeKYCData(ctx, userID, requestedBy); err != nil {
+			return errors.Wrapf(err, "failed to purgeKYCData for userID:%v", userID)
+		}
+	}
+	if err = r.DeleteAllDeviceMetadata(ctx, userID); err != nil {
+		return errors.Wrapf(err, "failed to DeleteAllDeviceMetadata for userID:%v", userID)
+	}
+	if _, err = auditedExec(ctx, r.db, `DELETE FROM user_rectifications WHERE user_id = $1`, userID); err != nil { // Their changes are PII too.
+		return errors.Wrapf(err, "failed to delete user rectifications for userID:%v", userID)
+	}
+	if _, err = auditedExec(ctx, r.db, `DELETE FROM agenda_contact_names WHERE user_id = $1`, userID); err != nil {
+		return errors.Wrapf(err, "failed to delete agenda contact names for userID:%v", userID)
+	}
+	usr := anonymized(gUser)
+	sql := `UPDATE users
+			SET updated_at = $2,
+				first_name = NULL,
+				last_name = NULL,
+				email = id,
+				email_index = NULL,
+				phone_number = id,
+				phone_number_hash = id,
+				phone_number_index = NULL,
+				username = id,
+				lookup = $3::tsvector,
+				profile_picture_name = $4,
+				client_data = NULL,
+				agenda_contact_user_ids = NULL
+			WHERE id = $1`
+	if _, err = auditedExec(ctx, r.db, sql, userID, usr.UpdatedAt.Time, usr.lookup(), usr.ProfilePictureURL); err != nil {
+		return errors.Wrapf(err, "failed to anonymize user with id %v", userID)
+	}
+	us := &UserSnapshot{User: r.sanitizeUser(usr), Before: r.sanitizeUser(gUser), Event: AnonymizedUserSnapshotEvent}
+
+	return errors.Wrapf(r.sendUserSnapshotMessage(ctx, us), "failed to send anonymized user message for %#v", us)
+}
+
+func anonymized(usr *User) *User {
+	anon := *usr
+	anon.UpdatedAt = time.Now()
+	anon.FirstName, anon.LastName = nil, nil
+	anon.Email, anon.PhoneNumber, anon.PhoneNumberHash, anon.Username = usr.ID, usr.ID, usr.ID, usr.ID
+	anon.EmailIndex, anon.PhoneNumberIndex = nil, nil
+	anon.ProfilePictureURL = RandomDefaultProfilePictureName()
+	anon.ClientData = nil
+	anon.AgendaContactUserIDs = nil
+	anon.AgendaPhoneNumberHashes = nil
+
+	return &anon
+}
+
+// DeleteOrAnonymizeUser deletes or anonymizes the user, depending on the `deletionPolicy`, unless `anonymize` overrides it.
+func (r *repository) DeleteOrAnonymizeUser(ctx context.Context, userID UserID, anonymize *bool) error {
+	if (anonymize == nil && r.cfg.DeletionPolicy == AnonymizeDeletionPolicy) || (anonymize != nil && *anonymize) {
+		return errors.Wrapf(r.AnonymizeUser(ctx, userID), "failed to AnonymizeUser for userID:%v", userID)
+	}
+
+	return errors.Wrapf(r.DeleteUser(ctx, userID), "failed to DeleteUser for userID:%v", userID)
+}
@@ -23,7 +23,7 @@ func (r *repository) findAgendaContactIDs(ctx context.Context, usr *User) ([]Use
 		return nil, nil, nil, errors.Wrapf(err, "can't get contacts for user id: %v", usr.ID)
 	}
 	sql := `SELECT id FROM users WHERE phone_number_hash = ANY($1)`
-	contactIDs, err := storage.Select[UserID](ctx, r.db, sql, strings.Split(*usr.AgendaPhoneNumberHashes, ","))
+	contactIDs, err := auditedSelect[UserID](ctx, r.db, sql, strings.Split(*usr.AgendaPhoneNumberHashes, ","))
 	if err != nil {
 		return nil, nil, nil, errors.Wrapf(err, "can't get user ids by agenda hashes:%#v for userID:%v", *usr.AgendaPhoneNumberHashes, usr.ID)
 	}
@@ -78,7 +78,7 @@ func (r *repository) getAgendaContacts(ctx context.Context, userID UserID) ([]Us
 		AgendaContactUserIDs []UserID `db:"agenda_contact_user_ids"`
 	}
 	sql := `SELECT COALESCE(agenda_contact_user_ids,'{}'::TEXT[]) as agenda_contact_user_ids FROM users WHERE id = $1`
-	res, err := storage.Get[contacts](ctx, r.db, sql, userID)
+	res, err := auditedGet[contacts](ctx, r.db, sql, userID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "can't get contact user ids for userID:%v", userID)
 	}
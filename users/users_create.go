@@ -16,23 +16,21 @@ import (
 	"github.com/ice-blockchain/wintr/time"
 )
 
-//nolint:funlen,lll // A lot of SQL params.
 func (r *repository) CreateUser(ctx context.Context, usr *User, clientIP net.IP) error {
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "create user failed because context failed")
 	}
-	r.setCreateUserDefaults(ctx, usr, clientIP)
-	sql := `
-	INSERT INTO users 
-		(ID, MINING_BLOCKCHAIN_ACCOUNT_ADDRESS, BLOCKCHAIN_ACCOUNT_ADDRESS, EMAIL, FIRST_NAME, LAST_NAME, PHONE_NUMBER, PHONE_NUMBER_HASH, USERNAME, REFERRED_BY, RANDOM_REFERRED_BY, CLIENT_DATA, PROFILE_PICTURE_NAME, COUNTRY, CITY, LANGUAGE, CREATED_AT, UPDATED_AT, LOOKUP)
-	VALUES
-		($1,                                $2,                         $3,    $4,         $5,        $6,           $7,                $8,       $9,         $10,                $11,   $12::json,                  $13,     $14,  $15,      $16,        $17,        $18,    $19::tsvector)`
-	args := []any{
-		usr.ID, usr.MiningBlockchainAccountAddress, usr.BlockchainAccountAddress, usr.Email, usr.FirstName, usr.LastName,
-		usr.PhoneNumber, usr.PhoneNumberHash, usr.Username, usr.ReferredBy, usr.RandomReferredBy, usr.ClientData, usr.ProfilePictureURL, usr.Country,
-		usr.City, usr.Language, usr.CreatedAt.Time, usr.UpdatedAt.Time, usr.lookup(),
+	if usr.ID == "" {
+		return r.createUserWithGeneratedID(ctx, usr, clientIP)
+	}
+	if err := r.screenProfanity(ctx, usr.Language, usr, nil); err != nil {
+		return errors.Wrapf(err, "failed to screenProfanity for %#v", usr)
 	}
-	if _, err := storage.Exec(ctx, r.db, sql, args...); err != nil {
+	if err := validateDateOfBirth(usr.DateOfBirth); err != nil {
+		return errors.Wrapf(err, "invalid date of birth for userID:%v", usr.ID)
+	}
+	r.setCreateUserDefaults(ctx, usr, clientIP)
+	if err := r.insertUser(ctx, usr); err != nil {
 		field, tErr := detectAndParseDuplicateDatabaseError(err)
 		if field == usernameDBColumnName {
 			return r.CreateUser(ctx, usr, clientIP)
@@ -40,6 +38,14 @@ func (r *repository) CreateUser(ctx context.Context, usr *User, clientIP net.IP)
 
 		return errors.Wrapf(tErr, "failed to insert user %#v", usr)
 	}
+	underage, err := r.captureDateOfBirth(ctx, usr, usr.DateOfBirth)
+	if err != nil {
+		revertCtx, revertCancel := context.WithTimeout(context.Background(), requestDeadline)
+		defer revertCancel()
+
+		return multierror.Append(errors.Wrapf(err, "failed to captureDateOfBirth for userID:%v", usr.ID), //nolint:wrapcheck // Not needed.
+			errors.Wrapf(r.deleteUser(revertCtx, usr), "failed to delete user due to rollback, for userID:%v", usr.ID)).ErrorOrNil() //nolint:contextcheck // .
+	}
 	us := &UserSnapshot{User: r.sanitizeUser(usr), Before: nil}
 	if err := errors.Wrapf(r.sendUserSnapshotMessage(ctx, us), "failed to send user created message for %#v", usr); err != nil {
 		revertCtx, revertCancel := context.WithTimeout(context.Background(), requestDeadline)
@@ -48,17 +54,52 @@ func (r *repository) CreateUser(ctx context.Context, usr *User, clientIP net.IP)
 		return multierror.Append(errors.Wrapf(err, "failed to send user created message for %#v", usr), //nolint:wrapcheck // Not needed.
 			errors.Wrapf(r.deleteUser(revertCtx, usr), "failed to delete user due to rollback, for userID:%v", usr.ID)).ErrorOrNil() //nolint:contextcheck // .
 	}
+	if err := r.recordEmailDomainSignup(ctx, nil, usr); err != nil { // It's just statistics, the user was created anyway.
+		log.Error(errors.Wrapf(err, "failed to recordEmailDomainSignup for userID:%v", usr.ID))
+	}
+	if err := r.incrementDailyCounter(ctx, dailySignupsGlobalKey); err != nil { // Same.
+		log.Error(errors.Wrapf(err, "failed to count the signup of userID:%v", usr.ID))
+	}
+	if err := r.attributeReferralInvitations(ctx, nil, usr); err != nil { // It's not worth failing the creation for it.
+		log.Error(errors.Wrapf(err, "failed to attributeReferralInvitations for userID:%v", usr.ID))
+	}
 	hashCode := usr.HashCode
 	r.sanitizeUserForUI(usr)
 	usr.HashCode = hashCode
+	if underage {
+		return errors.Wrapf(ErrUnderage, "userID:%v was created, but it's blocked", usr.ID)
+	}
 
 	return nil
 }
 
+//nolint:lll // A lot of SQL params.
+func (r *repository) insertUser(ctx context.Context, usr *User) error {
+	if err := r.sealSensitiveUserInformation(usr); err != nil {
+		return errors.Wrapf(err, "failed to sealSensitiveUserInformation for userID:%v", usr.ID)
+	}
+	sql := `
+	INSERT INTO users 
+		(ID, MINING_BLOCKCHAIN_ACCOUNT_ADDRESS, BLOCKCHAIN_ACCOUNT_ADDRESS, EMAIL, FIRST_NAME, LAST_NAME, PHONE_NUMBER, PHONE_NUMBER_HASH, USERNAME, REFERRED_BY, RANDOM_REFERRED_BY, CLIENT_DATA, PROFILE_PICTURE_NAME, COUNTRY, CITY, LANGUAGE, CREATED_AT, UPDATED_AT, LOOKUP, PHONE_NUMBER_INDEX, RESIDENCY, EMAIL_INDEX)
+	VALUES
+		($1,                                $2,                         $3,    $4,         $5,        $6,           $7,                $8,       $9,         $10,                $11,   $12::json,                  $13,     $14,  $15,      $16,        $17,        $18,    $19::tsvector, $20,               $21,       $22)`
+	args := []any{
+		usr.ID, usr.MiningBlockchainAccountAddress, usr.BlockchainAccountAddress, usr.Email, usr.FirstName, usr.LastName,
+		usr.PhoneNumber, usr.PhoneNumberHash, usr.Username, usr.ReferredBy, usr.RandomReferredBy, usr.ClientData, usr.ProfilePictureURL, usr.Country,
+		usr.City, usr.Language, usr.CreatedAt.Time, usr.UpdatedAt.Time, usr.lookup(), usr.PhoneNumberIndex, usr.Residency, usr.EmailIndex,
+	}
+	_, err := auditedExec(ctx, r.db, sql, args...)
+
+	return err //nolint:wrapcheck // The callers need to detect the duplicates.
+}
+
 func (r *repository) setCreateUserDefaults(ctx context.Context, usr *User, clientIP net.IP) {
 	usr.CreatedAt = time.Now()
 	usr.UpdatedAt = usr.CreatedAt
-	usr.DeviceLocation = *r.GetDeviceMetadataLocation(ctx, &device.ID{UserID: usr.ID}, clientIP)
+	usr.DeviceLocation = r.GetDeviceMetadataLocation(ctx, &device.ID{UserID: usr.ID}, clientIP).DeviceLocation
+	usr.City = r.CanonicalCity(usr.Country, usr.City)
+	residency := r.cfg.residencyOf(usr.Country)
+	usr.Residency = &residency
 	usr.ProfilePictureURL = RandomDefaultProfilePictureName()
 	usr.Username = usr.ID
 	if usr.ReferredBy == "" {
@@ -87,9 +128,9 @@ func detectAndParseDuplicateDatabaseError(err error) (field string, newErr error
 	if storage.IsErr(err, storage.ErrDuplicate) { //nolint:nestif // .
 		if storage.IsErr(err, storage.ErrDuplicate, "pk") { //nolint:gocritic // .
 			field = "id"
-		} else if storage.IsErr(err, storage.ErrDuplicate, "phonenumber") {
+		} else if storage.IsErr(err, storage.ErrDuplicate, "phonenumber") || storage.IsErr(err, storage.ErrDuplicate, "phonenumberindex") {
 			field = "phone_number"
-		} else if storage.IsErr(err, storage.ErrDuplicate, "email") {
+		} else if storage.IsErr(err, storage.ErrDuplicate, "email") || storage.IsErr(err, storage.ErrDuplicate, "emailindex") {
 			field = "email"
 		} else if storage.IsErr(err, storage.ErrDuplicate, usernameDBColumnName) {
 			field = usernameDBColumnName
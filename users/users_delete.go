@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
 )
 
 func (r *repository) DeleteUser(ctx context.Context, userID UserID) error {
@@ -20,14 +21,29 @@ func (r *repository) DeleteUser(ctx context.Context, userID UserID) error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to get user for userID:%v", userID)
 	}
+	if preview := dryRun(ctx); preview != nil {
+		return errors.Wrapf(r.previewUserRemoval(ctx, preview, gUser, DeletedUserSnapshotEvent), "failed to previewUserRemoval for userID:%v", userID)
+	}
+	if hasKYCData(gUser) {
+		requestedBy := requestingUserID(ctx)
+		if requestedBy == "" {
+			requestedBy = userID
+		}
+		if _, err = r.purgeKYCData(ctx, userID, requestedBy); err != nil {
+			return errors.Wrapf(err, "failed to purgeKYCData for userID:%v", userID)
+		}
+	}
 	if err = r.deleteUser(ctx, gUser); err != nil {
 		return errors.Wrapf(err, "failed to deleteUser for:%#v", gUser)
 	}
+	if err = r.incrementDailyCounter(ctx, dailyDeletionsGlobalKey); err != nil { // It's just statistics, the user was deleted anyway.
+		log.Error(errors.Wrapf(err, "failed to count the deletion of userID:%v", userID))
+	}
 	u := &UserSnapshot{Before: r.sanitizeUser(gUser)}
 	if err = r.sendUserSnapshotMessage(ctx, u); err != nil {
 		return errors.Wrapf(err, "failed to send deleted user message for %#v", u)
 	}
-	if err = r.sendTombstonedUserMessage(ctx, userID); err != nil {
+	if err = r.sendTombstonedUserMessage(ctx, u); err != nil {
 		return errors.Wrapf(err, "failed to sendTombstonedUserMessage for userID:%v", userID)
 	}
 
@@ -54,8 +70,11 @@ func (r *repository) deleteUser(ctx context.Context, usr *User) error { //nolint
 		return errors.Wrapf(err, "failed to get user for userID:%v", usr.ID)
 	}
 	*usr = *gUser
+	if err = r.deleteRoutedRows(ctx, usr); err != nil {
+		return errors.Wrapf(err, "failed to deleteRoutedRows for userID:%v", usr.ID)
+	}
 	sql := `DELETE FROM users WHERE id = $1`
-	if _, tErr := storage.Exec(ctx, r.db, sql, usr.ID); tErr != nil {
+	if _, tErr := auditedExec(ctx, r.db, sql, usr.ID); tErr != nil {
 		if storage.IsErr(tErr, storage.ErrRelationNotFound) || storage.IsErr(tErr, storage.ErrRelationInUse) {
 			return r.deleteUser(ctx, usr)
 		}
@@ -101,13 +120,13 @@ func (r *repository) updateReferredByForAllT1Referrals(ctx context.Context, user
 		    AND id != 'bogus'
 			AND id != 'icenetwork' 
 		    AND referred_by != id`
-	_, err := storage.Exec(ctx, r.db, sql, userID, icenetwork)
+	_, err := auditedExec(ctx, r.db, sql, userID, icenetwork)
 
 	return errors.Wrap(err, "failed to update referred by for all of user's t1 referrals")
 }
 
 func (r *repository) deleteUserTracking(ctx context.Context, usr *UserSnapshot) error {
-	if usr.Before != nil && usr.User == nil {
+	if usr.Before != nil && (usr.User == nil || usr.Event == AnonymizedUserSnapshotEvent) {
 		return errors.Wrapf(r.trackingClient.DeleteUser(ctx, usr.Before.ID), "failed to delete tracking data for userID:%v", usr.Before.ID)
 	}
 
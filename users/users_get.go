@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
 	"github.com/ice-blockchain/wintr/time"
 )
 
@@ -17,7 +18,7 @@ func (r *repository) getUserByID(ctx context.Context, id UserID) (*User, error)
 	if ctx.Err() != nil {
 		return nil, errors.Wrap(ctx.Err(), "get user failed because context failed")
 	}
-	result, err := storage.Get[User](ctx, r.db, `
+	result, err := auditedGet[User](ctx, r.db, `
 	SELECT users.*,
 	       qs.user_id IS NOT NULL AND qs.ended_at is not null AND qs.ended_successfully = true AS quiz_completed
 		FROM users
@@ -43,19 +44,38 @@ func (r *repository) GetUserByID(ctx context.Context, userID string) (*UserProfi
 			u.*,
 			(qs.user_id IS NOT NULL AND qs.ended_at is not null AND qs.ended_successfully = true) AS quiz_completed,
 			COALESCE(refs.t1, 0) 		  as t1_referral_count,
-			COALESCE(refs.t2, 0)		  as t2_referral_count
+			COALESCE(refs.t2, 0)		  as t2_referral_count,
+			gr.rank 					  as global_rank,
+			ul.level 					  as level
 		FROM users u 
 				LEFT JOIN referral_acquisition_history refs
 						ON refs.user_id = u.id
+				LEFT JOIN global_ranks gr
+						ON gr.user_id = u.id
+				LEFT JOIN user_levels ul
+						ON ul.user_id = u.id
 				LEFT JOIN quiz_sessions qs
 					ON qs.user_id = u.id
 		WHERE u.id = $1`
-	res, err := storage.Get[UserProfile](ctx, r.db, sql, userID)
+	res, err := auditedGet[UserProfile](ctx, r.db, sql, userID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to select user by id %v", userID)
 	}
 	r.sanitizeUser(res.User)
 	r.sanitizeUserForUI(res.User)
+	if r.cfg.profileCompletenessEnabled() {
+		completeness, _ := r.cfg.profileCompleteness(res.User)
+		res.ProfileCompleteness = &completeness
+	}
+	if res.PendingCountryChange, err = r.getPendingCountryChange(ctx, userID); err != nil {
+		return nil, errors.Wrapf(err, "failed to getPendingCountryChange for userID:%v", userID)
+	}
+	if res.KYCTimeline, err = r.getKYCTimeline(ctx, res.User); err != nil {
+		return nil, errors.Wrapf(err, "failed to getKYCTimeline for userID:%v", userID)
+	}
+	if res.ProfileViews, err = r.getProfileViews(ctx, res.User); err != nil {
+		return nil, errors.Wrapf(err, "failed to getProfileViews for userID:%v", userID)
+	}
 
 	return res, nil
 }
@@ -68,23 +88,27 @@ func (r *repository) getOtherUserByID(ctx context.Context, userID string) (*User
 	if err != nil {
 		return nil, err
 	}
+	r.recordProfileView(ctx, usr)
 	verified := usr.IsVerified()
 	*usr = User{
 		HiddenProfileElements: usr.HiddenProfileElements,
 		PublicUserInformation: usr.PublicUserInformation,
 		Verified:              &verified,
 	}
-	referralCountNeeded := true
+	referralCountNeeded, globalRankNeeded, levelNeeded := true, true, true
 	if usr.HiddenProfileElements != nil {
 		for _, element := range *usr.HiddenProfileElements {
-			if element == ReferralCountHiddenProfileElement {
+			switch element { //nolint:exhaustive // The rest aren't fetched.
+			case ReferralCountHiddenProfileElement:
 				referralCountNeeded = false
-
-				break
+			case GlobalRankHiddenProfileElement:
+				globalRankNeeded = false
+			case LevelHiddenProfileElement:
+				levelNeeded = false
 			}
 		}
 	}
-	if !referralCountNeeded {
+	if !referralCountNeeded && !globalRankNeeded && !levelNeeded {
 		resp := new(UserProfile)
 		resp.User = r.sanitizeUser(usr)
 
@@ -93,23 +117,39 @@ func (r *repository) getOtherUserByID(ctx context.Context, userID string) (*User
 
 	sql := `SELECT  u.id,
 					COALESCE(refs.t1, 0) AS t1_referral_count,
-					COALESCE(refs.t2, 0) 		  AS t2_referral_count
+					COALESCE(refs.t2, 0) 		  AS t2_referral_count,
+					gr.rank 			 AS global_rank,
+					ul.level 			 AS level
 			FROM users u 
 				LEFT JOIN referral_acquisition_history refs
 						ON refs.user_id = u.id
+				LEFT JOIN global_ranks gr
+						ON gr.user_id = u.id
+				LEFT JOIN user_levels ul
+						ON ul.user_id = u.id
 			WHERE u.id = $1`
 	type result struct {
+		GlobalRank      *uint64
+		Level           *uint64
 		ID              string
 		T1ReferralCount uint64
 		T2ReferralCount uint64
 	}
-	dbRes, err := storage.Get[result](ctx, r.db, sql, userID)
+	dbRes, err := auditedGet[result](ctx, r.db, sql, userID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to select referralCount for user by id %v", userID)
 	}
 	resp := new(UserProfile)
-	resp.T1ReferralCount = &dbRes.T1ReferralCount
-	resp.T2ReferralCount = &dbRes.T2ReferralCount
+	if referralCountNeeded {
+		resp.T1ReferralCount = &dbRes.T1ReferralCount
+		resp.T2ReferralCount = &dbRes.T2ReferralCount
+	}
+	if globalRankNeeded {
+		resp.GlobalRank = dbRes.GlobalRank
+	}
+	if levelNeeded {
+		resp.Level = dbRes.Level
+	}
 	resp.User = r.sanitizeUser(usr)
 
 	return resp, nil
@@ -119,7 +159,7 @@ func (r *repository) GetUserByUsername(ctx context.Context, username string) (*U
 	if ctx.Err() != nil {
 		return nil, errors.Wrap(ctx.Err(), "get user failed because context failed")
 	}
-	result, err := storage.Get[User](ctx, r.db, `
+	result, err := auditedGet[User](ctx, r.db, `
 		SELECT users.*, 
        		   (qs.user_id IS NOT NULL AND qs.ended_at is not null AND qs.ended_successfully = true) AS quiz_completed
 		FROM users 
@@ -143,14 +183,14 @@ func (r *repository) GetUserByPhoneNumber(ctx context.Context, phoneNumber strin
 			FROM users 
 			LEFT JOIN quiz_sessions qs
 					ON qs.user_id = users.id
-			WHERE phone_number = $1 AND phone_number != id`
-	usr, err := storage.Get[User](ctx, r.db, sql, phoneNumber)
+			WHERE (phone_number = $1 OR phone_number_index = $2) AND phone_number != id`
+	usr, err := auditedGet[User](ctx, r.db, sql, phoneNumber, r.PhoneNumberIndex(phoneNumber))
 	if err != nil {
 		if errors.Is(err, ErrNotFound) {
 			return nil, nil //nolint:nilnil // Nope.
 		}
 
-		return nil, errors.Wrapf(err, "failed to get user by phoneNumber `%v`", phoneNumber)
+		return nil, errors.Wrapf(err, "failed to get user by phoneNumber `%v`", RedactedPhoneNumber(phoneNumber))
 	}
 	r.sanitizeUser(usr)
 	r.sanitizeUserForUI(usr)
@@ -159,14 +199,14 @@ func (r *repository) GetUserByPhoneNumber(ctx context.Context, phoneNumber strin
 }
 
 func (r *repository) IsEmailUsedBySomebodyElse(ctx context.Context, userID, email string) (bool, error) {
-	sql := `SELECT id FROM users where email = $1`
-	usr, err := storage.Get[struct{ ID string }](ctx, r.db, sql, email)
+	sql := `SELECT id FROM users where email = $1 OR email_index = $2`
+	usr, err := auditedGet[struct{ ID string }](ctx, r.db, sql, email, r.EmailIndex(email))
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			return false, nil
 		}
 
-		return false, errors.Wrapf(err, "failed to check email ownership for userID:%v,email:%v", userID, email)
+		return false, errors.Wrapf(err, "failed to check email ownership for userID:%v,email:%v", userID, RedactedEmail(email))
 	}
 	if usr.ID == userID {
 		return false, ErrDuplicate
@@ -175,11 +215,23 @@ func (r *repository) IsEmailUsedBySomebodyElse(ctx context.Context, userID, emai
 	return true, nil
 }
 
+// GetUsers matches the keyword against the search index, once it's backfilled, and against the lookup column if it fails or finds nothing.
+// The pages past the candidates the search index returns are matched against the lookup column too.
+//
 //nolint:funlen // Big sql.
 func (r *repository) GetUsers(ctx context.Context, keyword string, limit, offset uint64) (result []*MinimalUserProfile, err error) {
 	if ctx.Err() != nil {
 		return nil, errors.Wrap(ctx.Err(), "get users failed because context failed")
 	}
+	match, keywordParam := `u.lookup @@ $2::tsquery`, any(strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(keyword), "_", "\\_"), "%", "\\%"))
+	if r.searchIndex != nil && offset+limit <= maxUserSearchIndexCandidates && r.isSearchIndexBackfilled(ctx) {
+		// The database is searched too if the index finds nothing, because it might lag behind it.
+		if userIDs, sErr := r.searchUserIDs(ctx, keyword); sErr != nil {
+			log.Error(errors.Wrapf(sErr, "failed to search the index for users by `%v`, falling back to the database", keyword))
+		} else if len(userIDs) != 0 {
+			match, keywordParam = `u.id = ANY($2::text[])`, userIDs
+		}
+	}
 	sql := fmt.Sprintf(`
 			SELECT 
 			    (u.kyc_step_passed >= %[2]v AND u.quiz_completed) 				  AS verified,
@@ -192,7 +244,8 @@ func (r *repository) GetUsers(ctx context.Context, keyword string, limit, offset
 				u.profile_picture_url 									  		  AS profile_picture_name,
 				u.country 											  	  		  AS country,
 				u.city 													  		  AS city,
-			    u.referral_type 										  		  AS referral_type
+			    u.referral_type 										  		  AS referral_type,
+			    u.search_match 										  		  	  AS search_match
 			FROM (SELECT COALESCE(u.last_mining_ended_at,to_timestamp(1)) 		  AS last_mining_ended_at,
 				   (CASE
 						WHEN user_requesting_this.id != u.id AND (u.referred_by = user_requesting_this.id OR u.id = user_requesting_this.referred_by)
@@ -239,7 +292,14 @@ func (r *repository) GetUsers(ctx context.Context, keyword string, limit, offset
 				    user_requesting_this.referred_by                                                    AS user_requesting_this_referred_by,
 				    t0.referred_by                                                                      AS t0_referred_by,
 				    t0.id                                                                               AS t0_id,
-			        qs.user_id IS NOT NULL AND qs.ended_at is not null AND qs.ended_successfully = true AS quiz_completed
+			        qs.user_id IS NOT NULL AND qs.ended_at is not null AND qs.ended_successfully = true AS quiz_completed,
+				   (CASE
+						WHEN strpos(lower(u.username), $6::text) > 0
+							THEN json_build_object(
+									'field', '%[3]v',
+									'start', strpos(lower(u.username), $6::text) - 1,
+									'end', strpos(lower(u.username), $6::text) - 1 + length($6::text))
+					END) 																				AS search_match
 			FROM users u
 					 JOIN USERS t0
 						  ON t0.id = u.referred_by
@@ -252,7 +312,8 @@ func (r *repository) GetUsers(ctx context.Context, keyword string, limit, offset
 				     LEFT JOIN quiz_sessions qs
 					   ON qs.user_id = u.id
 			WHERE 
-					u.lookup @@ $2::tsquery
+					%[4]v
+				AND NOT EXISTS (SELECT 1 FROM user_blocks b WHERE b.user_id = $5 AND b.blocked_user_id = u.id)
 				  ) u 
 				  WHERE referral_type != '' AND u.username != u.id AND u.referred_by != u.id
 				  ORDER BY
@@ -261,18 +322,20 @@ func (r *repository) GetUsers(ctx context.Context, keyword string, limit, offset
 							u.t0_id = u.user_requesting_this_id DESC,
 							u.t0_referred_by = u.user_requesting_this_id DESC,
 							u.username DESC
-			LIMIT $3 OFFSET $4`, r.pictureClient.SQLAliasDownloadURL(`u.profile_picture_name`), LivenessDetectionKYCStep)
+			LIMIT $3 OFFSET $4`, r.pictureClient.SQLAliasDownloadURL(`u.profile_picture_name`), LivenessDetectionKYCStep, UsernameUserSearchField, match)
 	params := []any{
 		time.Now().Time,
-		strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(keyword), "_", "\\_"), "%", "\\%"),
+		keywordParam,
 		limit,
 		offset,
 		requestingUserID(ctx),
+		strings.ToLower(keyword), // Unescaped, for the match range.
 	}
-	result, err = storage.Select[MinimalUserProfile](ctx, r.db, sql, params...)
+	result, err = auditedSelect[MinimalUserProfile](ctx, r.db, sql, params...)
 	if result == nil {
 		result = []*MinimalUserProfile{}
 	}
+	r.openMinimalUserProfiles(result)
 
 	return result, errors.Wrapf(err, "failed to select for users by %#v", params...)
 }
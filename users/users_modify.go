@@ -12,7 +12,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
-	storage "github.com/ice-blockchain/wintr/connectors/storage/v2"
+	"github.com/ice-blockchain/wintr/log"
 	"github.com/ice-blockchain/wintr/time"
 )
 
@@ -39,8 +39,28 @@ func (r *repository) ModifyUser(ctx context.Context, usr *User, profilePicture *
 	if lu != nil && oldUsr.UpdatedAt.UnixNano() != lu.UnixNano() {
 		return ErrRaceCondition
 	}
-	if usr.Country != "" && !r.IsValid(usr.Country) {
-		return ErrInvalidCountry
+	if usr.Country != "" {
+		var valid bool
+		if usr.Country, valid = r.CanonicalCountry(usr.Country); !valid {
+			return ErrInvalidCountry
+		}
+	}
+	if usr.City != "" {
+		country := usr.Country
+		if country == "" {
+			country = oldUsr.Country
+		}
+		usr.City = r.CanonicalCity(country, usr.City)
+	}
+	language := usr.Language
+	if language == "" {
+		language = oldUsr.Language
+	}
+	if err = r.screenProfanity(ctx, language, usr, oldUsr); err != nil {
+		return errors.Wrapf(err, "failed to screenProfanity for userID:%v", usr.ID)
+	}
+	if err = validateDateOfBirth(usr.DateOfBirth); err != nil {
+		return errors.Wrapf(err, "invalid date of birth for userID:%v", usr.ID)
 	}
 	if usr.Language != "" && oldUsr.Language == usr.Language {
 		usr.Language = ""
@@ -49,22 +69,50 @@ func (r *repository) ModifyUser(ctx context.Context, usr *User, profilePicture *
 		usr.LastPingCooldownEndedAt = nil
 	}
 	usr.UpdatedAt = time.Now()
+	var countryChange, pendingCountryChange *CountryChange
+	if usr.Country != "" && !strings.EqualFold(usr.Country, oldUsr.Country) && !countryChangeDecided(ctx) {
+		if countryChange, err = r.checkCountryChange(ctx, oldUsr, usr); err != nil {
+			return errors.Wrapf(err, "failed to checkCountryChange for userID:%v", usr.ID)
+		}
+		if countryChange.Status == PendingCountryChangeStatus {
+			if err = r.recordCountryChange(ctx, countryChange); err != nil {
+				return errors.Wrapf(err, "failed to record pending country change for userID:%v", usr.ID)
+			}
+			pendingCountryChange, countryChange = countryChange, nil
+			usr.Country, usr.City = "", ""
+		}
+	}
 	if profilePicture != nil {
-		if profilePicture.Header.Get("Reset") == "true" {
+		switch {
+		case profilePicture.Header.Get("Reset") == "true":
 			profilePicture.Filename = RandomDefaultProfilePictureName()
-		} else {
-			pictureExt := resolveProfilePictureExtension(profilePicture.Filename)
-			profilePicture.Filename = fmt.Sprintf("%v_%v%v", oldUsr.HashCode, usr.UpdatedAt.UnixNano(), pictureExt)
+		case profilePicture.Header.Get("Uploaded") == "true":
+			if err = r.verifyUploadedProfilePicture(ctx, oldUsr, profilePicture); err != nil {
+				return errors.Wrapf(err, "failed to verifyUploadedProfilePicture for userID:%v", usr.ID)
+			}
+		default:
+			profilePicture.Filename = profilePictureName(oldUsr, usr.UpdatedAt, resolveProfilePictureExtension(profilePicture.Filename))
 		}
 		usr.ProfilePictureURL = profilePicture.Filename
 		if err = r.pictureClient.UploadPicture(ctx, profilePicture, oldUsr.ProfilePictureURL); err != nil {
 			return errors.Wrapf(err, "failed to upload profile picture for userID:%v", usr.ID)
 		}
 	}
+	// It's stored apart, so it's captured even if nothing else changes, as per the country the user has before the change.
+	underage, err := r.captureDateOfBirth(ctx, oldUsr, usr.DateOfBirth)
+	if err != nil {
+		return errors.Wrapf(err, "failed to captureDateOfBirth for userID:%v", usr.ID)
+	}
+	if underage {
+		return errors.Wrapf(ErrUnderage, "userID:%v is blocked", usr.ID)
+	}
 	agendaBefore, agendaContactIDsForUpdate, uniqueAgendaContactIDsForSend, err := r.findAgendaContactIDs(ctx, usr)
 	if err != nil {
 		return errors.Wrapf(err, "can't find agenda contact ids for user:%v", usr.ID)
 	}
+	if err = r.sealSensitiveUserInformation(usr); err != nil {
+		return errors.Wrapf(err, "failed to sealSensitiveUserInformation for userID:%v", usr.ID)
+	}
 	sql, params := usr.genSQLUpdate(ctx, agendaContactIDsForUpdate)
 	noOpNoOfParams := 1 + 1
 	if lu != nil {
@@ -73,10 +121,11 @@ func (r *repository) ModifyUser(ctx context.Context, usr *User, profilePicture *
 	if len(params) == noOpNoOfParams {
 		*usr = *r.sanitizeUser(oldUsr)
 		r.sanitizeUserForUI(usr)
+		usr.PendingCountryChange = pendingCountryChange
 
 		return nil
 	}
-	if updatedRowsCount, tErr := storage.Exec(ctx, r.db, sql, params...); tErr != nil || updatedRowsCount == 0 {
+	if updatedRowsCount, tErr := auditedExec(ctx, r.db, sql, params...); tErr != nil || updatedRowsCount == 0 {
 		_, tErr = detectAndParseDuplicateDatabaseError(tErr)
 		if tErr == nil && updatedRowsCount == 0 {
 			return ErrRaceCondition
@@ -91,7 +140,7 @@ func (r *repository) ModifyUser(ctx context.Context, usr *User, profilePicture *
 	if sErr := runConcurrently(ctx, r.sendContactMessage, uniqueAgendaContactIDsForSend); sErr != nil {
 		rollbackSQL, rollBackParams := bkpUsr.genSQLUpdate(ctx, agendaBefore)
 		rollBackParams[1] = bkpUsr.UpdatedAt.Time
-		_, rErr := storage.Exec(ctx, r.db, rollbackSQL, rollBackParams...)
+		_, rErr := auditedExec(ctx, r.db, rollbackSQL, rollBackParams...)
 
 		return errors.Wrapf(multierror.Append(rErr, sErr).ErrorOrNil(), "can't send contacts message for userID:%v", usr.ID)
 	}
@@ -100,15 +149,30 @@ func (r *repository) ModifyUser(ctx context.Context, usr *User, profilePicture *
 	if err = r.sendUserSnapshotMessage(ctx, us); err != nil {
 		rollbackSQL, rollBackParams := bkpUsr.genSQLUpdate(ctx, agendaBefore)
 		rollBackParams[1] = bkpUsr.UpdatedAt.Time
-		_, rollbackErr := storage.Exec(ctx, r.db, rollbackSQL, rollBackParams...)
+		_, rollbackErr := auditedExec(ctx, r.db, rollbackSQL, rollBackParams...)
 
 		return multierror.Append( //nolint:wrapcheck // Not needed.
 			errors.Wrapf(err, "failed to send updated user snapshot message %#v", us),
 			errors.Wrapf(rollbackErr, "failed to replace user to previous value, due to rollback, prev:%#v", bkpUsr),
 		).ErrorOrNil()
 	}
+	if countryChange != nil {
+		if err = r.recordCountryChange(ctx, countryChange); err != nil {
+			return errors.Wrapf(err, "failed to record country change for userID:%v", usr.ID)
+		}
+	}
+	if err = r.recordKYCFunnel(ctx, us.Before, us.User); err != nil { // It's just statistics, the user was modified anyway.
+		log.Error(errors.Wrapf(err, "failed to recordKYCFunnel for userID:%v", usr.ID))
+	}
+	if err = r.recordEmailDomainSignup(ctx, us.Before, us.User); err != nil { // Same.
+		log.Error(errors.Wrapf(err, "failed to recordEmailDomainSignup for userID:%v", usr.ID))
+	}
+	if err = r.attributeReferralInvitations(ctx, us.Before, us.User); err != nil { // It's not worth failing the modification for it.
+		log.Error(errors.Wrapf(err, "failed to attributeReferralInvitations for userID:%v", usr.ID))
+	}
 	*usr = *us.User
 	r.sanitizeUserForUI(usr)
+	usr.PendingCountryChange = pendingCountryChange
 
 	return nil
 }
@@ -232,12 +296,16 @@ func (u *User) genSQLUpdate(ctx context.Context, agendaUserIDs []UserID) (sql st
 		sql += fmt.Sprintf(", PHONE_NUMBER = $%v", nextIndex)
 		params = append(params, u.PhoneNumberHash)
 		sql += fmt.Sprintf(", PHONE_NUMBER_HASH = $%v", nextIndex+1)
-		nextIndex += 2
+		params = append(params, u.PhoneNumberIndex)
+		sql += fmt.Sprintf(", PHONE_NUMBER_INDEX = $%v", nextIndex+2)
+		nextIndex += 3
 	}
 	if u.Email != "" {
 		params = append(params, u.Email)
 		sql += fmt.Sprintf(", EMAIL = $%v", nextIndex)
-		nextIndex++
+		params = append(params, u.EmailIndex)
+		sql += fmt.Sprintf(", EMAIL_INDEX = $%v", nextIndex+1)
+		nextIndex += 2
 	}
 	if u.BlockchainAccountAddress != "" {
 		params = append(params, u.BlockchainAccountAddress)
@@ -63,7 +63,8 @@ func (r *repository) GetReferrals(ctx context.Context, userID string, referralTy
 					AND referrals.id = ANY(u.agenda_contact_user_ids)
                     AND referrals.username != referrals.id
 					AND referrals.referred_by != referrals.id
-					AND u.id != referrals.id`
+					AND u.id != referrals.id
+					AND NOT EXISTS (SELECT 1 FROM user_blocks b WHERE b.user_id = u.id AND b.blocked_user_id = referrals.id)`
 		totalAndActiveColumns = `'0' 																   				AS id,
 								 '0' 																   				AS username,`
 	default:
@@ -140,10 +141,11 @@ func (r *repository) GetReferrals(ctx context.Context, userID string, referralTy
 				LIMIT $5 OFFSET $3
 			 ) X`, r.pictureClient.SQLAliasDownloadURL(`referrals.profile_picture_name`), referralTypeJoin, totalAndActiveColumns, referralTypeJoinSumAgg, LivenessDetectionKYCStep) //nolint:lll // .
 	args := []any{userID, referralType, offset, time.Now().Time, limit}
-	result, err := storage.Select[MinimalUserProfile](ctx, r.db, sql, args...)
+	result, err := auditedSelect[MinimalUserProfile](ctx, r.db, sql, args...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to select for all t1 referrals of userID:%v + their new random referralID", userID)
 	}
+	r.openMinimalUserProfiles(result)
 	if len(result) == 0 {
 		return &Referrals{
 			UserCount: UserCount{
@@ -153,6 +155,11 @@ func (r *repository) GetReferrals(ctx context.Context, userID string, referralTy
 			Referrals: make([]*MinimalUserProfile, 0),
 		}, nil
 	}
+	if referralType == ContactsReferrals {
+		if err = r.setContactNames(ctx, userID, result[1:]); err != nil {
+			return nil, errors.Wrapf(err, "failed to setContactNames for userID:%v", userID)
+		}
+	}
 	var total, active uint64
 	if result[0].ID != "" {
 		total, err = strconv.ParseUint(result[0].ID, 10, 64)
@@ -310,7 +317,7 @@ func (r *repository) getTeamReferrals(ctx context.Context, userID string, limit,
 		*MinimalUserProfile
 		IDX uint64
 	}
-	result, err := storage.Select[orderedMinimalUserProfile](ctx, r.db, sql, args...)
+	result, err := auditedSelect[orderedMinimalUserProfile](ctx, r.db, sql, args...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to select for all t1+t2 referrals of userID:%v", userID)
 	}
@@ -354,35 +361,15 @@ func (r *repository) getTeamReferrals(ctx context.Context, userID string, limit,
 	}, nil
 }
 
-//nolint:funlen // Long SQL with field list.
 func (r *repository) GetReferralAcquisitionHistory(ctx context.Context, userID string) ([]*ReferralAcquisition, error) {
 	if ctx.Err() != nil {
 		return nil, errors.Wrap(ctx.Err(), "failed to get acquisition history because context failed")
 	}
-
-	now := time.Now()
-	nowMidnight := time.New(time.Now().In(stdlibtime.UTC).Truncate(hoursInOneDay * stdlibtime.Hour))
 	sql := `
 		SELECT *
 		from referral_acquisition_history
 			where user_id = $1`
-	type resultFromQuery struct {
-		Date          *time.Time `db:"date"`
-		UserID        UserID     `db:"user_id"`
-		T1            int64      `db:"t1"`
-		T2            int64      `db:"t2"`
-		T1Today       int64      `db:"t1_today"`
-		T2Today       int64      `db:"t2_today"`
-		T1TodayMinus1 int64      `db:"t1_today_minus_1"`
-		T2TodayMinus1 int64      `db:"t2_today_minus_1"`
-		T1TodayMinus2 int64      `db:"t1_today_minus_2"`
-		T2TodayMinus2 int64      `db:"t2_today_minus_2"`
-		T1TodayMinus3 int64      `db:"t1_today_minus_3"`
-		T2TodayMinus3 int64      `db:"t2_today_minus_3"`
-		T1TodayMinus4 int64      `db:"t1_today_minus_4"`
-		T2TodayMinus4 int64      `db:"t2_today_minus_4"`
-	}
-	res, err := storage.Get[resultFromQuery](ctx, r.db, sql, userID)
+	res, err := auditedGet[referralAcquisitionHistory](ctx, r.db, sql, userID)
 	if err != nil {
 		if storage.IsErr(err, storage.ErrNotFound) {
 			return []*ReferralAcquisition{}, nil
@@ -390,13 +377,55 @@ func (r *repository) GetReferralAcquisitionHistory(ctx context.Context, userID s
 
 		return nil, errors.Wrapf(err, "failed to select ReferralAcquisition history for userID:%v", userID)
 	}
-	elapsedDaysSinceLastRefCountsUpdate := int(nowMidnight.Sub(*res.Date.Time).Nanoseconds() / int64(hoursInOneDay*stdlibtime.Hour))
+
+	return res.acquisitions(time.Now()), nil
+}
+
+// StreamReferralAcquisitionHistories goes over the histories in pages, by user ID, so that any number of them can be streamed.
+func (r *repository) StreamReferralAcquisitionHistories(
+	ctx context.Context, userIDs []UserID, country string, fn func(*UserReferralAcquisitionHistory) error,
+) error {
+	if userIDs == nil && country == "" {
+		return errors.New("either the userIDs or the country are required")
+	}
+	sql := `SELECT h.*
+			FROM referral_acquisition_history h
+			WHERE h.user_id > $1
+			  AND ($2::text[] IS NULL OR h.user_id = ANY($2))
+			  AND ($3 = '' OR EXISTS (SELECT 1 FROM users u WHERE u.id = h.user_id AND u.country = $3))
+			ORDER BY h.user_id
+			LIMIT $4`
+	for lastUserID := ""; ; {
+		if ctx.Err() != nil {
+			return errors.Wrap(ctx.Err(), "failed to stream acquisition histories because context failed")
+		}
+		page, err := auditedSelect[referralAcquisitionHistory](ctx, r.db, sql, lastUserID, userIDs, country, referralAcquisitionHistoriesPageSize)
+		if err != nil {
+			return errors.Wrapf(err, "failed to select ReferralAcquisition histories after userID:%v", lastUserID)
+		}
+		now := time.Now()
+		for _, history := range page {
+			if err = fn(&UserReferralAcquisitionHistory{UserID: history.UserID, History: history.acquisitions(now)}); err != nil {
+				return errors.Wrapf(err, "failed to stream the ReferralAcquisition history of userID:%v", history.UserID)
+			}
+			lastUserID = history.UserID
+		}
+		if len(page) < referralAcquisitionHistoriesPageSize {
+			return nil
+		}
+	}
+}
+
+// acquisitions are the last maxDaysReferralsHistory days of the history, as of now. The days since it was last updated had no referrals.
+func (h *referralAcquisitionHistory) acquisitions(now *time.Time) []*ReferralAcquisition {
+	nowMidnight := time.New(now.In(stdlibtime.UTC).Truncate(hoursInOneDay * stdlibtime.Hour))
+	elapsedDaysSinceLastRefCountsUpdate := int(nowMidnight.Sub(*h.Date.Time).Nanoseconds() / int64(hoursInOneDay*stdlibtime.Hour))
 	if elapsedDaysSinceLastRefCountsUpdate > maxDaysReferralsHistory {
 		elapsedDaysSinceLastRefCountsUpdate = maxDaysReferralsHistory
 	}
 	result := make([]*ReferralAcquisition, maxDaysReferralsHistory) //nolint:makezero // We're know size for sure.
-	orderOfDaysT1 := []int64{res.T1Today, res.T1TodayMinus1, res.T1TodayMinus2, res.T1TodayMinus3, res.T1TodayMinus4}
-	orderOfDaysT2 := []int64{res.T2Today, res.T2TodayMinus1, res.T2TodayMinus2, res.T2TodayMinus3, res.T2TodayMinus4}
+	orderOfDaysT1 := []int64{h.T1Today, h.T1TodayMinus1, h.T1TodayMinus2, h.T1TodayMinus3, h.T1TodayMinus4}
+	orderOfDaysT2 := []int64{h.T2Today, h.T2TodayMinus1, h.T2TodayMinus2, h.T2TodayMinus3, h.T2TodayMinus4}
 	for ind := 0; ind < elapsedDaysSinceLastRefCountsUpdate; ind++ {
 		var date *time.Time
 		if ind != 0 {
@@ -419,7 +448,7 @@ func (r *repository) GetReferralAcquisitionHistory(ctx context.Context, userID s
 		}
 	}
 
-	return result, nil
+	return result
 }
 
 func (r *repository) updateReferralCount(ctx context.Context, msgTimestamp stdlibtime.Time, us *UserSnapshot) error {
@@ -439,7 +468,7 @@ func (r *repository) updateReferralCount(ctx context.Context, msgTimestamp stdli
 		referredBy = us.ReferredBy
 		dayBetweenCreationAndDeletion = -1
 	}
-	_, err := storage.Exec(ctx, r.db, `INSERT INTO processed_referrals(user_id, referred_by, processed_at, deleted) VALUES ($1, $2, $3, $4)`,
+	_, err := auditedExec(ctx, r.db, `INSERT INTO processed_referrals(user_id, referred_by, processed_at, deleted) VALUES ($1, $2, $3, $4)`,
 		userID, referredBy, msgTimestamp, us.User == nil)
 	if storage.IsErr(err, storage.ErrDuplicate) {
 		return nil
@@ -474,7 +503,7 @@ func (r *repository) getCurrentReferralCount(ctx context.Context, userID UserID)
 		LEFT JOIN referral_acquisition_history refs ON refs.user_id = $1
 		LEFT JOIN referral_acquisition_history t0_refs ON t0_refs.user_id = t0.id
 	`
-	count, err := storage.Get[refCount](ctx, r.db, sql, userID)
+	count, err := auditedGet[refCount](ctx, r.db, sql, userID)
 	if err != nil {
 		return 0, 0, nil, nil, "", errors.Wrapf(err, "failed to read current referral count for userID:%v", userID)
 	}
@@ -548,7 +577,7 @@ func (r *repository) incrementOrDecrementReferralCount(ctx context.Context, user
 		op,
 		opToday,
 	)
-	rowsUpdated, err := storage.Exec(ctx, r.db, sql, userID, storedDate.Time, t1, t2, nowMidnight.Time, t0UserID, t0Date.Time)
+	rowsUpdated, err := auditedExec(ctx, r.db, sql, userID, storedDate.Time, t1, t2, nowMidnight.Time, t0UserID, t0Date.Time)
 	if rowsUpdated == 0 || storage.IsErr(err, storage.ErrNotFound) {
 		return r.incrementOrDecrementReferralCount(ctx, userID, daysBetweenCreationAndDeletion)
 	}
@@ -637,7 +666,7 @@ func (p *processor) deleteOldProcessedReferrals(ctx context.Context) error {
 		return errors.Wrap(ctx.Err(), "unexpected deadline")
 	}
 	sql := `DELETE FROM processed_referrals WHERE processed_at < $1`
-	if _, err := storage.Exec(ctx, p.db, sql, time.Now().Add(-24*stdlibtime.Hour)); err != nil {
+	if _, err := auditedExec(ctx, p.db, sql, time.Now().Add(-24*stdlibtime.Hour)); err != nil {
 		return errors.Wrap(err, "failed to delete old data from processed_referrals")
 	}
 
@@ -649,7 +678,7 @@ func (r *repository) deleteReferralAcquisitionHistory(ctx context.Context, userI
 		return errors.Wrap(ctx.Err(), "unexpected deadline")
 	}
 	sql := `DELETE FROM referral_acquisition_history WHERE user_id = $1`
-	if _, err := storage.Exec(ctx, r.db, sql, userID); err != nil {
+	if _, err := auditedExec(ctx, r.db, sql, userID); err != nil {
 		return errors.Wrapf(err, "failed to delete referral acquisition history for userID:%v", userID)
 	}
 
@@ -10,34 +10,36 @@ import (
 	"github.com/pkg/errors"
 
 	messagebroker "github.com/ice-blockchain/wintr/connectors/message_broker"
+	"github.com/ice-blockchain/wintr/log"
 )
 
 func (s *userSnapshotSource) Process(ctx context.Context, msg *messagebroker.Message) error {
 	if ctx.Err() != nil {
 		return errors.Wrap(ctx.Err(), "unexpected deadline while processing message")
 	}
-	if len(msg.Value) == 0 {
-		return nil
-	}
-	usr := new(UserSnapshot)
-	if err := json.UnmarshalContext(ctx, msg.Value, usr); err != nil {
-		return errors.Wrapf(err, "process: cannot unmarshall %v into %#v", string(msg.Value), usr)
+	envelope, err := ParseUserSnapshotEnvelope(ctx, msg)
+	if err != nil || envelope.Snapshot == nil {
+		return errors.Wrap(err, "process: failed to ParseUserSnapshotEnvelope")
 	}
+	usr := envelope.Snapshot
 
 	return multierror.Append( //nolint:wrapcheck // Not needed.
 		errors.Wrap(s.updateTotalUsersCount(ctx, usr), "failed to updateTotalUsersCount"),
 		errors.Wrap(s.updateTotalUsersPerCountryCount(ctx, usr), "failed to updateTotalUsersPerCountryCount"),
 		errors.Wrap(s.updateReferralCount(ctx, msg.Timestamp, usr), "failed to updateReferralCount"),
 		errors.Wrap(s.deleteUserTracking(ctx, usr), "failed to deleteUserTracking"),
+		errors.Wrap(s.moderateProfilePicture(ctx, usr), "failed to moderateProfilePicture"),
+		errors.Wrap(s.sendProfileCompletenessChange(ctx, usr), "failed to sendProfileCompletenessChange"),
+		errors.Wrap(s.awardBadges(ctx, usr), "failed to awardBadges"),
+		errors.Wrap(s.levelUp(ctx, usr), "failed to levelUp"),
+		errors.Wrap(s.indexUser(ctx, usr), "failed to indexUser"),
 	).ErrorOrNil()
 }
 
-func (r *repository) sendTombstonedUserMessage(ctx context.Context, userID string) error {
-	msg := &messagebroker.Message{
-		Headers: map[string]string{"producer": "eskimo"},
-		Key:     userID,
-		Topic:   r.cfg.MessageBroker.Topics[1].Name,
-	}
+func (r *repository) sendTombstonedUserMessage(ctx context.Context, deleted *UserSnapshot) error {
+	envelope := r.cfg.userSnapshotEnvelope(deleted)
+	envelope.Snapshot = nil
+	msg := envelope.message(r.cfg.MessageBroker.Topics[1].Name, nil)
 	responder := make(chan error, 1)
 	defer close(responder)
 	r.mb.SendMessage(ctx, msg, responder)
@@ -46,28 +48,106 @@ func (r *repository) sendTombstonedUserMessage(ctx context.Context, userID strin
 }
 
 func (r *repository) sendUserSnapshotMessage(ctx context.Context, user *UserSnapshot) error {
+	if user.User != nil && user.BlockedUserIDs == nil {
+		blockedUserIDs, err := r.blockedUserIDs(ctx, user.ID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get blockedUserIDs for %#v", user)
+		}
+		user.BlockedUserIDs = blockedUserIDs
+	}
+	if user.User != nil && user.BlockchainAddresses == nil {
+		blockchainAddresses, err := r.GetBlockchainAddresses(ctx, user.ID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get blockchainAddresses for userID:%v", user.ID)
+		}
+		user.BlockchainAddresses = blockchainAddresses
+	}
+	if user.User != nil && user.Badges == nil {
+		badges, err := r.badgeNames(ctx, user.ID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get badgeNames for userID:%v", user.ID)
+		}
+		user.Badges = badges
+	}
 	valueBytes, err := json.MarshalContext(ctx, user)
 	if err != nil {
 		return errors.Wrapf(err, "failed to marshal %#v", user)
 	}
+	msg := r.cfg.userSnapshotEnvelope(user).message(r.cfg.MessageBroker.Topics[1].Name, valueBytes)
 
-	var key string
-	if user.User == nil {
-		key = user.Before.ID
-	} else {
-		key = user.ID
+	responder := make(chan error, 1)
+	defer close(responder)
+	r.mb.SendMessage(ctx, msg, responder)
+	if err = <-responder; err != nil {
+		return errors.Wrapf(err, "failed to send user snapshot message to broker")
 	}
+	log.Error(errors.Wrap(r.recordUserEvents(ctx, user), "failed to recordUserEvents"))
+
+	return nil
+}
 
-	msg := &messagebroker.Message{
-		Headers: map[string]string{"producer": "eskimo"},
-		Key:     key,
-		Topic:   r.cfg.MessageBroker.Topics[1].Name,
-		Value:   valueBytes,
+func (c *config) userSnapshotEnvelope(us *UserSnapshot) *UserSnapshotEnvelope {
+	usr := us.User
+	if usr == nil {
+		usr = us.Before
+	}
+	key := usr.ID
+	if c.UserSnapshots.Key == UsernameUserSnapshotKey && usr.Username != "" {
+		key = usr.Username
+	}
+	envelope := &UserSnapshotEnvelope{Snapshot: us, Key: key, EventType: us.eventType()}
+	if c.UserSnapshots.EnrichHeaders {
+		envelope.SchemaVersion, envelope.Tenant = UserSnapshotSchemaVersion, c.UserSnapshots.Tenant
 	}
 
-	responder := make(chan error, 1)
-	defer close(responder)
-	r.mb.SendMessage(ctx, msg, responder)
+	return envelope
+}
+
+func (us *UserSnapshot) eventType() UserSnapshotEvent {
+	switch {
+	case us.Event != "":
+		return us.Event
+	case us.User == nil:
+		return DeletedUserSnapshotEvent
+	case us.Before == nil:
+		return CreatedUserSnapshotEvent
+	default:
+		return UpdatedUserSnapshotEvent
+	}
+}
+
+func (e *UserSnapshotEnvelope) message(topic string, value []byte) *messagebroker.Message {
+	headers := map[string]string{"producer": "eskimo"}
+	if e.SchemaVersion != "" { // It's set only if the headers are enriched.
+		headers["eventType"], headers["schemaVersion"] = string(e.EventType), e.SchemaVersion
+	}
+	if e.Tenant != "" {
+		headers["tenant"] = e.Tenant
+	}
+
+	return &messagebroker.Message{Headers: headers, Key: e.Key, Topic: topic, Value: value}
+}
+
+// ParseUserSnapshotEnvelope parses the messages of the user snapshots topic. The snapshot is nil for tombstones.
+// The event type is derived from the snapshot if the producer didn't enrich the headers.
+func ParseUserSnapshotEnvelope(ctx context.Context, msg *messagebroker.Message) (*UserSnapshotEnvelope, error) {
+	envelope := &UserSnapshotEnvelope{
+		Key:           msg.Key,
+		EventType:     UserSnapshotEvent(msg.Headers["eventType"]),
+		SchemaVersion: msg.Headers["schemaVersion"],
+		Tenant:        msg.Headers["tenant"],
+	}
+	if len(msg.Value) == 0 {
+		return envelope, nil
+	}
+	us := new(UserSnapshot)
+	if err := json.UnmarshalContext(ctx, msg.Value, us); err != nil {
+		return nil, errors.Wrapf(err, "cannot unmarshall %v into %#v", string(msg.Value), us)
+	}
+	envelope.Snapshot = us
+	if envelope.EventType == "" {
+		envelope.EventType = us.eventType()
+	}
 
-	return errors.Wrapf(<-responder, "failed to send user snapshot message to broker")
+	return envelope, nil
 }
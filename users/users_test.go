@@ -145,7 +145,7 @@ func requireAllAPIMethodsFailIfRepositoryOrProcessorAreStopped(cctx context.Cont
 			return err
 		},
 		func(ctx context.Context) error {
-			_, err := usersRepository.GetTopCountries(ctx, "us", 1, 0)
+			_, _, _, err := usersRepository.GetTopCountries(ctx, "us", "", 1, 0)
 			return err
 		},
 	}